@@ -14,7 +14,10 @@ type acceptRateLimiter struct {
 	burst  float64   // maximum tokens
 	tokens float64   // current tokens
 	last   time.Time // last refill time
-	mu     sync.Mutex
+	// scale multiplies rate and burst while node RPC latency backoff is
+	// active (0 or 1 means full rate).
+	scale float64
+	mu    sync.Mutex
 }
 
 func newAcceptRateLimiter(maxPerSecond, burst int) *acceptRateLimiter {
@@ -48,11 +51,44 @@ func (l *acceptRateLimiter) updateRate(newRate, newBurst int) {
 
 	l.rate = float64(newRate)
 	l.burst = float64(newBurst)
-	if l.tokens > l.burst {
-		l.tokens = l.burst
+	if burst := l.curBurstLocked(); l.tokens > burst {
+		l.tokens = burst
 	}
 }
 
+// setScale scales the configured rate and burst by scale (clamped to
+// (0, 1]) without losing them, so the backoff can be lifted later.
+func (l *acceptRateLimiter) setScale(scale float64) {
+	if l == nil {
+		return
+	}
+	if scale <= 0 || scale > 1 {
+		scale = 1
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.scale = scale
+	if burst := l.curBurstLocked(); l.tokens > burst {
+		l.tokens = burst
+	}
+}
+
+func (l *acceptRateLimiter) curRateLocked() float64 {
+	if l.scale > 0 && l.scale < 1 {
+		return l.rate * l.scale
+	}
+	return l.rate
+}
+
+// curBurstLocked never drops below one token so a scaled limiter still
+// admits connections.
+func (l *acceptRateLimiter) curBurstLocked() float64 {
+	if l.scale > 0 && l.scale < 1 {
+		return max(l.burst*l.scale, 1)
+	}
+	return l.burst
+}
+
 // wait blocks as needed so that no more than "rate" accepts occur on average,
 // with up to "burst" accepts allowed in a short spike. It respects ctx
 // cancellation so shutdown is not delayed by the limiter.
@@ -71,11 +107,12 @@ func (l *acceptRateLimiter) wait(ctx context.Context) bool {
 	if l.last.IsZero() {
 		l.last = now
 	}
+	rate, burst := l.curRateLocked(), l.curBurstLocked()
 	elapsed := now.Sub(l.last).Seconds()
 	if elapsed > 0 {
-		l.tokens += elapsed * l.rate
-		if l.tokens > l.burst {
-			l.tokens = l.burst
+		l.tokens += elapsed * rate
+		if l.tokens > burst {
+			l.tokens = burst
 		}
 		l.last = now
 	}
@@ -89,7 +126,6 @@ func (l *acceptRateLimiter) wait(ctx context.Context) bool {
 	// Reserve a token so concurrent waiters can't all pass after sleeping.
 	l.tokens -= 1
 	need := -l.tokens
-	rate := l.rate
 	l.mu.Unlock()
 
 	wait := time.Duration(need / rate * float64(time.Second))
@@ -106,17 +142,18 @@ func (l *acceptRateLimiter) wait(ctx context.Context) bool {
 		if l.last.IsZero() {
 			l.last = now
 		}
+		rate, burst := l.curRateLocked(), l.curBurstLocked()
 		elapsed := now.Sub(l.last).Seconds()
 		if elapsed > 0 {
-			l.tokens += elapsed * l.rate
-			if l.tokens > l.burst {
-				l.tokens = l.burst
+			l.tokens += elapsed * rate
+			if l.tokens > burst {
+				l.tokens = burst
 			}
 			l.last = now
 		}
 		l.tokens += 1
-		if l.tokens > l.burst {
-			l.tokens = l.burst
+		if l.tokens > burst {
+			l.tokens = burst
 		}
 		l.mu.Unlock()
 		return false
@@ -125,3 +162,25 @@ func (l *acceptRateLimiter) wait(ctx context.Context) bool {
 
 	return true
 }
+
+// allow takes a token if one is available without waiting, for callers that
+// reject excess work instead of delaying it.
+func (l *acceptRateLimiter) allow(now time.Time) bool {
+	if l == nil {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rate <= 0 {
+		return true
+	}
+	if elapsed := now.Sub(l.last).Seconds(); elapsed > 0 {
+		l.tokens = min(l.tokens+elapsed*l.curRateLocked(), l.curBurstLocked())
+		l.last = now
+	}
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	acceptRPCBackoffInterval = 2 * time.Second
+	// acceptRPCBackoffAlpha weights each new getblocktemplate latency sample
+	// in the moving average the backoff reacts to.
+	acceptRPCBackoffAlpha = 0.3
+	// acceptRPCBackoffStep caps how much one interval may cut the rate.
+	acceptRPCBackoffStep = 0.75
+	// acceptRPCRecoverStep is the share of the full rate restored per
+	// interval once latency is comfortably below the threshold.
+	acceptRPCRecoverStep = 0.1
+	// acceptRPCRecoverRatio is the hysteresis band: latency must fall below
+	// this fraction of the threshold before rates recover.
+	acceptRPCRecoverRatio = 0.75
+)
+
+// acceptRPCBackoff scales the accept rate down while getblocktemplate
+// latency stays above a threshold, so a reconnect storm cannot pile work on
+// a struggling node. Cuts are bounded per interval and recovery is additive
+// with a hysteresis band, so the rate moves smoothly instead of flapping.
+type acceptRPCBackoff struct {
+	threshold float64 // seconds
+	minScale  float64
+	avg       float64 // moving average latency, seconds
+	lastCount uint64
+	scale     float64
+}
+
+func newAcceptRPCBackoff(threshold time.Duration, minPercent float64) *acceptRPCBackoff {
+	minScale := minPercent / 100
+	if minScale <= 0 || minScale > 1 {
+		minScale = defaultAcceptRPCLatencyMinPercent / 100
+	}
+	return &acceptRPCBackoff{threshold: threshold.Seconds(), minScale: minScale, scale: 1}
+}
+
+// observe takes the latest getblocktemplate latency and the running sample
+// count and returns the accept rate scale in [minScale, 1]. Without a new
+// sample the scale is left as is.
+func (b *acceptRPCBackoff) observe(latest float64, count uint64) float64 {
+	if count == b.lastCount {
+		return b.scale
+	}
+	b.lastCount = count
+	if b.avg == 0 {
+		b.avg = latest
+	} else {
+		b.avg = acceptRPCBackoffAlpha*latest + (1-acceptRPCBackoffAlpha)*b.avg
+	}
+
+	switch {
+	case b.avg > b.threshold:
+		target := max(b.threshold/b.avg, b.minScale)
+		if b.scale > target {
+			b.scale = max(b.scale*acceptRPCBackoffStep, target)
+		}
+	case b.avg < b.threshold*acceptRPCRecoverRatio:
+		b.scale = min(b.scale+acceptRPCRecoverStep, 1)
+	}
+	return b.scale
+}
+
+// runAcceptRPCBackoff applies accept_rpc_latency_backoff_ms to limiter using
+// the getblocktemplate latency recorded in metrics.
+func runAcceptRPCBackoff(ctx context.Context, limiter *acceptRateLimiter, metrics *PoolMetrics, cfg Config) {
+	if limiter == nil || metrics == nil || cfg.AcceptRPCLatencyBackoffMs <= 0 {
+		return
+	}
+	b := newAcceptRPCBackoff(time.Duration(cfg.AcceptRPCLatencyBackoffMs)*time.Millisecond, cfg.AcceptRPCLatencyMinPercent)
+	logger.Info("accept rate RPC latency backoff enabled", "component", "stratum", "kind", "throttle",
+		"threshold_ms", cfg.AcceptRPCLatencyBackoffMs, "min_percent", b.minScale*100)
+
+	ticker := time.NewTicker(acceptRPCBackoffInterval)
+	defer ticker.Stop()
+	prev := 1.0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		_, _, _, _, gbtLast, _, gbtCount, _, _, _, _, _ := metrics.SnapshotDiagnostics()
+		scale := b.observe(gbtLast, gbtCount)
+		if scale == prev {
+			continue
+		}
+		limiter.setScale(scale)
+		switch {
+		case prev == 1:
+			logger.Warn("node RPC latency high; backing off accept rate", "component", "stratum", "kind", "throttle",
+				"gbt_latency_avg", time.Duration(b.avg*float64(time.Second)), "rate_percent", scale*100)
+		case scale == 1:
+			logger.Info("node RPC latency normal; accept rate restored", "component", "stratum", "kind", "throttle",
+				"gbt_latency_avg", time.Duration(b.avg*float64(time.Second)))
+		default:
+			logger.Debug("accept rate backoff adjusted", "component", "stratum", "kind", "throttle",
+				"gbt_latency_avg", time.Duration(b.avg*float64(time.Second)), "rate_percent", scale*100)
+		}
+		prev = scale
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// TestAcceptRPCBackoffSmoothAndBounded drives the backoff through a latency
+// spike and recovery and checks it never undercuts the floor, never cuts more
+// than one step per sample and returns to the full rate.
+func TestAcceptRPCBackoffSmoothAndBounded(t *testing.T) {
+	b := newAcceptRPCBackoff(500*time.Millisecond, 20)
+	var count uint64
+	feed := func(latency float64) float64 {
+		count++
+		return b.observe(latency, count)
+	}
+
+	if got := feed(0.1); got != 1 {
+		t.Fatalf("scale = %v under normal latency, want 1", got)
+	}
+
+	prev := 1.0
+	for range 30 {
+		got := feed(20)
+		if got < 0.2 {
+			t.Fatalf("scale %v fell below the 20%% floor", got)
+		}
+		if got < prev*acceptRPCBackoffStep-1e-9 {
+			t.Fatalf("scale dropped from %v to %v in one step", prev, got)
+		}
+		prev = got
+	}
+	if prev != 0.2 {
+		t.Fatalf("scale = %v after sustained high latency, want floor 0.2", prev)
+	}
+
+	// No new sample: nothing changes.
+	if got := b.observe(0.01, count); got != prev {
+		t.Fatalf("scale changed without a new sample: %v -> %v", prev, got)
+	}
+
+	// Latency inside the hysteresis band holds the current scale.
+	b.avg = 0.45
+	if got := feed(0.45); got != prev {
+		t.Fatalf("scale moved inside the hysteresis band: %v -> %v", prev, got)
+	}
+
+	for range 50 {
+		feed(0.05)
+	}
+	if got := feed(0.05); got != 1 {
+		t.Fatalf("scale = %v after latency normalized, want 1", got)
+	}
+}
+
+func TestAcceptRateLimiterScaleKeepsConfiguredRate(t *testing.T) {
+	l := newAcceptRateLimiter(100, 50)
+	l.setScale(0.1)
+	l.mu.Lock()
+	rate, burst, tokens := l.curRateLocked(), l.curBurstLocked(), l.tokens
+	l.mu.Unlock()
+	if rate != 10 || burst != 5 || tokens != 5 {
+		t.Fatalf("scaled limiter rate=%v burst=%v tokens=%v, want 10/5/5", rate, burst, tokens)
+	}
+
+	l.setScale(1)
+	l.mu.Lock()
+	rate, burst = l.curRateLocked(), l.curBurstLocked()
+	l.mu.Unlock()
+	if rate != 100 || burst != 50 {
+		t.Fatalf("restored limiter rate=%v burst=%v, want 100/50", rate, burst)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -67,6 +68,9 @@ type WorkerView struct {
 	ConnectionSeq             uint64       `json:"connection_seq,omitempty"`
 	ConnectedAt               time.Time    `json:"connected_at"`
 	WalletValidated           bool         `json:"wallet_validated,omitempty"`
+	// Listeners the worker's connections arrived on, sorted; only set with
+	// tuning [stratum] share_listener_labels.
+	Listeners []string `json:"listeners,omitempty"`
 }
 
 // RecentWorkView is a minimal view of worker data for the overview page's
@@ -319,6 +323,11 @@ type AccountStore struct {
 	ban   *banStore
 	ready bool
 	err   error
+
+	dataDir string
+	// recoveryFile saves records that fail to flush to the recovery file
+	// (accounting_recovery_file) instead of dropping them.
+	recoveryFile bool
 }
 
 type banStore struct {
@@ -505,9 +514,18 @@ func NewAccountStore(cfg Config, enableShareLog bool, cleanBans bool) (*AccountS
 			return nil, err
 		}
 	}
+	// Replay records saved by a failed shutdown flush before any new shares
+	// are accepted.
+	if n, err := replayAccountingRecovery(dir); err != nil {
+		logger.Error("accounting recovery replay", "component", "db", "kind", "recovery", "path", accountingRecoveryPath(dir), "replayed", n, "error", err)
+	} else if n > 0 {
+		logger.Info("replayed accounting recovery records", "component", "db", "kind", "recovery", "records", n)
+	}
 	return &AccountStore{
-		ban:   bans,
-		ready: true,
+		ban:          bans,
+		ready:        true,
+		dataDir:      dir,
+		recoveryFile: cfg.AccountingRecoveryFile,
 	}, nil
 }
 
@@ -578,9 +596,35 @@ func (s *AccountStore) Ready() bool {
 	return s.ready
 }
 
+// Flush waits for queued found-block records and retries any whose database
+// write failed. With accounting_recovery_file enabled, records that still
+// fail are saved to the recovery file and replayed on the next start.
 func (s *AccountStore) Flush() error {
 	if s == nil || s.ban == nil {
 		return s.LastError()
 	}
+	if !drainFoundBlockLogger(accountingFlushDrainTimeout) {
+		logger.Warn("timed out draining found block log queue", "component", "db", "kind", "flush")
+	}
+	var failed []accountingRecord
+	var flushErr error
+	for _, rec := range takePendingAccountingRecords() {
+		if err := insertAccountingRecord(rec); err != nil {
+			if flushErr == nil {
+				flushErr = err
+			}
+			failed = append(failed, rec)
+		}
+	}
+	if len(failed) > 0 {
+		if !s.recoveryFile {
+			return errors.Join(s.LastError(), fmt.Errorf("%d accounting records not persisted: %w", len(failed), flushErr))
+		}
+		path := accountingRecoveryPath(s.dataDir)
+		if err := writeAccountingRecovery(path, failed); err != nil {
+			return errors.Join(s.LastError(), fmt.Errorf("%d accounting records lost; write recovery file: %w", len(failed), err))
+		}
+		logger.Warn("saved unflushed accounting records for replay on next start", "component", "db", "kind", "flush", "records", len(failed), "path", path, "error", flushErr)
+	}
 	return s.LastError()
 }
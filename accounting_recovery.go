@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// accountingRecoveryFileName lives in <data_dir>/state and holds records
+	// that could not be written to the state database at shutdown.
+	accountingRecoveryFileName = "accounting_recovery.jsonl"
+	// maxPendingAccountingRecords bounds records kept in memory after a
+	// failed database write.
+	maxPendingAccountingRecords = 1024
+	accountingFlushDrainTimeout = 5 * time.Second
+)
+
+// accountingRecord is one record that has not yet reached the state database.
+type accountingRecord struct {
+	Kind          string `json:"kind"`
+	CreatedAtUnix int64  `json:"created_at_unix"`
+	JSON          string `json:"json"`
+}
+
+const accountingRecordFoundBlock = "found_block"
+
+var (
+	pendingAccountingMu      sync.Mutex
+	pendingAccountingRecords []accountingRecord
+)
+
+// notePendingAccountingRecord keeps a record whose database write failed so
+// Flush can retry it (and, if enabled, save it to the recovery file).
+func notePendingAccountingRecord(rec accountingRecord) {
+	pendingAccountingMu.Lock()
+	defer pendingAccountingMu.Unlock()
+	if len(pendingAccountingRecords) >= maxPendingAccountingRecords {
+		logger.Warn("pending accounting records full; dropping oldest", "component", "db", "kind", "flush")
+		pendingAccountingRecords = pendingAccountingRecords[1:]
+	}
+	pendingAccountingRecords = append(pendingAccountingRecords, rec)
+}
+
+func takePendingAccountingRecords() []accountingRecord {
+	pendingAccountingMu.Lock()
+	defer pendingAccountingMu.Unlock()
+	recs := pendingAccountingRecords
+	pendingAccountingRecords = nil
+	return recs
+}
+
+// insertAccountingRecord writes rec to the state database unless an identical
+// record is already there, so replaying the same record twice is harmless.
+func insertAccountingRecord(rec accountingRecord) error {
+	db := getSharedStateDB()
+	if db == nil {
+		return errors.New("state database unavailable")
+	}
+	switch rec.Kind {
+	case accountingRecordFoundBlock:
+		var exists int
+		err := db.QueryRow("SELECT 1 FROM found_blocks_log WHERE json = ? LIMIT 1", rec.JSON).Scan(&exists)
+		if err == nil {
+			return nil
+		}
+		_, err = db.Exec("INSERT INTO found_blocks_log (created_at_unix, json) VALUES (?, ?)", rec.CreatedAtUnix, rec.JSON)
+		return err
+	default:
+		return fmt.Errorf("unknown accounting record kind %q", rec.Kind)
+	}
+}
+
+// drainFoundBlockLogger waits for queued found-block log entries to be
+// written so Flush sees every record that is still pending.
+func drainFoundBlockLogger(timeout time.Duration) bool {
+	done := make(chan struct{})
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case foundBlockLogCh <- foundBlockLogEntry{Done: done}:
+	case <-timer.C:
+		return false
+	}
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+func accountingRecoveryPath(dataDir string) string {
+	if dataDir == "" {
+		dataDir = defaultDataDir
+	}
+	return filepath.Join(dataDir, "state", accountingRecoveryFileName)
+}
+
+// writeAccountingRecovery appends recs to the recovery file and syncs it.
+func writeAccountingRecovery(path string, recs []accountingRecord) error {
+	if len(recs) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	for _, rec := range recs {
+		data, err := fastJSONMarshal(rec)
+		if err != nil {
+			_ = f.Close()
+			return err
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// replayAccountingRecovery inserts the records saved by a failed shutdown
+// flush. Records already in the database are skipped. The file is removed
+// once every record is persisted; records that still fail are kept in it
+// for the next start.
+func replayAccountingRecovery(dataDir string) (replayed int, err error) {
+	path := accountingRecoveryPath(dataDir)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	var failed []accountingRecord
+	var firstErr error
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		var rec accountingRecord
+		if err := fastJSONUnmarshal([]byte(line), &rec); err != nil {
+			logger.Warn("skipping malformed accounting recovery record", "component", "db", "kind", "recovery", "path", path, "error", err)
+			continue
+		}
+		if err := insertAccountingRecord(rec); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			failed = append(failed, rec)
+			continue
+		}
+		replayed++
+	}
+	if err := sc.Err(); err != nil {
+		return replayed, err
+	}
+	if len(failed) == 0 {
+		return replayed, os.Remove(path)
+	}
+	tmp := path + ".tmp"
+	_ = os.Remove(tmp)
+	if err := writeAccountingRecovery(tmp, failed); err != nil {
+		return replayed, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return replayed, err
+	}
+	return replayed, fmt.Errorf("%d accounting recovery records still pending: %w", len(failed), firstErr)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func countFoundBlockRows(t *testing.T, json string) int {
+	t.Helper()
+	var n int
+	if err := getSharedStateDB().QueryRow("SELECT COUNT(*) FROM found_blocks_log WHERE json = ?", json).Scan(&n); err != nil {
+		t.Fatalf("count found_blocks_log: %v", err)
+	}
+	return n
+}
+
+func TestAccountStoreFlushWritesRecoveryFileAndReplays(t *testing.T) {
+	dir := t.TempDir()
+	setupTestStateDB(t, dir)
+	flushFoundBlockLogger(t)
+	takePendingAccountingRecords()
+
+	stored := accountingRecord{Kind: accountingRecordFoundBlock, CreatedAtUnix: 1, JSON: `{"height":1,"hash":"aa"}`}
+	if err := insertAccountingRecord(stored); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	pending := accountingRecord{Kind: accountingRecordFoundBlock, CreatedAtUnix: 2, JSON: `{"height":2,"hash":"bb"}`}
+	notePendingAccountingRecord(stored)
+	notePendingAccountingRecord(pending)
+
+	// With the database gone, the flush can only save the records.
+	restore := setSharedStateDBForTest(nil)
+	store := &AccountStore{ban: &banStore{}, ready: true, dataDir: dir, recoveryFile: true}
+	if err := store.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	restore()
+	if _, err := os.Stat(accountingRecoveryPath(dir)); err != nil {
+		t.Fatalf("expected recovery file: %v", err)
+	}
+
+	// Replay skips the record that was already stored.
+	n, err := replayAccountingRecovery(dir)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("replayed %d records, want 2", n)
+	}
+	if got := countFoundBlockRows(t, stored.JSON); got != 1 {
+		t.Fatalf("already-stored record duplicated: %d rows", got)
+	}
+	if got := countFoundBlockRows(t, pending.JSON); got != 1 {
+		t.Fatalf("pending record rows = %d, want 1", got)
+	}
+	if _, err := os.Stat(accountingRecoveryPath(dir)); !os.IsNotExist(err) {
+		t.Fatalf("expected recovery file removed after replay, stat err = %v", err)
+	}
+
+	// Replaying the same file again is a no-op.
+	if err := writeAccountingRecovery(accountingRecoveryPath(dir), []accountingRecord{pending}); err != nil {
+		t.Fatalf("write recovery: %v", err)
+	}
+	if _, err := replayAccountingRecovery(dir); err != nil {
+		t.Fatalf("second replay: %v", err)
+	}
+	if got := countFoundBlockRows(t, pending.JSON); got != 1 {
+		t.Fatalf("second replay duplicated record: %d rows", got)
+	}
+}
+
+func TestAccountStoreFlushWithoutRecoveryReportsLoss(t *testing.T) {
+	dir := t.TempDir()
+	setupTestStateDB(t, dir)
+	flushFoundBlockLogger(t)
+	takePendingAccountingRecords()
+
+	notePendingAccountingRecord(accountingRecord{Kind: accountingRecordFoundBlock, CreatedAtUnix: 3, JSON: `{"height":3}`})
+	restore := setSharedStateDBForTest(nil)
+	defer restore()
+	store := &AccountStore{ban: &banStore{}, ready: true, dataDir: dir}
+	if err := store.Flush(); err == nil {
+		t.Fatalf("expected flush error when records cannot be persisted")
+	}
+	if _, err := os.Stat(accountingRecoveryPath(dir)); !os.IsNotExist(err) {
+		t.Fatalf("recovery file written with the option disabled")
+	}
+}
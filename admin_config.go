@@ -1,6 +1,7 @@
 package main
 
 import (
+	"crypto/pbkdf2"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
@@ -18,6 +19,13 @@ import (
 const (
 	defaultAdminSessionExpirationSeconds = 900
 	minAdminPasswordLen                  = 16
+
+	// Admin accounts store PBKDF2-HMAC-SHA256 hashes as
+	// "pbkdf2-sha256$<iterations>$<salt>$<key>" (base64url, no padding).
+	adminPasswordKDFPrefix     = "pbkdf2-sha256"
+	adminPasswordKDFIterations = 600000
+	adminPasswordKDFSaltLen    = 16
+	adminPasswordKDFKeyLen     = 32
 )
 
 var adminConfigTemplate = `# Administrative control panel (hidden by default).
@@ -25,24 +33,75 @@ var adminConfigTemplate = `# Administrative control panel (hidden by default).
 # - Set enabled = true and change the credentials before opening /admin.
 # - The admin UI edits live (in-memory) settings and can request a reboot.
 # - Major actions such as rebooting require re-entering the password and typing REBOOT.
-# - password_sha256 is used for authentication; password can be cleared after first login.
-# - On startup, if password is set, goPool verifies/refreshes password_sha256 to match it.
-# - After a successful admin login, goPool clears password and keeps password_sha256.
+# - password_hash (salted PBKDF2) is used for authentication; password can be cleared
+#   after first login.
+# - On startup, if password is set, goPool verifies/refreshes password_hash to match it.
+# - After a successful admin login, goPool clears password and keeps password_hash.
+# - A legacy unsalted password_sha256 still works and is replaced by password_hash on
+#   the next successful login.
 # - Minimum password length is 16 characters (shorter passwords are replaced on startup).
+# - Extra named accounts go in [[users]] tables (username, password). On startup a
+#   plaintext password of 16+ characters is replaced by a PBKDF2 password_hash.
+#   Deleting a [[users]] entry ends that account's active sessions.
+# - session_secret_rotation_hours > 0 signs sessions with a key kept in the state
+#   database so logins survive restarts; the key is replaced every N hours, which
+#   logs every admin out. 0 (default) keeps sessions in memory only.
 # Keep this file off version control and serve the UI only on trusted networks.
 enabled = %t
 username = %s
 password = %s
-password_sha256 = %s
+password_hash = %s
 session_expiration_seconds = %d
+session_secret_rotation_hours = %d
 `
 
 type adminFileConfig struct {
-	Enabled                  bool   `toml:"enabled"`
-	Username                 string `toml:"username"`
-	Password                 string `toml:"password"`
-	PasswordSHA256           string `toml:"password_sha256"`
-	SessionExpirationSeconds int    `toml:"session_expiration_seconds"`
+	Enabled                  bool              `toml:"enabled"`
+	Username                 string            `toml:"username"`
+	Password                 string            `toml:"password"`
+	PasswordHash             string            `toml:"password_hash"`
+	SessionExpirationSeconds int               `toml:"session_expiration_seconds"`
+	Users                    []adminUserConfig `toml:"users"`
+
+	// Hours between rotations of the persisted session signing key; 0 keeps
+	// sessions in memory only (they end when goPool restarts).
+	SessionSecretRotationHours int `toml:"session_secret_rotation_hours"`
+
+	// PasswordSHA256 is the unsalted hash older releases stored for the
+	// primary account. It is still accepted until the next successful login
+	// or startup with a plaintext password replaces it with PasswordHash.
+	PasswordSHA256 string `toml:"password_sha256"`
+}
+
+// adminUserConfig is an additional named admin account. Only PasswordHash
+// (PBKDF2) is used to log in; Password is a one-time plaintext that startup
+// converts into PasswordHash.
+type adminUserConfig struct {
+	Username     string `toml:"username"`
+	Password     string `toml:"password"`
+	PasswordHash string `toml:"password_hash"`
+}
+
+// hasAccount reports whether username is the primary account or one of the
+// [[users]] accounts.
+func (cfg adminFileConfig) hasAccount(username string) bool {
+	if username == "" {
+		return false
+	}
+	if username == cfg.Username {
+		return true
+	}
+	_, ok := cfg.user(username)
+	return ok
+}
+
+func (cfg adminFileConfig) user(username string) (adminUserConfig, bool) {
+	for _, u := range cfg.Users {
+		if u.Username == username {
+			return u, true
+		}
+	}
+	return adminUserConfig{}, false
 }
 
 func (cfg adminFileConfig) sessionDuration() time.Duration {
@@ -52,21 +111,43 @@ func (cfg adminFileConfig) sessionDuration() time.Duration {
 	return time.Duration(cfg.SessionExpirationSeconds) * time.Second
 }
 
+func (cfg adminFileConfig) sessionSecretRotation() time.Duration {
+	if cfg.SessionSecretRotationHours <= 0 {
+		return 0
+	}
+	return time.Duration(cfg.SessionSecretRotationHours) * time.Hour
+}
+
 func renderAdminConfig(cfg adminFileConfig) string {
 	username := strings.TrimSpace(cfg.Username)
 	if username == "" {
 		username = "admin"
 	}
 	password := strings.TrimSpace(cfg.Password)
-	passwordHash := strings.TrimSpace(cfg.PasswordSHA256)
-	return fmt.Sprintf(
+	passwordHash := strings.TrimSpace(cfg.PasswordHash)
+	out := fmt.Sprintf(
 		adminConfigTemplate,
 		cfg.Enabled,
 		strconv.Quote(username),
 		strconv.Quote(password),
 		strconv.Quote(passwordHash),
 		cfg.SessionExpirationSeconds,
+		cfg.SessionSecretRotationHours,
 	)
+	var b strings.Builder
+	b.WriteString(out)
+	if cfg.PasswordSHA256 != "" {
+		b.WriteString("password_sha256 = " + strconv.Quote(cfg.PasswordSHA256) + "\n")
+	}
+	for _, u := range cfg.Users {
+		b.WriteString("\n[[users]]\n")
+		b.WriteString("username = " + strconv.Quote(u.Username) + "\n")
+		if u.Password != "" {
+			b.WriteString("password = " + strconv.Quote(u.Password) + "\n")
+		}
+		b.WriteString("password_hash = " + strconv.Quote(u.PasswordHash) + "\n")
+	}
+	return b.String()
 }
 
 func ensureAdminConfigFile(dataDir string) (string, error) {
@@ -84,11 +165,15 @@ func ensureAdminConfigFile(dataDir string) (string, error) {
 		if err != nil {
 			return "", fmt.Errorf("generate admin password: %w", err)
 		}
+		hash, err := adminPasswordKDF(password, adminPasswordKDFIterations)
+		if err != nil {
+			return "", fmt.Errorf("hash admin password: %w", err)
+		}
 		cfg := adminFileConfig{
 			Enabled:                  false,
 			Username:                 "admin",
 			Password:                 password,
-			PasswordSHA256:           adminPasswordHash(password),
+			PasswordHash:             hash,
 			SessionExpirationSeconds: defaultAdminSessionExpirationSeconds,
 		}
 		if err := os.WriteFile(adminPath, []byte(renderAdminConfig(cfg)), 0o600); err != nil {
@@ -103,20 +188,12 @@ func ensureAdminConfigFile(dataDir string) (string, error) {
 			return "", err
 		}
 		needsRewrite := false
-		if cfg.Password != "" {
-			hash := adminPasswordHash(cfg.Password)
-			if cfg.PasswordSHA256 == "" || !strings.EqualFold(cfg.PasswordSHA256, hash) {
-				cfg.PasswordSHA256 = hash
-				needsRewrite = true
-			}
-		}
-		if cfg.Password == "" && cfg.PasswordSHA256 == "" {
+		if cfg.Password == "" && cfg.PasswordHash == "" && cfg.PasswordSHA256 == "" {
 			password, err := generateAdminPassword()
 			if err != nil {
 				return "", fmt.Errorf("generate admin password: %w", err)
 			}
 			cfg.Password = password
-			cfg.PasswordSHA256 = adminPasswordHash(password)
 			needsRewrite = true
 			logger.Warn("admin password was missing; generated a new one", "path", adminPath)
 		}
@@ -126,10 +203,21 @@ func ensureAdminConfigFile(dataDir string) (string, error) {
 				return "", fmt.Errorf("generate admin password: %w", err)
 			}
 			cfg.Password = password
-			cfg.PasswordSHA256 = adminPasswordHash(password)
 			needsRewrite = true
 			logger.Warn("admin password was missing/weak; generated a new one", "path", adminPath)
 		}
+		if cfg.Password != "" && (cfg.PasswordSHA256 != "" || !verifyAdminPasswordKDF(cfg.PasswordHash, cfg.Password)) {
+			hash, err := adminPasswordKDF(cfg.Password, adminPasswordKDFIterations)
+			if err != nil {
+				return "", fmt.Errorf("hash admin password: %w", err)
+			}
+			cfg.PasswordHash = hash
+			cfg.PasswordSHA256 = ""
+			needsRewrite = true
+		}
+		if hashAdminUserPasswords(&cfg, adminPath) {
+			needsRewrite = true
+		}
 		if needsRewrite {
 			if err := atomicWriteFileMode(adminPath, []byte(renderAdminConfig(cfg)), 0o600); err != nil {
 				return "", fmt.Errorf("rewrite %s: %w", adminPath, err)
@@ -151,13 +239,58 @@ func loadAdminConfigFile(path string) (adminFileConfig, error) {
 	}
 	cfg.Username = strings.TrimSpace(cfg.Username)
 	cfg.Password = strings.TrimSpace(cfg.Password)
+	cfg.PasswordHash = strings.TrimSpace(cfg.PasswordHash)
 	cfg.PasswordSHA256 = strings.TrimSpace(strings.ToLower(cfg.PasswordSHA256))
 	if cfg.SessionExpirationSeconds <= 0 {
 		cfg.SessionExpirationSeconds = defaultAdminSessionExpirationSeconds
 	}
+	users := cfg.Users[:0]
+	seen := make(map[string]struct{}, len(cfg.Users))
+	for _, u := range cfg.Users {
+		u.Username = strings.TrimSpace(u.Username)
+		u.Password = strings.TrimSpace(u.Password)
+		u.PasswordHash = strings.TrimSpace(u.PasswordHash)
+		if u.Username == "" || u.Username == cfg.Username {
+			logger.Warn("ignoring admin user with empty or primary username", "path", path, "username", u.Username)
+			continue
+		}
+		if _, dup := seen[u.Username]; dup {
+			logger.Warn("ignoring duplicate admin user", "path", path, "username", u.Username)
+			continue
+		}
+		seen[u.Username] = struct{}{}
+		users = append(users, u)
+	}
+	cfg.Users = users
 	return cfg, nil
 }
 
+// hashAdminUserPasswords replaces plaintext [[users]] passwords with PBKDF2
+// hashes. Passwords shorter than minAdminPasswordLen are left in place and
+// the account cannot log in until it is fixed. It reports whether cfg changed.
+func hashAdminUserPasswords(cfg *adminFileConfig, path string) bool {
+	changed := false
+	for i := range cfg.Users {
+		u := &cfg.Users[i]
+		if u.Password == "" {
+			continue
+		}
+		if len(u.Password) < minAdminPasswordLen {
+			logger.Warn("admin user password too short; account disabled until fixed", "path", path, "username", u.Username, "min_len", minAdminPasswordLen)
+			continue
+		}
+		hash, err := adminPasswordKDF(u.Password, adminPasswordKDFIterations)
+		if err != nil {
+			logger.Warn("hash admin user password failed", "path", path, "username", u.Username, "error", err)
+			continue
+		}
+		u.PasswordHash = hash
+		u.Password = ""
+		changed = true
+	}
+	return changed
+}
+
 func atomicWriteFile(path string, data []byte) error {
 	return atomicWriteFileMode(path, data, 0o644)
 }
@@ -210,6 +343,49 @@ func generateAdminPassword() (string, error) {
 	return base64.RawURLEncoding.EncodeToString(buf), nil
 }
 
+// adminPasswordKDF derives an encoded PBKDF2-HMAC-SHA256 hash of password
+// with a random salt.
+func adminPasswordKDF(password string, iterations int) (string, error) {
+	salt := make([]byte, adminPasswordKDFSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key, err := pbkdf2.Key(sha256.New, password, salt, iterations, adminPasswordKDFKeyLen)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s$%d$%s$%s", adminPasswordKDFPrefix, iterations,
+		base64.RawURLEncoding.EncodeToString(salt), base64.RawURLEncoding.EncodeToString(key)), nil
+}
+
+// verifyAdminPasswordKDF checks password against an adminPasswordKDF hash.
+// Malformed hashes never match.
+func verifyAdminPasswordKDF(encoded, password string) bool {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 4 || parts[0] != adminPasswordKDFPrefix {
+		return false
+	}
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations <= 0 {
+		return false
+	}
+	salt, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || len(salt) == 0 {
+		return false
+	}
+	want, err := base64.RawURLEncoding.DecodeString(parts[3])
+	if err != nil || len(want) == 0 {
+		return false
+	}
+	got, err := pbkdf2.Key(sha256.New, password, salt, iterations, len(want))
+	if err != nil {
+		return false
+	}
+	return compareStringsConstantTime(string(got), string(want))
+}
+
+// adminPasswordHash is the unsalted SHA-256 older releases stored as
+// password_sha256. It is only used to verify those legacy values.
 func adminPasswordHash(password string) string {
 	sum := sha256.Sum256([]byte(password))
 	return hexEncode32LowerString(&sum)
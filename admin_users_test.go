@@ -0,0 +1,181 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAdminPasswordKDFRoundTrip(t *testing.T) {
+	hash, err := adminPasswordKDF("correct horse battery", 1000)
+	if err != nil {
+		t.Fatalf("kdf: %v", err)
+	}
+	if !strings.HasPrefix(hash, adminPasswordKDFPrefix+"$1000$") || strings.Contains(hash, "correct") {
+		t.Fatalf("unexpected hash encoding: %q", hash)
+	}
+	if !verifyAdminPasswordKDF(hash, "correct horse battery") {
+		t.Fatalf("expected password to verify")
+	}
+	if verifyAdminPasswordKDF(hash, "wrong horse battery") {
+		t.Fatalf("wrong password verified")
+	}
+	for _, bad := range []string{"", "abc", adminPasswordHash("correct horse battery"), "pbkdf2-sha256$0$AA$AA"} {
+		if verifyAdminPasswordKDF(bad, "correct horse battery") {
+			t.Fatalf("malformed hash %q verified", bad)
+		}
+	}
+}
+
+func TestAdminUsersHashedOnStartupAndSessionsEndOnRemoval(t *testing.T) {
+	dir := t.TempDir()
+	adminPath := filepath.Join(dir, "config", "admin.toml")
+	cfg := adminFileConfig{
+		Enabled:                  true,
+		Username:                 "admin",
+		PasswordSHA256:           adminPasswordHash("primary-password-123"),
+		SessionExpirationSeconds: 900,
+		Users: []adminUserConfig{
+			{Username: "alice", Password: "alice-password-1234"},
+			{Username: "bob", Password: "short"},
+		},
+	}
+	if err := os.MkdirAll(filepath.Dir(adminPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(adminPath, []byte(renderAdminConfig(cfg)), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := ensureAdminConfigFile(dir); err != nil {
+		t.Fatalf("ensureAdminConfigFile: %v", err)
+	}
+	raw, err := os.ReadFile(adminPath)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if strings.Contains(string(raw), "alice-password-1234") {
+		t.Fatalf("plaintext password left in admin.toml")
+	}
+	loaded, err := loadAdminConfigFile(adminPath)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	alice, ok := loaded.user("alice")
+	if !ok || !strings.HasPrefix(alice.PasswordHash, adminPasswordKDFPrefix+"$") {
+		t.Fatalf("expected alice to get a kdf hash, got %+v", alice)
+	}
+
+	s := &StatusServer{adminConfigPath: adminPath, adminSessions: make(map[string]adminSession)}
+	if !s.adminCredentialsMatch(loaded, "alice", "alice-password-1234") {
+		t.Fatalf("alice should be able to log in")
+	}
+	if s.adminCredentialsMatch(loaded, "alice", "primary-password-123") {
+		t.Fatalf("alice must not accept the primary password")
+	}
+	if s.adminCredentialsMatch(loaded, "bob", "short") {
+		t.Fatalf("account with a too-short plaintext password must not log in")
+	}
+	if !s.adminCredentialsMatch(loaded, "admin", "primary-password-123") {
+		t.Fatalf("primary account should still log in")
+	}
+
+	token, _, err := s.createAdminSession("alice", time.Minute, 0)
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	req.AddCookie(&http.Cookie{Name: adminSessionCookieName, Value: token})
+	if !s.isAdminAuthenticated(req) {
+		t.Fatalf("expected alice session to be valid")
+	}
+	if !s.adminReauthMatches(req, loaded, "alice-password-1234") || s.adminReauthMatches(req, loaded, "primary-password-123") {
+		t.Fatalf("re-auth must check the session owner's password")
+	}
+
+	loaded.Users = loaded.Users[1:]
+	if err := os.WriteFile(adminPath, []byte(renderAdminConfig(loaded)), 0o600); err != nil {
+		t.Fatalf("rewrite: %v", err)
+	}
+	if s.isAdminAuthenticated(req) {
+		t.Fatalf("session must end once the account is removed")
+	}
+	if s.activeAdminSessionCount() != 0 {
+		t.Fatalf("expected removed account's session to be deleted")
+	}
+}
+
+func TestAdminPrimaryPasswordMigratesToKDF(t *testing.T) {
+	dir := t.TempDir()
+	adminPath := filepath.Join(dir, "config", "admin.toml")
+	if err := os.MkdirAll(filepath.Dir(adminPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	legacy := adminFileConfig{
+		Enabled:        true,
+		Username:       "admin",
+		PasswordSHA256: adminPasswordHash("primary-password-123"),
+	}
+	if err := os.WriteFile(adminPath, []byte(renderAdminConfig(legacy)), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	// Without a plaintext password startup cannot rehash, so the legacy
+	// hash is kept and still logs in.
+	if _, err := ensureAdminConfigFile(dir); err != nil {
+		t.Fatalf("ensureAdminConfigFile: %v", err)
+	}
+	loaded, err := loadAdminConfigFile(adminPath)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if loaded.PasswordSHA256 != legacy.PasswordSHA256 || loaded.PasswordHash != "" {
+		t.Fatalf("startup changed legacy hash: %+v", loaded)
+	}
+	s := &StatusServer{adminConfigPath: adminPath, adminSessions: make(map[string]adminSession)}
+	if !s.adminCredentialsMatch(loaded, "admin", "primary-password-123") {
+		t.Fatalf("legacy password_sha256 should still log in")
+	}
+
+	// A successful login replaces it with a PBKDF2 hash.
+	if err := s.scrubAdminPasswordPlaintext(loaded, "primary-password-123"); err != nil {
+		t.Fatalf("scrub: %v", err)
+	}
+	raw, err := os.ReadFile(adminPath)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if strings.Contains(string(raw), "password_sha256 =") {
+		t.Fatalf("legacy password_sha256 left in admin.toml:\n%s", raw)
+	}
+	loaded, err = loadAdminConfigFile(adminPath)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !strings.HasPrefix(loaded.PasswordHash, adminPasswordKDFPrefix+"$") {
+		t.Fatalf("expected a kdf password_hash, got %q", loaded.PasswordHash)
+	}
+	if !s.adminCredentialsMatch(loaded, "admin", "primary-password-123") {
+		t.Fatalf("primary account should log in with the migrated hash")
+	}
+	if s.adminCredentialsMatch(loaded, "admin", "wrong-password-1234") {
+		t.Fatalf("wrong password verified against the migrated hash")
+	}
+
+	// A generated admin.toml uses the KDF from the start.
+	fresh := t.TempDir()
+	freshPath, err := ensureAdminConfigFile(fresh)
+	if err != nil {
+		t.Fatalf("ensureAdminConfigFile: %v", err)
+	}
+	generated, err := loadAdminConfigFile(freshPath)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if generated.PasswordSHA256 != "" || !verifyAdminPasswordKDF(generated.PasswordHash, generated.Password) {
+		t.Fatalf("generated admin.toml should carry a kdf hash of its password: %+v", generated)
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	autoProfileCheckInterval      = 30 * time.Second
+	defaultAutoProfileMinInterval = time.Hour
+	// autoProfileDirName lives in <data_dir>/state.
+	autoProfileDirName = "profiles"
+	// maxAutoProfileCaptures bounds how many capture sets are kept on disk.
+	maxAutoProfileCaptures = 10
+)
+
+// autoProfileTrigger describes why a capture fired.
+type autoProfileTrigger struct {
+	goroutines int
+	heapBytes  uint64
+	reasons    []string
+}
+
+// checkAutoProfileThresholds returns a trigger when goroutine count or live
+// heap crosses the configured limits (0 disables a limit).
+func checkAutoProfileThresholds(cfg Config, goroutines int, heapBytes uint64) (autoProfileTrigger, bool) {
+	t := autoProfileTrigger{goroutines: goroutines, heapBytes: heapBytes}
+	if cfg.AutoProfileGoroutines > 0 && goroutines >= cfg.AutoProfileGoroutines {
+		t.reasons = append(t.reasons, "goroutines")
+	}
+	if cfg.AutoProfileHeapMB > 0 && heapBytes >= uint64(cfg.AutoProfileHeapMB)<<20 {
+		t.reasons = append(t.reasons, "heap")
+	}
+	return t, len(t.reasons) > 0
+}
+
+func autoProfileDir(dataDir string) string {
+	if dataDir == "" {
+		dataDir = defaultDataDir
+	}
+	return filepath.Join(dataDir, "state", autoProfileDirName)
+}
+
+// runAutoProfiler samples goroutine count and heap size and writes a
+// goroutine dump plus heap profile when a threshold is crossed, at most once
+// per auto_profile_interval_seconds.
+func runAutoProfiler(ctx context.Context, configFn func() Config) {
+	var lastCapture time.Time
+	ticker := time.NewTicker(autoProfileCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		cfg := configFn()
+		if cfg.AutoProfileGoroutines <= 0 && cfg.AutoProfileHeapMB <= 0 {
+			continue
+		}
+		var ms runtime.MemStats
+		runtime.ReadMemStats(&ms)
+		trigger, hit := checkAutoProfileThresholds(cfg, runtime.NumGoroutine(), ms.HeapAlloc)
+		if !hit {
+			continue
+		}
+		now := time.Now()
+		interval := cfg.AutoProfileMinInterval
+		if interval <= 0 {
+			interval = defaultAutoProfileMinInterval
+		}
+		if !lastCapture.IsZero() && now.Sub(lastCapture) < interval {
+			continue
+		}
+		lastCapture = now
+		dir := autoProfileDir(cfg.DataDir)
+		fields := []any{"component", "profile", "kind", "auto_capture",
+			"trigger", strings.Join(trigger.reasons, ","),
+			"goroutines", trigger.goroutines, "goroutine_threshold", cfg.AutoProfileGoroutines,
+			"heap_mb", trigger.heapBytes >> 20, "heap_threshold_mb", cfg.AutoProfileHeapMB,
+			"dir", dir,
+		}
+		if err := captureAutoProfiles(dir, now); err != nil {
+			logger.Warn("auto profile capture failed", append(fields, "error", err)...)
+			continue
+		}
+		logger.Warn("auto profile captured", append(fields, "next_after", now.Add(interval))...)
+	}
+}
+
+// captureAutoProfiles writes goroutine-<ts>.txt and heap-<ts>.pb.gz to dir
+// and prunes older captures beyond maxAutoProfileCaptures.
+func captureAutoProfiles(dir string, now time.Time) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	stamp := now.UTC().Format("20060102T150405Z")
+	if err := writeAutoProfile(filepath.Join(dir, "goroutine-"+stamp+".txt"), func(w io.Writer) error {
+		return pprof.Lookup("goroutine").WriteTo(w, 2)
+	}); err != nil {
+		return fmt.Errorf("goroutine dump: %w", err)
+	}
+	if err := writeAutoProfile(filepath.Join(dir, "heap-"+stamp+".pb.gz"), pprof.WriteHeapProfile); err != nil {
+		return fmt.Errorf("heap profile: %w", err)
+	}
+	pruneAutoProfiles(dir, maxAutoProfileCaptures)
+	return nil
+}
+
+func writeAutoProfile(path string, write func(io.Writer) error) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	err = write(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// pruneAutoProfiles keeps the newest keep captures of each kind.
+func pruneAutoProfiles(dir string, keep int) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	byKind := map[string][]string{}
+	for _, e := range entries {
+		name := e.Name()
+		switch {
+		case strings.HasPrefix(name, "goroutine-") && strings.HasSuffix(name, ".txt"):
+			byKind["goroutine"] = append(byKind["goroutine"], name)
+		case strings.HasPrefix(name, "heap-") && strings.HasSuffix(name, ".pb.gz"):
+			byKind["heap"] = append(byKind["heap"], name)
+		}
+	}
+	for _, names := range byKind {
+		if len(names) <= keep {
+			continue
+		}
+		// Timestamps sort lexically, oldest first.
+		sort.Strings(names)
+		for _, name := range names[:len(names)-keep] {
+			_ = os.Remove(filepath.Join(dir, name))
+		}
+	}
+}
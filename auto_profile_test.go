@@ -0,0 +1,48 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCheckAutoProfileThresholds(t *testing.T) {
+	cfg := Config{AutoProfileGoroutines: 100, AutoProfileHeapMB: 64}
+	if _, hit := checkAutoProfileThresholds(cfg, 99, 63<<20); hit {
+		t.Fatalf("below both limits should not trigger")
+	}
+	tr, hit := checkAutoProfileThresholds(cfg, 100, 64<<20)
+	if !hit || len(tr.reasons) != 2 {
+		t.Fatalf("expected both triggers, got %+v", tr)
+	}
+	if _, hit := checkAutoProfileThresholds(Config{}, 1_000_000, 1<<40); hit {
+		t.Fatalf("disabled limits must never trigger")
+	}
+}
+
+func TestCaptureAutoProfilesWritesAndPrunes(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "profiles")
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i := 0; i < maxAutoProfileCaptures+2; i++ {
+		if err := captureAutoProfiles(dir, start.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("capture %d: %v", i, err)
+		}
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	if len(entries) != 2*maxAutoProfileCaptures {
+		t.Fatalf("expected %d files after pruning, got %d", 2*maxAutoProfileCaptures, len(entries))
+	}
+	for _, name := range []string{"goroutine-20260102T030405Z.txt", "heap-20260102T030405Z.pb.gz"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			t.Fatalf("oldest capture %s should have been pruned", name)
+		}
+	}
+	info, err := os.Stat(filepath.Join(dir, "goroutine-20260102T031505Z.txt"))
+	if err != nil || info.Size() == 0 {
+		t.Fatalf("expected newest goroutine dump, err=%v", err)
+	}
+}
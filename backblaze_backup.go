@@ -80,10 +80,15 @@ const (
 
 func newBackblazeBackupService(ctx context.Context, cfg Config, dbPath string) (*backblazeBackupService, error) {
 	b2Enabled := backblazeCloudConfigured(cfg)
-	if cfg.BackblazeBackupEnabled && !b2Enabled {
+	// In offline mode an enabled B2 backup keeps running as local snapshots.
+	localOnly := cfg.OfflineMode && cfg.BackblazeBackupEnabled
+	switch {
+	case localOnly:
+		logger.Info("backblaze cloud backups disabled; keeping local snapshots", "reason", "offline_mode")
+	case cfg.BackblazeBackupEnabled && !b2Enabled:
 		logger.Info("backblaze cloud backups disabled", "reason", "backblaze_backup.bucket, backblaze_account_id, and backblaze_application_key are required")
 	}
-	if !b2Enabled && !cfg.BackblazeKeepLocalCopy && strings.TrimSpace(cfg.BackupSnapshotPath) == "" {
+	if !b2Enabled && !localOnly && !cfg.BackblazeKeepLocalCopy && strings.TrimSpace(cfg.BackupSnapshotPath) == "" {
 		return nil, nil
 	}
 	if dbPath == "" {
@@ -156,7 +161,7 @@ func newBackblazeBackupService(ctx context.Context, cfg Config, dbPath string) (
 	// Additionally, when B2 is enabled, always write a local snapshot by default
 	// even if keep_local_copy is disabled. This guarantees operators have a local
 	// "safe to copy while running" snapshot regardless of B2 health.
-	if svc.snapshotPath == "" && (cfg.BackblazeKeepLocalCopy || b2Enabled) {
+	if svc.snapshotPath == "" && (cfg.BackblazeKeepLocalCopy || b2Enabled || localOnly) {
 		svc.snapshotPath = filepath.Join(stateDir, filepath.Base(dbPath)+backupLocalCopySuffix)
 	}
 	return svc, nil
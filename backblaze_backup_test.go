@@ -162,6 +162,44 @@ func TestBackups_CloudEnabledWithoutCredentials_DoesNotStartServiceWhenNoLocalBa
 	}
 }
 
+func TestBackups_OfflineModeKeepsLocalSnapshotsOnly(t *testing.T) {
+	tmp := t.TempDir()
+	cfg := defaultConfig()
+	cfg.DataDir = tmp
+	cfg.OfflineMode = true
+	cfg.BackblazeBackupEnabled = true
+	cfg.BackblazeKeepLocalCopy = false
+	cfg.BackupSnapshotPath = ""
+	cfg.BackblazeBucket = "bucket"
+	cfg.BackblazeAccountID = "account"
+	cfg.BackblazeApplicationKey = "key"
+
+	dbPath := filepath.Join(cfg.DataDir, "state", "workers.db")
+	createTestWorkerDB(t, dbPath)
+
+	db, err := openStateDB(dbPath)
+	if err != nil {
+		t.Fatalf("openStateDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	cleanup := setSharedStateDBForTest(db)
+	t.Cleanup(cleanup)
+
+	svc, err := newBackblazeBackupService(context.Background(), cfg, dbPath)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	if svc == nil {
+		t.Fatalf("expected a local-only service in offline mode")
+	}
+	if svc.b2Enabled || svc.bucket != nil {
+		t.Fatalf("expected B2 to stay disabled in offline mode")
+	}
+	if svc.snapshotPath == "" {
+		t.Fatalf("expected a local snapshot path in offline mode")
+	}
+}
+
 func TestBackups_RunOnInterval_EvenWhenDBUnchanged(t *testing.T) {
 	tmp := t.TempDir()
 	cfg := defaultConfig()
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	banFeedMaxBytes  = 16 << 20
+	banFeedCacheFile = "ban_feed_cache.txt"
+)
+
+// banFeed holds IP/CIDR bans imported from an external list (a URL or a local
+// file). Imported bans are kept apart from the worker bans in the accounting
+// database: they are never written to the bans table and cannot be removed
+// from the admin panel, and each successful refresh replaces the whole set,
+// so an entry stays banned for exactly as long as the feed lists it. A failed
+// refresh keeps the last good list, which is also cached on disk so it
+// applies again right after a restart.
+type banFeed struct {
+	source    string
+	cachePath string
+	client    *http.Client
+
+	mu          sync.RWMutex
+	prefixes    []netip.Prefix
+	updatedAt   time.Time
+	lastErr     string
+	lastErrAt   time.Time
+	skippedRows int
+}
+
+// banFeedStatus is the admin-facing summary of the imported list.
+type banFeedStatus struct {
+	Source      string
+	Entries     int
+	SkippedRows int
+	UpdatedAt   time.Time
+	LastError   string
+	LastErrorAt time.Time
+}
+
+func banFeedConfigured(cfg Config) bool {
+	src := strings.TrimSpace(cfg.BanFeedSource)
+	if src == "" {
+		return false
+	}
+	return !cfg.OfflineMode || !banFeedIsURL(src)
+}
+
+func banFeedIsURL(src string) bool {
+	lower := strings.ToLower(src)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+func newBanFeed(cfg Config) *banFeed {
+	if !banFeedConfigured(cfg) {
+		return nil
+	}
+	dataDir := cfg.DataDir
+	if dataDir == "" {
+		dataDir = defaultDataDir
+	}
+	return &banFeed{
+		source:    strings.TrimSpace(cfg.BanFeedSource),
+		cachePath: filepath.Join(dataDir, "state", banFeedCacheFile),
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// parseBanFeed reads one IP or CIDR per line. Anything after the first field
+// is ignored, as are blank lines and lines starting with '#' or ';', which
+// covers plain lists as well as the common "1.2.3.0/24 ; note" feed format.
+func parseBanFeed(data []byte) (prefixes []netip.Prefix, skipped int) {
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 4096), 64*1024)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], ";") {
+			continue
+		}
+		entry := strings.TrimRight(fields[0], ";,")
+		if p, err := netip.ParsePrefix(entry); err == nil {
+			prefixes = append(prefixes, p.Masked())
+			continue
+		}
+		if addr, err := netip.ParseAddr(entry); err == nil {
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		skipped++
+	}
+	return prefixes, skipped
+}
+
+func formatBanFeed(prefixes []netip.Prefix) []byte {
+	var b bytes.Buffer
+	b.WriteString("# goPool imported ban feed cache; rewritten on each successful refresh.\n")
+	for _, p := range prefixes {
+		b.WriteString(p.String())
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
+
+func (f *banFeed) fetch(ctx context.Context) ([]byte, error) {
+	if !banFeedIsURL(f.source) {
+		return os.ReadFile(f.source)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ban feed http status %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, banFeedMaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > banFeedMaxBytes {
+		return nil, fmt.Errorf("ban feed larger than %d bytes", banFeedMaxBytes)
+	}
+	return data, nil
+}
+
+// refresh fetches and swaps in the feed. On any failure, including a feed
+// with no usable entries (usually an error page or a truncated download), the
+// current list is kept.
+func (f *banFeed) refresh(ctx context.Context, now time.Time) error {
+	if f == nil {
+		return nil
+	}
+	data, err := f.fetch(ctx)
+	var prefixes []netip.Prefix
+	var skipped int
+	if err == nil {
+		prefixes, skipped = parseBanFeed(data)
+		if len(prefixes) == 0 {
+			err = fmt.Errorf("ban feed has no valid entries (%d unparsed lines)", skipped)
+		}
+	}
+	f.mu.Lock()
+	if err != nil {
+		f.lastErr = err.Error()
+		f.lastErrAt = now
+		f.mu.Unlock()
+		return err
+	}
+	f.prefixes = prefixes
+	f.skippedRows = skipped
+	f.updatedAt = now
+	f.lastErr = ""
+	f.lastErrAt = time.Time{}
+	f.mu.Unlock()
+
+	if err := atomicWriteFile(f.cachePath, formatBanFeed(prefixes)); err != nil {
+		logger.Warn("write ban feed cache", "component", "bans", "kind", "ban_feed", "path", f.cachePath, "error", err)
+	}
+	return nil
+}
+
+// loadCache restores the last imported list so it is enforced before the
+// first refresh completes.
+func (f *banFeed) loadCache() {
+	if f == nil {
+		return
+	}
+	data, err := os.ReadFile(f.cachePath)
+	if err != nil {
+		return
+	}
+	prefixes, _ := parseBanFeed(data)
+	if len(prefixes) == 0 {
+		return
+	}
+	var updatedAt time.Time
+	if info, err := os.Stat(f.cachePath); err == nil {
+		updatedAt = info.ModTime()
+	}
+	f.mu.Lock()
+	if f.prefixes == nil {
+		f.prefixes = prefixes
+		f.updatedAt = updatedAt
+	}
+	f.mu.Unlock()
+}
+
+// blocked reports whether host (an IP without port) is covered by the feed.
+func (f *banFeed) blocked(host string) bool {
+	if f == nil {
+		return false
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for _, p := range f.prefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *banFeed) status() banFeedStatus {
+	if f == nil {
+		return banFeedStatus{}
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return banFeedStatus{
+		Source:      f.source,
+		Entries:     len(f.prefixes),
+		SkippedRows: f.skippedRows,
+		UpdatedAt:   f.updatedAt,
+		LastError:   f.lastErr,
+		LastErrorAt: f.lastErrAt,
+	}
+}
+
+func (f *banFeed) run(ctx context.Context, interval time.Duration) {
+	if f == nil {
+		return
+	}
+	if interval <= 0 {
+		interval = defaultBanFeedRefreshSeconds * time.Second
+	}
+	refresh := func() {
+		if err := f.refresh(ctx, time.Now()); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			st := f.status()
+			logger.Warn("ban feed refresh failed; keeping last imported list",
+				"component", "bans", "kind", "ban_feed",
+				"source", f.source,
+				"entries", st.Entries,
+				"error", err,
+			)
+			return
+		}
+		st := f.status()
+		logger.Info("ban feed refreshed",
+			"component", "bans", "kind", "ban_feed",
+			"source", f.source,
+			"entries", st.Entries,
+			"skipped", st.SkippedRows,
+		)
+	}
+	refresh()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			refresh()
+		}
+	}
+}
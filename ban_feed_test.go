@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseBanFeed(t *testing.T) {
+	data := []byte(`# comment
+; spamhaus-style comment
+192.0.2.0/24 ; SBL123
+198.51.100.7
+2001:db8::/32
+::ffff:203.0.113.9
+not-an-ip
+10.0.0.1/33
+`)
+	prefixes, skipped := parseBanFeed(data)
+	if len(prefixes) != 4 || skipped != 2 {
+		t.Fatalf("parsed %d prefixes (%v), skipped %d; want 4 and 2", len(prefixes), prefixes, skipped)
+	}
+	f := &banFeed{prefixes: prefixes}
+	for host, want := range map[string]bool{
+		"192.0.2.200":      true,
+		"198.51.100.7":     true,
+		"198.51.100.8":     false,
+		"2001:db8::1":      true,
+		"203.0.113.9":      true,
+		"::ffff:192.0.2.1": true,
+		"not-a-host":       false,
+	} {
+		if got := f.blocked(host); got != want {
+			t.Fatalf("blocked(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestBanFeedRefreshKeepsLastListOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "feed.txt")
+	cfg := defaultConfig()
+	cfg.DataDir = dir
+	cfg.BanFeedSource = src
+	f := newBanFeed(cfg)
+	ctx := context.Background()
+
+	if err := os.WriteFile(src, []byte("192.0.2.1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.refresh(ctx, time.Now()); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+
+	// A missing file and a feed with nothing usable both keep the old list.
+	if err := os.Remove(src); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.refresh(ctx, time.Now()); err == nil {
+		t.Fatalf("expected error for missing feed")
+	}
+	if err := os.WriteFile(src, []byte("<html>error</html>\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.refresh(ctx, time.Now()); err == nil {
+		t.Fatalf("expected error for feed without entries")
+	}
+	if !f.blocked("192.0.2.1") || f.status().LastError == "" {
+		t.Fatalf("failed refresh dropped the last imported list: %+v", f.status())
+	}
+
+	// A restart restores the cached list before the first refresh.
+	restarted := newBanFeed(cfg)
+	restarted.loadCache()
+	if !restarted.blocked("192.0.2.1") {
+		t.Fatalf("cached list not restored after restart")
+	}
+
+	// A successful refresh replaces the list wholesale.
+	if err := os.WriteFile(src, []byte("198.51.100.0/24\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.refresh(ctx, time.Now()); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if f.blocked("192.0.2.1") || !f.blocked("198.51.100.20") || f.status().LastError != "" {
+		t.Fatalf("successful refresh did not replace the list: %+v", f.status())
+	}
+}
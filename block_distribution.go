@@ -0,0 +1,241 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+// Roles assigned to coinbase outputs in a recorded reward distribution.
+const (
+	rewardRolePool              = "pool_fee"
+	rewardRoleDonation          = "donation"
+	rewardRoleWorker            = "worker"
+	rewardRoleWitnessCommitment = "witness_commitment"
+	rewardRoleOther             = "other"
+)
+
+// blockRewardOutput is one output of the coinbase that was actually submitted.
+type blockRewardOutput struct {
+	Index     int    `json:"index"`
+	Role      string `json:"role"`
+	ValueSats int64  `json:"value_sats"`
+	ScriptHex string `json:"script_hex"`
+	Address   string `json:"address,omitempty"`
+}
+
+// blockRewardDistribution is the audit record of how a found block's reward
+// was split. It is decoded from the submitted block itself, so CoinbaseTxid
+// and Outputs can be checked against the chain.
+type blockRewardDistribution struct {
+	CoinbaseTxid      string              `json:"coinbase_txid"`
+	CoinbaseValueSats int64               `json:"coinbase_value_sats"`
+	Worker            string              `json:"worker,omitempty"`
+	Outputs           []blockRewardOutput `json:"outputs"`
+}
+
+// coinbaseTxFromBlock returns the coinbase transaction of a serialized block
+// and its txid (internal byte order). Witness data, if present, is excluded
+// from the txid as consensus requires.
+func coinbaseTxFromBlock(block []byte) (tx []byte, txid []byte, err error) {
+	if len(block) < 81 {
+		return nil, nil, fmt.Errorf("block too short")
+	}
+	pos := 80
+	txCount, p, ok := decodeVarInt(block, pos)
+	if !ok || txCount == 0 {
+		return nil, nil, fmt.Errorf("invalid block tx count")
+	}
+	pos = p
+	start := pos
+
+	if pos+4 > len(block) {
+		return nil, nil, fmt.Errorf("truncated coinbase version")
+	}
+	pos += 4
+	segwit := pos+2 <= len(block) && block[pos] == 0x00 && block[pos+1] == 0x01
+	if segwit {
+		pos += 2
+	}
+	bodyStart := pos
+
+	skipScript := func() bool {
+		n, p, ok := decodeVarInt(block, pos)
+		if !ok || p+int(n) > len(block) || int(n) < 0 {
+			return false
+		}
+		pos = p + int(n)
+		return true
+	}
+
+	vinCount, p, ok := decodeVarInt(block, pos)
+	if !ok || vinCount == 0 {
+		return nil, nil, fmt.Errorf("invalid coinbase input count")
+	}
+	pos = p
+	for range vinCount {
+		if pos+36 > len(block) {
+			return nil, nil, fmt.Errorf("truncated coinbase input")
+		}
+		pos += 36
+		if !skipScript() || pos+4 > len(block) {
+			return nil, nil, fmt.Errorf("truncated coinbase input")
+		}
+		pos += 4
+	}
+	voutCount, p, ok := decodeVarInt(block, pos)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid coinbase output count")
+	}
+	pos = p
+	for range voutCount {
+		if pos+8 > len(block) {
+			return nil, nil, fmt.Errorf("truncated coinbase output")
+		}
+		pos += 8
+		if !skipScript() {
+			return nil, nil, fmt.Errorf("truncated coinbase output")
+		}
+	}
+	bodyEnd := pos
+	if segwit {
+		for range vinCount {
+			items, p, ok := decodeVarInt(block, pos)
+			if !ok {
+				return nil, nil, fmt.Errorf("truncated coinbase witness")
+			}
+			pos = p
+			for range items {
+				if !skipScript() {
+					return nil, nil, fmt.Errorf("truncated coinbase witness")
+				}
+			}
+		}
+	}
+	if pos+4 > len(block) {
+		return nil, nil, fmt.Errorf("truncated coinbase locktime")
+	}
+	pos += 4
+	tx = block[start:pos]
+
+	if !segwit {
+		return tx, doubleSHA256(tx), nil
+	}
+	var stripped bytes.Buffer
+	stripped.Write(block[start : start+4])
+	stripped.Write(block[bodyStart:bodyEnd])
+	stripped.Write(block[pos-4 : pos])
+	return tx, doubleSHA256(stripped.Bytes()), nil
+}
+
+// rewardDistributionFromBlock decodes the submitted block's coinbase and
+// labels each output by comparing its script against the pool, donation and
+// worker payout scripts.
+func (mc *MinerConn) rewardDistributionFromBlock(job *Job, worker, blockHex string) (*blockRewardDistribution, error) {
+	raw, err := hex.DecodeString(blockHex)
+	if err != nil {
+		return nil, fmt.Errorf("decode block: %w", err)
+	}
+	tx, txid, err := coinbaseTxFromBlock(raw)
+	if err != nil {
+		return nil, err
+	}
+	detail := &ShareDetail{Coinbase: hex.EncodeToString(tx)}
+	detail.DecodeCoinbaseFields()
+	if len(detail.CoinbaseOutputs) == 0 {
+		return nil, fmt.Errorf("coinbase has no decodable outputs")
+	}
+
+	var poolScript, donationScript []byte
+	if job != nil {
+		poolScript = job.PayoutScript
+		donationScript = job.DonationScript
+	}
+	workerScript := mc.workerPayoutScript(worker)
+
+	dist := &blockRewardDistribution{
+		CoinbaseTxid:      hex.EncodeToString(reverseBytes(txid)),
+		CoinbaseValueSats: detail.TotalCoinbaseValue,
+		Worker:            mc.minerName(worker),
+		Outputs:           make([]blockRewardOutput, 0, len(detail.CoinbaseOutputs)),
+	}
+	for i, o := range detail.CoinbaseOutputs {
+		script, _ := hex.DecodeString(o.ScriptHex)
+		role := rewardRoleOther
+		switch {
+		case len(script) > 0 && script[0] == 0x6a && o.ValueSats == 0:
+			role = rewardRoleWitnessCommitment
+		case len(workerScript) > 0 && bytes.Equal(script, workerScript):
+			role = rewardRoleWorker
+		case len(poolScript) > 0 && bytes.Equal(script, poolScript), job != nil && job.paysPayoutSplit(script):
+			role = rewardRolePool
+		case len(donationScript) > 0 && bytes.Equal(script, donationScript):
+			role = rewardRoleDonation
+		}
+		dist.Outputs = append(dist.Outputs, blockRewardOutput{
+			Index:     i,
+			Role:      role,
+			ValueSats: o.ValueSats,
+			ScriptHex: o.ScriptHex,
+			Address:   o.Address,
+		})
+	}
+	return dist, nil
+}
+
+// foundBlockDetail is the full found_blocks_log record for one block,
+// including the reward distribution when it was recorded.
+type foundBlockDetail struct {
+	Timestamp          time.Time                `json:"timestamp"`
+	Height             int64                    `json:"height"`
+	Hash               string                   `json:"hash"`
+	Worker             string                   `json:"worker"`
+	ShareDiff          float64                  `json:"share_diff"`
+	CoinbaseValueSats  int64                    `json:"coinbase_value_sats"`
+	PoolFeeSats        int64                    `json:"pool_fee_sats"`
+	WorkerPayoutSats   int64                    `json:"worker_payout_sats"`
+	RewardDistribution *blockRewardDistribution `json:"reward_distribution,omitempty"`
+	AlsoFound          bool                     `json:"also_found,omitempty"`
+	AlsoFoundFor       string                   `json:"also_found_for,omitempty"`
+}
+
+// loadFoundBlockDetail returns the most recent found-block record at height,
+// optionally narrowed by block hash. Also-found records (blocks that were not
+// submitted) are only returned when asked for by hash.
+func loadFoundBlockDetail(height int64, hash string) (*foundBlockDetail, error) {
+	db := getSharedStateDB()
+	if db == nil {
+		return nil, fmt.Errorf("state database unavailable")
+	}
+	rows, err := db.Query("SELECT json FROM found_blocks_log ORDER BY id DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	hash = strings.TrimSpace(hash)
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			continue
+		}
+		var rec foundBlockDetail
+		if err := sonic.Unmarshal([]byte(strings.TrimSpace(line)), &rec); err != nil {
+			continue
+		}
+		if rec.Height != height {
+			continue
+		}
+		if hash != "" && !strings.EqualFold(rec.Hash, hash) {
+			continue
+		}
+		if hash == "" && rec.AlsoFound {
+			continue
+		}
+		return &rec, nil
+	}
+	return nil, rows.Err()
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestRewardDistributionMatchesSubmittedCoinbase(t *testing.T) {
+	dir := t.TempDir()
+	setupTestStateDB(t, dir)
+
+	_, poolScript := generateTestWallet(t)
+	_, donationScript := generateTestWallet(t)
+	worker, workerAddr, workerScript := generateTestWorker(t)
+
+	commitment, _ := hex.DecodeString("6a24aa21a9ed" + "00112233445566778899aabbccddeeff00112233445566778899aabbccddeeff")
+	cbTx, cbTxid, err := serializeTripleCoinbaseTxPredecoded(840000, []byte{1, 2, 3, 4}, []byte{5, 6, 7, 8}, 8,
+		poolScript, donationScript, workerScript, 312500000, 2, 10, 546, commitment, nil, nil, "audit", 1700000000)
+	if err != nil {
+		t.Fatalf("build coinbase: %v", err)
+	}
+	var block bytes.Buffer
+	block.Write(make([]byte, 80))
+	writeVarInt(&block, 1)
+	block.Write(cbTx)
+
+	job := &Job{
+		JobID:          "dist-job",
+		PayoutScript:   poolScript,
+		DonationScript: donationScript,
+		Template:       GetBlockTemplateResult{Height: 840000, CoinbaseValue: 312500000},
+	}
+	mc := &MinerConn{cfg: Config{DataDir: dir, RecordRewardDistribution: true}}
+	mc.setWorkerWallet(worker, workerAddr, workerScript)
+
+	dist, err := mc.rewardDistributionFromBlock(job, worker, hex.EncodeToString(block.Bytes()))
+	if err != nil {
+		t.Fatalf("rewardDistributionFromBlock: %v", err)
+	}
+	if want := hex.EncodeToString(reverseBytes(cbTxid)); dist.CoinbaseTxid != want {
+		t.Fatalf("coinbase txid=%s, want %s", dist.CoinbaseTxid, want)
+	}
+	if dist.CoinbaseValueSats != 312500000 {
+		t.Fatalf("coinbase value=%d, want 312500000", dist.CoinbaseValueSats)
+	}
+	roles := map[string]int64{}
+	for _, o := range dist.Outputs {
+		roles[o.Role] += o.ValueSats
+	}
+	if _, ok := roles[rewardRoleWitnessCommitment]; !ok {
+		t.Fatalf("witness commitment output not labeled: %+v", dist.Outputs)
+	}
+	if roles[rewardRolePool] <= 0 || roles[rewardRoleDonation] <= 0 || roles[rewardRoleWorker] <= 0 {
+		t.Fatalf("expected pool, donation and worker outputs, got %+v", roles)
+	}
+	if roles[rewardRolePool]+roles[rewardRoleDonation]+roles[rewardRoleWorker] != 312500000 {
+		t.Fatalf("labeled outputs do not add up to the reward: %+v", roles)
+	}
+
+	mc.logFoundBlock(job, worker, "blockhash-dist", 1.0, dist)
+	flushFoundBlockLogger(t)
+	detail, err := loadFoundBlockDetail(840000, "")
+	if err != nil || detail == nil {
+		t.Fatalf("loadFoundBlockDetail: detail=%v err=%v", detail, err)
+	}
+	if detail.RewardDistribution == nil || detail.RewardDistribution.CoinbaseTxid != dist.CoinbaseTxid {
+		t.Fatalf("stored distribution mismatch: %+v", detail.RewardDistribution)
+	}
+	if len(detail.RewardDistribution.Outputs) != len(dist.Outputs) {
+		t.Fatalf("stored %d outputs, want %d", len(detail.RewardDistribution.Outputs), len(dist.Outputs))
+	}
+}
+
+func TestCoinbaseTxFromBlockRejectsTruncated(t *testing.T) {
+	if _, _, err := coinbaseTxFromBlock(make([]byte, 82)); err == nil {
+		t.Fatalf("expected error for truncated block")
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// blockFoundDedupe decides which block-found events get announced. Several
+// paths can report the same block (a resubmitted share, a replayed pending
+// submission, a later confirmation); the block hash is the key, so a
+// different block at the same height, such as one found after a reorg, is
+// always a new event.
+type blockFoundDedupe struct {
+	mu       sync.Mutex
+	byHash   map[string]time.Time
+	byWorker map[string]time.Time
+}
+
+// allow reports whether the block hashHex found by worker should be
+// announced (pool) and whether the worker's subscribers should be pinged
+// (worker). A hash already announced within poolCooldown is suppressed
+// entirely. With a positive workerCooldown, pings for a worker that was
+// pinged within it are skipped, but the block itself is still announced.
+func (d *blockFoundDedupe) allow(worker, hashHex string, now time.Time, poolCooldown, workerCooldown time.Duration) (pool, ping bool) {
+	hashHex = strings.ToLower(strings.TrimSpace(hashHex))
+	if d == nil || hashHex == "" {
+		return true, true
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.byHash == nil {
+		d.byHash = make(map[string]time.Time)
+		d.byWorker = make(map[string]time.Time)
+	}
+	for h, at := range d.byHash {
+		if now.Sub(at) >= poolCooldown {
+			delete(d.byHash, h)
+		}
+	}
+	for w, at := range d.byWorker {
+		if now.Sub(at) >= workerCooldown {
+			delete(d.byWorker, w)
+		}
+	}
+
+	if _, seen := d.byHash[hashHex]; seen {
+		return false, false
+	}
+	if poolCooldown > 0 {
+		d.byHash[hashHex] = now
+	}
+	if _, seen := d.byWorker[worker]; seen {
+		return true, false
+	}
+	if workerCooldown > 0 && worker != "" {
+		d.byWorker[worker] = now
+	}
+	return true, true
+}
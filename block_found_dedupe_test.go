@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBlockFoundDedupeByHash(t *testing.T) {
+	var d blockFoundDedupe
+	now := time.Unix(1700000000, 0)
+	hour := time.Hour
+
+	if pool, ping := d.allow("w1", "AA11", now, hour, 0); !pool || !ping {
+		t.Fatalf("first report should announce and ping")
+	}
+	// The same block reported again by another path, in any case.
+	if pool, _ := d.allow("w1", "aa11", now.Add(time.Minute), hour, 0); pool {
+		t.Fatalf("repeat report of the same hash should be suppressed")
+	}
+	// A different block at the same height (e.g. after a reorg) is new.
+	if pool, ping := d.allow("w2", "bb22", now.Add(2*time.Minute), hour, 0); !pool || !ping {
+		t.Fatalf("distinct hash should announce")
+	}
+	// Once the cooldown has passed the hash is forgotten.
+	if pool, _ := d.allow("w1", "aa11", now.Add(2*hour), hour, 0); !pool {
+		t.Fatalf("hash should be announced again after the cooldown")
+	}
+}
+
+func TestBlockFoundDedupeWorkerCooldown(t *testing.T) {
+	var d blockFoundDedupe
+	now := time.Unix(1700000000, 0)
+
+	if pool, ping := d.allow("w1", "aa11", now, time.Hour, 10*time.Minute); !pool || !ping {
+		t.Fatalf("first block should announce and ping")
+	}
+	if pool, ping := d.allow("w1", "bb22", now.Add(time.Minute), time.Hour, 10*time.Minute); !pool || ping {
+		t.Fatalf("second block inside the worker cooldown should announce without ping, got pool=%v ping=%v", pool, ping)
+	}
+	if pool, ping := d.allow("w2", "cc33", now.Add(time.Minute), time.Hour, 10*time.Minute); !pool || !ping {
+		t.Fatalf("other workers are not affected by the worker cooldown")
+	}
+	if _, ping := d.allow("w1", "dd44", now.Add(11*time.Minute), time.Hour, 10*time.Minute); !ping {
+		t.Fatalf("worker should be pinged again after the cooldown")
+	}
+}
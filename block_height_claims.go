@@ -0,0 +1,94 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// blockClaimWaitTimeout bounds how long a second finder waits for the
+	// first submission at the same height before submitting anyway.
+	blockClaimWaitTimeout = 10 * time.Second
+	// blockClaimKeepHeights is how many heights below the newest claim are
+	// remembered.
+	blockClaimKeepHeights = 16
+)
+
+// blockHeightClaim is the block being submitted (or already accepted) for one
+// height.
+type blockHeightClaim struct {
+	hash     string
+	done     chan struct{}
+	accepted bool
+}
+
+// blockHeightClaims lets only the first valid block per height be submitted
+// when dedupe_block_submissions is on. Two connections can solve the same
+// height at nearly the same instant with different extranonce/nonce; the
+// second block would only compete with our own.
+type blockHeightClaims struct {
+	mu     sync.Mutex
+	claims map[int64]*blockHeightClaim
+}
+
+// acquire claims height for hash. It returns first=true when the caller
+// should submit, in which case it must call release with the outcome. When
+// another block for the height was already accepted, it returns first=false
+// and that block's hash. A claim still being submitted is waited on for up
+// to timeout; if that submission fails the caller takes over the claim, and
+// if it is still pending after timeout the caller submits without a claim.
+func (c *blockHeightClaims) acquire(height int64, hash string, timeout time.Duration) (first bool, winner string) {
+	deadline := time.Now().Add(timeout)
+	for {
+		c.mu.Lock()
+		if c.claims == nil {
+			c.claims = make(map[int64]*blockHeightClaim)
+		}
+		claim, ok := c.claims[height]
+		if !ok {
+			c.claims[height] = &blockHeightClaim{hash: hash, done: make(chan struct{})}
+			for h := range c.claims {
+				if h < height-blockClaimKeepHeights {
+					delete(c.claims, h)
+				}
+			}
+			c.mu.Unlock()
+			return true, ""
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-claim.done:
+		case <-time.After(time.Until(deadline)):
+			return true, ""
+		}
+		c.mu.Lock()
+		accepted := claim.accepted
+		c.mu.Unlock()
+		if accepted {
+			return false, claim.hash
+		}
+		// The first submission failed and released its claim; try again.
+	}
+}
+
+// release records the outcome of a submission started by acquire. A failed
+// submission frees the height for the next finder.
+func (c *blockHeightClaims) release(height int64, hash string, accepted bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	claim, ok := c.claims[height]
+	if !ok || claim.hash != hash {
+		return
+	}
+	select {
+	case <-claim.done:
+		return
+	default:
+	}
+	claim.accepted = accepted
+	if !accepted {
+		delete(c.claims, height)
+	}
+	close(claim.done)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBlockHeightClaimsFirstWins(t *testing.T) {
+	var c blockHeightClaims
+	if first, _ := c.acquire(100, "aa", time.Second); !first {
+		t.Fatalf("expected first claim to win")
+	}
+
+	done := make(chan string, 1)
+	go func() {
+		first, winner := c.acquire(100, "bb", 5*time.Second)
+		if first {
+			winner = ""
+		}
+		done <- winner
+	}()
+	// The second finder waits while the first block is being submitted.
+	select {
+	case <-done:
+		t.Fatalf("second finder should wait for the first submission")
+	case <-time.After(50 * time.Millisecond):
+	}
+	c.release(100, "aa", true)
+	if winner := <-done; winner != "aa" {
+		t.Fatalf("expected second finder to see winner aa, got %q", winner)
+	}
+
+	// Other heights are independent.
+	if first, _ := c.acquire(101, "cc", time.Second); !first {
+		t.Fatalf("expected claim at a new height to win")
+	}
+}
+
+func TestBlockHeightClaimsFailedSubmissionHandsOver(t *testing.T) {
+	var c blockHeightClaims
+	c.acquire(200, "aa", time.Second)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		c.release(200, "aa", false)
+	}()
+	if first, _ := c.acquire(200, "bb", 5*time.Second); !first {
+		t.Fatalf("expected second finder to take over after a failed submission")
+	}
+	c.release(200, "bb", true)
+	if first, winner := c.acquire(200, "cc", time.Second); first || winner != "bb" {
+		t.Fatalf("expected bb to hold height 200, got first=%v winner=%q", first, winner)
+	}
+}
+
+func TestBlockHeightClaimsConcurrentFinders(t *testing.T) {
+	var c blockHeightClaims
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	firsts := 0
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			hash := string(rune('a' + i))
+			if first, _ := c.acquire(300, hash, 5*time.Second); first {
+				mu.Lock()
+				firsts++
+				mu.Unlock()
+				c.release(300, hash, true)
+			}
+		}()
+	}
+	wg.Wait()
+	if firsts != 1 {
+		t.Fatalf("expected exactly one submission at a height, got %d", firsts)
+	}
+}
+
+func TestBlockHeightClaimsTimeoutSubmitsAnyway(t *testing.T) {
+	var c blockHeightClaims
+	c.acquire(400, "aa", time.Second)
+	if first, _ := c.acquire(400, "bb", 10*time.Millisecond); !first {
+		t.Fatalf("expected a stuck claim to time out and let the caller submit")
+	}
+}
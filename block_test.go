@@ -174,7 +174,7 @@ func TestDualVsSinglePayoutBlocks(t *testing.T) {
 	totalValue := job.Template.CoinbaseValue
 	feePercent := 2.0
 
-	cbTx, cbTxid, err := serializeDualCoinbaseTx(job.Template.Height, ex1, ex2, job.TemplateExtraNonce2Size, poolScript, workerScript, totalValue, feePercent, job.WitnessCommitment, job.Template.CoinbaseAux.Flags, job.CoinbaseMsg, job.ScriptTime)
+	cbTx, cbTxid, err := serializeDualCoinbaseTx(job.Template.Height, ex1, ex2, job.TemplateExtraNonce2Size, poolScript, workerScript, totalValue, feePercent, job.WitnessCommitment, job.extraOpReturnScript, job.Template.CoinbaseAux.Flags, job.CoinbaseMsg, job.ScriptTime)
 	if err != nil {
 		t.Fatalf("serializeDualCoinbaseTx error: %v", err)
 	}
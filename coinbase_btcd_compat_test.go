@@ -57,7 +57,7 @@ func TestCoinbase_EncodeDecode_AgainstBtcd(t *testing.T) {
 	t.Run("single", func(t *testing.T) {
 		payoutScript := []byte{0x51} // OP_TRUE
 		coinbaseValue := int64(50 * 1e8)
-		raw, txid, err := serializeCoinbaseTx(height, ex1, ex2, templateExtraNonce2Size, payoutScript, coinbaseValue, witnessCommitment, coinbaseFlags, coinbaseMsg, scriptTime)
+		raw, txid, err := serializeCoinbaseTx(height, ex1, ex2, templateExtraNonce2Size, payoutScript, coinbaseValue, witnessCommitment, nil, coinbaseFlags, coinbaseMsg, scriptTime)
 		if err != nil {
 			t.Fatalf("serializeCoinbaseTx: %v", err)
 		}
@@ -69,7 +69,7 @@ func TestCoinbase_EncodeDecode_AgainstBtcd(t *testing.T) {
 		workerScript := []byte{0x52}
 		totalValue := int64(50 * 1e8)
 		feePercent := 2.0
-		raw, txid, err := serializeDualCoinbaseTx(height, ex1, ex2, templateExtraNonce2Size, poolScript, workerScript, totalValue, feePercent, witnessCommitment, coinbaseFlags, coinbaseMsg, scriptTime)
+		raw, txid, err := serializeDualCoinbaseTx(height, ex1, ex2, templateExtraNonce2Size, poolScript, workerScript, totalValue, feePercent, witnessCommitment, nil, coinbaseFlags, coinbaseMsg, scriptTime)
 		if err != nil {
 			t.Fatalf("serializeDualCoinbaseTx: %v", err)
 		}
@@ -83,7 +83,7 @@ func TestCoinbase_EncodeDecode_AgainstBtcd(t *testing.T) {
 		totalValue := int64(50 * 1e8)
 		poolFeePercent := 2.0
 		donationFeePercent := 12.5
-		raw, txid, err := serializeTripleCoinbaseTx(height, ex1, ex2, templateExtraNonce2Size, poolScript, donationScript, workerScript, totalValue, poolFeePercent, donationFeePercent, witnessCommitment, coinbaseFlags, coinbaseMsg, scriptTime)
+		raw, txid, err := serializeTripleCoinbaseTx(height, ex1, ex2, templateExtraNonce2Size, poolScript, donationScript, workerScript, totalValue, poolFeePercent, donationFeePercent, witnessCommitment, nil, coinbaseFlags, coinbaseMsg, scriptTime)
 		if err != nil {
 			t.Fatalf("serializeTripleCoinbaseTx: %v", err)
 		}
@@ -98,7 +98,7 @@ func TestCoinbase_EncodeDecode_AgainstBtcd(t *testing.T) {
 			{Script: []byte{0x52}, Value: 2},
 			{Script: []byte{0x53}, Value: 3},
 		}
-		raw, txid, err := serializeCoinbaseTxPayoutsPredecoded(height, ex1, ex2, templateExtraNonce2Size, payouts, commitmentScript, flagsBytes, coinbaseMsg, scriptTime)
+		raw, txid, err := serializeCoinbaseTxPayoutsPredecoded(height, ex1, ex2, templateExtraNonce2Size, payouts, commitmentScript, nil, flagsBytes, coinbaseMsg, scriptTime)
 		if err != nil {
 			t.Fatalf("serializeCoinbaseTxPayoutsPredecoded: %v", err)
 		}
@@ -123,7 +123,7 @@ func TestCoinbaseParts_ReconstructsExactTx_Btcd(t *testing.T) {
 		{Script: []byte{0x53}, Value: 3},
 	}
 
-	coinb1, coinb2, err := buildCoinbasePartsPayouts(height, ex1, extranonce2Size, templateExtraNonce2Size, payouts, witnessCommitment, coinbaseFlags, coinbaseMsg, scriptTime)
+	coinb1, coinb2, err := buildCoinbasePartsPayouts(height, ex1, extranonce2Size, templateExtraNonce2Size, payouts, witnessCommitment, nil, coinbaseFlags, coinbaseMsg, scriptTime)
 	if err != nil {
 		t.Fatalf("buildCoinbasePartsPayouts: %v", err)
 	}
@@ -132,7 +132,7 @@ func TestCoinbaseParts_ReconstructsExactTx_Btcd(t *testing.T) {
 
 	flagsBytes := mustDecodeHex(t, coinbaseFlags)
 	commitmentScript := mustDecodeHex(t, witnessCommitment)
-	rawDirect, txidDirect, err := serializeCoinbaseTxPayoutsPredecoded(height, ex1, ex2, templateExtraNonce2Size, payouts, commitmentScript, flagsBytes, coinbaseMsg, scriptTime)
+	rawDirect, txidDirect, err := serializeCoinbaseTxPayoutsPredecoded(height, ex1, ex2, templateExtraNonce2Size, payouts, commitmentScript, nil, flagsBytes, coinbaseMsg, scriptTime)
 	if err != nil {
 		t.Fatalf("serializeCoinbaseTxPayoutsPredecoded: %v", err)
 	}
@@ -159,7 +159,7 @@ func TestCoinbaseParts_Lengths_SparkMinerCompatibility(t *testing.T) {
 	donationScript := mustDecodeHex(t, "0014331a0a25095d90d338e93dab611d8f8c3584fa57")
 
 	t.Run("single", func(t *testing.T) {
-		coinb1, coinb2, err := buildCoinbaseParts(height, ex1, extranonce2Size, templateExtraNonce2Size, workerScript, 50*1e8, witnessCommitment, coinbaseFlags, coinbaseMsg, scriptTime)
+		coinb1, coinb2, err := buildCoinbaseParts(height, ex1, extranonce2Size, templateExtraNonce2Size, workerScript, 50*1e8, witnessCommitment, nil, coinbaseFlags, coinbaseMsg, scriptTime)
 		if err != nil {
 			t.Fatalf("buildCoinbaseParts: %v", err)
 		}
@@ -170,7 +170,7 @@ func TestCoinbaseParts_Lengths_SparkMinerCompatibility(t *testing.T) {
 	})
 
 	t.Run("dual", func(t *testing.T) {
-		coinb1, coinb2, err := buildDualPayoutCoinbaseParts(height, ex1, extranonce2Size, templateExtraNonce2Size, poolScript, workerScript, 50*1e8, 2.0, witnessCommitment, coinbaseFlags, coinbaseMsg, scriptTime)
+		coinb1, coinb2, err := buildDualPayoutCoinbaseParts(height, ex1, extranonce2Size, templateExtraNonce2Size, poolScript, workerScript, 50*1e8, 2.0, 0, witnessCommitment, nil, coinbaseFlags, coinbaseMsg, scriptTime)
 		if err != nil {
 			t.Fatalf("buildDualPayoutCoinbaseParts: %v", err)
 		}
@@ -184,7 +184,7 @@ func TestCoinbaseParts_Lengths_SparkMinerCompatibility(t *testing.T) {
 	})
 
 	t.Run("triple", func(t *testing.T) {
-		coinb1, coinb2, err := buildTriplePayoutCoinbaseParts(height, ex1, extranonce2Size, templateExtraNonce2Size, poolScript, donationScript, workerScript, 50*1e8, 2.0, 12.5, witnessCommitment, coinbaseFlags, coinbaseMsg, scriptTime)
+		coinb1, coinb2, err := buildTriplePayoutCoinbaseParts(height, ex1, extranonce2Size, templateExtraNonce2Size, poolScript, donationScript, workerScript, 50*1e8, 2.0, 12.5, 0, witnessCommitment, nil, coinbaseFlags, coinbaseMsg, scriptTime)
 		if err != nil {
 			t.Fatalf("buildTriplePayoutCoinbaseParts: %v", err)
 		}
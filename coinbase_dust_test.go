@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func sumPayouts(payouts []coinbasePayoutOutput) int64 {
+	var total int64
+	for _, p := range payouts {
+		total += p.Value
+	}
+	return total
+}
+
+func TestComputeCoinbasePayouts_DustFoldingBoundary(t *testing.T) {
+	poolScript := []byte{0x51}
+	donationScript := []byte{0x52}
+	workerScript := []byte{0x53}
+	const dust = 546
+
+	// 1% pool fee of 100000 = 1000 sats; donation percent is applied to that.
+	tests := []struct {
+		name         string
+		donationPct  float64
+		wantDonation int64 // 0 means folded away
+		wantPool     int64
+	}{
+		{name: "donation one below dust folds into pool", donationPct: 54.5, wantDonation: 0, wantPool: 1000},
+		{name: "donation exactly at dust is kept", donationPct: 54.6, wantDonation: 546, wantPool: 454},
+		{name: "donation above dust is kept", donationPct: 60, wantDonation: 600, wantPool: 400},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			plan := coinbasePayoutPlan{
+				TotalValue:      100000,
+				RemainderScript: workerScript,
+				FeeSlices: []coinbaseFeeSlice{{
+					Script:    poolScript,
+					Percent:   1,
+					SubSlices: []coinbaseFeeSubSlice{{Script: donationScript, Percent: tc.donationPct}},
+				}},
+				RequireRemainderPositive: true,
+				DustThreshold:            dust,
+			}
+			payouts, _, err := computeCoinbasePayouts(plan)
+			if err != nil {
+				t.Fatalf("computeCoinbasePayouts: %v", err)
+			}
+			if got := sumPayouts(payouts); got != plan.TotalValue {
+				t.Fatalf("total not preserved: got %d want %d", got, plan.TotalValue)
+			}
+			var gotPool, gotDonation, gotWorker int64
+			for _, p := range payouts {
+				if p.Value < dust {
+					t.Fatalf("output below dust: %+v", p)
+				}
+				switch {
+				case bytes.Equal(p.Script, poolScript):
+					gotPool = p.Value
+				case bytes.Equal(p.Script, donationScript):
+					gotDonation = p.Value
+				case bytes.Equal(p.Script, workerScript):
+					gotWorker = p.Value
+				}
+			}
+			if gotDonation != tc.wantDonation {
+				t.Fatalf("donation=%d want %d", gotDonation, tc.wantDonation)
+			}
+			// A pool output below dust after the donation split folds into the
+			// worker output rather than being paid as dust.
+			wantPool := tc.wantPool
+			if wantPool < dust {
+				wantPool = 0
+			}
+			if gotPool != wantPool {
+				t.Fatalf("pool=%d want %d", gotPool, wantPool)
+			}
+			if gotWorker != plan.TotalValue-gotPool-gotDonation {
+				t.Fatalf("worker=%d does not absorb the remainder", gotWorker)
+			}
+		})
+	}
+}
+
+func TestComputeCoinbasePayouts_PoolFeeBelowDustFoldsIntoWorker(t *testing.T) {
+	plan := coinbasePayoutPlan{
+		TotalValue:               50000,
+		RemainderScript:          []byte{0x53},
+		FeeSlices:                []coinbaseFeeSlice{{Script: []byte{0x51}, Percent: 1}}, // 500 sats
+		RequireRemainderPositive: true,
+		DustThreshold:            546,
+	}
+	payouts, breakdown, err := computeCoinbasePayouts(plan)
+	if err != nil {
+		t.Fatalf("computeCoinbasePayouts: %v", err)
+	}
+	if len(payouts) != 1 || payouts[0].Value != 50000 {
+		t.Fatalf("expected a single 50000 sat worker output, got %+v", payouts)
+	}
+	if breakdown.FeeSlices[0].ParentValue != 0 || breakdown.RemainderValue != 50000 {
+		t.Fatalf("breakdown not folded: %+v", breakdown)
+	}
+
+	// At exactly the threshold the fee output stays.
+	plan.TotalValue = 54600
+	payouts, _, err = computeCoinbasePayouts(plan)
+	if err != nil {
+		t.Fatalf("computeCoinbasePayouts: %v", err)
+	}
+	if len(payouts) != 2 || sumPayouts(payouts) != 54600 {
+		t.Fatalf("expected pool+worker outputs summing to 54600, got %+v", payouts)
+	}
+}
+
+func TestComputeCoinbasePayouts_RemainderBelowDustFails(t *testing.T) {
+	plan := coinbasePayoutPlan{
+		TotalValue:               10000,
+		RemainderScript:          []byte{0x53},
+		FeeSlices:                []coinbaseFeeSlice{{Script: []byte{0x51}, Percent: 99.99}},
+		RequireRemainderPositive: true,
+		DustThreshold:            546,
+	}
+	if _, _, err := computeCoinbasePayouts(plan); err == nil {
+		t.Fatalf("expected error for worker output below dust")
+	}
+}
+
+func TestDualAndTripleCoinbaseFoldDust(t *testing.T) {
+	poolScript := []byte{0x51}
+	donationScript := []byte{0x52}
+	workerScript := []byte{0x53}
+
+	tx, _, err := serializeTripleCoinbaseTxPredecoded(100, []byte{1, 2, 3, 4}, []byte{5, 6, 7, 8}, 8, poolScript, donationScript, workerScript, 100000, 1, 10, 546, nil, nil, nil, "", 0)
+	if err != nil {
+		t.Fatalf("triple: %v", err)
+	}
+	// 1000 sat fee, 100 sat donation folds into the pool -> two payout outputs.
+	if bytes.Contains(tx, append([]byte{0x01}, donationScript...)) {
+		t.Fatalf("donation output should have been folded away")
+	}
+	dual, _, err := serializeDualCoinbaseTxPredecoded(100, []byte{1, 2, 3, 4}, []byte{5, 6, 7, 8}, 8, poolScript, workerScript, 50000, 1, 546, nil, nil, nil, "", 0)
+	if err != nil {
+		t.Fatalf("dual: %v", err)
+	}
+	if bytes.Contains(dual, append([]byte{0x01}, poolScript...)) {
+		t.Fatalf("pool fee output below dust should have been folded into the worker output")
+	}
+}
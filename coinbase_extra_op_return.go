@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+)
+
+// maxCoinbaseExtraOpReturnBytes is the data carrier limit bitcoind relays by
+// default (-datacarriersize 83 bytes of script: OP_RETURN, a push opcode
+// and up to 80 bytes of data).
+const maxCoinbaseExtraOpReturnBytes = 80
+
+// witnessCommitmentHeader prefixes the BIP141 witness commitment data. An
+// extra OP_RETURN carrying it would be taken as the commitment, since the
+// highest-index match wins.
+var witnessCommitmentHeader = []byte{0xaa, 0x21, 0xa9, 0xed}
+
+// coinbaseExtraOpReturnScript builds the OP_RETURN script for the
+// coinbase_extra_op_return setting (hex data). It returns nil when the
+// setting is empty.
+func coinbaseExtraOpReturnScript(dataHex string) ([]byte, error) {
+	if dataHex == "" {
+		return nil, nil
+	}
+	data, err := hex.DecodeString(dataHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	if len(data) > maxCoinbaseExtraOpReturnBytes {
+		return nil, fmt.Errorf("%d bytes exceeds the %d byte OP_RETURN standardness limit", len(data), maxCoinbaseExtraOpReturnBytes)
+	}
+	if bytes.HasPrefix(data, witnessCommitmentHeader) {
+		return nil, fmt.Errorf("data must not start with the witness commitment header %x", witnessCommitmentHeader)
+	}
+	script := make([]byte, 0, len(data)+3)
+	script = append(script, 0x6a) // OP_RETURN
+	if len(data) >= 0x4c {
+		script = append(script, 0x4c) // OP_PUSHDATA1
+	}
+	script = append(script, byte(len(data)))
+	return append(script, data...), nil
+}
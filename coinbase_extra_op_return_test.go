@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+func TestCoinbaseExtraOpReturnScript(t *testing.T) {
+	if script, err := coinbaseExtraOpReturnScript(""); err != nil || script != nil {
+		t.Fatalf("empty setting: script=%x err=%v", script, err)
+	}
+
+	script, err := coinbaseExtraOpReturnScript("cafe")
+	if err != nil {
+		t.Fatalf("short data: %v", err)
+	}
+	if want := []byte{0x6a, 0x02, 0xca, 0xfe}; !bytes.Equal(script, want) {
+		t.Fatalf("short data script = %x, want %x", script, want)
+	}
+
+	max := strings.Repeat("ab", maxCoinbaseExtraOpReturnBytes)
+	script, err = coinbaseExtraOpReturnScript(max)
+	if err != nil {
+		t.Fatalf("80-byte data: %v", err)
+	}
+	if len(script) != 83 || script[0] != 0x6a || script[1] != 0x4c || script[2] != maxCoinbaseExtraOpReturnBytes {
+		t.Fatalf("80-byte data script prefix = %x, len %d", script[:3], len(script))
+	}
+
+	for name, in := range map[string]string{
+		"too long":   max + "ab",
+		"bad hex":    "zz",
+		"odd length": "abc",
+		"commitment": "aa21a9ed00",
+	} {
+		if _, err := coinbaseExtraOpReturnScript(in); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+func TestValidateConfigRejectsOversizedCoinbaseExtraOpReturn(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.AllowPublicRPC = true
+	cfg.PayoutAddress = "1Pool"
+	cfg.CoinbaseExtraOpReturn = strings.Repeat("00", maxCoinbaseExtraOpReturnBytes+1)
+	if err := validateConfig(cfg); err == nil || !strings.Contains(err.Error(), "coinbase_extra_op_return") {
+		t.Fatalf("expected coinbase_extra_op_return error, got %v", err)
+	}
+}
+
+func TestCoinbaseExtraOpReturnOutputPlacement(t *testing.T) {
+	height := int64(789)
+	ex1 := []byte{0x01, 0x02, 0x03, 0x04}
+	ex2 := []byte{0xaa, 0xbb, 0xcc, 0xdd}
+	witnessCommitment := "6a24aa21a9ed" + strings.Repeat("11", 32)
+	commitmentScript := mustDecodeHex(t, witnessCommitment)
+	extra, err := coinbaseExtraOpReturnScript("0102030405")
+	if err != nil {
+		t.Fatalf("extra script: %v", err)
+	}
+	payouts := []coinbasePayoutOutput{
+		{Script: []byte{0x51}, Value: 1000},
+		{Script: []byte{0x52}, Value: 2000},
+	}
+
+	coinb1, coinb2, err := buildCoinbasePartsPayouts(height, ex1, len(ex2), 8, payouts, witnessCommitment, extra, "", "extra-op-return", 0)
+	if err != nil {
+		t.Fatalf("buildCoinbasePartsPayouts: %v", err)
+	}
+	raw, txid, err := serializeCoinbaseTxPayoutsPredecoded(height, ex1, ex2, 8, payouts, commitmentScript, extra, nil, "extra-op-return", 0)
+	if err != nil {
+		t.Fatalf("serializeCoinbaseTxPayoutsPredecoded: %v", err)
+	}
+	if fromParts := mustDecodeHex(t, coinb1+hex.EncodeToString(ex1)+hex.EncodeToString(ex2)+coinb2); !bytes.Equal(fromParts, raw) {
+		t.Fatalf("coinb1/coinb2 reconstruction mismatch vs direct serialization")
+	}
+	assertCoinbaseEncodesAndDecodesWithBtcd(t, raw, txid)
+
+	var tx wire.MsgTx
+	if err := tx.Deserialize(bytes.NewReader(raw)); err != nil {
+		t.Fatalf("deserialize: %v", err)
+	}
+	if len(tx.TxOut) != 4 {
+		t.Fatalf("expected 4 outputs, got %d", len(tx.TxOut))
+	}
+	if !bytes.Equal(tx.TxOut[0].PkScript, commitmentScript) {
+		t.Fatalf("witness commitment is not the first output")
+	}
+	last := tx.TxOut[len(tx.TxOut)-1]
+	if last.Value != 0 || !bytes.Equal(last.PkScript, extra) {
+		t.Fatalf("extra OP_RETURN output = %d %x, want 0 %x", last.Value, last.PkScript, extra)
+	}
+
+	single, _, err := serializeCoinbaseTxPredecoded(height, ex1, ex2, 8, []byte{0x51}, 3000, nil, extra, nil, "extra-op-return", 0)
+	if err != nil {
+		t.Fatalf("serializeCoinbaseTxPredecoded: %v", err)
+	}
+	if !bytes.Contains(single, extra) {
+		t.Fatalf("single-output coinbase is missing the extra OP_RETURN output")
+	}
+}
@@ -68,6 +68,7 @@ func FuzzCoinbasePayoutSerializationRoundTrip(f *testing.F) {
 			payouts,
 			commitment,
 			nil,
+			nil,
 			"fuzz-payouts",
 			0,
 		)
@@ -15,7 +15,7 @@ func TestSerializeCoinbaseTxPayoutsPredecoded_ManyOutputs(t *testing.T) {
 		{Script: []byte{0x54}, Value: 4},
 	}
 
-	raw, _, err := serializeCoinbaseTxPayoutsPredecoded(height, ex1, ex2, templateExtra, payouts, nil, nil, "test", 0)
+	raw, _, err := serializeCoinbaseTxPayoutsPredecoded(height, ex1, ex2, templateExtra, payouts, nil, nil, nil, "test", 0)
 	if err != nil {
 		t.Fatalf("serializeCoinbaseTxPayoutsPredecoded error: %v", err)
 	}
@@ -58,7 +58,7 @@ func TestSerializeCoinbaseTxPayoutsPredecoded_CommitmentPlusPayouts(t *testing.T
 	}
 	commitmentScript := []byte{0x6a, 0x01, 0x00} // minimal OP_RETURN-ish placeholder
 
-	raw, _, err := serializeCoinbaseTxPayoutsPredecoded(height, ex1, ex2, templateExtra, payouts, commitmentScript, nil, "test", 0)
+	raw, _, err := serializeCoinbaseTxPayoutsPredecoded(height, ex1, ex2, templateExtra, payouts, commitmentScript, nil, nil, "test", 0)
 	if err != nil {
 		t.Fatalf("serializeCoinbaseTxPayoutsPredecoded error: %v", err)
 	}
@@ -84,3 +84,108 @@ func TestSerializeCoinbaseTxPayoutsPredecoded_CommitmentPlusPayouts(t *testing.T
 		t.Fatalf("voutCount=%d, want %d", voutCount, len(payouts)+1)
 	}
 }
+
+func TestCapCoinbasePayoutOutputsFoldsSmallest(t *testing.T) {
+	payouts := []coinbasePayoutOutput{
+		{Script: []byte{0x51}, Value: 50},
+		{Script: []byte{0x52}, Value: 5},
+		{Script: []byte{0x53}, Value: 30},
+		{Script: []byte{0x54}, Value: 1},
+		{Script: []byte{0x55}, Value: 1000}, // remainder
+	}
+	capped, folded, _ := capCoinbasePayoutOutputs(payouts, 3)
+	if len(capped) != 3 {
+		t.Fatalf("len=%d, want 3", len(capped))
+	}
+	if folded != 6 {
+		t.Fatalf("folded=%d, want 6", folded)
+	}
+	if capped[0].Value != 50 || capped[1].Value != 30 {
+		t.Fatalf("expected the two largest fee outputs to survive, got %+v", capped)
+	}
+	last := capped[len(capped)-1]
+	if last.Script[0] != 0x55 || last.Value != 1006 {
+		t.Fatalf("remainder=%+v, want script 0x55 value 1006", last)
+	}
+	var before, after int64
+	for _, o := range payouts {
+		before += o.Value
+	}
+	for _, o := range capped {
+		after += o.Value
+	}
+	if before != after {
+		t.Fatalf("total changed: %d -> %d", before, after)
+	}
+}
+
+func TestComputeCoinbasePayoutsCapKeepsBreakdownConsistent(t *testing.T) {
+	subs := make([]coinbaseFeeSubSlice, 40)
+	for i := range subs {
+		subs[i] = coinbaseFeeSubSlice{Script: []byte{0x60, byte(i)}, Percent: float64(i+1) * 0.05}
+	}
+	plan := coinbasePayoutPlan{
+		TotalValue:      312_500_000,
+		RemainderScript: []byte{0x51},
+		FeeSlices:       []coinbaseFeeSlice{{Script: []byte{0x52}, Percent: 10, SubSlices: subs}},
+	}
+	payouts, breakdown, err := computeCoinbasePayouts(plan)
+	if err != nil {
+		t.Fatalf("computeCoinbasePayouts: %v", err)
+	}
+	if len(payouts) != maxCoinbasePayoutOutputs {
+		t.Fatalf("outputs=%d, want %d", len(payouts), maxCoinbasePayoutOutputs)
+	}
+
+	var outputsTotal int64
+	for _, o := range payouts {
+		outputsTotal += o.Value
+	}
+	breakdownTotal := breakdown.RemainderValue
+	nonZero := 1
+	for _, sb := range breakdown.FeeSlices {
+		breakdownTotal += sb.ParentValue
+		if sb.ParentValue > 0 {
+			nonZero++
+		}
+		for _, v := range sb.SubValues {
+			breakdownTotal += v
+			if v > 0 {
+				nonZero++
+			}
+		}
+	}
+	if outputsTotal != plan.TotalValue || breakdownTotal != plan.TotalValue {
+		t.Fatalf("outputs sum to %d and breakdown to %d, want %d", outputsTotal, breakdownTotal, plan.TotalValue)
+	}
+	if nonZero != len(payouts) {
+		t.Fatalf("breakdown has %d non-zero entries, want one per output (%d)", nonZero, len(payouts))
+	}
+	if last := payouts[len(payouts)-1]; last.Value != breakdown.RemainderValue {
+		t.Fatalf("remainder output %d != breakdown remainder %d", last.Value, breakdown.RemainderValue)
+	}
+}
+
+func TestCoinbaseMaxBytesGuard(t *testing.T) {
+	t.Cleanup(func() { setCoinbaseMaxBytes(0) })
+	payouts := []coinbasePayoutOutput{{Script: make([]byte, 34), Value: 1}}
+
+	setCoinbaseMaxBytes(0)
+	raw, _, err := serializeCoinbaseTxPayoutsPredecoded(1, []byte{1, 2, 3, 4}, []byte{5, 6, 7, 8}, 8, payouts, nil, nil, nil, "test", 0)
+	if err != nil {
+		t.Fatalf("default limit rejected small coinbase: %v", err)
+	}
+
+	setCoinbaseMaxBytes(len(raw) - 1)
+	if _, _, err := serializeCoinbaseTxPayoutsPredecoded(1, []byte{1, 2, 3, 4}, []byte{5, 6, 7, 8}, 8, payouts, nil, nil, nil, "test", 0); err == nil {
+		t.Fatalf("expected serialized coinbase over the limit to fail")
+	}
+	if _, _, err := buildCoinbasePartsPayouts(1, []byte{1, 2, 3, 4}, 4, 8, payouts, "", nil, "", "test", 0); err == nil {
+		t.Fatalf("expected coinbase parts over the limit to fail")
+	}
+
+	setCoinbaseMaxBytes(len(raw))
+	if _, _, err := buildCoinbasePartsPayouts(1, []byte{1, 2, 3, 4}, 4, 8, payouts, "", nil, "", "test", 0); err != nil {
+		t.Fatalf("coinbase parts at the limit rejected: %v", err)
+	}
+}
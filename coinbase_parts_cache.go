@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/binary"
+	"sync"
+)
+
+// maxCoinbaseTailCacheEntries bounds one job's cache. Pools with many
+// per-worker wallets stop caching new payout sets past this and build them
+// per notify as before.
+const maxCoinbaseTailCacheEntries = 4096
+
+// coinbaseTail is a serialized coinb2 and its byte length.
+type coinbaseTail struct {
+	hex  string
+	size int
+}
+
+// coinbaseTailCache holds one job's coinb2 values keyed by payout outputs
+// (tuning [mining] coinbase_parts_cache). Everything else in coinb2 (tag,
+// witness commitment) is fixed for the job, so connections paying to the
+// same scripts and values get the same coinb2, while per-worker wallets and
+// fee splits land on distinct keys.
+type coinbaseTailCache struct {
+	mu    sync.Mutex
+	tails map[string]coinbaseTail
+}
+
+func newCoinbaseTailCache() *coinbaseTailCache {
+	return &coinbaseTailCache{tails: make(map[string]coinbaseTail)}
+}
+
+func coinbaseTailKey(payouts []coinbasePayoutOutput) string {
+	n := 0
+	for _, p := range payouts {
+		n += 8 + binary.MaxVarintLen64 + len(p.Script)
+	}
+	key := make([]byte, 0, n)
+	for _, p := range payouts {
+		key = binary.LittleEndian.AppendUint64(key, uint64(p.Value))
+		key = binary.AppendUvarint(key, uint64(len(p.Script)))
+		key = append(key, p.Script...)
+	}
+	return string(key)
+}
+
+// get returns the cached coinb2 for payouts, calling build on a miss. A nil
+// cache always builds. Build errors are not cached.
+func (c *coinbaseTailCache) get(payouts []coinbasePayoutOutput, build func() (coinbaseTail, error)) (coinbaseTail, error) {
+	if c == nil {
+		return build()
+	}
+	key := coinbaseTailKey(payouts)
+	c.mu.Lock()
+	tail, ok := c.tails[key]
+	c.mu.Unlock()
+	if ok {
+		return tail, nil
+	}
+	tail, err := build()
+	if err != nil {
+		return coinbaseTail{}, err
+	}
+	c.mu.Lock()
+	if len(c.tails) < maxCoinbaseTailCacheEntries {
+		c.tails[key] = tail
+	}
+	c.mu.Unlock()
+	return tail, nil
+}
+
+// notifyCoinbaseParts builds coinb1/coinb2 for a notify of job paying to
+// payouts, reusing the job's coinb2 cache when enabled.
+func (job *Job) notifyCoinbaseParts(extranonce1 []byte, payouts []coinbasePayoutOutput, scriptTime int64) (string, string, error) {
+	return buildCoinbasePartsPayoutsCached(job.coinbaseTails, job.Template.Height, extranonce1, job.Extranonce2Size, job.TemplateExtraNonce2Size, payouts, job.WitnessCommitment, job.extraOpReturnScript, job.Template.CoinbaseAux.Flags, job.CoinbaseMsg, scriptTime)
+}
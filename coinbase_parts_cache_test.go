@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCoinbaseTailCacheMatchesUncachedParts(t *testing.T) {
+	job := benchmarkSubmitJobForTest(t)
+	job.coinbaseTails = newCoinbaseTailCache()
+
+	poolScript := append([]byte(nil), job.PayoutScript...)
+	workerScript := append([]byte{0x00, 0x14}, bytes.Repeat([]byte{0x42}, 20)...)
+	dual, err := dualCoinbasePayouts(poolScript, workerScript, job.CoinbaseValue, 2.0, 0)
+	if err != nil {
+		t.Fatalf("dual payouts: %v", err)
+	}
+	payoutSets := map[string][]coinbasePayoutOutput{
+		"pool":   {{Script: poolScript, Value: job.CoinbaseValue}},
+		"worker": {{Script: workerScript, Value: job.CoinbaseValue}},
+		"dual":   dual,
+	}
+
+	coinb2s := make(map[string]string)
+	for name, payouts := range payoutSets {
+		// Two connections with different extranonce1/scriptTime share coinb2
+		// but must still get their own coinb1.
+		for i, ex1 := range [][]byte{{1, 2, 3, 4}, {5, 6, 7, 8}} {
+			scriptTime := job.ScriptTime + int64(i+1)
+			gotB1, gotB2, err := job.notifyCoinbaseParts(ex1, payouts, scriptTime)
+			if err != nil {
+				t.Fatalf("%s: cached parts: %v", name, err)
+			}
+			wantB1, wantB2, err := buildCoinbasePartsPayouts(job.Template.Height, ex1, job.Extranonce2Size, job.TemplateExtraNonce2Size, payouts, job.WitnessCommitment, job.extraOpReturnScript, job.Template.CoinbaseAux.Flags, job.CoinbaseMsg, scriptTime)
+			if err != nil {
+				t.Fatalf("%s: uncached parts: %v", name, err)
+			}
+			if gotB1 != wantB1 || gotB2 != wantB2 {
+				t.Fatalf("%s conn %d: cached coinbase differs from uncached build", name, i)
+			}
+		}
+		_, coinb2s[name], _ = job.notifyCoinbaseParts([]byte{9, 9, 9, 9}, payouts, job.ScriptTime)
+	}
+
+	if coinb2s["pool"] == coinb2s["worker"] || coinb2s["pool"] == coinb2s["dual"] || coinb2s["worker"] == coinb2s["dual"] {
+		t.Fatalf("distinct payouts must not share a cached coinb2")
+	}
+	if got := len(job.coinbaseTails.tails); got != len(payoutSets) {
+		t.Fatalf("expected %d cached tails, got %d", len(payoutSets), got)
+	}
+}
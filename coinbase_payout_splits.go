@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	// maxPayoutSplits bounds [[mining.payout_splits]] so a split pool fee
+	// plus the worker, donation and witness outputs stays well inside
+	// maxCoinbasePayoutOutputs and the coinbase size ceiling.
+	maxPayoutSplits = 16
+	// payoutSplitWeightTolerance is how far the split weights may stray
+	// from 100 (float rounding in hand-written configs).
+	payoutSplitWeightTolerance = 0.01
+)
+
+// PayoutSplit is one operator wallet that receives a weighted share of the
+// pool fee output ([[mining.payout_splits]]). Weights are percentages of the
+// pool fee and must sum to 100.
+type PayoutSplit struct {
+	Address string  `toml:"address" json:"address"`
+	Weight  float64 `toml:"weight" json:"weight"`
+}
+
+// coinbasePayoutSplit is a PayoutSplit with its address resolved to a
+// script.
+type coinbasePayoutSplit struct {
+	Script []byte
+	Weight float64
+}
+
+func validatePayoutSplits(splits []PayoutSplit) error {
+	if len(splits) == 0 {
+		return nil
+	}
+	if len(splits) > maxPayoutSplits {
+		return fmt.Errorf("payout_splits has %d entries, maximum is %d", len(splits), maxPayoutSplits)
+	}
+	seen := make(map[string]struct{}, len(splits))
+	var sum float64
+	for i, s := range splits {
+		addr := strings.TrimSpace(s.Address)
+		if addr == "" {
+			return fmt.Errorf("payout_splits[%d]: address is required", i)
+		}
+		if _, dup := seen[addr]; dup {
+			return fmt.Errorf("payout_splits[%d]: duplicate address %q", i, addr)
+		}
+		seen[addr] = struct{}{}
+		if math.IsNaN(s.Weight) || math.IsInf(s.Weight, 0) || s.Weight <= 0 {
+			return fmt.Errorf("payout_splits[%d]: weight must be > 0, got %v", i, s.Weight)
+		}
+		sum += s.Weight
+	}
+	if math.Abs(sum-100) > payoutSplitWeightTolerance {
+		return fmt.Errorf("payout_splits weights must sum to 100, got %v", sum)
+	}
+	return nil
+}
+
+// derivePayoutSplitScripts resolves the configured split addresses. It
+// returns nil when no splits are configured.
+func derivePayoutSplitScripts(cfg Config) ([]coinbasePayoutSplit, error) {
+	if err := validatePayoutSplits(cfg.PayoutSplits); err != nil {
+		return nil, err
+	}
+	if len(cfg.PayoutSplits) == 0 {
+		return nil, nil
+	}
+	out := make([]coinbasePayoutSplit, 0, len(cfg.PayoutSplits))
+	for _, s := range cfg.PayoutSplits {
+		addr := strings.TrimSpace(s.Address)
+		script, err := fetchPayoutScript(nil, addr)
+		if err != nil {
+			return nil, fmt.Errorf("payout split address %q: %w", addr, err)
+		}
+		out = append(out, coinbasePayoutSplit{Script: script, Weight: s.Weight})
+	}
+	return out, nil
+}
+
+// splitPayoutOutputs divides value between splits by weight. Every split
+// after the first gets floor(value*weight/total); the first split takes the
+// rest, so rounding remainders land on it deterministically and the outputs
+// always sum to value. Splits that round to zero or below dust (when
+// positive) are folded into the first split.
+func splitPayoutOutputs(value int64, splits []coinbasePayoutSplit, dust int64) []coinbasePayoutOutput {
+	var totalWeight float64
+	for _, s := range splits {
+		totalWeight += s.Weight
+	}
+	out := make([]coinbasePayoutOutput, 1, len(splits))
+	remaining := value
+	for _, s := range splits[1:] {
+		v := int64(math.Floor(float64(value) * s.Weight / totalWeight))
+		if v <= 0 || (dust > 0 && v < dust) {
+			continue
+		}
+		remaining -= v
+		out = append(out, coinbasePayoutOutput{Script: s.Script, Value: v})
+	}
+	out[0] = coinbasePayoutOutput{Script: splits[0].Script, Value: remaining}
+	return out
+}
+
+// applyPayoutSplits replaces the pool fee output (the one paying poolScript)
+// with the weighted split outputs. The worker and donation outputs and the
+// total value are unchanged. Without splits payouts is returned as is.
+func applyPayoutSplits(payouts []coinbasePayoutOutput, poolScript []byte, splits []coinbasePayoutSplit, dust int64) ([]coinbasePayoutOutput, error) {
+	if len(splits) == 0 {
+		return payouts, nil
+	}
+	for i, o := range payouts {
+		if !bytes.Equal(o.Script, poolScript) {
+			continue
+		}
+		split := splitPayoutOutputs(o.Value, splits, dust)
+		out := make([]coinbasePayoutOutput, 0, len(payouts)-1+len(split))
+		out = append(out, payouts[:i]...)
+		out = append(out, split...)
+		out = append(out, payouts[i+1:]...)
+		if err := validateCoinbasePayoutOutputs(out); err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+	// The pool fee output was folded away as dust; nothing to split.
+	return payouts, nil
+}
+
+// multiCoinbasePayouts is the dual or triple payout layout with the pool fee
+// output divided between payout splits.
+func multiCoinbasePayouts(poolScript []byte, donationScript []byte, workerScript []byte, splits []coinbasePayoutSplit, totalValue int64, poolFeePercent float64, donationFeePercent float64, dustThreshold int64) ([]coinbasePayoutOutput, error) {
+	var (
+		payouts []coinbasePayoutOutput
+		err     error
+	)
+	if donationFeePercent > 0 && len(donationScript) > 0 {
+		payouts, err = tripleCoinbasePayouts(poolScript, donationScript, workerScript, totalValue, poolFeePercent, donationFeePercent, dustThreshold)
+	} else {
+		payouts, err = dualCoinbasePayouts(poolScript, workerScript, totalValue, poolFeePercent, dustThreshold)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return applyPayoutSplits(payouts, poolScript, splits, dustThreshold)
+}
+
+// serializeMultiCoinbaseTxPredecoded builds the coinbase for the
+// multiCoinbasePayouts layout.
+func serializeMultiCoinbaseTxPredecoded(height int64, extranonce1, extranonce2 []byte, templateExtraNonce2Size int, poolScript []byte, donationScript []byte, workerScript []byte, splits []coinbasePayoutSplit, totalValue int64, poolFeePercent float64, donationFeePercent float64, dustThreshold int64, commitmentScript []byte, extraOpReturn []byte, flagsBytes []byte, coinbaseMsg string, scriptTime int64) ([]byte, []byte, error) {
+	payouts, err := multiCoinbasePayouts(poolScript, donationScript, workerScript, splits, totalValue, poolFeePercent, donationFeePercent, dustThreshold)
+	if err != nil {
+		return nil, nil, err
+	}
+	return serializeCoinbaseTxPayoutsPredecoded(height, extranonce1, extranonce2, templateExtraNonce2Size, payouts, commitmentScript, extraOpReturn, flagsBytes, coinbaseMsg, scriptTime)
+}
+
+// paysPayoutSplit reports whether script is one of the job's split wallets.
+func (job *Job) paysPayoutSplit(script []byte) bool {
+	for _, s := range job.PayoutSplits {
+		if bytes.Equal(s.Script, script) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestValidatePayoutSplits(t *testing.T) {
+	ok := []PayoutSplit{{Address: "a", Weight: 60}, {Address: "b", Weight: 39.995}}
+	if err := validatePayoutSplits(ok); err != nil {
+		t.Fatalf("expected weights within tolerance to pass: %v", err)
+	}
+	for name, bad := range map[string][]PayoutSplit{
+		"sum":       {{Address: "a", Weight: 60}, {Address: "b", Weight: 30}},
+		"zero":      {{Address: "a", Weight: 100}, {Address: "b", Weight: 0}},
+		"duplicate": {{Address: "a", Weight: 50}, {Address: "a", Weight: 50}},
+		"address":   {{Address: " ", Weight: 100}},
+	} {
+		if err := validatePayoutSplits(bad); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestMultiCoinbasePayoutsSplitsPoolFee(t *testing.T) {
+	pool := []byte{0x51}
+	worker := []byte{0x52}
+	splits := []coinbasePayoutSplit{
+		{Script: []byte{0x53}, Weight: 50},
+		{Script: []byte{0x54}, Weight: 25},
+		{Script: []byte{0x55}, Weight: 25},
+	}
+	const total = 1_000_003
+	payouts, err := multiCoinbasePayouts(pool, nil, worker, splits, total, 1, 0, 0)
+	if err != nil {
+		t.Fatalf("multiCoinbasePayouts: %v", err)
+	}
+	var sum, fee int64
+	values := map[byte]int64{}
+	for _, o := range payouts {
+		if bytes.Equal(o.Script, pool) {
+			t.Fatalf("pool output should have been replaced by splits")
+		}
+		sum += o.Value
+		values[o.Script[0]] = o.Value
+		if o.Script[0] != 0x52 {
+			fee += o.Value
+		}
+	}
+	if sum != total {
+		t.Fatalf("outputs sum to %d, want %d", sum, total)
+	}
+	// 1% of 1,000,003 rounds to 10,000; the 25% splits get 2,500 each and
+	// the first split keeps the rest.
+	if fee != 10_000 || values[0x54] != 2_500 || values[0x55] != 2_500 || values[0x53] != 5_000 {
+		t.Fatalf("unexpected split values %v (fee %d)", values, fee)
+	}
+
+	odd := splitPayoutOutputs(10_001, splits, 0)
+	if odd[0].Value != 5_001 {
+		t.Fatalf("rounding remainder should go to the first split, got %+v", odd)
+	}
+	folded := splitPayoutOutputs(1_000, splits, 546)
+	if len(folded) != 1 || folded[0].Value != 1_000 {
+		t.Fatalf("dust splits should fold into the first split, got %+v", folded)
+	}
+}
+
+func TestDerivePayoutSplitScripts(t *testing.T) {
+	const addr = "1BitcoinEaterAddressDontSendf59kuE"
+	splits, err := derivePayoutSplitScripts(Config{PayoutSplits: []PayoutSplit{{Address: addr, Weight: 100}}})
+	if err != nil || len(splits) != 1 || len(splits[0].Script) == 0 {
+		t.Fatalf("expected one resolved split, got %+v err=%v", splits, err)
+	}
+	if _, err := derivePayoutSplitScripts(Config{PayoutSplits: []PayoutSplit{{Address: "not-an-address", Weight: 100}}}); err == nil {
+		t.Fatalf("expected invalid split address to fail")
+	}
+}
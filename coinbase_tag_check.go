@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const coinbaseTagCheckPoll = time.Minute
+
+// coinbaseTagStatus is the outcome of one coinbase tag self-check.
+type coinbaseTagStatus struct {
+	Height    int64
+	Limit     int
+	Tag       string // configured tag, including the pool entropy suffix
+	Effective string // what fits in the scriptSig at Height
+	Truncated bool
+}
+
+// checkCoinbaseTag sizes the configured coinbase tag against
+// coinbase_scriptsig_max_bytes the same way job building does. The BIP34
+// height push grows with the chain, so a tag that fits today can start being
+// cut at a later height without any config change.
+func checkCoinbaseTag(cfg Config, height int64, flags string, now time.Time) (coinbaseTagStatus, error) {
+	st := coinbaseTagStatus{Height: height, Limit: cfg.CoinbaseScriptSigMaxBytes}
+	msg := cfg.CoinbaseMsg
+	if cfg.JobEntropy > 0 {
+		// Only the suffix length matters here; its random part differs per job.
+		withSuffix, err := buildCoinbaseMsgWithSuffix(msg, cfg.PoolEntropy, cfg.JobEntropy)
+		if err != nil {
+			return st, err
+		}
+		msg = withSuffix
+	}
+	st.Tag = strings.TrimPrefix(normalizeCoinbaseMessage(msg), "/")
+	effective, truncated, err := clampCoinbaseMessage(msg, cfg.CoinbaseScriptSigMaxBytes, height, now.Unix(), flags, cfg.Extranonce2Size, cfg.TemplateExtraNonce2Size)
+	if err != nil {
+		return st, err
+	}
+	st.Effective = effective
+	st.Truncated = truncated
+	return st, nil
+}
+
+// coinbaseTagWatch remembers the last reported truncation state so the
+// check only alerts when it changes.
+type coinbaseTagWatch struct {
+	known     bool
+	truncated bool
+}
+
+// observe reports whether truncated is news. A tag that already fits on the
+// first check stays quiet; one that is already being cut is reported.
+func (w *coinbaseTagWatch) observe(truncated bool) bool {
+	changed := truncated
+	if w.known {
+		changed = truncated != w.truncated
+	}
+	w.known = true
+	w.truncated = truncated
+	return changed
+}
+
+// runCoinbaseTagCheck re-checks the coinbase tag every
+// coinbase_tag_check_hours against the current job height, and logs and
+// alerts when the tag starts or stops being truncated. The first check runs
+// once the first job is available.
+func runCoinbaseTagCheck(ctx context.Context, jobMgr *JobManager, cfgFn func() Config, metrics *PoolMetrics, notifier *discordNotifier) {
+	if jobMgr == nil || cfgFn == nil {
+		return
+	}
+	var w coinbaseTagWatch
+	var next time.Time
+	ticker := time.NewTicker(coinbaseTagCheckPoll)
+	defer ticker.Stop()
+	for {
+		now := time.Now()
+		cfg := cfgFn()
+		job := jobMgr.CurrentJob()
+		if cfg.CoinbaseTagCheckInterval > 0 && job != nil && !now.Before(next) {
+			next = now.Add(cfg.CoinbaseTagCheckInterval)
+			st, err := checkCoinbaseTag(cfg, job.Template.Height, job.Template.CoinbaseAux.Flags, now)
+			if err != nil {
+				logger.Warn("coinbase tag check failed", "component", "job", "kind", "coinbase_tag", "error", err)
+			} else if w.observe(st.Truncated) {
+				reportCoinbaseTagChange(st, now, metrics, notifier)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func reportCoinbaseTagChange(st coinbaseTagStatus, now time.Time, metrics *PoolMetrics, notifier *discordNotifier) {
+	if st.Truncated {
+		msg := fmt.Sprintf("Coinbase tag is truncated at height %d: %q is cut to %q to fit coinbase_scriptsig_max_bytes = %d.",
+			st.Height, st.Tag, st.Effective, st.Limit)
+		logger.Warn("coinbase tag truncated to fit scriptsig limit",
+			"component", "job", "kind", "coinbase_tag",
+			"height", st.Height, "limit", st.Limit,
+			"tag", st.Tag, "effective", st.Effective,
+		)
+		if metrics != nil {
+			metrics.RecordErrorEvent("coinbase_tag", msg, now)
+		}
+		notifier.NotifyPoolAlert(msg)
+		return
+	}
+	logger.Info("coinbase tag fits scriptsig limit again",
+		"component", "job", "kind", "coinbase_tag",
+		"height", st.Height, "limit", st.Limit, "tag", st.Tag,
+	)
+	notifier.NotifyPoolAlert(fmt.Sprintf("Coinbase tag %q fits coinbase_scriptsig_max_bytes = %d again.", st.Tag, st.Limit))
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckCoinbaseTagTruncatesAsHeightGrows(t *testing.T) {
+	cfg := Config{
+		CoinbaseScriptSigMaxBytes: 100,
+		Extranonce2Size:           4,
+		TemplateExtraNonce2Size:   8,
+	}
+	now := time.Unix(1_700_000_000, 0)
+	const lowHeight, highHeight = 900_000, 1 << 23
+
+	// Find the longest tag that still fits at lowHeight.
+	for n := 1; ; n++ {
+		cfg.CoinbaseMsg = strings.Repeat("a", n)
+		st, err := checkCoinbaseTag(cfg, lowHeight, "", now)
+		if err != nil {
+			t.Fatalf("checkCoinbaseTag: %v", err)
+		}
+		if st.Truncated {
+			cfg.CoinbaseMsg = strings.Repeat("a", n-1)
+			break
+		}
+	}
+
+	st, err := checkCoinbaseTag(cfg, lowHeight, "", now)
+	if err != nil || st.Truncated || st.Effective != cfg.CoinbaseMsg {
+		t.Fatalf("at height %d: got %+v, %v; want the full tag", lowHeight, st, err)
+	}
+	st, err = checkCoinbaseTag(cfg, highHeight, "", now)
+	if err != nil {
+		t.Fatalf("checkCoinbaseTag: %v", err)
+	}
+	if !st.Truncated || len(st.Effective) >= len(st.Tag) || st.Tag != cfg.CoinbaseMsg {
+		t.Fatalf("at height %d: got %+v; want a shortened tag", highHeight, st)
+	}
+}
+
+func TestCoinbaseTagWatchReportsStateChanges(t *testing.T) {
+	var w coinbaseTagWatch
+	if w.observe(false) {
+		t.Fatalf("a tag that fits on the first check should not alert")
+	}
+	if !w.observe(true) {
+		t.Fatalf("expected an alert when truncation starts")
+	}
+	if w.observe(true) {
+		t.Fatalf("unchanged truncation should not alert again")
+	}
+	if !w.observe(false) {
+		t.Fatalf("expected a notice when the tag fits again")
+	}
+
+	var first coinbaseTagWatch
+	if !first.observe(true) {
+		t.Fatalf("a tag already truncated on the first check should alert")
+	}
+}
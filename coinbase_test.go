@@ -23,7 +23,7 @@ func TestSerializeCoinbaseTx_SingleOutputStructure(t *testing.T) {
 	coinbaseMsg := "goPool-test"
 	scriptTime := int64(0)
 
-	raw, txid, err := serializeCoinbaseTx(height, ex1, ex2, templateExtra, payoutScript, coinbaseValue, witnessCommitment, coinbaseFlags, coinbaseMsg, scriptTime)
+	raw, txid, err := serializeCoinbaseTx(height, ex1, ex2, templateExtra, payoutScript, coinbaseValue, witnessCommitment, nil, coinbaseFlags, coinbaseMsg, scriptTime)
 	if err != nil {
 		t.Fatalf("serializeCoinbaseTx error: %v", err)
 	}
@@ -73,7 +73,7 @@ func TestSerializeDualCoinbaseTx_DualOutputs(t *testing.T) {
 	coinbaseMsg := "goPool-dual"
 	scriptTime := int64(0)
 
-	raw, txid, err := serializeDualCoinbaseTx(height, ex1, ex2, templateExtra, poolScript, workerScript, totalValue, feePercent, witnessCommitment, coinbaseFlags, coinbaseMsg, scriptTime)
+	raw, txid, err := serializeDualCoinbaseTx(height, ex1, ex2, templateExtra, poolScript, workerScript, totalValue, feePercent, witnessCommitment, nil, coinbaseFlags, coinbaseMsg, scriptTime)
 	if err != nil {
 		t.Fatalf("serializeDualCoinbaseTx error: %v", err)
 	}
@@ -152,7 +152,7 @@ func TestSerializeCoinbaseTx_WithWitnessCommitment(t *testing.T) {
 	coinbaseMsg := "goPool-witness"
 	scriptTime := int64(0)
 
-	raw, _, err := serializeCoinbaseTx(height, ex1, ex2, templateExtra, payoutScript, coinbaseValue, witnessCommitment, coinbaseFlags, coinbaseMsg, scriptTime)
+	raw, _, err := serializeCoinbaseTx(height, ex1, ex2, templateExtra, payoutScript, coinbaseValue, witnessCommitment, nil, coinbaseFlags, coinbaseMsg, scriptTime)
 	if err != nil {
 		t.Fatalf("serializeCoinbaseTx error: %v", err)
 	}
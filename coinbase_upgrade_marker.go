@@ -0,0 +1,53 @@
+package main
+
+import "strings"
+
+// maxCoinbaseUpgradeMarkerLen bounds the build marker appended to the
+// coinbase tag so a long version string cannot crowd out the pool tag.
+const maxCoinbaseUpgradeMarkerLen = 24
+
+// coinbaseUpgradeMarker returns the build version (or, failing that, the
+// build time) as a coinbase-safe tag part: printable ASCII without '/'.
+// It is empty for builds that carry neither.
+func coinbaseUpgradeMarker() string {
+	raw := strings.TrimSpace(buildVersion)
+	if raw == "" {
+		raw = strings.TrimSpace(buildTime)
+	}
+	var b strings.Builder
+	for i := 0; i < len(raw) && b.Len() < maxCoinbaseUpgradeMarkerLen; i++ {
+		c := raw[i]
+		if c > 0x20 && c <= 0x7e && c != '/' {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// withCoinbaseUpgradeMarker appends the build marker to msg while
+// coinbase_upgrade_marker is enabled and no block has been found since this
+// process started, so the first block after a restart or upgrade records
+// which build produced it. clampJobCoinbaseMessage drops the marker again if
+// it would not fit the scriptSig limit.
+func (jm *JobManager) withCoinbaseUpgradeMarker(msg string) string {
+	if !jm.cfg.CoinbaseUpgradeMarker || jm.blockFoundSinceStart.Load() {
+		return msg
+	}
+	marker := coinbaseUpgradeMarker()
+	if marker == "" {
+		return msg
+	}
+	return appendCoinbaseTagPart(msg, marker)
+}
+
+// NoteBlockFound records an accepted block. Jobs built afterwards use the
+// normal coinbase tag; the next template (a found block always moves the
+// tip) carries no marker.
+func (jm *JobManager) NoteBlockFound() {
+	if jm == nil {
+		return
+	}
+	if !jm.blockFoundSinceStart.Swap(true) {
+		logger.Debug("first block since start found", "component", "job", "kind", "coinbase_marker")
+	}
+}
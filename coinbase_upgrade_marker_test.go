@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCoinbaseUpgradeMarkerUntilFirstBlock(t *testing.T) {
+	oldVersion := buildVersion
+	buildVersion = "v1.2/3 rc"
+	t.Cleanup(func() { buildVersion = oldVersion })
+
+	jm := &JobManager{cfg: Config{CoinbaseUpgradeMarker: true}}
+	msg, err := jm.clampJobCoinbaseMessage("/goPool/", 900000, 1700000000, "")
+	if err != nil {
+		t.Fatalf("clamp: %v", err)
+	}
+	if msg != "/goPool/v1.23rc" {
+		t.Fatalf("expected marked tag, got %q", msg)
+	}
+
+	jm.NoteBlockFound()
+	msg, err = jm.clampJobCoinbaseMessage("/goPool/", 900000, 1700000000, "")
+	if err != nil {
+		t.Fatalf("clamp: %v", err)
+	}
+	if msg != "/goPool/" {
+		t.Fatalf("expected normal tag after the first block, got %q", msg)
+	}
+}
+
+func TestCoinbaseUpgradeMarkerDroppedWhenOverLimit(t *testing.T) {
+	oldVersion := buildVersion
+	buildVersion = "v1.2.3"
+	t.Cleanup(func() { buildVersion = oldVersion })
+
+	cfg := Config{CoinbaseUpgradeMarker: true, Extranonce2Size: 4, TemplateExtraNonce2Size: 4}
+	fixed, err := coinbaseScriptSigFixedLen(900000, 1700000000, "", cfg.Extranonce2Size, cfg.TemplateExtraNonce2Size)
+	if err != nil {
+		t.Fatalf("fixed len: %v", err)
+	}
+	// Room for "/goPool/" (plus its push opcode) but not the marker.
+	cfg.CoinbaseScriptSigMaxBytes = fixed + len(serializeStringScript("/goPool/"))
+	jm := &JobManager{cfg: cfg}
+	msg, err := jm.clampJobCoinbaseMessage("/goPool/", 900000, 1700000000, "")
+	if err != nil {
+		t.Fatalf("clamp: %v", err)
+	}
+	if strings.Contains(msg, "v1") || !strings.Contains(msg, "goPool") {
+		t.Fatalf("expected marker dropped and pool tag kept, got %q", msg)
+	}
+}
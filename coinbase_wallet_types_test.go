@@ -153,6 +153,7 @@ func TestDualCoinbaseWithMixedWalletTypes(t *testing.T) {
 					totalValue,
 					feePercent,
 					witnessCommitment,
+					nil,
 					coinbaseFlags,
 					coinbaseMsg,
 					scriptTime,
@@ -275,6 +276,7 @@ func TestSingleCoinbaseWithAllWalletTypes(t *testing.T) {
 				script,
 				coinbaseValue,
 				witnessCommitment,
+				nil,
 				coinbaseFlags,
 				coinbaseMsg,
 				scriptTime,
@@ -431,6 +433,7 @@ func TestTaprootInDualCoinbase(t *testing.T) {
 				totalValue,
 				feePercent,
 				witnessCommitment,
+				nil,
 				coinbaseFlags,
 				coinbaseMsg,
 				scriptTime,
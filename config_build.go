@@ -12,6 +12,8 @@ func buildBaseFileConfig(cfg Config) baseFileConfig {
 			StatusListen:    cfg.StatusAddr,
 			StatusTLSListen: &cfg.StatusTLSAddr,
 			StatusPublicURL: cfg.StatusPublicURL,
+			OfflineMode:     cfg.OfflineMode,
+			WatchTuningFile: cfg.WatchTuningFile,
 		},
 		Branding: brandingConfig{
 			StatusBrandName:                 cfg.StatusBrandName,
@@ -22,9 +24,15 @@ func buildBaseFileConfig(cfg Config) baseFileConfig {
 			FiatCurrency:                    cfg.FiatCurrency,
 			PoolDonationAddress:             cfg.PoolDonationAddress,
 			ServerLocation:                  cfg.ServerLocation,
+			DisplayTimezone:                 cfg.DisplayTimezone,
 		},
 		Stratum: stratumConfig{
 			StratumTLSListen:       cfg.StratumTLSListen,
+			StratumTLSClientCA:     cfg.StratumTLSClientCA,
+			StratumProxyListen:     cfg.StratumProxyListen,
+			StratumWebSocketPath:   cfg.StratumWebSocketPath,
+			StratumWSListen:        cfg.StratumWSListen,
+			StratumReusePort:       cfg.StratumReusePort,
 			StratumPasswordEnabled: cfg.StratumPasswordEnabled,
 			StratumPassword:        cfg.StratumPassword,
 			StratumPasswordPublic:  cfg.StratumPasswordPublic,
@@ -36,6 +44,7 @@ func buildBaseFileConfig(cfg Config) baseFileConfig {
 			ZMQHashBlockAddr: cfg.ZMQHashBlockAddr,
 			ZMQRawBlockAddr:  cfg.ZMQRawBlockAddr,
 			RPCCookiePath:    cfg.RPCCookiePath,
+			RPCCookieRetry:   cfg.RPCCookieRetry,
 		},
 		Mining: miningConfig{
 			PoolFeePercent:          new(cfg.PoolFeePercent),
@@ -45,10 +54,19 @@ func buildBaseFileConfig(cfg Config) baseFileConfig {
 			OperatorDonationURL:     cfg.OperatorDonationURL,
 			PoolEntropy:             stringPtr(cfg.PoolEntropy),
 			PoolTagPrefix:           cfg.PoolTagPrefix,
+			PayoutSplits:            cfg.PayoutSplits,
 		},
 		Logging: loggingConfig{
-			Debug:    boolPtr(cfg.LogDebug),
-			NetDebug: boolPtr(cfg.LogNetDebug),
+			Debug:                      boolPtr(cfg.LogDebug),
+			NetDebug:                   boolPtr(cfg.LogNetDebug),
+			TraceSamplePercent:         float64Ptr(cfg.LogTraceSamplePercent),
+			RetentionDays:              new(cfg.LogRetentionDays),
+			RetentionFiles:             new(cfg.LogRetentionFiles),
+			CompressRotated:            boolPtr(cfg.LogCompressRotated),
+			AutoProfileGoroutines:      new(cfg.AutoProfileGoroutines),
+			AutoProfileHeapMB:          new(cfg.AutoProfileHeapMB),
+			AutoProfileIntervalSeconds: new(int(cfg.AutoProfileMinInterval / time.Second)),
+			Format:                     stringPtr(cfg.LogFormat),
 		},
 	}
 }
@@ -78,10 +96,21 @@ func buildServicesFileConfig(cfg Config) servicesFileConfig {
 			DiscordServerID:              cfg.DiscordServerID,
 			DiscordNotifyChannelID:       cfg.DiscordNotifyChannelID,
 			WorkerNotifyThresholdSeconds: new(cfg.DiscordWorkerNotifyThresholdSeconds),
+			BlockCooldownSeconds:         new(cfg.DiscordBlockCooldownSeconds),
+			WorkerBlockCooldownSeconds:   new(cfg.DiscordWorkerBlockCooldownSeconds),
+			WorkerHashrateAlertMinutes:   new(cfg.DiscordWorkerHashrateAlertMinutes),
 		},
 		Status: servicesStatusConfig{
-			MempoolAddressURL: cfg.MempoolAddressURL,
-			GitHubURL:         cfg.GitHubURL,
+			MempoolAddressURL:  cfg.MempoolAddressURL,
+			GitHubURL:          cfg.GitHubURL,
+			MaintenanceMode:    cfg.StatusMaintenanceMode,
+			MaintenanceMessage: cfg.StatusMaintenanceMessage,
+			OperatorFields:     cfg.OperatorFields,
+			MetricsEnabled:     cfg.MetricsEnabled,
+
+			MetricsPushAddr:            cfg.MetricsPushAddr,
+			MetricsPushFormat:          cfg.MetricsPushFormat,
+			MetricsPushIntervalSeconds: new(cfg.MetricsPushIntervalSeconds),
 		},
 	}
 }
@@ -89,26 +118,57 @@ func buildServicesFileConfig(cfg Config) servicesFileConfig {
 func buildPolicyFileConfig(cfg Config) policyFileConfig {
 	return policyFileConfig{
 		Stratum: policyStratumConfig{
-			CKPoolEmulate: new(cfg.CKPoolEmulate),
+			CKPoolEmulate:                 new(cfg.CKPoolEmulate),
+			SubscribePoWBits:              new(cfg.SubscribePoWBits),
+			SubscribePoWBitsTLS:           new(cfg.SubscribePoWBitsTLS),
+			GateOnNetworkInactive:         new(cfg.GateOnNetworkInactive),
+			ReplaceStaleWorkerConnections: new(cfg.ReplaceStaleWorkerConnections),
+			AllowAuthorizeBeforeSubscribe: new(cfg.AllowAuthorizeBeforeSubscribe),
+			TrackTransportChanges:         new(cfg.TrackTransportChanges),
+			BadIDPolicy:                   new(cfg.StratumBadIDPolicy),
+			FirstJobAlertSeconds:          new(int(cfg.FirstJobAlertTimeout / time.Second)),
+			FirstJobAlertIBDSeconds:       new(int(cfg.FirstJobAlertIBDTimeout / time.Second)),
+			WorkerConnHistorySeconds:      new(int(cfg.WorkerConnHistoryWindow / time.Second)),
 		},
 		Mining: policyMiningConfig{
 			ShareJobFreshnessMode:            new(cfg.ShareJobFreshnessMode),
 			ShareCheckNTimeWindow:            new(cfg.ShareCheckNTimeWindow),
+			ShareNTimeRefreshCurrentJob:      new(cfg.ShareNTimeRefreshCurrentJob),
 			ShareCheckVersionRolling:         new(cfg.ShareCheckVersionRolling),
 			ShareRequireAuthorizedConnection: new(cfg.ShareRequireAuthorizedConnection),
 			ShareCheckParamFormat:            new(cfg.ShareCheckParamFormat),
 			ShareRequireWorkerMatch:          new(cfg.ShareRequireWorkerMatch),
+			ShareRequireSubscribedConnection: new(cfg.ShareRequireSubscribedConnection),
 			SubmitProcessInline:              new(cfg.SubmitProcessInline),
+			SubmitPanicDisconnectAfter:       new(cfg.SubmitPanicDisconnectAfter),
 			ShareCheckDuplicate:              new(cfg.ShareCheckDuplicate),
+			ShareMaxJobAgeSeconds:            new(cfg.ShareMaxJobAgeSeconds),
+			RequiredTemplateTxids:            cfg.RequiredTemplateTxids,
+			CoinbaseDustThresholdSats:        new(cfg.CoinbaseDustThreshold),
+			InvalidWalletFallbackToPool:      new(cfg.InvalidWalletFallbackToPool),
+			CoinbasePayoutMode:               new(cfg.CoinbasePayoutMode),
+			RecordRewardDistribution:         new(cfg.RecordRewardDistribution),
+			PayoutAddressCheckIntervalSec:    new(int(cfg.PayoutAddressCheckInterval / time.Second)),
+			NearMissFactor:                   new(cfg.NearMissFactor),
+			AccountingRecoveryFile:           new(cfg.AccountingRecoveryFile),
+			TemplateAllowConfirmedReorg:      new(cfg.TemplateAllowConfirmedReorg),
+			DegradedFeedSharePolicy:          new(cfg.DegradedFeedSharePolicy),
+			SavedWorkerStaticDifficulty:      new(cfg.SavedWorkerStaticDifficulty),
+			DedupeBlockSubmissions:           new(cfg.DedupeBlockSubmissions),
 		},
 		Hashrate: policyHashrateConfig{
-			ShareNTimeMaxForwardSeconds: new(cfg.ShareNTimeMaxForwardSeconds),
+			ShareNTimeMaxForwardSeconds:      new(cfg.ShareNTimeMaxForwardSeconds),
+			HashrateDropAlertPercent:         new(cfg.HashrateDropAlertPercent),
+			HashrateDropAlertWindowSeconds:   new(int(cfg.HashrateDropAlertWindow / time.Second)),
+			HashrateDropAlertDebounceSeconds: new(int(cfg.HashrateDropAlertDebounce / time.Second)),
 		},
 		Version: versionTuning{
 			MinVersionBits:                new(cfg.MinVersionBits),
 			ShareAllowVersionMaskMismatch: new(cfg.ShareAllowVersionMaskMismatch),
 			ShareAllowDegradedVersionBits: new(cfg.ShareAllowDegradedVersionBits),
 			BIP110Enabled:                 new(cfg.BIP110Enabled),
+			VersionMaskResyncCooldownSec:  new(int(cfg.VersionMaskResyncCooldown / time.Second)),
+			ShareVersionConventionLock:    new(cfg.ShareVersionConventionLock),
 		},
 		Bans: banTuning{
 			CleanExpiredOnStartup:            new(cfg.CleanExpiredBansOnStartup),
@@ -118,10 +178,18 @@ func buildPolicyFileConfig(cfg Config) policyFileConfig {
 			ReconnectBanThreshold:            new(cfg.ReconnectBanThreshold),
 			ReconnectBanWindowSeconds:        new(cfg.ReconnectBanWindowSeconds),
 			ReconnectBanDurationSeconds:      new(cfg.ReconnectBanDurationSeconds),
+			SourcePortChurnPerMin:            new(cfg.SourcePortChurnPerMin),
+			NonceCheckMinSamples:             new(cfg.NonceCheckMinSamples),
+			NonceCheckBanAfter:               new(cfg.NonceCheckBanAfter),
 			BannedMinerTypes:                 cfg.BannedMinerTypes,
+			AllowedMinerTypes:                cfg.AllowedMinerTypes,
+			BanFeedSource:                    cfg.BanFeedSource,
+			BanFeedRefreshSeconds:            new(cfg.BanFeedRefreshSeconds),
 		},
 		Timeouts: timeoutTuning{
 			ConnectionTimeoutSec: new(int(cfg.ConnectionTimeout / time.Second)),
+			TLSInitialTimeoutSec: new(int(cfg.TLSInitialTimeout / time.Second)),
+			LongpollTimeoutSec:   new(int(cfg.LongpollTimeout / time.Second)),
 		},
 	}
 }
@@ -130,6 +198,7 @@ func buildTuningFileConfig(cfg Config) tuningFileConfig {
 	return tuningFileConfig{
 		RateLimits: rateLimitTuning{
 			MaxConns:                          new(cfg.MaxConns),
+			MaxConnsPerIP:                     new(cfg.MaxConnsPerIP),
 			MaxAcceptsPerSecond:               new(cfg.MaxAcceptsPerSecond),
 			MaxAcceptBurst:                    new(cfg.MaxAcceptBurst),
 			DisableConnectRateLimits:          new(cfg.DisableConnectRateLimits),
@@ -140,7 +209,11 @@ func buildTuningFileConfig(cfg Config) tuningFileConfig {
 			AcceptSteadyStateRate:             new(cfg.AcceptSteadyStateRate),
 			AcceptSteadyStateReconnectPercent: new(cfg.AcceptSteadyStateReconnectPercent),
 			AcceptSteadyStateReconnectWindow:  new(cfg.AcceptSteadyStateReconnectWindow),
+			AcceptRPCLatencyBackoffMs:         new(cfg.AcceptRPCLatencyBackoffMs),
+			AcceptRPCLatencyMinPercent:        new(cfg.AcceptRPCLatencyMinPercent),
 			StratumMessagesPerMinute:          new(cfg.StratumMessagesPerMinute),
+			StatusRequestsPerSecond:           new(cfg.StatusRequestsPerSecond),
+			StatusMaxInflightRequests:         new(cfg.StatusMaxInflightRequests),
 		},
 		Difficulty: difficultyTuning{
 			MaxDifficulty:                    new(cfg.MaxDifficulty),
@@ -150,14 +223,25 @@ func buildTuningFileConfig(cfg Config) tuningFileConfig {
 			VarDiffEnabled:                   new(cfg.VarDiffEnabled),
 			LockSuggestedDifficulty:          new(cfg.LockSuggestedDifficulty),
 			EnforceSuggestedDifficultyLimits: new(cfg.EnforceSuggestedDifficultyLimits),
+			ShareFloodSharesPerMin:           new(cfg.ShareFloodSharesPerMin),
+			ShareFloodHoldSeconds:            new(int(cfg.ShareFloodHold / time.Second)),
+			VarDiffStaleFeedbackPercent:      new(cfg.VarDiffStaleFeedbackPercent),
+			WorkerDifficultySync:             new(cfg.WorkerDifficultySync),
+			WorkerOverrides:                  cfg.WorkerDifficultyOverrides,
 		},
 		Mining: miningTuning{
 			Extranonce2Size:           new(cfg.Extranonce2Size),
 			TemplateExtraNonce2Size:   new(cfg.TemplateExtraNonce2Size),
 			JobEntropy:                new(cfg.JobEntropy),
 			CoinbaseScriptSigMaxBytes: new(cfg.CoinbaseScriptSigMaxBytes),
+			CoinbaseTagCheckHours:     new(int(cfg.CoinbaseTagCheckInterval / time.Hour)),
+			CoinbaseMaxBytes:          new(cfg.CoinbaseMaxBytes),
+			CoinbaseUpgradeMarker:     new(cfg.CoinbaseUpgradeMarker),
+			CoinbasePartsCache:        new(cfg.CoinbasePartsCache),
+			ScriptTimeMaxDriftSeconds: new(cfg.ScriptTimeMaxDriftSeconds),
 			DisablePoolJobEntropy:     new(false),
 			DifficultyStepGranularity: new(cfg.DifficultyStepGranularity),
+			CoinbaseExtraOpReturn:     new(cfg.CoinbaseExtraOpReturn),
 		},
 		Hashrate: tuningHashrateConfig{
 			HashrateEMATauSeconds:              new(cfg.HashrateEMATauSeconds),
@@ -166,8 +250,16 @@ func buildTuningFileConfig(cfg Config) tuningFileConfig {
 			SavedWorkerHistoryFlushIntervalSec: new(int(cfg.SavedWorkerHistoryFlushInterval / time.Second)),
 		},
 		Stratum: tuningStratumConfig{
-			TCPReadBufferBytes:  new(cfg.StratumTCPReadBufferBytes),
-			TCPWriteBufferBytes: new(cfg.StratumTCPWriteBufferBytes),
+			TCPReadBufferBytes:           new(cfg.StratumTCPReadBufferBytes),
+			TCPWriteBufferBytes:          new(cfg.StratumTCPWriteBufferBytes),
+			MaxConnectionLifetimeSeconds: new(int(cfg.MaxConnectionLifetime / time.Second)),
+			WriteStallTimeoutSeconds:     new(int(cfg.WriteStallTimeout / time.Second)),
+			NotifyHeartbeatSeconds:       new(int(cfg.NotifyHeartbeatInterval / time.Second)),
+			StatsNotifySeconds:           new(int(cfg.StatsNotifyInterval / time.Second)),
+			ShareListenerLabels:          new(cfg.ShareListenerLabels),
+		},
+		Memory: tuningMemoryConfig{
+			BudgetMB: new(cfg.MemoryBudgetMB),
 		},
 		PeerCleaning: peerCleaningTuning{
 			Enabled:   new(cfg.PeerCleanupEnabled),
@@ -186,11 +278,73 @@ func (cfg Config) Effective() EffectiveConfig {
 	if cfg.SavedWorkerHistoryFlushInterval > 0 {
 		savedWorkerHistoryFlushInterval = cfg.SavedWorkerHistoryFlushInterval.String()
 	}
+	payoutAddressCheckInterval := ""
+	if cfg.PayoutAddressCheckInterval > 0 {
+		payoutAddressCheckInterval = cfg.PayoutAddressCheckInterval.String()
+	}
+	autoProfileMinInterval := ""
+	if cfg.AutoProfileMinInterval > 0 {
+		autoProfileMinInterval = cfg.AutoProfileMinInterval.String()
+	}
+	hashrateDropAlertWindow := ""
+	if cfg.HashrateDropAlertWindow > 0 {
+		hashrateDropAlertWindow = cfg.HashrateDropAlertWindow.String()
+	}
+	hashrateDropAlertDebounce := ""
+	if cfg.HashrateDropAlertDebounce > 0 {
+		hashrateDropAlertDebounce = cfg.HashrateDropAlertDebounce.String()
+	}
+	coinbaseTagCheckInterval := ""
+	if cfg.CoinbaseTagCheckInterval > 0 {
+		coinbaseTagCheckInterval = cfg.CoinbaseTagCheckInterval.String()
+	}
+	firstJobAlertTimeout := ""
+	if cfg.FirstJobAlertTimeout > 0 {
+		firstJobAlertTimeout = cfg.FirstJobAlertTimeout.String()
+	}
+	workerConnHistoryWindow := ""
+	if cfg.WorkerConnHistoryWindow > 0 {
+		workerConnHistoryWindow = cfg.WorkerConnHistoryWindow.String()
+	}
+	firstJobAlertIBDTimeout := ""
+	if cfg.FirstJobAlertIBDTimeout > 0 {
+		firstJobAlertIBDTimeout = cfg.FirstJobAlertIBDTimeout.String()
+	}
+	maxConnectionLifetime := ""
+	if cfg.MaxConnectionLifetime > 0 {
+		maxConnectionLifetime = cfg.MaxConnectionLifetime.String()
+	}
+	writeStallTimeout := ""
+	if cfg.WriteStallTimeout > 0 {
+		writeStallTimeout = cfg.WriteStallTimeout.String()
+	}
+	notifyHeartbeatInterval := ""
+	if cfg.NotifyHeartbeatInterval > 0 {
+		notifyHeartbeatInterval = cfg.NotifyHeartbeatInterval.String()
+	}
+	statsNotifyInterval := ""
+	if cfg.StatsNotifyInterval > 0 {
+		statsNotifyInterval = cfg.StatsNotifyInterval.String()
+	}
+	tlsInitialTimeout := ""
+	longpollTimeout := ""
+	if cfg.LongpollTimeout > 0 {
+		longpollTimeout = cfg.LongpollTimeout.String()
+	}
+	if cfg.TLSInitialTimeout > 0 {
+		tlsInitialTimeout = cfg.TLSInitialTimeout.String()
+	}
+	versionMaskResyncCooldown := ""
+	if cfg.VersionMaskResyncCooldown > 0 {
+		versionMaskResyncCooldown = cfg.VersionMaskResyncCooldown.String()
+	}
 
 	return EffectiveConfig{
 		ListenAddr:                        cfg.ListenAddr,
 		StatusAddr:                        cfg.StatusAddr,
 		StatusTLSAddr:                     cfg.StatusTLSAddr,
+		OfflineMode:                       cfg.OfflineMode,
+		WatchTuningFile:                   cfg.WatchTuningFile,
 		StatusBrandName:                   cfg.StatusBrandName,
 		StatusBrandDomain:                 cfg.StatusBrandDomain,
 		StatusTagline:                     cfg.StatusTagline,
@@ -198,13 +352,45 @@ func (cfg Config) Effective() EffectiveConfig {
 		PoolDonationAddress:               cfg.PoolDonationAddress,
 		DiscordURL:                        cfg.DiscordURL,
 		DiscordWorkerNotifyThresholdSec:   cfg.DiscordWorkerNotifyThresholdSeconds,
+		DiscordBlockCooldownSec:           cfg.DiscordBlockCooldownSeconds,
+		DiscordWorkerBlockCooldownSec:     cfg.DiscordWorkerBlockCooldownSeconds,
+		DiscordWorkerHashrateAlertMinutes: cfg.DiscordWorkerHashrateAlertMinutes,
 		GitHubURL:                         cfg.GitHubURL,
+		StatusMaintenanceMode:             cfg.StatusMaintenanceMode,
+		StatusMaintenanceMessage:          cfg.StatusMaintenanceMessage,
+		OperatorFields:                    cfg.OperatorFields,
+		MetricsEnabled:                    cfg.MetricsEnabled,
+		MetricsPushAddr:                   cfg.MetricsPushAddr,
+		MetricsPushFormat:                 cfg.MetricsPushFormat,
+		MetricsPushInterval:               cfg.MetricsPushIntervalSeconds,
 		ServerLocation:                    cfg.ServerLocation,
+		DisplayTimezone:                   cfg.DisplayTimezone,
 		StratumTLSListen:                  cfg.StratumTLSListen,
+		StratumProxyListen:                cfg.StratumProxyListen,
+		StratumWebSocketPath:              cfg.StratumWebSocketPath,
+		StratumWSListen:                   cfg.StratumWSListen,
+		StratumTLSClientCA:                cfg.StratumTLSClientCA,
+		StratumReusePort:                  cfg.StratumReusePort,
 		SafeMode:                          cfg.SafeMode,
 		CKPoolEmulate:                     cfg.CKPoolEmulate,
+		SubscribePoWBits:                  cfg.SubscribePoWBits,
+		SubscribePoWBitsTLS:               cfg.SubscribePoWBitsTLS,
+		GateOnNetworkInactive:             cfg.GateOnNetworkInactive,
+		ReplaceStaleWorkerConnections:     cfg.ReplaceStaleWorkerConnections,
+		AllowAuthorizeBeforeSubscribe:     cfg.AllowAuthorizeBeforeSubscribe,
+		TrackTransportChanges:             cfg.TrackTransportChanges,
+		StratumBadIDPolicy:                cfg.StratumBadIDPolicy,
+		FirstJobAlertTimeout:              firstJobAlertTimeout,
+		FirstJobAlertIBDTimeout:           firstJobAlertIBDTimeout,
+		WorkerConnHistoryWindow:           workerConnHistoryWindow,
 		StratumTCPReadBufferBytes:         cfg.StratumTCPReadBufferBytes,
 		StratumTCPWriteBufferBytes:        cfg.StratumTCPWriteBufferBytes,
+		MaxConnectionLifetime:             maxConnectionLifetime,
+		WriteStallTimeout:                 writeStallTimeout,
+		NotifyHeartbeatInterval:           notifyHeartbeatInterval,
+		StatsNotifyInterval:               statsNotifyInterval,
+		ShareListenerLabels:               cfg.ShareListenerLabels,
+		MemoryBudgetMB:                    cfg.MemoryBudgetMB,
 		ClerkIssuerURL:                    cfg.ClerkIssuerURL,
 		ClerkJWKSURL:                      cfg.ClerkJWKSURL,
 		ClerkSignInURL:                    cfg.ClerkSignInURL,
@@ -214,17 +400,36 @@ func (cfg Config) Effective() EffectiveConfig {
 		RPCURL:                            cfg.RPCURL,
 		RPCUser:                           cfg.RPCUser,
 		RPCPassSet:                        strings.TrimSpace(cfg.RPCPass) != "",
+		RPCCookieRetry:                    cfg.RPCCookieRetry,
 		PayoutAddress:                     cfg.PayoutAddress,
 		PoolFeePercent:                    cfg.PoolFeePercent,
+		CoinbaseDustThreshold:             cfg.CoinbaseDustThreshold,
+		InvalidWalletFallbackToPool:       cfg.InvalidWalletFallbackToPool,
+		CoinbasePayoutMode:                cfg.CoinbasePayoutMode,
+		RecordRewardDistribution:          cfg.RecordRewardDistribution,
+		PayoutAddressCheckInterval:        payoutAddressCheckInterval,
+		NearMissFactor:                    cfg.NearMissFactor,
+		AccountingRecoveryFile:            cfg.AccountingRecoveryFile,
+		TemplateAllowConfirmedReorg:       cfg.TemplateAllowConfirmedReorg,
+		DegradedFeedSharePolicy:           cfg.DegradedFeedSharePolicy,
+		SavedWorkerStaticDifficulty:       cfg.SavedWorkerStaticDifficulty,
+		DedupeBlockSubmissions:            cfg.DedupeBlockSubmissions,
 		OperatorDonationPercent:           cfg.OperatorDonationPercent,
 		OperatorDonationAddress:           cfg.OperatorDonationAddress,
 		OperatorDonationName:              cfg.OperatorDonationName,
 		OperatorDonationURL:               cfg.OperatorDonationURL,
+		PayoutSplits:                      cfg.PayoutSplits,
 		Extranonce2Size:                   cfg.Extranonce2Size,
 		TemplateExtraNonce2Size:           cfg.TemplateExtraNonce2Size,
 		JobEntropy:                        cfg.JobEntropy,
 		PoolID:                            cfg.PoolEntropy,
 		CoinbaseScriptSigMaxBytes:         cfg.CoinbaseScriptSigMaxBytes,
+		CoinbaseTagCheckInterval:          coinbaseTagCheckInterval,
+		CoinbaseMaxBytes:                  cfg.CoinbaseMaxBytes,
+		CoinbaseUpgradeMarker:             cfg.CoinbaseUpgradeMarker,
+		CoinbasePartsCache:                cfg.CoinbasePartsCache,
+		ScriptTimeMaxDriftSeconds:         cfg.ScriptTimeMaxDriftSeconds,
+		CoinbaseExtraOpReturn:             cfg.CoinbaseExtraOpReturn,
 		ZMQHashBlockAddr:                  cfg.ZMQHashBlockAddr,
 		ZMQRawBlockAddr:                   cfg.ZMQRawBlockAddr,
 		BackblazeBackupEnabled:            cfg.BackblazeBackupEnabled,
@@ -236,6 +441,7 @@ func (cfg Config) Effective() EffectiveConfig {
 		BackblazeForceEveryInterval:       cfg.BackblazeForceEveryInterval,
 		BackupSnapshotPath:                cfg.BackupSnapshotPath,
 		MaxConns:                          cfg.MaxConns,
+		MaxConnsPerIP:                     cfg.MaxConnsPerIP,
 		MaxAcceptsPerSecond:               cfg.MaxAcceptsPerSecond,
 		MaxAcceptBurst:                    cfg.MaxAcceptBurst,
 		DisableConnectRateLimits:          cfg.DisableConnectRateLimits,
@@ -246,13 +452,21 @@ func (cfg Config) Effective() EffectiveConfig {
 		AcceptSteadyStateRate:             cfg.AcceptSteadyStateRate,
 		AcceptSteadyStateReconnectPercent: cfg.AcceptSteadyStateReconnectPercent,
 		AcceptSteadyStateReconnectWindow:  cfg.AcceptSteadyStateReconnectWindow,
+		AcceptRPCLatencyBackoffMs:         cfg.AcceptRPCLatencyBackoffMs,
+		AcceptRPCLatencyMinPercent:        cfg.AcceptRPCLatencyMinPercent,
 		StratumMessagesPerMinute:          cfg.StratumMessagesPerMinute,
+		StatusRequestsPerSecond:           cfg.StatusRequestsPerSecond,
+		StatusMaxInflightRequests:         cfg.StatusMaxInflightRequests,
 		MaxRecentJobs:                     cfg.MaxRecentJobs,
 		ConnectionTimeout:                 cfg.ConnectionTimeout.String(),
+		TLSInitialTimeout:                 tlsInitialTimeout,
+		LongpollTimeout:                   longpollTimeout,
 		VersionMask:                       uint32ToHex8Lower(cfg.VersionMask),
 		MinVersionBits:                    cfg.MinVersionBits,
 		ShareAllowVersionMaskMismatch:     cfg.ShareAllowVersionMaskMismatch,
 		ShareAllowDegradedVersionBits:     cfg.ShareAllowDegradedVersionBits,
+		VersionMaskResyncCooldown:         versionMaskResyncCooldown,
+		ShareVersionConventionLock:        cfg.ShareVersionConventionLock,
 		BIP110Enabled:                     cfg.BIP110Enabled,
 		MaxDifficulty:                     cfg.MaxDifficulty,
 		MinDifficulty:                     cfg.MinDifficulty,
@@ -261,18 +475,39 @@ func (cfg Config) Effective() EffectiveConfig {
 		// Effective config mirrors whether suggested difficulty locking is enabled.
 		LockSuggestedDifficulty:          cfg.LockSuggestedDifficulty,
 		DifficultyStepGranularity:        cfg.DifficultyStepGranularity,
+		ShareFloodSharesPerMin:           cfg.ShareFloodSharesPerMin,
+		VarDiffStaleFeedbackPercent:      cfg.VarDiffStaleFeedbackPercent,
+		WorkerDifficultySync:             cfg.WorkerDifficultySync,
+		WorkerDifficultyOverrides:        cfg.WorkerDifficultyOverrides,
+		ShareFloodHold:                   cfg.ShareFloodHold.String(),
 		ShareJobFreshnessMode:            cfg.ShareJobFreshnessMode,
 		ShareCheckNTimeWindow:            cfg.ShareCheckNTimeWindow,
+		ShareNTimeRefreshCurrentJob:      cfg.ShareNTimeRefreshCurrentJob,
 		ShareCheckVersionRolling:         cfg.ShareCheckVersionRolling,
 		ShareRequireAuthorizedConnection: cfg.ShareRequireAuthorizedConnection,
 		ShareCheckParamFormat:            cfg.ShareCheckParamFormat,
 		ShareRequireWorkerMatch:          cfg.ShareRequireWorkerMatch,
+		ShareRequireSubscribedConnection: cfg.ShareRequireSubscribedConnection,
 		SubmitProcessInline:              cfg.SubmitProcessInline,
+		ShareMaxJobAgeSeconds:            cfg.ShareMaxJobAgeSeconds,
+		SubmitPanicDisconnectAfter:       cfg.SubmitPanicDisconnectAfter,
+		RequiredTemplateTxids:            cfg.RequiredTemplateTxids,
 		HashrateEMATauSeconds:            cfg.HashrateEMATauSeconds,
 		ShareNTimeMaxForwardSeconds:      cfg.ShareNTimeMaxForwardSeconds,
+		HashrateDropAlertPercent:         cfg.HashrateDropAlertPercent,
+		HashrateDropAlertWindow:          hashrateDropAlertWindow,
+		HashrateDropAlertDebounce:        hashrateDropAlertDebounce,
 		ShareCheckDuplicate:              cfg.ShareCheckDuplicate,
 		LogDebug:                         cfg.LogDebug,
 		LogNetDebug:                      cfg.LogNetDebug,
+		LogTraceSamplePercent:            cfg.LogTraceSamplePercent,
+		LogRetentionDays:                 cfg.LogRetentionDays,
+		LogRetentionFiles:                cfg.LogRetentionFiles,
+		LogCompressRotated:               cfg.LogCompressRotated,
+		LogFormat:                        cfg.LogFormat,
+		AutoProfileGoroutines:            cfg.AutoProfileGoroutines,
+		AutoProfileHeapMB:                cfg.AutoProfileHeapMB,
+		AutoProfileMinInterval:           autoProfileMinInterval,
 		CleanExpiredBansOnStartup:        cfg.CleanExpiredBansOnStartup,
 		BanInvalidSubmissionsAfter:       cfg.BanInvalidSubmissionsAfter,
 		BanInvalidSubmissionsWindow:      cfg.BanInvalidSubmissionsWindow.String(),
@@ -280,7 +515,11 @@ func (cfg Config) Effective() EffectiveConfig {
 		ReconnectBanThreshold:            cfg.ReconnectBanThreshold,
 		ReconnectBanWindowSeconds:        cfg.ReconnectBanWindowSeconds,
 		ReconnectBanDurationSeconds:      cfg.ReconnectBanDurationSeconds,
+		SourcePortChurnPerMin:            cfg.SourcePortChurnPerMin,
+		NonceCheckMinSamples:             cfg.NonceCheckMinSamples,
+		NonceCheckBanAfter:               cfg.NonceCheckBanAfter,
 		BannedMinerTypes:                 cfg.BannedMinerTypes,
+		AllowedMinerTypes:                cfg.AllowedMinerTypes,
 		PeerCleanupEnabled:               cfg.PeerCleanupEnabled,
 		PeerCleanupMaxPingMs:             cfg.PeerCleanupMaxPingMs,
 		PeerCleanupMinPeers:              cfg.PeerCleanupMinPeers,
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRunConfigCheck(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.AllowPublicRPC = true
+	cfg.PayoutAddress = "bc1qagc0l2cvx0c0mx23rkjpwhe7klelynj98h82tj"
+
+	var out bytes.Buffer
+	if code := runConfigCheck(cfg, runtimeOverrides{}, "", "", &out); code != 0 {
+		t.Fatalf("valid config exit code = %d, output:\n%s", code, out.String())
+	}
+	body := strings.TrimSuffix(out.String(), "check-config: ok\n")
+	var eff EffectiveConfig
+	if err := json.Unmarshal([]byte(body), &eff); err != nil {
+		t.Fatalf("output is not effective config JSON: %v\n%s", err, body)
+	}
+	if eff.RPCURL != cfg.RPCURL {
+		t.Fatalf("effective rpc_url = %q, want %q", eff.RPCURL, cfg.RPCURL)
+	}
+
+	cfg.Extranonce2Size = 0
+	wantErr := validateConfig(cfg)
+	if wantErr == nil {
+		t.Fatalf("expected validateConfig to reject extranonce2_size 0")
+	}
+	out.Reset()
+	if code := runConfigCheck(cfg, runtimeOverrides{}, "", "", &out); code != 1 {
+		t.Fatalf("invalid config exit code = %d, want 1", code)
+	}
+	if !strings.HasSuffix(out.String(), "check-config: config: "+wantErr.Error()+"\n") {
+		t.Fatalf("output does not end with the validateConfig error:\n%s", out.String())
+	}
+}
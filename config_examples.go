@@ -92,15 +92,45 @@ func baseConfigDocComments() []byte {
 # - [server].status_listen: HTTP listener for status UI (requires restart).
 # - [server].status_tls_listen: HTTPS listener; "" disables TLS (requires restart).
 # - [server].status_public_url: Canonical public URL for redirects/cookies; empty = auto-detect.
+# - [server].offline_mode: Disable all outbound calls except to the node (fiat price, Discord, Clerk, Backblaze B2,
+#   metrics push, peer reverse DNS) for air-gapped deployments. B2 backups fall back to local snapshots (requires restart).
+# - [server].watch_tuning_file: Reload the config when tuning.toml changes; a bad file is logged and ignored (requires restart).
+# - [branding].display_timezone: IANA timezone (e.g. "Europe/Berlin") for timestamps on HTML pages; empty = UTC. JSON APIs always use UTC.
 # - [stratum].stratum_tls_listen: Optional Stratum-over-TLS listener (requires restart).
+# - [stratum].stratum_tls_client_ca: PEM CA bundle; when set, the Stratum TLS listener only accepts miners presenting
+#   a client certificate signed by it (private pools). The HTTPS status server is unaffected (requires restart).
+# - [stratum].stratum_proxy_listen: Optional listener for a trusted aggregating proxy. Non-standard: every mining.submit
+#   on it must carry an "hmac" field keyed by stratum_proxy_hmac_secret in secrets.toml, or the share is rejected.
+#   Miners must not connect to it directly (requires restart).
+# - [stratum].stratum_websocket_path: Serve Stratum over WebSocket at this path on the status server (e.g. "/stratum")
+#   for browser-based miners; one JSON-RPC message per text frame. Empty disables it (default; requires restart).
+# - [stratum].stratum_ws_listen: Dedicated plain-HTTP listener (e.g. ":3334") that only accepts Stratum WebSocket
+#   upgrades, on any path, independent of the status server. Put a TLS proxy in front for wss://. Empty disables it
+#   (default; requires restart).
+# - [stratum].stratum_reuse_port: Bind the Stratum listeners with SO_REUSEPORT (Linux) so a new pool process can bind
+#   the same ports during a planned restart and take over new connections; see documentation/operations.md (requires restart).
 # - [stratum].stratum_password_enabled: Require miners to send a password on authorize (requires restart).
 # - [stratum].stratum_password: Password string checked against mining.authorize params (requires restart).
 # - [stratum].stratum_password_public: Show the stratum password on the public connect panel (requires restart).
 # - [stratum].safe_mode: Force conservative compatibility/safety behavior (disables unsafe debug/public-RPC toggles).
 # - Runtime override: --safe-mode=true/false
+# - [[mining.payout_splits]]: Optional address + weight tables that divide the pool fee output between operator
+#   wallets. Weights are percentages of the pool fee and must sum to 100 (at most 16 entries). Rounding
+#   remainders go to the first split; splits below coinbase_dust_threshold_sats fold into the first split.
+#   Single-output coinbases (single_pool mode, pool_fee_percent = 0) are not split.
 #
 # Logging
 # - [logging].level: debug, info, warn, error (requires restart).
+# - [logging].retention_days / retention_files: Delete rolled daily log segments (pool, debug, net-debug) older than
+#   this many days (default 3; 0 keeps all) or beyond this many per stream (default 0 = no count limit).
+# - [logging].compress_rotated: gzip rolled log segments in the background; the open log is never touched (default false).
+# - [logging].format: "text" (default) or "json" for one JSON object per line (ts, level, msg, then each field)
+#   in pool.log, debug.log, net-debug.log and the -stdout mirror (requires restart).
+# - [logging].auto_profile_goroutines / auto_profile_heap_mb: When the goroutine count or live heap (MB) reaches
+#   this limit, write a goroutine dump and heap profile to <data_dir>/state/profiles (0 disables each; default 0).
+# - [logging].auto_profile_interval_seconds: At most one capture per this interval (default 3600); the newest 10
+#   captures are kept.
+# - [logging].trace_sample_percent: Percent of connections (0-100) whose Stratum JSON request/response lines are logged to debug.log when debug logging is on (0 disables).
 #
 # Advanced settings can be split across services.toml, policy.toml, and tuning.toml.
 #
@@ -112,7 +142,23 @@ func servicesConfigDocComments() []byte {
 # - [auth]: Clerk/OIDC endpoints and session cookie settings.
 # - [backblaze_backup]: Cloud backup service toggle, bucket, prefix, and cadence.
 # - [discord]: Discord integration endpoints/channels and worker notification threshold.
-# - [status]: UI external links (mempool_address_url, github_url).
+# - [discord].block_cooldown_seconds: Post at most one block-found notice per block hash within this window, however
+#   many paths report it (default 3600; 0 disables deduplication). A different hash at the same height still posts.
+# - [discord].worker_block_cooldown_seconds: Skip subscriber pings for a worker pinged about a block within this window;
+#   the channel notice still posts (0 disables, the default).
+# - [discord].worker_hashrate_alert_minutes: Ping when a connected saved worker with notifications on has had no
+#   hashrate for this many minutes, and again once it has been hashing for worker_notify_threshold_seconds (0 disables, the default).
+# - [status]: UI external links (mempool_address_url, github_url) and
+#   maintenance_mode ("off", "banner" or "page") with an optional
+#   maintenance_message shown to visitors while the node/Stratum is unhealthy.
+# - [status.operator_fields]: Optional static key/values (e.g. region = "eu", support_url = "...") returned
+#   under "operator" in /api/overview and /api/pool-page. Keys: letters, digits, '_' or '-'; values: string,
+#   number or boolean (at most 32 entries).
+# - [status].metrics_enabled: Serve Prometheus text-format metrics at /metrics on the status listeners (default false;
+#   requires restart).
+# - [status].metrics_push_addr: UDP host:port to push pool metrics to every metrics_push_interval_seconds
+#   (default 10). metrics_push_format is "influx" (InfluxDB line protocol, running totals; default) or "statsd"
+#   (counters as per-interval increases). Empty disables; off in offline_mode; requires restart.
 #
 `)
 }
@@ -120,6 +166,7 @@ func servicesConfigDocComments() []byte {
 func tuningConfigDocComments() []byte {
 	return []byte(`# Rate limits ([rate_limits])
 # - max_conns: Maximum simultaneous Stratum connections allowed (checked on accept; requires restart).
+# - max_conns_per_ip: Maximum simultaneous Stratum connections from one remote host; 0 disables (checked on accept).
 # - disable_connect_rate_limits: Disable accept/connect throttling entirely (intended for local-only pools on trusted networks; requires restart).
 # - auto_accept_rate_limits: When true, computes accept throttles from max_conns on startup (overrides explicit accept_* values; requires restart).
 # - max_accepts_per_second: Accepts/sec during the initial restart/reconnect window (requires restart).
@@ -130,7 +177,13 @@ func tuningConfigDocComments() []byte {
 # - accept_steady_state_rate: Accepts/sec once steady-state mode activates (requires restart).
 # - accept_steady_state_reconnect_percent: Expected % of miners reconnecting during normal operation (used for auto_accept_rate_limits; requires restart).
 # - accept_steady_state_reconnect_window: Seconds to spread expected steady-state reconnects across (used for auto_accept_rate_limits; requires restart).
+# - accept_rpc_latency_backoff_ms: Scale the accept rate down while the getblocktemplate latency average is above this many
+#   ms, and back up once it falls below 75% of it (0 disables, the default; requires restart).
+# - accept_rpc_latency_min_percent: Lowest accept rate the latency backoff may reach, as % of the configured rate (default 10).
 # - stratum_messages_per_minute: Per-connection Stratum messages/min before disconnect (0 disables; requires restart).
+# - status_requests_per_second / status_max_inflight_requests: Answer status HTTP requests with 429 beyond this
+#   pool-wide rate (burst of 2x) or number of concurrent requests, so a flood cannot starve Stratum. /admin, /status.txt
+#   and login/logout are exempt (0 disables each, the default; requires restart).
 #
 # Difficulty ([difficulty])
 # - default_difficulty: Fallback difficulty if no suggest_* arrives during the startup delay; 0 means "use min_difficulty" (or the built-in minimum if min_difficulty=0).
@@ -138,12 +191,36 @@ func tuningConfigDocComments() []byte {
 # - min_difficulty / max_difficulty: VarDiff clamp for miner connections; 0 disables that clamp (no limit; requires restart).
 # - lock_suggested_difficulty: If true, the first mining.suggest_difficulty / mining.suggest_target locks that connection to the suggested difficulty (disables VarDiff; requires restart).
 # - enforce_suggested_difficulty_limits: If true, ban/disconnect when miner-suggested difficulty is outside min_difficulty/max_difficulty.
+# - share_flood_shares_per_min: Per-connection submit rate that triggers a temporary difficulty floor sized to bring the connection back to target_shares_per_min (0 disables, the default; 600 is a reasonable starting point). Must be more than twice target_shares_per_min.
+# - share_flood_hold_seconds: How long a share-flood floor stays in place after the flood stops before vardiff may lower difficulty again (default 300).
+# - vardiff_stale_feedback_percent: When a connection's recent stale-share rate exceeds this percent, vardiff aims below its cadence target (at most halving it). Only stale-job rejects count; malformed or bad-nonce rejects never lower difficulty. 0 disables (default).
+# - worker_difficulty_sync: When one worker name has several connections, each vardiff move is pulled toward the
+#   geometric mean of the worker's other connections so they converge on a shared difficulty. Skipped (with a
+#   one-time warning) when the rigs' hashrates differ by more than 4x, since mixed hardware is better served by
+#   independent vardiff. Default false.
+# - worker_overrides: Table of worker name -> pinned difficulty, e.g. "bc1q....rig1" = 65536 or "rig1.*" = 131072.
+#   Matching is case-insensitive against the full worker name and the part after the wallet; a trailing * matches
+#   a prefix, and exact names beat wildcards. Pinned connections skip vardiff and ignore suggest_* (min/max still
+#   apply). /admin/miners can pin a live miner and saves the table back here. Empty by default.
 #
 # Mining ([mining])
 # - extranonce2_size: Per-share extranonce2 byte length used for submit parsing and validation (requires restart).
 # - template_extra_nonce2_size: Template extranonce2 byte length used in generated jobs (requires restart).
 # - job_entropy: Entropy bytes added to per-job coinbase tags (requires restart).
 # - coinbase_scriptsig_max_bytes: Maximum allowed coinbase scriptSig size in bytes (requires restart).
+# - coinbase_tag_check_hours: How often to check that the coinbase tag still fits coinbase_scriptsig_max_bytes at the
+#   current height; logs and alerts when it starts or stops being truncated (0 disables, default 24).
+# - coinbase_max_bytes: Maximum serialized coinbase transaction size; jobs whose coinbase would exceed it are
+#   refused (0 = built-in 100000 byte ceiling, the default; lower values only).
+# - coinbase_upgrade_marker: Append the build version (or build time) to the coinbase tag until the first block found
+#   after a restart, then revert to the normal tag. Dropped whenever it would not fit coinbase_scriptsig_max_bytes (default false).
+# - coinbase_parts_cache: Serialize each job's coinbase outputs once per distinct payout set and reuse them for every
+#   connection paying to the same outputs (e.g. all pool-payout miners) instead of rebuilding them per notify (default false).
+# - script_time_max_drift_seconds: The coinbase scriptSig carries a time fixed when the job is built. When the template
+#   has not changed for this many seconds, reissue it as a new job (new job id, current time, clean_jobs=false) so
+#   that time stays close to the wall clock. Work already sent keeps its own time (0 disables, the default).
+# - coinbase_extra_op_return: Hex data (at most 80 bytes) for an extra zero-value OP_RETURN output appended after the
+#   payouts, e.g. a merged-mining tag or a signed operator message (default empty = no extra output).
 # - difficulty_step_granularity: Quantize difficulty to 2^(k/N) steps (N=1 power-of-two, N=4 quarter, N=10 tenth-step default). Higher values are finer; requires restart.
 #
 # Hashrate ([hashrate])
@@ -157,6 +234,19 @@ func tuningConfigDocComments() []byte {
 #
 # Stratum tuning ([stratum])
 # - tcp_read_buffer_bytes / tcp_write_buffer_bytes: Socket buffer sizes in bytes (0 = OS default; restart to apply).
+# - max_connection_lifetime_seconds: Send client.reconnect once a connection reaches this age (0 disables, the default; 86400 / 24h recommended when enabled).
+#   Each connection adds up to 25% random jitter so reconnects are staggered rather than synchronized.
+# - write_stall_timeout_seconds: Force-close a connection whose pending write has made no progress for this long (0 disables, the default; minimum 10).
+# - notify_heartbeat_seconds: Re-send the current job unchanged (clean_jobs=false) when no mining.notify has gone out for this long, for firmware that drops quiet connections between blocks (0 disables, the default; minimum 5).
+# - stats_notify_seconds: Push a non-standard goPool.stats notification (difficulty, accepted, rejected, hashrate) to
+#   each authorized miner this often, for firmware/dashboards that display pool stats (0 disables, the default; 30-3600).
+# - share_listener_labels: Count shares per Stratum listener (tcp, tls, proxy, ws) in /metrics and list each worker's
+#   listeners in the worker JSON (default false).
+#
+# Memory ([memory])
+# - budget_mb: Process memory budget in MiB (0 disables, the default). Also set as the Go runtime soft memory limit.
+#   At 80% of the budget new miner connections are refused; at 90% retained jobs and duplicate-share caches are
+#   shortened as well. Existing miners keep hashing, and found-block and accounting data are never dropped.
 #
 #
 `)
@@ -165,25 +255,98 @@ func tuningConfigDocComments() []byte {
 func policyConfigDocComments() []byte {
 	return []byte(`# Stratum policy ([stratum])
 # - ckpool_emulate: CKPool-style subscribe response compatibility shape.
+# - subscribe_pow_bits / subscribe_pow_bits_tls: Require a proof-of-work
+#   challenge (leading zero bits) before mining.subscribe on the plain/TLS
+#   listener. 0 disables. Standard miner firmware cannot connect when enabled.
+# - gate_on_network_inactive: Refuse/disconnect miners while the node reports networkactive=false
+#   (setnetworkactive false). Regtest is exempt. The condition is always logged; default false.
+# - replace_stale_worker_connections: When a reconnect authorizes the same worker from the same IP and resumes
+#   the old subscribe session ID, close the leftover connection. Default false.
+# - allow_authorize_before_subscribe: Accept mining.authorize sent before mining.subscribe (some non-conforming
+#   clients do this); work still starts only after subscribe. When false, such an authorize gets error 25
+#   "not subscribed". Default false.
+# - track_transport_changes: Remember whether each worker last authorized over plain TCP or TLS and count/log
+#   reconnects that switch (TLS to TCP is logged as a downgrade warning). Never refuses a connection. Default false.
+# - bad_id_policy: Requests whose JSON-RPC id is missing or not a string/number: "compat" handles them and replies
+#   with id null, "ignore" drops them, "reject" replies with a -32600 error. Requests with id null are notifications
+#   and never get a reply. Default "compat".
+# - first_job_alert_seconds: Alert (log, error history, Discord notice channel) when no job template is available
+#   this many seconds after the Stratum listeners come up. Default 0 (disabled).
+# - first_job_alert_ibd_seconds: Longer limit used while the node reports IBD/syncing. Default 0 (no alert while
+#   the node is still syncing).
+# - worker_connection_history_seconds: Keep each worker's connects/disconnects for this many seconds and show its
+#   reconnect count and connection durations on the worker page. A worker with no connection changes for a full
+#   window starts over from zero. Default 0 (disabled).
 #
 # Mining policy ([mining])
 # - share_job_freshness_mode: 0=off, 1=job_id, 2=job_id+prevhash.
 # - share_check_ntime_window: Enforce nTime policy window.
+# - share_ntime_refresh_current_job: Keep moving the current job's nTime max forward with real time (now +
+#   share_ntime_max_forward_seconds) so long-lived jobs on slow-block networks keep accepting shares. The
+#   template curtime/mintime floor and the job itself are unchanged. Default false.
 # - share_check_version_rolling: Enforce version-rolling policy.
 # - share_require_authorized_connection: Require authorized connection for submit.
 # - share_check_param_format: Enforce submit parameter format checks.
 # - share_require_worker_match: Require submit worker matches authorized worker.
+# - share_require_subscribed_connection: Reject submits sent before mining.subscribe with error 25 "not subscribed"
+#   instead of letting them fail job lookup (default false).
 # - submit_process_inline: Process mining.submit inline on connection goroutine.
+# - submit_panic_disconnect_after: A submit whose processing panics is rejected with "internal error" and the worker keeps
+#   running; a connection is dropped after this many such panics (default 3, 0 never disconnects).
 # - share_check_duplicate: Enable duplicate share checks.
+# - share_max_job_age_seconds: Reject shares as stale when their job was created longer ago than this, even if the job id
+#   is still known. Keep it well above the block interval (e.g. 3600); block-solving shares are still submitted (default 0, off).
+# - required_template_txids: Txids (hex) the node must include in block templates.
+#   Missing txids are only alerted on; jobs still use the node's template.
+# - coinbase_dust_threshold_sats: Pool-fee/donation coinbase outputs below this many sats are folded into
+#   the output they were split from (donation -> pool fee -> worker) so no dust output is created
+#   (default 0, off; 546 covers every standard output type).
+# - coinbase_payout_mode: "auto" (default) picks per worker: single output to the worker when pool_fee_percent is 0,
+#   single output to the pool when the worker authorizes as the pool payout address, otherwise dual (fee + worker)
+#   or triple (with operator donation). "single_pool" always pays one output to payout_address.
+# - invalid_wallet_fallback_to_pool: Let workers whose name is not a valid address mine to the pool
+#   payout_address instead of being disconnected (default false; changes who gets paid).
+# - record_reward_distribution: Store each found block's coinbase txid and decoded outputs (address, value, role)
+#   in the found-blocks log and serve them from /api/blocks/detail (default false).
+# - payout_address_check_interval_seconds: Periodically re-check payout_address with the node's validateaddress
+#   and alert if it is reported invalid or its scriptPubKey differs from the pool's (0 disables, the default;
+#   minimum 60). Alerts only; the payout script is never changed at runtime.
+# - near_miss_factor: Count accepted shares whose difficulty is within this factor of the network difficulty as
+#   near-misses for luck analysis (e.g. 10 = at least 1/10 of network diff; 0 disables, the default). Block-solving
+#   shares are never counted as near-misses.
+# - accounting_recovery_file: If the accounting flush fails at shutdown, save the unwritten found-block records to
+#   state/accounting_recovery.jsonl and replay them (skipping ones already stored) on the next start (default false).
+# - template_allow_confirmed_reorg: Switch to a template at a lower height than the current job only when the node
+#   reports the current job's parent block is no longer on its active chain (a genuine reorg). Otherwise lower-height
+#   templates (e.g. from a lagging backup node) are always refused (default false).
+# - degraded_feed_share_policy: How shares are handled while template refreshes are failing but the last job is still
+#   being served. "lenient" (default) accepts and credits them; "strict" rejects them as stale. Shares that solve a
+#   block are always submitted to the node either way.
+# - saved_worker_static_difficulty: Let signed-in users set a static difficulty on their saved workers; it is applied
+#   on authorize (clamped to min/max) and turns vardiff off for that connection. A value is only used after the rig
+#   proves its owner by sending owner=<token> in its password. An admin worker_overrides entry wins. Default false.
+# - dedupe_block_submissions: When two connections solve the same height at nearly the same time, submit only the
+#   first block and record the other as also-found instead of submitting a competing block. Default false.
 #
 # Hashrate policy ([hashrate])
 # - share_ntime_max_forward_seconds: max allowed forward nTime skew.
+# - hashrate_drop_alert_percent: Alert when pool hashrate or connection count falls at least this many percent
+#   below its peak in the window (e.g. 50; 0 disables, the default). Logged, added to error history and posted to
+#   the Discord notify channel with before/after numbers. Shutdown drains never alert.
+# - hashrate_drop_alert_window_seconds: Peak lookback window (default 600).
+# - hashrate_drop_alert_debounce_seconds: How long the drop must persist before alerting (default 120).
 #
 # Version policy ([version])
 # - min_version_bits
 # - share_allow_version_mask_mismatch: allow miners to submit version bits
 #   outside the negotiated version-rolling mask (useful for BIP-110 bit 4 signaling).
 # - share_allow_degraded_version_bits
+# - version_mask_resync_cooldown_seconds: When a miner submits version bits outside its negotiated mask, re-send
+#   mining.set_version_mask (at most once per cooldown) instead of counting the reject toward a ban. If the miner is
+#   still out of mask 10s after the push, shares are rejected normally. 0 disables (default).
+# - share_version_convention_lock: Once a connection has sent 16 consecutive version-rolled submits in one
+#   convention (a delta inside the mask, or a full version), keep interpreting its submits that way so a single
+#   anomalous value cannot flip delta/full handling. Default false.
 # - bip110_enabled: set BIP-110 signaling bit 4 on generated templates.
 #   Reference: https://github.com/bitcoin/bips/blob/master/bip-0110.mediawiki
 #   Note: version_bits.toml is applied after this flag and can still force
@@ -191,9 +354,25 @@ func policyConfigDocComments() []byte {
 #
 # Timeouts ([timeouts])
 # - connection_timeout_seconds
+# - tls_initial_timeout_seconds: Read window for TLS miners before their first accepted shares (subscribe/authorize),
+#   counted from the end of the TLS handshake (0 = same 90s window as plain TCP, the default).
+# - longpoll_timeout_seconds: Abandon a getblocktemplate longpoll that has not returned for this long, log the
+#   stall and fetch a fresh template (0 waits indefinitely, the default; minimum 300).
 #
 # Bans ([bans])
 # - invalid-submit and reconnect ban thresholds/windows.
+# - source_port_churn_per_min: Ban an IP (for reconnect_ban_duration_seconds) when it uses more than this many
+#   distinct source ports within one minute (0 disables, the default). With reconnect_ban_duration_seconds = 0 the
+#   churn is only logged, once per minute per IP. Keep it well above what NAT gateways produce.
+# - nonce_check_min_samples: Judge each connection's submit nonces in batches of this size and warn when a batch
+#   repeats or clusters in a narrow range (0 disables, the default; values below 8 are raised to 8).
+# - nonce_check_ban_after: Ban after this many consecutive degenerate batches (0 = alert only, the default).
+# - allowed_miner_types: Strict mode for private pools. When non-empty, mining.subscribe must send a client ID whose
+#   full value or name without version (cgminer for cgminer/4.10) matches an entry, case-insensitively; subscribes with a missing, empty or
+#   unlisted ID are refused and logged with the ID they sent (empty = allow all, the default).
+# - ban_feed_source: URL (http/https) or file path of an IP/CIDR ban list, one entry per line, imported every
+#   ban_feed_refresh_seconds (default 3600, minimum 60). Imported bans are kept separate from worker bans and a failed
+#   refresh keeps the last imported list (empty disables, the default; URLs are skipped in offline mode).
 #
 `)
 }
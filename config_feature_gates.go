@@ -2,20 +2,29 @@ package main
 
 import "strings"
 
+// The gates below report false in offline mode, which turns off every
+// outbound service they guard.
+
 func clerkConfigured(cfg Config) bool {
-	return strings.TrimSpace(cfg.ClerkSecretKey) != "" &&
+	return !cfg.OfflineMode &&
+		strings.TrimSpace(cfg.ClerkSecretKey) != "" &&
 		strings.TrimSpace(cfg.ClerkPublishableKey) != "" &&
 		strings.TrimSpace(cfg.ClerkFrontendAPIURL) != ""
 }
 
 func discordConfigured(cfg Config) bool {
-	return strings.TrimSpace(cfg.DiscordServerID) != "" &&
+	return !cfg.OfflineMode &&
+		strings.TrimSpace(cfg.DiscordServerID) != "" &&
 		strings.TrimSpace(cfg.DiscordBotToken) != "" &&
 		strings.TrimSpace(cfg.DiscordNotifyChannelID) != ""
 }
 
+func metricsPushConfigured(cfg Config) bool {
+	return !cfg.OfflineMode && strings.TrimSpace(cfg.MetricsPushAddr) != ""
+}
+
 func backblazeCloudConfigured(cfg Config) bool {
-	if !cfg.BackblazeBackupEnabled {
+	if !cfg.BackblazeBackupEnabled || cfg.OfflineMode {
 		return false
 	}
 	return strings.TrimSpace(cfg.BackblazeBucket) != "" &&
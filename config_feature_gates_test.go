@@ -0,0 +1,37 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOfflineModeDisablesOutboundServices(t *testing.T) {
+	cfg := Config{
+		ClerkSecretKey:          "sk",
+		ClerkPublishableKey:     "pk",
+		ClerkFrontendAPIURL:     "https://clerk.example",
+		DiscordServerID:         "1",
+		DiscordBotToken:         "token",
+		DiscordNotifyChannelID:  "2",
+		BackblazeBackupEnabled:  true,
+		BackblazeBucket:         "bucket",
+		BackblazeAccountID:      "account",
+		BackblazeApplicationKey: "key",
+	}
+	if !clerkConfigured(cfg) || !discordConfigured(cfg) || !backblazeCloudConfigured(cfg) {
+		t.Fatalf("expected services to be configured when online")
+	}
+	cfg.OfflineMode = true
+	if clerkConfigured(cfg) || discordConfigured(cfg) || backblazeCloudConfigured(cfg) {
+		t.Fatalf("expected offline mode to disable clerk, discord and backblaze")
+	}
+
+	p := NewPriceService()
+	p.SetOffline(true)
+	if _, err := p.BTCPrice("usd"); !errors.Is(err, errPriceOffline) {
+		t.Fatalf("expected offline price error, got %v", err)
+	}
+	if !p.LastUpdate().IsZero() {
+		t.Fatalf("offline lookups must not record a fetch")
+	}
+}
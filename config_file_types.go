@@ -5,6 +5,8 @@ type serverConfig struct {
 	StatusListen    string  `toml:"status_listen"`
 	StatusTLSListen *string `toml:"status_tls_listen"` // nil = default, "" = disabled
 	StatusPublicURL string  `toml:"status_public_url"`
+	OfflineMode     bool    `toml:"offline_mode"`
+	WatchTuningFile bool    `toml:"watch_tuning_file"`
 }
 
 type brandingConfig struct {
@@ -16,6 +18,7 @@ type brandingConfig struct {
 	FiatCurrency                    string `toml:"fiat_currency"`
 	PoolDonationAddress             string `toml:"pool_donation_address"`
 	ServerLocation                  string `toml:"server_location"`
+	DisplayTimezone                 string `toml:"display_timezone"`
 }
 
 // brandingConfigRead includes legacy fields that used to live under [branding]
@@ -29,6 +32,7 @@ type brandingConfigRead struct {
 	FiatCurrency                    string `toml:"fiat_currency"`
 	PoolDonationAddress             string `toml:"pool_donation_address"`
 	ServerLocation                  string `toml:"server_location"`
+	DisplayTimezone                 string `toml:"display_timezone"`
 	DiscordURL                      string `toml:"discord_url"`
 	DiscordServerID                 string `toml:"discord_server_id"`
 	DiscordNotifyChannelID          string `toml:"discord_notify_channel_id"`
@@ -36,6 +40,11 @@ type brandingConfigRead struct {
 
 type stratumConfig struct {
 	StratumTLSListen       string `toml:"stratum_tls_listen"`
+	StratumTLSClientCA     string `toml:"stratum_tls_client_ca"`
+	StratumProxyListen     string `toml:"stratum_proxy_listen"`
+	StratumWebSocketPath   string `toml:"stratum_websocket_path"`
+	StratumWSListen        string `toml:"stratum_ws_listen"`
+	StratumReusePort       bool   `toml:"stratum_reuse_port"`
 	StratumPasswordEnabled bool   `toml:"stratum_password_enabled"`
 	StratumPassword        string `toml:"stratum_password"`
 	StratumPasswordPublic  bool   `toml:"stratum_password_public"`
@@ -58,6 +67,7 @@ type nodeConfig struct {
 	ZMQHashBlockAddr string `toml:"zmq_hashblock_addr"`
 	ZMQRawBlockAddr  string `toml:"zmq_rawblock_addr"`
 	RPCCookiePath    string `toml:"rpc_cookie_path"`
+	RPCCookieRetry   bool   `toml:"rpc_cookie_retry"`
 }
 
 type nodeConfigRead struct {
@@ -67,11 +77,20 @@ type nodeConfigRead struct {
 	ZMQHashBlockAddr   string `toml:"zmq_hashblock_addr"`
 	ZMQRawBlockAddr    string `toml:"zmq_rawblock_addr"`
 	RPCCookiePath      string `toml:"rpc_cookie_path"`
+	RPCCookieRetry     bool   `toml:"rpc_cookie_retry"`
 }
 
 type loggingConfig struct {
-	Debug    *bool `toml:"debug"`
-	NetDebug *bool `toml:"net_debug"`
+	Debug                      *bool    `toml:"debug"`
+	NetDebug                   *bool    `toml:"net_debug"`
+	TraceSamplePercent         *float64 `toml:"trace_sample_percent"`
+	RetentionDays              *int     `toml:"retention_days"`
+	RetentionFiles             *int     `toml:"retention_files"`
+	CompressRotated            *bool    `toml:"compress_rotated"`
+	AutoProfileGoroutines      *int     `toml:"auto_profile_goroutines"`
+	AutoProfileHeapMB          *int     `toml:"auto_profile_heap_mb"`
+	AutoProfileIntervalSeconds *int     `toml:"auto_profile_interval_seconds"`
+	Format                     *string  `toml:"format"`
 }
 
 type backblazeBackupConfig struct {
@@ -92,6 +111,8 @@ type miningConfig struct {
 	OperatorDonationURL     string   `toml:"operator_donation_url"`
 	PoolEntropy             *string  `toml:"pool_entropy"`
 	PoolTagPrefix           string   `toml:"pooltag_prefix"`
+
+	PayoutSplits []PayoutSplit `toml:"payout_splits,omitempty"`
 }
 
 type baseFileConfig struct {
@@ -119,11 +140,24 @@ type servicesDiscordConfig struct {
 	DiscordServerID              string `toml:"discord_server_id"`
 	DiscordNotifyChannelID       string `toml:"discord_notify_channel_id"`
 	WorkerNotifyThresholdSeconds *int   `toml:"worker_notify_threshold_seconds"`
+	BlockCooldownSeconds         *int   `toml:"block_cooldown_seconds"`
+	WorkerBlockCooldownSeconds   *int   `toml:"worker_block_cooldown_seconds"`
+	WorkerHashrateAlertMinutes   *int   `toml:"worker_hashrate_alert_minutes"`
 }
 
 type servicesStatusConfig struct {
-	MempoolAddressURL string `toml:"mempool_address_url"`
-	GitHubURL         string `toml:"github_url"`
+	MempoolAddressURL  string `toml:"mempool_address_url"`
+	GitHubURL          string `toml:"github_url"`
+	MaintenanceMode    string `toml:"maintenance_mode"`
+	MaintenanceMessage string `toml:"maintenance_message"`
+	// OperatorFields are static key/values echoed under "operator" in the
+	// status JSON APIs.
+	OperatorFields map[string]any `toml:"operator_fields,omitempty"`
+	MetricsEnabled bool           `toml:"metrics_enabled"`
+	// MetricsPush* send PoolMetrics to a UDP InfluxDB/StatsD endpoint.
+	MetricsPushAddr            string `toml:"metrics_push_addr"`
+	MetricsPushFormat          string `toml:"metrics_push_format"`
+	MetricsPushIntervalSeconds *int   `toml:"metrics_push_interval_seconds"`
 }
 
 type servicesFileConfig struct {
@@ -135,6 +169,7 @@ type servicesFileConfig struct {
 
 type rateLimitTuning struct {
 	MaxConns                          *int     `toml:"max_conns"`
+	MaxConnsPerIP                     *int     `toml:"max_conns_per_ip"`
 	MaxAcceptsPerSecond               *int     `toml:"max_accepts_per_second"`
 	MaxAcceptBurst                    *int     `toml:"max_accept_burst"`
 	DisableConnectRateLimits          *bool    `toml:"disable_connect_rate_limits"`
@@ -145,11 +180,17 @@ type rateLimitTuning struct {
 	AcceptSteadyStateRate             *int     `toml:"accept_steady_state_rate"`
 	AcceptSteadyStateReconnectPercent *float64 `toml:"accept_steady_state_reconnect_percent"`
 	AcceptSteadyStateReconnectWindow  *int     `toml:"accept_steady_state_reconnect_window"`
+	AcceptRPCLatencyBackoffMs         *int     `toml:"accept_rpc_latency_backoff_ms"`
+	AcceptRPCLatencyMinPercent        *float64 `toml:"accept_rpc_latency_min_percent"`
 	StratumMessagesPerMinute          *int     `toml:"stratum_messages_per_minute"`
+	StatusRequestsPerSecond           *int     `toml:"status_requests_per_second"`
+	StatusMaxInflightRequests         *int     `toml:"status_max_inflight_requests"`
 }
 
 type timeoutTuning struct {
 	ConnectionTimeoutSec *int `toml:"connection_timeout_seconds"`
+	TLSInitialTimeoutSec *int `toml:"tls_initial_timeout_seconds"`
+	LongpollTimeoutSec   *int `toml:"longpoll_timeout_seconds"`
 }
 
 type difficultyTuning struct {
@@ -160,6 +201,12 @@ type difficultyTuning struct {
 	VarDiffEnabled                   *bool    `toml:"vardiff_enabled"`
 	LockSuggestedDifficulty          *bool    `toml:"lock_suggested_difficulty"`
 	EnforceSuggestedDifficultyLimits *bool    `toml:"enforce_suggested_difficulty_limits"`
+	ShareFloodSharesPerMin           *float64 `toml:"share_flood_shares_per_min"`
+	ShareFloodHoldSeconds            *int     `toml:"share_flood_hold_seconds"`
+	VarDiffStaleFeedbackPercent      *float64 `toml:"vardiff_stale_feedback_percent"`
+	WorkerDifficultySync             *bool    `toml:"worker_difficulty_sync"`
+
+	WorkerOverrides map[string]float64 `toml:"worker_overrides"`
 }
 
 type miningTuning struct {
@@ -167,8 +214,15 @@ type miningTuning struct {
 	TemplateExtraNonce2Size   *int  `toml:"template_extra_nonce2_size"`
 	JobEntropy                *int  `toml:"job_entropy"`
 	CoinbaseScriptSigMaxBytes *int  `toml:"coinbase_scriptsig_max_bytes"`
+	CoinbaseTagCheckHours     *int  `toml:"coinbase_tag_check_hours"`
+	CoinbaseMaxBytes          *int  `toml:"coinbase_max_bytes"`
+	CoinbaseUpgradeMarker     *bool `toml:"coinbase_upgrade_marker"`
+	CoinbasePartsCache        *bool `toml:"coinbase_parts_cache"`
+	ScriptTimeMaxDriftSeconds *int  `toml:"script_time_max_drift_seconds"`
 	DisablePoolJobEntropy     *bool `toml:"disable_pool_job_entropy"`
 	DifficultyStepGranularity *int  `toml:"difficulty_step_granularity"`
+
+	CoinbaseExtraOpReturn *string `toml:"coinbase_extra_op_return"`
 }
 
 type hashrateTuning struct {
@@ -193,7 +247,13 @@ type banTuning struct {
 	ReconnectBanThreshold            *int     `toml:"reconnect_ban_threshold"`
 	ReconnectBanWindowSeconds        *int     `toml:"reconnect_ban_window_seconds"`
 	ReconnectBanDurationSeconds      *int     `toml:"reconnect_ban_duration_seconds"`
+	SourcePortChurnPerMin            *int     `toml:"source_port_churn_per_min"`
+	NonceCheckMinSamples             *int     `toml:"nonce_check_min_samples"`
+	NonceCheckBanAfter               *int     `toml:"nonce_check_ban_after"`
 	BannedMinerTypes                 []string `toml:"banned_miner_types"`
+	AllowedMinerTypes                []string `toml:"allowed_miner_types"`
+	BanFeedSource                    string   `toml:"ban_feed_source"`
+	BanFeedRefreshSeconds            *int     `toml:"ban_feed_refresh_seconds"`
 }
 
 type versionTuning struct {
@@ -201,6 +261,8 @@ type versionTuning struct {
 	ShareAllowVersionMaskMismatch *bool `toml:"share_allow_version_mask_mismatch"`
 	ShareAllowDegradedVersionBits *bool `toml:"share_allow_degraded_version_bits"`
 	BIP110Enabled                 *bool `toml:"bip110_enabled"`
+	VersionMaskResyncCooldownSec  *int  `toml:"version_mask_resync_cooldown_seconds"`
+	ShareVersionConventionLock    *bool `toml:"share_version_convention_lock"`
 }
 
 // fileOverrideConfig groups override sections used internally when applying
@@ -217,22 +279,51 @@ type fileOverrideConfig struct {
 }
 
 type policyMiningConfig struct {
-	ShareJobFreshnessMode            *int  `toml:"share_job_freshness_mode"`
-	ShareCheckNTimeWindow            *bool `toml:"share_check_ntime_window"`
-	ShareCheckVersionRolling         *bool `toml:"share_check_version_rolling"`
-	ShareRequireAuthorizedConnection *bool `toml:"share_require_authorized_connection"`
-	ShareCheckParamFormat            *bool `toml:"share_check_param_format"`
-	ShareRequireWorkerMatch          *bool `toml:"share_require_worker_match"`
-	SubmitProcessInline              *bool `toml:"submit_process_inline"`
-	ShareCheckDuplicate              *bool `toml:"share_check_duplicate"`
+	ShareJobFreshnessMode            *int     `toml:"share_job_freshness_mode"`
+	ShareCheckNTimeWindow            *bool    `toml:"share_check_ntime_window"`
+	ShareNTimeRefreshCurrentJob      *bool    `toml:"share_ntime_refresh_current_job"`
+	ShareCheckVersionRolling         *bool    `toml:"share_check_version_rolling"`
+	ShareRequireAuthorizedConnection *bool    `toml:"share_require_authorized_connection"`
+	ShareCheckParamFormat            *bool    `toml:"share_check_param_format"`
+	ShareRequireWorkerMatch          *bool    `toml:"share_require_worker_match"`
+	ShareRequireSubscribedConnection *bool    `toml:"share_require_subscribed_connection"`
+	SubmitProcessInline              *bool    `toml:"submit_process_inline"`
+	SubmitPanicDisconnectAfter       *int     `toml:"submit_panic_disconnect_after"`
+	ShareCheckDuplicate              *bool    `toml:"share_check_duplicate"`
+	ShareMaxJobAgeSeconds            *int     `toml:"share_max_job_age_seconds"`
+	RequiredTemplateTxids            []string `toml:"required_template_txids"`
+	CoinbaseDustThresholdSats        *int64   `toml:"coinbase_dust_threshold_sats"`
+	InvalidWalletFallbackToPool      *bool    `toml:"invalid_wallet_fallback_to_pool"`
+	CoinbasePayoutMode               *string  `toml:"coinbase_payout_mode"`
+	RecordRewardDistribution         *bool    `toml:"record_reward_distribution"`
+	PayoutAddressCheckIntervalSec    *int     `toml:"payout_address_check_interval_seconds"`
+	NearMissFactor                   *float64 `toml:"near_miss_factor"`
+	AccountingRecoveryFile           *bool    `toml:"accounting_recovery_file"`
+	TemplateAllowConfirmedReorg      *bool    `toml:"template_allow_confirmed_reorg"`
+	DegradedFeedSharePolicy          *string  `toml:"degraded_feed_share_policy"`
+	SavedWorkerStaticDifficulty      *bool    `toml:"saved_worker_static_difficulty"`
+	DedupeBlockSubmissions           *bool    `toml:"dedupe_block_submissions"`
 }
 
 type policyHashrateConfig struct {
-	ShareNTimeMaxForwardSeconds *int `toml:"share_ntime_max_forward_seconds"`
+	ShareNTimeMaxForwardSeconds      *int     `toml:"share_ntime_max_forward_seconds"`
+	HashrateDropAlertPercent         *float64 `toml:"hashrate_drop_alert_percent"`
+	HashrateDropAlertWindowSeconds   *int     `toml:"hashrate_drop_alert_window_seconds"`
+	HashrateDropAlertDebounceSeconds *int     `toml:"hashrate_drop_alert_debounce_seconds"`
 }
 
 type policyStratumConfig struct {
-	CKPoolEmulate *bool `toml:"ckpool_emulate"`
+	CKPoolEmulate                 *bool   `toml:"ckpool_emulate"`
+	SubscribePoWBits              *int    `toml:"subscribe_pow_bits"`
+	SubscribePoWBitsTLS           *int    `toml:"subscribe_pow_bits_tls"`
+	GateOnNetworkInactive         *bool   `toml:"gate_on_network_inactive"`
+	ReplaceStaleWorkerConnections *bool   `toml:"replace_stale_worker_connections"`
+	AllowAuthorizeBeforeSubscribe *bool   `toml:"allow_authorize_before_subscribe"`
+	TrackTransportChanges         *bool   `toml:"track_transport_changes"`
+	BadIDPolicy                   *string `toml:"bad_id_policy"`
+	FirstJobAlertSeconds          *int    `toml:"first_job_alert_seconds"`
+	FirstJobAlertIBDSeconds       *int    `toml:"first_job_alert_ibd_seconds"`
+	WorkerConnHistorySeconds      *int    `toml:"worker_connection_history_seconds"`
 }
 
 type policyFileConfig struct {
@@ -252,8 +343,18 @@ type tuningHashrateConfig struct {
 }
 
 type tuningStratumConfig struct {
-	TCPReadBufferBytes  *int `toml:"tcp_read_buffer_bytes"`
-	TCPWriteBufferBytes *int `toml:"tcp_write_buffer_bytes"`
+	TCPReadBufferBytes           *int `toml:"tcp_read_buffer_bytes"`
+	TCPWriteBufferBytes          *int `toml:"tcp_write_buffer_bytes"`
+	MaxConnectionLifetimeSeconds *int `toml:"max_connection_lifetime_seconds"`
+	WriteStallTimeoutSeconds     *int `toml:"write_stall_timeout_seconds"`
+	NotifyHeartbeatSeconds       *int `toml:"notify_heartbeat_seconds"`
+	StatsNotifySeconds           *int `toml:"stats_notify_seconds"`
+	// Per-listener share accounting; see Config.ShareListenerLabels.
+	ShareListenerLabels *bool `toml:"share_listener_labels"`
+}
+
+type tuningMemoryConfig struct {
+	BudgetMB *int `toml:"budget_mb"`
 }
 
 type tuningFileConfig struct {
@@ -263,6 +364,7 @@ type tuningFileConfig struct {
 	Hashrate     tuningHashrateConfig `toml:"hashrate"`
 	Stratum      tuningStratumConfig  `toml:"stratum"`
 	PeerCleaning peerCleaningTuning   `toml:"peer_cleaning"`
+	Memory       tuningMemoryConfig   `toml:"memory"`
 }
 
 type versionBitOverride struct {
@@ -285,4 +387,5 @@ type secretsConfig struct {
 	ClerkPublishableKey     string `toml:"clerk_publishable_key"`
 	BackblazeAccountID      string `toml:"backblaze_account_id"`
 	BackblazeApplicationKey string `toml:"backblaze_application_key"`
+	StratumProxyHMACSecret  string `toml:"stratum_proxy_hmac_secret"`
 }
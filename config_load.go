@@ -40,7 +40,9 @@ func loadConfig(configPath, secretsPath string) (Config, string) {
 
 		os.Exit(1)
 	}
-	ensureExampleFiles(cfg.DataDir)
+	if !configCheckOnly {
+		ensureExampleFiles(cfg.DataDir)
+	}
 
 	if cfg.PoolEntropy == "" {
 		cfg.PoolEntropy = generatePoolEntropy()
@@ -55,7 +57,7 @@ func loadConfig(configPath, secretsPath string) (Config, string) {
 		logger.Info("loaded miner blacklist", "path", blacklistPath, "count", len(entries))
 	}
 
-	if needsRewrite && configFileExisted {
+	if needsRewrite && configFileExisted && !configCheckOnly {
 		if err := rewriteConfigFile(configPath, cfg); err != nil {
 			logger.Warn("rewrite config file", "path", configPath, "error", err)
 		} else if cfg.PoolEntropy != "" {
@@ -119,7 +121,7 @@ func loadConfig(configPath, secretsPath string) (Config, string) {
 	// smoothly after pool restarts without hitting rate limits.
 	autoConfigureAcceptRateLimits(&cfg, runtimeOverrides, tuningConfigLoaded)
 
-	if needsServicesMigration && !servicesConfigLoaded && configFileExisted {
+	if needsServicesMigration && !servicesConfigLoaded && configFileExisted && !configCheckOnly {
 		if err := rewriteServicesFile(servicesPath, cfg); err != nil {
 			logger.Warn("rewrite services file", "path", servicesPath, "error", err)
 		} else {
@@ -185,7 +187,7 @@ func applyVersionBitsConfig(cfg *Config, fc versionBitsFileConfig) error {
 }
 
 func ensureSecretFilePermissions(path string) {
-	if strings.TrimSpace(path) == "" {
+	if strings.TrimSpace(path) == "" || configCheckOnly {
 		return
 	}
 	info, err := os.Stat(path)
@@ -221,6 +223,8 @@ func applyBaseConfig(cfg *Config, fc baseFileConfigRead) (configChanged bool, mi
 	if fc.Server.StatusPublicURL != "" {
 		cfg.StatusPublicURL = strings.TrimSpace(fc.Server.StatusPublicURL)
 	}
+	cfg.OfflineMode = fc.Server.OfflineMode
+	cfg.WatchTuningFile = fc.Server.WatchTuningFile
 	if fc.Branding.StatusBrandName != "" {
 		cfg.StatusBrandName = fc.Branding.StatusBrandName
 	}
@@ -245,6 +249,9 @@ func applyBaseConfig(cfg *Config, fc baseFileConfigRead) (configChanged bool, mi
 	if fc.Branding.ServerLocation != "" {
 		cfg.ServerLocation = strings.TrimSpace(fc.Branding.ServerLocation)
 	}
+	if fc.Branding.DisplayTimezone != "" {
+		cfg.DisplayTimezone = strings.TrimSpace(fc.Branding.DisplayTimezone)
+	}
 	if fc.Stratum.StratumTLSListen != "" {
 		addr := strings.TrimSpace(fc.Stratum.StratumTLSListen)
 		if addr != "" && !strings.Contains(addr, ":") {
@@ -252,6 +259,28 @@ func applyBaseConfig(cfg *Config, fc baseFileConfigRead) (configChanged bool, mi
 		}
 		cfg.StratumTLSListen = addr
 	}
+	if fc.Stratum.StratumTLSClientCA != "" {
+		cfg.StratumTLSClientCA = strings.TrimSpace(fc.Stratum.StratumTLSClientCA)
+	}
+	if addr := strings.TrimSpace(fc.Stratum.StratumProxyListen); addr != "" {
+		if !strings.Contains(addr, ":") {
+			addr = ":" + addr
+		}
+		cfg.StratumProxyListen = addr
+	}
+	if path := strings.TrimSpace(fc.Stratum.StratumWebSocketPath); path != "" {
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		cfg.StratumWebSocketPath = path
+	}
+	if addr := strings.TrimSpace(fc.Stratum.StratumWSListen); addr != "" {
+		if !strings.Contains(addr, ":") {
+			addr = ":" + addr
+		}
+		cfg.StratumWSListen = addr
+	}
+	cfg.StratumReusePort = fc.Stratum.StratumReusePort
 	cfg.StratumPasswordEnabled = fc.Stratum.StratumPasswordEnabled
 	if fc.Stratum.StratumPassword != "" {
 		cfg.StratumPassword = strings.TrimSpace(fc.Stratum.StratumPassword)
@@ -286,6 +315,7 @@ func applyBaseConfig(cfg *Config, fc baseFileConfigRead) (configChanged bool, mi
 	if cookiePath != "" {
 		cfg.RPCCookiePath = cookiePath
 	}
+	cfg.RPCCookieRetry = fc.Node.RPCCookieRetry
 	if fc.Mining.PoolFeePercent != nil {
 		cfg.PoolFeePercent = *fc.Mining.PoolFeePercent
 	}
@@ -307,12 +337,42 @@ func applyBaseConfig(cfg *Config, fc baseFileConfigRead) (configChanged bool, mi
 	if fc.Mining.PoolTagPrefix != "" {
 		cfg.PoolTagPrefix = filterAlphanumeric(strings.TrimSpace(fc.Mining.PoolTagPrefix))
 	}
+	if len(fc.Mining.PayoutSplits) > 0 {
+		cfg.PayoutSplits = make([]PayoutSplit, 0, len(fc.Mining.PayoutSplits))
+		for _, s := range fc.Mining.PayoutSplits {
+			cfg.PayoutSplits = append(cfg.PayoutSplits, PayoutSplit{Address: strings.TrimSpace(s.Address), Weight: s.Weight})
+		}
+	}
 	if fc.Logging.Debug != nil {
 		cfg.LogDebug = *fc.Logging.Debug
 	}
 	if fc.Logging.NetDebug != nil {
 		cfg.LogNetDebug = *fc.Logging.NetDebug
 	}
+	if fc.Logging.TraceSamplePercent != nil {
+		cfg.LogTraceSamplePercent = *fc.Logging.TraceSamplePercent
+	}
+	if fc.Logging.RetentionDays != nil {
+		cfg.LogRetentionDays = *fc.Logging.RetentionDays
+	}
+	if fc.Logging.RetentionFiles != nil {
+		cfg.LogRetentionFiles = *fc.Logging.RetentionFiles
+	}
+	if fc.Logging.CompressRotated != nil {
+		cfg.LogCompressRotated = *fc.Logging.CompressRotated
+	}
+	if fc.Logging.AutoProfileGoroutines != nil {
+		cfg.AutoProfileGoroutines = *fc.Logging.AutoProfileGoroutines
+	}
+	if fc.Logging.AutoProfileHeapMB != nil {
+		cfg.AutoProfileHeapMB = *fc.Logging.AutoProfileHeapMB
+	}
+	if fc.Logging.AutoProfileIntervalSeconds != nil {
+		cfg.AutoProfileMinInterval = time.Duration(*fc.Logging.AutoProfileIntervalSeconds) * time.Second
+	}
+	if fc.Logging.Format != nil {
+		cfg.LogFormat = strings.ToLower(strings.TrimSpace(*fc.Logging.Format))
+	}
 
 	// Legacy config.toml -> services.toml migration:
 	// old [auth], [backblaze_backup], and [branding].discord_* fields.
@@ -438,18 +498,47 @@ func applyServicesConfig(cfg *Config, fc servicesFileConfig) {
 	if fc.Discord.WorkerNotifyThresholdSeconds != nil && *fc.Discord.WorkerNotifyThresholdSeconds > 0 {
 		cfg.DiscordWorkerNotifyThresholdSeconds = *fc.Discord.WorkerNotifyThresholdSeconds
 	}
+	if fc.Discord.BlockCooldownSeconds != nil {
+		cfg.DiscordBlockCooldownSeconds = *fc.Discord.BlockCooldownSeconds
+	}
+	if fc.Discord.WorkerBlockCooldownSeconds != nil {
+		cfg.DiscordWorkerBlockCooldownSeconds = *fc.Discord.WorkerBlockCooldownSeconds
+	}
+	if fc.Discord.WorkerHashrateAlertMinutes != nil {
+		cfg.DiscordWorkerHashrateAlertMinutes = *fc.Discord.WorkerHashrateAlertMinutes
+	}
 	if strings.TrimSpace(fc.Status.MempoolAddressURL) != "" {
 		cfg.MempoolAddressURL = strings.TrimSpace(fc.Status.MempoolAddressURL)
 	}
 	if strings.TrimSpace(fc.Status.GitHubURL) != "" {
 		cfg.GitHubURL = strings.TrimSpace(fc.Status.GitHubURL)
 	}
+	if strings.TrimSpace(fc.Status.MaintenanceMode) != "" {
+		cfg.StatusMaintenanceMode = strings.ToLower(strings.TrimSpace(fc.Status.MaintenanceMode))
+	}
+	cfg.StatusMaintenanceMessage = strings.TrimSpace(fc.Status.MaintenanceMessage)
+	if fc.Status.MetricsEnabled {
+		cfg.MetricsEnabled = true
+	}
+	cfg.MetricsPushAddr = strings.TrimSpace(fc.Status.MetricsPushAddr)
+	if strings.TrimSpace(fc.Status.MetricsPushFormat) != "" {
+		cfg.MetricsPushFormat = strings.ToLower(strings.TrimSpace(fc.Status.MetricsPushFormat))
+	}
+	if fc.Status.MetricsPushIntervalSeconds != nil {
+		cfg.MetricsPushIntervalSeconds = *fc.Status.MetricsPushIntervalSeconds
+	}
+	if len(fc.Status.OperatorFields) > 0 {
+		cfg.OperatorFields = fc.Status.OperatorFields
+	}
 }
 
 func applyFileOverrides(cfg *Config, fc fileOverrideConfig) {
 	if fc.RateLimits.MaxConns != nil {
 		cfg.MaxConns = *fc.RateLimits.MaxConns
 	}
+	if fc.RateLimits.MaxConnsPerIP != nil {
+		cfg.MaxConnsPerIP = *fc.RateLimits.MaxConnsPerIP
+	}
 	if fc.RateLimits.MaxAcceptsPerSecond != nil {
 		cfg.MaxAcceptsPerSecond = *fc.RateLimits.MaxAcceptsPerSecond
 	}
@@ -480,12 +569,30 @@ func applyFileOverrides(cfg *Config, fc fileOverrideConfig) {
 	if fc.RateLimits.AcceptSteadyStateReconnectWindow != nil {
 		cfg.AcceptSteadyStateReconnectWindow = *fc.RateLimits.AcceptSteadyStateReconnectWindow
 	}
+	if fc.RateLimits.AcceptRPCLatencyBackoffMs != nil {
+		cfg.AcceptRPCLatencyBackoffMs = *fc.RateLimits.AcceptRPCLatencyBackoffMs
+	}
+	if fc.RateLimits.AcceptRPCLatencyMinPercent != nil {
+		cfg.AcceptRPCLatencyMinPercent = *fc.RateLimits.AcceptRPCLatencyMinPercent
+	}
 	if fc.RateLimits.StratumMessagesPerMinute != nil {
 		cfg.StratumMessagesPerMinute = *fc.RateLimits.StratumMessagesPerMinute
 	}
+	if fc.RateLimits.StatusRequestsPerSecond != nil {
+		cfg.StatusRequestsPerSecond = *fc.RateLimits.StatusRequestsPerSecond
+	}
+	if fc.RateLimits.StatusMaxInflightRequests != nil {
+		cfg.StatusMaxInflightRequests = *fc.RateLimits.StatusMaxInflightRequests
+	}
 	if fc.Timeouts.ConnectionTimeoutSec != nil {
 		cfg.ConnectionTimeout = time.Duration(*fc.Timeouts.ConnectionTimeoutSec) * time.Second
 	}
+	if fc.Timeouts.TLSInitialTimeoutSec != nil {
+		cfg.TLSInitialTimeout = time.Duration(*fc.Timeouts.TLSInitialTimeoutSec) * time.Second
+	}
+	if fc.Timeouts.LongpollTimeoutSec != nil {
+		cfg.LongpollTimeout = time.Duration(*fc.Timeouts.LongpollTimeoutSec) * time.Second
+	}
 	if fc.Difficulty.MaxDifficulty != nil {
 		cfg.MaxDifficulty = *fc.Difficulty.MaxDifficulty
 	}
@@ -507,6 +614,21 @@ func applyFileOverrides(cfg *Config, fc fileOverrideConfig) {
 	if fc.Difficulty.EnforceSuggestedDifficultyLimits != nil {
 		cfg.EnforceSuggestedDifficultyLimits = *fc.Difficulty.EnforceSuggestedDifficultyLimits
 	}
+	if fc.Difficulty.ShareFloodSharesPerMin != nil {
+		cfg.ShareFloodSharesPerMin = *fc.Difficulty.ShareFloodSharesPerMin
+	}
+	if fc.Difficulty.ShareFloodHoldSeconds != nil && *fc.Difficulty.ShareFloodHoldSeconds > 0 {
+		cfg.ShareFloodHold = time.Duration(*fc.Difficulty.ShareFloodHoldSeconds) * time.Second
+	}
+	if fc.Difficulty.VarDiffStaleFeedbackPercent != nil {
+		cfg.VarDiffStaleFeedbackPercent = *fc.Difficulty.VarDiffStaleFeedbackPercent
+	}
+	if fc.Difficulty.WorkerDifficultySync != nil {
+		cfg.WorkerDifficultySync = *fc.Difficulty.WorkerDifficultySync
+	}
+	if fc.Difficulty.WorkerOverrides != nil {
+		cfg.WorkerDifficultyOverrides = normalizeWorkerDifficultyOverrides(fc.Difficulty.WorkerOverrides)
+	}
 	if fc.Mining.DisablePoolJobEntropy != nil && *fc.Mining.DisablePoolJobEntropy {
 		// Disables coinbase "<pool entropy>-<job entropy>" suffix by bypassing
 		// the suffix builder (which is gated on JobEntropy > 0).
@@ -524,6 +646,24 @@ func applyFileOverrides(cfg *Config, fc fileOverrideConfig) {
 	if fc.Mining.CoinbaseScriptSigMaxBytes != nil {
 		cfg.CoinbaseScriptSigMaxBytes = *fc.Mining.CoinbaseScriptSigMaxBytes
 	}
+	if fc.Mining.CoinbaseTagCheckHours != nil {
+		cfg.CoinbaseTagCheckInterval = time.Duration(*fc.Mining.CoinbaseTagCheckHours) * time.Hour
+	}
+	if fc.Mining.CoinbaseMaxBytes != nil {
+		cfg.CoinbaseMaxBytes = *fc.Mining.CoinbaseMaxBytes
+	}
+	if fc.Mining.CoinbaseUpgradeMarker != nil {
+		cfg.CoinbaseUpgradeMarker = *fc.Mining.CoinbaseUpgradeMarker
+	}
+	if fc.Mining.CoinbasePartsCache != nil {
+		cfg.CoinbasePartsCache = *fc.Mining.CoinbasePartsCache
+	}
+	if fc.Mining.ScriptTimeMaxDriftSeconds != nil {
+		cfg.ScriptTimeMaxDriftSeconds = *fc.Mining.ScriptTimeMaxDriftSeconds
+	}
+	if fc.Mining.CoinbaseExtraOpReturn != nil {
+		cfg.CoinbaseExtraOpReturn = strings.ToLower(strings.TrimSpace(*fc.Mining.CoinbaseExtraOpReturn))
+	}
 	if fc.Mining.DifficultyStepGranularity != nil && *fc.Mining.DifficultyStepGranularity > 0 {
 		cfg.DifficultyStepGranularity = *fc.Mining.DifficultyStepGranularity
 	}
@@ -572,9 +712,27 @@ func applyFileOverrides(cfg *Config, fc fileOverrideConfig) {
 	if fc.Bans.ReconnectBanDurationSeconds != nil && *fc.Bans.ReconnectBanDurationSeconds > 0 {
 		cfg.ReconnectBanDurationSeconds = *fc.Bans.ReconnectBanDurationSeconds
 	}
+	if fc.Bans.SourcePortChurnPerMin != nil {
+		cfg.SourcePortChurnPerMin = *fc.Bans.SourcePortChurnPerMin
+	}
+	if fc.Bans.NonceCheckMinSamples != nil {
+		cfg.NonceCheckMinSamples = *fc.Bans.NonceCheckMinSamples
+	}
+	if fc.Bans.NonceCheckBanAfter != nil {
+		cfg.NonceCheckBanAfter = *fc.Bans.NonceCheckBanAfter
+	}
 	if fc.Bans.BannedMinerTypes != nil {
 		cfg.BannedMinerTypes = fc.Bans.BannedMinerTypes
 	}
+	if fc.Bans.AllowedMinerTypes != nil {
+		cfg.AllowedMinerTypes = fc.Bans.AllowedMinerTypes
+	}
+	if src := strings.TrimSpace(fc.Bans.BanFeedSource); src != "" {
+		cfg.BanFeedSource = src
+	}
+	if fc.Bans.BanFeedRefreshSeconds != nil {
+		cfg.BanFeedRefreshSeconds = *fc.Bans.BanFeedRefreshSeconds
+	}
 	if fc.Version.MinVersionBits != nil {
 		cfg.MinVersionBits = *fc.Version.MinVersionBits
 	}
@@ -587,12 +745,48 @@ func applyFileOverrides(cfg *Config, fc fileOverrideConfig) {
 	if fc.Version.BIP110Enabled != nil {
 		cfg.BIP110Enabled = *fc.Version.BIP110Enabled
 	}
+	if fc.Version.VersionMaskResyncCooldownSec != nil {
+		cfg.VersionMaskResyncCooldown = time.Duration(*fc.Version.VersionMaskResyncCooldownSec) * time.Second
+	}
+	if fc.Version.ShareVersionConventionLock != nil {
+		cfg.ShareVersionConventionLock = *fc.Version.ShareVersionConventionLock
+	}
 }
 
 func applyPolicyConfig(cfg *Config, fc policyFileConfig) {
 	if fc.Stratum.CKPoolEmulate != nil {
 		cfg.CKPoolEmulate = *fc.Stratum.CKPoolEmulate
 	}
+	if fc.Stratum.SubscribePoWBits != nil {
+		cfg.SubscribePoWBits = *fc.Stratum.SubscribePoWBits
+	}
+	if fc.Stratum.SubscribePoWBitsTLS != nil {
+		cfg.SubscribePoWBitsTLS = *fc.Stratum.SubscribePoWBitsTLS
+	}
+	if fc.Stratum.GateOnNetworkInactive != nil {
+		cfg.GateOnNetworkInactive = *fc.Stratum.GateOnNetworkInactive
+	}
+	if fc.Stratum.TrackTransportChanges != nil {
+		cfg.TrackTransportChanges = *fc.Stratum.TrackTransportChanges
+	}
+	if fc.Stratum.BadIDPolicy != nil {
+		cfg.StratumBadIDPolicy = strings.ToLower(strings.TrimSpace(*fc.Stratum.BadIDPolicy))
+	}
+	if fc.Stratum.FirstJobAlertSeconds != nil {
+		cfg.FirstJobAlertTimeout = time.Duration(*fc.Stratum.FirstJobAlertSeconds) * time.Second
+	}
+	if fc.Stratum.FirstJobAlertIBDSeconds != nil {
+		cfg.FirstJobAlertIBDTimeout = time.Duration(*fc.Stratum.FirstJobAlertIBDSeconds) * time.Second
+	}
+	if fc.Stratum.WorkerConnHistorySeconds != nil {
+		cfg.WorkerConnHistoryWindow = time.Duration(*fc.Stratum.WorkerConnHistorySeconds) * time.Second
+	}
+	if fc.Stratum.ReplaceStaleWorkerConnections != nil {
+		cfg.ReplaceStaleWorkerConnections = *fc.Stratum.ReplaceStaleWorkerConnections
+	}
+	if fc.Stratum.AllowAuthorizeBeforeSubscribe != nil {
+		cfg.AllowAuthorizeBeforeSubscribe = *fc.Stratum.AllowAuthorizeBeforeSubscribe
+	}
 	if fc.Mining.ShareJobFreshnessMode != nil {
 		mode := normalizeShareJobFreshnessMode(*fc.Mining.ShareJobFreshnessMode)
 		if mode >= 0 {
@@ -602,6 +796,9 @@ func applyPolicyConfig(cfg *Config, fc policyFileConfig) {
 	if fc.Mining.ShareCheckNTimeWindow != nil {
 		cfg.ShareCheckNTimeWindow = *fc.Mining.ShareCheckNTimeWindow
 	}
+	if fc.Mining.ShareNTimeRefreshCurrentJob != nil {
+		cfg.ShareNTimeRefreshCurrentJob = *fc.Mining.ShareNTimeRefreshCurrentJob
+	}
 	if fc.Mining.ShareCheckVersionRolling != nil {
 		cfg.ShareCheckVersionRolling = *fc.Mining.ShareCheckVersionRolling
 	}
@@ -614,15 +811,69 @@ func applyPolicyConfig(cfg *Config, fc policyFileConfig) {
 	if fc.Mining.ShareRequireWorkerMatch != nil {
 		cfg.ShareRequireWorkerMatch = *fc.Mining.ShareRequireWorkerMatch
 	}
+	if fc.Mining.ShareRequireSubscribedConnection != nil {
+		cfg.ShareRequireSubscribedConnection = *fc.Mining.ShareRequireSubscribedConnection
+	}
 	if fc.Mining.SubmitProcessInline != nil {
 		cfg.SubmitProcessInline = *fc.Mining.SubmitProcessInline
 	}
+	if fc.Mining.SubmitPanicDisconnectAfter != nil {
+		cfg.SubmitPanicDisconnectAfter = *fc.Mining.SubmitPanicDisconnectAfter
+	}
 	if fc.Mining.ShareCheckDuplicate != nil {
 		cfg.ShareCheckDuplicate = *fc.Mining.ShareCheckDuplicate
 	}
+	if fc.Mining.ShareMaxJobAgeSeconds != nil {
+		cfg.ShareMaxJobAgeSeconds = *fc.Mining.ShareMaxJobAgeSeconds
+	}
+	if fc.Mining.RequiredTemplateTxids != nil {
+		cfg.RequiredTemplateTxids = normalizeRequiredTemplateTxids(fc.Mining.RequiredTemplateTxids)
+	}
+	if fc.Mining.CoinbaseDustThresholdSats != nil {
+		cfg.CoinbaseDustThreshold = *fc.Mining.CoinbaseDustThresholdSats
+	}
+	if fc.Mining.InvalidWalletFallbackToPool != nil {
+		cfg.InvalidWalletFallbackToPool = *fc.Mining.InvalidWalletFallbackToPool
+	}
+	if fc.Mining.CoinbasePayoutMode != nil {
+		cfg.CoinbasePayoutMode = strings.ToLower(strings.TrimSpace(*fc.Mining.CoinbasePayoutMode))
+	}
+	if fc.Mining.RecordRewardDistribution != nil {
+		cfg.RecordRewardDistribution = *fc.Mining.RecordRewardDistribution
+	}
+	if fc.Mining.AccountingRecoveryFile != nil {
+		cfg.AccountingRecoveryFile = *fc.Mining.AccountingRecoveryFile
+	}
+	if fc.Mining.TemplateAllowConfirmedReorg != nil {
+		cfg.TemplateAllowConfirmedReorg = *fc.Mining.TemplateAllowConfirmedReorg
+	}
+	if fc.Mining.DegradedFeedSharePolicy != nil {
+		cfg.DegradedFeedSharePolicy = strings.ToLower(strings.TrimSpace(*fc.Mining.DegradedFeedSharePolicy))
+	}
+	if fc.Mining.SavedWorkerStaticDifficulty != nil {
+		cfg.SavedWorkerStaticDifficulty = *fc.Mining.SavedWorkerStaticDifficulty
+	}
+	if fc.Mining.DedupeBlockSubmissions != nil {
+		cfg.DedupeBlockSubmissions = *fc.Mining.DedupeBlockSubmissions
+	}
+	if fc.Mining.NearMissFactor != nil {
+		cfg.NearMissFactor = *fc.Mining.NearMissFactor
+	}
+	if fc.Mining.PayoutAddressCheckIntervalSec != nil {
+		cfg.PayoutAddressCheckInterval = time.Duration(*fc.Mining.PayoutAddressCheckIntervalSec) * time.Second
+	}
 	if fc.Hashrate.ShareNTimeMaxForwardSeconds != nil && *fc.Hashrate.ShareNTimeMaxForwardSeconds > 0 {
 		cfg.ShareNTimeMaxForwardSeconds = *fc.Hashrate.ShareNTimeMaxForwardSeconds
 	}
+	if fc.Hashrate.HashrateDropAlertPercent != nil {
+		cfg.HashrateDropAlertPercent = *fc.Hashrate.HashrateDropAlertPercent
+	}
+	if fc.Hashrate.HashrateDropAlertWindowSeconds != nil {
+		cfg.HashrateDropAlertWindow = time.Duration(*fc.Hashrate.HashrateDropAlertWindowSeconds) * time.Second
+	}
+	if fc.Hashrate.HashrateDropAlertDebounceSeconds != nil {
+		cfg.HashrateDropAlertDebounce = time.Duration(*fc.Hashrate.HashrateDropAlertDebounceSeconds) * time.Second
+	}
 	t := fileOverrideConfig{
 		Version:  fc.Version,
 		Bans:     fc.Bans,
@@ -638,6 +889,24 @@ func applyTuningConfig(cfg *Config, fc tuningFileConfig) {
 	if fc.Stratum.TCPWriteBufferBytes != nil {
 		cfg.StratumTCPWriteBufferBytes = *fc.Stratum.TCPWriteBufferBytes
 	}
+	if fc.Stratum.MaxConnectionLifetimeSeconds != nil && *fc.Stratum.MaxConnectionLifetimeSeconds >= 0 {
+		cfg.MaxConnectionLifetime = time.Duration(*fc.Stratum.MaxConnectionLifetimeSeconds) * time.Second
+	}
+	if fc.Stratum.WriteStallTimeoutSeconds != nil {
+		cfg.WriteStallTimeout = time.Duration(*fc.Stratum.WriteStallTimeoutSeconds) * time.Second
+	}
+	if fc.Stratum.NotifyHeartbeatSeconds != nil {
+		cfg.NotifyHeartbeatInterval = time.Duration(*fc.Stratum.NotifyHeartbeatSeconds) * time.Second
+	}
+	if fc.Stratum.StatsNotifySeconds != nil {
+		cfg.StatsNotifyInterval = time.Duration(*fc.Stratum.StatsNotifySeconds) * time.Second
+	}
+	if fc.Stratum.ShareListenerLabels != nil {
+		cfg.ShareListenerLabels = *fc.Stratum.ShareListenerLabels
+	}
+	if fc.Memory.BudgetMB != nil {
+		cfg.MemoryBudgetMB = *fc.Memory.BudgetMB
+	}
 	t := fileOverrideConfig{
 		RateLimits:   fc.RateLimits,
 		Difficulty:   fc.Difficulty,
@@ -669,4 +938,7 @@ func applySecretsConfig(cfg *Config, sc secretsConfig) {
 	if sc.BackblazeApplicationKey != "" {
 		cfg.BackblazeApplicationKey = strings.TrimSpace(sc.BackblazeApplicationKey)
 	}
+	if sc.StratumProxyHMACSecret != "" {
+		cfg.StratumProxyHMACSecret = strings.TrimSpace(sc.StratumProxyHMACSecret)
+	}
 }
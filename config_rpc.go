@@ -204,7 +204,7 @@ func warnCookieMissing(msg string, attrs ...any) {
 }
 
 func persistRPCCookiePathIfNeeded(configPath string, cfg *Config) {
-	if configPath == "" {
+	if configPath == "" || configCheckOnly {
 		return
 	}
 	path := strings.TrimSpace(cfg.RPCCookiePath)
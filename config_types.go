@@ -21,6 +21,11 @@ rpc_pass = "password"
 # - If using the master key, the Key ID is your Account ID.
 # backblaze_account_id = "003xxxxxxxxxxxxxxxxxxxx"
 # backblaze_application_key = "KXXXXXXXXXXXXXXXXXXXXXXXXXXXXXX"
+
+# Shared secret for the trusted-proxy Stratum listener ([stratum].stratum_proxy_listen).
+# Every mining.submit on that listener must carry an HMAC-SHA256 keyed by this value
+# (non-standard; see documentation/operations.md). At least 32 characters.
+# stratum_proxy_hmac_secret = "change-me-to-a-long-random-string"
 `)
 
 type Config struct {
@@ -28,6 +33,13 @@ type Config struct {
 	ListenAddr    string
 	StatusAddr    string
 	StatusTLSAddr string
+	// OfflineMode turns off every outbound call except to the node: fiat
+	// price, Discord, Clerk, Backblaze B2 uploads, metrics push and peer
+	// reverse DNS.
+	OfflineMode bool
+	// WatchTuningFile polls tuning.toml and reloads the config when it
+	// changes; a file that fails to parse or validate is ignored.
+	WatchTuningFile bool
 
 	// Branding.
 	StatusBrandName                 string
@@ -41,6 +53,19 @@ type Config struct {
 	GitHubURL                       string
 	MempoolAddressURL               string // URL prefix for explorer links (defaults to mempool.space/address/)
 	ServerLocation                  string
+	DisplayTimezone                 string // IANA zone for HTML timestamps ("" = UTC); JSON stays UTC
+	StatusMaintenanceMode           string // off, banner or page while Stratum is unhealthy
+	StatusMaintenanceMessage        string // visitor text for maintenance ("" = built-in message)
+	// Operator-defined scalars exposed under "operator" in /api/overview and
+	// /api/pool-page (services.toml [status.operator_fields]).
+	OperatorFields map[string]any
+	// Serve Prometheus text metrics at /metrics (requires restart).
+	MetricsEnabled bool
+	// Push PoolMetrics over UDP every MetricsPushIntervalSeconds as InfluxDB
+	// line protocol or StatsD ("" addr disables; requires restart).
+	MetricsPushAddr            string
+	MetricsPushFormat          string
+	MetricsPushIntervalSeconds int
 
 	// Discord integration.
 	DiscordURL                          string
@@ -48,9 +73,34 @@ type Config struct {
 	DiscordNotifyChannelID              string
 	DiscordBotToken                     string // store in secrets.toml
 	DiscordWorkerNotifyThresholdSeconds int    // min seconds online/offline before notify
+	// Block-found notices are deduplicated by block hash for
+	// DiscordBlockCooldownSeconds; DiscordWorkerBlockCooldownSeconds (0 off)
+	// additionally spaces out subscriber pings for the same worker.
+	DiscordBlockCooldownSeconds       int
+	DiscordWorkerBlockCooldownSeconds int
+	// DiscordWorkerHashrateAlertMinutes pings when a connected saved worker
+	// reports no hashrate for this long (0 disables).
+	DiscordWorkerHashrateAlertMinutes int
 
 	// Stratum TLS (empty to disable).
 	StratumTLSListen string
+	// StratumTLSClientCA is a PEM CA bundle; when set, the stratum TLS
+	// listener requires miners to present a client certificate it signed.
+	StratumTLSClientCA string
+	// StratumProxyListen is an optional listener for a trusted aggregating
+	// proxy (empty to disable). Every mining.submit on it must carry a valid
+	// non-standard "hmac" field keyed by StratumProxyHMACSecret.
+	StratumProxyListen     string
+	StratumProxyHMACSecret string // from secrets.toml
+	// StratumWebSocketPath serves Stratum over WebSocket at this path on the
+	// status server for browser-based miners (empty to disable).
+	StratumWebSocketPath string
+	// StratumWSListen is a dedicated plain-HTTP address that serves only
+	// Stratum WebSocket upgrades, on any path (empty to disable).
+	StratumWSListen string
+	// StratumReusePort binds the stratum listeners with SO_REUSEPORT so a
+	// replacement process can take over new accepts during a restart.
+	StratumReusePort bool
 	// Stratum auth (optional; when enabled, require miners to send the password in mining.authorize).
 	StratumPasswordEnabled bool
 	StratumPassword        string
@@ -60,9 +110,56 @@ type Config struct {
 	// CKPool compatibility mode: advertise a minimal CKPool-style subscribe
 	// result (mining.notify tuple only) while keeping other compatibility paths.
 	CKPoolEmulate bool
+	// Optional per-listener client puzzle required before mining.subscribe
+	// (leading zero bits; 0 disables). Incompatible with standard firmware.
+	SubscribePoWBits    int
+	SubscribePoWBitsTLS int
+	// Treat the node's networkactive=false as a degraded feed and gate
+	// Stratum (regtest is exempt). The condition is always logged.
+	GateOnNetworkInactive bool
+	// Close an older connection for the same worker, remote IP and resumed
+	// subscribe session when a reconnect authorizes.
+	ReplaceStaleWorkerConnections bool
+	// Accept mining.authorize before mining.subscribe. Work still starts only
+	// after subscribe; when false such an authorize is refused with error 25.
+	AllowAuthorizeBeforeSubscribe bool
+	// Track which transport (TCP/TLS) each worker authorizes over across
+	// reconnects and count/log upgrades and downgrades.
+	TrackTransportChanges bool
+	// What to do with requests whose id is missing or not a string/number:
+	// "compat" (handle, reply with id null), "ignore" or "reject".
+	// Requests with id null are always notifications and get no reply.
+	StratumBadIDPolicy string
+	// Alert when no job template is available this long after the Stratum
+	// listeners come up (0 disables). While the node reports IBD/syncing,
+	// FirstJobAlertIBDTimeout applies instead (0 = do not alert while syncing).
+	FirstJobAlertTimeout    time.Duration
+	FirstJobAlertIBDTimeout time.Duration
+	// Per-worker connect/disconnect history shown on the worker page
+	// (0 disables; see worker_conn_history.go).
+	WorkerConnHistoryWindow time.Duration
 	// Stratum TCP socket buffer tuning (0 = leave OS defaults).
 	StratumTCPReadBufferBytes  int
 	StratumTCPWriteBufferBytes int
+	// Max connection lifetime before sending client.reconnect (0 disables).
+	// Each connection adds random jitter so reconnects are staggered.
+	MaxConnectionLifetime time.Duration
+	// Force-close a connection whose in-flight write has made no progress
+	// for this long (0 disables); see miner_write_watchdog.go.
+	WriteStallTimeout time.Duration
+	// Re-send the current job (clean_jobs=false) when no notify has gone out
+	// for this long (0 disables); see miner_notify_heartbeat.go.
+	NotifyHeartbeatInterval time.Duration
+	// Push a goPool.stats notification with the connection's difficulty,
+	// share counts and hashrate this often (0 disables); see
+	// miner_stats_notify.go.
+	StatsNotifyInterval time.Duration
+	// Count shares per Stratum listener (tcp/tls/proxy/ws) in /metrics and
+	// list each worker's listeners in worker views.
+	ShareListenerLabels bool
+	// Process memory budget in MiB (0 disables); see memory_budget.go for the
+	// shedding levels applied as usage approaches it.
+	MemoryBudgetMB int
 
 	// Clerk authentication.
 	ClerkIssuerURL         string
@@ -83,15 +180,63 @@ type Config struct {
 	rpCCookiePathFromConfig string
 	rpcCookieWatch          bool
 	AllowPublicRPC          bool // allow unauthenticated RPC (testing only)
+	// On a 401, re-read the cookie at once and retry the call a single
+	// time; a second 401 fails the call instead of backing off forever.
+	RPCCookieRetry bool
 
 	// Payouts.
 	PayoutAddress  string
 	PoolFeePercent float64
+	// Pool-fee/donation outputs below this many sats are folded into the
+	// output they were split from instead of being paid as dust (0 disables).
+	CoinbaseDustThreshold int64
+	// Opt-in: workers whose name is not a valid payout address mine to
+	// PayoutAddress (with a one-time warning) instead of being rejected.
+	InvalidWalletFallbackToPool bool
+	// Coinbase output selection: "auto" (single/dual/triple per worker) or
+	// "single_pool" (always one output to PayoutAddress).
+	CoinbasePayoutMode string
+	// Record each found block's submitted coinbase outputs and txid in the
+	// found-blocks log for audit (served by /api/blocks/detail).
+	RecordRewardDistribution bool
+	// Re-validate PayoutAddress with the node's validateaddress at this
+	// interval and alert on invalid/drifted results (0 disables).
+	PayoutAddressCheckInterval time.Duration
+	// Count accepted shares within this factor of the network difficulty
+	// as near-misses (e.g. 10 = at least 10% of network diff; 0 disables).
+	NearMissFactor float64
+	// Alert (log, error history, Discord notice channel) when pool hashrate
+	// or connection count falls this many percent below its peak within
+	// HashrateDropAlertWindow and stays down for HashrateDropAlertDebounce
+	// (0 disables). Deliberate shutdown drains never alert.
+	HashrateDropAlertPercent  float64
+	HashrateDropAlertWindow   time.Duration
+	HashrateDropAlertDebounce time.Duration
+	// Save found-block records that fail to flush at shutdown to
+	// state/accounting_recovery.jsonl for replay on the next start.
+	AccountingRecoveryFile bool
+	// Switch to a lower-height template only when the node confirms the
+	// current job's parent left its active chain (see job_height_regression.go).
+	TemplateAllowConfirmedReorg bool
+	// How non-block shares are handled while the job feed is degraded but
+	// still serving the last job: "lenient" (accept and credit) or "strict"
+	// (reject as stale). Block-solving shares are always submitted.
+	DegradedFeedSharePolicy string
+	// Let signed-in users pin a static difficulty on their saved workers
+	// (see saved_worker_static_difficulty.go). Off by default.
+	SavedWorkerStaticDifficulty bool
+	// Submit only the first valid block per height; later finders at that
+	// height are recorded as also-found (see block_height_claims.go). Off by
+	// default.
+	DedupeBlockSubmissions bool
 
 	OperatorDonationPercent float64
 	OperatorDonationAddress string
 	OperatorDonationName    string
 	OperatorDonationURL     string
+	// Divide the pool fee output between several operator wallets by
+	// weight ([[mining.payout_splits]]; see coinbase_payout_splits.go).
+	PayoutSplits []PayoutSplit
 
 	// Mining parameters.
 	Extranonce2Size           int
@@ -101,8 +246,25 @@ type Config struct {
 	PoolEntropy               string
 	PoolTagPrefix             string
 	CoinbaseScriptSigMaxBytes int
-	ZMQHashBlockAddr          string
-	ZMQRawBlockAddr           string
+	// How often to re-check that the coinbase tag still fits
+	// CoinbaseScriptSigMaxBytes at the current height (0 disables).
+	CoinbaseTagCheckInterval time.Duration
+	// Hex data for an extra zero-value OP_RETURN coinbase output (empty = none).
+	CoinbaseExtraOpReturn string
+	// Serialized coinbase transaction size ceiling (0 = defaultCoinbaseMaxBytes).
+	CoinbaseMaxBytes int
+	// Append the build version to the coinbase tag until the first block
+	// found since this process started.
+	CoinbaseUpgradeMarker bool
+	// Reuse each job's serialized coinbase outputs across connections that
+	// share the same payout outputs (see coinbase_parts_cache.go).
+	CoinbasePartsCache bool
+	// Reissue an unchanged template as a new job once the current job's
+	// coinbase scriptTime is this many seconds old (0 disables).
+	ScriptTimeMaxDriftSeconds int
+
+	ZMQHashBlockAddr string
+	ZMQRawBlockAddr  string
 
 	// Backblaze B2 backup.
 	BackblazeBackupEnabled         bool
@@ -117,6 +279,9 @@ type Config struct {
 
 	DataDir  string
 	MaxConns int
+	// MaxConnsPerIP caps simultaneous Stratum connections from one remote
+	// host (0 disables the cap).
+	MaxConnsPerIP int
 
 	// Accept rate limiting (auto-configured from MaxConns when AutoAcceptRateLimits=true).
 	MaxAcceptsPerSecond               int
@@ -129,26 +294,46 @@ type Config struct {
 	AcceptSteadyStateRate             int     // max accepts/sec in steady state
 	AcceptSteadyStateReconnectPercent float64 // expected % of miners reconnecting at once
 	AcceptSteadyStateReconnectWindow  int     // seconds to spread steady-state reconnects
+	AcceptRPCLatencyBackoffMs         int     // GBT latency that starts scaling accepts down (0 disables)
+	AcceptRPCLatencyMinPercent        float64 // floor for the latency backoff, % of the configured rate
 	StratumMessagesPerMinute          int     // per-connection Stratum messages/min (0 disables)
+	StatusRequestsPerSecond           int     // status HTTP requests/sec before 429 (0 disables)
+	StatusMaxInflightRequests         int     // concurrent status HTTP requests before 429 (0 disables)
 
-	MaxRecentJobs                 int
-	ConnectionTimeout             time.Duration
+	MaxRecentJobs     int
+	ConnectionTimeout time.Duration
+	// TLSInitialTimeout replaces the pre-share read window on the TLS
+	// listener (0 uses the same window as plain TCP).
+	TLSInitialTimeout time.Duration
+	// LongpollTimeout re-issues a plain getblocktemplate when a longpoll
+	// has not returned for this long (0 waits indefinitely).
+	LongpollTimeout               time.Duration
 	VersionMask                   uint32
 	MinVersionBits                int
 	ShareAllowVersionMaskMismatch bool
 	ShareAllowDegradedVersionBits bool
-	BIP110Enabled                 bool
-	VersionBitOverrides           map[uint32]bool
-	VersionMaskConfigured         bool
-	MaxDifficulty                 float64
-	MinDifficulty                 float64
-	DefaultDifficulty             float64
-	TargetSharesPerMin            float64 // vardiff target share rate
-	VarDiffEnabled                bool    // enable dynamic difficulty retargeting
+	// Re-send mining.set_version_mask to a miner rolling outside its mask at
+	// most once per cooldown before rejecting normally (0 disables).
+	VersionMaskResyncCooldown time.Duration
+	// Lock each connection to the delta/full version convention it used
+	// consistently for its first submits (see miner_version_convention.go).
+	ShareVersionConventionLock bool
+	BIP110Enabled              bool
+	VersionBitOverrides        map[uint32]bool
+	VersionMaskConfigured      bool
+	MaxDifficulty              float64
+	MinDifficulty              float64
+	DefaultDifficulty          float64
+	TargetSharesPerMin         float64 // vardiff target share rate
+	VarDiffEnabled             bool    // enable dynamic difficulty retargeting
 
 	LockSuggestedDifficulty          bool          // keep suggested difficulty instead of vardiff
 	EnforceSuggestedDifficultyLimits bool          // ban/disconnect when suggest_* outside min/max
 	DifficultyStepGranularity        int           // quantize to 2^(k/N) steps; default N=10
+	ShareFloodSharesPerMin           float64       // per-connection submit rate that triggers a temporary diff floor (0 disables)
+	ShareFloodHold                   time.Duration // how long a share-flood diff floor stays after the flood stops
+	VarDiffStaleFeedbackPercent      float64       // bias vardiff down when stale rejects exceed this % (0 disables)
+	WorkerDifficultySync             bool          // converge vardiff across a worker name's similar-hashrate connections
 	HashrateEMATauSeconds            float64       // EMA time constant for hashrate
 	HashrateCumulativeEnabled        bool          // blend per-connection EMA with cumulative hashrate (display)
 	HashrateRecentCumulativeEnabled  bool          // allow short-horizon cumulative (vardiff window) to influence display
@@ -156,16 +341,47 @@ type Config struct {
 	ShareNTimeMaxForwardSeconds      int           // max seconds ntime can roll forward
 	ShareCheckDuplicate              bool          // enable duplicate detection (off by default for solo)
 
+	// Pinned difficulties by worker pattern (lower-case; trailing * allowed).
+	// Matching connections skip vardiff; see miner_difficulty_override.go.
+	WorkerDifficultyOverrides map[string]float64
+
 	ShareJobFreshnessMode            int  // 0=off, 1=job_id, 2=job_id+prevhash
 	ShareCheckNTimeWindow            bool // reject ntime outside configured window
+	ShareNTimeRefreshCurrentJob      bool // let the current job's ntime max follow wall clock
 	ShareCheckVersionRolling         bool // reject invalid version rolling policy violations
 	ShareRequireAuthorizedConnection bool // reject submits from unauthorized connections
 	ShareCheckParamFormat            bool // enforce strict submit field format/length checks
 	ShareRequireWorkerMatch          bool // enforce submit worker name must match authorized worker
+	ShareRequireSubscribedConnection bool // reject submits sent before mining.subscribe
 	SubmitProcessInline              bool // process submits on connection goroutine (bypass worker pool)
+	SubmitPanicDisconnectAfter       int  // disconnect after this many recovered submit panics (0 = never)
+	ShareMaxJobAgeSeconds            int  // shares for jobs older than this are stale (0 = no limit)
 	LogDebug                         bool // enable debug logs and detailed runtime traces
 	LogNetDebug                      bool // enable raw network debug logging (when supported)
 
+	// Percent of connections (0-100) whose Stratum JSON request/response
+	// lines are written to debug.log. Chosen once per connection at accept.
+	LogTraceSamplePercent float64
+	// Rolled daily log segments: delete after LogRetentionDays (0 keeps
+	// all), keep at most LogRetentionFiles per stream (0 = no limit), and
+	// gzip them when LogCompressRotated is set.
+	LogRetentionDays   int
+	LogRetentionFiles  int
+	LogCompressRotated bool
+	// LogFormat is "text" (default) or "json"; it applies to pool.log,
+	// debug.log, net-debug.log and the -stdout mirror.
+	LogFormat string
+	// Write a goroutine dump and heap profile to <data_dir>/state/profiles
+	// when the goroutine count or live heap (MB) reaches these limits
+	// (0 disables each), at most once per AutoProfileMinInterval.
+	AutoProfileGoroutines  int
+	AutoProfileHeapMB      int
+	AutoProfileMinInterval time.Duration
+
+	// Txids the node must include in block templates (alert only; we never
+	// inject transactions ourselves).
+	RequiredTemplateTxids []string
+
 	// Maintenance behavior.
 	CleanExpiredBansOnStartup bool // rewrite/drop expired bans on startup
 
@@ -178,7 +394,21 @@ type Config struct {
 	ReconnectBanThreshold       int
 	ReconnectBanWindowSeconds   int
 	ReconnectBanDurationSeconds int
-	BannedMinerTypes            []string
+	// Distinct source ports per IP per minute before the IP is banned for
+	// ReconnectBanDurationSeconds (0 disables).
+	SourcePortChurnPerMin int
+	// Submit nonces per degenerate-nonce batch (0 disables) and consecutive
+	// degenerate batches before a ban (0 alerts only).
+	NonceCheckMinSamples int
+	NonceCheckBanAfter   int
+	BannedMinerTypes     []string
+	// IP/CIDR ban list imported from a URL or file every
+	// BanFeedRefreshSeconds (empty source disables).
+	BanFeedSource         string
+	BanFeedRefreshSeconds int
+	// Strict subscribe allowlist: when non-empty, mining.subscribe must name
+	// a client whose full ID or name matches an entry; others are dropped.
+	AllowedMinerTypes []string
 
 	// High-latency peer cleanup.
 	PeerCleanupEnabled   bool
@@ -190,6 +420,8 @@ type EffectiveConfig struct {
 	ListenAddr                        string   `json:"listen_addr"`
 	StatusAddr                        string   `json:"status_addr"`
 	StatusTLSAddr                     string   `json:"status_tls_listen,omitempty"`
+	OfflineMode                       bool     `json:"offline_mode,omitempty"`
+	WatchTuningFile                   bool     `json:"watch_tuning_file,omitempty"`
 	StatusBrandName                   string   `json:"status_brand_name,omitempty"`
 	StatusBrandDomain                 string   `json:"status_brand_domain,omitempty"`
 	StatusTagline                     string   `json:"status_tagline,omitempty"`
@@ -199,13 +431,40 @@ type EffectiveConfig struct {
 	PoolDonationAddress               string   `json:"pool_donation_address,omitempty"`
 	DiscordURL                        string   `json:"discord_url,omitempty"`
 	DiscordWorkerNotifyThresholdSec   int      `json:"discord_worker_notify_threshold_seconds,omitempty"`
+	DiscordBlockCooldownSec           int      `json:"discord_block_cooldown_seconds,omitempty"`
+	DiscordWorkerBlockCooldownSec     int      `json:"discord_worker_block_cooldown_seconds,omitempty"`
+	DiscordWorkerHashrateAlertMinutes int      `json:"discord_worker_hashrate_alert_minutes,omitempty"`
 	GitHubURL                         string   `json:"github_url,omitempty"`
+	StatusMaintenanceMode             string   `json:"status_maintenance_mode,omitempty"`
+	StatusMaintenanceMessage          string   `json:"status_maintenance_message,omitempty"`
 	ServerLocation                    string   `json:"server_location,omitempty"`
+	DisplayTimezone                   string   `json:"display_timezone,omitempty"`
 	StratumTLSListen                  string   `json:"stratum_tls_listen,omitempty"`
+	StratumProxyListen                string   `json:"stratum_proxy_listen,omitempty"`
+	StratumWebSocketPath              string   `json:"stratum_websocket_path,omitempty"`
+	StratumWSListen                   string   `json:"stratum_ws_listen,omitempty"`
+	StratumTLSClientCA                string   `json:"stratum_tls_client_ca,omitempty"`
+	StratumReusePort                  bool     `json:"stratum_reuse_port,omitempty"`
 	SafeMode                          bool     `json:"safe_mode,omitempty"`
 	CKPoolEmulate                     bool     `json:"ckpool_emulate"`
+	SubscribePoWBits                  int      `json:"subscribe_pow_bits,omitempty"`
+	SubscribePoWBitsTLS               int      `json:"subscribe_pow_bits_tls,omitempty"`
+	GateOnNetworkInactive             bool     `json:"gate_on_network_inactive"`
+	ReplaceStaleWorkerConnections     bool     `json:"replace_stale_worker_connections"`
+	AllowAuthorizeBeforeSubscribe     bool     `json:"allow_authorize_before_subscribe"`
+	TrackTransportChanges             bool     `json:"track_transport_changes,omitempty"`
+	StratumBadIDPolicy                string   `json:"stratum_bad_id_policy,omitempty"`
+	FirstJobAlertTimeout              string   `json:"first_job_alert_timeout,omitempty"`
+	FirstJobAlertIBDTimeout           string   `json:"first_job_alert_ibd_timeout,omitempty"`
+	WorkerConnHistoryWindow           string   `json:"worker_connection_history_window,omitempty"`
 	StratumTCPReadBufferBytes         int      `json:"stratum_tcp_read_buffer_bytes,omitempty"`
 	StratumTCPWriteBufferBytes        int      `json:"stratum_tcp_write_buffer_bytes,omitempty"`
+	MaxConnectionLifetime             string   `json:"max_connection_lifetime,omitempty"`
+	WriteStallTimeout                 string   `json:"write_stall_timeout,omitempty"`
+	NotifyHeartbeatInterval           string   `json:"notify_heartbeat_interval,omitempty"`
+	StatsNotifyInterval               string   `json:"stats_notify_interval,omitempty"`
+	ShareListenerLabels               bool     `json:"share_listener_labels,omitempty"`
+	MemoryBudgetMB                    int      `json:"memory_budget_mb,omitempty"`
 	ClerkIssuerURL                    string   `json:"clerk_issuer_url,omitempty"`
 	ClerkJWKSURL                      string   `json:"clerk_jwks_url,omitempty"`
 	ClerkSignInURL                    string   `json:"clerk_signin_url,omitempty"`
@@ -215,8 +474,23 @@ type EffectiveConfig struct {
 	RPCURL                            string   `json:"rpc_url"`
 	RPCUser                           string   `json:"rpc_user"`
 	RPCPassSet                        bool     `json:"rpc_pass_set"`
+	RPCCookieRetry                    bool     `json:"rpc_cookie_retry,omitempty"`
 	PayoutAddress                     string   `json:"payout_address"`
 	PoolFeePercent                    float64  `json:"pool_fee_percent,omitempty"`
+	CoinbaseDustThreshold             int64    `json:"coinbase_dust_threshold_sats,omitempty"`
+	InvalidWalletFallbackToPool       bool     `json:"invalid_wallet_fallback_to_pool,omitempty"`
+	CoinbasePayoutMode                string   `json:"coinbase_payout_mode,omitempty"`
+	RecordRewardDistribution          bool     `json:"record_reward_distribution,omitempty"`
+	PayoutAddressCheckInterval        string   `json:"payout_address_check_interval,omitempty"`
+	NearMissFactor                    float64  `json:"near_miss_factor,omitempty"`
+	HashrateDropAlertPercent          float64  `json:"hashrate_drop_alert_percent,omitempty"`
+	HashrateDropAlertWindow           string   `json:"hashrate_drop_alert_window,omitempty"`
+	HashrateDropAlertDebounce         string   `json:"hashrate_drop_alert_debounce,omitempty"`
+	AccountingRecoveryFile            bool     `json:"accounting_recovery_file,omitempty"`
+	TemplateAllowConfirmedReorg       bool     `json:"template_allow_confirmed_reorg,omitempty"`
+	DegradedFeedSharePolicy           string   `json:"degraded_feed_share_policy,omitempty"`
+	SavedWorkerStaticDifficulty       bool     `json:"saved_worker_static_difficulty,omitempty"`
+	DedupeBlockSubmissions            bool     `json:"dedupe_block_submissions,omitempty"`
 	OperatorDonationPercent           float64  `json:"operator_donation_percent,omitempty"`
 	OperatorDonationAddress           string   `json:"operator_donation_address,omitempty"`
 	OperatorDonationName              string   `json:"operator_donation_name,omitempty"`
@@ -226,6 +500,12 @@ type EffectiveConfig struct {
 	JobEntropy                        int      `json:"job_entropy"`
 	PoolID                            string   `json:"pool_id,omitempty"`
 	CoinbaseScriptSigMaxBytes         int      `json:"coinbase_scriptsig_max_bytes"`
+	CoinbaseTagCheckInterval          string   `json:"coinbase_tag_check_interval,omitempty"`
+	CoinbaseMaxBytes                  int      `json:"coinbase_max_bytes,omitempty"`
+	CoinbaseUpgradeMarker             bool     `json:"coinbase_upgrade_marker,omitempty"`
+	CoinbasePartsCache                bool     `json:"coinbase_parts_cache,omitempty"`
+	ScriptTimeMaxDriftSeconds         int      `json:"script_time_max_drift_seconds,omitempty"`
+	CoinbaseExtraOpReturn             string   `json:"coinbase_extra_op_return,omitempty"`
 	ZMQHashBlockAddr                  string   `json:"zmq_hashblock_addr,omitempty"`
 	ZMQRawBlockAddr                   string   `json:"zmq_rawblock_addr,omitempty"`
 	BackblazeBackupEnabled            bool     `json:"backblaze_backup_enabled,omitempty"`
@@ -237,6 +517,7 @@ type EffectiveConfig struct {
 	BackblazeForceEveryInterval       bool     `json:"backblaze_force_every_interval,omitempty"`
 	BackupSnapshotPath                string   `json:"backup_snapshot_path,omitempty"`
 	MaxConns                          int      `json:"max_conns,omitempty"`
+	MaxConnsPerIP                     int      `json:"max_conns_per_ip,omitempty"`
 	MaxAcceptsPerSecond               int      `json:"max_accepts_per_second,omitempty"`
 	MaxAcceptBurst                    int      `json:"max_accept_burst,omitempty"`
 	DisableConnectRateLimits          bool     `json:"disable_connect_rate_limits,omitempty"`
@@ -245,15 +526,23 @@ type EffectiveConfig struct {
 	AcceptBurstWindow                 int      `json:"accept_burst_window,omitempty"`
 	AcceptSteadyStateWindow           int      `json:"accept_steady_state_window,omitempty"`
 	AcceptSteadyStateRate             int      `json:"accept_steady_state_rate,omitempty"`
+	AcceptRPCLatencyBackoffMs         int      `json:"accept_rpc_latency_backoff_ms,omitempty"`
+	AcceptRPCLatencyMinPercent        float64  `json:"accept_rpc_latency_min_percent,omitempty"`
 	AcceptSteadyStateReconnectPercent float64  `json:"accept_steady_state_reconnect_percent,omitempty"`
 	AcceptSteadyStateReconnectWindow  int      `json:"accept_steady_state_reconnect_window,omitempty"`
 	StratumMessagesPerMinute          int      `json:"stratum_messages_per_minute,omitempty"`
+	StatusRequestsPerSecond           int      `json:"status_requests_per_second,omitempty"`
+	StatusMaxInflightRequests         int      `json:"status_max_inflight_requests,omitempty"`
 	MaxRecentJobs                     int      `json:"max_recent_jobs"`
 	ConnectionTimeout                 string   `json:"connection_timeout"`
+	TLSInitialTimeout                 string   `json:"tls_initial_timeout,omitempty"`
+	LongpollTimeout                   string   `json:"longpoll_timeout,omitempty"`
 	VersionMask                       string   `json:"version_mask,omitempty"`
 	MinVersionBits                    int      `json:"min_version_bits,omitempty"`
 	ShareAllowVersionMaskMismatch     bool     `json:"share_allow_version_mask_mismatch,omitempty"`
 	ShareAllowDegradedVersionBits     bool     `json:"share_allow_degraded_version_bits,omitempty"`
+	VersionMaskResyncCooldown         string   `json:"version_mask_resync_cooldown,omitempty"`
+	ShareVersionConventionLock        bool     `json:"share_version_convention_lock,omitempty"`
 	BIP110Enabled                     bool     `json:"bip110_enabled,omitempty"`
 	MaxDifficulty                     float64  `json:"max_difficulty,omitempty"`
 	MinDifficulty                     float64  `json:"min_difficulty,omitempty"`
@@ -261,18 +550,35 @@ type EffectiveConfig struct {
 	VarDiffEnabled                    bool     `json:"vardiff_enabled"`
 	LockSuggestedDifficulty           bool     `json:"lock_suggested_difficulty,omitempty"`
 	DifficultyStepGranularity         int      `json:"difficulty_step_granularity,omitempty"`
+	ShareFloodSharesPerMin            float64  `json:"share_flood_shares_per_min,omitempty"`
+	VarDiffStaleFeedbackPercent       float64  `json:"vardiff_stale_feedback_percent,omitempty"`
+	WorkerDifficultySync              bool     `json:"worker_difficulty_sync,omitempty"`
+	ShareFloodHold                    string   `json:"share_flood_hold,omitempty"`
 	ShareJobFreshnessMode             int      `json:"share_job_freshness_mode"`
 	ShareCheckNTimeWindow             bool     `json:"share_check_ntime_window"`
+	ShareNTimeRefreshCurrentJob       bool     `json:"share_ntime_refresh_current_job,omitempty"`
 	ShareCheckVersionRolling          bool     `json:"share_check_version_rolling"`
 	ShareRequireAuthorizedConnection  bool     `json:"share_require_authorized_connection"`
 	ShareCheckParamFormat             bool     `json:"share_check_param_format"`
 	ShareRequireWorkerMatch           bool     `json:"share_require_worker_match"`
+	ShareRequireSubscribedConnection  bool     `json:"share_require_subscribed_connection,omitempty"`
 	SubmitProcessInline               bool     `json:"submit_process_inline"`
+	ShareMaxJobAgeSeconds             int      `json:"share_max_job_age_seconds,omitempty"`
+	SubmitPanicDisconnectAfter        int      `json:"submit_panic_disconnect_after"`
+	RequiredTemplateTxids             []string `json:"required_template_txids,omitempty"`
 	HashrateEMATauSeconds             float64  `json:"hashrate_ema_tau_seconds,omitempty"`
 	ShareNTimeMaxForwardSeconds       int      `json:"share_ntime_max_forward_seconds,omitempty"`
 	ShareCheckDuplicate               bool     `json:"share_check_duplicate,omitempty"`
 	LogDebug                          bool     `json:"log_debug,omitempty"`
 	LogNetDebug                       bool     `json:"log_net_debug,omitempty"`
+	LogTraceSamplePercent             float64  `json:"log_trace_sample_percent,omitempty"`
+	LogRetentionDays                  int      `json:"log_retention_days,omitempty"`
+	LogRetentionFiles                 int      `json:"log_retention_files,omitempty"`
+	LogCompressRotated                bool     `json:"log_compress_rotated,omitempty"`
+	LogFormat                         string   `json:"log_format,omitempty"`
+	AutoProfileGoroutines             int      `json:"auto_profile_goroutines,omitempty"`
+	AutoProfileHeapMB                 int      `json:"auto_profile_heap_mb,omitempty"`
+	AutoProfileMinInterval            string   `json:"auto_profile_interval,omitempty"`
 	CleanExpiredBansOnStartup         bool     `json:"clean_expired_bans_on_startup,omitempty"`
 	BanInvalidSubmissionsAfter        int      `json:"ban_invalid_submissions_after,omitempty"`
 	BanInvalidSubmissionsWindow       string   `json:"ban_invalid_submissions_window,omitempty"`
@@ -280,8 +586,21 @@ type EffectiveConfig struct {
 	ReconnectBanThreshold             int      `json:"reconnect_ban_threshold,omitempty"`
 	ReconnectBanWindowSeconds         int      `json:"reconnect_ban_window_seconds,omitempty"`
 	ReconnectBanDurationSeconds       int      `json:"reconnect_ban_duration_seconds,omitempty"`
+	SourcePortChurnPerMin             int      `json:"source_port_churn_per_min,omitempty"`
+	NonceCheckMinSamples              int      `json:"nonce_check_min_samples,omitempty"`
+	NonceCheckBanAfter                int      `json:"nonce_check_ban_after,omitempty"`
 	BannedMinerTypes                  []string `json:"banned_miner_types,omitempty"`
+	AllowedMinerTypes                 []string `json:"allowed_miner_types,omitempty"`
 	PeerCleanupEnabled                bool     `json:"peer_cleanup_enabled,omitempty"`
 	PeerCleanupMaxPingMs              float64  `json:"peer_cleanup_max_ping_ms,omitempty"`
 	PeerCleanupMinPeers               int      `json:"peer_cleanup_min_peers,omitempty"`
+
+	OperatorFields      map[string]any `json:"operator_fields,omitempty"`
+	MetricsEnabled      bool           `json:"metrics_enabled,omitempty"`
+	MetricsPushAddr     string         `json:"metrics_push_addr,omitempty"`
+	MetricsPushFormat   string         `json:"metrics_push_format,omitempty"`
+	MetricsPushInterval int            `json:"metrics_push_interval_seconds,omitempty"`
+	PayoutSplits        []PayoutSplit  `json:"payout_splits,omitempty"`
+
+	WorkerDifficultyOverrides map[string]float64 `json:"worker_difficulty_overrides,omitempty"`
 }
@@ -2,9 +2,12 @@ package main
 
 import (
 	"fmt"
+	"math"
 	"math/bits"
+	"net"
 	"net/url"
 	"strings"
+	"time"
 )
 
 func validateConfig(cfg Config) error {
@@ -17,7 +20,8 @@ func validateConfig(cfg Config) error {
 	if cfg.TemplateExtraNonce2Size < cfg.Extranonce2Size {
 		cfg.TemplateExtraNonce2Size = cfg.Extranonce2Size
 	}
-	if !cfg.AllowPublicRPC && !cfg.rpcCookieWatch && (strings.TrimSpace(cfg.RPCUser) == "" || strings.TrimSpace(cfg.RPCPass) == "") {
+	cookieAuth := cfg.rpcCookieWatch || strings.TrimSpace(cfg.RPCCookiePath) != ""
+	if !cfg.AllowPublicRPC && !cookieAuth && (strings.TrimSpace(cfg.RPCUser) == "" || strings.TrimSpace(cfg.RPCPass) == "") {
 		return fmt.Errorf("rpc credentials are missing (set node.rpc_cookie_path, allow public RPC, or restart with -allow-rpc-credentials configured)")
 	}
 	if strings.TrimSpace(cfg.RPCURL) == "" {
@@ -37,6 +41,9 @@ func validateConfig(cfg Config) error {
 	if cfg.MaxConns < 0 {
 		return fmt.Errorf("max_conns cannot be negative")
 	}
+	if cfg.MaxConnsPerIP < 0 {
+		return fmt.Errorf("max_conns_per_ip cannot be negative")
+	}
 	if cfg.MaxAcceptsPerSecond < 0 {
 		return fmt.Errorf("max_accepts_per_second cannot be negative")
 	}
@@ -66,12 +73,27 @@ func validateConfig(cfg Config) error {
 	if cfg.CoinbaseScriptSigMaxBytes < 0 {
 		return fmt.Errorf("coinbase_scriptsig_max_bytes cannot be negative")
 	}
+	if cfg.CoinbaseTagCheckInterval < 0 {
+		return fmt.Errorf("coinbase_tag_check_hours cannot be negative")
+	}
+	if cfg.CoinbaseMaxBytes < 0 {
+		return fmt.Errorf("coinbase_max_bytes cannot be negative")
+	}
+	if cfg.CoinbaseMaxBytes > defaultCoinbaseMaxBytes {
+		return fmt.Errorf("coinbase_max_bytes cannot exceed %d", defaultCoinbaseMaxBytes)
+	}
+	if _, err := coinbaseExtraOpReturnScript(cfg.CoinbaseExtraOpReturn); err != nil {
+		return fmt.Errorf("coinbase_extra_op_return: %w", err)
+	}
 	if cfg.ConnectionTimeout < 0 {
 		return fmt.Errorf("connection_timeout_seconds cannot be negative")
 	}
 	if cfg.ConnectionTimeout < minMinerTimeout {
 		return fmt.Errorf("connection_timeout_seconds must be >= %s, got %s", minMinerTimeout, cfg.ConnectionTimeout)
 	}
+	if cfg.VersionMaskResyncCooldown < 0 {
+		return fmt.Errorf("version_mask_resync_cooldown_seconds cannot be negative")
+	}
 	if cfg.MinVersionBits < 0 {
 		return fmt.Errorf("min_version_bits cannot be negative")
 	}
@@ -88,6 +110,18 @@ func validateConfig(cfg Config) error {
 	if cfg.MinDifficulty < 0 {
 		return fmt.Errorf("min_difficulty cannot be negative")
 	}
+	if math.IsNaN(cfg.MinDifficulty) || math.IsInf(cfg.MinDifficulty, 0) || math.IsNaN(cfg.MaxDifficulty) || math.IsInf(cfg.MaxDifficulty, 0) {
+		return fmt.Errorf("min_difficulty and max_difficulty must be finite")
+	}
+	if cfg.MaxDifficulty > 0 && cfg.MaxDifficulty < minShareDifficulty {
+		return fmt.Errorf("max_difficulty %g is below the lowest usable share difficulty %g", cfg.MaxDifficulty, minShareDifficulty)
+	}
+	if err := validateWorkerDifficultyOverrides(cfg.WorkerDifficultyOverrides); err != nil {
+		return err
+	}
+	if cfg.MaxDifficulty > 0 && cfg.MinDifficulty > cfg.MaxDifficulty {
+		return fmt.Errorf("min_difficulty must be <= max_difficulty when max_difficulty is set")
+	}
 	if cfg.TargetSharesPerMin <= 0 {
 		return fmt.Errorf("target_shares_per_min must be > 0, got %v", cfg.TargetSharesPerMin)
 	}
@@ -100,9 +134,69 @@ func validateConfig(cfg Config) error {
 	if cfg.OperatorDonationPercent < 0 || cfg.OperatorDonationPercent > 100 {
 		return fmt.Errorf("operator_donation_percent must be >= 0 and <= 100, got %v", cfg.OperatorDonationPercent)
 	}
+	if _, err := loadDisplayTimezone(cfg.DisplayTimezone); err != nil {
+		return fmt.Errorf("display_timezone %q is not a valid IANA timezone: %w", cfg.DisplayTimezone, err)
+	}
+	if cfg.SubscribePoWBits < 0 || cfg.SubscribePoWBits > maxSubscribePoWBits {
+		return fmt.Errorf("subscribe_pow_bits must be between 0 and %d, got %d", maxSubscribePoWBits, cfg.SubscribePoWBits)
+	}
+	if cfg.SubscribePoWBitsTLS < 0 || cfg.SubscribePoWBitsTLS > maxSubscribePoWBits {
+		return fmt.Errorf("subscribe_pow_bits_tls must be between 0 and %d, got %d", maxSubscribePoWBits, cfg.SubscribePoWBitsTLS)
+	}
+	switch cfg.StratumBadIDPolicy {
+	case "", stratumBadIDPolicyCompat, stratumBadIDPolicyIgnore, stratumBadIDPolicyReject:
+	default:
+		return fmt.Errorf("bad_id_policy must be %q, %q or %q, got %q", stratumBadIDPolicyCompat, stratumBadIDPolicyIgnore, stratumBadIDPolicyReject, cfg.StratumBadIDPolicy)
+	}
+	if cfg.SubmitPanicDisconnectAfter < 0 {
+		return fmt.Errorf("submit_panic_disconnect_after must be >= 0, got %d", cfg.SubmitPanicDisconnectAfter)
+	}
+	if cfg.VarDiffStaleFeedbackPercent < 0 || cfg.VarDiffStaleFeedbackPercent >= 100 {
+		return fmt.Errorf("vardiff_stale_feedback_percent must be >= 0 and < 100, got %v", cfg.VarDiffStaleFeedbackPercent)
+	}
+	if cfg.FirstJobAlertTimeout < 0 || cfg.FirstJobAlertIBDTimeout < 0 {
+		return fmt.Errorf("first_job_alert_seconds and first_job_alert_ibd_seconds must be >= 0")
+	}
+	if cfg.WorkerConnHistoryWindow < 0 {
+		return fmt.Errorf("worker_connection_history_seconds must be >= 0")
+	}
+	switch cfg.StatusMaintenanceMode {
+	case "", statusMaintenanceOff, statusMaintenanceBanner, statusMaintenancePage:
+	default:
+		return fmt.Errorf("maintenance_mode must be %q, %q or %q, got %q", statusMaintenanceOff, statusMaintenanceBanner, statusMaintenancePage, cfg.StatusMaintenanceMode)
+	}
+	switch cfg.MetricsPushFormat {
+	case "", metricsPushFormatInflux, metricsPushFormatStatsD:
+	default:
+		return fmt.Errorf("metrics_push_format must be %q or %q, got %q", metricsPushFormatInflux, metricsPushFormatStatsD, cfg.MetricsPushFormat)
+	}
+	if cfg.MetricsPushAddr != "" {
+		if _, _, err := net.SplitHostPort(cfg.MetricsPushAddr); err != nil {
+			return fmt.Errorf("metrics_push_addr must be host:port, got %q: %v", cfg.MetricsPushAddr, err)
+		}
+		if cfg.MetricsPushIntervalSeconds < 1 {
+			return fmt.Errorf("metrics_push_interval_seconds must be >= 1, got %d", cfg.MetricsPushIntervalSeconds)
+		}
+	}
+	switch cfg.CoinbasePayoutMode {
+	case "", coinbasePayoutModeAuto, coinbasePayoutModeSinglePool:
+	default:
+		return fmt.Errorf("coinbase_payout_mode must be %q or %q, got %q", coinbasePayoutModeAuto, coinbasePayoutModeSinglePool, cfg.CoinbasePayoutMode)
+	}
+	switch cfg.DegradedFeedSharePolicy {
+	case "", degradedFeedShareLenient, degradedFeedShareStrict:
+	default:
+		return fmt.Errorf("degraded_feed_share_policy must be %q or %q, got %q", degradedFeedShareLenient, degradedFeedShareStrict, cfg.DegradedFeedSharePolicy)
+	}
+	if cfg.CoinbaseDustThreshold < 0 {
+		return fmt.Errorf("coinbase_dust_threshold_sats cannot be negative")
+	}
 	if cfg.OperatorDonationPercent > 0 && strings.TrimSpace(cfg.OperatorDonationAddress) == "" {
 		return fmt.Errorf("operator_donation_address is required when operator_donation_percent > 0")
 	}
+	if err := validatePayoutSplits(cfg.PayoutSplits); err != nil {
+		return err
+	}
 	if cfg.HashrateEMATauSeconds <= 0 {
 		return fmt.Errorf("hashrate_ema_tau_seconds must be > 0, got %v", cfg.HashrateEMATauSeconds)
 	}
@@ -115,6 +209,12 @@ func validateConfig(cfg Config) error {
 	if cfg.ShareNTimeMaxForwardSeconds <= 0 {
 		return fmt.Errorf("share_ntime_max_forward_seconds must be > 0, got %v", cfg.ShareNTimeMaxForwardSeconds)
 	}
+	if cfg.ScriptTimeMaxDriftSeconds < 0 {
+		return fmt.Errorf("script_time_max_drift_seconds cannot be negative, got %d", cfg.ScriptTimeMaxDriftSeconds)
+	}
+	if cfg.ShareMaxJobAgeSeconds < 0 {
+		return fmt.Errorf("share_max_job_age_seconds cannot be negative, got %d", cfg.ShareMaxJobAgeSeconds)
+	}
 	if normalizeShareJobFreshnessMode(cfg.ShareJobFreshnessMode) < 0 {
 		return fmt.Errorf("share_job_freshness_mode must be one of %d, %d, or %d", shareJobFreshnessOff, shareJobFreshnessJobID, shareJobFreshnessJobIDPrev)
 	}
@@ -133,11 +233,141 @@ func validateConfig(cfg Config) error {
 	if cfg.ReconnectBanWindowSeconds < 0 {
 		return fmt.Errorf("reconnect_ban_window_seconds cannot be negative")
 	}
+	if cfg.DiscordBlockCooldownSeconds < 0 || cfg.DiscordWorkerBlockCooldownSeconds < 0 {
+		return fmt.Errorf("discord block cooldowns cannot be negative")
+	}
+	if cfg.DiscordWorkerHashrateAlertMinutes < 0 {
+		return fmt.Errorf("discord worker_hashrate_alert_minutes cannot be negative")
+	}
 	if cfg.ReconnectBanDurationSeconds < 0 {
 		return fmt.Errorf("reconnect_ban_duration_seconds cannot be negative")
 	}
+	if cfg.StratumTLSClientCA != "" && strings.TrimSpace(cfg.StratumTLSListen) == "" {
+		return fmt.Errorf("stratum_tls_client_ca requires stratum_tls_listen")
+	}
+	if cfg.StratumWebSocketPath == "/" || strings.HasPrefix(cfg.StratumWebSocketPath, "/api/") || strings.HasPrefix(cfg.StratumWebSocketPath, "/admin") {
+		return fmt.Errorf("stratum_websocket_path %q would shadow status pages; use a dedicated path such as /stratum", cfg.StratumWebSocketPath)
+	}
+	if strings.TrimSpace(cfg.StratumProxyListen) != "" && len(cfg.StratumProxyHMACSecret) < minStratumProxyHMACSecretLen {
+		return fmt.Errorf("stratum_proxy_listen requires stratum_proxy_hmac_secret in secrets.toml (at least %d characters)", minStratumProxyHMACSecretLen)
+	}
+	if cfg.HashrateDropAlertPercent < 0 || cfg.HashrateDropAlertPercent >= 100 {
+		return fmt.Errorf("hashrate_drop_alert_percent must be >= 0 and < 100, got %v", cfg.HashrateDropAlertPercent)
+	}
+	if cfg.HashrateDropAlertPercent > 0 && cfg.HashrateDropAlertWindow < hashrateDropSampleInterval {
+		return fmt.Errorf("hashrate_drop_alert_window_seconds must be >= %d when the alert is enabled, got %v", int(hashrateDropSampleInterval/time.Second), cfg.HashrateDropAlertWindow)
+	}
+	if cfg.HashrateDropAlertDebounce < 0 {
+		return fmt.Errorf("hashrate_drop_alert_debounce_seconds must be >= 0, got %v", cfg.HashrateDropAlertDebounce)
+	}
+	if cfg.NearMissFactor != 0 && !(cfg.NearMissFactor > 1) {
+		return fmt.Errorf("near_miss_factor must be 0 (disabled) or greater than 1, got %v", cfg.NearMissFactor)
+	}
+	if cfg.PayoutAddressCheckInterval < 0 {
+		return fmt.Errorf("payout_address_check_interval_seconds cannot be negative")
+	}
+	if cfg.PayoutAddressCheckInterval > 0 && cfg.PayoutAddressCheckInterval < minPayoutAddressCheckInterval {
+		return fmt.Errorf("payout_address_check_interval_seconds must be 0 or at least %d", int(minPayoutAddressCheckInterval/time.Second))
+	}
+	if cfg.LongpollTimeout < 0 {
+		return fmt.Errorf("longpoll_timeout_seconds cannot be negative")
+	}
+	if cfg.LongpollTimeout > 0 && cfg.LongpollTimeout < minLongpollTimeout {
+		return fmt.Errorf("longpoll_timeout_seconds must be 0 or at least %d", int(minLongpollTimeout/time.Second))
+	}
+	if cfg.TLSInitialTimeout < 0 {
+		return fmt.Errorf("tls_initial_timeout_seconds cannot be negative")
+	}
+	if cfg.SourcePortChurnPerMin < 0 {
+		return fmt.Errorf("source_port_churn_per_min cannot be negative")
+	}
+	if strings.TrimSpace(cfg.BanFeedSource) != "" && cfg.BanFeedRefreshSeconds < 60 {
+		return fmt.Errorf("ban_feed_refresh_seconds must be at least 60")
+	}
+	if cfg.NonceCheckMinSamples < 0 {
+		return fmt.Errorf("nonce_check_min_samples cannot be negative")
+	}
+	if cfg.NonceCheckBanAfter < 0 {
+		return fmt.Errorf("nonce_check_ban_after cannot be negative")
+	}
+	if cfg.MaxConnectionLifetime < 0 {
+		return fmt.Errorf("max_connection_lifetime_seconds cannot be negative")
+	}
+	if err := validateOperatorFields(cfg.OperatorFields); err != nil {
+		return err
+	}
+	if cfg.WriteStallTimeout < 0 {
+		return fmt.Errorf("write_stall_timeout_seconds cannot be negative")
+	}
+	if cfg.WriteStallTimeout > 0 && cfg.WriteStallTimeout < minWriteStallTimeout {
+		return fmt.Errorf("write_stall_timeout_seconds must be 0 (disabled) or >= %d", int(minWriteStallTimeout/time.Second))
+	}
+	if cfg.NotifyHeartbeatInterval < 0 {
+		return fmt.Errorf("notify_heartbeat_seconds cannot be negative")
+	}
+	if cfg.NotifyHeartbeatInterval > 0 && cfg.NotifyHeartbeatInterval < minNotifyHeartbeatInterval {
+		return fmt.Errorf("notify_heartbeat_seconds must be 0 (disabled) or >= %d", int(minNotifyHeartbeatInterval/time.Second))
+	}
+	if cfg.StatsNotifyInterval != 0 && (cfg.StatsNotifyInterval < minStatsNotifyInterval || cfg.StatsNotifyInterval > maxStatsNotifyInterval) {
+		return fmt.Errorf("stats_notify_seconds must be 0 (disabled) or between %d and %d", int(minStatsNotifyInterval/time.Second), int(maxStatsNotifyInterval/time.Second))
+	}
+	if cfg.MemoryBudgetMB < 0 {
+		return fmt.Errorf("[memory] budget_mb cannot be negative")
+	}
+	if cfg.MemoryBudgetMB > 0 && cfg.MemoryBudgetMB < minMemoryBudgetMB {
+		return fmt.Errorf("[memory] budget_mb must be 0 or at least %d, got %d", minMemoryBudgetMB, cfg.MemoryBudgetMB)
+	}
+	if cfg.MaxConnectionLifetime > 0 && cfg.MaxConnectionLifetime < minMaxConnectionLifetime {
+		return fmt.Errorf("max_connection_lifetime_seconds must be 0 (disabled) or >= %d", int(minMaxConnectionLifetime/time.Second))
+	}
 	if cfg.StratumMessagesPerMinute < 0 {
 		return fmt.Errorf("stratum_messages_per_minute cannot be negative")
 	}
+	if cfg.StatusRequestsPerSecond < 0 {
+		return fmt.Errorf("status_requests_per_second cannot be negative")
+	}
+	if cfg.AcceptRPCLatencyBackoffMs < 0 {
+		return fmt.Errorf("accept_rpc_latency_backoff_ms cannot be negative")
+	}
+	if cfg.AcceptRPCLatencyBackoffMs > 0 && (cfg.AcceptRPCLatencyMinPercent <= 0 || cfg.AcceptRPCLatencyMinPercent > 100) {
+		return fmt.Errorf("accept_rpc_latency_min_percent must be > 0 and <= 100, got %v", cfg.AcceptRPCLatencyMinPercent)
+	}
+	if cfg.StatusMaxInflightRequests < 0 {
+		return fmt.Errorf("status_max_inflight_requests cannot be negative")
+	}
+	if cfg.ShareFloodSharesPerMin < 0 {
+		return fmt.Errorf("share_flood_shares_per_min cannot be negative")
+	}
+	if cfg.ShareFloodSharesPerMin > 0 && cfg.ShareFloodSharesPerMin <= cfg.TargetSharesPerMin*2 {
+		return fmt.Errorf("share_flood_shares_per_min must be 0 (disabled) or more than twice target_shares_per_min (%v), got %v", cfg.TargetSharesPerMin, cfg.ShareFloodSharesPerMin)
+	}
+	if cfg.ShareFloodHold < 0 {
+		return fmt.Errorf("share_flood_hold_seconds cannot be negative")
+	}
+	if cfg.LogTraceSamplePercent < 0 || cfg.LogTraceSamplePercent > 100 {
+		return fmt.Errorf("trace_sample_percent must be >= 0 and <= 100, got %v", cfg.LogTraceSamplePercent)
+	}
+	switch cfg.LogFormat {
+	case "", logFormatText, logFormatJSON:
+	default:
+		return fmt.Errorf("format must be %q or %q, got %q", logFormatText, logFormatJSON, cfg.LogFormat)
+	}
+	if cfg.LogRetentionDays < 0 {
+		return fmt.Errorf("retention_days must be >= 0, got %d", cfg.LogRetentionDays)
+	}
+	if cfg.AutoProfileGoroutines < 0 || cfg.AutoProfileHeapMB < 0 {
+		return fmt.Errorf("auto_profile_goroutines and auto_profile_heap_mb must be >= 0, got %d and %d", cfg.AutoProfileGoroutines, cfg.AutoProfileHeapMB)
+	}
+	if (cfg.AutoProfileGoroutines > 0 || cfg.AutoProfileHeapMB > 0) && cfg.AutoProfileMinInterval < autoProfileCheckInterval {
+		return fmt.Errorf("auto_profile_interval_seconds must be >= %d when auto profiling is enabled, got %v", int(autoProfileCheckInterval/time.Second), cfg.AutoProfileMinInterval)
+	}
+	if cfg.LogRetentionFiles < 0 {
+		return fmt.Errorf("retention_files must be >= 0, got %d", cfg.LogRetentionFiles)
+	}
+	for _, txid := range cfg.RequiredTemplateTxids {
+		if !isHexTxid(txid) {
+			return fmt.Errorf("required_template_txids entry %q must be a 64-character hex txid", txid)
+		}
+	}
 	return nil
 }
@@ -27,6 +27,9 @@ const (
 	defaultPoolFeePercent          = 2.0
 	defaultRecentJobs              = 10
 	defaultConnectionTimeout       = 3 * time.Minute
+	// Max connection lifetime is opt-in (0 disables); when enabled, 24h is the
+	// recommended value and per-connection jitter spreads reconnects out.
+	defaultMaxConnectionLifetime = time.Duration(0)
 
 	// Accept rate limiting defaults.
 	defaultMaxAcceptsPerSecond               = 500
@@ -37,11 +40,20 @@ const (
 	defaultAcceptSteadyStateRate             = 50
 	defaultAcceptSteadyStateReconnectPercent = 5.0
 	defaultAcceptSteadyStateReconnectWindow  = 60
+	defaultAcceptRPCLatencyMinPercent        = 10.0
 	defaultStratumMessagesPerMinute          = 0
 
 	defaultJobEntropy                = 4
 	maxJobEntropy                    = 16
 	defaultCoinbaseScriptSigMaxBytes = 100
+	defaultCoinbaseTagCheckInterval  = 24 * time.Hour
+	// defaultCoinbaseMaxBytes is the standard-transaction weight limit
+	// (400k WU) in non-witness bytes; coinbase_max_bytes may only lower it.
+	defaultCoinbaseMaxBytes = 100_000
+	// maxCoinbasePayoutOutputs caps the payout outputs of one coinbase
+	// (fee slices, sub-slices and the remainder); smaller outputs past the
+	// cap fold into the remainder.
+	maxCoinbasePayoutOutputs = 32
 
 	defaultMaxConns = 50000
 
@@ -57,8 +69,10 @@ const (
 	defaultReconnectBanThreshold       = 60
 	defaultReconnectBanWindowSeconds   = 60
 	defaultReconnectBanDurationSeconds = 3600
+	defaultBanFeedRefreshSeconds       = 3600
 
 	defaultDiscordWorkerNotifyThresholdSeconds = 300
+	defaultDiscordBlockCooldownSeconds         = 3600
 
 	defaultMaxDifficulty = 0
 	defaultMinDifficulty = 256.0
@@ -94,6 +108,14 @@ const (
 
 	defaultAutoAcceptRateLimits    = true
 	defaultOperatorDonationPercent = 0.0
+	// Dust folding is opt-in so upgrades never change payout outputs; 546
+	// (Bitcoin Core's P2PKH dust limit, the largest of the standard output
+	// types) is the value to use when enabling it.
+	defaultCoinbaseDustThreshold = 0
+
+	// defaultSubmitPanicDisconnectAfter drops a connection whose submits keep
+	// panicking the submission workers.
+	defaultSubmitPanicDisconnectAfter = 3
 
 	defaultPeerCleanupEnabled   = false
 	defaultPeerCleanupMaxPingMs = 250
@@ -123,16 +145,30 @@ const (
 	vardiffHighWarmupSamplesMin  = 8
 	vardiffHighWarmupStreakNeed  = 3
 	vardiffWarmupDownwardBias    = 0.93
-	vardiffTimeoutGuardMinQuiet  = 20 * time.Second
-	vardiffTimeoutGuardLead      = 5 * time.Second
-	vardiffTimeoutGuardThreshold = 0.7
-	vardiffTimeoutGuardMaxPZero  = 0.01
-	vardiffUncertaintyAbsRatio   = 2.0
-	vardiffUncertaintyMinSamples = 4
-	hashrateControlTauFactor     = 0.35
-	hashrateControlTauMin        = 45 * time.Second
-	startupDiffPrimingFactor     = 0.75
-	startupDiffPrimingMinFactor  = 0.60
+	// Stale-reject feedback (vardiff_stale_feedback_percent): each point of
+	// stale rate above the threshold lowers the target by Gain points,
+	// never below MinBias, once MinSamples recent submits are known.
+	vardiffStaleFeedbackGain       = 2.0
+	vardiffStaleFeedbackMinBias    = 0.5
+	vardiffStaleFeedbackMinSamples = 32
+	// worker_difficulty_sync only converges connections whose hashrates are
+	// within this factor of each other (mixed hardware keeps its own vardiff).
+	workerDiffSyncMaxHashrateRatio = 4.0
+	vardiffTimeoutGuardMinQuiet    = 20 * time.Second
+	vardiffTimeoutGuardLead        = 5 * time.Second
+	vardiffTimeoutGuardThreshold   = 0.7
+	vardiffTimeoutGuardMaxPZero    = 0.01
+	vardiffUncertaintyAbsRatio     = 2.0
+	vardiffUncertaintyMinSamples   = 4
+	hashrateControlTauFactor       = 0.35
+	hashrateControlTauMin          = 45 * time.Second
+	startupDiffPrimingFactor       = 0.75
+	startupDiffPrimingMinFactor    = 0.60
+
+	// Share-flood protection is opt-in (0 disables); the hold applies once an
+	// operator sets share_flood_shares_per_min.
+	defaultShareFloodSharesPerMin = 0
+	defaultShareFloodHold         = 5 * time.Minute
 
 	defaultHashrateEMATauSeconds = 450.0
 	initialHashrateEMATau        = 45 * time.Second
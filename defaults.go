@@ -12,8 +12,12 @@ func defaultConfig() Config {
 		StatusTagline:                       defaultStatusTagline,
 		FiatCurrency:                        defaultFiatCurrency,
 		DiscordWorkerNotifyThresholdSeconds: defaultDiscordWorkerNotifyThresholdSeconds,
+		DiscordBlockCooldownSeconds:         defaultDiscordBlockCooldownSeconds,
+		MetricsPushFormat:                   metricsPushFormatInflux,
+		MetricsPushIntervalSeconds:          defaultMetricsPushIntervalSeconds,
 		GitHubURL:                           defaultGitHubURL,
 		MempoolAddressURL:                   defaultMempoolAddressURL,
+		StatusMaintenanceMode:               statusMaintenanceOff,
 		StratumTLSListen:                    defaultStratumTLSListen,
 		StratumPasswordEnabled:              false,
 		StratumPassword:                     "",
@@ -22,6 +26,7 @@ func defaultConfig() Config {
 		CKPoolEmulate:                       true,
 		StratumTCPReadBufferBytes:           0,
 		StratumTCPWriteBufferBytes:          0,
+		MaxConnectionLifetime:               defaultMaxConnectionLifetime,
 		ClerkIssuerURL:                      defaultClerkIssuerURL,
 		ClerkJWKSURL:                        defaultClerkJWKSURL,
 		ClerkSignInURL:                      defaultClerkSignInURL,
@@ -34,12 +39,20 @@ func defaultConfig() Config {
 		RPCURL:                              defaultRPCURL,
 		PoolEntropy:                         generatePoolEntropy(),
 		PoolFeePercent:                      defaultPoolFeePercent,
+		CoinbaseDustThreshold:               defaultCoinbaseDustThreshold,
+		CoinbasePayoutMode:                  coinbasePayoutModeAuto,
+		DegradedFeedSharePolicy:             degradedFeedShareLenient,
+		StratumBadIDPolicy:                  stratumBadIDPolicyCompat,
+		AutoProfileMinInterval:              defaultAutoProfileMinInterval,
+		HashrateDropAlertWindow:             defaultHashrateDropWindow,
+		HashrateDropAlertDebounce:           defaultHashrateDropDebounce,
 		OperatorDonationPercent:             defaultOperatorDonationPercent,
 		Extranonce2Size:                     defaultExtranonce2Size,
 		TemplateExtraNonce2Size:             defaultTemplateExtraNonce2Size,
 		JobEntropy:                          defaultJobEntropy,
 		CoinbaseMsg:                         poolSoftwareName,
 		CoinbaseScriptSigMaxBytes:           defaultCoinbaseScriptSigMaxBytes,
+		CoinbaseTagCheckInterval:            defaultCoinbaseTagCheckInterval,
 		ZMQHashBlockAddr:                    defaultZMQHashBlockAddr,
 		ZMQRawBlockAddr:                     defaultZMQRawBlockAddr,
 		BackblazeBackupIntervalSeconds:      defaultBackblazeBackupIntervalSeconds,
@@ -55,6 +68,7 @@ func defaultConfig() Config {
 		AcceptBurstWindow:                   defaultAcceptBurstWindow,
 		AcceptSteadyStateWindow:             defaultAcceptSteadyStateWindow,
 		AcceptSteadyStateRate:               defaultAcceptSteadyStateRate,
+		AcceptRPCLatencyMinPercent:          defaultAcceptRPCLatencyMinPercent,
 		AcceptSteadyStateReconnectPercent:   defaultAcceptSteadyStateReconnectPercent,
 		AcceptSteadyStateReconnectWindow:    defaultAcceptSteadyStateReconnectWindow,
 		StratumMessagesPerMinute:            defaultStratumMessagesPerMinute,
@@ -73,6 +87,8 @@ func defaultConfig() Config {
 		VarDiffEnabled:                      true,
 		DifficultyStepGranularity:           defaultDifficultyStepGranularity,
 		EnforceSuggestedDifficultyLimits:    false,
+		ShareFloodSharesPerMin:              defaultShareFloodSharesPerMin,
+		ShareFloodHold:                      defaultShareFloodHold,
 		HashrateEMATauSeconds:               defaultHashrateEMATauSeconds,
 		HashrateCumulativeEnabled:           false,
 		HashrateRecentCumulativeEnabled:     false,
@@ -80,6 +96,8 @@ func defaultConfig() Config {
 		ShareNTimeMaxForwardSeconds:         defaultShareNTimeMaxForwardSeconds,
 		CleanExpiredBansOnStartup:           true,
 		LogDebug:                            false,
+		LogRetentionDays:                    logRetentionDays,
+		LogFormat:                           logFormatText,
 		LogNetDebug:                         false,
 		ShareJobFreshnessMode:               shareJobFreshnessJobID,
 		ShareCheckNTimeWindow:               true,
@@ -88,6 +106,7 @@ func defaultConfig() Config {
 		ShareCheckParamFormat:               true,
 		ShareRequireWorkerMatch:             false,
 		SubmitProcessInline:                 false,
+		SubmitPanicDisconnectAfter:          defaultSubmitPanicDisconnectAfter,
 		ShareCheckDuplicate:                 true,
 		BanInvalidSubmissionsAfter:          defaultBanInvalidSubmissionsAfter,
 		BanInvalidSubmissionsWindow:         defaultBanInvalidSubmissionsWindow,
@@ -95,6 +114,7 @@ func defaultConfig() Config {
 		ReconnectBanThreshold:               defaultReconnectBanThreshold,
 		ReconnectBanWindowSeconds:           defaultReconnectBanWindowSeconds,
 		ReconnectBanDurationSeconds:         defaultReconnectBanDurationSeconds,
+		BanFeedRefreshSeconds:               defaultBanFeedRefreshSeconds,
 		PeerCleanupEnabled:                  defaultPeerCleanupEnabled,
 		PeerCleanupMaxPingMs:                defaultPeerCleanupMaxPingMs,
 		PeerCleanupMinPeers:                 defaultPeerCleanupMinPeers,
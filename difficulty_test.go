@@ -97,3 +97,52 @@ func TestQuantizeDifficultyGranularity(t *testing.T) {
 		})
 	}
 }
+
+// TestTargetFromDifficulty_TinyDifficulties covers regtest/testnet-style
+// difficulties below 1: targets grow past diff1 but never wrap past 256 bits.
+func TestTargetFromDifficulty_TinyDifficulties(t *testing.T) {
+	if got := targetFromDifficulty(1); got.Cmp(diff1Target) != 0 {
+		t.Fatalf("diff 1 target = %x, want diff1Target", got)
+	}
+	if got, want := targetFromDifficulty(0.5), new(big.Int).Lsh(diff1Target, 1); got.Cmp(want) != 0 {
+		t.Fatalf("diff 0.5 target = %x, want %x", got, want)
+	}
+
+	prev := targetFromDifficulty(1)
+	for _, diff := range []float64{1e-3, 1e-6, 1e-9, minShareDifficulty, 1e-12, 1e-300, math.SmallestNonzeroFloat64} {
+		got := targetFromDifficulty(diff)
+		if got.Sign() <= 0 || got.Cmp(maxUint256) > 0 {
+			t.Fatalf("targetFromDifficulty(%g) out of range: %x", diff, got)
+		}
+		if got.Cmp(prev) < 0 {
+			t.Fatalf("targetFromDifficulty(%g) decreased as difficulty fell", diff)
+		}
+		prev = got
+	}
+	if got := targetFromDifficulty(minShareDifficulty / 2); got.Cmp(maxUint256) != 0 {
+		t.Fatalf("difficulty below the floor should saturate at maxUint256")
+	}
+	if got := targetFromDifficulty(math.NaN()); got.Cmp(maxUint256) != 0 {
+		t.Fatalf("NaN difficulty should map to maxUint256")
+	}
+	if got := targetFromDifficulty(math.Inf(1)); got.Cmp(big.NewInt(1)) != 0 {
+		t.Fatalf("+Inf difficulty should map to target 1, got %x", got)
+	}
+}
+
+func TestClampDifficultyTinyBounds(t *testing.T) {
+	mc := &MinerConn{cfg: Config{MinDifficulty: 1e-6, MaxDifficulty: 1e-3, DifficultyStepGranularity: 1}}
+	if got := mc.clampDifficulty(1); got > 1e-3 || got < 1e-6 {
+		t.Fatalf("clampDifficulty(1) = %g, want within [1e-6, 1e-3]", got)
+	}
+	if got := mc.clampDifficulty(1e-9); got < 1e-6 || got > 1e-3 {
+		t.Fatalf("clampDifficulty(1e-9) = %g, want within [1e-6, 1e-3]", got)
+	}
+
+	mc = &MinerConn{cfg: Config{DifficultyStepGranularity: 1}}
+	for _, diff := range []float64{0, 1e-300} {
+		if got := mc.clampDifficulty(diff); got < minShareDifficulty {
+			t.Fatalf("clampDifficulty(%g) = %g, want >= minShareDifficulty", diff, got)
+		}
+	}
+}
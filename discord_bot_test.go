@@ -202,3 +202,72 @@ func TestDiscordNotifierUpdateWorkerStates_NoOfflineWithoutPriorOnline(t *testin
 		t.Fatalf("unexpected offline notification without prior online: offline=%v online=%v", offline, online)
 	}
 }
+
+func TestDiscordNotifierUpdateHashrateStates_AlertDebounceAndRecovery(t *testing.T) {
+	s := &StatusServer{}
+	cfg := defaultConfig()
+	cfg.DiscordWorkerNotifyThresholdSeconds = 60
+	cfg.DiscordWorkerHashrateAlertMinutes = 10
+	s.UpdateConfig(cfg)
+
+	n := &discordNotifier{s: s}
+	userID := "user-6"
+	hash := "worker-hash-6"
+	t0 := time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC)
+	current := map[string]float64{hash: 1e12}
+	step := func(at time.Time, rate float64) (zero, back []string) {
+		current[hash] = rate
+		return n.updateHashrateStates(userID, current, at)
+	}
+
+	if zero, back := step(t0, 1e12); len(zero) != 0 || len(back) != 0 {
+		t.Fatalf("unexpected notifications on first observation: zero=%v back=%v", zero, back)
+	}
+
+	// A short stall that resumes before the threshold never alerts.
+	step(t0.Add(1*time.Minute), 0)
+	step(t0.Add(9*time.Minute), 1e12)
+	if zero, back := step(t0.Add(12*time.Minute), 0); len(zero) != 0 || len(back) != 0 {
+		t.Fatalf("unexpected notifications after short stall: zero=%v back=%v", zero, back)
+	}
+
+	t1 := t0.Add(22 * time.Minute)
+	if zero, _ := step(t1, 0); len(zero) != 1 || zero[0] != hash {
+		t.Fatalf("expected zero-hashrate alert after threshold, got %v", zero)
+	}
+	if zero, _ := step(t1.Add(time.Minute), 0); len(zero) != 0 {
+		t.Fatalf("zero-hashrate alert repeated: %v", zero)
+	}
+
+	// A brief burst of shares neither announces recovery nor re-alerts.
+	step(t1.Add(2*time.Minute), 1e12)
+	step(t1.Add(2*time.Minute+30*time.Second), 0)
+	if zero, back := step(t1.Add(20*time.Minute), 0); len(zero) != 0 || len(back) != 0 {
+		t.Fatalf("unexpected notifications after flapping: zero=%v back=%v", zero, back)
+	}
+
+	t2 := t1.Add(21 * time.Minute)
+	step(t2, 1e12)
+	if _, back := step(t2.Add(time.Minute), 1e12); len(back) != 1 || back[0] != hash {
+		t.Fatalf("expected recovery after notify threshold, got %v", back)
+	}
+
+	// Disconnected workers are left to the online/offline notifications.
+	delete(current, hash)
+	n.updateHashrateStates(userID, current, t2.Add(2*time.Minute))
+	if _, ok := n.hashrateByUser[userID]; ok {
+		t.Fatalf("expected state for disconnected worker to be dropped")
+	}
+}
+
+func TestSavedWorkerHashrate_IgnoresQuietConnections(t *testing.T) {
+	now := time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)
+	views := []WorkerView{
+		{RollingHashrate: 100, LastShare: now.Add(-30 * time.Second)},
+		{RollingHashrate: 50, LastShare: now.Add(-workerHashrateIdleAfter)},
+		{RollingHashrate: 25},
+	}
+	if got := savedWorkerHashrate(views, now); got != 100 {
+		t.Fatalf("savedWorkerHashrate = %v, want 100", got)
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// workerHashrateIdleAfter is how long a connection may go without an accepted
+// share before its rolling hashrate is treated as zero. The decayed EMA never
+// reaches zero on its own, and vardiff aims for several shares per minute, so a
+// connection this quiet is not doing useful work.
+const workerHashrateIdleAfter = 2 * time.Minute
+
+// workerHashrateState tracks the zero-hashrate alert for one saved worker.
+type workerHashrateState struct {
+	Zero  bool
+	Since time.Time
+
+	// HadHashrate is set once the worker has been seen hashing, so a
+	// connection that never submits is not reported as a drop.
+	HadHashrate bool
+
+	// Alerted stays set until the recovery ping, so brief bursts of shares
+	// while stalled neither re-alert nor announce a recovery.
+	Alerted bool
+}
+
+// savedWorkerHashrate sums the rolling hashrate of a saved worker's live
+// connections, ignoring connections that have gone quiet.
+func savedWorkerHashrate(views []WorkerView, now time.Time) float64 {
+	var total float64
+	for _, v := range views {
+		if v.RollingHashrate <= 0 || v.LastShare.IsZero() || now.Sub(v.LastShare) >= workerHashrateIdleAfter {
+			continue
+		}
+		total += v.RollingHashrate
+	}
+	return total
+}
+
+func (n *discordNotifier) workerHashrateAlertThreshold() time.Duration {
+	if n == nil || n.s == nil {
+		return 0
+	}
+	if v := n.s.Config().DiscordWorkerHashrateAlertMinutes; v > 0 {
+		return time.Duration(v) * time.Minute
+	}
+	return 0
+}
+
+// updateHashrateStates advances the zero-hashrate tracking for a user's
+// connected saved workers. A worker is reported once its hashrate has stayed at
+// zero for the alert threshold, and reported recovered once it has been hashing
+// again for the regular worker notify threshold. Workers missing from current
+// (disconnected or notifications off) are forgotten; the online/offline
+// notifications already cover disconnects.
+func (n *discordNotifier) updateHashrateStates(userID string, current map[string]float64, now time.Time) (zeroOverdue, recovered []string) {
+	alertAfter := n.workerHashrateAlertThreshold()
+	recoverAfter := n.workerNotifyThreshold()
+
+	n.stateMu.Lock()
+	defer n.stateMu.Unlock()
+
+	if alertAfter <= 0 {
+		n.hashrateByUser = nil
+		return nil, nil
+	}
+	if n.hashrateByUser == nil {
+		n.hashrateByUser = make(map[string]map[string]workerHashrateState, 16)
+	}
+	state := n.hashrateByUser[userID]
+	if state == nil {
+		state = make(map[string]workerHashrateState, len(current))
+		n.hashrateByUser[userID] = state
+	}
+
+	for hash, rate := range current {
+		zero := rate <= 0
+		st, ok := state[hash]
+		if !ok {
+			state[hash] = workerHashrateState{Zero: zero, Since: now, HadHashrate: !zero}
+			continue
+		}
+		if st.Zero != zero {
+			st.Zero = zero
+			st.Since = now
+			if !zero {
+				st.HadHashrate = true
+			}
+			state[hash] = st
+			continue
+		}
+		elapsed := now.Sub(st.Since)
+		switch {
+		case zero && st.HadHashrate && !st.Alerted && elapsed >= alertAfter:
+			st.Alerted = true
+			zeroOverdue = append(zeroOverdue, hash)
+		case !zero && st.Alerted && elapsed >= recoverAfter:
+			st.Alerted = false
+			recovered = append(recovered, hash)
+		default:
+			continue
+		}
+		state[hash] = st
+	}
+
+	for hash := range state {
+		if _, ok := current[hash]; !ok {
+			delete(state, hash)
+		}
+	}
+	if len(state) == 0 {
+		delete(n.hashrateByUser, userID)
+	}
+	return zeroOverdue, recovered
+}
+
+// hashrateAlertParts renders the zero-hashrate and recovery pings in the same
+// short form as the online/offline notifications.
+func (n *discordNotifier) hashrateAlertParts(zeroOverdue, recovered []string, nameByHash map[string]string) []string {
+	if len(zeroOverdue) == 0 && len(recovered) == 0 {
+		return nil
+	}
+	alertLabel := formatNotifyThresholdLabel(n.workerHashrateAlertThreshold())
+	recoverLabel := formatNotifyThresholdLabel(n.workerNotifyThreshold())
+	parts := make([]string, 0, 2)
+	if len(zeroOverdue) <= 1 && len(recovered) <= 1 {
+		if len(zeroOverdue) > 0 {
+			parts = append(parts, "No hashrate >"+alertLabel+": "+strings.Join(renderNames(zeroOverdue, nameByHash), ", "))
+		}
+		if len(recovered) > 0 {
+			parts = append(parts, "Hashing again ("+recoverLabel+"+): "+strings.Join(renderNames(recovered, nameByHash), ", "))
+		}
+		return parts
+	}
+	if len(zeroOverdue) > 0 {
+		parts = append(parts, fmt.Sprintf("%d miners with no hashrate >%s", len(zeroOverdue), alertLabel))
+	}
+	if len(recovered) > 0 {
+		parts = append(parts, fmt.Sprintf("%d miners hashing again (%s+)", len(recovered), recoverLabel))
+	}
+	return parts
+}
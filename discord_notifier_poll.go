@@ -135,6 +135,7 @@ func (n *discordNotifier) checkUser(link discordLink, now time.Time) {
 	}
 
 	currentOnline := make(map[string]bool, len(saved))
+	currentHashrate := make(map[string]float64, len(saved))
 	nameByHash := make(map[string]string, len(saved))
 	for _, sw := range saved {
 		if !sw.NotifyEnabled {
@@ -145,19 +146,23 @@ func (n *discordNotifier) checkUser(link discordLink, now time.Time) {
 			continue
 		}
 		currentOnline[lookupHash] = len(views) > 0
+		if len(views) > 0 {
+			currentHashrate[lookupHash] = savedWorkerHashrate(views, now)
+		}
 		if _, ok := nameByHash[lookupHash]; !ok {
 			nameByHash[lookupHash] = sw.Name
 		}
 	}
 
 	offlineOverdue, onlineOverdue := n.updateWorkerStates(link.UserID, currentOnline, now)
-	if len(offlineOverdue) == 0 && len(onlineOverdue) == 0 {
+	zeroOverdue, hashingAgain := n.updateHashrateStates(link.UserID, currentHashrate, now)
+	if len(offlineOverdue) == 0 && len(onlineOverdue) == 0 && len(zeroOverdue) == 0 && len(hashingAgain) == 0 {
 		return
 	}
 
 	thresholdLabel := formatNotifyThresholdLabel(n.workerNotifyThreshold())
 	detailed := len(offlineOverdue) <= 1 && len(onlineOverdue) <= 1
-	parts := make([]string, 0, 3)
+	parts := make([]string, 0, 4)
 	if detailed {
 		if len(offlineOverdue) > 0 {
 			parts = append(parts, "Offline >"+thresholdLabel+": "+strings.Join(renderNames(offlineOverdue, nameByHash), ", "))
@@ -173,6 +178,7 @@ func (n *discordNotifier) checkUser(link discordLink, now time.Time) {
 			parts = append(parts, fmt.Sprintf("%d miners back online (%s+)", len(onlineOverdue), thresholdLabel))
 		}
 	}
+	parts = append(parts, n.hashrateAlertParts(zeroOverdue, hashingAgain, nameByHash)...)
 
 	line := strings.Join(parts, " | ")
 	n.enqueuePing(link.DiscordUserID, line)
@@ -198,6 +204,14 @@ func (n *discordNotifier) NotifyFoundBlock(worker string, height int64, hashHex
 	if now.IsZero() {
 		now = time.Now()
 	}
+	cfg := n.s.Config()
+	announce, ping := n.blockDedupe.allow(worker, hashHex, now,
+		time.Duration(cfg.DiscordBlockCooldownSeconds)*time.Second,
+		time.Duration(cfg.DiscordWorkerBlockCooldownSeconds)*time.Second)
+	if !announce {
+		logger.Info("block found notice already sent; skipping", "height", height, "hash", hashHex)
+		return
+	}
 
 	workerLabel := shortWorkerName(worker, workerNamePrefix, workerNameSuffix)
 	if workerLabel == "" {
@@ -212,6 +226,9 @@ func (n *discordNotifier) NotifyFoundBlock(worker string, height int64, hashHex
 	ts := now.Unix()
 	line := fmt.Sprintf("Block found: height %d by %s (hash %s) at <t:%d:F>", height, workerLabel, hashLabel, ts)
 	n.enqueueEveryoneNotice(line)
+	if !ping {
+		return
+	}
 
 	subscribers, err := n.s.workerLists.ListNotifiedUsersForWorker(worker)
 	if err != nil || len(subscribers) == 0 {
@@ -236,6 +253,17 @@ func (n *discordNotifier) NotifyFoundBlock(worker string, height int64, hashHex
 	}
 }
 
+// NotifyPoolAlert posts an operator alert to the notify channel.
+func (n *discordNotifier) NotifyPoolAlert(msg string) {
+	if n == nil || n.dg == nil || !n.enabled() {
+		return
+	}
+	if strings.TrimSpace(n.notifyChannelID) == "" {
+		return
+	}
+	n.enqueueNotice(msg)
+}
+
 func (n *discordNotifier) workerNotifyThreshold() time.Duration {
 	sec := defaultDiscordWorkerNotifyThresholdSeconds
 	if n != nil && n.s != nil {
@@ -419,12 +447,10 @@ func (n *discordNotifier) sweep(active map[string]struct{}) {
 		return
 	}
 	n.lastSweepAt = now
-	if n.statusByUser == nil {
-		return
-	}
 	if active == nil {
 		// Nothing enabled; clear everything.
 		n.statusByUser = nil
+		n.hashrateByUser = nil
 		return
 	}
 	for uid := range n.statusByUser {
@@ -432,6 +458,11 @@ func (n *discordNotifier) sweep(active map[string]struct{}) {
 			delete(n.statusByUser, uid)
 		}
 	}
+	for uid := range n.hashrateByUser {
+		if _, ok := active[uid]; !ok {
+			delete(n.hashrateByUser, uid)
+		}
+	}
 }
 
 func (n *discordNotifier) clearUserOfflineState(userID string) {
@@ -441,9 +472,8 @@ func (n *discordNotifier) clearUserOfflineState(userID string) {
 	}
 	n.stateMu.Lock()
 	defer n.stateMu.Unlock()
-	if n.statusByUser != nil {
-		delete(n.statusByUser, userID)
-	}
+	delete(n.statusByUser, userID)
+	delete(n.hashrateByUser, userID)
 }
 
 func minInt(a, b int) int {
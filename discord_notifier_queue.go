@@ -23,11 +23,7 @@ func (n *discordNotifier) noticePrefix() string {
 	// distinct tag in Discord messages.
 	tag := displayPoolTagFromCoinbaseMessage(cfg.CoinbaseMsg)
 	if tag == "" {
-		brand := poolSoftwareName
-		if cfg.PoolTagPrefix != "" {
-			brand = cfg.PoolTagPrefix + "-" + brand
-		}
-		tag = "/" + brand + "/"
+		tag = poolCoinbaseTag(cfg.PoolTagPrefix)
 	}
 	// For Discord notices, use a compact bracket tag without slashes.
 	tag = strings.Trim(tag, "/")
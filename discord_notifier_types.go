@@ -19,6 +19,7 @@ type discordNotifier struct {
 	notifyChannelID    string
 	stateMu            sync.Mutex
 	statusByUser       map[string]map[string]workerNotifyState // clerk user_id -> workerHash -> state
+	hashrateByUser     map[string]map[string]workerHashrateState
 	lastSweepAt        time.Time
 	links              []discordLink
 	linkIdx            int
@@ -30,6 +31,8 @@ type discordNotifier struct {
 	droppedQueuedLines int
 	lastDropNoticeAt   time.Time
 
+	blockDedupe blockFoundDedupe
+
 	netMu        sync.Mutex
 	networkOK    bool
 	networkKnown bool
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const firstJobWatchdogInterval = 5 * time.Second
+
+// firstJobWatchdog tracks how long the pool has gone without its first job
+// template since the Stratum listeners came up. A node in IBD/syncing gets
+// its own (usually much longer) limit; once the node reports synced, the
+// normal limit counts from that moment.
+type firstJobWatchdog struct {
+	start       time.Time
+	syncedSince time.Time
+	alertedIBD  bool
+	alerted     bool
+}
+
+func newFirstJobWatchdog(start time.Time) *firstJobWatchdog {
+	return &firstJobWatchdog{start: start, syncedSince: start}
+}
+
+// check returns a non-empty alert message the first time a limit is crossed
+// (at most once for the syncing case and once for the synced case).
+func (w *firstJobWatchdog) check(now time.Time, syncing bool, limit, ibdLimit time.Duration) string {
+	if syncing {
+		w.syncedSince = time.Time{}
+		if ibdLimit > 0 && !w.alertedIBD && now.Sub(w.start) >= ibdLimit {
+			w.alertedIBD = true
+			return fmt.Sprintf("No job template %s after startup; the node is still syncing (IBD).", humanShortDuration(now.Sub(w.start)))
+		}
+		return ""
+	}
+	if w.syncedSince.IsZero() {
+		w.syncedSince = now
+	}
+	if limit > 0 && !w.alerted && now.Sub(w.syncedSince) >= limit {
+		w.alerted = true
+		return fmt.Sprintf("No job template %s after startup; the node reports synced but is not producing work.", humanShortDuration(now.Sub(w.start)))
+	}
+	return ""
+}
+
+// runFirstJobWatchdog alerts when the pool has no job template within
+// first_job_alert_seconds of the Stratum listeners coming up, so a pool that
+// sits gated forever pages the operator. It exits once the first job arrives.
+func runFirstJobWatchdog(ctx context.Context, jobMgr *JobManager, cfgFn func() Config, metrics *PoolMetrics, notifier *discordNotifier) {
+	if jobMgr == nil || cfgFn == nil {
+		return
+	}
+	w := newFirstJobWatchdog(time.Now())
+	ticker := time.NewTicker(firstJobWatchdogInterval)
+	defer ticker.Stop()
+	for {
+		now := time.Now()
+		if job := jobMgr.CurrentJob(); job != nil && !job.CreatedAt.IsZero() {
+			logger.Info("first job template received", "component", "startup", "kind", "first_job", "after", now.Sub(w.start).Round(time.Millisecond))
+			if w.alerted || w.alertedIBD {
+				notifier.NotifyPoolAlert("Pool received its first job template; mining is available.")
+			}
+			return
+		}
+		cfg := cfgFn()
+		ibd, blocks, headers, fetchedAt := jobMgr.nodeSyncSnapshot()
+		syncing := stratumNodeSyncSnapshotFresh(now, fetchedAt) && (ibd || (headers > 0 && blocks < headers))
+		if msg := w.check(now, syncing, cfg.FirstJobAlertTimeout, cfg.FirstJobAlertIBDTimeout); msg != "" {
+			fields := []any{"component", "startup", "kind", "first_job",
+				"waited", now.Sub(w.start).Round(time.Second),
+				"syncing", syncing, "blocks", blocks, "headers", headers}
+			if fs := jobMgr.FeedStatus(); fs.LastError != nil {
+				lastErr := strings.TrimSpace(fs.LastError.Error())
+				fields = append(fields, "last_error", lastErr)
+				msg += " Last error: " + lastErr
+			}
+			logger.Error("no job template since startup", fields...)
+			if metrics != nil {
+				metrics.RecordErrorEvent("first_job", msg, now)
+			}
+			notifier.NotifyPoolAlert(msg)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
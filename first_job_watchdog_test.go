@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFirstJobWatchdogSyncedNode(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	w := newFirstJobWatchdog(start)
+	if msg := w.check(start.Add(59*time.Second), false, time.Minute, 0); msg != "" {
+		t.Fatalf("alerted before the limit: %q", msg)
+	}
+	if msg := w.check(start.Add(time.Minute), false, time.Minute, 0); msg == "" {
+		t.Fatalf("expected alert at the limit")
+	}
+	if msg := w.check(start.Add(2*time.Minute), false, time.Minute, 0); msg != "" {
+		t.Fatalf("expected a single alert, got %q", msg)
+	}
+}
+
+func TestFirstJobWatchdogIBD(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	w := newFirstJobWatchdog(start)
+
+	// While syncing only the IBD limit applies; 0 never alerts.
+	if msg := w.check(start.Add(time.Hour), true, time.Minute, 0); msg != "" {
+		t.Fatalf("alerted while syncing without an IBD limit: %q", msg)
+	}
+	if msg := w.check(start.Add(2*time.Hour), true, time.Minute, 2*time.Hour); msg == "" {
+		t.Fatalf("expected IBD alert at the IBD limit")
+	}
+
+	// Once synced, the normal limit counts from the moment sync finished.
+	synced := start.Add(3 * time.Hour)
+	if msg := w.check(synced, false, time.Minute, 2*time.Hour); msg != "" {
+		t.Fatalf("alerted immediately after sync: %q", msg)
+	}
+	if msg := w.check(synced.Add(time.Minute), false, time.Minute, 2*time.Hour); msg == "" {
+		t.Fatalf("expected alert once synced node produced no work")
+	}
+}
@@ -32,18 +32,22 @@ func startFoundBlockLogger() {
 				continue
 			}
 
-			// Use the shared state database connection
-			db := getSharedStateDB()
-			if db == nil {
+			line := strings.TrimSpace(string(entry.Line))
+			if line == "" {
 				continue
 			}
+			rec := accountingRecord{Kind: accountingRecordFoundBlock, CreatedAtUnix: time.Now().Unix(), JSON: line}
 
-			line := strings.TrimSpace(string(entry.Line))
-			if line == "" {
+			// Use the shared state database connection
+			db := getSharedStateDB()
+			if db == nil {
+				notePendingAccountingRecord(rec)
 				continue
 			}
-			if _, err := db.Exec("INSERT INTO found_blocks_log (created_at_unix, json) VALUES (?, ?)", time.Now().Unix(), line); err != nil {
+			if _, err := db.Exec("INSERT INTO found_blocks_log (created_at_unix, json) VALUES (?, ?)", rec.CreatedAtUnix, line); err != nil {
 				logger.Warn("found block sqlite insert", "error", err)
+				// Keep the record so the shutdown flush can retry it.
+				notePendingAccountingRecord(rec)
 			}
 		}
 	}()
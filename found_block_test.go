@@ -211,6 +211,7 @@ func TestFoundBlockSubmission_DualPayout(t *testing.T) {
 		totalValue,
 		feePercent,
 		job.WitnessCommitment,
+		job.extraOpReturnScript,
 		job.Template.CoinbaseAux.Flags,
 		job.CoinbaseMsg,
 		job.ScriptTime,
@@ -148,19 +148,41 @@ func (h *fileServerWithFallback) reserveStaticCacheSpace(size int64) {
 	}
 }
 
+// PreloadCache reads every cacheable static asset and swaps the result in as
+// the live cache. Nothing is replaced unless the whole walk succeeds.
 func (h *fileServerWithFallback) PreloadCache() error {
 	if h == nil {
 		return nil
 	}
-	if h.staticFS == nil {
-		return fmt.Errorf("static asset filesystem not configured")
+	cache, cacheBytes, err := h.loadStaticCache()
+	if err != nil {
+		return err
 	}
 	h.cacheMu.Lock()
-	if h.cache == nil {
-		h.cache = make(map[string]cachedStaticFile)
-	}
+	h.cache = cache
+	h.cacheBytes = cacheBytes
 	h.cacheMu.Unlock()
+	return nil
+}
 
+// ReloadCache rebuilds the static cache. It is atomic: on failure the
+// previous cache keeps serving and the error is returned.
+func (h *fileServerWithFallback) ReloadCache() error {
+	if err := h.PreloadCache(); err != nil {
+		return fmt.Errorf("%w (previous static cache kept)", err)
+	}
+	return nil
+}
+
+// loadStaticCache builds a fresh cache map without touching the live one.
+// Files past the total cache budget are skipped; ServePath still reads them
+// on demand.
+func (h *fileServerWithFallback) loadStaticCache() (map[string]cachedStaticFile, int64, error) {
+	if h.staticFS == nil {
+		return nil, 0, fmt.Errorf("static asset filesystem not configured")
+	}
+	cache := make(map[string]cachedStaticFile)
+	var cacheBytes int64
 	err := fs.WalkDir(h.staticFS, ".", func(assetPath string, d fs.DirEntry, walkErr error) error {
 		if walkErr != nil {
 			return walkErr
@@ -175,7 +197,7 @@ func (h *fileServerWithFallback) PreloadCache() error {
 		if err != nil {
 			return nil
 		}
-		if !canCacheStaticFile(info) {
+		if !canCacheStaticFile(info) || cacheBytes+info.Size() > staticCacheMaxBytes {
 			return nil
 		}
 		cleanPath, ok := cleanStaticAssetPath(assetPath)
@@ -189,28 +211,19 @@ func (h *fileServerWithFallback) PreloadCache() error {
 		if int64(len(payload)) != info.Size() {
 			return nil
 		}
-
-		h.reserveStaticCacheSpace(info.Size())
-
-		contentType := detectContentType(cleanPath, payload)
-		h.storeCached(cleanPath, info, payload, contentType)
+		cache[cleanPath] = cachedStaticFile{
+			payload:     payload,
+			size:        info.Size(),
+			modTime:     info.ModTime(),
+			contentType: detectContentType(cleanPath, payload),
+		}
+		cacheBytes += info.Size()
 		return nil
 	})
 	if err != nil {
-		return err
+		return nil, 0, err
 	}
-	return nil
-}
-
-func (h *fileServerWithFallback) ReloadCache() error {
-	if h == nil {
-		return nil
-	}
-	h.cacheMu.Lock()
-	h.cache = make(map[string]cachedStaticFile)
-	h.cacheBytes = 0
-	h.cacheMu.Unlock()
-	return h.PreloadCache()
+	return cache, cacheBytes, nil
 }
 
 func (h *fileServerWithFallback) serveFallback(w http.ResponseWriter, r *http.Request) {
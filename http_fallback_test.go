@@ -0,0 +1,39 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// failingFS fails every directory walk so a cache rebuild cannot complete.
+type failingFS struct{ fstest.MapFS }
+
+func (f failingFS) ReadDir(string) ([]fs.DirEntry, error) {
+	return nil, errors.New("asset read failed")
+}
+
+func TestReloadCacheKeepsPreviousCacheOnFailure(t *testing.T) {
+	staticFS := fstest.MapFS{"app.css": &fstest.MapFile{Data: []byte("body{}")}}
+	h := newStaticFileServer(staticFS, http.NotFoundHandler())
+	if err := h.PreloadCache(); err != nil {
+		t.Fatalf("preload: %v", err)
+	}
+
+	h.staticFS = failingFS{staticFS}
+	if err := h.ReloadCache(); err == nil {
+		t.Fatalf("expected reload error")
+	}
+
+	rec := httptest.NewRecorder()
+	if !h.ServeCached(rec, httptest.NewRequest(http.MethodGet, "/app.css", nil), "app.css") {
+		t.Fatalf("previous cache entry dropped by a failed reload")
+	}
+	if !strings.Contains(rec.Body.String(), "body{}") {
+		t.Fatalf("unexpected body %q", rec.Body.String())
+	}
+}
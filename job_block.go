@@ -26,8 +26,11 @@ func buildMerkleBranches(txids [][]byte) []string {
 			layer = append(layer, layer[L-1])
 			L++
 		}
+		// layer[0] stands in for the coinbase side of the tree (unknown to
+		// the pool) and layer[1] was just emitted as its sibling, so the
+		// remaining pairs start at index 2.
 		next := make([][]byte, 0, L/2)
-		for i := 1; i+1 < L; i += 2 {
+		for i := 2; i+1 < L; i += 2 {
 			joined := append(append([]byte{}, layer[i]...), layer[i+1]...)
 			next = append(next, doubleSHA256(joined))
 		}
@@ -262,7 +265,7 @@ func buildBlockWithScriptTime(job *Job, extranonce1 []byte, extranonce2 []byte,
 		return "", nil, nil, nil, fmt.Errorf("payout script is required")
 	}
 
-	coinbaseTx, coinbaseTxid, err := serializeCoinbaseTx(job.Template.Height, extranonce1, extranonce2, job.TemplateExtraNonce2Size, payoutScript, job.CoinbaseValue, job.WitnessCommitment, job.Template.CoinbaseAux.Flags, job.CoinbaseMsg, scriptTime)
+	coinbaseTx, coinbaseTxid, err := serializeCoinbaseTx(job.Template.Height, extranonce1, extranonce2, job.TemplateExtraNonce2Size, payoutScript, job.CoinbaseValue, job.WitnessCommitment, job.extraOpReturnScript, job.Template.CoinbaseAux.Flags, job.CoinbaseMsg, scriptTime)
 	if err != nil {
 		return "", nil, nil, nil, fmt.Errorf("coinbase build: %w", err)
 	}
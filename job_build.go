@@ -47,15 +47,9 @@ func (jm *JobManager) buildJob(ctx context.Context, tpl GetBlockTemplateResult)
 		}
 		coinbaseMsg = msg
 	}
-	if jm.cfg.CoinbaseScriptSigMaxBytes > 0 {
-		trimmed, truncated, err := clampCoinbaseMessage(coinbaseMsg, jm.cfg.CoinbaseScriptSigMaxBytes, tpl.Height, scriptTime, tpl.CoinbaseAux.Flags, jm.cfg.Extranonce2Size, jm.cfg.TemplateExtraNonce2Size)
-		if err != nil {
-			return nil, fmt.Errorf("coinbase scriptsig limit: %w", err)
-		}
-		if truncated {
-			logger.Debug("clamped coinbase message to meet scriptSig limit", "limit", jm.cfg.CoinbaseScriptSigMaxBytes, "message", trimmed)
-		}
-		coinbaseMsg = trimmed
+	coinbaseMsg, err = jm.clampJobCoinbaseMessage(coinbaseMsg, tpl.Height, scriptTime, tpl.CoinbaseAux.Flags)
+	if err != nil {
+		return nil, err
 	}
 
 	var prevBytes [32]byte
@@ -89,11 +83,17 @@ func (jm *JobManager) buildJob(ctx context.Context, tpl GetBlockTemplateResult)
 		commitScript = b
 	}
 
+	extraOpReturn, err := coinbaseExtraOpReturnScript(jm.cfg.CoinbaseExtraOpReturn)
+	if err != nil {
+		return nil, err
+	}
+
 	job := &Job{
 		JobID:                   jm.nextJobID(),
 		Template:                tpl,
 		Target:                  target,
 		targetBE:                uint256BEFromBigInt(target),
+		networkDiff:             difficultyFromTarget(target),
 		CreatedAt:               time.Now(),
 		ScriptTime:              scriptTime,
 		Extranonce2Size:         jm.cfg.Extranonce2Size,
@@ -106,34 +106,68 @@ func (jm *JobManager) buildJob(ctx context.Context, tpl GetBlockTemplateResult)
 		TransactionIDs:          txids,
 		PayoutScript:            jm.payoutScript,
 		DonationScript:          jm.donationScript,
+		PayoutSplits:            jm.payoutSplits,
 		OperatorDonationPercent: jm.cfg.OperatorDonationPercent,
+		CoinbaseDustThreshold:   jm.cfg.CoinbaseDustThreshold,
 		VersionMask:             computePoolMask(tpl, jm.cfg),
 		PrevHash:                tpl.Previous,
 		prevHashBytes:           prevBytes,
 		bitsBytes:               bitsBytes,
 		coinbaseFlagsBytes:      flagsBytes,
 		witnessCommitScript:     commitScript,
+		extraOpReturnScript:     extraOpReturn,
 		TemplateExtraNonce2Size: jm.cfg.TemplateExtraNonce2Size,
 	}
+	if jm.cfg.CoinbasePartsCache {
+		job.coinbaseTails = newCoinbaseTailCache()
+	}
 
 	return job, nil
 }
 
+// clampJobCoinbaseMessage applies the coinbase upgrade marker (when active)
+// and the coinbase_scriptsig_max_bytes limit to msg. A marker that would be
+// truncated is dropped entirely rather than shipped partially.
+func (jm *JobManager) clampJobCoinbaseMessage(msg string, height, scriptTime int64, flags string) (string, error) {
+	plain := msg
+	msg = jm.withCoinbaseUpgradeMarker(msg)
+	limit := jm.cfg.CoinbaseScriptSigMaxBytes
+	if limit <= 0 {
+		return msg, nil
+	}
+	trimmed, truncated, err := clampCoinbaseMessage(msg, limit, height, scriptTime, flags, jm.cfg.Extranonce2Size, jm.cfg.TemplateExtraNonce2Size)
+	if err == nil && truncated && msg != plain {
+		trimmed, truncated, err = clampCoinbaseMessage(plain, limit, height, scriptTime, flags, jm.cfg.Extranonce2Size, jm.cfg.TemplateExtraNonce2Size)
+	}
+	if err != nil {
+		return "", fmt.Errorf("coinbase scriptsig limit: %w", err)
+	}
+	if truncated {
+		logger.Debug("clamped coinbase message to meet scriptSig limit", "limit", limit, "message", trimmed)
+	}
+	return trimmed, nil
+}
+
 func buildCoinbaseMsgWithSuffix(base, poolEntropy string, jobEntropy int) (string, error) {
 	suffix, err := buildPoolSuffix(poolEntropy, jobEntropy)
 	if err != nil {
 		return "", fmt.Errorf("coinbase suffix: %w", err)
 	}
+	return appendCoinbaseTagPart(base, suffix), nil
+}
+
+// appendCoinbaseTagPart joins part onto a coinbase tag with a single '/'.
+func appendCoinbaseTagPart(base, part string) string {
 	if base == "" {
-		return suffix, nil
+		return part
 	}
-	if suffix == "" {
-		return base, nil
+	if part == "" {
+		return base
 	}
 	if strings.HasSuffix(base, "/") {
-		return base + suffix, nil
+		return base + part
 	}
-	return base + "/" + suffix, nil
+	return base + "/" + part
 }
 
 func buildPoolSuffix(poolEntropy string, jobEntropy int) (string, error) {
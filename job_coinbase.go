@@ -7,6 +7,7 @@ import (
 	"math"
 	"sort"
 	"strings"
+	"sync/atomic"
 )
 
 // coinbasePayoutOutput describes a single non-witness-commitment output in a
@@ -21,6 +22,10 @@ type coinbasePayoutPlan struct {
 	RemainderScript          []byte
 	FeeSlices                []coinbaseFeeSlice
 	RequireRemainderPositive bool
+	// DustThreshold, when positive, folds fee outputs below this many sats
+	// into the output they were split from (see foldDustPayouts) and rejects
+	// a remainder below it. Zero keeps every output as computed.
+	DustThreshold int64
 }
 
 // coinbaseFeeSlice describes a percentage-based deduction from the total block
@@ -52,7 +57,64 @@ type coinbaseFeeSliceBreakdown struct {
 	SubValues   []int64
 }
 
-const maxCoinbasePayoutOutputs = 32
+// coinbaseMaxBytes mirrors [mining].coinbase_max_bytes for the coinbase
+// builders, which run without access to Config.
+var coinbaseMaxBytes atomic.Int64
+
+func setCoinbaseMaxBytes(n int) {
+	if n <= 0 || n > defaultCoinbaseMaxBytes {
+		n = defaultCoinbaseMaxBytes
+	}
+	coinbaseMaxBytes.Store(int64(n))
+}
+
+// checkCoinbaseSize rejects a serialized coinbase larger than the configured
+// ceiling so an oversized payout layout fails at job build instead of
+// producing a block the node would refuse.
+func checkCoinbaseSize(n int) error {
+	limit := coinbaseMaxBytes.Load()
+	if limit <= 0 {
+		limit = defaultCoinbaseMaxBytes
+	}
+	if int64(n) > limit {
+		return fmt.Errorf("coinbase transaction is %d bytes, exceeds limit %d", n, limit)
+	}
+	return nil
+}
+
+// capCoinbasePayoutOutputs keeps at most limit outputs by folding the smallest
+// non-remainder outputs into the remainder output (always the last entry), so
+// the total value is unchanged. Kept outputs stay in their original order. It
+// returns the folded value and the indexes (into payouts) that were folded.
+func capCoinbasePayoutOutputs(payouts []coinbasePayoutOutput, limit int) ([]coinbasePayoutOutput, int64, []int) {
+	if limit <= 0 || len(payouts) <= limit {
+		return payouts, 0, nil
+	}
+	last := len(payouts) - 1
+	order := make([]int, last)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return payouts[order[a]].Value > payouts[order[b]].Value
+	})
+	foldedIdx := append([]int(nil), order[limit-1:]...)
+	dropped := make(map[int]bool, len(foldedIdx))
+	var folded int64
+	for _, i := range foldedIdx {
+		dropped[i] = true
+		folded += payouts[i].Value
+	}
+	capped := make([]coinbasePayoutOutput, 0, limit)
+	for i, o := range payouts[:last] {
+		if !dropped[i] {
+			capped = append(capped, o)
+		}
+	}
+	remainder := payouts[last]
+	remainder.Value += folded
+	return append(capped, remainder), folded, foldedIdx
+}
 
 func validateCoinbasePayoutOutputs(outputs []coinbasePayoutOutput) error {
 	if len(outputs) == 0 {
@@ -80,7 +142,6 @@ func computeCoinbasePayouts(plan coinbasePayoutPlan) ([]coinbasePayoutOutput, *c
 		return nil, nil, fmt.Errorf("remainder payout script required")
 	}
 	remaining := plan.TotalValue
-	payouts := make([]coinbasePayoutOutput, 0, 1+len(plan.FeeSlices))
 
 	breakdown := &coinbasePayoutBreakdown{
 		TotalValue: plan.TotalValue,
@@ -108,7 +169,6 @@ func computeCoinbasePayouts(plan coinbasePayoutPlan) ([]coinbasePayoutOutput, *c
 
 		feeRemaining := feeTotal
 		subValues := make([]int64, 0, len(fee.SubSlices))
-		subPayouts := make([]coinbasePayoutOutput, 0, len(fee.SubSlices))
 		for j, sub := range fee.SubSlices {
 			if len(sub.Script) == 0 {
 				return nil, nil, fmt.Errorf("fee slice %d subslice %d script required", i, j)
@@ -128,14 +188,8 @@ func computeCoinbasePayouts(plan coinbasePayoutPlan) ([]coinbasePayoutOutput, *c
 			feeRemaining -= subAmt
 
 			subValues = append(subValues, subAmt)
-			subPayouts = append(subPayouts, coinbasePayoutOutput{Script: sub.Script, Value: subAmt})
 		}
 
-		// The builder emits fee slice outputs first (then subslices). Final
-		// on-wire ordering is handled by buildCoinbaseOutputs.
-		payouts = append(payouts, coinbasePayoutOutput{Script: fee.Script, Value: feeRemaining})
-		payouts = append(payouts, subPayouts...)
-
 		breakdown.FeeSlices = append(breakdown.FeeSlices, coinbaseFeeSliceBreakdown{
 			FeeTotal:    feeTotal,
 			ParentValue: feeRemaining,
@@ -144,10 +198,50 @@ func computeCoinbasePayouts(plan coinbasePayoutPlan) ([]coinbasePayoutOutput, *c
 	}
 
 	breakdown.RemainderValue = remaining
-	if plan.RequireRemainderPositive && remaining <= 0 {
+	if plan.DustThreshold > 0 {
+		foldDustPayouts(breakdown, plan.DustThreshold)
+		if breakdown.RemainderValue < plan.DustThreshold {
+			return nil, nil, fmt.Errorf("remainder payout %d sats is below dust threshold %d", breakdown.RemainderValue, plan.DustThreshold)
+		}
+	}
+	if plan.RequireRemainderPositive && breakdown.RemainderValue <= 0 {
 		return nil, nil, fmt.Errorf("remainder payout must be positive after applying fees")
 	}
-	payouts = append(payouts, coinbasePayoutOutput{Script: plan.RemainderScript, Value: remaining})
+
+	// The builder emits fee slice outputs first (then subslices). Final
+	// on-wire ordering is handled by buildCoinbaseOutputs. Outputs folded
+	// away as dust are omitted entirely.
+	// sources[k] is the breakdown entry behind payouts[k], so outputs folded
+	// by the cap below can be zeroed there as well.
+	payouts := make([]coinbasePayoutOutput, 0, 1+len(plan.FeeSlices))
+	sources := make([]*int64, 0, 1+len(plan.FeeSlices))
+	for i, fee := range plan.FeeSlices {
+		sb := &breakdown.FeeSlices[i]
+		if plan.DustThreshold <= 0 || sb.ParentValue > 0 {
+			payouts = append(payouts, coinbasePayoutOutput{Script: fee.Script, Value: sb.ParentValue})
+			sources = append(sources, &sb.ParentValue)
+		}
+		for j, sub := range fee.SubSlices {
+			if plan.DustThreshold <= 0 || sb.SubValues[j] > 0 {
+				payouts = append(payouts, coinbasePayoutOutput{Script: sub.Script, Value: sb.SubValues[j]})
+				sources = append(sources, &sb.SubValues[j])
+			}
+		}
+	}
+	payouts = append(payouts, coinbasePayoutOutput{Script: plan.RemainderScript, Value: breakdown.RemainderValue})
+	if capped, folded, foldedIdx := capCoinbasePayoutOutputs(payouts, maxCoinbasePayoutOutputs); len(capped) != len(payouts) {
+		logger.Warn("coinbase payout outputs capped",
+			"component", "coinbase", "kind", "payouts",
+			"outputs", len(payouts),
+			"limit", maxCoinbasePayoutOutputs,
+			"folded_sats", folded,
+		)
+		payouts = capped
+		for _, k := range foldedIdx {
+			*sources[k] = 0
+		}
+		breakdown.RemainderValue += folded
+	}
 
 	if err := validateCoinbasePayoutOutputs(payouts); err != nil {
 		return nil, nil, err
@@ -155,7 +249,31 @@ func computeCoinbasePayouts(plan coinbasePayoutPlan) ([]coinbasePayoutOutput, *c
 	return payouts, breakdown, nil
 }
 
-func buildCoinbaseOutputs(commitmentScript []byte, payouts []coinbasePayoutOutput) ([]byte, error) {
+// foldDustPayouts moves any fee output below dust into the output it was
+// carved from: a sub-slice (e.g. the operator donation) folds into its parent
+// fee output, and a parent fee output folds into the remainder (worker)
+// output. Folded values are zeroed in the breakdown; the total is unchanged.
+func foldDustPayouts(breakdown *coinbasePayoutBreakdown, dust int64) {
+	for i := range breakdown.FeeSlices {
+		sb := &breakdown.FeeSlices[i]
+		for j, v := range sb.SubValues {
+			if v < dust {
+				sb.ParentValue += v
+				sb.SubValues[j] = 0
+			}
+		}
+		if sb.ParentValue < dust {
+			breakdown.RemainderValue += sb.ParentValue
+			sb.ParentValue = 0
+		}
+	}
+}
+
+// buildCoinbaseOutputs encodes the witness commitment (when present) first,
+// then the payouts, then the optional extra OP_RETURN output. The extra output
+// cannot start with the commitment header (see validateCoinbaseExtraOpReturn),
+// so the commitment stays the only BIP141 match.
+func buildCoinbaseOutputs(commitmentScript, extraOpReturn []byte, payouts []coinbasePayoutOutput) ([]byte, error) {
 	if err := validateCoinbasePayoutOutputs(payouts); err != nil {
 		return nil, err
 	}
@@ -174,6 +292,9 @@ func buildCoinbaseOutputs(commitmentScript []byte, payouts []coinbasePayoutOutpu
 	for _, o := range orderedPayouts {
 		total += 8 + 9 + len(o.Script)
 	}
+	if len(extraOpReturn) > 0 {
+		total += 8 + 9 + len(extraOpReturn)
+	}
 	total += 9 // output count varint upper bound
 	outputs.Grow(total)
 
@@ -181,6 +302,9 @@ func buildCoinbaseOutputs(commitmentScript []byte, payouts []coinbasePayoutOutpu
 	if len(commitmentScript) > 0 {
 		outputCount++
 	}
+	if len(extraOpReturn) > 0 {
+		outputCount++
+	}
 	writeVarInt(&outputs, outputCount)
 	if len(commitmentScript) > 0 {
 		writeUint64LE(&outputs, 0)
@@ -192,13 +316,18 @@ func buildCoinbaseOutputs(commitmentScript []byte, payouts []coinbasePayoutOutpu
 		writeVarInt(&outputs, uint64(len(o.Script)))
 		outputs.Write(o.Script)
 	}
+	if len(extraOpReturn) > 0 {
+		writeUint64LE(&outputs, 0)
+		writeVarInt(&outputs, uint64(len(extraOpReturn)))
+		outputs.Write(extraOpReturn)
+	}
 	return outputs.Bytes(), nil
 }
 
 // serializeCoinbaseTxPayoutsPredecoded builds a coinbase tx with 1..N payout
-// outputs plus an optional witness commitment output. Payout outputs are
-// encoded largest-to-smallest by value.
-func serializeCoinbaseTxPayoutsPredecoded(height int64, extranonce1, extranonce2 []byte, templateExtraNonce2Size int, payouts []coinbasePayoutOutput, commitmentScript []byte, flagsBytes []byte, coinbaseMsg string, scriptTime int64) ([]byte, []byte, error) {
+// outputs plus an optional witness commitment output and an optional extra
+// OP_RETURN output. Payout outputs are encoded largest-to-smallest by value.
+func serializeCoinbaseTxPayoutsPredecoded(height int64, extranonce1, extranonce2 []byte, templateExtraNonce2Size int, payouts []coinbasePayoutOutput, commitmentScript []byte, extraOpReturn []byte, flagsBytes []byte, coinbaseMsg string, scriptTime int64) ([]byte, []byte, error) {
 	padLen := max(templateExtraNonce2Size-len(extranonce2), 0)
 	placeholderLen := len(extranonce1) + len(extranonce2) + padLen
 	extraNoncePlaceholder := bytes.Repeat([]byte{0x00}, placeholderLen)
@@ -227,7 +356,7 @@ func serializeCoinbaseTxPayoutsPredecoded(height int64, extranonce1, extranonce2
 	vin.Write(scriptSigPart2)
 	writeUint32LE(&vin, 0)
 
-	outputs, err := buildCoinbaseOutputs(commitmentScript, payouts)
+	outputs, err := buildCoinbaseOutputs(commitmentScript, extraOpReturn, payouts)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -237,12 +366,15 @@ func serializeCoinbaseTxPayoutsPredecoded(height int64, extranonce1, extranonce2
 	tx.Write(vin.Bytes())
 	tx.Write(outputs)
 	writeUint32LE(&tx, 0)
+	if err := checkCoinbaseSize(tx.Len()); err != nil {
+		return nil, nil, err
+	}
 
 	txid := doubleSHA256(tx.Bytes())
 	return tx.Bytes(), txid, nil
 }
 
-func serializeCoinbaseTx(height int64, extranonce1, extranonce2 []byte, templateExtraNonce2Size int, payoutScript []byte, coinbaseValue int64, witnessCommitment string, coinbaseFlags string, coinbaseMsg string, scriptTime int64) ([]byte, []byte, error) {
+func serializeCoinbaseTx(height int64, extranonce1, extranonce2 []byte, templateExtraNonce2Size int, payoutScript []byte, coinbaseValue int64, witnessCommitment string, extraOpReturn []byte, coinbaseFlags string, coinbaseMsg string, scriptTime int64) ([]byte, []byte, error) {
 	var flagsBytes []byte
 	if coinbaseFlags != "" {
 		b, err := hex.DecodeString(coinbaseFlags)
@@ -259,19 +391,19 @@ func serializeCoinbaseTx(height int64, extranonce1, extranonce2 []byte, template
 		}
 		commitmentScript = b
 	}
-	return serializeCoinbaseTxPredecoded(height, extranonce1, extranonce2, templateExtraNonce2Size, payoutScript, coinbaseValue, commitmentScript, flagsBytes, coinbaseMsg, scriptTime)
+	return serializeCoinbaseTxPredecoded(height, extranonce1, extranonce2, templateExtraNonce2Size, payoutScript, coinbaseValue, commitmentScript, extraOpReturn, flagsBytes, coinbaseMsg, scriptTime)
 }
 
 // serializeCoinbaseTxPredecoded is the hot-path variant that reuses
 // pre-decoded flags/commitment bytes.
-func serializeCoinbaseTxPredecoded(height int64, extranonce1, extranonce2 []byte, templateExtraNonce2Size int, payoutScript []byte, coinbaseValue int64, commitmentScript []byte, flagsBytes []byte, coinbaseMsg string, scriptTime int64) ([]byte, []byte, error) {
+func serializeCoinbaseTxPredecoded(height int64, extranonce1, extranonce2 []byte, templateExtraNonce2Size int, payoutScript []byte, coinbaseValue int64, commitmentScript []byte, extraOpReturn []byte, flagsBytes []byte, coinbaseMsg string, scriptTime int64) ([]byte, []byte, error) {
 	payouts := []coinbasePayoutOutput{{Script: payoutScript, Value: coinbaseValue}}
-	return serializeCoinbaseTxPayoutsPredecoded(height, extranonce1, extranonce2, templateExtraNonce2Size, payouts, commitmentScript, flagsBytes, coinbaseMsg, scriptTime)
+	return serializeCoinbaseTxPayoutsPredecoded(height, extranonce1, extranonce2, templateExtraNonce2Size, payouts, commitmentScript, extraOpReturn, flagsBytes, coinbaseMsg, scriptTime)
 }
 
 // serializeDualCoinbaseTxPredecoded is the hot-path variant that reuses
 // pre-decoded flags/commitment bytes.
-func serializeDualCoinbaseTxPredecoded(height int64, extranonce1, extranonce2 []byte, templateExtraNonce2Size int, poolScript []byte, workerScript []byte, totalValue int64, feePercent float64, commitmentScript []byte, flagsBytes []byte, coinbaseMsg string, scriptTime int64) ([]byte, []byte, error) {
+func serializeDualCoinbaseTxPredecoded(height int64, extranonce1, extranonce2 []byte, templateExtraNonce2Size int, poolScript []byte, workerScript []byte, totalValue int64, feePercent float64, dustThreshold int64, commitmentScript []byte, extraOpReturn []byte, flagsBytes []byte, coinbaseMsg string, scriptTime int64) ([]byte, []byte, error) {
 	if len(poolScript) == 0 || len(workerScript) == 0 {
 		return nil, nil, fmt.Errorf("both pool and worker payout scripts are required")
 	}
@@ -280,17 +412,18 @@ func serializeDualCoinbaseTxPredecoded(height int64, extranonce1, extranonce2 []
 		RemainderScript:          workerScript,
 		FeeSlices:                []coinbaseFeeSlice{{Script: poolScript, Percent: feePercent}},
 		RequireRemainderPositive: true,
+		DustThreshold:            dustThreshold,
 	}
 	payouts, _, err := computeCoinbasePayouts(plan)
 	if err != nil {
 		return nil, nil, err
 	}
-	return serializeCoinbaseTxPayoutsPredecoded(height, extranonce1, extranonce2, templateExtraNonce2Size, payouts, commitmentScript, flagsBytes, coinbaseMsg, scriptTime)
+	return serializeCoinbaseTxPayoutsPredecoded(height, extranonce1, extranonce2, templateExtraNonce2Size, payouts, commitmentScript, extraOpReturn, flagsBytes, coinbaseMsg, scriptTime)
 }
 
 // serializeTripleCoinbaseTxPredecoded is the hot-path variant that reuses
 // pre-decoded flags/commitment bytes.
-func serializeTripleCoinbaseTxPredecoded(height int64, extranonce1, extranonce2 []byte, templateExtraNonce2Size int, poolScript []byte, donationScript []byte, workerScript []byte, totalValue int64, poolFeePercent float64, donationFeePercent float64, commitmentScript []byte, flagsBytes []byte, coinbaseMsg string, scriptTime int64) ([]byte, []byte, error) {
+func serializeTripleCoinbaseTxPredecoded(height int64, extranonce1, extranonce2 []byte, templateExtraNonce2Size int, poolScript []byte, donationScript []byte, workerScript []byte, totalValue int64, poolFeePercent float64, donationFeePercent float64, dustThreshold int64, commitmentScript []byte, extraOpReturn []byte, flagsBytes []byte, coinbaseMsg string, scriptTime int64) ([]byte, []byte, error) {
 	if len(poolScript) == 0 || len(donationScript) == 0 || len(workerScript) == 0 {
 		return nil, nil, fmt.Errorf("pool, donation, and worker payout scripts are all required")
 	}
@@ -299,6 +432,7 @@ func serializeTripleCoinbaseTxPredecoded(height int64, extranonce1, extranonce2
 		RemainderScript:          workerScript,
 		FeeSlices:                []coinbaseFeeSlice{{Script: poolScript, Percent: poolFeePercent, SubSlices: []coinbaseFeeSubSlice{{Script: donationScript, Percent: donationFeePercent}}}},
 		RequireRemainderPositive: true,
+		DustThreshold:            dustThreshold,
 	}
 	payouts, breakdown, err := computeCoinbasePayouts(plan)
 	if err != nil {
@@ -325,7 +459,7 @@ func serializeTripleCoinbaseTxPredecoded(height int64, extranonce1, extranonce2
 		"pool_keeps_sats", poolFee,
 		"worker_sats", breakdown.RemainderValue)
 
-	return serializeCoinbaseTxPayoutsPredecoded(height, extranonce1, extranonce2, templateExtraNonce2Size, payouts, commitmentScript, flagsBytes, coinbaseMsg, scriptTime)
+	return serializeCoinbaseTxPayoutsPredecoded(height, extranonce1, extranonce2, templateExtraNonce2Size, payouts, commitmentScript, extraOpReturn, flagsBytes, coinbaseMsg, scriptTime)
 }
 
 func serializeNumberScript(n int64) []byte {
@@ -427,12 +561,20 @@ func clampCoinbaseMessage(message string, limit int, height int64, scriptTime in
 }
 
 // buildCoinbaseParts constructs coinb1/coinb2 for Stratum notify.
-func buildCoinbaseParts(height int64, extranonce1 []byte, extranonce2Size int, templateExtraNonce2Size int, payoutScript []byte, coinbaseValue int64, witnessCommitment string, coinbaseFlags string, coinbaseMsg string, scriptTime int64) (string, string, error) {
+func buildCoinbaseParts(height int64, extranonce1 []byte, extranonce2Size int, templateExtraNonce2Size int, payoutScript []byte, coinbaseValue int64, witnessCommitment string, extraOpReturn []byte, coinbaseFlags string, coinbaseMsg string, scriptTime int64) (string, string, error) {
 	payouts := []coinbasePayoutOutput{{Script: payoutScript, Value: coinbaseValue}}
-	return buildCoinbasePartsPayouts(height, extranonce1, extranonce2Size, templateExtraNonce2Size, payouts, witnessCommitment, coinbaseFlags, coinbaseMsg, scriptTime)
+	return buildCoinbasePartsPayouts(height, extranonce1, extranonce2Size, templateExtraNonce2Size, payouts, witnessCommitment, extraOpReturn, coinbaseFlags, coinbaseMsg, scriptTime)
+}
+
+func buildCoinbasePartsPayouts(height int64, extranonce1 []byte, extranonce2Size int, templateExtraNonce2Size int, payouts []coinbasePayoutOutput, witnessCommitment string, extraOpReturn []byte, coinbaseFlags string, coinbaseMsg string, scriptTime int64) (string, string, error) {
+	return buildCoinbasePartsPayoutsCached(nil, height, extranonce1, extranonce2Size, templateExtraNonce2Size, payouts, witnessCommitment, extraOpReturn, coinbaseFlags, coinbaseMsg, scriptTime)
 }
 
-func buildCoinbasePartsPayouts(height int64, extranonce1 []byte, extranonce2Size int, templateExtraNonce2Size int, payouts []coinbasePayoutOutput, witnessCommitment string, coinbaseFlags string, coinbaseMsg string, scriptTime int64) (string, string, error) {
+// buildCoinbasePartsPayoutsCached is buildCoinbasePartsPayouts with coinb2
+// (scriptSig tail, outputs and locktime) taken from tails when non-nil.
+// coinb2 does not depend on extranonce1 or scriptTime, so every connection
+// of a job that pays to the same outputs shares it.
+func buildCoinbasePartsPayoutsCached(tails *coinbaseTailCache, height int64, extranonce1 []byte, extranonce2Size int, templateExtraNonce2Size int, payouts []coinbasePayoutOutput, witnessCommitment string, extraOpReturn []byte, coinbaseFlags string, coinbaseMsg string, scriptTime int64) (string, string, error) {
 	if extranonce2Size <= 0 {
 		extranonce2Size = 4
 	}
@@ -470,74 +612,102 @@ func buildCoinbasePartsPayouts(height int64, extranonce1 []byte, extranonce2Size
 	writeVarInt(&p1, uint64(len(scriptSigPart1)+len(extraNoncePlaceholder)+len(scriptSigPart2)))
 	p1.Write(scriptSigPart1)
 
-	// Outputs
+	tail, err := tails.get(payouts, func() (coinbaseTail, error) {
+		return buildCoinbaseTail(scriptSigPart2, witnessCommitment, extraOpReturn, payouts)
+	})
+	if err != nil {
+		return "", "", err
+	}
+	if err := checkCoinbaseSize(p1.Len() + len(extraNoncePlaceholder) + tail.size); err != nil {
+		return "", "", err
+	}
+
+	coinb1 := hex.EncodeToString(p1.Bytes())
+	if padLen > 0 {
+		coinb1 += strings.Repeat("00", padLen)
+	}
+	return coinb1, tail.hex, nil
+}
+
+// buildCoinbaseTail serializes p2: scriptSig_part2 || sequence || outputs ||
+// locktime.
+func buildCoinbaseTail(scriptSigPart2 []byte, witnessCommitment string, extraOpReturn []byte, payouts []coinbasePayoutOutput) (coinbaseTail, error) {
 	var commitmentScript []byte
 	if witnessCommitment != "" {
 		b, err := hex.DecodeString(witnessCommitment)
 		if err != nil {
-			return "", "", fmt.Errorf("decode witness commitment: %w", err)
+			return coinbaseTail{}, fmt.Errorf("decode witness commitment: %w", err)
 		}
 		commitmentScript = b
 	}
-	outputs, err := buildCoinbaseOutputs(commitmentScript, payouts)
+	outputs, err := buildCoinbaseOutputs(commitmentScript, extraOpReturn, payouts)
 	if err != nil {
-		return "", "", err
+		return coinbaseTail{}, err
 	}
 
-	// p2: scriptSig_part2 || sequence || outputs || locktime
 	var p2 bytes.Buffer
 	p2.Write(scriptSigPart2)
 	writeUint32LE(&p2, 0)
 	p2.Write(outputs)
 	writeUint32LE(&p2, 0)
-
-	coinb1 := hex.EncodeToString(p1.Bytes())
-	if padLen > 0 {
-		coinb1 += strings.Repeat("00", padLen)
-	}
-	coinb2 := hex.EncodeToString(p2.Bytes())
-	return coinb1, coinb2, nil
+	return coinbaseTail{hex: hex.EncodeToString(p2.Bytes()), size: p2.Len()}, nil
 }
 
 // buildDualPayoutCoinbaseParts constructs coinbase parts for a dual-payout
 // layout where the block reward is split between a pool-fee output and a
 // worker output. It mirrors buildCoinbaseParts but takes separate scripts for
-// the pool and worker, along with a fee percentage, and is used by
-// MinerConn.sendNotifyFor when dual-payout parameters are available.
-func buildDualPayoutCoinbaseParts(height int64, extranonce1 []byte, extranonce2Size int, templateExtraNonce2Size int, poolScript []byte, workerScript []byte, totalValue int64, feePercent float64, witnessCommitment string, coinbaseFlags string, coinbaseMsg string, scriptTime int64) (string, string, error) {
+// the pool and worker, along with a fee percentage. MinerConn.sendNotifyFor
+// builds the same layout from dualCoinbasePayouts.
+func buildDualPayoutCoinbaseParts(height int64, extranonce1 []byte, extranonce2Size int, templateExtraNonce2Size int, poolScript []byte, workerScript []byte, totalValue int64, feePercent float64, dustThreshold int64, witnessCommitment string, extraOpReturn []byte, coinbaseFlags string, coinbaseMsg string, scriptTime int64) (string, string, error) {
+	payouts, err := dualCoinbasePayouts(poolScript, workerScript, totalValue, feePercent, dustThreshold)
+	if err != nil {
+		return "", "", err
+	}
+	return buildCoinbasePartsPayouts(height, extranonce1, extranonce2Size, templateExtraNonce2Size, payouts, witnessCommitment, extraOpReturn, coinbaseFlags, coinbaseMsg, scriptTime)
+}
+
+// buildTriplePayoutCoinbaseParts constructs coinbase parts for a triple-payout
+// layout where the block reward is split between a pool-fee output, a donation
+// output, and a worker output. This is used when both dual-payout parameters
+// and donation parameters are available.
+func buildTriplePayoutCoinbaseParts(height int64, extranonce1 []byte, extranonce2Size int, templateExtraNonce2Size int, poolScript []byte, donationScript []byte, workerScript []byte, totalValue int64, poolFeePercent float64, donationFeePercent float64, dustThreshold int64, witnessCommitment string, extraOpReturn []byte, coinbaseFlags string, coinbaseMsg string, scriptTime int64) (string, string, error) {
+	payouts, err := tripleCoinbasePayouts(poolScript, donationScript, workerScript, totalValue, poolFeePercent, donationFeePercent, dustThreshold)
+	if err != nil {
+		return "", "", err
+	}
+	return buildCoinbasePartsPayouts(height, extranonce1, extranonce2Size, templateExtraNonce2Size, payouts, witnessCommitment, extraOpReturn, coinbaseFlags, coinbaseMsg, scriptTime)
+}
+
+// dualCoinbasePayouts splits totalValue into a pool-fee output and a worker
+// output (the dual-payout layout).
+func dualCoinbasePayouts(poolScript []byte, workerScript []byte, totalValue int64, feePercent float64, dustThreshold int64) ([]coinbasePayoutOutput, error) {
 	if len(poolScript) == 0 || len(workerScript) == 0 {
-		return "", "", fmt.Errorf("both pool and worker payout scripts are required")
+		return nil, fmt.Errorf("both pool and worker payout scripts are required")
 	}
 	plan := coinbasePayoutPlan{
 		TotalValue:               totalValue,
 		RemainderScript:          workerScript,
 		FeeSlices:                []coinbaseFeeSlice{{Script: poolScript, Percent: feePercent}},
 		RequireRemainderPositive: true,
+		DustThreshold:            dustThreshold,
 	}
 	payouts, _, err := computeCoinbasePayouts(plan)
-	if err != nil {
-		return "", "", err
-	}
-	return buildCoinbasePartsPayouts(height, extranonce1, extranonce2Size, templateExtraNonce2Size, payouts, witnessCommitment, coinbaseFlags, coinbaseMsg, scriptTime)
+	return payouts, err
 }
 
-// buildTriplePayoutCoinbaseParts constructs coinbase parts for a triple-payout
-// layout where the block reward is split between a pool-fee output, a donation
-// output, and a worker output. This is used when both dual-payout parameters
-// and donation parameters are available.
-func buildTriplePayoutCoinbaseParts(height int64, extranonce1 []byte, extranonce2Size int, templateExtraNonce2Size int, poolScript []byte, donationScript []byte, workerScript []byte, totalValue int64, poolFeePercent float64, donationFeePercent float64, witnessCommitment string, coinbaseFlags string, coinbaseMsg string, scriptTime int64) (string, string, error) {
+// tripleCoinbasePayouts splits totalValue into pool-fee, donation and worker
+// outputs (the triple-payout layout).
+func tripleCoinbasePayouts(poolScript []byte, donationScript []byte, workerScript []byte, totalValue int64, poolFeePercent float64, donationFeePercent float64, dustThreshold int64) ([]coinbasePayoutOutput, error) {
 	if len(poolScript) == 0 || len(donationScript) == 0 || len(workerScript) == 0 {
-		return "", "", fmt.Errorf("pool, donation, and worker payout scripts are all required")
+		return nil, fmt.Errorf("pool, donation, and worker payout scripts are all required")
 	}
 	plan := coinbasePayoutPlan{
 		TotalValue:               totalValue,
 		RemainderScript:          workerScript,
 		FeeSlices:                []coinbaseFeeSlice{{Script: poolScript, Percent: poolFeePercent, SubSlices: []coinbaseFeeSubSlice{{Script: donationScript, Percent: donationFeePercent}}}},
 		RequireRemainderPositive: true,
+		DustThreshold:            dustThreshold,
 	}
 	payouts, _, err := computeCoinbasePayouts(plan)
-	if err != nil {
-		return "", "", err
-	}
-	return buildCoinbasePartsPayouts(height, extranonce1, extranonce2Size, templateExtraNonce2Size, payouts, witnessCommitment, coinbaseFlags, coinbaseMsg, scriptTime)
+	return payouts, err
 }
@@ -5,7 +5,7 @@ import (
 	"fmt"
 )
 
-func serializeDualCoinbaseTx(height int64, extranonce1, extranonce2 []byte, templateExtraNonce2Size int, poolScript []byte, workerScript []byte, totalValue int64, feePercent float64, witnessCommitment string, coinbaseFlags string, coinbaseMsg string, scriptTime int64) ([]byte, []byte, error) {
+func serializeDualCoinbaseTx(height int64, extranonce1, extranonce2 []byte, templateExtraNonce2Size int, poolScript []byte, workerScript []byte, totalValue int64, feePercent float64, witnessCommitment string, extraOpReturn []byte, coinbaseFlags string, coinbaseMsg string, scriptTime int64) ([]byte, []byte, error) {
 	var flagsBytes []byte
 	if coinbaseFlags != "" {
 		b, err := hex.DecodeString(coinbaseFlags)
@@ -22,10 +22,10 @@ func serializeDualCoinbaseTx(height int64, extranonce1, extranonce2 []byte, temp
 		}
 		commitmentScript = b
 	}
-	return serializeDualCoinbaseTxPredecoded(height, extranonce1, extranonce2, templateExtraNonce2Size, poolScript, workerScript, totalValue, feePercent, commitmentScript, flagsBytes, coinbaseMsg, scriptTime)
+	return serializeDualCoinbaseTxPredecoded(height, extranonce1, extranonce2, templateExtraNonce2Size, poolScript, workerScript, totalValue, feePercent, 0, commitmentScript, extraOpReturn, flagsBytes, coinbaseMsg, scriptTime)
 }
 
-func serializeTripleCoinbaseTx(height int64, extranonce1, extranonce2 []byte, templateExtraNonce2Size int, poolScript []byte, donationScript []byte, workerScript []byte, totalValue int64, poolFeePercent float64, donationFeePercent float64, witnessCommitment string, coinbaseFlags string, coinbaseMsg string, scriptTime int64) ([]byte, []byte, error) {
+func serializeTripleCoinbaseTx(height int64, extranonce1, extranonce2 []byte, templateExtraNonce2Size int, poolScript []byte, donationScript []byte, workerScript []byte, totalValue int64, poolFeePercent float64, donationFeePercent float64, witnessCommitment string, extraOpReturn []byte, coinbaseFlags string, coinbaseMsg string, scriptTime int64) ([]byte, []byte, error) {
 	var flagsBytes []byte
 	if coinbaseFlags != "" {
 		b, err := hex.DecodeString(coinbaseFlags)
@@ -42,5 +42,5 @@ func serializeTripleCoinbaseTx(height int64, extranonce1, extranonce2 []byte, te
 		}
 		commitmentScript = b
 	}
-	return serializeTripleCoinbaseTxPredecoded(height, extranonce1, extranonce2, templateExtraNonce2Size, poolScript, donationScript, workerScript, totalValue, poolFeePercent, donationFeePercent, commitmentScript, flagsBytes, coinbaseMsg, scriptTime)
+	return serializeTripleCoinbaseTxPredecoded(height, extranonce1, extranonce2, templateExtraNonce2Size, poolScript, donationScript, workerScript, totalValue, poolFeePercent, donationFeePercent, 0, commitmentScript, extraOpReturn, flagsBytes, coinbaseMsg, scriptTime)
 }
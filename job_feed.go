@@ -135,7 +135,32 @@ func (jm *JobManager) longpollLoop(ctx context.Context) {
 			"rules":      []string{"segwit"},
 			"longpollid": job.Template.LongPollID,
 		}
-		tpl, err := jm.fetchTemplateCtx(ctx, params, true)
+		lpCtx, cancel := jm.beginLongpoll(ctx)
+		tpl, err := jm.fetchTemplateCtx(lpCtx, params, true)
+		timedOut := errors.Is(lpCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil
+		superseded := jm.endLongpoll(cancel)
+		if err != nil && superseded && ctx.Err() == nil {
+			// ZMQ already refreshed the job; re-issue with the new longpollid.
+			continue
+		}
+		if err != nil && timedOut {
+			timeout := jm.longpollTimeout()
+			logger.Warn("longpoll stalled; re-issuing getblocktemplate",
+				"component", "rpc", "kind", "longpoll",
+				"timeout", timeout.String(),
+				"longpollid", job.Template.LongPollID,
+			)
+			if jm.metrics != nil {
+				jm.metrics.RecordErrorEvent("longpoll", "longpoll stalled for "+timeout.String()+"; re-issued getblocktemplate", time.Now())
+			}
+			if err := jm.refreshJobCtx(ctx); err != nil {
+				logger.Error("job refresh after longpoll stall", "component", "rpc", "kind", "template_refresh", "error", err)
+				if err := jm.sleepRetry(ctx); err != nil {
+					return
+				}
+			}
+			continue
+		}
 		if err != nil {
 			jm.recordJobError(err)
 			if errors.Is(err, context.Canceled) {
@@ -174,6 +199,7 @@ func (jm *JobManager) handleZMQNotification(ctx context.Context, topic string, p
 	case "hashblock":
 		blockHash := hex.EncodeToString(payload)
 		logger.Info("zmq block notification", "component", "zmq", "kind", "notify", "block_hash", blockHash)
+		defer jm.cancelLongpoll()
 		return jm.refreshJobCtxForce(ctx)
 	case "rawblock":
 		tip, err := parseRawBlockTip(payload)
@@ -187,6 +213,7 @@ func (jm *JobManager) handleZMQNotification(ctx context.Context, topic string, p
 		jm.recordRawBlockPayload(len(payload))
 		// Some deployments only publish rawblock and not hashblock; refresh the
 		// template on rawblock as well so job/tip advance on new blocks.
+		defer jm.cancelLongpoll()
 		return jm.refreshJobCtxForce(ctx)
 	default:
 		return nil
@@ -0,0 +1,25 @@
+package main
+
+const (
+	degradedFeedShareLenient = "lenient"
+	degradedFeedShareStrict  = "strict"
+)
+
+// feedDegraded reports whether the job feed is serving a job while its most
+// recent template refresh failed (node pruning, loadblock, RPC errors). It
+// is cheap enough for the submit path, unlike FeedStatus.
+func (jm *JobManager) feedDegraded() bool {
+	if jm == nil {
+		return false
+	}
+	jm.lastErrMu.RLock()
+	failing := jm.lastErr != nil
+	jm.lastErrMu.RUnlock()
+	return failing && jm.CurrentJob() != nil
+}
+
+// rejectSharesOnDegradedFeed reports whether a non-block share should be
+// refused as stale under [mining] degraded_feed_share_policy = "strict".
+func (mc *MinerConn) rejectSharesOnDegradedFeed() bool {
+	return mc.cfg.DegradedFeedSharePolicy == degradedFeedShareStrict && mc.jobMgr.feedDegraded()
+}
@@ -38,11 +38,20 @@ var maxUint256 = func() *big.Int {
 	return n.Sub(n, big.NewInt(1))
 }()
 
+// minShareDifficulty is the lowest difficulty with a target that still fits
+// in 256 bits (diff1Target / 2^256). Anything easier maps to maxUint256, so
+// every hash is a share; regtest/testnet setups with tiny difficulties are
+// floored here rather than handed a target that wraps.
+const minShareDifficulty = float64(0xffff) / (1 << 48)
+
 func targetFromDifficulty(diff float64) *big.Int {
-	if diff <= 0 {
+	if diff <= 0 || math.IsNaN(diff) {
 		// Lowest difficulty means the largest possible target.
 		return new(big.Int).Set(maxUint256)
 	}
+	if math.IsInf(diff, 1) {
+		return big.NewInt(1)
+	}
 	diffStr := strconv.FormatFloat(diff, 'g', -1, 64)
 	r, ok := new(big.Rat).SetString(diffStr)
 	if !ok || r.Sign() <= 0 {
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// checkLowerHeightTemplate decides whether tpl, which is below the current
+// job's height, may replace it (policy [mining] template_allow_confirmed_reorg).
+// A lower height is only genuine when the node has abandoned the block the
+// current job builds on: getblockheader reports it with negative
+// confirmations (not on the active chain). A node that still has that block
+// on its chain, or has never seen it, is lagging behind the work we already
+// serve (e.g. a backup node), and switching would put miners on a shorter
+// chain, so the template is refused.
+func (jm *JobManager) checkLowerHeightTemplate(ctx context.Context, cur *Job, tpl GetBlockTemplateResult) error {
+	parent := cur.Template.Previous
+	fields := []any{"component", "rpc", "kind", "template_height",
+		"current_height", cur.Template.Height, "template_height", tpl.Height,
+		"current_prev", parent, "template_prev", tpl.Previous}
+
+	var header struct {
+		Confirmations int64 `json:"confirmations"`
+	}
+	err := jm.rpc.callCtx(ctx, "getblockheader", []any{parent, true}, &header)
+	if err == nil && header.Confirmations < 0 {
+		logger.Warn("accepting lower-height template; node confirms reorg away from current job", fields...)
+		return nil
+	}
+
+	reason := fmt.Sprintf("current parent still has %d confirmations on the node", header.Confirmations)
+	if err != nil {
+		reason = "node cannot confirm current parent: " + err.Error()
+	}
+	logger.Warn("refusing lower-height template", append(fields, "reason", reason)...)
+	return fmt.Errorf("%w: template height regressed from %d to %d (%s)", errStaleTemplate, cur.Template.Height, tpl.Height, reason)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newHeightRegressionJobManager serves a node whose best block is "b99" and
+// reports confirmations for the given block hashes (unknown hashes fail).
+func newHeightRegressionJobManager(t *testing.T, cfg Config, confirmations map[string]int64) *JobManager {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode rpc request: %v", err)
+			return
+		}
+		resp := rpcResponse{ID: req.ID}
+		switch req.Method {
+		case "getbestblockhash":
+			resp.Result, _ = json.Marshal("b99")
+		case "getblockheader":
+			params, _ := req.Params.([]any)
+			hash, _ := params[0].(string)
+			conf, ok := confirmations[hash]
+			if !ok {
+				resp.Error = &rpcError{Code: -5, Message: "Block not found"}
+				break
+			}
+			resp.Result, _ = json.Marshal(map[string]any{"hash": hash, "confirmations": conf})
+		default:
+			resp.Error = &rpcError{Code: -32601, Message: "method not found"}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+
+	client := &RPCClient{url: srv.URL, client: srv.Client(), lp: srv.Client()}
+	jm := NewJobManager(client, cfg, nil, nil, nil)
+	jm.curJob = &Job{Template: GetBlockTemplateResult{Height: 101, Previous: "b100", CurTime: 2000}}
+	return jm
+}
+
+func TestEnsureTemplateFreshLowerHeight(t *testing.T) {
+	lower := GetBlockTemplateResult{Height: 100, Previous: "b99", CurTime: 1990}
+	tests := []struct {
+		name          string
+		allow         bool
+		confirmations map[string]int64
+		wantErr       bool
+	}{
+		{name: "disabled refuses", allow: false, confirmations: map[string]int64{"b100": -1}, wantErr: true},
+		{name: "confirmed reorg accepted", allow: true, confirmations: map[string]int64{"b100": -1}, wantErr: false},
+		{name: "lagging node refused", allow: true, confirmations: map[string]int64{"b100": 1}, wantErr: true},
+		{name: "unknown parent refused", allow: true, confirmations: map[string]int64{}, wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			jm := newHeightRegressionJobManager(t, Config{TemplateAllowConfirmedReorg: tc.allow}, tc.confirmations)
+			err := jm.ensureTemplateFresh(context.Background(), lower)
+			if tc.wantErr {
+				if !errors.Is(err, errStaleTemplate) {
+					t.Fatalf("expected stale template error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected lower-height template to be accepted, got %v", err)
+			}
+		})
+	}
+}
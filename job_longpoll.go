@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// minLongpollTimeout keeps longpoll_timeout_seconds well above normal block
+// intervals; a longpoll legitimately blocks until the next block or mempool
+// change, which can take many minutes.
+const minLongpollTimeout = 5 * time.Minute
+
+// longpollTimeout returns the configured longpoll stall timeout
+// ([timeouts].longpoll_timeout_seconds; 0 waits indefinitely).
+func (jm *JobManager) longpollTimeout() time.Duration {
+	if jm == nil {
+		return 0
+	}
+	jm.applyMu.Lock()
+	defer jm.applyMu.Unlock()
+	return jm.cfg.LongpollTimeout
+}
+
+// beginLongpoll derives the context for one longpoll request. It expires
+// after the stall timeout (when configured) and can be canceled early by
+// cancelLongpoll when ZMQ reports a new block.
+func (jm *JobManager) beginLongpoll(ctx context.Context) (context.Context, context.CancelFunc) {
+	var (
+		lpCtx  context.Context
+		cancel context.CancelFunc
+	)
+	if timeout := jm.longpollTimeout(); timeout > 0 {
+		lpCtx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		lpCtx, cancel = context.WithCancel(ctx)
+	}
+	jm.longpollMu.Lock()
+	jm.longpollCancel = cancel
+	jm.longpollSuperseded = false
+	jm.longpollMu.Unlock()
+	return lpCtx, cancel
+}
+
+// endLongpoll releases the request context and reports whether it was
+// canceled by cancelLongpoll.
+func (jm *JobManager) endLongpoll(cancel context.CancelFunc) (superseded bool) {
+	cancel()
+	jm.longpollMu.Lock()
+	defer jm.longpollMu.Unlock()
+	jm.longpollCancel = nil
+	superseded = jm.longpollSuperseded
+	jm.longpollSuperseded = false
+	return superseded
+}
+
+// cancelLongpoll aborts the in-flight longpoll, if any, so the loop re-issues
+// it with the longpollid of the template ZMQ just refreshed.
+func (jm *JobManager) cancelLongpoll() {
+	jm.longpollMu.Lock()
+	defer jm.longpollMu.Unlock()
+	if jm.longpollCancel == nil {
+		return
+	}
+	jm.longpollSuperseded = true
+	jm.longpollCancel()
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLongpollCancelMarksSuperseded(t *testing.T) {
+	jm := &JobManager{}
+
+	lpCtx, cancel := jm.beginLongpoll(context.Background())
+	jm.cancelLongpoll()
+	if !errors.Is(lpCtx.Err(), context.Canceled) {
+		t.Fatalf("expected longpoll context canceled, got %v", lpCtx.Err())
+	}
+	if !jm.endLongpoll(cancel) {
+		t.Fatalf("expected longpoll to be reported as superseded")
+	}
+
+	// A longpoll that finishes on its own is not superseded, and a ZMQ
+	// notification with nothing in flight is a no-op.
+	_, cancel = jm.beginLongpoll(context.Background())
+	if jm.endLongpoll(cancel) {
+		t.Fatalf("expected uncanceled longpoll not to be superseded")
+	}
+	jm.cancelLongpoll()
+	_, cancel = jm.beginLongpoll(context.Background())
+	if jm.endLongpoll(cancel) {
+		t.Fatalf("stale cancel should not supersede the next longpoll")
+	}
+}
+
+func TestLongpollTimeoutExpires(t *testing.T) {
+	jm := &JobManager{cfg: Config{LongpollTimeout: 20 * time.Millisecond}}
+
+	lpCtx, cancel := jm.beginLongpoll(context.Background())
+	defer jm.endLongpoll(cancel)
+	select {
+	case <-lpCtx.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatalf("longpoll context did not expire")
+	}
+	if !errors.Is(lpCtx.Err(), context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", lpCtx.Err())
+	}
+
+	jm.cfg.LongpollTimeout = 0
+	lpCtx, cancel = jm.beginLongpoll(context.Background())
+	defer jm.endLongpoll(cancel)
+	if _, ok := lpCtx.Deadline(); ok {
+		t.Fatalf("expected no deadline when longpoll timeout is disabled")
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"runtime"
 	"strconv"
 	"strings"
@@ -112,9 +113,10 @@ func (jm *JobManager) refreshNodeSyncInfo(ctx context.Context) {
 	}
 
 	type bcInfo struct {
-		Blocks               int64 `json:"blocks"`
-		Headers              int64 `json:"headers"`
-		InitialBlockDownload bool  `json:"initialblockdownload"`
+		Chain                string `json:"chain"`
+		Blocks               int64  `json:"blocks"`
+		Headers              int64  `json:"headers"`
+		InitialBlockDownload bool   `json:"initialblockdownload"`
 	}
 	var bc bcInfo
 
@@ -139,6 +141,8 @@ func (jm *JobManager) refreshNodeSyncInfo(ctx context.Context) {
 	jm.nodeHeaders = bc.Headers
 	jm.nodeSyncFetched = time.Now()
 	jm.nodeSyncMu.Unlock()
+
+	jm.refreshNodeNetworkActive(ctx, bc.Chain)
 }
 
 func (jm *JobManager) FeedStatus() JobFeedStatus {
@@ -399,20 +403,82 @@ func (jm *JobManager) Start(ctx context.Context) {
 
 	go jm.longpollLoop(ctx)
 	go jm.heartbeatLoop(ctx)
+	go jm.payoutAddressCheckLoop(ctx)
 	jm.startZMQLoops(ctx)
 }
 
+// derivePayoutScripts derives the pool payout script and, when a donation is
+// configured (operator_donation_percent > 0), the donation script from cfg.
+// With a zero donation percent no donation address is needed and the
+// returned donation script is nil, so jobs use the dual/single coinbase.
+func derivePayoutScripts(cfg Config) (payoutScript, donationScript []byte, err error) {
+	payoutScript, err = fetchPayoutScript(nil, cfg.PayoutAddress)
+	if err != nil {
+		return nil, nil, fmt.Errorf("payout address: %w", err)
+	}
+	if cfg.OperatorDonationPercent <= 0 {
+		return payoutScript, nil, nil
+	}
+	addr := strings.TrimSpace(cfg.OperatorDonationAddress)
+	if addr == "" {
+		return nil, nil, errors.New("operator_donation_address is required when operator_donation_percent > 0")
+	}
+	donationScript, err = fetchPayoutScript(nil, addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("donation address %q: %w", addr, err)
+	}
+	if len(donationScript) == 0 {
+		return nil, nil, fmt.Errorf("donation address %q: empty script", addr)
+	}
+	return payoutScript, donationScript, nil
+}
+
 // ApplyRuntimeConfig updates future job-building settings and payout scripts in
-// memory so admin Apply can take effect without a process restart.
-func (jm *JobManager) ApplyRuntimeConfig(cfg Config, payoutScript, donationScript []byte) {
+// memory so admin Apply can take effect without a process restart. It refuses
+// (keeping the current config and scripts) when the scripts do not match cfg,
+// e.g. a donation percent with no donation script.
+func (jm *JobManager) ApplyRuntimeConfig(cfg Config, payoutScript, donationScript []byte) error {
 	if jm == nil {
-		return
+		return nil
+	}
+	if len(payoutScript) == 0 {
+		return errors.New("payout script is empty")
+	}
+	if cfg.OperatorDonationPercent > 0 && len(donationScript) == 0 {
+		return fmt.Errorf("operator_donation_percent is %v but the donation script is empty", cfg.OperatorDonationPercent)
+	}
+	if cfg.OperatorDonationPercent <= 0 {
+		donationScript = nil
+	}
+	splits, err := derivePayoutSplitScripts(cfg)
+	if err != nil {
+		return err
 	}
 	jm.applyMu.Lock()
 	jm.cfg = cfg
 	jm.payoutScript = append(jm.payoutScript[:0], payoutScript...)
 	jm.donationScript = append(jm.donationScript[:0], donationScript...)
+	jm.payoutSplits = splits
 	jm.applyMu.Unlock()
+	return nil
+}
+
+// ApplyTuningConfig overlays tuning.toml settings on the job manager's
+// config, leaving payout scripts and every other setting untouched. It
+// refuses (keeping the current config) when the result does not validate.
+func (jm *JobManager) ApplyTuningConfig(fc tuningFileConfig) error {
+	if jm == nil {
+		return nil
+	}
+	jm.applyMu.Lock()
+	defer jm.applyMu.Unlock()
+	cfg := jm.cfg
+	applyTuningConfig(&cfg, fc)
+	if err := validateConfig(cfg); err != nil {
+		return err
+	}
+	jm.cfg = cfg
+	return nil
 }
 
 func (jm *JobManager) heartbeatLoop(ctx context.Context) {
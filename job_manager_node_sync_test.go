@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -45,3 +46,47 @@ func TestRefreshNodeSyncInfo_RecordsErrorWhenNoCurrentJob(t *testing.T) {
 		t.Fatal("expected job-feed error when no current job exists")
 	}
 }
+
+func TestRefreshNodeSyncInfo_NetworkInactiveGatesStratum(t *testing.T) {
+	var networkActive atomic.Bool
+	chain := "main"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpcRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		var result any
+		switch req.Method {
+		case "getblockchaininfo":
+			result = map[string]any{"chain": chain, "blocks": 100, "headers": 100, "initialblockdownload": false}
+		case "getnetworkinfo":
+			result = map[string]any{"networkactive": networkActive.Load()}
+		}
+		raw, _ := json.Marshal(result)
+		_ = json.NewEncoder(w).Encode(rpcResponse{Result: raw, ID: req.ID})
+	}))
+	t.Cleanup(srv.Close)
+
+	rpc := &RPCClient{url: srv.URL, client: srv.Client(), lp: srv.Client()}
+	jm := &JobManager{rpc: rpc, cfg: Config{GateOnNetworkInactive: true}}
+	jm.mu.Lock()
+	jm.curJob = &Job{CreatedAt: time.Now()}
+	jm.mu.Unlock()
+
+	jm.refreshNodeSyncInfo(context.Background())
+	if h := stratumHealthStatus(jm, time.Now()); h.Healthy {
+		t.Fatalf("expected stratum to be gated while networkactive=false")
+	}
+
+	networkActive.Store(true)
+	jm.refreshNodeSyncInfo(context.Background())
+	if h := stratumHealthStatus(jm, time.Now()); !h.Healthy {
+		t.Fatalf("expected recovery once networkactive=true, got %+v", h)
+	}
+
+	// Regtest nodes routinely run without networking.
+	networkActive.Store(false)
+	chain = "regtest"
+	jm.refreshNodeSyncInfo(context.Background())
+	if h := stratumHealthStatus(jm, time.Now()); !h.Healthy {
+		t.Fatalf("regtest should not be gated, got %+v", h)
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import "testing"
+
+func TestDerivePayoutScriptsDonation(t *testing.T) {
+	const addr = "1BitcoinEaterAddressDontSendf59kuE"
+
+	// Zero donation percent needs no valid donation address.
+	payout, donation, err := derivePayoutScripts(Config{PayoutAddress: addr, OperatorDonationAddress: "not-an-address"})
+	if err != nil {
+		t.Fatalf("zero donation percent: %v", err)
+	}
+	if len(payout) == 0 || donation != nil {
+		t.Fatalf("expected payout script only, got payout=%x donation=%x", payout, donation)
+	}
+
+	if _, _, err := derivePayoutScripts(Config{PayoutAddress: addr, OperatorDonationPercent: 1, OperatorDonationAddress: "not-an-address"}); err == nil {
+		t.Fatalf("expected error for invalid donation address")
+	}
+	if _, donation, err = derivePayoutScripts(Config{PayoutAddress: addr, OperatorDonationPercent: 1, OperatorDonationAddress: addr}); err != nil || len(donation) == 0 {
+		t.Fatalf("valid donation address: script=%x err=%v", donation, err)
+	}
+}
+
+func TestApplyRuntimeConfigKeepsScriptsOnMismatch(t *testing.T) {
+	jm := &JobManager{payoutScript: []byte{0x51}, donationScript: []byte{0x52}}
+	jm.cfg.OperatorDonationPercent = 1
+
+	if err := jm.ApplyRuntimeConfig(Config{OperatorDonationPercent: 2}, []byte{0x53}, nil); err == nil {
+		t.Fatalf("expected refusal when donation percent is set without a script")
+	}
+	if jm.cfg.OperatorDonationPercent != 1 || string(jm.payoutScript) != "\x51" || string(jm.donationScript) != "\x52" {
+		t.Fatalf("refused apply changed state: cfg=%v payout=%x donation=%x", jm.cfg.OperatorDonationPercent, jm.payoutScript, jm.donationScript)
+	}
+
+	if err := jm.ApplyRuntimeConfig(Config{}, []byte{0x53}, []byte{0x52}); err != nil {
+		t.Fatalf("apply with zero donation percent: %v", err)
+	}
+	if len(jm.donationScript) != 0 {
+		t.Fatalf("expected donation script cleared at zero percent, got %x", jm.donationScript)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// refreshNodeNetworkActive polls getnetworkinfo for networkactive. A node
+// with networking disabled (setnetworkactive false) still answers
+// getblocktemplate, but its tip and mempool stop advancing, so the pool
+// alerts and, when gate_on_network_inactive is set, treats the feed as
+// degraded. Regtest nodes commonly run without peers and are exempt.
+func (jm *JobManager) refreshNodeNetworkActive(ctx context.Context, chain string) {
+	if jm == nil || jm.rpc == nil {
+		return
+	}
+	var info struct {
+		NetworkActive *bool `json:"networkactive"`
+	}
+	callCtx, cancel := context.WithTimeout(ctx, jobMgrNodeSyncTimeout)
+	defer cancel()
+	if err := jm.rpc.callCtx(callCtx, "getnetworkinfo", nil, &info); err != nil {
+		if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+			logger.Debug("getnetworkinfo failed", "component", "node", "kind", "network_active", "error", err)
+		}
+		return
+	}
+	active := info.NetworkActive == nil || *info.NetworkActive
+	if strings.EqualFold(strings.TrimSpace(chain), "regtest") {
+		active = true
+	}
+
+	gate := jm.gateOnNetworkInactive()
+
+	jm.nodeSyncMu.Lock()
+	wasActive := !jm.nodeNetworkInactive
+	jm.nodeNetworkInactive = !active
+	jm.nodeNetworkGate = gate
+	jm.nodeNetworkFetched = time.Now()
+	jm.nodeSyncMu.Unlock()
+
+	switch {
+	case wasActive && !active:
+		logger.Error("node reports networkactive=false; templates may be built on a stale tip",
+			"component", "node", "kind", "network_active",
+			"chain", chain,
+			"gating_stratum", gate,
+		)
+		if jm.metrics != nil {
+			jm.metrics.RecordErrorEvent("node", fmt.Sprintf("networkactive=false on %s node", chain), time.Now())
+		}
+	case !wasActive && active:
+		logger.Info("node networkactive restored", "component", "node", "kind", "network_active", "chain", chain)
+	}
+}
+
+// nodeNetworkSnapshot reports whether the node last said its networking is
+// disabled, whether that should gate Stratum, and when it was fetched.
+func (jm *JobManager) nodeNetworkSnapshot() (inactive, gate bool, fetchedAt time.Time) {
+	if jm == nil {
+		return false, false, time.Time{}
+	}
+	jm.nodeSyncMu.RLock()
+	defer jm.nodeSyncMu.RUnlock()
+	return jm.nodeNetworkInactive, jm.nodeNetworkGate, jm.nodeNetworkFetched
+}
+
+// gateOnNetworkInactive reports whether networkactive=false should gate
+// Stratum like IBD does (policy [stratum].gate_on_network_inactive).
+func (jm *JobManager) gateOnNetworkInactive() bool {
+	if jm == nil {
+		return false
+	}
+	jm.applyMu.Lock()
+	defer jm.applyMu.Unlock()
+	return jm.cfg.GateOnNetworkInactive
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
@@ -34,6 +35,22 @@ func (jm *JobManager) refreshJobCtxMinInterval(ctx context.Context, minInterval
 }
 
 func (jm *JobManager) fetchTemplateCtx(ctx context.Context, params map[string]any, useLongPoll bool) (GetBlockTemplateResult, error) {
+	// A truncated reply is usually a one-off from a busy node, so retry it a
+	// couple of times here; schema mismatches are returned immediately. Either
+	// way the caller keeps serving the previous job on error.
+	for attempt := 0; ; attempt++ {
+		tpl, err := jm.fetchTemplateOnce(ctx, params, useLongPoll)
+		var de *templateDecodeError
+		if err == nil || !errors.As(err, &de) || de.schema || attempt >= templateDecodeRetries {
+			return tpl, err
+		}
+		if err := sleepContext(ctx, templateDecodeRetryDelay); err != nil {
+			return GetBlockTemplateResult{}, err
+		}
+	}
+}
+
+func (jm *JobManager) fetchTemplateOnce(ctx context.Context, params map[string]any, useLongPoll bool) (GetBlockTemplateResult, error) {
 	var tpl GetBlockTemplateResult
 	var err error
 	if useLongPoll {
@@ -41,7 +58,21 @@ func (jm *JobManager) fetchTemplateCtx(ctx context.Context, params map[string]an
 	} else {
 		err = jm.rpc.callCtx(ctx, "getblocktemplate", []any{params}, &tpl)
 	}
-	return tpl, err
+	if err == nil {
+		err = validateBlockTemplateFields(tpl)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, errRPCDecode):
+			err = &templateDecodeError{err: err}
+		case errors.Is(err, errRPCResultDecode):
+			err = &templateDecodeError{schema: true, err: err}
+		}
+		jm.noteTemplateDecodeError(err)
+		return GetBlockTemplateResult{}, err
+	}
+	jm.noteTemplateDecodeOK()
+	return tpl, nil
 }
 
 func (jm *JobManager) refreshFromTemplate(ctx context.Context, tpl GetBlockTemplateResult) error {
@@ -49,6 +80,12 @@ func (jm *JobManager) refreshFromTemplate(ctx context.Context, tpl GetBlockTempl
 	defer jm.applyMu.Unlock()
 
 	needsNewJob, clean := jm.templateChanged(tpl)
+	if !needsNewJob && jm.scriptTimeDrifted(time.Now()) {
+		// Same work, but the job's scriptTime has fallen too far behind the
+		// clock: issue it again as a new job (new job ID, fresh scriptTime,
+		// clean_jobs=false). Notifies already sent keep their scriptTime.
+		needsNewJob = true
+	}
 
 	// If the template hasn't meaningfully changed, skip building and broadcasting a new job.
 	// This avoids unnecessary job churn and duplicate JobIDs for the same work.
@@ -65,6 +102,8 @@ func (jm *JobManager) refreshFromTemplate(ctx context.Context, tpl GetBlockTempl
 		return err
 	}
 	job.Clean = clean
+	jm.checkRequiredTemplateTxids(tpl)
+	jm.checkJobMerkleRoot(job)
 
 	jm.mu.Lock()
 	jm.curJob = job
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRefreshFromTemplate_ReissuesJobWhenScriptTimeDrifts(t *testing.T) {
+	bestHash := "0000000000000000000000000000000000000000000000000000000000000001"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpcRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		resp := rpcResponse{ID: req.ID}
+		if req.Method == "getbestblockhash" {
+			resp.Result, _ = json.Marshal(bestHash)
+		} else {
+			resp.Error = &rpcError{Code: -32601, Message: "method not found"}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+
+	rpc := &RPCClient{url: srv.URL, client: srv.Client(), lp: srv.Client()}
+	cfg := Config{Extranonce2Size: 4, TemplateExtraNonce2Size: 8, ScriptTimeMaxDriftSeconds: 600}
+	jm := NewJobManager(rpc, cfg, nil, []byte{0x51}, nil)
+
+	tpl := GetBlockTemplateResult{
+		Height:                   103,
+		CurTime:                  time.Now().Unix(),
+		Bits:                     "1d00ffff",
+		Previous:                 bestHash,
+		DefaultWitnessCommitment: "00",
+		CoinbaseValue:            50 * 1e8,
+	}
+	if err := jm.refreshFromTemplate(context.Background(), tpl); err != nil {
+		t.Fatalf("refreshFromTemplate error: %v", err)
+	}
+	first := jm.CurrentJob()
+
+	// An unchanged template with a fresh scriptTime keeps the job.
+	if err := jm.refreshFromTemplate(context.Background(), tpl); err != nil {
+		t.Fatalf("refreshFromTemplate error: %v", err)
+	}
+	if jm.CurrentJob() != first {
+		t.Fatalf("unchanged template should keep the current job")
+	}
+
+	// Age the job past the drift limit; the same template now yields a new
+	// job with its own ID and a current scriptTime, without clean_jobs.
+	oldScriptTime := time.Now().Add(-11 * time.Minute).Unix()
+	first.ScriptTime = oldScriptTime
+	if err := jm.refreshFromTemplate(context.Background(), tpl); err != nil {
+		t.Fatalf("refreshFromTemplate error: %v", err)
+	}
+	second := jm.CurrentJob()
+	if second == first || second.JobID == first.JobID {
+		t.Fatalf("expected a reissued job with a new ID")
+	}
+	if second.Clean {
+		t.Fatalf("reissued job should not set clean_jobs")
+	}
+	if drift := time.Now().Unix() - second.ScriptTime; drift < 0 || drift > 5 {
+		t.Fatalf("reissued job scriptTime is %ds from now", drift)
+	}
+	if first.ScriptTime != oldScriptTime {
+		t.Fatalf("previous job's scriptTime must not change")
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMissingRequiredTxids(t *testing.T) {
+	present := strings.Repeat("ab", 32)
+	absent := strings.Repeat("cd", 32)
+	txs := []GBTTransaction{{Txid: strings.ToUpper(present)}}
+
+	required := normalizeRequiredTemplateTxids([]string{" " + strings.ToUpper(present) + " ", absent, absent, ""})
+	if len(required) != 2 {
+		t.Fatalf("expected 2 normalized txids, got %v", required)
+	}
+
+	missing := missingRequiredTxids(txs, required)
+	if len(missing) != 1 || missing[0] != absent {
+		t.Fatalf("unexpected missing txids: %v", missing)
+	}
+	if got := missingRequiredTxids(txs, nil); got != nil {
+		t.Fatalf("expected nil when nothing is required, got %v", got)
+	}
+}
+
+func TestCheckRequiredTemplateTxids_AlertsOncePerTemplate(t *testing.T) {
+	absent := strings.Repeat("cd", 32)
+	metrics := NewPoolMetrics()
+	jm := &JobManager{
+		cfg:     Config{RequiredTemplateTxids: []string{absent}},
+		metrics: metrics,
+	}
+	tpl := GetBlockTemplateResult{Height: 100}
+
+	jm.checkRequiredTemplateTxids(tpl)
+	jm.checkRequiredTemplateTxids(tpl)
+	if got := len(metrics.SnapshotErrorHistory()); got != 1 {
+		t.Fatalf("expected a single alert for an unchanged template, got %d", got)
+	}
+
+	tpl.Transactions = []GBTTransaction{{Txid: absent}}
+	jm.checkRequiredTemplateTxids(tpl)
+	if jm.requiredTxidsAlert != "" {
+		t.Fatalf("expected alert state to reset once the txid is present")
+	}
+}
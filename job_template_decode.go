@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// templateDecodeRetries is how many times a truncated getblocktemplate
+	// reply is re-fetched before the error is returned to the caller.
+	templateDecodeRetries    = 2
+	templateDecodeRetryDelay = 250 * time.Millisecond
+	// templateDecodeAlertAfter is how many consecutive truncated replies are
+	// treated as transient before alerting.
+	templateDecodeAlertAfter = 3
+)
+
+// templateDecodeError describes a getblocktemplate result that could not be
+// turned into a complete template. schema is set when the JSON was well formed
+// but did not match the expected shape (wrong types or missing required
+// fields); such errors will not fix themselves on retry.
+type templateDecodeError struct {
+	schema bool
+	err    error
+}
+
+func (e *templateDecodeError) Error() string {
+	if e.schema {
+		return "block template schema mismatch: " + e.err.Error()
+	}
+	return "block template decode failed: " + e.err.Error()
+}
+
+func (e *templateDecodeError) Unwrap() error { return e.err }
+
+func (e *templateDecodeError) kind() string {
+	if e.schema {
+		return "schema"
+	}
+	return "truncated"
+}
+
+// validateBlockTemplateFields rejects a decoded template that is missing
+// fields every getblocktemplate result carries, so a partially decoded
+// template never reaches buildJob. transactions may be absent (empty).
+func validateBlockTemplateFields(tpl GetBlockTemplateResult) error {
+	var missing []string
+	if tpl.Bits == "" {
+		missing = append(missing, "bits")
+	}
+	if tpl.CurTime <= 0 {
+		missing = append(missing, "curtime")
+	}
+	if tpl.Height <= 0 {
+		missing = append(missing, "height")
+	}
+	if len(tpl.Previous) != 64 {
+		missing = append(missing, "previousblockhash")
+	}
+	if tpl.CoinbaseValue <= 0 {
+		missing = append(missing, "coinbasevalue")
+	}
+	if len(missing) > 0 {
+		return &templateDecodeError{schema: true, err: fmt.Errorf("missing or invalid fields: %v", missing)}
+	}
+	return nil
+}
+
+// noteTemplateDecodeError counts a failed template decode, records it in the
+// pool metrics and alerts: schema mismatches immediately, truncated replies
+// only once they persist for templateDecodeAlertAfter attempts.
+func (jm *JobManager) noteTemplateDecodeError(err error) {
+	var de *templateDecodeError
+	if !errors.As(err, &de) {
+		return
+	}
+	if jm.metrics != nil {
+		jm.metrics.RecordTemplateDecodeError(de.kind(), err.Error(), time.Now())
+	}
+
+	jm.templateDecodeMu.Lock()
+	jm.templateDecodeFailures++
+	failures := jm.templateDecodeFailures
+	alert := !jm.templateDecodeAlerted && (de.schema || failures >= templateDecodeAlertAfter)
+	if alert {
+		jm.templateDecodeAlerted = true
+	}
+	jm.templateDecodeMu.Unlock()
+
+	if alert {
+		logger.Error("block template unusable; holding previous job",
+			"component", "rpc", "kind", "template_decode",
+			"reason", de.kind(),
+			"consecutive", failures,
+			"error", err,
+		)
+		return
+	}
+	logger.Warn("block template decode failed; will retry",
+		"component", "rpc", "kind", "template_decode",
+		"reason", de.kind(),
+		"consecutive", failures,
+		"error", err,
+	)
+}
+
+func (jm *JobManager) noteTemplateDecodeOK() {
+	jm.templateDecodeMu.Lock()
+	failures := jm.templateDecodeFailures
+	jm.templateDecodeFailures = 0
+	jm.templateDecodeAlerted = false
+	jm.templateDecodeMu.Unlock()
+	if failures > 0 {
+		logger.Info("block template decoding recovered",
+			"component", "rpc", "kind", "template_decode",
+			"failed_attempts", failures,
+		)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testGBTResult = `{"bits":"1d00ffff","curtime":1700000000,"height":101,"version":536870912,` +
+	`"previousblockhash":"0000000000000000000000000000000000000000000000000000000000000001",` +
+	`"coinbasevalue":5000000000,"transactions":[]}`
+
+func newTemplateDecodeTestJobManager(t *testing.T, body string) (*JobManager, *PoolMetrics) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	metrics := NewPoolMetrics()
+	rpc := &RPCClient{url: srv.URL, client: srv.Client(), lp: srv.Client()}
+	jm := &JobManager{rpc: rpc, metrics: metrics}
+	return jm, metrics
+}
+
+func TestFetchTemplateDecodesCompleteResult(t *testing.T) {
+	jm, metrics := newTemplateDecodeTestJobManager(t, `{"result":`+testGBTResult+`,"error":null,"id":1}`)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tpl, err := jm.fetchTemplateCtx(ctx, nil, false)
+	if err != nil {
+		t.Fatalf("fetchTemplateCtx: %v", err)
+	}
+	if tpl.Height != 101 || tpl.CoinbaseValue != 5000000000 {
+		t.Fatalf("decoded template mismatch: %+v", tpl)
+	}
+	if got := metrics.TemplateDecodeErrors(); got != 0 {
+		t.Fatalf("TemplateDecodeErrors=%d want 0", got)
+	}
+}
+
+func TestFetchTemplateSchemaMismatchIsNotRetried(t *testing.T) {
+	tests := []struct {
+		name   string
+		result string
+	}{
+		{name: "missing coinbasevalue", result: strings.Replace(testGBTResult, `"coinbasevalue":5000000000,`, "", 1)},
+		{name: "wrong type", result: strings.Replace(testGBTResult, `"curtime":1700000000`, `"curtime":"soon"`, 1)},
+		{name: "null result", result: "null"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			jm, metrics := newTemplateDecodeTestJobManager(t, `{"result":`+tc.result+`,"error":null,"id":1}`)
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			tpl, err := jm.fetchTemplateCtx(ctx, nil, false)
+			var de *templateDecodeError
+			if !errors.As(err, &de) || !de.schema {
+				t.Fatalf("expected schema error, got %v", err)
+			}
+			if tpl.Height != 0 {
+				t.Fatalf("partially decoded template leaked: %+v", tpl)
+			}
+			if got := metrics.TemplateDecodeErrors(); got != 1 {
+				t.Fatalf("TemplateDecodeErrors=%d want 1 (schema errors are not retried)", got)
+			}
+			if !jm.templateDecodeAlerted {
+				t.Fatalf("expected schema mismatch to alert immediately")
+			}
+		})
+	}
+}
+
+func TestFetchTemplateTruncatedResponseKeepsJobAndCounts(t *testing.T) {
+	// Simulate a node that drops the connection mid-response.
+	jm, metrics := newTemplateDecodeTestJobManager(t, `{"result":{"bits":"1d00ffff","curtime":17000`)
+	prev := &Job{JobID: "prev", CreatedAt: time.Now()}
+	jm.curJob = prev
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := jm.refreshJobCtxForce(ctx)
+	var de *templateDecodeError
+	if !errors.As(err, &de) || de.schema {
+		t.Fatalf("expected transient decode error, got %v", err)
+	}
+	if jm.CurrentJob() != prev {
+		t.Fatalf("previous job was replaced after decode failures")
+	}
+	if got, want := metrics.TemplateDecodeErrors(), uint64(1+templateDecodeRetries); got != want {
+		t.Fatalf("TemplateDecodeErrors=%d want %d", got, want)
+	}
+	if !jm.templateDecodeAlerted {
+		t.Fatalf("expected persistent decode failures to alert")
+	}
+	if jm.rpc.Disconnects() != 0 {
+		t.Fatalf("a malformed reply must not count as a node disconnect")
+	}
+
+	jm.noteTemplateDecodeOK()
+	if jm.templateDecodeFailures != 0 || jm.templateDecodeAlerted {
+		t.Fatalf("decode failure streak not reset after success")
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"math/big"
 	"sync"
@@ -44,6 +45,7 @@ type Job struct {
 	Template                GetBlockTemplateResult
 	Target                  *big.Int
 	targetBE                [32]byte
+	networkDiff             float64
 	CreatedAt               time.Time
 	Clean                   bool
 	Extranonce2Size         int
@@ -57,14 +59,22 @@ type Job struct {
 	PayoutScript            []byte
 	DonationScript          []byte
 	OperatorDonationPercent float64
+	CoinbaseDustThreshold   int64
 	VersionMask             uint32
 	PrevHash                string
 	prevHashBytes           [32]byte
 	bitsBytes               [4]byte
 	coinbaseFlagsBytes      []byte
 	witnessCommitScript     []byte
+	extraOpReturnScript     []byte
 	ScriptTime              int64
 	TemplateExtraNonce2Size int
+	// PayoutSplits divides the pool fee output between operator wallets
+	// (nil unless [[mining.payout_splits]] is configured).
+	PayoutSplits []coinbasePayoutSplit
+	// coinbaseTails caches coinb2 per payout set when
+	// coinbase_parts_cache is enabled (nil otherwise).
+	coinbaseTails *coinbaseTailCache
 }
 
 const (
@@ -105,6 +115,7 @@ type JobManager struct {
 	curJob              *Job
 	payoutScript        []byte
 	donationScript      []byte
+	payoutSplits        []coinbasePayoutSplit
 	extraID             uint32
 	jobIDCounter        uint64
 	subs                map[chan *Job]struct{}
@@ -118,9 +129,23 @@ type JobManager struct {
 	lastErrAt           time.Time
 	lastJobSuccess      time.Time
 	jobFeedErrHistory   []string
+	// requiredTxidsAlert remembers the last missing-required-txids alert so a
+	// long-lived template does not re-alert on every refresh (guarded by applyMu).
+	requiredTxidsAlert string
+	// templateDecodeFailures counts consecutive getblocktemplate results that
+	// could not be fully decoded; templateDecodeAlerted is set once the streak
+	// has been alerted (both guarded by templateDecodeMu).
+	templateDecodeMu       sync.Mutex
+	templateDecodeFailures int
+	templateDecodeAlerted  bool
 	// Refresh/apply coordination to prevent concurrent refreshes and concurrent
 	// template application from longpoll/ZMQ.
-	refreshMu          sync.Mutex
+	refreshMu sync.Mutex
+	// longpollCancel aborts the in-flight longpoll when ZMQ reports a new
+	// block; longpollSuperseded records that it did (see job_longpoll.go).
+	longpollMu         sync.Mutex
+	longpollCancel     context.CancelFunc
+	longpollSuperseded bool
 	lastRefreshAttempt time.Time
 	applyMu            sync.Mutex
 	zmqPayload         JobFeedPayloadStatus
@@ -133,6 +158,11 @@ type JobManager struct {
 	nodeBlocks      int64
 	nodeHeaders     int64
 	nodeSyncFetched time.Time
+	// nodeNetwork* mirrors getnetworkinfo.networkactive (see
+	// job_network_active.go); nodeNetworkGate is the policy at fetch time.
+	nodeNetworkInactive bool
+	nodeNetworkGate     bool
+	nodeNetworkFetched  time.Time
 	// Async notification queue
 	notifyQueue chan *Job
 	notifyWg    sizedwaitgroup.SizedWaitGroup
@@ -141,6 +171,12 @@ type JobManager struct {
 	// Retry backoff state for job refresh loops
 	retryDelay time.Duration
 	retryMu    sync.Mutex
+	// blockFoundSinceStart ends the coinbase upgrade marker (see
+	// coinbase_upgrade_marker.go) once a block has been accepted.
+	blockFoundSinceStart atomic.Bool
+	// blockClaims keeps one submitted block per height when
+	// dedupe_block_submissions is on (see block_height_claims.go).
+	blockClaims blockHeightClaims
 }
 
 func NewJobManager(rpc *RPCClient, cfg Config, metrics *PoolMetrics, payoutScript []byte, donationScript []byte) *JobManager {
@@ -6,6 +6,8 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	"strings"
+	"time"
 )
 
 func (jm *JobManager) ensureTemplateFresh(ctx context.Context, tpl GetBlockTemplateResult) error {
@@ -26,7 +28,10 @@ func (jm *JobManager) ensureTemplateFresh(ctx context.Context, tpl GetBlockTempl
 	cur := jm.curJob
 	jm.mu.RUnlock()
 	if cur != nil && tpl.Height < cur.Template.Height {
-		return fmt.Errorf("%w: template height regressed from %d to %d", errStaleTemplate, cur.Template.Height, tpl.Height)
+		if !jm.cfg.TemplateAllowConfirmedReorg {
+			return fmt.Errorf("%w: template height regressed from %d to %d", errStaleTemplate, cur.Template.Height, tpl.Height)
+		}
+		return jm.checkLowerHeightTemplate(ctx, cur, tpl)
 	}
 	if cur != nil && tpl.CurTime < cur.Template.CurTime {
 		return fmt.Errorf("%w: template curtime regressed from %d to %d", errStaleTemplate, cur.Template.CurTime, tpl.CurTime)
@@ -167,3 +172,173 @@ func (jm *JobManager) templateChanged(tpl GetBlockTemplateResult) (needsNewJob,
 	// No meaningful changes.
 	return false, false
 }
+
+// scriptTimeDrifted reports whether the current job's scriptTime is at least
+// script_time_max_drift_seconds behind now.
+func (jm *JobManager) scriptTimeDrifted(now time.Time) bool {
+	maxDrift := jm.cfg.ScriptTimeMaxDriftSeconds
+	if maxDrift <= 0 {
+		return false
+	}
+	jm.mu.RLock()
+	cur := jm.curJob
+	jm.mu.RUnlock()
+	if cur == nil || cur.ScriptTime == 0 {
+		return false
+	}
+	drift := now.Unix() - cur.ScriptTime
+	if drift < int64(maxDrift) {
+		return false
+	}
+	logger.Info("script time drifted; reissuing job", "component", "job", "kind", "script_time",
+		"job_id", cur.JobID, "height", cur.Template.Height, "drift", time.Duration(drift)*time.Second)
+	return true
+}
+
+// normalizeRequiredTemplateTxids trims, lowercases, and de-duplicates the
+// configured required txids while preserving their order.
+func normalizeRequiredTemplateTxids(txids []string) []string {
+	out := make([]string, 0, len(txids))
+	seen := make(map[string]struct{}, len(txids))
+	for _, txid := range txids {
+		txid = strings.ToLower(strings.TrimSpace(txid))
+		if txid == "" {
+			continue
+		}
+		if _, ok := seen[txid]; ok {
+			continue
+		}
+		seen[txid] = struct{}{}
+		out = append(out, txid)
+	}
+	return out
+}
+
+func isHexTxid(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// missingRequiredTxids returns the required txids that are not present in the
+// template transaction list.
+func missingRequiredTxids(txs []GBTTransaction, required []string) []string {
+	if len(required) == 0 {
+		return nil
+	}
+	present := make(map[string]struct{}, len(txs))
+	for _, tx := range txs {
+		present[strings.ToLower(tx.Txid)] = struct{}{}
+	}
+	var missing []string
+	for _, txid := range required {
+		if _, ok := present[txid]; !ok {
+			missing = append(missing, txid)
+		}
+	}
+	return missing
+}
+
+// checkRequiredTemplateTxids alerts when a pinned txid is absent from the
+// node's template. We cannot safely add arbitrary transactions ourselves, so
+// the template is still used as-is; this only surfaces the problem.
+// Callers must hold applyMu.
+func (jm *JobManager) checkRequiredTemplateTxids(tpl GetBlockTemplateResult) {
+	missing := missingRequiredTxids(tpl.Transactions, jm.cfg.RequiredTemplateTxids)
+	if len(missing) == 0 {
+		if jm.requiredTxidsAlert != "" {
+			logger.Info("required template txids present again", "component", "job", "kind", "required_txids", "height", tpl.Height)
+		}
+		jm.requiredTxidsAlert = ""
+		return
+	}
+	key := fmt.Sprintf("%d:%s", tpl.Height, strings.Join(missing, ","))
+	if key == jm.requiredTxidsAlert {
+		return
+	}
+	jm.requiredTxidsAlert = key
+	logger.Warn("block template missing required txids; using node template as-is",
+		"component", "job", "kind", "required_txids",
+		"height", tpl.Height,
+		"missing", missing,
+		"required", len(jm.cfg.RequiredTemplateTxids),
+	)
+	jm.metrics.RecordErrorEvent("template", fmt.Sprintf("height %d missing %d required txid(s)", tpl.Height, len(missing)), time.Now())
+}
+
+// merkleRootFromTxids builds the block merkle root directly from the coinbase
+// txid and the template txids, independently of the Stratum merkle branches.
+func merkleRootFromTxids(coinbaseTxid []byte, txids [][]byte) []byte {
+	layer := make([][]byte, 0, 1+len(txids))
+	layer = append(layer, coinbaseTxid)
+	layer = append(layer, txids...)
+	for len(layer) > 1 {
+		if len(layer)%2 == 1 {
+			layer = append(layer, layer[len(layer)-1])
+		}
+		next := make([][]byte, 0, len(layer)/2)
+		for i := 0; i+1 < len(layer); i += 2 {
+			joined := append(append([]byte{}, layer[i]...), layer[i+1]...)
+			next = append(next, doubleSHA256(joined))
+		}
+		layer = next
+	}
+	return layer[0]
+}
+
+// verifyJobMerkleRoot checks that a miner following mining.notify
+// (coinb1 + extranonce1 + extranonce2 + coinb2, then the merkle branches)
+// arrives at the same merkle root as the block we would submit for the
+// template. A mismatch means every share builds an invalid block.
+func verifyJobMerkleRoot(job *Job) error {
+	if job == nil {
+		return fmt.Errorf("nil job")
+	}
+	extranonce1 := make([]byte, 4)
+	extranonce2 := make([]byte, job.Extranonce2Size)
+	coinb1, coinb2, err := buildCoinbaseParts(job.Template.Height, extranonce1, job.Extranonce2Size, job.TemplateExtraNonce2Size, job.PayoutScript, job.CoinbaseValue, job.WitnessCommitment, job.extraOpReturnScript, job.Template.CoinbaseAux.Flags, job.CoinbaseMsg, job.ScriptTime)
+	if err != nil {
+		return fmt.Errorf("build coinbase parts: %w", err)
+	}
+	_, blockCoinbaseTxid, err := serializeCoinbaseTx(job.Template.Height, extranonce1, extranonce2, job.TemplateExtraNonce2Size, job.PayoutScript, job.CoinbaseValue, job.WitnessCommitment, job.extraOpReturnScript, job.Template.CoinbaseAux.Flags, job.CoinbaseMsg, job.ScriptTime)
+	if err != nil {
+		return fmt.Errorf("serialize coinbase: %w", err)
+	}
+	c1, err := hex.DecodeString(coinb1)
+	if err != nil {
+		return fmt.Errorf("decode coinb1: %w", err)
+	}
+	c2, err := hex.DecodeString(coinb2)
+	if err != nil {
+		return fmt.Errorf("decode coinb2: %w", err)
+	}
+	stratumCoinbase := make([]byte, 0, len(c1)+len(extranonce1)+len(extranonce2)+len(c2))
+	stratumCoinbase = append(stratumCoinbase, c1...)
+	stratumCoinbase = append(stratumCoinbase, extranonce1...)
+	stratumCoinbase = append(stratumCoinbase, extranonce2...)
+	stratumCoinbase = append(stratumCoinbase, c2...)
+
+	got := computeMerkleRootFromBranches(doubleSHA256(stratumCoinbase), job.MerkleBranches)
+	want := merkleRootFromTxids(blockCoinbaseTxid, job.TransactionIDs)
+	if !bytes.Equal(got, want) {
+		return fmt.Errorf("notify merkle root %x does not match template merkle root %x", got, want)
+	}
+	return nil
+}
+
+// checkJobMerkleRoot runs verifyJobMerkleRoot on a freshly built job and
+// raises an error-level alert on mismatch.
+func (jm *JobManager) checkJobMerkleRoot(job *Job) {
+	if err := verifyJobMerkleRoot(job); err != nil {
+		logger.Error("merkle self-check failed; shares on this job would build invalid blocks",
+			"component", "job", "kind", "merkle_check",
+			"height", job.Template.Height,
+			"job_id", job.JobID,
+			"txs", len(job.TransactionIDs),
+			"error", err,
+		)
+		jm.metrics.RecordErrorEvent("merkle_check", fmt.Sprintf("height %d job %s: %v", job.Template.Height, job.JobID, err), time.Now())
+	}
+}
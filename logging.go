@@ -26,6 +26,7 @@ const (
 	logLevelError
 )
 
+// logRetentionDays is the default [logging].retention_days.
 const logRetentionDays = 3
 
 var levelNames = []string{
@@ -184,24 +185,29 @@ func closeWriter(w io.Writer) {
 }
 
 func (l *simpleLogger) writeEntry(evt logEvent) {
-	username := formatAttrs(evt.attrs)
-	timestamp := time.Now().UTC().Format(time.RFC3339Nano)
+	now := time.Now().UTC()
 	levelName := "UNKNOWN"
 	if int(evt.level) >= 0 && int(evt.level) < len(levelNames) {
 		levelName = levelNames[evt.level]
 	}
-	var entry strings.Builder
-	entry.WriteString(timestamp)
-	entry.WriteString(" [")
-	entry.WriteString(levelName)
-	entry.WriteString("] ")
-	entry.WriteString(evt.msg)
-	if username != "" {
-		entry.WriteString(" ")
-		entry.WriteString(username)
+	var line string
+	if logJSON.Load() {
+		line = formatJSONLogLine(now, levelName, evt.msg, evt.attrs)
+	} else {
+		username := formatAttrs(evt.attrs)
+		var entry strings.Builder
+		entry.WriteString(now.Format(time.RFC3339Nano))
+		entry.WriteString(" [")
+		entry.WriteString(levelName)
+		entry.WriteString("] ")
+		entry.WriteString(evt.msg)
+		if username != "" {
+			entry.WriteString(" ")
+			entry.WriteString(username)
+		}
+		entry.WriteByte('\n')
+		line = entry.String()
 	}
-	entry.WriteByte('\n')
-	line := entry.String()
 
 	l.writerMu.RLock()
 	pool := l.poolWriter
@@ -270,6 +276,7 @@ type dailyRollingFileWriter struct {
 	mu          sync.Mutex
 	f           *os.File
 	currentDate string
+	maintaining atomic.Bool
 }
 
 func (w *dailyRollingFileWriter) ensureFile(now time.Time) error {
@@ -296,42 +303,21 @@ func (w *dailyRollingFileWriter) ensureFile(now time.Time) error {
 	return nil
 }
 
+// cleanupOldLogs applies retention and compression to rolled segments in
+// the background so rollover never blocks the log writer.
 func (w *dailyRollingFileWriter) cleanupOldLogs(now time.Time) {
-	if logRetentionDays <= 0 {
-		return
-	}
 	if w.name == "" || w.dir == "" {
 		return
 	}
-	cutoff := now.UTC().AddDate(0, 0, -(logRetentionDays - 1))
-	entries, err := os.ReadDir(w.dir)
-	if err != nil {
+	if !w.maintaining.CompareAndSwap(false, true) {
 		return
 	}
-	prefix := w.name + "-"
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		name := entry.Name()
-		if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, w.ext) {
-			continue
-		}
-		if len(name) < len(prefix)+len(w.ext)+len("2006-01-02") {
-			continue
-		}
-		dateStr := name[len(prefix) : len(name)-len(w.ext)]
-		if len(dateStr) != len("2006-01-02") {
-			continue
-		}
-		ts, err := time.Parse("2006-01-02", dateStr)
-		if err != nil {
-			continue
-		}
-		if ts.Before(cutoff) {
-			_ = os.Remove(filepath.Join(w.dir, name))
-		}
-	}
+	active := fmt.Sprintf("%s-%s%s", w.name, w.currentDate, w.ext)
+	policy := activeLogRotationPolicy()
+	go func() {
+		defer w.maintaining.Store(false)
+		maintainRolledLogs(w.dir, w.name, w.ext, active, policy, now)
+	}()
 }
 
 func (w *dailyRollingFileWriter) Write(p []byte) (int, error) {
@@ -371,7 +357,8 @@ func verboseRuntimeEnabled() bool {
 	return debugEnabled()
 }
 
-func configureFileLogging(poolPath, errorPath, debugPath string, stdout bool) {
+func configureFileLogging(poolPath, errorPath, debugPath string, stdout bool, format string) {
+	logJSON.Store(format == logFormatJSON)
 	logger.configureWriters(
 		newDailyRollingFileWriter(poolPath),
 		newDailyRollingFileWriter(errorPath),
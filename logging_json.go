@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+// logJSON switches pool.log, debug.log, net-debug.log and the -stdout mirror
+// to one JSON object per line ([logging].format = "json").
+var logJSON atomic.Bool
+
+// formatJSONLogLine renders one entry as a JSON object line: ts, level and
+// msg first, then each attribute pair as a top-level key. Attribute keys
+// that collide with those three get a leading underscore, and a dangling key
+// is kept under "!BADKEY" as log/slog does.
+func formatJSONLogLine(ts time.Time, level, msg string, attrs []any) string {
+	var b strings.Builder
+	b.WriteString(`{"ts":`)
+	writeJSONLogValue(&b, ts.UTC().Format(time.RFC3339Nano))
+	b.WriteString(`,"level":`)
+	writeJSONLogValue(&b, level)
+	b.WriteString(`,"msg":`)
+	writeJSONLogValue(&b, msg)
+	for i := 0; i < len(attrs); i += 2 {
+		key := "!BADKEY"
+		val := attrs[i]
+		if i+1 < len(attrs) {
+			key = fmt.Sprint(attrs[i])
+			val = attrs[i+1]
+		}
+		switch key {
+		case "ts", "level", "msg":
+			key = "_" + key
+		}
+		b.WriteByte(',')
+		writeJSONLogValue(&b, key)
+		b.WriteByte(':')
+		writeJSONLogValue(&b, jsonLogValue(val))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// jsonLogValue keeps strings, bools and numbers as JSON scalars and renders
+// everything else (errors, durations, structs) the way the text format does.
+func jsonLogValue(v any) any {
+	switch x := v.(type) {
+	case nil, string, bool,
+		int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64:
+		return x
+	case float64:
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return fmt.Sprint(x)
+		}
+		return x
+	case float32:
+		if math.IsNaN(float64(x)) || math.IsInf(float64(x), 0) {
+			return fmt.Sprint(x)
+		}
+		return x
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+func writeJSONLogValue(b *strings.Builder, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprint(v))
+	}
+	b.Write(data)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFormatJSONLogLine(t *testing.T) {
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	line := formatJSONLogLine(ts, "WARN", `bad "share"`, []any{
+		"component", "stratum",
+		"count", 3,
+		"ok", true,
+		"rate", math.Inf(1),
+		"error", errors.New("boom"),
+		"wait", 1500 * time.Millisecond,
+		"msg", "shadowed",
+		"dangling",
+	})
+	if line[len(line)-1] != '\n' {
+		t.Fatalf("expected trailing newline: %q", line)
+	}
+	var got map[string]any
+	if err := json.Unmarshal([]byte(line), &got); err != nil {
+		t.Fatalf("unmarshal %q: %v", line, err)
+	}
+	want := map[string]any{
+		"ts":        "2026-01-02T03:04:05Z",
+		"level":     "WARN",
+		"msg":       `bad "share"`,
+		"component": "stratum",
+		"count":     float64(3),
+		"ok":        true,
+		"rate":      "+Inf",
+		"error":     "boom",
+		"wait":      "1.5s",
+		"_msg":      "shadowed",
+		"!BADKEY":   "dangling",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d keys, want %d: %v", len(got), len(want), got)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Fatalf("%s = %#v, want %#v", k, got[k], v)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// logRotationPolicy controls what happens to rolled daily log segments
+// (pool, debug and net-debug alike) once a new day's file is opened.
+type logRotationPolicy struct {
+	// RetentionDays deletes segments older than this many days (0 keeps all).
+	RetentionDays int
+	// RetentionFiles keeps at most this many rolled segments per stream,
+	// newest first (0 = no count limit).
+	RetentionFiles int
+	// Compress gzips rolled segments.
+	Compress bool
+}
+
+var (
+	logRotationMu      sync.RWMutex
+	currentLogRotation = logRotationPolicy{RetentionDays: logRetentionDays}
+)
+
+// setLogRotationPolicy applies the [logging] retention/compression settings
+// to every rolling log writer; it takes effect at the next rollover.
+func setLogRotationPolicy(cfg Config) {
+	logRotationMu.Lock()
+	currentLogRotation = logRotationPolicy{
+		RetentionDays:  cfg.LogRetentionDays,
+		RetentionFiles: cfg.LogRetentionFiles,
+		Compress:       cfg.LogCompressRotated,
+	}
+	logRotationMu.Unlock()
+}
+
+func activeLogRotationPolicy() logRotationPolicy {
+	logRotationMu.RLock()
+	defer logRotationMu.RUnlock()
+	return currentLogRotation
+}
+
+// rolledLogSegment is one dated log file of a stream, plain or gzipped.
+type rolledLogSegment struct {
+	name       string
+	date       time.Time
+	compressed bool
+}
+
+// rolledLogSegments lists the dated segments of stream name in dir, newest
+// first, excluding the segment named active.
+func rolledLogSegments(dir, name, ext, active string) []rolledLogSegment {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	prefix := name + "-"
+	var out []rolledLogSegment
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		file := entry.Name()
+		if file == active || !strings.HasPrefix(file, prefix) {
+			continue
+		}
+		rest := file[len(prefix):]
+		compressed := strings.HasSuffix(rest, ext+".gz")
+		switch {
+		case compressed:
+			rest = strings.TrimSuffix(rest, ext+".gz")
+		case strings.HasSuffix(rest, ext):
+			rest = strings.TrimSuffix(rest, ext)
+		default:
+			continue
+		}
+		date, err := time.Parse("2006-01-02", rest)
+		if err != nil {
+			continue
+		}
+		out = append(out, rolledLogSegment{name: file, date: date, compressed: compressed})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].date.After(out[j].date) })
+	return out
+}
+
+// maintainRolledLogs applies retention and compression to a stream's rolled
+// segments. The currently open segment (active) and anything dated today or
+// later are never touched.
+func maintainRolledLogs(dir, name, ext, active string, policy logRotationPolicy, now time.Time) {
+	today := now.UTC().Truncate(24 * time.Hour)
+	var cutoff time.Time
+	if policy.RetentionDays > 0 {
+		cutoff = now.UTC().AddDate(0, 0, -(policy.RetentionDays - 1))
+	}
+	kept := 0
+	for _, seg := range rolledLogSegments(dir, name, ext, active) {
+		if !seg.date.Before(today) {
+			continue
+		}
+		path := filepath.Join(dir, seg.name)
+		if (!cutoff.IsZero() && seg.date.Before(cutoff)) || (policy.RetentionFiles > 0 && kept >= policy.RetentionFiles) {
+			_ = os.Remove(path)
+			continue
+		}
+		kept++
+		if policy.Compress && !seg.compressed {
+			if err := gzipLogSegment(path); err != nil {
+				logger.Warn("compress rolled log", "component", "logging", "kind", "rotation", "path", path, "error", err)
+			}
+		}
+	}
+}
+
+// gzipLogSegment compresses path to path.gz via a temp file and an atomic
+// rename, then removes the original. A partial .gz is never left behind
+// under the final name.
+func gzipLogSegment(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp := path + ".gz.tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	_, err = io.Copy(zw, src)
+	if cerr := zw.Close(); err == nil {
+		err = cerr
+	}
+	if serr := dst.Sync(); err == nil {
+		err = serr
+	}
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path+".gz"); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Remove(path)
+}
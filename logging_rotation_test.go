@@ -0,0 +1,84 @@
+package main
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMaintainRolledLogsCompressesAndPrunes(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	write := func(name string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("line "+name+"\n"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	active := "pool-2026-03-10.log"
+	for _, name := range []string{
+		active,
+		"pool-2026-03-09.log",
+		"pool-2026-03-08.log",
+		"pool-2026-03-07.log.gz",
+		"pool-2026-03-01.log",
+		"debug-2026-03-09.log",
+	} {
+		write(name)
+	}
+
+	policy := logRotationPolicy{RetentionDays: 7, RetentionFiles: 2, Compress: true}
+	maintainRolledLogs(dir, "pool", ".log", active, policy, now)
+
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(dir, name))
+		return err == nil
+	}
+	for _, name := range []string{active, "pool-2026-03-09.log.gz", "pool-2026-03-08.log.gz", "debug-2026-03-09.log"} {
+		if !exists(name) {
+			t.Fatalf("expected %s to exist", name)
+		}
+	}
+	// Over the file count (03-07) or past the age limit (03-01).
+	for _, name := range []string{"pool-2026-03-09.log", "pool-2026-03-07.log.gz", "pool-2026-03-01.log", "pool-2026-03-09.log.gz.tmp"} {
+		if exists(name) {
+			t.Fatalf("expected %s to be removed", name)
+		}
+	}
+
+	f, err := os.Open(filepath.Join(dir, "pool-2026-03-09.log.gz"))
+	if err != nil {
+		t.Fatalf("open gz: %v", err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("read gz: %v", err)
+	}
+	if string(data) != "line pool-2026-03-09.log\n" {
+		t.Fatalf("unexpected decompressed contents %q", data)
+	}
+}
+
+func TestMaintainRolledLogsNeverTouchesActiveFile(t *testing.T) {
+	dir := t.TempDir()
+	// The writer is still on yesterday's file (no write since midnight).
+	now := time.Date(2026, 3, 10, 0, 5, 0, 0, time.UTC)
+	active := "pool-2026-03-09.log"
+	if err := os.WriteFile(filepath.Join(dir, active), []byte("open\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	maintainRolledLogs(dir, "pool", ".log", active, logRotationPolicy{RetentionFiles: 1, Compress: true}, now)
+	if _, err := os.Stat(filepath.Join(dir, active)); err != nil {
+		t.Fatalf("active log was touched: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, active+".gz")); err == nil {
+		t.Fatalf("active log must not be compressed")
+	}
+}
@@ -106,6 +106,9 @@ func main() {
 	netDebugFlag := flag.Bool("net-debug", false, "enable raw network debug logging at startup (when supported)")
 	backupOnBootFlag := flag.Bool("backup-on-boot", false, "run a forced database backup once at startup (best-effort)")
 	minerProfileJSONFlag := flag.String("miner-profile-json", "", "optional path to write aggregated miner profile JSON for offline tuning")
+	recordSharesFlag := flag.String("record-shares", "", "append every processed share to a JSON-lines file that -replay-shares can replay")
+	replaySharesFlag := flag.String("replay-shares", "", "replay recorded shares from a JSON-lines file through share validation, print tallies and exit (no listeners)")
+	checkConfigFlag := flag.Bool("check-config", false, "load and validate the config, print the effective settings as JSON and exit (0 = valid, 1 = invalid); writes nothing and contacts nothing")
 	savedWorkersLocalNoAuthFlag := flag.Bool("saved-workers-local-noauth", false, "allow saved-workers pages without Clerk auth (local single-user mode)")
 	flag.Parse()
 
@@ -158,15 +161,24 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	// Set up SIGUSR1/SIGUSR2 handler for template/config reloading
+	// Set up SIGUSR1/SIGUSR2/SIGHUP handler for template/config reloading
 	reloadChan := make(chan os.Signal, 1)
-	signal.Notify(reloadChan, syscall.SIGUSR1, syscall.SIGUSR2)
+	signal.Notify(reloadChan, syscall.SIGUSR1, syscall.SIGUSR2, syscall.SIGHUP)
 
 	cfgPath := defaultConfigPath()
+	configCheckOnly = *checkConfigFlag
 	cfg, secretsPath := loadConfig(cfgPath, *secretsFlag)
+	if configCheckOnly {
+		os.Exit(runConfigCheck(cfg, overrides, secretsPath, cfgPath, os.Stdout))
+	}
 	if err := applyRuntimeOverrides(&cfg, overrides); err != nil {
 		fatal("config", err)
 	}
+	if *replaySharesFlag != "" {
+		// Offline correctness check against the current share policy; needs
+		// no node, so it runs before RPC credentials are resolved.
+		os.Exit(runShareReplay(*replaySharesFlag, cfg, os.Stdout))
+	}
 	if err := finalizeRPCCredentials(&cfg, secretsPath, overrides.allowRPCCredentials, cfgPath); err != nil {
 		fatal("rpc auth", err)
 	}
@@ -196,6 +208,10 @@ func main() {
 	// Mirror current log-level into globals used by hot paths.
 	debugLogging = debugEnabled()
 	verboseRuntimeLogging = verboseRuntimeEnabled()
+	setTraceSamplePercent(cfg.LogTraceSamplePercent)
+	setLogRotationPolicy(cfg)
+	setDisplayTimezone(cfg.DisplayTimezone)
+	setCoinbaseMaxBytes(cfg.CoinbaseMaxBytes)
 
 	cleanBansOnStartup := cfg.CleanExpiredBansOnStartup
 	if !cleanBansOnStartup {
@@ -217,29 +233,9 @@ func main() {
 		SetChainParams("mainnet")
 	}
 
-	// Derive a concise coinbase tag like "/goPool/" or "/<prefix>-goPool/",
-	// truncated to at most 40 bytes and restricted to printable ASCII so it
-	// stays within standard coinbase scriptSig bounds.
-	brand := poolSoftwareName
-	if cfg.PoolTagPrefix != "" {
-		brand = cfg.PoolTagPrefix + "-" + brand
-	}
-	tag := "/" + brand + "/"
-	// Keep only printable ASCII bytes.
-	var buf []byte
-	for i := 0; i < len(tag); i++ {
-		b := tag[i]
-		if b >= 0x20 && b <= 0x7e {
-			buf = append(buf, b)
-		}
-	}
-	if len(buf) == 0 {
-		buf = []byte("/" + brand + "/")
-	}
-	if len(buf) > 40 {
-		buf = buf[:40]
-	}
-	cfg.CoinbaseMsg = string(buf)
+	// Derive the coinbase tag ("/goPool/" or "/<prefix>-goPool/"); config
+	// reloads use the same derivation.
+	cfg.CoinbaseMsg = poolCoinbaseTag(cfg.PoolTagPrefix)
 
 	// After loading config, applying CLI/network overrides, and deriving
 	// the effective coinbase tag (all of which are local operations),
@@ -267,7 +263,7 @@ func main() {
 			fatal("debug log file", err)
 		}
 	}
-	configureFileLogging(logPath, errorLogPath, debugLogPath, *stdoutLogFlag)
+	configureFileLogging(logPath, errorLogPath, debugLogPath, *stdoutLogFlag, cfg.LogFormat)
 	ensureSubmissionWorkerPool()
 	defer logger.Stop()
 
@@ -362,6 +358,8 @@ func main() {
 		if clerkErr != nil {
 			logger.Warn("initialize clerk verifier", "error", clerkErr)
 		}
+	} else if cfg.OfflineMode {
+		logger.Info("clerk auth disabled", "reason", "offline_mode")
 	} else {
 		logger.Info("clerk auth disabled", "reason", "clerk_secret_key, clerk_publishable_key, and clerk_frontend_api_url are required")
 	}
@@ -432,6 +430,12 @@ func main() {
 			time.Duration(cfg.ReconnectBanDurationSeconds)*time.Second,
 		)
 	}
+	sourcePorts := newSourcePortTracker(cfg.SourcePortChurnPerMin, time.Duration(cfg.ReconnectBanDurationSeconds)*time.Second)
+	importedBans := newBanFeed(cfg)
+	if importedBans != nil {
+		importedBans.loadCache()
+		go importedBans.run(ctx, time.Duration(cfg.BanFeedRefreshSeconds)*time.Second)
+	}
 	if profiler := newMinerProfileCollector(*minerProfileJSONFlag); profiler != nil {
 		setMinerProfileCollector(profiler)
 		defer func() {
@@ -444,6 +448,19 @@ func main() {
 		logger.Info("miner profile collector enabled", "path", *minerProfileJSONFlag)
 	}
 
+	if recorder, err := newShareRecorder(*recordSharesFlag); err != nil {
+		fatal("share recorder", err, "path", *recordSharesFlag)
+	} else if recorder != nil {
+		setShareRecorder(recorder)
+		defer func() {
+			setShareRecorder(nil)
+			if err := recorder.Close(); err != nil {
+				logger.Warn("close share recorder", "error", err, "path", *recordSharesFlag)
+			}
+		}()
+		logger.Info("recording shares for -replay-shares", "path", *recordSharesFlag)
+	}
+
 	// Start the status webserver before connecting to the node so operators
 	// can see connection state while bitcoind starts up.
 	statusServer := NewStatusServer(ctx, nil, metrics, registry, workerRegistry, accounting, rpcClient, cfg, startTime, clerkVerifier, workerLists, cfgPath, adminConfigPath, stop)
@@ -452,6 +469,7 @@ func main() {
 		logger.Warn("saved-workers local no-auth mode enabled", "flag", "saved-workers-local-noauth")
 	}
 	statusServer.SetBackupService(backupSvc)
+	statusServer.banFeed = importedBans
 	statusServer.startOneTimeCodeJanitor(ctx)
 	statusServer.loadOneTimeCodesFromDB(cfg.DataDir)
 	statusServer.startOneTimeCodePersistence(ctx)
@@ -462,8 +480,64 @@ func main() {
 	if err := notifier.start(ctx); err != nil {
 		logger.Warn("discord notifier start failed", "error", err)
 	}
+	go runHashrateDropMonitor(ctx, statusServer, notifier)
+	go runMetricsPusher(ctx, statusServer)
+	go runAutoProfiler(ctx, statusServer.Config)
+
+	// Config reloads can be triggered by SIGUSR2, SIGHUP or a tuning.toml
+	// change. Serialize them so a signal arriving mid-reload waits for the
+	// in-progress reload instead of interleaving config/log-level updates.
+	var configReloadMu sync.Mutex
+	reloadConfig := func(sigName string) (Config, bool) {
+		configReloadMu.Lock()
+		defer configReloadMu.Unlock()
+		logger.Info(sigName + " received, reloading config")
+		if err := checkTuningFile(tuningFilePath(statusServer.Config())); err != nil {
+			logger.Error("config reload refused; keeping current config", "component", "startup", "kind", "config_reload", "error", err, "signal", sigName)
+			return Config{}, false
+		}
+		reloadedCfg, err := reloadStatusConfig(cfgPath, *secretsFlag, overrides)
+		if err != nil {
+			logger.Error("config reload failed", "error", err, "signal", sigName)
+			return Config{}, false
+		}
+		if _, _, err := derivePayoutScripts(reloadedCfg); err != nil {
+			logger.Error("config reload refused; keeping current config and payout scripts", "component", "startup", "kind", "config_reload", "error", err, "signal", sigName)
+			return Config{}, false
+		}
+		if _, err := derivePayoutSplitScripts(reloadedCfg); err != nil {
+			logger.Error("config reload refused; keeping current config and payout scripts", "component", "startup", "kind", "config_reload", "error", err, "signal", sigName)
+			return Config{}, false
+		}
+		statusServer.UpdateConfig(reloadedCfg)
+		if reloadedCfg.LogDebug {
+			setLogLevel(logLevelDebug)
+		} else {
+			setLogLevel(logLevelInfo)
+		}
+		verboseRuntimeLogging = verboseRuntimeEnabled()
+		setTraceSamplePercent(reloadedCfg.LogTraceSamplePercent)
+		setLogRotationPolicy(reloadedCfg)
+		setDisplayTimezone(reloadedCfg.DisplayTimezone)
+		setCoinbaseMaxBytes(reloadedCfg.CoinbaseMaxBytes)
+		if reloadedCfg.LogNetDebug {
+			netPath := ""
+			netPath, netPathErr := initNetLogOutput(reloadedCfg, strings.TrimSpace(*logDirFlag), strings.TrimSpace(*netDebugLogPathFlag))
+			if netPathErr != nil {
+				logger.Warn("net-debug reload init failed", "error", netPathErr)
+			} else if err := setNetLogRuntime(true, newDailyRollingFileWriter(netPath)); err != nil {
+				logger.Warn("net-debug reload enable failed", "error", err)
+			}
+		} else {
+			if err := setNetLogRuntime(false, nil); err != nil {
+				logger.Warn("net-debug reload disable failed", "error", err)
+			}
+		}
+		logger.Info("config reloaded", "component", "startup", "kind", "config_reload", "path", cfgPath, "signal", sigName)
+		return reloadedCfg, true
+	}
 
-	// Start SIGUSR1/SIGUSR2 handler for embedded UI refreshes and config reloading.
+	// Start SIGUSR1/SIGUSR2/SIGHUP handler for embedded UI refreshes and config reloading.
 	go func() {
 		for {
 			select {
@@ -474,39 +548,17 @@ func main() {
 				case syscall.SIGUSR1:
 					logger.Info("SIGUSR1 received, refreshing embedded templates and static cache")
 					if err := statusServer.ReloadTemplates(); err != nil {
-						logger.Error("template reload failed", "error", err)
+						logger.Error("template reload failed; previous templates still serving", "error", err)
 					}
 					if err := statusServer.ReloadStaticFiles(); err != nil {
-						logger.Error("static cache reload failed", "error", err)
+						logger.Error("static cache reload failed; previous static cache still serving", "error", err)
 					}
 				case syscall.SIGUSR2:
-					logger.Info("SIGUSR2 received, reloading config")
-					reloadedCfg, err := reloadStatusConfig(cfgPath, *secretsFlag, overrides)
-					if err != nil {
-						logger.Error("config reload failed", "error", err)
-						continue
-					}
-					statusServer.UpdateConfig(reloadedCfg)
-					if reloadedCfg.LogDebug {
-						setLogLevel(logLevelDebug)
-					} else {
-						setLogLevel(logLevelInfo)
-					}
-					verboseRuntimeLogging = verboseRuntimeEnabled()
-					if reloadedCfg.LogNetDebug {
-						netPath := ""
-						netPath, netPathErr := initNetLogOutput(reloadedCfg, strings.TrimSpace(*logDirFlag), strings.TrimSpace(*netDebugLogPathFlag))
-						if netPathErr != nil {
-							logger.Warn("net-debug reload init failed", "error", netPathErr)
-						} else if err := setNetLogRuntime(true, newDailyRollingFileWriter(netPath)); err != nil {
-							logger.Warn("net-debug reload enable failed", "error", err)
-						}
-					} else {
-						if err := setNetLogRuntime(false, nil); err != nil {
-							logger.Warn("net-debug reload disable failed", "error", err)
-						}
-					}
-					logger.Info("config reloaded", "component", "startup", "kind", "config_reload", "path", cfgPath)
+					go reloadConfig("SIGUSR2")
+				case syscall.SIGHUP:
+					// SIGHUP is the conventional daemon reload signal; treat it as
+					// an alias for SIGUSR2 rather than the default terminate.
+					go reloadConfig("SIGHUP")
 				}
 			}
 		}
@@ -526,22 +578,29 @@ func main() {
 		mux.HandleFunc("/api/node", statusServer.handleNodePageJSON)
 		mux.HandleFunc("/api/server", statusServer.handleServerPageJSON)
 		mux.HandleFunc("/api/pool-hashrate", statusServer.handlePoolHashrateJSON)
+		mux.HandleFunc("/api/worker-history", statusServer.handleWorkerHashrateHistoryJSON)
+		mux.HandleFunc("/api/stratum-health", statusServer.handleStratumHealthJSON)
 		mux.HandleFunc("/api/auth/session-refresh", statusServer.handleClerkSessionRefresh)
 		mux.HandleFunc("/api/saved-workers", statusServer.withClerkUser(statusServer.handleSavedWorkersJSON))
 		mux.HandleFunc("/api/saved-workers/history", statusServer.withClerkUser(statusServer.handleSavedWorkerHistoryJSON))
 		mux.HandleFunc("/api/saved-workers/notify-enabled", statusServer.withClerkUser(statusServer.handleSavedWorkersNotifyEnabled))
+		mux.HandleFunc("/api/saved-workers/difficulty", statusServer.withClerkUser(statusServer.handleSavedWorkerDifficulty))
 		mux.HandleFunc("/api/discord/notify-enabled", statusServer.withClerkUser(statusServer.handleDiscordNotifyEnabled))
 		mux.HandleFunc("/api/saved-workers/one-time-code", statusServer.withClerkUser(statusServer.handleSavedWorkersOneTimeCode))
 		mux.HandleFunc("/api/saved-workers/one-time-code/clear", statusServer.withClerkUser(statusServer.handleSavedWorkersOneTimeCodeClear))
 
 		// Other endpoints
 		mux.HandleFunc("/api/blocks", statusServer.handleBlocksListJSON)
+		mux.HandleFunc("/api/blocks/detail", statusServer.handleBlockDetailJSON)
 	}
 	// HTML endpoints
 	mux.HandleFunc("/admin", statusServer.handleAdminPage)
 	mux.HandleFunc("/admin/miners", statusServer.handleAdminMinersPage)
 	mux.HandleFunc("/admin/miners/disconnect", statusServer.handleAdminMinerDisconnect)
 	mux.HandleFunc("/admin/miners/ban", statusServer.handleAdminMinerBan)
+	mux.HandleFunc("/admin/miners/setdiff", statusServer.handleAdminMinerSetDiff)
+	mux.HandleFunc("/admin/miners/rotate-extranonce", statusServer.handleAdminMinerRotateExtranonce)
+	mux.HandleFunc("/admin/drain", statusServer.handleAdminDrain)
 	mux.HandleFunc("/admin/logins", statusServer.handleAdminLoginsPage)
 	mux.HandleFunc("/admin/logins/delete", statusServer.handleAdminLoginDelete)
 	mux.HandleFunc("/admin/logins/ban", statusServer.handleAdminLoginBan)
@@ -573,6 +632,11 @@ func main() {
 	mux.HandleFunc("/pool", statusServer.handlePoolInfo)
 	mux.HandleFunc("/server", statusServer.handleServerInfoPage)
 	mux.HandleFunc("/about", statusServer.handleAboutPage)
+	mux.HandleFunc("/status.txt", statusServer.handleStatusText)
+	if cfg.MetricsEnabled {
+		mux.HandleFunc("/metrics", statusServer.handleMetrics)
+	}
+	mux.HandleFunc("/status-lite", statusServer.handleStatusLitePage)
 	mux.HandleFunc("/help", statusServer.handleHelpPage)
 	// Static legal pages
 	mux.HandleFunc("/privacy", statusServer.handleStaticFile("privacy.html"))
@@ -629,7 +693,23 @@ func main() {
 
 	var statusHTTPServer *http.Server
 	var statusHTTPSServer *http.Server
-	appHandler := statusServer.serveShortResponseCache(mux)
+	appHandler := withStatusRequestThrottle(
+		statusServer.withMaintenancePage(statusServer.serveShortResponseCache(mux)),
+		newStatusRequestThrottle(cfg.StatusRequestsPerSecond, cfg.StatusMaxInflightRequests),
+	)
+
+	// Optional Stratum over WebSocket on the status server for browser
+	// miners; served by the stratum accept loop below like any listener.
+	var wsStratumLn *wsStratumListener
+	if path := cfg.StratumWebSocketPath; path != "" {
+		if httpAddr == "" && httpsAddr == "" {
+			logger.Warn("stratum websocket path set but the status server is disabled", "component", "stratum", "kind", "websocket", "path", path)
+		} else {
+			wsStratumLn = newWSStratumListener(path)
+			appHandler = wsStratumLn.wrap(appHandler)
+			logger.Info("stratum websocket enabled", "component", "stratum", "kind", "listen", "path", path)
+		}
+	}
 
 	// Start HTTP server.
 	if httpAddr != "" {
@@ -640,6 +720,9 @@ func main() {
 			httpHandler = http.HandlerFunc(statusServer.redirectToHTTPS)
 			httpLogMsg = "status http listener redirecting to https"
 			httpLogFields = append(httpLogFields, "https_addr", httpsAddr)
+			if wsStratumLn != nil {
+				httpHandler = wsStratumLn.wrap(httpHandler)
+			}
 		}
 
 		statusHTTPServer = &http.Server{
@@ -727,8 +810,18 @@ func main() {
 	// Once the node is reachable, derive a network-appropriate version mask
 	// from bitcoind instead of relying on a manual version_mask setting.
 	autoConfigureVersionMaskFromNode(ctx, rpcClient, &cfg)
+	statusServer.UpdateConfig(cfg)
+
+	payoutSplits, err := derivePayoutSplitScripts(cfg)
+	if err != nil {
+		fatal("payout splits", err)
+	}
+	if len(payoutSplits) > 0 {
+		logger.Info("pool fee split between operator wallets", "component", "startup", "kind", "payout", "splits", len(payoutSplits))
+	}
 
 	jobMgr := NewJobManager(rpcClient, cfg, metrics, payoutScript, donationScript)
+	jobMgr.payoutSplits = payoutSplits
 	statusServer.SetJobManager(jobMgr)
 	if cfg.ZMQHashBlockAddr != "" || cfg.ZMQRawBlockAddr != "" {
 		logger.Info("block updates via zmq + longpoll", "component", "startup", "kind", "job_feed", "hashblock_addr", cfg.ZMQHashBlockAddr, "rawblock_addr", cfg.ZMQRawBlockAddr)
@@ -736,13 +829,26 @@ func main() {
 		logger.Info("block updates via longpoll", "component", "startup", "kind", "job_feed")
 	}
 	jobMgr.Start(ctx)
+	if cfg.WatchTuningFile {
+		tuningPath := tuningFilePath(cfg)
+		logger.Info("watching tuning file for changes", "component", "startup", "kind", "tuning_watch", "path", tuningPath)
+		go watchTuningFile(ctx, tuningPath, tuningWatchInterval, func() {
+			configReloadMu.Lock()
+			defer configReloadMu.Unlock()
+			if _, err := statusServer.reloadTuningFile(tuningPath); err != nil {
+				logger.Error("tuning reload refused; keeping current config", "component", "startup", "kind", "tuning_watch", "path", tuningPath, "error", err)
+				return
+			}
+			logger.Info("tuning reloaded", "component", "startup", "kind", "tuning_watch", "path", tuningPath)
+		})
+	}
 
 	// Once Stratum is live, enforce the same freshness rule at runtime:
 	// - refuse new miner connections while the job feed is stale
 	// - disconnect existing miners so they stop hashing stale work
 	go enforceStratumFreshness(ctx, jobMgr, registry, statusServer, startTime)
 
-	ln, err := net.Listen("tcp", cfg.ListenAddr)
+	ln, err := listenStratum(ctx, cfg.ListenAddr, cfg.StratumReusePort)
 	if err != nil {
 		fatal("listen error", err, "addr", cfg.ListenAddr)
 	}
@@ -766,14 +872,46 @@ func main() {
 			go certReloader.watch(ctx)
 			logger.Info("tls certificate auto-reload enabled", "component", "stratum", "kind", "tls", "check_interval", "1h")
 		}
-		tlsCfg := &tls.Config{
-			GetCertificate: certReloader.getCertificate,
+		tlsCfg, err := stratumTLSConfig(certReloader, cfg.StratumTLSClientCA)
+		if err != nil {
+			fatal("stratum tls client ca", err, "path", cfg.StratumTLSClientCA)
 		}
-		tlsLn, err = tls.Listen("tcp", cfg.StratumTLSListen, tlsCfg)
+		rawTLSLn, err := listenStratum(ctx, cfg.StratumTLSListen, cfg.StratumReusePort)
 		if err != nil {
 			fatal("stratum tls listen error", err, "addr", cfg.StratumTLSListen)
 		}
-		logger.Info("stratum TLS listening", "component", "stratum", "kind", "listen", "addr", cfg.StratumTLSListen)
+		tlsLn = tls.NewListener(rawTLSLn, tlsCfg)
+		logger.Info("stratum TLS listening", "component", "stratum", "kind", "listen", "addr", cfg.StratumTLSListen, "client_cert_required", cfg.StratumTLSClientCA != "")
+	}
+
+	// Optional trusted-proxy listener (non-standard submit HMAC).
+	var proxyLn net.Listener
+	if addr := strings.TrimSpace(cfg.StratumProxyListen); addr != "" {
+		proxyLn, err = listenStratum(ctx, addr, cfg.StratumReusePort)
+		if err != nil {
+			fatal("stratum proxy listen error", err, "addr", addr)
+		}
+		logger.Info("stratum proxy listening (submit hmac required)", "component", "stratum", "kind", "listen", "addr", addr)
+	}
+
+	// Optional dedicated WebSocket listener. It feeds the same listener as
+	// stratum_websocket_path, so both share one "ws" accept loop.
+	var wsHTTPServer *http.Server
+	if addr := strings.TrimSpace(cfg.StratumWSListen); addr != "" {
+		rawWSLn, err := listenStratum(ctx, addr, cfg.StratumReusePort)
+		if err != nil {
+			fatal("stratum websocket listen error", err, "addr", addr)
+		}
+		if wsStratumLn == nil {
+			wsStratumLn = newWSStratumListener(cfg.StratumWebSocketPath)
+		}
+		wsHTTPServer = newStratumWSServer(wsStratumLn)
+		go func() {
+			if err := wsHTTPServer.Serve(rawWSLn); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("stratum websocket server error", "component", "stratum", "kind", "websocket", "addr", addr, "error", err)
+			}
+		}()
+		logger.Info("stratum websocket listening", "component", "stratum", "kind", "listen", "addr", addr)
 	}
 
 	var acceptLimiter *acceptRateLimiter
@@ -808,15 +946,27 @@ func main() {
 		}()
 	}
 
+	go runAcceptRPCBackoff(ctx, acceptLimiter, metrics, cfg)
+
 	var connWg sync.WaitGroup
 
 	go func() {
 		<-ctx.Done()
+		setPoolDraining(true)
 		logger.Info("shutdown requested; closing stratum listeners", "component", "stratum", "kind", "shutdown")
 		ln.Close()
 		if tlsLn != nil {
 			tlsLn.Close()
 		}
+		if proxyLn != nil {
+			proxyLn.Close()
+		}
+		if wsHTTPServer != nil {
+			wsHTTPServer.Close()
+		}
+		if wsStratumLn != nil {
+			wsStratumLn.Close()
+		}
 	}()
 
 	serveStratum := func(label string, l net.Listener) {
@@ -834,6 +984,14 @@ func main() {
 				logger.Error("accept error", "component", "stratum", "kind", "accept", "listener", label, "error", err)
 				continue
 			}
+			if stratumDrain.Active() {
+				if time.Since(lastRefuseLog) > 5*time.Second {
+					logger.Info("refusing miner connection: draining", "component", "stratum", "kind", "drain", "listener", label, "remote", conn.RemoteAddr().String())
+					lastRefuseLog = time.Now()
+				}
+				_ = conn.Close()
+				continue
+			}
 			disableTCPNagle(conn)
 			curCfg := statusServer.Config()
 			setTCPBuffers(conn, curCfg.StratumTCPReadBufferBytes, curCfg.StratumTCPWriteBufferBytes)
@@ -861,6 +1019,47 @@ func main() {
 				}
 			}
 			remote := conn.RemoteAddr().String()
+			if sourcePorts != nil || debugLogging {
+				host, portStr, _ := net.SplitHostPort(remote)
+				port, _ := strconv.Atoi(portStr)
+				if debugLogging {
+					logger.Debug("stratum accept", "component", "stratum", "kind", "accept", "listener", label, "host", host, "port", port)
+				}
+				allowed, anomaly := sourcePorts.observe(host, port, time.Now())
+				if anomaly != nil {
+					msg := "banning host for source-port churn"
+					if allowed {
+						// reconnect_ban_duration_seconds = 0: report only.
+						msg = "source-port churn detected; not banning"
+					}
+					logger.Warn(msg,
+						"component", "stratum", "kind", "source_port_churn",
+						"listener", label,
+						"host", host,
+						"distinct_ports", anomaly.DistinctPorts,
+						"accepts", anomaly.Accepts,
+						"port_min", anomaly.MinPort,
+						"port_max", anomaly.MaxPort,
+						"window", anomaly.Window.Round(time.Millisecond),
+						"limit_per_min", cfg.SourcePortChurnPerMin,
+						"ban", time.Duration(cfg.ReconnectBanDurationSeconds)*time.Second,
+					)
+					metrics.RecordErrorEvent("source_port_churn", fmt.Sprintf("%s used %d source ports in %s", host, anomaly.DistinctPorts, anomaly.Window.Round(time.Second)), time.Now())
+				}
+				if !allowed {
+					_ = conn.Close()
+					continue
+				}
+			}
+			if importedBans != nil {
+				if host := stratumRemoteHost(remote); importedBans.blocked(host) {
+					if debugLogging {
+						logger.Debug("rejecting miner: imported ban", "component", "stratum", "kind", "ban_feed", "listener", label, "remote", remote, "host", host)
+					}
+					_ = conn.Close()
+					continue
+				}
+			}
 			if reconnectLimiter != nil {
 				host, _, errSplit := net.SplitHostPort(remote)
 				if errSplit != nil {
@@ -883,7 +1082,31 @@ func main() {
 				_ = conn.Close()
 				continue
 			}
-			mc := NewMinerConn(ctx, conn, jobMgr, rpcClient, curCfg, metrics, accounting, workerRegistry, workerLists, notifier, label == "tls")
+			// The proxy listener carries many miners from one trusted host.
+			if curCfg.MaxConnsPerIP > 0 && label != "proxy" {
+				host := stratumRemoteHost(remote)
+				if registry.HostCount(host) >= curCfg.MaxConnsPerIP {
+					logger.Warn("rejecting miner: per-IP capacity", "component", "stratum", "kind", "capacity", "listener", label, "remote", remote, "host", host, "max_conns_per_ip", curCfg.MaxConnsPerIP)
+					_ = conn.Close()
+					continue
+				}
+			}
+			if memoryRefusingConnections() {
+				// Existing miners come first: shed new ones while over budget.
+				logger.Warn("rejecting miner: memory budget", "component", "stratum", "kind", "memory_budget", "listener", label, "remote", conn.RemoteAddr().String(), "level", memoryPressure().String())
+				_ = conn.Close()
+				continue
+			}
+			if label == "proxy" && len(curCfg.StratumProxyHMACSecret) < minStratumProxyHMACSecretLen {
+				// Never serve the proxy listener without a usable secret.
+				logger.Warn("rejecting proxy connection: stratum_proxy_hmac_secret not set", "component", "stratum", "kind", "proxy_hmac", "remote", conn.RemoteAddr().String())
+				_ = conn.Close()
+				continue
+			}
+			mc := NewMinerConn(ctx, conn, jobMgr, rpcClient, curCfg, metrics, accounting, workerRegistry, workerLists, notifier, label)
+			if label == "proxy" {
+				mc.submitHMACKey = []byte(curCfg.StratumProxyHMACSecret)
+			}
 			registry.Add(mc)
 
 			connWg.Add(1)
@@ -902,8 +1125,25 @@ func main() {
 	if tlsLn != nil {
 		go serveStratum("tls", tlsLn)
 	}
+	if proxyLn != nil {
+		go serveStratum("proxy", proxyLn)
+	}
+	if wsStratumLn != nil {
+		go serveStratum("ws", wsStratumLn)
+	}
+	go runFirstJobWatchdog(ctx, jobMgr, statusServer.Config, metrics, notifier)
+	go runCoinbaseTagCheck(ctx, jobMgr, statusServer.Config, metrics, notifier)
+	go runMemoryBudgetWatcher(ctx, statusServer.Config, metrics, notifier)
+	go runWriteStallWatchdog(ctx, registry, statusServer.Config)
+	if err := writeStratumReadyFile(cfg.DataDir); err != nil {
+		logger.Warn("stratum ready file", "component", "stratum", "kind", "listen", "error", err)
+	} else {
+		logger.Info("stratum ready", "component", "stratum", "kind", "listen", "pid", os.Getpid(), "ready_file", stratumReadyFilePath(cfg.DataDir), "reuse_port", cfg.StratumReusePort)
+	}
 	serveStratum("tcp", ln)
+	removeStratumReadyFile(cfg.DataDir)
 
+	setPoolDraining(true)
 	logger.Info("shutdown requested; draining active miners", "component", "stratum", "kind", "shutdown")
 	shutdownStart := time.Now()
 	for _, mc := range registry.Snapshot() {
@@ -1098,25 +1338,7 @@ func reloadStatusConfig(cfgPath, secretsPath string, overrides runtimeOverrides)
 		return Config{}, err
 	}
 
-	tag := poolSoftwareName
-	brand := strings.TrimSpace(cfg.StatusBrandName)
-	if brand != "" {
-		tag = poolSoftwareName + "-" + brand
-	}
-	var buf []byte
-	for i := 0; i < len(tag); i++ {
-		b := tag[i]
-		if b >= 0x20 && b <= 0x7e {
-			buf = append(buf, b)
-		}
-	}
-	if len(buf) == 0 {
-		buf = []byte(poolSoftwareName)
-	}
-	if len(buf) > 40 {
-		buf = buf[:40]
-	}
-	cfg.CoinbaseMsg = string(buf)
+	cfg.CoinbaseMsg = poolCoinbaseTag(cfg.PoolTagPrefix)
 
 	callbackPath := strings.TrimSpace(cfg.ClerkCallbackPath)
 	if callbackPath == "" {
@@ -1170,9 +1392,3 @@ func initNamedLogOutput(cfg Config, logDirOverride, pathOverride, baseName strin
 	}
 	return filepath.Join(logDir, baseName), nil
 }
-
-// sanityCheckPoolAddressRPC performs a one-shot RPC validation of the pool
-// payout address using the node's validateaddress RPC. It is intended as a
-// boot-time sanity check: if the call fails or the node reports the address
-// as invalid, the pool exits with a clear error instead of retrying. A short
-// timeout is used so startup is not blocked indefinitely on RPC issues.
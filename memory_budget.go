@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	minMemoryBudgetMB          = 64
+	memoryBudgetCheckInterval  = 5 * time.Second
+	memoryRefuseConnsPercent   = 80.0
+	memoryShedCachesPercent    = 90.0
+	memoryRecoveryHysteresis   = 5.0 // points below a threshold before its level clears
+	memoryShedMinRecentJobs    = 3
+	memoryShedDuplicateHistory = duplicateShareHistory / 4
+)
+
+// memoryPressureLevel is how hard the pool is shedding to stay inside
+// [memory] budget_mb. Levels only ever trade new work for existing miners:
+// found-block and accounting paths are never shed.
+type memoryPressureLevel int32
+
+const (
+	memoryPressureNone memoryPressureLevel = iota
+	// memoryPressureRefuseConns refuses new miner connections.
+	memoryPressureRefuseConns
+	// memoryPressureShedCaches additionally shortens per-connection retained
+	// jobs and duplicate-share caches.
+	memoryPressureShedCaches
+)
+
+func (l memoryPressureLevel) String() string {
+	switch l {
+	case memoryPressureRefuseConns:
+		return "refusing new connections"
+	case memoryPressureShedCaches:
+		return "refusing new connections and shedding caches"
+	default:
+		return "normal"
+	}
+}
+
+var currentMemoryPressure atomic.Int32
+
+func memoryPressure() memoryPressureLevel {
+	return memoryPressureLevel(currentMemoryPressure.Load())
+}
+
+func memoryRefusingConnections() bool {
+	return memoryPressure() >= memoryPressureRefuseConns
+}
+
+// duplicateShareLimit is the per-job duplicate-share history length.
+func duplicateShareLimit() int {
+	if memoryPressure() >= memoryPressureShedCaches {
+		return memoryShedDuplicateHistory
+	}
+	return duplicateShareHistory
+}
+
+// retainedJobLimit is how many recent jobs mc keeps for late submits.
+func (mc *MinerConn) retainedJobLimit() int {
+	if memoryPressure() < memoryPressureShedCaches {
+		return mc.maxRecentJobs
+	}
+	return min(mc.maxRecentJobs, max(mc.maxRecentJobs/2, memoryShedMinRecentJobs))
+}
+
+func memoryPressureForPercent(pct, slack float64) memoryPressureLevel {
+	switch {
+	case pct >= memoryShedCachesPercent-slack:
+		return memoryPressureShedCaches
+	case pct >= memoryRefuseConnsPercent-slack:
+		return memoryPressureRefuseConns
+	default:
+		return memoryPressureNone
+	}
+}
+
+// nextMemoryPressure returns the level for used bytes against budget bytes.
+// Levels rise as soon as a threshold is crossed but only fall once usage is
+// memoryRecoveryHysteresis points below it, so the pool does not flap.
+func nextMemoryPressure(cur memoryPressureLevel, used, budget uint64) memoryPressureLevel {
+	if budget == 0 {
+		return memoryPressureNone
+	}
+	pct := float64(used) * 100 / float64(budget)
+	level := memoryPressureForPercent(pct, 0)
+	if level < cur {
+		level = min(cur, memoryPressureForPercent(pct, memoryRecoveryHysteresis))
+	}
+	return level
+}
+
+// processMemoryInUse approximates resident memory from the Go runtime: what
+// it has obtained from the OS minus what it has already returned.
+func processMemoryInUse() uint64 {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return ms.Sys - ms.HeapReleased
+}
+
+// runMemoryBudgetWatcher enforces [memory] budget_mb. It sets the Go soft
+// memory limit to the budget so the GC works harder before shedding starts,
+// then raises or clears the shedding level every few seconds and logs and
+// alerts on each change.
+func runMemoryBudgetWatcher(ctx context.Context, cfgFn func() Config, metrics *PoolMetrics, notifier *discordNotifier) {
+	if cfgFn == nil {
+		return
+	}
+	defaultLimit := debug.SetMemoryLimit(-1)
+	appliedMB := 0
+	ticker := time.NewTicker(memoryBudgetCheckInterval)
+	defer ticker.Stop()
+	for {
+		budgetMB := cfgFn().MemoryBudgetMB
+		if budgetMB != appliedMB {
+			if budgetMB > 0 {
+				debug.SetMemoryLimit(int64(budgetMB) << 20)
+			} else {
+				debug.SetMemoryLimit(defaultLimit)
+			}
+			appliedMB = budgetMB
+		}
+		cur := memoryPressure()
+		next := memoryPressureNone
+		var used uint64
+		if budgetMB > 0 {
+			used = processMemoryInUse()
+			next = nextMemoryPressure(cur, used, uint64(budgetMB)<<20)
+		}
+		if next != cur {
+			currentMemoryPressure.Store(int32(next))
+			reportMemoryPressureChange(cur, next, used, budgetMB, metrics, notifier)
+			if next == memoryPressureShedCaches {
+				debug.FreeOSMemory()
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func reportMemoryPressureChange(from, to memoryPressureLevel, used uint64, budgetMB int, metrics *PoolMetrics, notifier *discordNotifier) {
+	usedMB := math.Round(float64(used) / (1 << 20))
+	fields := []any{"component", "runtime", "kind", "memory_budget", "level", to.String(), "previous", from.String(), "used_mb", usedMB, "budget_mb", budgetMB}
+	if to < from {
+		logger.Info("memory pressure eased", fields...)
+		if to == memoryPressureNone {
+			notifier.NotifyPoolAlert(fmt.Sprintf("Memory back under budget (%.0f of %d MiB); accepting new miners again.", usedMB, budgetMB))
+		}
+		return
+	}
+	msg := fmt.Sprintf("Memory at %.0f of %d MiB budget; %s.", usedMB, budgetMB, to)
+	logger.Warn("memory pressure rising", fields...)
+	if metrics != nil {
+		metrics.RecordErrorEvent("memory_budget", msg, time.Now())
+	}
+	notifier.NotifyPoolAlert(msg)
+}
@@ -0,0 +1,58 @@
+package main
+
+import "testing"
+
+func TestNextMemoryPressureHysteresis(t *testing.T) {
+	const budget = 1000
+	steps := []struct {
+		cur  memoryPressureLevel
+		used uint64
+		want memoryPressureLevel
+	}{
+		{memoryPressureNone, 700, memoryPressureNone},
+		{memoryPressureNone, 820, memoryPressureRefuseConns},
+		{memoryPressureRefuseConns, 950, memoryPressureShedCaches},
+		// Below 90% but within the hysteresis band: keep shedding.
+		{memoryPressureShedCaches, 870, memoryPressureShedCaches},
+		{memoryPressureShedCaches, 840, memoryPressureRefuseConns},
+		{memoryPressureRefuseConns, 770, memoryPressureRefuseConns},
+		{memoryPressureRefuseConns, 740, memoryPressureNone},
+	}
+	for i, s := range steps {
+		if got := nextMemoryPressure(s.cur, s.used, budget); got != s.want {
+			t.Fatalf("step %d: nextMemoryPressure(%v, %d) = %v, want %v", i, s.cur, s.used, got, s.want)
+		}
+	}
+	if got := nextMemoryPressure(memoryPressureShedCaches, 5000, 0); got != memoryPressureNone {
+		t.Fatalf("expected no pressure without a budget, got %v", got)
+	}
+}
+
+func TestMemoryShedCacheLimits(t *testing.T) {
+	t.Cleanup(func() { currentMemoryPressure.Store(int32(memoryPressureNone)) })
+	mc := &MinerConn{maxRecentJobs: 8}
+
+	currentMemoryPressure.Store(int32(memoryPressureRefuseConns))
+	if !memoryRefusingConnections() || mc.retainedJobLimit() != 8 || duplicateShareLimit() != duplicateShareHistory {
+		t.Fatalf("refuse level must only refuse new connections")
+	}
+
+	currentMemoryPressure.Store(int32(memoryPressureShedCaches))
+	if got := mc.retainedJobLimit(); got != 4 {
+		t.Fatalf("expected retained jobs halved to 4, got %d", got)
+	}
+	if got := (&MinerConn{maxRecentJobs: 2}).retainedJobLimit(); got != 2 {
+		t.Fatalf("shedding must never raise the retained job limit, got %d", got)
+	}
+
+	var set duplicateShareSet
+	for i := range duplicateShareHistory {
+		var key duplicateShareKey
+		key.n = 1
+		key.buf[0] = byte(i)
+		set.seenOrAdd(key)
+	}
+	if len(set.order) > memoryShedDuplicateHistory {
+		t.Fatalf("duplicate cache not shortened under pressure: %d entries", len(set.order))
+	}
+}
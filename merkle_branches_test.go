@@ -99,3 +99,53 @@ func TestPrepareShareContextMerkleBytesEquivalent(t *testing.T) {
 		t.Fatalf("share context differs: string=%+v bytes=%+v", ctxStr, ctxBytes)
 	}
 }
+
+func TestVerifyJobMerkleRoot(t *testing.T) {
+	_, payoutScript := generateTestWallet(t)
+	txids := make([][]byte, 5)
+	for i := range txids {
+		txids[i] = bytes.Repeat([]byte{byte(i + 1)}, 32)
+	}
+	job := &Job{
+		JobID:           "merkle-check",
+		Template:        GetBlockTemplateResult{Height: 840000},
+		Extranonce2Size: 4,
+		CoinbaseValue:   50_0000_0000,
+		CoinbaseMsg:     "/goPool/",
+		ScriptTime:      time.Now().Unix(),
+		MerkleBranches:  buildMerkleBranches(txids),
+		TransactionIDs:  txids,
+		PayoutScript:    payoutScript,
+	}
+	if err := verifyJobMerkleRoot(job); err != nil {
+		t.Fatalf("expected merkle self-check to pass: %v", err)
+	}
+
+	job.MerkleBranches = append([]string(nil), job.MerkleBranches...)
+	job.MerkleBranches[1] = "cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc"
+	if err := verifyJobMerkleRoot(job); err == nil {
+		t.Fatalf("expected merkle self-check to fail with a corrupted branch")
+	}
+
+	// Empty templates (coinbase only) have no branches.
+	job.TransactionIDs = nil
+	job.MerkleBranches = buildMerkleBranches(nil)
+	if err := verifyJobMerkleRoot(job); err != nil {
+		t.Fatalf("expected coinbase-only merkle self-check to pass: %v", err)
+	}
+}
+
+func TestMerkleBranchesMatchFullTree(t *testing.T) {
+	coinbaseTxid := bytes.Repeat([]byte{0x99}, 32)
+	for n := 0; n <= 9; n++ {
+		txids := make([][]byte, n)
+		for i := range txids {
+			txids[i] = bytes.Repeat([]byte{byte(i + 1)}, 32)
+		}
+		got := computeMerkleRootFromBranches(coinbaseTxid, buildMerkleBranches(txids))
+		want := merkleRootFromTxids(coinbaseTxid, txids)
+		if !bytes.Equal(got, want) {
+			t.Fatalf("%d txs: branch root %x != full tree root %x", n, got, want)
+		}
+	}
+}
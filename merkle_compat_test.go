@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// TestMerkleBranchesCompatWithBtcd checks that folding the coinbase txid
+// through buildMerkleBranches' output, as a miner does for mining.notify,
+// gives the same root btcd computes over the full transaction list.
+func TestMerkleBranchesCompatWithBtcd(t *testing.T) {
+	for n := 0; n <= 12; n++ {
+		t.Run(fmt.Sprintf("%d_txs", n), func(t *testing.T) {
+			// Distinct dummy transactions; the first plays the coinbase.
+			txs := make([]*btcutil.Tx, 0, n+1)
+			for i := 0; i <= n; i++ {
+				msg := wire.NewMsgTx(wire.TxVersion)
+				msg.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: uint32(i)}, nil, nil))
+				msg.AddTxOut(wire.NewTxOut(int64(i+1), []byte{0x51}))
+				txs = append(txs, btcutil.NewTx(msg))
+			}
+			txids := make([][]byte, 0, n)
+			for _, tx := range txs[1:] {
+				txids = append(txids, tx.Hash().CloneBytes())
+			}
+
+			got := computeMerkleRootFromBranches(txs[0].Hash().CloneBytes(), buildMerkleBranches(txids))
+			want := blockchain.CalcMerkleRoot(txs, false)
+			if !bytes.Equal(got, want[:]) {
+				t.Fatalf("branch root %x != btcd root %x", got, want[:])
+			}
+		})
+	}
+}
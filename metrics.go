@@ -18,6 +18,9 @@ const rpcGBTRollingWindowSeconds = 24 * 60 * 60
 const shareRateWindowSeconds = 60
 const startupErrorIgnoreDuration = 2 * time.Minute
 
+// maxSubmitErrorLabels caps distinct submit error reasons kept for /metrics.
+const maxSubmitErrorLabels = 64
+
 type ErrorEvent struct {
 	At      time.Time
 	Type    string
@@ -43,13 +46,22 @@ type PoolMetrics struct {
 
 	mu               sync.RWMutex
 	rejectReasons    map[string]uint64
+	submitErrors     map[string]uint64
 	vardiffUp        uint64
 	vardiffDown      uint64
+	transportUp      uint64
+	transportDown    uint64
+	submitPanics     uint64
 	blockSubAccepted uint64
 	blockSubErrored  uint64
 	rpcErrorCount    uint64
+	tplDecodeErrors  uint64
 	shareErrorCount  uint64
+	nearMisses       uint64
+	lastNearMiss     NearMissShare
 	start            time.Time
+	// Per-listener share counts (tuning [stratum] share_listener_labels).
+	listenerShares map[string]ListenerShareCounts
 
 	errorHistory []ErrorEvent
 
@@ -364,11 +376,18 @@ func (m *PoolMetrics) RecordSubmitError(reason string) {
 	if shouldIgnoreShareErrorDiagnostics(reason) {
 		return
 	}
-	// We still normalize the label so that in-memory statistics remain
-	// consistent even without Prometheus.
-	_ = sanitizeLabel(reason, "unspecified")
+	label := sanitizeLabel(reason, "unspecified")
 	m.mu.Lock()
 	m.shareErrorCount++
+	if m.submitErrors == nil {
+		m.submitErrors = make(map[string]uint64)
+	}
+	// A few reject reasons carry error text; fold anything past the cap
+	// into "other" so /metrics label cardinality stays bounded.
+	if _, ok := m.submitErrors[label]; !ok && len(m.submitErrors) >= maxSubmitErrorLabels {
+		label = "other"
+	}
+	m.submitErrors[label]++
 	m.recordErrorEventLocked("share", reason, time.Now())
 	m.mu.Unlock()
 }
@@ -413,6 +432,29 @@ func (m *PoolMetrics) RecordRPCError(err error) {
 	m.mu.Unlock()
 }
 
+// RecordTemplateDecodeError counts a getblocktemplate result that could not be
+// fully decoded and adds it to the error history.
+func (m *PoolMetrics) RecordTemplateDecodeError(kind, message string, at time.Time) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.tplDecodeErrors++
+	m.recordErrorEventLocked("template_"+kind, message, at)
+	m.mu.Unlock()
+}
+
+// TemplateDecodeErrors returns the number of unusable getblocktemplate results
+// seen since startup.
+func (m *PoolMetrics) TemplateDecodeErrors() uint64 {
+	if m == nil {
+		return 0
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.tplDecodeErrors
+}
+
 func (m *PoolMetrics) RecordErrorEvent(kind, message string, at time.Time) {
 	if m == nil {
 		return
@@ -478,6 +520,52 @@ func (m *PoolMetrics) RecordVardiffMove(direction string) {
 	m.mu.Unlock()
 }
 
+// RecordTransportChange counts a worker reconnecting over a different
+// transport: "upgrade" (TCP to TLS) or "downgrade" (TLS to TCP).
+func (m *PoolMetrics) RecordTransportChange(direction string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	switch direction {
+	case "upgrade":
+		m.transportUp++
+	case "downgrade":
+		m.transportDown++
+	}
+	m.mu.Unlock()
+}
+
+// SnapshotTransportChanges returns the upgrade/downgrade counters.
+func (m *PoolMetrics) SnapshotTransportChanges() (upgrades, downgrades uint64) {
+	if m == nil {
+		return 0, 0
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.transportUp, m.transportDown
+}
+
+// RecordSubmitPanic counts a recovered panic while processing a share.
+func (m *PoolMetrics) RecordSubmitPanic() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.submitPanics++
+	m.mu.Unlock()
+}
+
+// SubmitPanics returns the number of recovered share-processing panics.
+func (m *PoolMetrics) SubmitPanics() uint64 {
+	if m == nil {
+		return 0
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.submitPanics
+}
+
 func (m *PoolMetrics) RecordBlockSubmission(result string) {
 	if m == nil {
 		return
@@ -493,6 +581,30 @@ func (m *PoolMetrics) RecordBlockSubmission(result string) {
 	m.mu.Unlock()
 }
 
+// RecordNearMiss counts an accepted share that came close to the network
+// target without solving a block. Block-solving shares are tracked by
+// RecordBlockSubmission instead.
+func (m *PoolMetrics) RecordNearMiss(share NearMissShare) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.nearMisses++
+	m.lastNearMiss = share
+	m.mu.Unlock()
+}
+
+// SnapshotNearMisses returns the near-miss count and the latest near-miss
+// share (zero Timestamp when none has been seen).
+func (m *PoolMetrics) SnapshotNearMisses() (uint64, NearMissShare) {
+	if m == nil {
+		return 0, NearMissShare{}
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.nearMisses, m.lastNearMiss
+}
+
 func (m *PoolMetrics) Snapshot() (uint64, uint64, map[string]uint64) {
 	if m == nil {
 		return 0, 0, nil
@@ -504,6 +616,57 @@ func (m *PoolMetrics) Snapshot() (uint64, uint64, map[string]uint64) {
 	return m.accepted, m.rejected, reasons
 }
 
+// SnapshotSubmitErrors returns submit error counts by sanitized reason.
+// ListenerShareCounts is the accepted/rejected tally for one Stratum
+// listener label.
+type ListenerShareCounts struct {
+	Accepted uint64 `json:"accepted"`
+	Rejected uint64 `json:"rejected"`
+}
+
+// RecordListenerShare counts a share against the listener it arrived on. A
+// worker connected through several listeners is counted once per share under
+// the listener of the connection that submitted it.
+func (m *PoolMetrics) RecordListenerShare(listener string, accepted bool) {
+	if m == nil || listener == "" {
+		return
+	}
+	m.mu.Lock()
+	if m.listenerShares == nil {
+		m.listenerShares = make(map[string]ListenerShareCounts)
+	}
+	c := m.listenerShares[listener]
+	if accepted {
+		c.Accepted++
+	} else {
+		c.Rejected++
+	}
+	m.listenerShares[listener] = c
+	m.mu.Unlock()
+}
+
+func (m *PoolMetrics) SnapshotListenerShares() map[string]ListenerShareCounts {
+	if m == nil {
+		return nil
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make(map[string]ListenerShareCounts, len(m.listenerShares))
+	maps.Copy(out, m.listenerShares)
+	return out
+}
+
+func (m *PoolMetrics) SnapshotSubmitErrors() map[string]uint64 {
+	if m == nil {
+		return nil
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make(map[string]uint64, len(m.submitErrors))
+	maps.Copy(out, m.submitErrors)
+	return out
+}
+
 // SnapshotDiagnostics returns a compact set of metrics for the server dashboard:
 // vardiff adjustment counts, block submission results, simple RPC latency
 // summaries for getblocktemplate and submitblock, and aggregate error counts.
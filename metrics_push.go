@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	metricsPushFormatInflux = "influx"
+	metricsPushFormatStatsD = "statsd"
+
+	defaultMetricsPushIntervalSeconds = 10
+
+	// metricsPushMaxPacket keeps each UDP datagram under a typical path MTU
+	// so a batch is never fragmented.
+	metricsPushMaxPacket = 1400
+	// metricsPushWarnInterval rate-limits the warning logged while the
+	// endpoint is unreachable.
+	metricsPushWarnInterval = 10 * time.Minute
+	metricsPushWriteTimeout = 100 * time.Millisecond
+)
+
+// metricsPushSample is one interval's reading of the PoolMetrics counters
+// and gauges.
+type metricsPushSample struct {
+	at             time.Time
+	accepted       uint64
+	rejected       uint64
+	blocksAccepted uint64
+	blocksErrored  uint64
+	rpcErrors      uint64
+	submitErrors   map[string]uint64
+	hashrate       float64
+	connected      int
+}
+
+func readMetricsPushSample(m *PoolMetrics, connected int, now time.Time) metricsPushSample {
+	accepted, rejected, _ := m.Snapshot()
+	_, _, blocksAccepted, blocksErrored, _, _, _, _, _, _, rpcErrors, _ := m.SnapshotDiagnostics()
+	return metricsPushSample{
+		at:             now,
+		accepted:       accepted,
+		rejected:       rejected,
+		blocksAccepted: blocksAccepted,
+		blocksErrored:  blocksErrored,
+		rpcErrors:      rpcErrors,
+		submitErrors:   m.SnapshotSubmitErrors(),
+		hashrate:       m.PoolHashrate(),
+		connected:      connected,
+	}
+}
+
+// metricsPushLines renders a sample in the given format. InfluxDB line
+// protocol carries the running totals; StatsD counters carry the increase
+// since prev, which is the zero sample on the first push.
+func metricsPushLines(format string, cur, prev metricsPushSample) []string {
+	reasons := make([]string, 0, len(cur.submitErrors))
+	for reason := range cur.submitErrors {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+
+	if format == metricsPushFormatStatsD {
+		lines := []string{
+			statsdCounter("gopool.shares.accepted", cur.accepted, prev.accepted),
+			statsdCounter("gopool.shares.rejected", cur.rejected, prev.rejected),
+			statsdCounter("gopool.blocks.accepted", cur.blocksAccepted, prev.blocksAccepted),
+			statsdCounter("gopool.blocks.error", cur.blocksErrored, prev.blocksErrored),
+			statsdCounter("gopool.rpc_errors", cur.rpcErrors, prev.rpcErrors),
+			"gopool.hashrate:" + strconv.FormatFloat(cur.hashrate, 'f', -1, 64) + "|g",
+			"gopool.connected_miners:" + strconv.Itoa(cur.connected) + "|g",
+		}
+		for _, reason := range reasons {
+			lines = append(lines, statsdCounter("gopool.submit_errors."+statsdName(reason), cur.submitErrors[reason], prev.submitErrors[reason]))
+		}
+		return lines
+	}
+
+	ts := strconv.FormatInt(cur.at.UnixNano(), 10)
+	lines := []string{
+		"gopool shares_accepted=" + strconv.FormatUint(cur.accepted, 10) + "i" +
+			",shares_rejected=" + strconv.FormatUint(cur.rejected, 10) + "i" +
+			",blocks_accepted=" + strconv.FormatUint(cur.blocksAccepted, 10) + "i" +
+			",blocks_error=" + strconv.FormatUint(cur.blocksErrored, 10) + "i" +
+			",rpc_errors=" + strconv.FormatUint(cur.rpcErrors, 10) + "i" +
+			",hashrate=" + strconv.FormatFloat(cur.hashrate, 'f', -1, 64) +
+			",connected_miners=" + strconv.Itoa(cur.connected) + "i " + ts,
+	}
+	for _, reason := range reasons {
+		lines = append(lines, "gopool_submit_errors,reason="+influxEscapeTag(reason)+
+			" count="+strconv.FormatUint(cur.submitErrors[reason], 10)+"i "+ts)
+	}
+	return lines
+}
+
+func statsdCounter(name string, cur, prev uint64) string {
+	delta := uint64(0)
+	if cur > prev {
+		delta = cur - prev
+	}
+	return name + ":" + strconv.FormatUint(delta, 10) + "|c"
+}
+
+// statsdName keeps a submit error reason usable as a StatsD bucket segment.
+func statsdName(v string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-':
+			return r
+		}
+		return '_'
+	}, v)
+}
+
+func influxEscapeTag(v string) string {
+	if v == "" {
+		return "none"
+	}
+	return strings.NewReplacer(`\`, `\\`, ",", `\,`, "=", `\=`, " ", `\ `, "\n", " ").Replace(v)
+}
+
+// metricsPushPackets joins lines with newlines into datagrams of at most
+// maxLen bytes. A single line longer than maxLen gets a packet of its own.
+func metricsPushPackets(lines []string, maxLen int) [][]byte {
+	var packets [][]byte
+	var cur []byte
+	for _, line := range lines {
+		if len(cur) > 0 && len(cur)+1+len(line) > maxLen {
+			packets = append(packets, cur)
+			cur = nil
+		}
+		if len(cur) > 0 {
+			cur = append(cur, '\n')
+		}
+		cur = append(cur, line...)
+	}
+	if len(cur) > 0 {
+		packets = append(packets, cur)
+	}
+	return packets
+}
+
+// metricsPusher sends batches over UDP. Sends are fire-and-forget: errors
+// (usually ICMP unreachable from an earlier datagram) are counted and
+// logged at most once per metricsPushWarnInterval.
+type metricsPusher struct {
+	addr     string
+	conn     net.Conn
+	failures int
+	lastWarn time.Time
+}
+
+func (p *metricsPusher) send(packets [][]byte, now time.Time) {
+	if p.conn == nil {
+		conn, err := net.Dial("udp", p.addr)
+		if err != nil {
+			p.fail(err, now)
+			return
+		}
+		p.conn = conn
+	}
+	for _, pkt := range packets {
+		_ = p.conn.SetWriteDeadline(now.Add(metricsPushWriteTimeout))
+		if _, err := p.conn.Write(pkt); err != nil {
+			p.fail(err, now)
+			// Re-resolve on the next interval in case the endpoint moved.
+			_ = p.conn.Close()
+			p.conn = nil
+			return
+		}
+	}
+}
+
+func (p *metricsPusher) fail(err error, now time.Time) {
+	p.failures++
+	if !p.lastWarn.IsZero() && now.Sub(p.lastWarn) < metricsPushWarnInterval {
+		return
+	}
+	logger.Warn("metrics push failed", "component", "metrics", "kind", "push", "addr", p.addr, "failures", p.failures, "error", err)
+	p.lastWarn = now
+	p.failures = 0
+}
+
+func (p *metricsPusher) close() {
+	if p.conn != nil {
+		_ = p.conn.Close()
+		p.conn = nil
+	}
+}
+
+// runMetricsPusher periodically sends PoolMetrics to [status].metrics_push_addr
+// when it is set. The address, format and interval are read once at startup.
+func runMetricsPusher(ctx context.Context, s *StatusServer) {
+	if s == nil {
+		return
+	}
+	cfg := s.Config()
+	if !metricsPushConfigured(cfg) {
+		return
+	}
+	interval := time.Duration(cfg.MetricsPushIntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = defaultMetricsPushIntervalSeconds * time.Second
+	}
+	format := cfg.MetricsPushFormat
+	p := &metricsPusher{addr: cfg.MetricsPushAddr}
+	defer p.close()
+	logger.Info("metrics push enabled", "component", "metrics", "kind", "push", "addr", p.addr, "format", format, "interval", interval)
+
+	var prev metricsPushSample
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		connected := 0
+		if s.registry != nil {
+			connected = s.registry.Count()
+		}
+		now := time.Now()
+		cur := readMetricsPushSample(s.metrics, connected, now)
+		p.send(metricsPushPackets(metricsPushLines(format, cur, prev), metricsPushMaxPacket), now)
+		prev = cur
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetricsPushLines(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	prev := metricsPushSample{accepted: 10, rejected: 2, submitErrors: map[string]uint64{"stale": 1}}
+	cur := metricsPushSample{
+		at:           at,
+		accepted:     15,
+		rejected:     2,
+		hashrate:     1.5e12,
+		connected:    3,
+		submitErrors: map[string]uint64{"stale": 4, "low difficulty": 1},
+	}
+
+	influx := strings.Join(metricsPushLines(metricsPushFormatInflux, cur, prev), "\n")
+	for _, want := range []string{
+		"gopool shares_accepted=15i,shares_rejected=2i,",
+		",hashrate=1500000000000,connected_miners=3i 1700000000000000000",
+		`gopool_submit_errors,reason=low\ difficulty count=1i 1700000000000000000`,
+		"gopool_submit_errors,reason=stale count=4i 1700000000000000000",
+	} {
+		if !strings.Contains(influx, want) {
+			t.Fatalf("influx output missing %q:\n%s", want, influx)
+		}
+	}
+
+	statsd := strings.Join(metricsPushLines(metricsPushFormatStatsD, cur, prev), "\n")
+	for _, want := range []string{
+		"gopool.shares.accepted:5|c",
+		"gopool.shares.rejected:0|c",
+		"gopool.hashrate:1500000000000|g",
+		"gopool.connected_miners:3|g",
+		"gopool.submit_errors.low_difficulty:1|c",
+		"gopool.submit_errors.stale:3|c",
+	} {
+		if !strings.Contains(statsd, want) {
+			t.Fatalf("statsd output missing %q:\n%s", want, statsd)
+		}
+	}
+}
+
+func TestMetricsPushPacketsBatch(t *testing.T) {
+	lines := []string{strings.Repeat("a", 40), strings.Repeat("b", 40), strings.Repeat("c", 40), strings.Repeat("d", 120)}
+	packets := metricsPushPackets(lines, 100)
+	if len(packets) != 3 {
+		t.Fatalf("expected 3 packets, got %d", len(packets))
+	}
+	if string(packets[0]) != lines[0]+"\n"+lines[1] {
+		t.Fatalf("first packet should batch two lines, got %q", packets[0])
+	}
+	if string(packets[2]) != lines[3] {
+		t.Fatalf("oversized line should get its own packet")
+	}
+}
+
+func TestMetricsPusherSendsAndSurvivesUnreachable(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	p := &metricsPusher{addr: pc.LocalAddr().String()}
+	defer p.close()
+
+	now := time.Now()
+	p.send([][]byte{[]byte("gopool x=1i")}, now)
+	_ = pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buf := make([]byte, metricsPushMaxPacket)
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if string(buf[:n]) != "gopool x=1i" {
+		t.Fatalf("unexpected payload %q", buf[:n])
+	}
+
+	// With the endpoint gone, sends must return promptly and not panic.
+	_ = pc.Close()
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		p.send([][]byte{[]byte("gopool x=2i")}, now.Add(time.Duration(i)*time.Second))
+	}
+	if time.Since(start) > time.Second {
+		t.Fatalf("sends to an unreachable endpoint blocked")
+	}
+}
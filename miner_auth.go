@@ -17,7 +17,23 @@ func normalizeMinerTypeName(s string) string {
 }
 
 func (mc *MinerConn) minerTypeBanned(minerType, minerName string) bool {
-	if mc == nil || len(mc.cfg.BannedMinerTypes) == 0 {
+	if mc == nil {
+		return false
+	}
+	return minerTypeListed(mc.cfg.BannedMinerTypes, minerType, minerName)
+}
+
+// minerTypeAllowed reports whether the [bans] allowed_miner_types allowlist
+// admits a client; an empty list admits everyone.
+func (mc *MinerConn) minerTypeAllowed(minerType, minerName string) bool {
+	if mc == nil || len(mc.cfg.AllowedMinerTypes) == 0 {
+		return true
+	}
+	return minerTypeListed(mc.cfg.AllowedMinerTypes, minerType, minerName)
+}
+
+func minerTypeListed(list []string, minerType, minerName string) bool {
+	if len(list) == 0 {
 		return false
 	}
 	typeNorm := normalizeMinerTypeName(minerType)
@@ -25,12 +41,12 @@ func (mc *MinerConn) minerTypeBanned(minerType, minerName string) bool {
 	if typeNorm == "" && nameNorm == "" {
 		return false
 	}
-	for _, banned := range mc.cfg.BannedMinerTypes {
-		bannedNorm := normalizeMinerTypeName(banned)
-		if bannedNorm == "" {
+	for _, entry := range list {
+		entryNorm := normalizeMinerTypeName(entry)
+		if entryNorm == "" {
 			continue
 		}
-		if bannedNorm == typeNorm || (nameNorm != "" && bannedNorm == nameNorm) {
+		if entryNorm == typeNorm || (nameNorm != "" && entryNorm == nameNorm) {
 			return true
 		}
 	}
@@ -74,6 +90,17 @@ func (mc *MinerConn) handleSubscribeID(id any, clientID string, haveClientID boo
 		return
 	}
 
+	if !mc.subscribeChallengeSatisfied() {
+		logger.Warn("subscribe rejected: proof-of-work challenge not solved", "component", "miner", "kind", "protocol", "remote", mc.id, "bits", mc.subscribePoWBits)
+		mc.writeResponse(StratumResponse{
+			ID:     id,
+			Result: nil,
+			Error:  newStratumError(stratumErrCodeUnauthorized, "proof-of-work challenge required"),
+		})
+		mc.Close("subscribe challenge not solved")
+		return
+	}
+
 	if haveClientID {
 		// Validate client ID length to prevent abuse
 		if len(clientID) > maxMinerClientIDLen {
@@ -115,6 +142,26 @@ func (mc *MinerConn) handleSubscribeID(id any, clientID string, haveClientID boo
 			}
 		}
 	}
+	if len(mc.cfg.AllowedMinerTypes) > 0 {
+		name, _ := parseMinerID(clientID)
+		if !mc.minerTypeAllowed(clientID, name) {
+			// Logged at warn with the exact ID so operators can grow the
+			// allowlist from the pool log.
+			logger.Warn("subscribe rejected: miner type not in allowlist",
+				"component", "miner", "kind", "ban",
+				"remote", mc.id,
+				"miner_type", clientID,
+				"miner_name", name,
+			)
+			mc.writeResponse(StratumResponse{
+				ID:     id,
+				Result: nil,
+				Error:  newStratumError(stratumErrCodeUnauthorized, "miner type not allowed"),
+			})
+			mc.Close("miner type not allowed")
+			return
+		}
+	}
 
 	// Ensure a stable per-connection session ID is available for the subscribe
 	// response. Some miners send it back as params[1] on reconnect.
@@ -141,7 +188,7 @@ func (mc *MinerConn) handleSubscribeID(id any, clientID string, haveClientID boo
 	//   "extranonce1",
 	//   extranonce2_size
 	// ]
-	ex1 := mc.extranonce1Hex
+	_, ex1 := mc.currentExtranonce1()
 	en2Size := mc.cfg.Extranonce2Size
 	if en2Size <= 0 {
 		en2Size = 4
@@ -182,7 +229,7 @@ func (mc *MinerConn) handleSubscribeID(id any, clientID string, haveClientID boo
 	// unsolicited can confuse miners that don't expect it (e.g., NMAxe/Bitaxe)
 	// since the message arrives while they're still sending authorize/configure
 	// requests and expecting responses to those.
-	if mc.extranonceSubscribed {
+	if mc.extranonceSubscribed.Load() {
 		mc.sendSetExtranonce(ex1, en2Size)
 	}
 	if initialJob == nil {
@@ -217,6 +264,19 @@ func (mc *MinerConn) handleAuthorize(req *StratumRequest) {
 }
 
 func (mc *MinerConn) handleAuthorizeID(id any, workerParam string, pass string) {
+	// The spec orders subscribe before authorize. Refuse the reverse unless
+	// the operator opted in; the connection stays open so the miner can
+	// subscribe and authorize again.
+	if !mc.subscribed && !mc.cfg.AllowAuthorizeBeforeSubscribe {
+		logger.Debug("authorize rejected: not subscribed", "component", "miner", "kind", "protocol", "remote", mc.id)
+		mc.writeResponse(StratumResponse{
+			ID:     id,
+			Result: false,
+			Error:  newStratumError(stratumErrCodeNotSubscribed, "not subscribed"),
+		})
+		return
+	}
+
 	workerClean, usernameDiff, hasUsernameDiff := parseWorkerDifficultyHint(workerParam)
 	worker := strings.TrimSpace(workerClean)
 
@@ -285,7 +345,7 @@ func (mc *MinerConn) handleAuthorizeID(id any, workerParam string, pass string)
 
 	// Before allowing hashing, ensure the worker name is a valid wallet-style
 	// address so we can construct dual-payout coinbases. Invalid workers are
-	// rejected immediately.
+	// rejected immediately unless invalid_wallet_fallback_to_pool is enabled.
 	if workerName != "" {
 		if _, _, ok := mc.ensureWorkerWallet(workerName); !ok {
 			addr := workerBaseAddress(workerName)
@@ -309,6 +369,7 @@ func (mc *MinerConn) handleAuthorizeID(id any, workerParam string, pass string)
 		// Assign a connection sequence before registering so the saved-workers
 		// dashboard can look up active connections via the worker registry.
 		mc.assignConnectionSeq()
+		mc.claimSavedWorkerOwner(workerName, pass)
 		mc.registerWorker(workerName)
 	}
 
@@ -365,8 +426,9 @@ func (mc *MinerConn) handleAuthorizeID(id any, workerParam string, pass string)
 
 	mc.writeTrueResponse(id)
 
-	// If the miner hasn't subscribed yet, accept authorization but don't start
-	// the job listener or send any pool->miner notifications until subscribe.
+	// If the miner hasn't subscribed yet (allow_authorize_before_subscribe),
+	// accept authorization but don't start the job listener or send any
+	// pool->miner notifications until subscribe assigns extranonce1.
 	// Some miners (CKPool-oriented stacks) send authorize/auth before subscribe.
 	if !mc.subscribed {
 		return
@@ -390,6 +452,9 @@ func (mc *MinerConn) handleAuthorizeID(id any, workerParam string, pass string)
 		go mc.listenJobs()
 	}
 
+	if mc.applyDifficultyOverride(mc.cfg, workerName) {
+		hasSuggestedDiff = false
+	}
 	if hasSuggestedDiff {
 		mc.applySuggestedDifficulty(suggestedDiff)
 	}
@@ -426,6 +491,14 @@ func (mc *MinerConn) suggestDifficulty(req *StratumRequest) {
 		return
 	}
 
+	mc.acceptSuggestedDifficulty(req, diff)
+}
+
+// acceptSuggestedDifficulty answers a difficulty hint from
+// mining.suggest_difficulty or mining.suggest_target. A hint outside the pool
+// limits bans the miner when enforce_suggested_difficulty_limits is set;
+// otherwise it is acknowledged and applied (clamped) if it is the first one.
+func (mc *MinerConn) acceptSuggestedDifficulty(req *StratumRequest, diff float64) {
 	min := mc.cfg.MinDifficulty
 	max := mc.cfg.MaxDifficulty
 	if min > 0 && max > 0 && max < min {
@@ -451,12 +524,11 @@ func (mc *MinerConn) suggestDifficulty(req *StratumRequest) {
 	}
 
 	// Always acknowledge the request
-	resp.Result = true
-	mc.writeResponse(resp)
+	mc.writeResponse(StratumResponse{ID: req.ID, Result: true})
 
-	// Only process the first mining.suggest_difficulty during initialization.
-	// Subsequent requests (from miner keepalive/reconnection) are ignored to
-	// prevent disrupting vardiff adjustments and grace period windows.
+	// Only process the first suggestion during initialization. Subsequent
+	// requests (from miner keepalive/reconnection) are ignored to prevent
+	// disrupting vardiff adjustments and grace period windows.
 	mc.applySuggestedDifficulty(diff)
 }
 
@@ -674,6 +746,10 @@ func (mc *MinerConn) applySuggestedDifficulty(diff float64) {
 		logger.Debug("suggest_difficulty ignored (already processed once)", "remote", mc.id)
 		return
 	}
+	if mc.difficultyPinned() {
+		logger.Debug("suggest_difficulty ignored (worker difficulty override)", "remote", mc.id)
+		return
+	}
 	mc.suggestDiffProcessed = true
 
 	// If we just restored a recent difficulty for this worker on a short
@@ -853,6 +929,11 @@ func (mc *MinerConn) suggestTarget(req *StratumRequest) {
 		return
 	}
 
+	if !validTargetHex(targetHex) {
+		resp.Error = newStratumError(stratumErrCodeInvalidRequest, "invalid target")
+		mc.writeResponse(resp)
+		return
+	}
 	diff, ok := difficultyFromTargetHex(targetHex)
 	if !ok || diff < 0 {
 		resp.Error = newStratumError(stratumErrCodeInvalidRequest, "invalid target")
@@ -866,36 +947,7 @@ func (mc *MinerConn) suggestTarget(req *StratumRequest) {
 		return
 	}
 
-	min := mc.cfg.MinDifficulty
-	max := mc.cfg.MaxDifficulty
-	if min > 0 && max > 0 && max < min {
-		max = min
-	}
-	outOfRange := (min > 0 && diff < min) || (max > 0 && diff > max)
-	if outOfRange && mc.cfg.EnforceSuggestedDifficultyLimits {
-		worker := mc.currentWorker()
-		reason := fmt.Sprintf("suggested difficulty %.8g outside pool limits", diff)
-		if min > 0 && diff < min {
-			reason = "Miner too slow"
-		} else if max > 0 && diff > max {
-			reason = "Miner too fast"
-		}
-		mc.banFor(reason, time.Hour, worker)
-		mc.writeResponse(StratumResponse{
-			ID:     req.ID,
-			Result: false,
-			Error:  mc.bannedStratumError(),
-		})
-		mc.Close(reason)
-		return
-	}
-
-	// Always acknowledge the request
-	resp.Result = true
-	mc.writeResponse(resp)
-
-	// Only process the first suggest_target during initialization (same as suggest_difficulty).
-	mc.applySuggestedDifficulty(diff)
+	mc.acceptSuggestedDifficulty(req, diff)
 }
 
 // maybeSendCleanJobAfterSuggest sends a clean notify if initial work was already sent.
@@ -933,6 +985,22 @@ func stratumNotifyJobID(base string, seq uint64) string {
 	return base[:maxJobIDLen-len(suffix)] + suffix
 }
 
+// validTargetHex reports whether targetHex is a full 256-bit target: 64 hex
+// digits, optionally 0x-prefixed.
+func validTargetHex(targetHex string) bool {
+	targetHex = strings.TrimPrefix(strings.TrimPrefix(targetHex, "0x"), "0X")
+	if len(targetHex) != 64 {
+		return false
+	}
+	for i := 0; i < len(targetHex); i++ {
+		c := targetHex[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') && (c < 'A' || c > 'F') {
+			return false
+		}
+	}
+	return true
+}
+
 // difficultyFromTargetHex converts a target hex string to difficulty.
 // difficulty = diff1Target / target
 func difficultyFromTargetHex(targetHex string) (float64, bool) {
@@ -1082,8 +1150,8 @@ func (mc *MinerConn) handleConfigure(req *StratumRequest) {
 			// mining.configure rather than calling mining.extranonce.subscribe.
 			// Treat it as an opt-in for mining.set_extranonce notifications.
 			result[name] = true
-			if !mc.extranonceSubscribed {
-				mc.extranonceSubscribed = true
+			if !mc.extranonceSubscribed.Load() {
+				mc.extranonceSubscribed.Store(true)
 				shouldSendExtranonce = true
 			}
 		default:
@@ -1107,7 +1175,7 @@ func (mc *MinerConn) handleConfigure(req *StratumRequest) {
 		mc.sendVersionMask()
 	}
 	if shouldSendExtranonce {
-		ex1 := mc.extranonce1Hex
+		_, ex1 := mc.currentExtranonce1()
 		en2Size := mc.cfg.Extranonce2Size
 		if en2Size <= 0 {
 			en2Size = 4
@@ -1149,6 +1217,7 @@ func (mc *MinerConn) sendNotifyFor(job *Job, forceClean bool) {
 	stratumJobID := stratumNotifyJobID(job.JobID, seq)
 	uniqueScriptTime := job.ScriptTime + int64(seq)
 	mc.jobScriptTime[stratumJobID] = uniqueScriptTime
+	en1 := mc.extranonce1
 	mc.jobMu.Unlock()
 
 	worker := mc.currentWorker()
@@ -1169,39 +1238,48 @@ func (mc *MinerConn) sendNotifyFor(job *Job, forceClean bool) {
 	)
 	if poolScript, workerScript, totalValue, feePercent, ok := mc.dualPayoutParams(job, worker); ok {
 		logger.Debug("payout check", "donation_percent", job.OperatorDonationPercent, "donation_script_len", len(job.DonationScript))
-		if job.OperatorDonationPercent > 0 && len(job.DonationScript) > 0 {
+		if len(job.PayoutSplits) > 0 {
+			var payouts []coinbasePayoutOutput
+			payouts, err = multiCoinbasePayouts(
+				poolScript,
+				job.DonationScript,
+				workerScript,
+				job.PayoutSplits,
+				totalValue,
+				feePercent,
+				job.OperatorDonationPercent,
+				job.CoinbaseDustThreshold,
+			)
+			if err == nil {
+				coinb1, coinb2, err = job.notifyCoinbaseParts(en1, payouts, uniqueScriptTime)
+			}
+		} else if job.OperatorDonationPercent > 0 && len(job.DonationScript) > 0 {
 			logger.Debug("using triple payout", "worker", worker, "donation_percent", job.OperatorDonationPercent)
-			coinb1, coinb2, err = buildTriplePayoutCoinbaseParts(
-				job.Template.Height,
-				mc.extranonce1,
-				job.Extranonce2Size,
-				job.TemplateExtraNonce2Size,
+			var payouts []coinbasePayoutOutput
+			payouts, err = tripleCoinbasePayouts(
 				poolScript,
 				job.DonationScript,
 				workerScript,
 				totalValue,
 				feePercent,
 				job.OperatorDonationPercent,
-				job.WitnessCommitment,
-				job.Template.CoinbaseAux.Flags,
-				job.CoinbaseMsg,
-				uniqueScriptTime,
+				job.CoinbaseDustThreshold,
 			)
+			if err == nil {
+				coinb1, coinb2, err = job.notifyCoinbaseParts(en1, payouts, uniqueScriptTime)
+			}
 		} else {
-			coinb1, coinb2, err = buildDualPayoutCoinbaseParts(
-				job.Template.Height,
-				mc.extranonce1,
-				job.Extranonce2Size,
-				job.TemplateExtraNonce2Size,
+			var payouts []coinbasePayoutOutput
+			payouts, err = dualCoinbasePayouts(
 				poolScript,
 				workerScript,
 				totalValue,
 				feePercent,
-				job.WitnessCommitment,
-				job.Template.CoinbaseAux.Flags,
-				job.CoinbaseMsg,
-				uniqueScriptTime,
+				job.CoinbaseDustThreshold,
 			)
+			if err == nil {
+				coinb1, coinb2, err = job.notifyCoinbaseParts(en1, payouts, uniqueScriptTime)
+			}
 		}
 	}
 	// Fallback to single-output coinbase if any required dual-payout parameter is missing.
@@ -1213,18 +1291,8 @@ func (mc *MinerConn) sendNotifyFor(job *Job, forceClean bool) {
 				"worker", worker,
 			)
 		}
-		coinb1, coinb2, err = buildCoinbaseParts(
-			job.Template.Height,
-			mc.extranonce1,
-			job.Extranonce2Size,
-			job.TemplateExtraNonce2Size,
-			mc.singlePayoutScript(job, worker),
-			job.CoinbaseValue,
-			job.WitnessCommitment,
-			job.Template.CoinbaseAux.Flags,
-			job.CoinbaseMsg,
-			uniqueScriptTime,
-		)
+		payouts := []coinbasePayoutOutput{{Script: mc.singlePayoutScript(job, worker), Value: job.CoinbaseValue}}
+		coinb1, coinb2, err = job.notifyCoinbaseParts(en1, payouts, uniqueScriptTime)
 	}
 	if err != nil {
 		logger.Error("notify coinbase parts", "component", "miner", "kind", "coinbase", "error", err)
@@ -1234,7 +1302,7 @@ func (mc *MinerConn) sendNotifyFor(job *Job, forceClean bool) {
 	if mc.jobNotifyCoinbase == nil {
 		mc.jobNotifyCoinbase = make(map[string]notifiedCoinbaseParts, mc.maxRecentJobs)
 	}
-	mc.jobNotifyCoinbase[stratumJobID] = notifiedCoinbaseParts{coinb1: coinb1, coinb2: coinb2}
+	mc.jobNotifyCoinbase[stratumJobID] = notifiedCoinbaseParts{coinb1: coinb1, coinb2: coinb2, extranonce1: en1}
 	mc.jobMu.Unlock()
 
 	prevhashLE := hexToLEHex(job.PrevHash)
@@ -1286,6 +1354,7 @@ func (mc *MinerConn) sendNotifyFor(job *Job, forceClean bool) {
 		)
 	}
 
+	mc.rememberNotify(job.JobID, params, time.Now())
 	if err := mc.writeJSON(StratumMessage{
 		ID:     nil,
 		Method: "mining.notify",
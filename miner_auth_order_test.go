@@ -17,7 +17,7 @@ func TestHandleAuthorize_AllowsBeforeSubscribe(t *testing.T) {
 		id:           "auth-before-subscribe",
 		ctx:          context.Background(),
 		conn:         conn,
-		cfg:          Config{ConnectionTimeout: time.Hour},
+		cfg:          Config{ConnectionTimeout: time.Hour, AllowAuthorizeBeforeSubscribe: true},
 		lastActivity: time.Now(),
 	}
 
@@ -43,6 +43,56 @@ func TestHandleAuthorize_AllowsBeforeSubscribe(t *testing.T) {
 	}
 }
 
+func TestHandleAuthorize_RejectsBeforeSubscribeByDefault(t *testing.T) {
+	conn := &writeRecorderConn{}
+	mc := &MinerConn{
+		id:           "auth-before-subscribe-strict",
+		ctx:          context.Background(),
+		conn:         conn,
+		cfg:          Config{ConnectionTimeout: time.Hour},
+		lastActivity: time.Now(),
+	}
+
+	mc.handleAuthorizeID(1, "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa.worker", "")
+
+	if mc.authorized {
+		t.Fatalf("expected authorized=false before subscribe")
+	}
+	if mc.currentWorker() != "" {
+		t.Fatalf("expected no worker to be registered, got %q", mc.currentWorker())
+	}
+	if conn.closed {
+		t.Fatalf("expected connection to remain open")
+	}
+	if out := conn.String(); !strings.Contains(out, "\"result\":false") || !strings.Contains(out, "not subscribed") {
+		t.Fatalf("expected not subscribed error, got: %q", out)
+	}
+}
+
+func TestSubmitBeforeSubscribe_RejectedAfterEarlyAuthorize(t *testing.T) {
+	conn := &writeRecorderConn{}
+	mc := &MinerConn{
+		id:           "auth-before-subscribe-submit",
+		ctx:          context.Background(),
+		conn:         conn,
+		cfg:          Config{ConnectionTimeout: time.Hour, AllowAuthorizeBeforeSubscribe: true},
+		lastActivity: time.Now(),
+	}
+	worker := "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa.worker"
+	mc.handleAuthorizeID(1, worker, "")
+	if !mc.authorized {
+		t.Fatalf("expected authorized=true")
+	}
+
+	params := submitParams{worker: worker, jobID: "1", extranonce2: "00000000", ntime: "00000000", nonce: "00000000"}
+	if _, ok := mc.prepareSubmissionTaskFromParsed(2, params, time.Now()); ok {
+		t.Fatalf("expected submit before subscribe to be rejected")
+	}
+	if out := conn.String(); !strings.Contains(out, "not subscribed") {
+		t.Fatalf("expected not subscribed error, got: %q", out)
+	}
+}
+
 func TestMinerConn_MiningAuthAlias(t *testing.T) {
 	server, client := net.Pipe()
 	defer client.Close()
@@ -52,7 +102,7 @@ func TestMinerConn_MiningAuthAlias(t *testing.T) {
 		ctx:          context.Background(),
 		conn:         server,
 		reader:       bufio.NewReader(server),
-		cfg:          Config{ConnectionTimeout: time.Hour},
+		cfg:          Config{ConnectionTimeout: time.Hour, AllowAuthorizeBeforeSubscribe: true},
 		lastActivity: time.Now(),
 	}
 
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -163,6 +164,42 @@ func TestHandleConfigureSupportsVariantShapes(t *testing.T) {
 	}
 }
 
+func TestHandleConfigureEchoesClampedMinBitCount(t *testing.T) {
+	for _, tc := range []struct {
+		requested string
+		want      int
+	}{
+		{requested: "2", want: 2},
+		{requested: "20", want: 4}, // only four bits in the negotiated mask
+	} {
+		conn := &writeRecorderConn{}
+		mc := &MinerConn{
+			id:       "configure-min-bits",
+			conn:     conn,
+			poolMask: 0x1fffe000,
+		}
+		mc.handleConfigure(&StratumRequest{
+			ID:     1,
+			Method: "mining.configure",
+			Params: []any{
+				[]any{"version-rolling"},
+				map[string]any{
+					"version-rolling.mask":          "0001e000",
+					"version-rolling.min-bit-count": tc.requested,
+				},
+			},
+		})
+
+		if mc.minVerBits != tc.want {
+			t.Fatalf("min-bit-count %s: stored %d, want %d", tc.requested, mc.minVerBits, tc.want)
+		}
+		want := fmt.Sprintf("\"version-rolling.min-bit-count\":%d", tc.want)
+		if out := conn.String(); !strings.Contains(out, want) || !strings.Contains(out, "\"version-rolling.mask\":\"0001e000\"") {
+			t.Fatalf("min-bit-count %s: configure response %q, want %s", tc.requested, out, want)
+		}
+	}
+}
+
 func TestHandleConfigureSubscribeExtranonceSendsSetExtranonce(t *testing.T) {
 	conn := &writeRecorderConn{}
 	mc := &MinerConn{
@@ -180,7 +217,7 @@ func TestHandleConfigureSubscribeExtranonceSendsSetExtranonce(t *testing.T) {
 	}
 	mc.handleConfigure(req)
 
-	if !mc.extranonceSubscribed {
+	if !mc.extranonceSubscribed.Load() {
 		t.Fatalf("expected extranonceSubscribed to be enabled")
 	}
 	out := conn.String()
@@ -207,3 +244,39 @@ func TestSubscribeResponseAdvertisesSetExtranonce(t *testing.T) {
 		t.Fatalf("expected subscribe response to advertise set_extranonce, got: %q", out)
 	}
 }
+
+func TestHandleSubscribeAllowedMinerTypes(t *testing.T) {
+	cases := []struct {
+		clientID     string
+		haveClientID bool
+		allowed      bool
+	}{
+		{"cgminer/4.10.0", true, true},
+		{"BMMiner/2.0.0", true, true},
+		{"NerdMiner", true, false},
+		{"", true, false},
+		{"", false, false},
+	}
+	for _, tc := range cases {
+		conn := &writeRecorderConn{}
+		mc := &MinerConn{
+			id:     "allowlist-miner",
+			conn:   conn,
+			jobMgr: &JobManager{},
+			cfg:    Config{AllowedMinerTypes: []string{"cgminer", "bmminer/2.0.0"}},
+		}
+		mc.handleSubscribeID(1, tc.clientID, tc.haveClientID, "", false)
+		if tc.allowed {
+			if conn.closed || strings.Contains(conn.String(), "miner type not allowed") {
+				t.Fatalf("client %q should be allowed, got %q", tc.clientID, conn.String())
+			}
+			continue
+		}
+		if mc.subscribed || !conn.closed {
+			t.Fatalf("client %q should be refused and disconnected", tc.clientID)
+		}
+		if !strings.Contains(conn.String(), "miner type not allowed") {
+			t.Fatalf("client %q: expected allowlist error, got %q", tc.clientID, conn.String())
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/hex"
 	"errors"
 	"io"
@@ -96,7 +97,7 @@ func encodeBase58Uint64(value uint64) string {
 	return string(buf[i:])
 }
 
-func NewMinerConn(ctx context.Context, c net.Conn, jobMgr *JobManager, rpc rpcCaller, cfg Config, metrics *PoolMetrics, accounting *AccountStore, workerRegistry *workerConnectionRegistry, workerLists *workerListStore, notifier *discordNotifier, isTLS bool) *MinerConn {
+func NewMinerConn(ctx context.Context, c net.Conn, jobMgr *JobManager, rpc rpcCaller, cfg Config, metrics *PoolMetrics, accounting *AccountStore, workerRegistry *workerConnectionRegistry, workerLists *workerListStore, notifier *discordNotifier, listener string) *MinerConn {
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -111,6 +112,7 @@ func NewMinerConn(ctx context.Context, c net.Conn, jobMgr *JobManager, rpc rpcCa
 		maxRecentJobs = defaultRecentJobs
 	}
 
+	isTLS := listener == "tls"
 	mask, minBits := versionRollingPolicyFromConfig(cfg)
 	vdiff := buildVarDiffConfig(cfg)
 
@@ -155,6 +157,9 @@ func NewMinerConn(ctx context.Context, c net.Conn, jobMgr *JobManager, rpc rpcCa
 		jobOrder:          make([]string, 0, maxRecentJobs),
 		connectedAt:       now,
 		lastActivity:      now,
+		lifetimeDeadline:  newConnectionLifetimeDeadline(now, cfg.MaxConnectionLifetime),
+		traceSampled:      traceSampleConnection(),
+		subscribePoWBits:  subscribePoWBitsForListener(cfg, isTLS),
 		jobDifficulty:     make(map[string]float64, maxRecentJobs), // Pre-allocate for expected job count
 		jobScriptTime:     make(map[string]int64, maxRecentJobs),
 		jobNotifyCoinbase: make(map[string]notifiedCoinbaseParts, maxRecentJobs),
@@ -170,6 +175,7 @@ func NewMinerConn(ctx context.Context, c net.Conn, jobMgr *JobManager, rpc rpcCa
 		minVerBits:        minBits,
 		bootstrapDone:     false,
 		isTLSConnection:   isTLS,
+		listener:          listener,
 		statsUpdates:      make(chan statsUpdate, 1000), // Buffered for up to 1000 pending stats updates
 		workerWallets:     make(map[string]workerWalletState, 4),
 	}
@@ -281,6 +287,10 @@ func (mc *MinerConn) handle() {
 	if debugLogging || verboseRuntimeLogging {
 		logger.Info("miner connected", "component", "miner", "kind", "lifecycle", "remote", mc.id, "extranonce1", mc.extranonce1Hex)
 	}
+	if !mc.completeTLSHandshake() {
+		return
+	}
+	mc.sendSubscribeChallenge()
 
 	for {
 		now := time.Now()
@@ -291,8 +301,12 @@ func (mc *MinerConn) handle() {
 			logger.Warn("closing miner for idle timeout", "component", "miner", "kind", "timeout", "remote", mc.id, "reason", reason)
 			return
 		}
+		if mc.maxLifetimeReached(now) {
+			logger.Info("closing miner after max connection lifetime", "component", "miner", "kind", "lifecycle", "remote", mc.id, "session", now.Sub(mc.connectedAt).Round(time.Second))
+			return
+		}
 		mc.maybeSendInitialWorkDue(now)
-		deadline := now.Add(mc.currentReadTimeout())
+		deadline := mc.lifetimeReadDeadline(now.Add(mc.currentReadTimeout()))
 		if err := mc.conn.SetReadDeadline(deadline); err != nil {
 			if mc.ctx.Err() != nil {
 				return
@@ -339,6 +353,7 @@ func (mc *MinerConn) handle() {
 			return
 		}
 		logNetMessage("recv", line)
+		mc.traceJSON("recv", line)
 		line = bytes.TrimSpace(line)
 		if len(line) == 0 {
 			continue
@@ -358,6 +373,11 @@ func (mc *MinerConn) handle() {
 			return
 		}
 
+		// Requests are handled one line at a time in arrival order. Data a
+		// client pipelines before our subscribe response is written stays in
+		// mc.reader until the previous request (including its response) is
+		// done, so authorize/submit always see the state the earlier lines
+		// left behind.
 		var req StratumRequest
 		if err := fastJSONUnmarshal(line, &req); err != nil {
 			if sniffedOK && len(sniffedIDRaw) > 0 {
@@ -376,10 +396,15 @@ func (mc *MinerConn) handle() {
 			}
 			return
 		}
+		if !mc.admitStratumRequestID(line, &req) {
+			continue
+		}
 
 		switch req.Method {
 		case "mining.subscribe":
 			mc.handleSubscribe(&req)
+		case "mining.challenge_response":
+			mc.handleChallengeResponse(&req)
 		case "mining.authorize":
 			mc.handleAuthorize(&req)
 		case "mining.auth":
@@ -439,7 +464,7 @@ func (mc *MinerConn) handle() {
 			//
 			// If there's no ID (or it's null), treat it as a notification and
 			// ignore to preserve compatibility with non-standard extensions.
-			if req.ID != nil {
+			if req.ID != nil && !isStratumNotificationID(req.ID) {
 				mc.writeResponse(StratumResponse{
 					ID:     req.ID,
 					Result: nil,
@@ -477,13 +502,13 @@ func (mc *MinerConn) handleGetTransactions(req *StratumRequest) {
 
 	var job *Job
 	if jobID != "" {
-		j, _, _, _, _, _, ok := mc.jobForIDWithLast(jobID)
+		j, _, _, _, _, _, _, ok := mc.jobForIDWithLast(jobID)
 		if ok {
 			job = j
 		}
 	} else {
 		// No job id provided: use the last job notified to this connection when available.
-		_, last, _, _, _, _, _ := mc.jobForIDWithLast("")
+		_, last, _, _, _, _, _, _ := mc.jobForIDWithLast("")
 		if last != nil {
 			job = last
 		} else if mc.jobMgr != nil {
@@ -577,7 +602,7 @@ func (mc *MinerConn) sendInitialWork() {
 
 	// Respect suggested difficulty if already processed. Otherwise, fall back
 	// to a sane default/minimum so miners have a starting target.
-	if !mc.suggestDiffProcessed && !mc.restoredRecentDiff {
+	if !mc.suggestDiffProcessed && !mc.restoredRecentDiff && !mc.difficultyPinned() {
 		diff := mc.cfg.DefaultDifficulty
 		if diff <= 0 {
 			// Default difficulty of 0 means "unset": treat it as the minimum
@@ -614,11 +639,48 @@ func (mc *MinerConn) currentReadTimeout() time.Duration {
 	mc.statsMu.Unlock()
 
 	if accepted < 3 {
-		return initialReadTimeout
+		return mc.initialReadTimeout()
 	}
 	return base
 }
 
+// initialReadTimeout is the read window before a miner has proven itself,
+// which covers subscribe and authorize. TLS listeners may use a longer one.
+func (mc *MinerConn) initialReadTimeout() time.Duration {
+	if mc.isTLSConnection && mc.cfg.TLSInitialTimeout > 0 {
+		return mc.cfg.TLSInitialTimeout
+	}
+	return initialReadTimeout
+}
+
+// completeTLSHandshake runs the TLS handshake up front under its own deadline
+// so that the subscribe/authorize read window starts once it has finished
+// rather than at accept. Plain connections return immediately.
+func (mc *MinerConn) completeTLSHandshake() bool {
+	tlsConn, ok := mc.conn.(*tls.Conn)
+	if !ok {
+		return true
+	}
+	timeout := mc.initialReadTimeout()
+	ctx, cancel := context.WithTimeout(mc.ctx, timeout)
+	defer cancel()
+	start := time.Now()
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		if mc.cfg.StratumTLSClientCA != "" && isTLSClientCertError(err) {
+			logger.Warn("tls client certificate rejected", "component", "miner", "kind", "tls", "remote", mc.id, "error", err)
+			return false
+		}
+		if mc.ctx.Err() == nil && (debugLogging || verboseRuntimeLogging) {
+			logger.Info("tls handshake failed", "component", "miner", "kind", "tls", "remote", mc.id, "elapsed", time.Since(start).Round(time.Millisecond), "error", err)
+		}
+		return false
+	}
+	if debugLogging || verboseRuntimeLogging {
+		logger.Debug("tls handshake complete", "component", "miner", "kind", "tls", "remote", mc.id, "elapsed", time.Since(start).Round(time.Millisecond))
+	}
+	return true
+}
+
 func (mc *MinerConn) listenJobs() {
 	defer func() {
 		if r := recover(); r != nil {
@@ -629,7 +691,36 @@ func (mc *MinerConn) listenJobs() {
 		}
 	}()
 
-	for job := range mc.jobCh {
-		mc.sendNotifyFor(job, false)
+	interval := mc.cfg.NotifyHeartbeatInterval
+	statsInterval := mc.cfg.StatsNotifyInterval
+	if interval <= 0 && statsInterval <= 0 {
+		for job := range mc.jobCh {
+			mc.sendNotifyFor(job, false)
+		}
+		return
+	}
+	var heartbeatTick, statsTick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(notifyHeartbeatCheckInterval(interval))
+		defer ticker.Stop()
+		heartbeatTick = ticker.C
+	}
+	if statsInterval > 0 {
+		ticker := time.NewTicker(statsInterval)
+		defer ticker.Stop()
+		statsTick = ticker.C
+	}
+	for {
+		select {
+		case job, ok := <-mc.jobCh:
+			if !ok {
+				return
+			}
+			mc.sendNotifyFor(job, false)
+		case now := <-heartbeatTick:
+			mc.sendNotifyHeartbeat(now, interval)
+		case <-statsTick:
+			mc.sendStatsNotify()
+		}
 	}
 }
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// controlHashrate returns the connection's decayed vardiff-control hashrate.
+func (mc *MinerConn) controlHashrate(now time.Time) float64 {
+	mc.statsMu.Lock()
+	defer mc.statsMu.Unlock()
+	control, display := mc.decayedHashratesLocked(now)
+	if control <= 0 {
+		control = display
+	}
+	return control
+}
+
+// workerSyncedDifficulty implements worker_difficulty_sync. It pulls this
+// connection's vardiff suggestion toward the geometric mean of the current
+// difficulty of the worker's other connections, so rigs running under one
+// worker name converge on a shared difficulty over a few retargets. Mixed
+// hardware (hashrates more than workerDiffSyncMaxHashrateRatio apart) keeps
+// independent vardiff, since forcing a small rig up to a large rig's
+// difficulty would starve it of shares.
+func (mc *MinerConn) workerSyncedDifficulty(now time.Time, suggested, hashrate float64) float64 {
+	if mc.workerRegistry == nil || suggested <= 0 || hashrate <= 0 {
+		return suggested
+	}
+	mc.statsMu.Lock()
+	hash := strings.TrimSpace(mc.stats.WorkerSHA256)
+	mc.statsMu.Unlock()
+	if hash == "" {
+		return suggested
+	}
+
+	logSum := math.Log(suggested)
+	n := 1
+	minRate, maxRate := hashrate, hashrate
+	for _, peer := range mc.workerRegistry.getConnectionsByHash(hash) {
+		if peer == nil || peer == mc {
+			continue
+		}
+		diff := atomicLoadFloat64(&peer.difficulty)
+		rate := peer.controlHashrate(now)
+		if diff <= 0 || rate <= 0 {
+			continue
+		}
+		minRate = min(minRate, rate)
+		maxRate = max(maxRate, rate)
+		logSum += math.Log(diff)
+		n++
+	}
+	if n == 1 {
+		return suggested
+	}
+	if maxRate/minRate > workerDiffSyncMaxHashrateRatio {
+		if mc.diffSyncMixedWarned.CompareAndSwap(false, true) {
+			logger.Warn("worker difficulty sync skipped for mixed hardware; connections keep independent vardiff",
+				"component", "miner", "kind", "vardiff",
+				"miner", mc.minerName(""),
+				"connections", n,
+				"min_hashrate", minRate,
+				"max_hashrate", maxRate,
+			)
+		}
+		return suggested
+	}
+
+	granularity := mc.cfg.DifficultyStepGranularity
+	if granularity <= 0 {
+		granularity = defaultDifficultyStepGranularity
+	}
+	return quantizeDifficulty(math.Exp(logSum/float64(n)), 0, 0, granularity)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func newDiffSyncTestConn(reg *workerConnectionRegistry, seq uint64, diff, hashrate float64) *MinerConn {
+	mc := &MinerConn{
+		id:                     "rig",
+		connectionSeq:          seq,
+		workerRegistry:         reg,
+		rollingHashrateControl: hashrate,
+		cfg:                    Config{WorkerDifficultySync: true, DifficultyStepGranularity: 1},
+	}
+	mc.stats.WorkerSHA256 = "worker-hash"
+	atomicStoreFloat64(&mc.difficulty, diff)
+	reg.register("worker-hash", "", mc)
+	return mc
+}
+
+func TestWorkerSyncedDifficultyConvergesSimilarRigs(t *testing.T) {
+	reg := newWorkerConnectionRegistry()
+	mc := newDiffSyncTestConn(reg, 1, 1024, 1e12)
+	newDiffSyncTestConn(reg, 2, 4096, 2e12)
+
+	if got := mc.workerSyncedDifficulty(time.Now(), 1024, 1e12); got != 2048 {
+		t.Fatalf("expected geometric mean 2048, got %v", got)
+	}
+}
+
+func TestWorkerSyncedDifficultySkipsMixedHardware(t *testing.T) {
+	reg := newWorkerConnectionRegistry()
+	mc := newDiffSyncTestConn(reg, 1, 1024, 1e12)
+	newDiffSyncTestConn(reg, 2, 65536, 50e12)
+
+	if got := mc.workerSyncedDifficulty(time.Now(), 1024, 1e12); got != 1024 {
+		t.Fatalf("expected independent vardiff for mixed hardware, got %v", got)
+	}
+	if !mc.diffSyncMixedWarned.Load() {
+		t.Fatalf("expected a mixed-hardware warning")
+	}
+}
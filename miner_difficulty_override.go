@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// maxWorkerDifficultyOverrides bounds tuning [difficulty].worker_overrides;
+// the table is scanned on every authorize.
+const maxWorkerDifficultyOverrides = 1024
+
+// normalizeWorkerDifficultyOverrides lower-cases and trims override keys so
+// lookups are case-insensitive. Keys that collide after normalization keep
+// the last value seen.
+func normalizeWorkerDifficultyOverrides(in map[string]float64) map[string]float64 {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make(map[string]float64, len(in))
+	for pattern, diff := range in {
+		out[strings.ToLower(strings.TrimSpace(pattern))] = diff
+	}
+	return out
+}
+
+func validateWorkerDifficultyOverrides(overrides map[string]float64) error {
+	if len(overrides) > maxWorkerDifficultyOverrides {
+		return fmt.Errorf("worker_overrides has %d entries (max %d)", len(overrides), maxWorkerDifficultyOverrides)
+	}
+	for pattern, diff := range overrides {
+		if pattern == "" || pattern == "*" {
+			return fmt.Errorf("worker_overrides: pattern %q must name a worker or a worker prefix", pattern)
+		}
+		if strings.Contains(strings.TrimSuffix(pattern, "*"), "*") {
+			return fmt.Errorf("worker_overrides: pattern %q may only use * as its last character", pattern)
+		}
+		if math.IsNaN(diff) || math.IsInf(diff, 0) || diff < minShareDifficulty {
+			return fmt.Errorf("worker_overrides: difficulty for %q must be a finite value >= %g, got %v", pattern, minShareDifficulty, diff)
+		}
+	}
+	return nil
+}
+
+// workerDifficultyOverride finds the pinned difficulty for worker. Patterns
+// are matched case-insensitively against the full authorized name
+// ("wallet.rig1") and against the part after the wallet ("rig1"). An exact
+// pattern beats a trailing-wildcard one ("rig1.*"); among wildcards the
+// longest prefix wins.
+func workerDifficultyOverride(overrides map[string]float64, worker string) (float64, bool) {
+	if len(overrides) == 0 {
+		return 0, false
+	}
+	name := strings.ToLower(strings.TrimSpace(worker))
+	if name == "" {
+		return 0, false
+	}
+	candidates := []string{name}
+	if _, suffix, ok := strings.Cut(name, "."); ok && suffix != "" {
+		candidates = append(candidates, suffix)
+	}
+	for _, c := range candidates {
+		if diff, ok := overrides[c]; ok {
+			return diff, true
+		}
+	}
+	bestLen := -1
+	var best float64
+	for pattern, diff := range overrides {
+		prefix, ok := strings.CutSuffix(pattern, "*")
+		if !ok || len(prefix) <= bestLen {
+			continue
+		}
+		for _, c := range candidates {
+			if strings.HasPrefix(c, prefix) {
+				bestLen, best = len(prefix), diff
+				break
+			}
+		}
+	}
+	return best, bestLen >= 0
+}
+
+// applyDifficultyOverride pins this connection to worker's override (within
+// min/max) and excludes it from vardiff, or releases a previous pin when no
+// override matches any more. An admin worker_overrides entry wins over a
+// saved-worker static difficulty. It reports whether the connection is pinned.
+func (mc *MinerConn) applyDifficultyOverride(cfg Config, worker string) bool {
+	diff, ok := workerDifficultyOverride(cfg.WorkerDifficultyOverrides, worker)
+	if !ok && cfg.SavedWorkerStaticDifficulty {
+		if saved := atomicLoadFloat64(&mc.savedStaticDiff); saved > 0 {
+			diff, ok = saved, true
+		}
+	}
+	prev := atomicLoadFloat64(&mc.difficultyOverride)
+	if !ok {
+		if prev > 0 {
+			atomicStoreFloat64(&mc.difficultyOverride, 0)
+			logger.Info("worker difficulty override released", "component", "miner", "kind", "difficulty", "remote", mc.id, "worker", worker)
+		}
+		return false
+	}
+	atomicStoreFloat64(&mc.difficultyOverride, diff)
+	if prev != diff {
+		logger.Info("worker difficulty override applied", "component", "miner", "kind", "difficulty", "remote", mc.id, "worker", worker, "difficulty", diff)
+		mc.setDifficulty(diff)
+	}
+	return true
+}
+
+// difficultyPinned reports whether a worker override excludes this
+// connection from vardiff and suggested difficulty.
+func (mc *MinerConn) difficultyPinned() bool {
+	return atomicLoadFloat64(&mc.difficultyOverride) > 0
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"maps"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWorkerDifficultyOverrideMatching(t *testing.T) {
+	overrides := normalizeWorkerDifficultyOverrides(map[string]float64{
+		"Rig1.*":                                 1000,
+		"rig1.big*":                              2000,
+		"1BitcoinEaterAddressDontSendf59kuE.S19": 3000,
+		"antminer":                               4000,
+	})
+	tests := []struct {
+		worker string
+		want   float64
+		ok     bool
+	}{
+		{worker: "rig1.a", want: 1000, ok: true},
+		{worker: "RIG1.BigOne", want: 2000, ok: true},
+		{worker: "1bitcoineateraddressdontsendf59kue.s19", want: 3000, ok: true},
+		{worker: "1BitcoinEaterAddressDontSendf59kuE.antminer", want: 4000, ok: true},
+		{worker: "1BitcoinEaterAddressDontSendf59kuE.rig1.x", want: 1000, ok: true},
+		{worker: "rig2.a", ok: false},
+		{worker: "", ok: false},
+	}
+	for _, tc := range tests {
+		got, ok := workerDifficultyOverride(overrides, tc.worker)
+		if ok != tc.ok || got != tc.want {
+			t.Fatalf("workerDifficultyOverride(%q) = %v,%v want %v,%v", tc.worker, got, ok, tc.want, tc.ok)
+		}
+	}
+
+	for _, bad := range []map[string]float64{
+		{"*": 1000},
+		{"rig*1": 1000},
+		{"rig1": 0},
+	} {
+		if err := validateWorkerDifficultyOverrides(bad); err == nil {
+			t.Fatalf("expected %v to be rejected", bad)
+		}
+	}
+}
+
+func TestApplyDifficultyOverridePinsAndReleases(t *testing.T) {
+	mc := &MinerConn{cfg: Config{VarDiffEnabled: true, TargetSharesPerMin: 5, DifficultyStepGranularity: 1}}
+	overrides := map[string]float64{"rig1.*": 4096}
+
+	if !mc.applyDifficultyOverride(Config{WorkerDifficultyOverrides: overrides}, "rig1.a") {
+		t.Fatalf("expected rig1.a to be pinned")
+	}
+	if got := atomicLoadFloat64(&mc.difficulty); got != 4096 {
+		t.Fatalf("pinned difficulty = %v, want 4096", got)
+	}
+	if mc.maybeAdjustDifficulty(time.Now()) {
+		t.Fatalf("vardiff must not adjust a pinned connection")
+	}
+	mc.applySuggestedDifficulty(1)
+	if got := atomicLoadFloat64(&mc.difficulty); got != 4096 {
+		t.Fatalf("suggest_difficulty changed a pinned connection to %v", got)
+	}
+
+	if mc.applyDifficultyOverride(Config{}, "rig1.a") || mc.difficultyPinned() {
+		t.Fatalf("expected the pin to be released once the override is removed")
+	}
+}
+
+func TestWorkerDifficultyOverridesRoundTripTuningFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tuning.toml")
+	cfg := defaultConfig()
+	cfg.WorkerDifficultyOverrides = map[string]float64{"rig1.*": 65536, "1bitcoineateraddressdontsendf59kue.s19": 131072}
+	if err := rewriteTuningFile(path, cfg); err != nil {
+		t.Fatalf("rewriteTuningFile: %v", err)
+	}
+	fc, ok, err := loadTuningFile(path)
+	if err != nil || !ok {
+		t.Fatalf("loadTuningFile: ok=%v err=%v", ok, err)
+	}
+	var loaded Config
+	applyTuningConfig(&loaded, *fc)
+	if !maps.Equal(loaded.WorkerDifficultyOverrides, cfg.WorkerDifficultyOverrides) {
+		t.Fatalf("round-trip overrides = %v, want %v", loaded.WorkerDifficultyOverrides, cfg.WorkerDifficultyOverrides)
+	}
+}
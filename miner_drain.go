@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// defaultDrainPercent and defaultDrainInterval pace /admin/drain when the
+	// request does not say otherwise: 10% of the miners every 5 seconds.
+	defaultDrainPercent  = 10.0
+	defaultDrainInterval = 5 * time.Second
+	maxDrainInterval     = 5 * time.Minute
+	drainShowMessage     = "Pool restarting; please reconnect."
+)
+
+// stratumDrainer moves miners off the pool a batch at a time ahead of a
+// rolling restart. While active, new stratum connections are refused but the
+// listeners stay open; canceling resumes normal accepts.
+type stratumDrainer struct {
+	mu       sync.Mutex
+	active   atomic.Bool
+	parent   context.Context
+	cancel   context.CancelFunc
+	percent  float64
+	interval time.Duration
+}
+
+var stratumDrain stratumDrainer
+
+// Active reports whether a drain is in progress (or finished but not yet
+// canceled), i.e. whether new connections should be refused.
+func (d *stratumDrainer) Active() bool { return d.active.Load() }
+
+// Start begins draining registry. It returns false when a drain is already
+// running.
+func (d *stratumDrainer) Start(ctx context.Context, registry *MinerRegistry, percent float64, interval time.Duration) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.active.Load() {
+		return false
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	d.parent = ctx
+	d.cancel = cancel
+	d.percent = percent
+	d.interval = interval
+	d.active.Store(true)
+	setPoolDraining(true)
+	go d.run(runCtx, registry, percent, interval)
+	return true
+}
+
+// Cancel stops an active drain and lets miners connect again. It returns
+// false when no drain was active.
+func (d *stratumDrainer) Cancel() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.active.Load() {
+		return false
+	}
+	d.cancel()
+	d.active.Store(false)
+	// A shutdown that started meanwhile owns the draining flag from here on.
+	if d.parent.Err() == nil {
+		setPoolDraining(false)
+	}
+	logger.Info("stratum drain canceled", "component", "stratum", "kind", "drain")
+	return true
+}
+
+// Settings returns the pace of the current (or last) drain.
+func (d *stratumDrainer) Settings() (percent float64, interval time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.percent, d.interval
+}
+
+func (d *stratumDrainer) run(ctx context.Context, registry *MinerRegistry, percent float64, interval time.Duration) {
+	total := registry.Count()
+	batch := drainBatchSize(total, percent)
+	logger.Info("stratum drain started", "component", "stratum", "kind", "drain",
+		"miners", total, "batch", batch, "interval", interval)
+
+	asked := make(map[*MinerConn]time.Time)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		conns := registry.Snapshot()
+		if len(conns) == 0 {
+			logger.Info("stratum drain complete; new connections still refused until canceled",
+				"component", "stratum", "kind", "drain", "miners", total)
+			return
+		}
+		sent, closed := drainStep(conns, asked, batch, time.Now())
+		logger.Info("stratum drain progress", "component", "stratum", "kind", "drain",
+			"remaining", len(conns), "asked", sent, "closed", closed, "pending", len(asked))
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drainBatchSize is how many miners each drain step asks to reconnect:
+// percent of the miners connected when the drain started, at least one.
+func drainBatchSize(total int, percent float64) int {
+	return max(int(math.Ceil(float64(total)*percent/100)), 1)
+}
+
+// drainStep asks up to batch miners in conns that have not been asked yet to
+// reconnect, and closes miners that ignored an earlier request for longer
+// than the reconnect grace period. asked records when each miner was asked
+// and is pruned of miners that have already left.
+func drainStep(conns []*MinerConn, asked map[*MinerConn]time.Time, batch int, now time.Time) (sent, closed int) {
+	present := make(map[*MinerConn]struct{}, len(conns))
+	for _, mc := range conns {
+		present[mc] = struct{}{}
+		if at, ok := asked[mc]; ok {
+			if now.Sub(at) >= maxConnectionLifetimeGrace {
+				mc.Close("drain")
+				delete(asked, mc)
+				closed++
+			}
+			continue
+		}
+		if sent >= batch {
+			continue
+		}
+		mc.sendClientShowMessage(drainShowMessage)
+		mc.sendClientReconnect("admin drain")
+		asked[mc] = now
+		sent++
+	}
+	for mc := range asked {
+		if _, ok := present[mc]; !ok {
+			delete(asked, mc)
+		}
+	}
+	return sent, closed
+}
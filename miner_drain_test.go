@@ -0,0 +1,75 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDrainBatchSize(t *testing.T) {
+	for _, tc := range []struct {
+		total   int
+		percent float64
+		want    int
+	}{
+		{0, 10, 1},
+		{5, 10, 1},
+		{100, 10, 10},
+		{101, 10, 11},
+		{7, 100, 7},
+	} {
+		if got := drainBatchSize(tc.total, tc.percent); got != tc.want {
+			t.Fatalf("drainBatchSize(%d, %v) = %d, want %d", tc.total, tc.percent, got, tc.want)
+		}
+	}
+}
+
+func TestDrainStepPacesAndClosesStragglers(t *testing.T) {
+	var conns []*MinerConn
+	var recs []*writeRecorderConn
+	for range 5 {
+		rec := &writeRecorderConn{}
+		recs = append(recs, rec)
+		conns = append(conns, &MinerConn{id: "drain-miner", conn: rec})
+	}
+	asked := make(map[*MinerConn]time.Time)
+	now := time.Now()
+
+	if sent, closed := drainStep(conns, asked, 2, now); sent != 2 || closed != 0 {
+		t.Fatalf("first step: sent=%d closed=%d, want 2/0", sent, closed)
+	}
+	reconnects := 0
+	for _, rec := range recs {
+		if strings.Contains(rec.String(), "client.reconnect") {
+			if !strings.Contains(rec.String(), "client.show_message") {
+				t.Fatalf("expected a show_message before client.reconnect, got %q", rec.String())
+			}
+			reconnects++
+		}
+	}
+	if reconnects != 2 {
+		t.Fatalf("expected 2 miners asked to reconnect, got %d", reconnects)
+	}
+
+	// Miners already asked are not asked again; the next batch moves on.
+	if sent, _ := drainStep(conns, asked, 2, now.Add(time.Second)); sent != 2 {
+		t.Fatalf("second step: sent=%d, want 2", sent)
+	}
+
+	// Miners that ignore the request are closed after the grace period, and
+	// miners that left on their own are forgotten.
+	var remaining []*MinerConn
+	for _, mc := range conns {
+		if at, ok := asked[mc]; ok && at.Equal(now) {
+			remaining = append(remaining, mc)
+		}
+	}
+	remaining = append(remaining, conns[4])
+	sent, closed := drainStep(remaining, asked, 2, now.Add(maxConnectionLifetimeGrace))
+	if sent != 1 || closed != 2 {
+		t.Fatalf("third step: sent=%d closed=%d, want 1/2", sent, closed)
+	}
+	if len(asked) != 1 {
+		t.Fatalf("expected only the newly asked miner to be tracked, got %d", len(asked))
+	}
+}
@@ -126,9 +126,10 @@ func (s *duplicateShareSet) seenOrAdd(key duplicateShareKey) bool {
 		return true
 	}
 
-	// Evict oldest 10% when at capacity (keeps 90% of recent history)
-	if len(s.order) >= duplicateShareHistory {
-		evictCount := max(duplicateShareHistory/10, 1)
+	// Evict oldest 10% when at capacity (keeps 90% of recent history). Under
+	// memory pressure the limit shrinks and the overflow goes at once.
+	if limit := duplicateShareLimit(); len(s.order) >= limit {
+		evictCount := max(len(s.order)-limit+limit/10, 1)
 		for i := 0; i < evictCount; i++ {
 			delete(s.m, s.order[i])
 		}
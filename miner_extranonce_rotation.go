@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// extranonceRotationGrace is how long shares for jobs notified before an
+// extranonce1 rotation are still accepted (validated against the old
+// extranonce1) so in-flight work is not rejected.
+const extranonceRotationGrace = 30 * time.Second
+
+var (
+	errExtranonceNotSubscribed = errors.New("miner did not subscribe to extranonce updates")
+	errExtranonceNotReady      = errors.New("miner is not subscribed yet")
+)
+
+// currentExtranonce1 returns the extranonce1 new work is built with.
+func (mc *MinerConn) currentExtranonce1() ([]byte, string) {
+	mc.jobMu.Lock()
+	defer mc.jobMu.Unlock()
+	return mc.extranonce1, mc.extranonce1Hex
+}
+
+// extranonce1ForJobLocked returns the extranonce1 the given stratum job was
+// notified with, falling back to the current one. Caller holds jobMu.
+func (mc *MinerConn) extranonce1ForJobLocked(jobID string) []byte {
+	if parts, ok := mc.jobNotifyCoinbase[jobID]; ok && parts.extranonce1 != nil {
+		return parts.extranonce1
+	}
+	return mc.extranonce1
+}
+
+func (mc *MinerConn) extranonce1ForJob(jobID string) []byte {
+	mc.jobMu.Lock()
+	defer mc.jobMu.Unlock()
+	return mc.extranonce1ForJobLocked(jobID)
+}
+
+// rotateExtranonce1 assigns the connection a fresh extranonce1, announces it
+// with mining.set_extranonce and sends a clean job built with it. Only miners
+// that opted in via mining.extranonce.subscribe (or the configure
+// subscribe-extranonce extension) are rotated; others would keep hashing with
+// the old prefix. Jobs notified before the rotation keep validating against
+// the old extranonce1 for extranonceRotationGrace.
+func (mc *MinerConn) rotateExtranonce1(now time.Time) (string, error) {
+	if !mc.extranonceSubscribed.Load() {
+		return "", errExtranonceNotSubscribed
+	}
+	if !mc.subscribed || mc.jobMgr == nil {
+		return "", errExtranonceNotReady
+	}
+	en1 := mc.jobMgr.NextExtranonce1()
+	ex1 := hex.EncodeToString(en1)
+
+	mc.jobMu.Lock()
+	old := mc.extranonce1Hex
+	mc.extranonce1 = en1
+	mc.extranonce1Hex = ex1
+	mc.extranonceRotatedAt = now
+	mc.jobMu.Unlock()
+
+	en2Size := mc.cfg.Extranonce2Size
+	if en2Size <= 0 {
+		en2Size = 4
+	}
+	mc.sendSetExtranonce(ex1, en2Size)
+	// set_extranonce takes effect with the next notify; send one right away.
+	mc.maybeSendCleanJobAfterSuggest()
+	logger.Info("extranonce1 rotated", "component", "miner", "kind", "extranonce",
+		"remote", mc.id, "old", old, "new", ex1)
+	return ex1, nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotateExtranonce1RequiresSubscription(t *testing.T) {
+	mc, conn := minerConnForNotifyTest(t)
+	mc.jobMgr = &JobManager{}
+
+	if _, err := mc.rotateExtranonce1(time.Now()); !errors.Is(err, errExtranonceNotSubscribed) {
+		t.Fatalf("expected errExtranonceNotSubscribed, got %v", err)
+	}
+	if strings.Contains(conn.String(), "mining.set_extranonce") {
+		t.Fatalf("unexpected set_extranonce sent to unsubscribed miner: %q", conn.String())
+	}
+}
+
+func TestRotateExtranonce1KeepsOldJobsDuringGrace(t *testing.T) {
+	mc, conn := minerConnForNotifyTest(t)
+	mc.jobMgr = &JobManager{}
+	mc.extranonceSubscribed.Store(true)
+	oldEn1 := append([]byte(nil), mc.extranonce1...)
+
+	job := benchmarkSubmitJobForTest(t)
+	job.ScriptTime = job.Template.CurTime
+	mc.sendNotifyFor(job, true)
+
+	now := time.Now()
+	ex1, err := mc.rotateExtranonce1(now)
+	if err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	if !strings.Contains(conn.String(), `"mining.set_extranonce"`) || !strings.Contains(conn.String(), ex1) {
+		t.Fatalf("expected set_extranonce with %q, got %q", ex1, conn.String())
+	}
+	if en1, hexEn1 := mc.currentExtranonce1(); hexEn1 != ex1 || bytes.Equal(en1, oldEn1) {
+		t.Fatalf("extranonce1 not rotated: %x (%s)", en1, hexEn1)
+	}
+
+	mc.sendNotifyFor(job, true)
+	ids := notifyJobIDsFromOutput(t, conn.String())
+	if len(ids) != 2 {
+		t.Fatalf("expected two notify job ids, got %#v", ids)
+	}
+
+	_, _, _, _, _, _, en1, ok := mc.jobForIDWithLast(ids[0])
+	if !ok || !bytes.Equal(en1, oldEn1) {
+		t.Fatalf("pre-rotation job: ok=%v en1=%x, want old %x", ok, en1, oldEn1)
+	}
+	_, _, _, _, _, _, en1, ok = mc.jobForIDWithLast(ids[1])
+	if !ok || bytes.Equal(en1, oldEn1) {
+		t.Fatalf("post-rotation job: ok=%v en1=%x, want new extranonce1", ok, en1)
+	}
+
+	mc.jobMu.Lock()
+	mc.extranonceRotatedAt = now.Add(-extranonceRotationGrace - time.Second)
+	mc.jobMu.Unlock()
+	if _, _, _, _, _, _, _, ok := mc.jobForIDWithLast(ids[0]); ok {
+		t.Fatalf("expected pre-rotation job to expire after the grace window")
+	}
+	if _, _, _, _, _, _, _, ok := mc.jobForIDWithLast(ids[1]); !ok {
+		t.Fatalf("expected post-rotation job to stay valid")
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// shareFloodWindow is the sampling window used to measure a connection's
+	// raw submit rate for flood protection.
+	shareFloodWindow = 15 * time.Second
+	// shareFloodMinElapsed keeps the rate estimate sane when a window trips
+	// its budget within a few milliseconds.
+	shareFloodMinElapsed = time.Second
+	// shareFloodMaxRaiseFactor bounds a single floor raise so one noisy window
+	// cannot push a connection to max_difficulty outright.
+	shareFloodMaxRaiseFactor = 1024.0
+)
+
+// noteSubmitForFloodGuard counts a submit that is about to be handed to the
+// submission workers and, when the connection's submit rate exceeds
+// share_flood_shares_per_min, raises a temporary difficulty floor sized to
+// bring it back to the vardiff target. This is separate from vardiff: it
+// reacts within one sampling window and also applies to connections with a
+// locked difficulty. Connections whose difficulty already yields a sane share
+// rate never reach the threshold. Runs on the connection goroutine.
+func (mc *MinerConn) noteSubmitForFloodGuard(now time.Time) {
+	threshold := mc.cfg.ShareFloodSharesPerMin
+	if threshold <= 0 {
+		return
+	}
+	if mc.floodWindowStart.IsZero() || now.Before(mc.floodWindowStart) {
+		mc.floodWindowStart = now
+		mc.floodWindowCount = 0
+	}
+	mc.floodWindowCount++
+
+	elapsed := now.Sub(mc.floodWindowStart)
+	budget := threshold * shareFloodWindow.Minutes()
+	windowDone := elapsed >= shareFloodWindow
+	if !windowDone && float64(mc.floodWindowCount) <= budget {
+		return
+	}
+	if elapsed < shareFloodMinElapsed {
+		elapsed = shareFloodMinElapsed
+	}
+	ratePerMin := float64(mc.floodWindowCount) / elapsed.Minutes()
+	mc.floodWindowStart = now
+	mc.floodWindowCount = 0
+
+	if ratePerMin <= threshold {
+		if mc.shareFloodFloor(now) == 0 && atomicLoadFloat64(&mc.floodMinDifficulty) > 0 {
+			atomicStoreFloat64(&mc.floodMinDifficulty, 0)
+			logger.Info("share flood subsided; difficulty floor released",
+				"component", "miner", "kind", "share_flood",
+				"miner", mc.minerName(""),
+				"shares_per_min", ratePerMin,
+			)
+		}
+		return
+	}
+	mc.raiseShareFloodFloor(now, ratePerMin)
+}
+
+func (mc *MinerConn) raiseShareFloodFloor(now time.Time, ratePerMin float64) {
+	target := mc.vardiff.TargetSharesPerMin
+	if target <= 0 {
+		target = mc.cfg.TargetSharesPerMin
+	}
+	if target <= 0 {
+		target = defaultVarDiffTargetSharesPerMin
+	}
+	factor := ratePerMin / target
+	if factor > shareFloodMaxRaiseFactor {
+		factor = shareFloodMaxRaiseFactor
+	}
+	currentDiff := atomicLoadFloat64(&mc.difficulty)
+	floor := currentDiff * factor
+	if prev := atomicLoadFloat64(&mc.floodMinDifficulty); prev > floor {
+		floor = prev
+	}
+
+	hold := mc.cfg.ShareFloodHold
+	if hold <= 0 {
+		hold = defaultShareFloodHold
+	}
+	mc.floodFloorUntil.Store(now.Add(hold).UnixNano())
+	if floor <= currentDiff {
+		// Already at (or above) the floor, typically because max_difficulty
+		// caps it; keep the hold extended but don't resend the same diff.
+		return
+	}
+	atomicStoreFloat64(&mc.floodMinDifficulty, floor)
+	logger.Warn("share flood detected; raising difficulty floor",
+		"component", "miner", "kind", "share_flood",
+		"miner", mc.minerName(""),
+		"remote", mc.id,
+		"shares_per_min", ratePerMin,
+		"threshold", mc.cfg.ShareFloodSharesPerMin,
+		"old_diff", currentDiff,
+		"floor", floor,
+		"hold", hold.String(),
+	)
+	if mc.metrics != nil {
+		mc.metrics.RecordErrorEvent("share_flood", fmt.Sprintf("%s: %.0f shares/min, diff floor %.8g", mc.minerName(mc.id), ratePerMin, floor), now)
+	}
+	mc.setDifficulty(floor)
+	mc.resetShareWindow(now)
+	mc.resetVardiffPending()
+}
+
+// shareFloodFloor returns the active flood difficulty floor, or 0 once its
+// hold has expired so vardiff is free to lower difficulty again.
+func (mc *MinerConn) shareFloodFloor(now time.Time) float64 {
+	floor := atomicLoadFloat64(&mc.floodMinDifficulty)
+	if floor <= 0 {
+		return 0
+	}
+	if until := mc.floodFloorUntil.Load(); until == 0 || now.UnixNano() >= until {
+		return 0
+	}
+	return floor
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func newFloodTestConn(threshold float64) *MinerConn {
+	mc := &MinerConn{
+		cfg: Config{
+			ShareFloodSharesPerMin: threshold,
+			ShareFloodHold:         time.Minute,
+			MaxDifficulty:          1 << 20,
+		},
+		vardiff: VarDiffConfig{
+			MinDiff:            1,
+			MaxDiff:            1 << 20,
+			TargetSharesPerMin: 10,
+		},
+	}
+	atomicStoreFloat64(&mc.difficulty, 1)
+	return mc
+}
+
+// submitAtRate feeds n submits spaced evenly at ratePerMin starting at start
+// and returns the time of the last submit.
+func submitAtRate(mc *MinerConn, start time.Time, ratePerMin float64, n int) time.Time {
+	step := time.Duration(float64(time.Minute) / ratePerMin)
+	now := start
+	for i := 0; i < n; i++ {
+		now = start.Add(time.Duration(i) * step)
+		mc.noteSubmitForFloodGuard(now)
+	}
+	return now
+}
+
+func TestShareFloodRaisesFloorAndRecovers(t *testing.T) {
+	mc := newFloodTestConn(600)
+	start := time.Now()
+
+	// 6000 shares/min for one window: 10x over threshold, 600x over target.
+	last := submitAtRate(mc, start, 6000, 200)
+	floor := mc.shareFloodFloor(last)
+	if floor <= 1 {
+		t.Fatalf("expected a flood floor above 1, got %v", floor)
+	}
+	if diff := atomicLoadFloat64(&mc.difficulty); diff < floor*0.9 {
+		t.Fatalf("difficulty %v not raised to floor %v", diff, floor)
+	}
+	if got := mc.clampDifficulty(1); got < floor*0.9 {
+		t.Fatalf("clampDifficulty(1) = %v, want >= floor %v", got, floor)
+	}
+
+	// Flood stops: after the hold expires a quiet window releases the floor.
+	quiet := last.Add(2 * time.Minute)
+	submitAtRate(mc, quiet, 5, 3)
+	if got := mc.shareFloodFloor(quiet); got != 0 {
+		t.Fatalf("floor still active after hold: %v", got)
+	}
+	if got := atomicLoadFloat64(&mc.floodMinDifficulty); got != 0 {
+		t.Fatalf("floor not cleared after quiet window: %v", got)
+	}
+	if got := mc.clampDifficulty(1); got != 1 {
+		t.Fatalf("clampDifficulty(1) after recovery = %v, want 1", got)
+	}
+}
+
+func TestShareFloodIgnoresRatesBelowThreshold(t *testing.T) {
+	// A fast miner whose difficulty already keeps it near the vardiff target
+	// must never be touched, no matter how much hashrate is behind it.
+	mc := newFloodTestConn(600)
+	atomicStoreFloat64(&mc.difficulty, 1<<16)
+	last := submitAtRate(mc, time.Now(), 20, 40)
+	if got := mc.shareFloodFloor(last); got != 0 {
+		t.Fatalf("unexpected flood floor %v", got)
+	}
+	if got := atomicLoadFloat64(&mc.difficulty); got != 1<<16 {
+		t.Fatalf("difficulty changed to %v", got)
+	}
+}
+
+func TestShareFloodFloorRespectsMaxDifficulty(t *testing.T) {
+	mc := newFloodTestConn(600)
+	mc.cfg.MaxDifficulty = 4
+	last := submitAtRate(mc, time.Now(), 60000, 2000)
+	if got := atomicLoadFloat64(&mc.difficulty); got > 4 {
+		t.Fatalf("difficulty %v exceeds max_difficulty", got)
+	}
+	if mc.shareFloodFloor(last) == 0 {
+		t.Fatalf("expected flood floor to be active")
+	}
+}
+
+func TestShareFloodDisabled(t *testing.T) {
+	mc := newFloodTestConn(0)
+	last := submitAtRate(mc, time.Now(), 60000, 2000)
+	if got := mc.shareFloodFloor(last); got != 0 {
+		t.Fatalf("flood guard disabled but floor = %v", got)
+	}
+}
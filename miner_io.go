@@ -22,27 +22,49 @@ func (mc *MinerConn) writeBytes(b []byte) error {
 	return mc.writeBytesLocked(b)
 }
 
+// writeBytesLocked writes all of b, looping over short writes so a message
+// is never interleaved with the next one. Any failure leaves an unknown
+// prefix of b on the wire, so the connection is closed and the error kept;
+// the read loop then sees the closed socket and tears the miner down.
 func (mc *MinerConn) writeBytesLocked(b []byte) error {
+	if mc.writeErr != nil {
+		return mc.writeErr
+	}
 	if err := mc.conn.SetWriteDeadline(time.Now().Add(stratumWriteTimeout)); err != nil {
-		return err
+		return mc.failWriteLocked(err, 0, len(b))
 	}
 	logNetMessage("send", b)
-	for len(b) > 0 {
-		n, err := mc.conn.Write(b)
+	mc.traceJSON("send", b)
+	mc.beginWrite(time.Now())
+	defer mc.endWrite()
+	total, written := len(b), 0
+	for written < total {
+		n, err := mc.conn.Write(b[written:])
 		if n > 0 {
-			b = b[n:]
+			written += n
+			mc.writeProgressAt.Store(time.Now().UnixNano())
 		}
 		if err != nil {
-			return err
+			return mc.failWriteLocked(err, written, total)
 		}
 		if n == 0 {
-			return io.ErrUnexpectedEOF
+			return mc.failWriteLocked(io.ErrShortWrite, written, total)
 		}
 	}
 	return nil
 }
 
+func (mc *MinerConn) failWriteLocked(err error, written, total int) error {
+	mc.writeErr = err
+	logger.Warn("miner write failed, closing connection", "component", "miner", "kind", "write", "remote", mc.id, "written", written, "size", total, "error", err)
+	_ = mc.conn.Close()
+	return err
+}
+
 func (mc *MinerConn) writeResponse(resp StratumResponse) {
+	if isStratumNotificationID(resp.ID) {
+		return
+	}
 	if err := mc.writeJSON(resp); err != nil {
 		logger.Error("write error", "remote", mc.id, "error", err)
 	}
@@ -84,6 +106,27 @@ func (mc *MinerConn) sendClientShowMessage(message string) {
 	}
 }
 
+// sendClientReconnect asks the miner to reconnect to the same host/port.
+// Empty params are the widely supported form of client.reconnect.
+func (mc *MinerConn) sendClientReconnect(reason string) {
+	if mc == nil || mc.conn == nil {
+		return
+	}
+	fields := []any{"component", "miner", "kind", "lifecycle", "remote", mc.id, "reason", reason}
+	if worker := mc.currentWorker(); worker != "" {
+		fields = append(fields, "worker", worker)
+	}
+	logger.Info("sending client.reconnect", fields...)
+	msg := StratumMessage{
+		ID:     nil,
+		Method: "client.reconnect",
+		Params: []any{},
+	}
+	if err := mc.writeJSON(msg); err != nil {
+		logger.Warn("client.reconnect write error", append(fields, "error", err)...)
+	}
+}
+
 func (mc *MinerConn) writePongResponse(id any) {
 	mc.writeResponse(StratumResponse{
 		ID:     id,
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// shortWriteConn accepts at most chunk bytes per Write and, once failAfter
+// bytes have been taken (when > 0), fails every further Write.
+type shortWriteConn struct {
+	buf       bytes.Buffer
+	chunk     int
+	failAfter int
+	writes    int
+	closed    bool
+}
+
+func (c *shortWriteConn) Read([]byte) (int, error)         { return 0, nil }
+func (c *shortWriteConn) LocalAddr() net.Addr              { return &net.IPAddr{} }
+func (c *shortWriteConn) RemoteAddr() net.Addr             { return &net.IPAddr{} }
+func (c *shortWriteConn) SetDeadline(time.Time) error      { return nil }
+func (c *shortWriteConn) SetReadDeadline(time.Time) error  { return nil }
+func (c *shortWriteConn) SetWriteDeadline(time.Time) error { return nil }
+func (c *shortWriteConn) Close() error                     { c.closed = true; return nil }
+
+var errShortWriteConnBroken = errors.New("broken pipe")
+
+func (c *shortWriteConn) Write(b []byte) (int, error) {
+	c.writes++
+	if c.failAfter > 0 && c.buf.Len() >= c.failAfter {
+		return 0, errShortWriteConnBroken
+	}
+	n := min(len(b), c.chunk)
+	if c.failAfter > 0 {
+		n = min(n, c.failAfter-c.buf.Len())
+	}
+	c.buf.Write(b[:n])
+	if n < len(b) && c.failAfter > 0 && c.buf.Len() >= c.failAfter {
+		return n, errShortWriteConnBroken
+	}
+	return n, nil
+}
+
+func TestWriteJSONCompletesShortWrites(t *testing.T) {
+	conn := &shortWriteConn{chunk: 3}
+	mc := &MinerConn{id: "short-writes", conn: conn}
+	mc.writeTrueResponse(int64(1))
+	mc.writePongResponse(int64(2))
+
+	lines := strings.Split(strings.TrimSuffix(conn.buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 complete lines, got %q", conn.buf.String())
+	}
+	if !strings.Contains(lines[0], `"id":1`) || !strings.Contains(lines[1], `"pong"`) {
+		t.Fatalf("messages were interleaved or truncated: %q", conn.buf.String())
+	}
+	if conn.writes <= 2 || conn.closed {
+		t.Fatalf("expected several short writes on an open conn, got writes=%d closed=%v", conn.writes, conn.closed)
+	}
+}
+
+func TestWriteJSONClosesOnWriteError(t *testing.T) {
+	conn := &shortWriteConn{chunk: 4, failAfter: 10}
+	mc := &MinerConn{id: "broken-writes", conn: conn}
+	if err := mc.writeJSON(StratumResponse{ID: int64(1), Result: true}); !errors.Is(err, errShortWriteConnBroken) {
+		t.Fatalf("expected write error, got %v", err)
+	}
+	if !conn.closed {
+		t.Fatalf("expected connection to be closed after a failed write")
+	}
+	sent, writes := conn.buf.Len(), conn.writes
+
+	// The stream now ends mid-message; nothing may follow it.
+	if err := mc.writeJSON(StratumResponse{ID: int64(2), Result: true}); !errors.Is(err, errShortWriteConnBroken) {
+		t.Fatalf("expected the recorded error on later writes, got %v", err)
+	}
+	if conn.buf.Len() != sent || conn.writes != writes {
+		t.Fatalf("later write reached the socket after a failure")
+	}
+}
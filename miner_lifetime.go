@@ -0,0 +1,70 @@
+package main
+
+import (
+	"math/rand"
+	"time"
+)
+
+const (
+	// minMaxConnectionLifetime rejects lifetimes short enough to cause
+	// constant reconnect churn.
+	minMaxConnectionLifetime = 10 * time.Minute
+	// maxConnectionLifetimeJitter is the maximum extra lifetime added per
+	// connection, as a fraction of the configured lifetime.
+	maxConnectionLifetimeJitter = 0.25
+	// maxConnectionLifetimeGrace is how long a miner has to act on
+	// client.reconnect before the pool closes the connection itself.
+	maxConnectionLifetimeGrace = 30 * time.Second
+)
+
+// connectionLifetimeDeadline returns when a connection established at
+// connectedAt should be asked to reconnect. jitter is a value in [0,1) that
+// stretches the lifetime by up to maxConnectionLifetimeJitter so connections
+// accepted together (e.g. after a restart) do not all reconnect at once.
+func connectionLifetimeDeadline(connectedAt time.Time, lifetime time.Duration, jitter float64) time.Time {
+	if lifetime <= 0 || connectedAt.IsZero() {
+		return time.Time{}
+	}
+	if jitter < 0 {
+		jitter = 0
+	} else if jitter >= 1 {
+		jitter = 0.999
+	}
+	extra := time.Duration(float64(lifetime) * maxConnectionLifetimeJitter * jitter)
+	return connectedAt.Add(lifetime + extra)
+}
+
+// maxLifetimeReached sends client.reconnect once the connection has outlived
+// its lifetime deadline and reports true once the reconnect grace period has
+// elapsed without the miner disconnecting on its own.
+func (mc *MinerConn) maxLifetimeReached(now time.Time) bool {
+	if mc.lifetimeDeadline.IsZero() || now.Before(mc.lifetimeDeadline) {
+		return false
+	}
+	if mc.lifetimeReconnectSentAt.IsZero() {
+		mc.lifetimeReconnectSentAt = now
+		mc.sendClientReconnect("max connection lifetime reached")
+		return false
+	}
+	return now.Sub(mc.lifetimeReconnectSentAt) >= maxConnectionLifetimeGrace
+}
+
+// lifetimeReadDeadline caps a read deadline so the handle loop wakes up in
+// time to act on the connection lifetime (or the reconnect grace period).
+func (mc *MinerConn) lifetimeReadDeadline(deadline time.Time) time.Time {
+	wake := mc.lifetimeDeadline
+	if wake.IsZero() {
+		return deadline
+	}
+	if !mc.lifetimeReconnectSentAt.IsZero() {
+		wake = mc.lifetimeReconnectSentAt.Add(maxConnectionLifetimeGrace)
+	}
+	if wake.Before(deadline) {
+		return wake
+	}
+	return deadline
+}
+
+func newConnectionLifetimeDeadline(connectedAt time.Time, lifetime time.Duration) time.Time {
+	return connectionLifetimeDeadline(connectedAt, lifetime, rand.Float64())
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConnectionLifetimeDeadlineJitterBounds(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	lifetime := 4 * time.Hour
+
+	if got := connectionLifetimeDeadline(start, 0, 0.5); !got.IsZero() {
+		t.Fatalf("expected zero deadline when disabled, got %v", got)
+	}
+	if got := connectionLifetimeDeadline(start, lifetime, 0); !got.Equal(start.Add(lifetime)) {
+		t.Fatalf("expected no jitter at 0, got %v", got)
+	}
+	maxDeadline := start.Add(lifetime + time.Duration(float64(lifetime)*maxConnectionLifetimeJitter))
+	for _, j := range []float64{0.25, 0.5, 0.999, 1.5} {
+		got := connectionLifetimeDeadline(start, lifetime, j)
+		if got.Before(start.Add(lifetime)) || !got.Before(maxDeadline) {
+			t.Fatalf("jitter %v produced out-of-range deadline %v", j, got)
+		}
+	}
+}
+
+func TestMaxLifetimeReachedSendsReconnectThenCloses(t *testing.T) {
+	conn := &writeRecorderConn{}
+	now := time.Now()
+	mc := &MinerConn{
+		id:               "lifetime-miner",
+		conn:             conn,
+		lifetimeDeadline: now.Add(time.Minute),
+	}
+
+	if mc.maxLifetimeReached(now) {
+		t.Fatalf("expected connection to stay open before its deadline")
+	}
+	if got := mc.lifetimeReadDeadline(now.Add(time.Hour)); !got.Equal(mc.lifetimeDeadline) {
+		t.Fatalf("expected read deadline capped at lifetime deadline, got %v", got)
+	}
+
+	expiredAt := now.Add(2 * time.Minute)
+	if mc.maxLifetimeReached(expiredAt) {
+		t.Fatalf("expected grace period after sending client.reconnect")
+	}
+	if !strings.Contains(conn.String(), `"client.reconnect"`) {
+		t.Fatalf("expected client.reconnect to be sent, got %q", conn.String())
+	}
+	if mc.maxLifetimeReached(expiredAt.Add(maxConnectionLifetimeGrace / 2)) {
+		t.Fatalf("expected connection to stay open during grace period")
+	}
+	if !mc.maxLifetimeReached(expiredAt.Add(maxConnectionLifetimeGrace)) {
+		t.Fatalf("expected connection to close after grace period")
+	}
+	if n := strings.Count(conn.String(), "client.reconnect"); n != 1 {
+		t.Fatalf("expected a single client.reconnect, got %d", n)
+	}
+}
@@ -87,7 +87,7 @@ func TestLogFoundBlockFallback_NoWorkerScript(t *testing.T) {
 	}
 	mc := &MinerConn{cfg: cfg}
 
-	mc.logFoundBlock(job, "worker1", "deadbeef", 1.0)
+	mc.logFoundBlock(job, "worker1", "deadbeef", 1.0, nil)
 
 	rec := readLastFoundBlockRecord(t, dir)
 
@@ -144,7 +144,7 @@ func TestLogFoundBlockFallback_SameWorkerAddress(t *testing.T) {
 	}
 	mc.setWorkerWallet(addr, addr, script)
 
-	mc.logFoundBlock(job, addr, "deadbeef", 1.0)
+	mc.logFoundBlock(job, addr, "deadbeef", 1.0, nil)
 
 	rec := readLastFoundBlockRecord(t, dir)
 
@@ -200,7 +200,7 @@ func TestLogFoundBlock_NoFallbackDifferentAddress(t *testing.T) {
 	}
 	mc.setWorkerWallet(workerAddr, workerAddr, script)
 
-	mc.logFoundBlock(job, workerAddr, "deadbeef", 1.0)
+	mc.logFoundBlock(job, workerAddr, "deadbeef", 1.0, nil)
 
 	rec := readLastFoundBlockRecord(t, dir)
 
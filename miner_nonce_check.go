@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// minNonceCheckSamples is the smallest batch the nonce-distribution check
+	// will judge; fewer samples cannot tell a bad miner from a slow one.
+	minNonceCheckSamples = 8
+	// maxNonceCheckSamples bounds the per-connection sample buffer.
+	maxNonceCheckSamples = 4096
+	// nonceCheckNarrowSpan is the max-min nonce spread treated as degenerate.
+	// Honest hashers scan the full 32-bit space, so a whole batch landing in
+	// a 64Ki window (for example always the same low value) is not plausible.
+	nonceCheckNarrowSpan = 1 << 16
+)
+
+// nonceCheckState holds one batch of submitted nonces for a connection. It is
+// guarded by MinerConn.stateMu since submits are processed on the submission
+// workers.
+type nonceCheckState struct {
+	samples  []uint32
+	strikes  int
+	reported bool
+}
+
+// nonceBatchVerdict describes why a batch of nonces looked degenerate.
+type nonceBatchVerdict struct {
+	Samples  int
+	Distinct int
+	Min      uint32
+	Max      uint32
+	Reason   string
+}
+
+// evaluateNonceBatch flags a batch whose nonces repeat heavily or cluster in a
+// narrow range. It returns ok=true when the batch looks like honest work.
+func evaluateNonceBatch(samples []uint32) (verdict nonceBatchVerdict, ok bool) {
+	verdict.Samples = len(samples)
+	if len(samples) == 0 {
+		return verdict, true
+	}
+	seen := make(map[uint32]struct{}, len(samples))
+	verdict.Min, verdict.Max = samples[0], samples[0]
+	for _, n := range samples {
+		seen[n] = struct{}{}
+		if n < verdict.Min {
+			verdict.Min = n
+		}
+		if n > verdict.Max {
+			verdict.Max = n
+		}
+	}
+	verdict.Distinct = len(seen)
+	switch {
+	case verdict.Distinct*2 < len(samples):
+		verdict.Reason = "repeated nonces"
+	case verdict.Max-verdict.Min < nonceCheckNarrowSpan:
+		verdict.Reason = "narrow nonce range"
+	default:
+		return verdict, true
+	}
+	return verdict, false
+}
+
+func nonceCheckBatchSize(cfg Config) int {
+	n := cfg.NonceCheckMinSamples
+	if n <= 0 {
+		return 0
+	}
+	if n < minNonceCheckSamples {
+		n = minNonceCheckSamples
+	}
+	if n > maxNonceCheckSamples {
+		n = maxNonceCheckSamples
+	}
+	return n
+}
+
+// noteSubmitNonce feeds a parsed submit nonce into the optional
+// nonce-distribution heuristic. Once a full batch of nonce_check_min_samples
+// has been collected it is judged and discarded. Degenerate batches are
+// logged (once per connection) and added to the error history; when
+// nonce_check_ban_after is set, that many consecutive degenerate batches ban
+// the connection. A healthy batch clears the strike count.
+func (mc *MinerConn) noteSubmitNonce(workerName string, nonce uint32, now time.Time) {
+	batch := nonceCheckBatchSize(mc.cfg)
+	if batch == 0 {
+		return
+	}
+	mc.stateMu.Lock()
+	st := &mc.nonceCheck
+	if st.samples == nil {
+		st.samples = make([]uint32, 0, batch)
+	}
+	st.samples = append(st.samples, nonce)
+	if len(st.samples) < batch {
+		mc.stateMu.Unlock()
+		return
+	}
+	verdict, ok := evaluateNonceBatch(st.samples)
+	st.samples = st.samples[:0]
+	if ok {
+		st.strikes = 0
+		mc.stateMu.Unlock()
+		return
+	}
+	st.strikes++
+	strikes := st.strikes
+	firstReport := !st.reported
+	st.reported = true
+	mc.stateMu.Unlock()
+
+	banAfter := mc.cfg.NonceCheckBanAfter
+	if firstReport {
+		logger.Warn("degenerate nonce distribution",
+			"component", "miner", "kind", "nonce_check",
+			"miner", mc.minerName(workerName),
+			"remote", mc.id,
+			"reason", verdict.Reason,
+			"samples", verdict.Samples,
+			"distinct", verdict.Distinct,
+			"min_nonce", uint32ToHex8Lower(verdict.Min),
+			"max_nonce", uint32ToHex8Lower(verdict.Max),
+		)
+		if mc.metrics != nil {
+			mc.metrics.RecordErrorEvent("nonce_check", fmt.Sprintf("%s: %s (%d distinct of %d)", mc.minerName(workerName), verdict.Reason, verdict.Distinct, verdict.Samples), now)
+		}
+	}
+	if banAfter > 0 && strikes >= banAfter {
+		banDuration := mc.cfg.BanInvalidSubmissionsDuration
+		if banDuration <= 0 {
+			banDuration = defaultBanInvalidSubmissionsDuration
+		}
+		mc.banFor("degenerate nonce distribution", banDuration, workerName)
+	}
+}
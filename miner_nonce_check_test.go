@@ -0,0 +1,82 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestEvaluateNonceBatch(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	random := make([]uint32, 32)
+	for i := range random {
+		random[i] = r.Uint32()
+	}
+	if v, ok := evaluateNonceBatch(random); !ok {
+		t.Fatalf("uniform nonces flagged: %+v", v)
+	}
+
+	constant := make([]uint32, 32)
+	for i := range constant {
+		constant[i] = 0x00000001
+	}
+	if v, ok := evaluateNonceBatch(constant); ok || v.Reason != "repeated nonces" {
+		t.Fatalf("constant nonces not flagged as repeated: %+v ok=%v", v, ok)
+	}
+
+	low := make([]uint32, 32)
+	for i := range low {
+		low[i] = uint32(i * 7)
+	}
+	if v, ok := evaluateNonceBatch(low); ok || v.Reason != "narrow nonce range" {
+		t.Fatalf("low nonces not flagged as narrow: %+v ok=%v", v, ok)
+	}
+}
+
+func TestNoteSubmitNonceAlertOnlyByDefault(t *testing.T) {
+	mc := &MinerConn{
+		id:   "nonce-alert-miner",
+		cfg:  Config{NonceCheckMinSamples: 8},
+		conn: &writeRecorderConn{},
+	}
+	now := time.Now()
+	for i := 0; i < 64; i++ {
+		mc.noteSubmitNonce("w", 0, now)
+	}
+	if mc.isBanned(now) {
+		t.Fatalf("expected alert-only mode not to ban")
+	}
+	if mc.nonceCheck.strikes != 8 {
+		t.Fatalf("expected 8 degenerate batches, got %d", mc.nonceCheck.strikes)
+	}
+}
+
+func TestNoteSubmitNonceBansAfterThreshold(t *testing.T) {
+	mc := &MinerConn{
+		id:           "nonce-ban-miner",
+		cfg:          Config{NonceCheckMinSamples: 8, NonceCheckBanAfter: 2},
+		conn:         &writeRecorderConn{},
+		statsUpdates: make(chan statsUpdate, 8),
+	}
+	now := time.Now()
+	r := rand.New(rand.NewSource(2))
+	// A healthy batch between degenerate ones resets the strike count.
+	for i := 0; i < 8; i++ {
+		mc.noteSubmitNonce("w", 5, now)
+	}
+	for i := 0; i < 8; i++ {
+		mc.noteSubmitNonce("w", r.Uint32(), now)
+	}
+	for i := 0; i < 8; i++ {
+		mc.noteSubmitNonce("w", 5, now)
+	}
+	if mc.isBanned(now) {
+		t.Fatalf("ban should require consecutive degenerate batches")
+	}
+	for i := 0; i < 8; i++ {
+		mc.noteSubmitNonce("w", 5, now)
+	}
+	if !mc.isBanned(now) {
+		t.Fatalf("expected ban after two consecutive degenerate batches")
+	}
+}
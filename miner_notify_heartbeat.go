@@ -0,0 +1,91 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// minNotifyHeartbeatInterval keeps notify_heartbeat_seconds from turning
+// into a steady stream of redundant notifies.
+const minNotifyHeartbeatInterval = 5 * time.Second
+
+// notifyHeartbeat remembers the last mining.notify sent on a connection so
+// it can be re-sent verbatim (with clean_jobs=false) when no new job has
+// gone out for notify_heartbeat_seconds. Some firmware drops the connection
+// after a long quiet stretch between blocks.
+type notifyHeartbeat struct {
+	mu            sync.Mutex
+	seq           uint64
+	templateJobID string
+	params        []any
+	sentAt        time.Time
+}
+
+// rememberNotify records params as the latest notify. It must run before the
+// notify is written so a heartbeat racing with it can tell it is stale.
+func (mc *MinerConn) rememberNotify(templateJobID string, params []any, now time.Time) {
+	hb := append([]any(nil), params...)
+	if len(hb) > 0 {
+		hb[len(hb)-1] = false
+	}
+	mc.notifyHeartbeat.mu.Lock()
+	mc.notifyHeartbeat.seq++
+	mc.notifyHeartbeat.templateJobID = templateJobID
+	mc.notifyHeartbeat.params = hb
+	mc.notifyHeartbeat.sentAt = now
+	mc.notifyHeartbeat.mu.Unlock()
+}
+
+// notifyHeartbeatCheckInterval is how often listenJobs looks for a quiet
+// connection; a heartbeat goes out at most a quarter interval late.
+func notifyHeartbeatCheckInterval(interval time.Duration) time.Duration {
+	return max(interval/4, time.Second)
+}
+
+// sendNotifyHeartbeat re-sends the last notify unchanged apart from
+// clean_jobs=false, so the miner keeps its current work. It does nothing if
+// a notify went out within interval, or if the job it carries is no longer
+// the pool's current job (the real new job is on its way).
+func (mc *MinerConn) sendNotifyHeartbeat(now time.Time, interval time.Duration) bool {
+	mc.notifyHeartbeat.mu.Lock()
+	seq := mc.notifyHeartbeat.seq
+	templateJobID := mc.notifyHeartbeat.templateJobID
+	params := mc.notifyHeartbeat.params
+	quiet := now.Sub(mc.notifyHeartbeat.sentAt) >= interval
+	mc.notifyHeartbeat.mu.Unlock()
+	if params == nil || !quiet {
+		return false
+	}
+	if mc.jobMgr != nil {
+		if cur := mc.jobMgr.CurrentJob(); cur == nil || cur.JobID != templateJobID {
+			return false
+		}
+	}
+	b, err := fastJSONMarshal(StratumMessage{ID: nil, Method: "mining.notify", Params: params})
+	if err != nil {
+		return false
+	}
+	b = append(b, '\n')
+
+	// Hold the write lock while re-checking so a real notify that was
+	// remembered in the meantime is never followed by this older one.
+	mc.writeMu.Lock()
+	defer mc.writeMu.Unlock()
+	mc.notifyHeartbeat.mu.Lock()
+	current := mc.notifyHeartbeat.seq == seq
+	if current {
+		mc.notifyHeartbeat.sentAt = now
+	}
+	mc.notifyHeartbeat.mu.Unlock()
+	if !current {
+		return false
+	}
+	if err := mc.writeBytesLocked(b); err != nil {
+		logger.Error("notify heartbeat write error", "component", "miner", "kind", "notify", "remote", mc.id, "error", err)
+		return false
+	}
+	if debugLogging {
+		logger.Debug("notify heartbeat sent", "component", "miner", "kind", "notify", "remote", mc.id, "job", params[0])
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSendNotifyHeartbeatResendsLastNotify(t *testing.T) {
+	conn := &writeRecorderConn{}
+	jm := &JobManager{curJob: &Job{JobID: "tmpl1"}}
+	mc := &MinerConn{id: "heartbeat", conn: conn, jobMgr: jm}
+	interval := 30 * time.Second
+	start := time.Unix(1700000000, 0)
+
+	if mc.sendNotifyHeartbeat(start, interval) {
+		t.Fatalf("heartbeat sent before any notify")
+	}
+	params := []any{"tmpl1:1", "prev", "cb1", "cb2", []string{}, "20000000", "1d00ffff", "65000000", true}
+	mc.rememberNotify("tmpl1", params, start)
+	if params[8] != true {
+		t.Fatalf("rememberNotify modified the caller's params")
+	}
+	if mc.sendNotifyHeartbeat(start.Add(interval-time.Second), interval) {
+		t.Fatalf("heartbeat sent before the interval elapsed")
+	}
+	if !mc.sendNotifyHeartbeat(start.Add(interval), interval) {
+		t.Fatalf("expected heartbeat after a quiet interval")
+	}
+	out := conn.String()
+	if !strings.Contains(out, `"mining.notify"`) || !strings.Contains(out, `["tmpl1:1","prev","cb1","cb2",[],"20000000","1d00ffff","65000000",false]`) {
+		t.Fatalf("heartbeat should repeat the last notify with clean_jobs=false, got %q", out)
+	}
+	if mc.sendNotifyHeartbeat(start.Add(interval+time.Second), interval) {
+		t.Fatalf("heartbeat should reset the quiet timer")
+	}
+
+	// Once the pool moves to a new job the stale notify is never repeated.
+	jm.curJob = &Job{JobID: "tmpl2"}
+	conn.buf.Reset()
+	if mc.sendNotifyHeartbeat(start.Add(3*interval), interval) || conn.String() != "" {
+		t.Fatalf("heartbeat must stop once the current job changes")
+	}
+}
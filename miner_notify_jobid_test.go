@@ -77,8 +77,8 @@ func TestSendNotifyForUsesUniqueStratumJobIDsForRepeatedNotify(t *testing.T) {
 		t.Fatalf("expected emitted Stratum job ids to be per-notify ids, base=%q ids=%#v", job.JobID, ids)
 	}
 
-	firstJob, _, _, _, _, firstScriptTime, firstOK := mc.jobForIDWithLast(ids[0])
-	secondJob, _, _, _, _, secondScriptTime, secondOK := mc.jobForIDWithLast(ids[1])
+	firstJob, _, _, _, _, firstScriptTime, _, firstOK := mc.jobForIDWithLast(ids[0])
+	secondJob, _, _, _, _, secondScriptTime, _, secondOK := mc.jobForIDWithLast(ids[1])
 	if !firstOK || !secondOK || firstJob != job || secondJob != job {
 		t.Fatalf("notify ids did not resolve to the underlying job")
 	}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readPipelinedResponses reads replies (messages with a non-null id) until
+// want have arrived, skipping pool notifications.
+func readPipelinedResponses(t *testing.T, br *bufio.Reader, client net.Conn, want int) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	_ = client.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for len(out) < want {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read response %d: %v", len(out)+1, err)
+		}
+		var msg map[string]any
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			t.Fatalf("unmarshal %q: %v", line, err)
+		}
+		if msg["id"] == nil {
+			continue
+		}
+		out = append(out, msg)
+	}
+	return out
+}
+
+func TestMinerConn_PipelinedRequestsHandledInOrder(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+
+	mc := &MinerConn{
+		id:           "pipelined",
+		ctx:          context.Background(),
+		conn:         server,
+		reader:       bufio.NewReader(server),
+		jobMgr:       &JobManager{},
+		cfg:          Config{ConnectionTimeout: time.Hour, ShareRequireAuthorizedConnection: true, ShareRequireSubscribedConnection: true},
+		lastActivity: time.Now(),
+	}
+	done := make(chan struct{})
+	go func() {
+		mc.handle()
+		close(done)
+	}()
+
+	worker := "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa.worker"
+	submit := func(id int) string {
+		return `{"id":` + strconv.Itoa(id) + `,"method":"mining.submit","params":["` + worker + `","1","00000000","00000000","00000000"]}`
+	}
+	// Everything arrives in one write, before any reply has been read.
+	burst := strings.Join([]string{
+		submit(1),
+		`{"id":2,"method":"mining.subscribe","params":["test/1.0"]}`,
+		submit(3),
+		`{"id":4,"method":"mining.authorize","params":["` + worker + `",""]}`,
+		`{"id":5,"method":"mining.ping","params":[]}`,
+	}, "\n") + "\n"
+	writeErr := make(chan error, 1)
+	go func() {
+		_, err := io.WriteString(client, burst)
+		writeErr <- err
+	}()
+
+	br := bufio.NewReader(client)
+	resps := readPipelinedResponses(t, br, client, 5)
+	if err := <-writeErr; err != nil {
+		t.Fatalf("write burst: %v", err)
+	}
+	for i, resp := range resps {
+		if resp["id"] != float64(i+1) {
+			t.Fatalf("response %d out of order: id=%v", i+1, resp["id"])
+		}
+	}
+	errCode := func(resp map[string]any) float64 {
+		e, ok := resp["error"].([]any)
+		if !ok || len(e) == 0 {
+			return 0
+		}
+		code, _ := e[0].(float64)
+		return code
+	}
+	if got := errCode(resps[0]); got != stratumErrCodeNotSubscribed {
+		t.Fatalf("submit before subscribe: expected error %d, got %v", stratumErrCodeNotSubscribed, resps[0])
+	}
+	if _, ok := resps[1]["result"].([]any); !ok {
+		t.Fatalf("expected subscribe result, got %v", resps[1])
+	}
+	if got := errCode(resps[2]); got != stratumErrCodeUnauthorized {
+		t.Fatalf("submit before authorize: expected error %d, got %v", stratumErrCodeUnauthorized, resps[2])
+	}
+	if resps[3]["result"] != true {
+		t.Fatalf("expected authorize true, got %v", resps[3])
+	}
+	if resps[4]["result"] != "pong" {
+		t.Fatalf("expected pong, got %v", resps[4])
+	}
+
+	_ = client.Close()
+	_ = server.Close()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("miner conn did not exit")
+	}
+}
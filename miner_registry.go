@@ -1,6 +1,9 @@
 package main
 
-import "sync"
+import (
+	"net"
+	"sync"
+)
 
 // MinerRegistry tracks active MinerConn instances with a mutex held only during
 // add/remove operations. Snapshotting allows status/metrics code to walk a
@@ -8,20 +11,36 @@ import "sync"
 type MinerRegistry struct {
 	mu    sync.Mutex
 	conns map[*MinerConn]struct{}
+	// hosts counts active connections per remote host for max_conns_per_ip.
+	hosts map[string]int
 }
 
 func NewMinerRegistry() *MinerRegistry {
 	return &MinerRegistry{
 		conns: make(map[*MinerConn]struct{}),
+		hosts: make(map[string]int),
 	}
 }
 
+// stratumRemoteHost strips the port from a connection's remote address,
+// returning addr unchanged when it has none.
+func stratumRemoteHost(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
 func (r *MinerRegistry) Add(mc *MinerConn) {
 	if mc == nil {
 		return
 	}
 	r.mu.Lock()
-	r.conns[mc] = struct{}{}
+	if _, ok := r.conns[mc]; !ok {
+		r.conns[mc] = struct{}{}
+		r.hosts[stratumRemoteHost(mc.id)]++
+	}
 	r.mu.Unlock()
 }
 
@@ -30,7 +49,15 @@ func (r *MinerRegistry) Remove(mc *MinerConn) {
 		return
 	}
 	r.mu.Lock()
-	delete(r.conns, mc)
+	if _, ok := r.conns[mc]; ok {
+		delete(r.conns, mc)
+		host := stratumRemoteHost(mc.id)
+		if r.hosts[host] <= 1 {
+			delete(r.hosts, host)
+		} else {
+			r.hosts[host]--
+		}
+	}
 	r.mu.Unlock()
 }
 
@@ -41,6 +68,14 @@ func (r *MinerRegistry) Count() int {
 	return n
 }
 
+// HostCount returns the number of active connections from host.
+func (r *MinerRegistry) HostCount(host string) int {
+	r.mu.Lock()
+	n := r.hosts[host]
+	r.mu.Unlock()
+	return n
+}
+
 func (r *MinerRegistry) Snapshot() []*MinerConn {
 	r.mu.Lock()
 	out := make([]*MinerConn, 0, len(r.conns))
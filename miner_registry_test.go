@@ -0,0 +1,34 @@
+package main
+
+import "testing"
+
+func TestMinerRegistryHostCount(t *testing.T) {
+	r := NewMinerRegistry()
+	a := &MinerConn{id: "192.0.2.1:4000"}
+	b := &MinerConn{id: "192.0.2.1:4001"}
+	c := &MinerConn{id: "[2001:db8::1]:4000"}
+	r.Add(a)
+	r.Add(a)
+	r.Add(b)
+	r.Add(c)
+
+	if got := r.HostCount("192.0.2.1"); got != 2 {
+		t.Fatalf("HostCount(192.0.2.1) = %d, want 2", got)
+	}
+	if got := r.HostCount("2001:db8::1"); got != 1 {
+		t.Fatalf("HostCount(2001:db8::1) = %d, want 1", got)
+	}
+
+	r.Remove(a)
+	r.Remove(a)
+	if got := r.HostCount("192.0.2.1"); got != 1 {
+		t.Fatalf("HostCount after remove = %d, want 1", got)
+	}
+	r.Remove(b)
+	if got := r.HostCount("192.0.2.1"); got != 0 {
+		t.Fatalf("HostCount after removing all = %d, want 0", got)
+	}
+	if len(r.hosts) != 1 {
+		t.Fatalf("expected emptied hosts to be dropped, got %v", r.hosts)
+	}
+}
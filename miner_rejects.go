@@ -1,7 +1,7 @@
 package main
 
 import (
-	"encoding/hex"
+	"bytes"
 	"fmt"
 	"math"
 	"math/big"
@@ -491,6 +491,23 @@ func (mc *MinerConn) vardiffRetargetInterval(rollingHashrate, currentDiff, targe
 	return interval
 }
 
+// staleFeedbackBias scales the vardiff target down when recent stale-job
+// rejects exceed vardiff_stale_feedback_percent, on the theory that work is
+// taking too long to find at the current difficulty. The stale rate only
+// counts timing rejects (see isStaleRejectReason), so a miner sending bad
+// nonces or malformed shares never earns a lower difficulty.
+func (mc *MinerConn) staleFeedbackBias(staleRate float64, samples int) float64 {
+	threshold := mc.cfg.VarDiffStaleFeedbackPercent / 100
+	if threshold <= 0 || samples < vardiffStaleFeedbackMinSamples || staleRate <= threshold {
+		return 1
+	}
+	bias := 1 - vardiffStaleFeedbackGain*(staleRate-threshold)
+	if bias < vardiffStaleFeedbackMinBias {
+		bias = vardiffStaleFeedbackMinBias
+	}
+	return bias
+}
+
 func applyStaleRetargetSlowdown(interval time.Duration, staleRate float64) time.Duration {
 	if interval <= 0 || staleRate <= 0 {
 		return interval
@@ -638,20 +655,17 @@ func (mc *MinerConn) trackJob(job *Job, stratumJobID string, clean bool) {
 		if job.Template.Mintime > 0 && job.Template.Mintime > minNTime {
 			minNTime = job.Template.Mintime
 		}
-		slack := mc.cfg.ShareNTimeMaxForwardSeconds
-		if slack <= 0 {
-			slack = defaultShareNTimeMaxForwardSeconds
-		}
 		mc.jobNTimeBounds[stratumJobID] = jobNTimeBounds{
 			min: minNTime,
-			max: minNTime + int64(slack),
+			max: minNTime + mc.ntimeForwardSlack(),
 		}
 	}
 
 	// Evict oldest jobs if we exceed the max limit
 	dupEnabled := mc.cfg.ShareCheckDuplicate
 	now := time.Time{}
-	for len(mc.jobOrder) > mc.maxRecentJobs && len(mc.jobOrder) > 0 {
+	jobLimit := mc.retainedJobLimit()
+	for len(mc.jobOrder) > jobLimit && len(mc.jobOrder) > 0 {
 		oldest := mc.jobOrder[0]
 		mc.jobOrder = mc.jobOrder[1:]
 		delete(mc.activeJobs, oldest)
@@ -695,6 +709,42 @@ func (mc *MinerConn) trackJob(job *Job, stratumJobID string, clean bool) {
 	}
 }
 
+func (mc *MinerConn) ntimeForwardSlack() int64 {
+	slack := mc.cfg.ShareNTimeMaxForwardSeconds
+	if slack <= 0 {
+		slack = defaultShareNTimeMaxForwardSeconds
+	}
+	return int64(slack)
+}
+
+// refreshNTimeBounds moves b's upper bound up to now+slack for a long-lived
+// job. The lower bound (template curtime/mintime floor) is never touched.
+func refreshNTimeBounds(b jobNTimeBounds, now, slack int64) jobNTimeBounds {
+	if b.max == 0 {
+		return b
+	}
+	if m := now + slack; m > b.max {
+		b.max = m
+	}
+	return b
+}
+
+// currentNTimeBoundsLocked returns the ntime window for jobID. With
+// share_ntime_refresh_current_job, the current job's window is first
+// refreshed against the wall clock so slow-block jobs stay mineable. Only
+// the stored window changes; the job and its merkle data are untouched.
+// Caller must hold jobMu.
+func (mc *MinerConn) currentNTimeBoundsLocked(jobID string) jobNTimeBounds {
+	b := mc.jobNTimeBounds[jobID]
+	if mc.cfg.ShareNTimeRefreshCurrentJob && jobID == mc.lastJobID {
+		if nb := refreshNTimeBounds(b, time.Now().Unix(), mc.ntimeForwardSlack()); nb != b {
+			mc.jobNTimeBounds[jobID] = nb
+			b = nb
+		}
+	}
+	return b
+}
+
 func (mc *MinerConn) scriptTimeForJob(jobID string, fallback int64) int64 {
 	if jobID == "" {
 		return fallback
@@ -709,27 +759,34 @@ func (mc *MinerConn) scriptTimeForJob(jobID string, fallback int64) int64 {
 }
 
 // jobForIDWithLast returns the job for the given ID along with the current lastJob
-// and the scriptTime used when this job was notified to this connection, all
-// under a single lock acquisition to avoid race conditions.
-func (mc *MinerConn) jobForIDWithLast(jobID string) (job *Job, lastJob *Job, lastPrevHash string, lastHeight int64, ntimeBounds jobNTimeBounds, scriptTime int64, ok bool) {
+// and the scriptTime and extranonce1 used when this job was notified to this
+// connection, all under a single lock acquisition to avoid race conditions.
+// Jobs notified before an extranonce1 rotation are reported missing once the
+// rotation grace window has passed.
+func (mc *MinerConn) jobForIDWithLast(jobID string) (job *Job, lastJob *Job, lastPrevHash string, lastHeight int64, ntimeBounds jobNTimeBounds, scriptTime int64, extranonce1 []byte, ok bool) {
 	mc.jobMu.Lock()
 	defer mc.jobMu.Unlock()
 	job, ok = mc.activeJobs[jobID]
 	if mc.cfg.ShareCheckNTimeWindow && mc.jobNTimeBounds != nil {
-		ntimeBounds = mc.jobNTimeBounds[jobID]
+		ntimeBounds = mc.currentNTimeBoundsLocked(jobID)
 	}
 	if mc.jobScriptTime != nil {
 		scriptTime = mc.jobScriptTime[jobID]
 	}
+	extranonce1 = mc.extranonce1ForJobLocked(jobID)
+	if ok && !bytes.Equal(extranonce1, mc.extranonce1) && time.Since(mc.extranonceRotatedAt) > extranonceRotationGrace {
+		job, ok = nil, false
+	}
 	if !ok && mc.lastJobID != "" {
 		if mc.cfg.ShareCheckNTimeWindow && mc.jobNTimeBounds != nil {
-			ntimeBounds = mc.jobNTimeBounds[mc.lastJobID]
+			ntimeBounds = mc.currentNTimeBoundsLocked(mc.lastJobID)
 		}
 		if mc.jobScriptTime != nil {
 			scriptTime = mc.jobScriptTime[mc.lastJobID]
 		}
+		extranonce1 = mc.extranonce1ForJobLocked(mc.lastJobID)
 	}
-	return job, mc.lastJob, mc.lastJobPrevHash, mc.lastJobHeight, ntimeBounds, scriptTime, ok
+	return job, mc.lastJob, mc.lastJobPrevHash, mc.lastJobHeight, ntimeBounds, scriptTime, extranonce1, ok
 }
 
 func (mc *MinerConn) setJobDifficulty(jobID string, diff float64) {
@@ -833,7 +890,7 @@ func (mc *MinerConn) isDuplicateShare(jobID string, extranonce2 []byte, ntime, n
 func (mc *MinerConn) maybeAdjustDifficulty(now time.Time) bool {
 	varDiffEnabled := mc.cfg.VarDiffEnabled || mc.cfg.TargetSharesPerMin <= 0
 	// If this connection is locked to a static difficulty, skip VarDiff.
-	if !varDiffEnabled || mc.lockDifficulty {
+	if !varDiffEnabled || mc.lockDifficulty || mc.difficultyPinned() {
 		return false
 	}
 
@@ -845,6 +902,10 @@ func (mc *MinerConn) maybeAdjustDifficulty(now time.Time) bool {
 		profiler.ObserveVardiff(mc, now, snap, currentDiff, newDiff)
 	}
 
+	if newDiff > 0 && mc.cfg.WorkerDifficultySync {
+		newDiff = mc.workerSyncedDifficulty(now, newDiff, snap.RollingHashrate)
+	}
+
 	if newDiff == 0 || math.Abs(newDiff-currentDiff) < 1e-6 {
 		return false
 	}
@@ -959,6 +1020,8 @@ func (mc *MinerConn) suggestedVardiff(now time.Time, snap minerShareSnapshot) fl
 		return currentDiff
 	}
 
+	targetDiff *= mc.staleFeedbackBias(snap.RecentStaleRate, snap.RecentSubmissionSamples)
+
 	// Aim directly at computed target share cadence.
 	if mc.vardiff.MaxDiff > 0 && targetDiff > mc.vardiff.MaxDiff {
 		targetDiff = mc.vardiff.MaxDiff
@@ -1372,6 +1435,15 @@ func (mc *MinerConn) clampDifficulty(diff float64) float64 {
 		max = min
 	}
 
+	// A share-flood floor tightens the minimum but never overrides the
+	// configured maximum.
+	if flood := mc.shareFloodFloor(time.Now()); flood > min {
+		min = flood
+		if max > 0 && min > max {
+			min = max
+		}
+	}
+
 	if diff < min {
 		diff = min
 	}
@@ -1383,7 +1455,8 @@ func (mc *MinerConn) clampDifficulty(diff float64) float64 {
 		granularity = defaultDifficultyStepGranularity
 	}
 	// Snap the final difficulty to the configured logarithmic step grid.
-	return quantizeDifficulty(diff, min, max, granularity)
+	// Never hand out zero or a difficulty too small for a 256-bit target.
+	return math.Max(quantizeDifficulty(diff, min, max, granularity), minShareDifficulty)
 }
 
 func (mc *MinerConn) setDifficulty(diff float64) {
@@ -1536,10 +1609,10 @@ func (mc *MinerConn) sendSetExtranonce(ex1 string, en2Size int) {
 }
 
 func (mc *MinerConn) handleExtranonceSubscribe(req *StratumRequest) {
-	mc.extranonceSubscribed = true
+	mc.extranonceSubscribed.Store(true)
 	mc.writeTrueResponse(req.ID)
 
-	ex1 := hex.EncodeToString(mc.extranonce1)
+	_, ex1 := mc.currentExtranonce1()
 	en2Size := mc.cfg.Extranonce2Size
 	if en2Size <= 0 {
 		en2Size = 4
@@ -227,6 +227,9 @@ func (mc *MinerConn) recordShare(worker string, accepted bool, creditedDiff floa
 
 	if mc.metrics != nil {
 		mc.metrics.RecordShare(accepted, reason)
+		if mc.cfg.ShareListenerLabels {
+			mc.metrics.RecordListenerShare(mc.listener, accepted)
+		}
 	}
 }
 
@@ -337,6 +340,7 @@ type minerShareSnapshot struct {
 	NotifyToFirstShareP95MS   float64
 	NotifyToFirstShareSamples int
 	RecentStaleRate           float64
+	RecentSubmissionSamples   int
 	LastShareHash             string
 	LastShareAccepted         bool
 	LastShareDifficulty       float64
@@ -380,6 +384,7 @@ func (mc *MinerConn) snapshotShareInfo() minerShareSnapshot {
 		NotifyToFirstShareP95MS:   warmP95,
 		NotifyToFirstShareSamples: mc.notifyToFirstCount,
 		RecentStaleRate:           mc.recentStaleRateLocked(),
+		RecentSubmissionSamples:   mc.recentSubmissionCount,
 		LastShareHash:             mc.lastShareHash,
 		LastShareAccepted:         mc.lastShareAccepted,
 		LastShareDifficulty:       mc.lastShareDifficulty,
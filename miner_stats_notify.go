@@ -0,0 +1,45 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+// Bounds for stats_notify_seconds: often enough for a dashboard, rare enough
+// that one extra line per connection stays negligible next to share traffic.
+const (
+	minStatsNotifyInterval = 30 * time.Second
+	maxStatsNotifyInterval = time.Hour
+)
+
+// statsNotifyMethod is a vendor notification; firmware that does not know it
+// ignores it like any other unknown method.
+const statsNotifyMethod = "goPool.stats"
+
+// sendStatsNotify pushes the connection's current difficulty, share counts
+// and estimated hashrate (H/s) as a single object parameter.
+func (mc *MinerConn) sendStatsNotify() bool {
+	if mc == nil || mc.conn == nil {
+		return false
+	}
+	snap := mc.snapshotShareInfo()
+	hashrate := snap.RollingHashrateDisplay
+	if math.IsNaN(hashrate) || math.IsInf(hashrate, 0) || hashrate < 0 {
+		hashrate = 0
+	}
+	msg := StratumMessage{
+		ID:     nil,
+		Method: statsNotifyMethod,
+		Params: []any{map[string]any{
+			"difficulty": atomicLoadFloat64(&mc.difficulty),
+			"accepted":   snap.Stats.Accepted,
+			"rejected":   snap.Stats.Rejected,
+			"hashrate":   math.Round(hashrate),
+		}},
+	}
+	if err := mc.writeJSON(msg); err != nil {
+		logger.Warn("stats notify write error", "component", "miner", "kind", "stats_notify", "remote", mc.id, "error", err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSendStatsNotify(t *testing.T) {
+	mc, conn := minerConnForNotifyTest(t)
+	atomicStoreFloat64(&mc.difficulty, 2048)
+	mc.statsMu.Lock()
+	mc.stats.Accepted = 7
+	mc.stats.Rejected = 2
+	mc.statsMu.Unlock()
+
+	if !mc.sendStatsNotify() {
+		t.Fatalf("expected stats notify to be sent")
+	}
+	var msg struct {
+		ID     any              `json:"id"`
+		Method string           `json:"method"`
+		Params []map[string]any `json:"params"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(conn.String())), &msg); err != nil {
+		t.Fatalf("unmarshal %q: %v", conn.String(), err)
+	}
+	if msg.ID != nil || msg.Method != statsNotifyMethod || len(msg.Params) != 1 {
+		t.Fatalf("unexpected notification: %+v", msg)
+	}
+	p := msg.Params[0]
+	if p["difficulty"] != float64(2048) || p["accepted"] != float64(7) || p["rejected"] != float64(2) {
+		t.Fatalf("unexpected stats params: %v", p)
+	}
+	if _, ok := p["hashrate"]; !ok {
+		t.Fatalf("missing hashrate: %v", p)
+	}
+}
@@ -8,7 +8,7 @@ import (
 	"time"
 )
 
-func benchmarkSubmitJob(b *testing.B) *Job {
+func benchmarkSubmitJob(b testing.TB) *Job {
 	b.Helper()
 
 	const (
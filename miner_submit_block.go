@@ -22,16 +22,37 @@ func (mc *MinerConn) handleBlockShare(reqID any, job *Job, stratumJobID string,
 	if scriptTime == 0 {
 		scriptTime = mc.scriptTimeForJob(stratumJobID, job.ScriptTime)
 	}
+	en1 := mc.extranonce1ForJob(stratumJobID)
 
 	// Only construct the full block (including all non-coinbase transactions)
 	// when the share actually satisfies the network target.
 	if poolScript, workerScript, totalValue, feePercent, ok := mc.dualPayoutParams(job, workerName); ok {
 		var cbTx, cbTxid []byte
 		var err error
-		if job.OperatorDonationPercent > 0 && len(job.DonationScript) > 0 {
+		if len(job.PayoutSplits) > 0 {
+			cbTx, cbTxid, err = serializeMultiCoinbaseTxPredecoded(
+				job.Template.Height,
+				en1,
+				en2,
+				job.TemplateExtraNonce2Size,
+				poolScript,
+				job.DonationScript,
+				workerScript,
+				job.PayoutSplits,
+				totalValue,
+				feePercent,
+				job.OperatorDonationPercent,
+				job.CoinbaseDustThreshold,
+				job.witnessCommitScript,
+				job.extraOpReturnScript,
+				job.coinbaseFlagsBytes,
+				job.CoinbaseMsg,
+				scriptTime,
+			)
+		} else if job.OperatorDonationPercent > 0 && len(job.DonationScript) > 0 {
 			cbTx, cbTxid, err = serializeTripleCoinbaseTxPredecoded(
 				job.Template.Height,
-				mc.extranonce1,
+				en1,
 				en2,
 				job.TemplateExtraNonce2Size,
 				poolScript,
@@ -40,7 +61,9 @@ func (mc *MinerConn) handleBlockShare(reqID any, job *Job, stratumJobID string,
 				totalValue,
 				feePercent,
 				job.OperatorDonationPercent,
+				job.CoinbaseDustThreshold,
 				job.witnessCommitScript,
+				job.extraOpReturnScript,
 				job.coinbaseFlagsBytes,
 				job.CoinbaseMsg,
 				scriptTime,
@@ -48,14 +71,16 @@ func (mc *MinerConn) handleBlockShare(reqID any, job *Job, stratumJobID string,
 		} else {
 			cbTx, cbTxid, err = serializeDualCoinbaseTxPredecoded(
 				job.Template.Height,
-				mc.extranonce1,
+				en1,
 				en2,
 				job.TemplateExtraNonce2Size,
 				poolScript,
 				workerScript,
 				totalValue,
 				feePercent,
+				job.CoinbaseDustThreshold,
 				job.witnessCommitScript,
+				job.extraOpReturnScript,
 				job.coinbaseFlagsBytes,
 				job.CoinbaseMsg,
 				scriptTime,
@@ -96,7 +121,7 @@ func (mc *MinerConn) handleBlockShare(reqID any, job *Job, stratumJobID string,
 		// Fallback to single-output block build if dual-payout params are
 		// unavailable or any step fails. This reuses the existing helper that
 		// constructs a canonical block for submission.
-		blockHex, _, _, _, err = buildBlockWithScriptTime(job, mc.extranonce1, en2, ntime, nonce, int32(useVersion), mc.singlePayoutScript(job, workerName), scriptTime)
+		blockHex, _, _, _, err = buildBlockWithScriptTime(job, en1, en2, ntime, nonce, int32(useVersion), mc.singlePayoutScript(job, workerName), scriptTime)
 		if err != nil {
 			if mc.metrics != nil {
 				mc.metrics.RecordBlockSubmission("error")
@@ -108,11 +133,26 @@ func (mc *MinerConn) handleBlockShare(reqID any, job *Job, stratumJobID string,
 		}
 	}
 
+	// With dedupe_block_submissions, only the first block per height goes to
+	// the node; a later finder at that height is recorded as also-found.
+	claimed := false
+	if mc.cfg.DedupeBlockSubmissions && mc.jobMgr != nil {
+		first, winner := mc.jobMgr.blockClaims.acquire(job.Template.Height, hashHex, blockClaimWaitTimeout)
+		if !first {
+			mc.handleAlsoFoundBlock(reqID, job, workerName, hashHex, winner, shareDiff)
+			return
+		}
+		claimed = true
+	}
+
 	// Submit the block via RPC using an aggressive, no-backoff retry loop
 	// so we race the rest of the network as hard as possible. This path is
 	// intentionally not tied to the miner or process context so shutdown
 	// signals do not cancel in-flight submissions.
 	err = mc.submitBlockWithFastRetry(job, workerName, hashHex, blockHex, &submitRes)
+	if claimed {
+		mc.jobMgr.blockClaims.release(job.Template.Height, hashHex, err == nil)
+	}
 	if err != nil {
 		if mc.metrics != nil {
 			mc.metrics.RecordBlockSubmission("error")
@@ -130,6 +170,7 @@ func (mc *MinerConn) handleBlockShare(reqID any, job *Job, stratumJobID string,
 	if mc.metrics != nil {
 		mc.metrics.RecordBlockSubmission("accepted")
 	}
+	mc.jobMgr.NoteBlockFound()
 
 	// For solo mining, treat the worker that submitted the block as the
 	// beneficiary of the block reward. We always split the reward between
@@ -165,7 +206,15 @@ func (mc *MinerConn) handleBlockShare(reqID any, job *Job, stratumJobID string,
 	if logger.Enabled(logLevelInfo) {
 		stats = mc.snapshotStats()
 	}
-	mc.logFoundBlock(job, workerName, hashHex, shareDiff)
+	var dist *blockRewardDistribution
+	if mc.cfg.RecordRewardDistribution {
+		if d, derr := mc.rewardDistributionFromBlock(job, workerName, blockHex); derr != nil {
+			logger.Warn("decode reward distribution", "component", "miner", "kind", "block", "height", job.Template.Height, "error", derr)
+		} else {
+			dist = d
+		}
+	}
+	mc.logFoundBlock(job, workerName, hashHex, shareDiff, dist)
 	if logger.Enabled(logLevelInfo) {
 		logger.Info("block found",
 			"miner", mc.minerName(workerName),
@@ -181,8 +230,9 @@ func (mc *MinerConn) handleBlockShare(reqID any, job *Job, stratumJobID string,
 
 // logFoundBlock appends a JSON line describing a found block to a log file in
 // the data directory. This is purely for operator audit/debugging and is best
-// effort; failures are logged but do not affect pool operation.
-func (mc *MinerConn) logFoundBlock(job *Job, worker, hashHex string, shareDiff float64) {
+// effort; failures are logged but do not affect pool operation. dist, when
+// non-nil, is the reward split decoded from the submitted block.
+func (mc *MinerConn) logFoundBlock(job *Job, worker, hashHex string, shareDiff float64, dist *blockRewardDistribution) {
 	dir := mc.cfg.DataDir
 	if dir == "" {
 		dir = defaultDataDir
@@ -241,6 +291,10 @@ func (mc *MinerConn) logFoundBlock(job *Job, worker, hashHex string, shareDiff f
 		"worker_payout_sats":   workerAmt,
 		"dual_payout_fallback": dualFallback,
 	}
+	if dist != nil {
+		rec["coinbase_txid"] = dist.CoinbaseTxid
+		rec["reward_distribution"] = dist
+	}
 	data, err := fastJSONMarshal(rec)
 	if err != nil {
 		logger.Warn("found block log marshal", "error", err)
@@ -258,6 +312,50 @@ func (mc *MinerConn) logFoundBlock(job *Job, worker, hashHex string, shareDiff f
 	mc.notifyDiscordFoundBlock(workerName, job.Template.Height, hashHex, now)
 }
 
+// handleAlsoFoundBlock records a valid block that was not submitted because
+// another connection's block for the same height (winner) was accepted
+// first. The share is still a valid share and is answered as accepted. The
+// record keeps the finder visible for accounting; with solo payouts only the
+// submitted block's coinbase pays out.
+func (mc *MinerConn) handleAlsoFoundBlock(reqID any, job *Job, workerName, hashHex, winner string, shareDiff float64) {
+	height := job.Template.Height
+	if strings.EqualFold(hashHex, winner) {
+		logger.Info("block already submitted; ignoring repeat", "component", "miner", "kind", "block",
+			"miner", mc.minerName(workerName), "height", height, "hash", hashHex)
+		mc.writeTrueResponse(reqID)
+		return
+	}
+	logger.Warn("also-found block not submitted; another block at this height was accepted first",
+		"component", "miner", "kind", "block",
+		"miner", mc.minerName(workerName),
+		"height", height,
+		"hash", hashHex,
+		"submitted_hash", winner,
+	)
+	rec := map[string]any{
+		"timestamp":          time.Now().UTC(),
+		"height":             height,
+		"hash":               hashHex,
+		"worker":             mc.minerName(workerName),
+		"share_diff":         shareDiff,
+		"job_id":             job.JobID,
+		"also_found":         true,
+		"also_found_for":     winner,
+		"pool_fee_sats":      0,
+		"worker_payout_sats": 0,
+	}
+	if data, err := fastJSONMarshal(rec); err != nil {
+		logger.Warn("found block log marshal", "error", err)
+	} else {
+		select {
+		case foundBlockLogCh <- foundBlockLogEntry{Dir: mc.cfg.DataDir, Line: append(data, '\n')}:
+		default:
+			logger.Warn("found block log queue full; dropping entry")
+		}
+	}
+	mc.writeTrueResponse(reqID)
+}
+
 func (mc *MinerConn) notifyDiscordFoundBlock(worker string, height int64, hashHex string, now time.Time) {
 	if mc == nil || mc.discordNotifier == nil {
 		return
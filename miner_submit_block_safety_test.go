@@ -183,6 +183,7 @@ func TestWinningBlockUsesNotifiedScriptTime(t *testing.T) {
 			payoutScript,
 			job.CoinbaseValue,
 			job.witnessCommitScript,
+			job.extraOpReturnScript,
 			job.coinbaseFlagsBytes,
 			job.CoinbaseMsg,
 			notifiedScriptTime,
@@ -212,6 +213,7 @@ func TestWinningBlockUsesNotifiedScriptTime(t *testing.T) {
 			payoutScript,
 			job.CoinbaseValue,
 			job.witnessCommitScript,
+			job.extraOpReturnScript,
 			job.coinbaseFlagsBytes,
 			job.CoinbaseMsg,
 			job.ScriptTime,
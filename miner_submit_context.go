@@ -21,6 +21,10 @@ func (mc *MinerConn) prepareShareContext(task submissionTask) (shareContext, boo
 	if scriptTime == 0 {
 		scriptTime = mc.scriptTimeForJob(jobID, job.ScriptTime)
 	}
+	en1 := task.extranonce1
+	if en1 == nil {
+		en1 = mc.extranonce1ForJob(jobID)
+	}
 
 	var (
 		header           []byte
@@ -33,10 +37,30 @@ func (mc *MinerConn) prepareShareContext(task submissionTask) (shareContext, boo
 	)
 
 	if poolScript, workerScript, totalValue, feePercent, ok := mc.dualPayoutParams(job, workerName); ok {
-		if job.OperatorDonationPercent > 0 && len(job.DonationScript) > 0 {
+		if len(job.PayoutSplits) > 0 {
+			cbTx, cbTxid, err = serializeMultiCoinbaseTxPredecoded(
+				job.Template.Height,
+				en1,
+				en2,
+				job.TemplateExtraNonce2Size,
+				poolScript,
+				job.DonationScript,
+				workerScript,
+				job.PayoutSplits,
+				totalValue,
+				feePercent,
+				job.OperatorDonationPercent,
+				job.CoinbaseDustThreshold,
+				job.witnessCommitScript,
+				job.extraOpReturnScript,
+				job.coinbaseFlagsBytes,
+				job.CoinbaseMsg,
+				scriptTime,
+			)
+		} else if job.OperatorDonationPercent > 0 && len(job.DonationScript) > 0 {
 			cbTx, cbTxid, err = serializeTripleCoinbaseTxPredecoded(
 				job.Template.Height,
-				mc.extranonce1,
+				en1,
 				en2,
 				job.TemplateExtraNonce2Size,
 				poolScript,
@@ -45,7 +69,9 @@ func (mc *MinerConn) prepareShareContext(task submissionTask) (shareContext, boo
 				totalValue,
 				feePercent,
 				job.OperatorDonationPercent,
+				job.CoinbaseDustThreshold,
 				job.witnessCommitScript,
+				job.extraOpReturnScript,
 				job.coinbaseFlagsBytes,
 				job.CoinbaseMsg,
 				scriptTime,
@@ -53,14 +79,16 @@ func (mc *MinerConn) prepareShareContext(task submissionTask) (shareContext, boo
 		} else {
 			cbTx, cbTxid, err = serializeDualCoinbaseTxPredecoded(
 				job.Template.Height,
-				mc.extranonce1,
+				en1,
 				en2,
 				job.TemplateExtraNonce2Size,
 				poolScript,
 				workerScript,
 				totalValue,
 				feePercent,
+				job.CoinbaseDustThreshold,
 				job.witnessCommitScript,
+				job.extraOpReturnScript,
 				job.coinbaseFlagsBytes,
 				job.CoinbaseMsg,
 				scriptTime,
@@ -90,12 +118,13 @@ func (mc *MinerConn) prepareShareContext(task submissionTask) (shareContext, boo
 		}
 		cbTx, cbTxid, err = serializeCoinbaseTxPredecoded(
 			job.Template.Height,
-			mc.extranonce1,
+			en1,
 			en2,
 			job.TemplateExtraNonce2Size,
 			mc.singlePayoutScript(job, workerName),
 			job.CoinbaseValue,
 			job.witnessCommitScript,
+			job.extraOpReturnScript,
 			job.coinbaseFlagsBytes,
 			job.CoinbaseMsg,
 			scriptTime,
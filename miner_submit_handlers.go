@@ -7,12 +7,20 @@ func (mc *MinerConn) handleSubmit(req *StratumRequest) {
 	// [worker_name, job_id, extranonce2, ntime, nonce]
 	now := time.Now()
 
+	if !mc.verifySubmitHMAC(req, now) {
+		return
+	}
 	task, ok := mc.prepareSubmissionTask(req, now)
 	if !ok {
 		return
 	}
+	// A verified proxy aggregates many miners, so its submit rate is not
+	// held to the per-connection share flood limit.
+	if len(mc.submitHMACKey) == 0 {
+		mc.noteSubmitForFloodGuard(now)
+	}
 	if mc.cfg.SubmitProcessInline {
-		mc.processSubmissionTask(task)
+		runSubmissionTask(task, -1)
 		return
 	}
 	ensureSubmissionWorkerPool()
@@ -21,12 +29,23 @@ func (mc *MinerConn) handleSubmit(req *StratumRequest) {
 
 func (mc *MinerConn) handleSubmitStringParams(id any, params []string) {
 	now := time.Now()
+	if len(mc.submitHMACKey) > 0 {
+		// String-param submits carry no HMAC, so they are always rejected
+		// on the proxy listener.
+		anyParams := make([]any, len(params))
+		for i, p := range params {
+			anyParams[i] = p
+		}
+		mc.verifySubmitHMAC(&StratumRequest{ID: id, Method: "mining.submit", Params: anyParams}, now)
+		return
+	}
 	task, ok := mc.prepareSubmissionTaskStringParams(id, params, now)
 	if !ok {
 		return
 	}
+	mc.noteSubmitForFloodGuard(now)
 	if mc.cfg.SubmitProcessInline {
-		mc.processSubmissionTask(task)
+		runSubmissionTask(task, -1)
 		return
 	}
 	ensureSubmissionWorkerPool()
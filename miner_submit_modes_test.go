@@ -926,6 +926,51 @@ func TestPrepareSubmissionTask_StaleAndFallbackFreshnessModes(t *testing.T) {
 			wantOK:           true,
 			wantPolicyReason: rejectStaleJob,
 		},
+		{
+			name: "strict degraded-feed policy marks shares stale while refreshes fail",
+			configure: func(mc *MinerConn, job *Job) {
+				mc.cfg.DegradedFeedSharePolicy = degradedFeedShareStrict
+				mc.jobMgr = &JobManager{curJob: job, lastErr: fmt.Errorf("getblocktemplate failed")}
+			},
+			wantOK:           true,
+			wantPolicyReason: rejectStaleJob,
+		},
+		{
+			name: "lenient degraded-feed policy keeps accepting shares",
+			configure: func(mc *MinerConn, job *Job) {
+				mc.cfg.DegradedFeedSharePolicy = degradedFeedShareLenient
+				mc.jobMgr = &JobManager{curJob: job, lastErr: fmt.Errorf("getblocktemplate failed")}
+			},
+			wantOK:           true,
+			wantPolicyReason: rejectUnknown,
+		},
+		{
+			name: "strict degraded-feed policy ignores a healthy feed",
+			configure: func(mc *MinerConn, job *Job) {
+				mc.cfg.DegradedFeedSharePolicy = degradedFeedShareStrict
+				mc.jobMgr = &JobManager{curJob: job}
+			},
+			wantOK:           true,
+			wantPolicyReason: rejectUnknown,
+		},
+		{
+			name: "max job age marks shares for an old cached job stale",
+			configure: func(mc *MinerConn, job *Job) {
+				mc.cfg.ShareMaxJobAgeSeconds = 600
+				job.CreatedAt = time.Unix(1700000000, 0).Add(-11 * time.Minute)
+			},
+			wantOK:           true,
+			wantPolicyReason: rejectStaleJob,
+		},
+		{
+			name: "max job age accepts a job inside the limit",
+			configure: func(mc *MinerConn, job *Job) {
+				mc.cfg.ShareMaxJobAgeSeconds = 600
+				job.CreatedAt = time.Unix(1700000000, 0).Add(-9 * time.Minute)
+			},
+			wantOK:           true,
+			wantPolicyReason: rejectUnknown,
+		},
 	}
 
 	for _, tc := range cases {
@@ -952,3 +997,43 @@ func TestPrepareSubmissionTask_StaleAndFallbackFreshnessModes(t *testing.T) {
 		})
 	}
 }
+
+func TestPrepareSubmissionTask_NTimeRefreshCurrentJob(t *testing.T) {
+	mc, job := newSubmitReadyMinerConnForModesTest(t)
+	mc.cfg.ShareCheckNTimeWindow = true
+	mc.cfg.ShareNTimeRefreshCurrentJob = true
+	mc.cfg.ShareNTimeMaxForwardSeconds = 600
+	mc.lastJobID = job.JobID
+	mc.jobNTimeBounds = map[string]jobNTimeBounds{
+		job.JobID: {min: 1700000000, max: 1700000600},
+	}
+	baseReq := testSubmitRequestForJob(job, mc.currentWorker())
+
+	// A long-lived current job accepts an ntime that has rolled forward
+	// with real time, well past the window set at notify.
+	req := cloneSubmitReq(baseReq)
+	req.Params[3] = uint32ToHex8Lower(uint32(time.Now().Unix()))
+	task, ok := mc.prepareSubmissionTask(req, time.Now())
+	if !ok || task.policyReject.reason != rejectUnknown {
+		t.Fatalf("expected refreshed window to accept current ntime, ok=%v policy=%+v", ok, task.policyReject)
+	}
+	if got := mc.jobNTimeBounds[job.JobID]; got.min != 1700000000 || got.max < time.Now().Unix() {
+		t.Fatalf("unexpected refreshed bounds %+v", got)
+	}
+
+	// The mintime floor is unchanged.
+	req = cloneSubmitReq(baseReq)
+	req.Params[3] = "6553f0ff" // 1699999999
+	if task, _ := mc.prepareSubmissionTask(req, time.Now()); task.policyReject.reason != rejectInvalidNTime {
+		t.Fatalf("expected ntime below mintime floor to stay rejected, got %+v", task.policyReject)
+	}
+
+	// Jobs other than the current one keep their original window.
+	mc.lastJobID = "newer-job"
+	mc.jobNTimeBounds[job.JobID] = jobNTimeBounds{min: 1700000000, max: 1700000600}
+	req = cloneSubmitReq(baseReq)
+	req.Params[3] = uint32ToHex8Lower(uint32(time.Now().Unix()))
+	if task, _ := mc.prepareSubmissionTask(req, time.Now()); task.policyReject.reason != rejectInvalidNTime {
+		t.Fatalf("expected non-current job window not to be refreshed, got %+v", task.policyReject)
+	}
+}
@@ -282,6 +282,20 @@ func (mc *MinerConn) prepareSubmissionTaskFromParsed(reqID any, params submitPar
 	submittedVersion := params.submittedVersion
 	validateFields := mc.cfg.ShareCheckParamFormat
 
+	// Pipelined requests are handled strictly in arrival order, so a submit
+	// sent before subscribe/authorize sees the state as of that line and is
+	// rejected (and counted), never dropped or reordered. A connection that
+	// authorized before subscribing has no extranonce1 or job yet, so its
+	// submits are refused the same way whatever the setting.
+	if (mc.cfg.ShareRequireSubscribedConnection || mc.authorized) && !mc.subscribed {
+		logger.Debug("submit rejected: not subscribed", "remote", mc.id)
+		mc.recordShare(worker, false, 0, 0, "not subscribed", "", nil, now)
+		if mc.metrics != nil {
+			mc.metrics.RecordSubmitError("not subscribed")
+		}
+		mc.writeResponse(StratumResponse{ID: reqID, Result: false, Error: newStratumError(stratumErrCodeNotSubscribed, "not subscribed")})
+		return submissionTask{}, false
+	}
 	if mc.cfg.ShareRequireAuthorizedConnection && !mc.authorized {
 		logger.Debug("submit rejected: unauthorized", "remote", mc.id)
 		mc.recordShare(worker, false, 0, 0, "unauthorized", "", nil, now)
@@ -318,7 +332,7 @@ func (mc *MinerConn) prepareSubmissionTaskFromParsed(reqID any, params submitPar
 		return submissionTask{}, false
 	}
 
-	job, curLast, curPrevHash, curHeight, ntimeBounds, notifiedScriptTime, ok := mc.jobForIDWithLast(jobID)
+	job, curLast, curPrevHash, curHeight, ntimeBounds, notifiedScriptTime, notifiedExtranonce1, ok := mc.jobForIDWithLast(jobID)
 	usedFallbackJob := false
 	if !ok || job == nil {
 		if shareJobFreshnessChecksJobID(mc.cfg.ShareJobFreshnessMode) {
@@ -351,6 +365,18 @@ func (mc *MinerConn) prepareSubmissionTaskFromParsed(reqID any, params submitPar
 		logger.Warn("submit: stale job mismatch (policy)", "remote", mc.id, "job", jobID, "expected_prev", job.Template.Previous, "expected_height", job.Template.Height, "current_prev", curPrevHash, "current_height", curHeight)
 		policyReject = submitPolicyReject{reason: rejectStaleJob, errCode: stratumErrCodeJobNotFound, errMsg: "job not found"}
 	}
+	if policyReject.reason == rejectUnknown && mc.rejectSharesOnDegradedFeed() {
+		// Policy-only like the checks above: a share that solves a block is
+		// still submitted even while the feed is degraded.
+		logger.Debug("submit: job feed degraded (policy)", "remote", mc.id, "job", jobID)
+		policyReject = submitPolicyReject{reason: rejectStaleJob, errCode: stratumErrCodeJobNotFound, errMsg: "job not found"}
+	}
+	if policyReject.reason == rejectUnknown && mc.cfg.ShareMaxJobAgeSeconds > 0 && !job.CreatedAt.IsZero() {
+		if age := now.Sub(job.CreatedAt); age > time.Duration(mc.cfg.ShareMaxJobAgeSeconds)*time.Second {
+			logger.Debug("submit: job too old (policy)", "remote", mc.id, "job", jobID, "age", age.Round(time.Second))
+			policyReject = submitPolicyReject{reason: rejectStaleJob, errCode: stratumErrCodeJobNotFound, errMsg: "job not found"}
+		}
+	}
 
 	en2Small, en2Len, en2Large, err := decodeExtranonce2Hex(extranonce2, validateFields, job.Extranonce2Size)
 	if err != nil {
@@ -400,7 +426,7 @@ func (mc *MinerConn) prepareSubmissionTaskFromParsed(reqID any, params submitPar
 
 	// BIP320: reject version rolls outside the negotiated mask (docs/protocols/bip-0320.mediawiki).
 	baseVersion := uint32(job.Template.Version)
-	useVersion, versionDiff := resolveSubmittedVersion(baseVersion, submittedVersion, mc.versionMask, mc.cfg.ShareAllowVersionMaskMismatch)
+	useVersion, versionDiff := mc.resolveConnSubmittedVersion(baseVersion, submittedVersion)
 
 	versionHex := ""
 	if debugLogging || verboseRuntimeLogging {
@@ -466,6 +492,7 @@ func (mc *MinerConn) prepareSubmissionTaskFromParsed(reqID any, params submitPar
 		versionHex:         versionHex,
 		useVersion:         useVersion,
 		scriptTime:         notifiedScriptTime,
+		extranonce1:        notifiedExtranonce1,
 		assignedDifficulty: mc.assignedDifficulty(jobID),
 		policyReject:       policyReject,
 		receivedAt:         now,
@@ -37,13 +37,15 @@ func (mc *MinerConn) processSubmissionTask(task submissionTask) {
 	}
 
 	ctx, ok := mc.prepareShareContext(task)
-	if !ok {
-		return
+	accepted := ok && mc.processShare(task, ctx)
+	if rec := getShareRecorder(); rec != nil {
+		rec.record(mc, task, accepted)
 	}
-	mc.processShare(task, ctx)
 }
 
-func (mc *MinerConn) processShare(task submissionTask, ctx shareContext) {
+// processShare applies the share checks and credits or rejects the share.
+// It reports whether the share was accepted (block candidates included).
+func (mc *MinerConn) processShare(task submissionTask, ctx shareContext) bool {
 	job := task.job
 	workerName := task.workerName
 	jobID := task.jobID
@@ -66,8 +68,11 @@ func (mc *MinerConn) processShare(task submissionTask, ctx shareContext) {
 	}
 
 	if !ctx.isBlock && policyReject.reason != rejectUnknown {
+		if policyReject.reason == rejectInvalidVersionMask && mc.handleVersionMaskReject(reqID, workerName, now) {
+			return false
+		}
 		mc.rejectShareWithBan(&StratumRequest{ID: reqID, Method: "mining.submit"}, workerName, policyReject.reason, policyReject.errCode, policyReject.errMsg, now)
-		return
+		return false
 	}
 
 	if !ctx.isBlock && mc.cfg.ShareCheckDuplicate && mc.isDuplicateShare(jobID, (&task).extranonce2Decoded(), task.ntimeVal, task.nonceVal, task.useVersion) {
@@ -98,7 +103,11 @@ func (mc *MinerConn) processShare(task submissionTask, ctx shareContext) {
 			"version", verLog,
 		)
 		mc.rejectShareWithBan(&StratumRequest{ID: reqID, Method: "mining.submit"}, workerName, rejectDuplicateShare, stratumErrCodeDuplicateShare, "duplicate share", now)
-		return
+		return false
+	}
+
+	if !ctx.isBlock {
+		mc.noteSubmitNonce(workerName, task.nonceVal, now)
 	}
 
 	thresholdDiff := assignedDiff
@@ -149,7 +158,7 @@ func (mc *MinerConn) processShare(task submissionTask, ctx shareContext) {
 				Error:  []any{stratumErrCodeLowDiffShare, fmt.Sprintf("low difficulty share (%.6g expected %.6g)", ctx.shareDiff, assignedDiff), nil},
 			})
 		}
-		return
+		return false
 	}
 
 	shareHash := ctx.hashHex
@@ -164,7 +173,7 @@ func (mc *MinerConn) processShare(task submissionTask, ctx shareContext) {
 		mc.trackBestShare(workerName, shareHash, ctx.shareDiff, now)
 		mc.maybeUpdateSavedWorkerMinuteBestDiff(ctx.shareDiff, now)
 		mc.maybeUpdateSavedWorkerBestDiff(ctx.shareDiff)
-		return
+		return true
 	}
 
 	mc.noteValidSubmit(now)
@@ -175,6 +184,7 @@ func (mc *MinerConn) processShare(task submissionTask, ctx shareContext) {
 	mc.writeTrueResponse(reqID)
 
 	mc.trackBestShare(workerName, shareHash, ctx.shareDiff, now)
+	mc.noteNearMiss(job, workerName, shareHash, ctx.shareDiff, now)
 	mc.maybeUpdateSavedWorkerMinuteBestDiff(ctx.shareDiff, now)
 	mc.maybeUpdateSavedWorkerBestDiff(ctx.shareDiff)
 
@@ -204,4 +214,5 @@ func (mc *MinerConn) processShare(task submissionTask, ctx shareContext) {
 			"submit_rate_per_min", subRate,
 		)
 	}
+	return true
 }
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func solveSubscribePoW(t *testing.T, challenge []byte, minBits int) []byte {
+	t.Helper()
+	nonce := make([]byte, 8)
+	for i := uint64(0); i < 1<<24; i++ {
+		binary.LittleEndian.PutUint64(nonce, i)
+		if verifySubscribePoW(challenge, nonce, minBits) {
+			return nonce
+		}
+	}
+	t.Fatalf("no solution found for %d bits", minBits)
+	return nil
+}
+
+func TestVerifySubscribePoW(t *testing.T) {
+	challenge := []byte("0123456789abcdef")
+	nonce := solveSubscribePoW(t, challenge, 8)
+	if !verifySubscribePoW(challenge, nonce, 8) {
+		t.Fatalf("expected solution to verify")
+	}
+	if verifySubscribePoW([]byte("fedcba9876543210"), nonce, 8) && verifySubscribePoW([]byte("fedcba9876543211"), nonce, 8) {
+		t.Fatalf("solution should be bound to its challenge")
+	}
+	if verifySubscribePoW(nil, nonce, 8) {
+		t.Fatalf("empty challenge must not verify")
+	}
+	if got := leadingZeroBits([]byte{0x00, 0x1f, 0xff}); got != 11 {
+		t.Fatalf("leadingZeroBits = %d, want 11", got)
+	}
+}
+
+func TestMinerConn_SubscribeRequiresChallenge(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+
+	mc := &MinerConn{
+		id:               "test",
+		ctx:              context.Background(),
+		conn:             server,
+		reader:           bufio.NewReader(server),
+		cfg:              Config{ConnectionTimeout: time.Hour},
+		lastActivity:     time.Now(),
+		subscribePoWBits: 8,
+	}
+	done := make(chan struct{})
+	go func() {
+		mc.handle()
+		close(done)
+	}()
+	_ = client.SetDeadline(time.Now().Add(5 * time.Second))
+
+	br := bufio.NewReader(client)
+	readMsg := func() map[string]any {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		var msg map[string]any
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			t.Fatalf("unmarshal %q: %v", line, err)
+		}
+		return msg
+	}
+
+	challengeMsg := readMsg()
+	if challengeMsg["method"] != "mining.challenge" {
+		t.Fatalf("expected mining.challenge first, got %#v", challengeMsg)
+	}
+	params, _ := challengeMsg["params"].([]any)
+	if len(params) != 2 {
+		t.Fatalf("unexpected challenge params %#v", challengeMsg["params"])
+	}
+	challenge, err := hex.DecodeString(params[0].(string))
+	if err != nil {
+		t.Fatalf("challenge hex: %v", err)
+	}
+	nonce := solveSubscribePoW(t, challenge, int(params[1].(float64)))
+
+	if _, err := io.WriteString(client, `{"id":1,"method":"mining.challenge_response","params":["`+hex.EncodeToString(nonce)+`"]}`+"\n"); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if resp := readMsg(); resp["result"] != true {
+		t.Fatalf("expected challenge accepted, got %#v", resp)
+	}
+	if !mc.subscribeChallengeSatisfied() {
+		t.Fatalf("expected challenge to be marked solved")
+	}
+
+	_ = client.Close()
+	_ = server.Close()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("miner conn did not exit")
+	}
+}
+
+func TestMinerConn_SubscribeWithoutChallengeRejected(t *testing.T) {
+	conn := &writeRecorderConn{}
+	mc := &MinerConn{
+		id:                    "pow-miner",
+		conn:                  conn,
+		subscribePoWBits:      8,
+		subscribePoWChallenge: []byte("0123456789abcdef"),
+	}
+	mc.handleSubscribeID(1, "", false, "", false)
+	if mc.subscribed {
+		t.Fatalf("subscribe should be refused before the challenge is solved")
+	}
+	if !conn.closed {
+		t.Fatalf("expected connection to be closed")
+	}
+	if out := conn.String(); !strings.Contains(out, "proof-of-work challenge required") {
+		t.Fatalf("expected challenge error response, got %q", out)
+	}
+}
@@ -330,3 +330,57 @@ func TestSuggestTargetEmptyIsIgnoredAndDoesNotBan(t *testing.T) {
 		t.Fatalf("expected result=true response, got: %q", out)
 	}
 }
+
+func TestSuggestTargetInvalidLengthOrZeroReturnsError(t *testing.T) {
+	for name, target := range map[string]string{
+		"short":    "00000000ffff",
+		"long":     strings.Repeat("0", 65) + "1",
+		"non-hex":  strings.Repeat("z", 64),
+		"all-zero": strings.Repeat("0", 64),
+	} {
+		t.Run(name, func(t *testing.T) {
+			conn := &writeRecorderConn{}
+			mc := &MinerConn{
+				id:           "suggest-target-invalid-miner",
+				cfg:          Config{MinDifficulty: 1.0, MaxDifficulty: 2.0, EnforceSuggestedDifficultyLimits: true},
+				conn:         conn,
+				statsUpdates: make(chan statsUpdate),
+			}
+			mc.suggestTarget(&StratumRequest{ID: 1, Method: "mining.suggest_target", Params: []any{target}})
+
+			if conn.closed {
+				t.Fatalf("did not expect miner connection to be closed")
+			}
+			if out := conn.String(); !strings.Contains(out, "invalid target") {
+				t.Fatalf("expected invalid target error, got: %q", out)
+			}
+			if mc.suggestDiffProcessed {
+				t.Fatalf("invalid target should not be applied")
+			}
+		})
+	}
+}
+
+func TestSuggestTargetAppliesClampedDifficultyAndLock(t *testing.T) {
+	conn := &writeRecorderConn{}
+	mc := &MinerConn{
+		id:           "suggest-target-apply-miner",
+		cfg:          Config{MinDifficulty: 1.0, MaxDifficulty: 2.0, LockSuggestedDifficulty: true},
+		conn:         conn,
+		statsUpdates: make(chan statsUpdate),
+	}
+
+	// diff=8 is above max=2 but enforcement is off, so it is clamped.
+	target := targetFromDifficulty(8)
+	mc.suggestTarget(&StratumRequest{ID: 1, Method: "mining.suggest_target", Params: []any{fmt.Sprintf("0x%064x", target)}})
+
+	if out := conn.String(); !strings.Contains(out, "\"result\":true") {
+		t.Fatalf("expected result=true response, got: %q", out)
+	}
+	if !mc.lockDifficulty {
+		t.Fatalf("expected lock_suggested_difficulty to lock the connection")
+	}
+	if got := mc.currentDifficulty(); got != 2 {
+		t.Fatalf("difficulty = %v, want clamped 2", got)
+	}
+}
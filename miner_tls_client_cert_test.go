@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestClientCert returns a self-signed client certificate; its PEM is
+// also usable as the CA bundle that trusts it.
+func newTestClientCert(t *testing.T) (tls.Certificate, []byte) {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-miner"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatalf("create cert: %v", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: priv},
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func newClientCertTestServer(t *testing.T, caPEM []byte) (*MinerConn, *tls.Conn, net.Conn) {
+	t.Helper()
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "cert.pem")
+	keyPath := filepath.Join(dir, "key.pem")
+	caPath := filepath.Join(dir, "client_ca.pem")
+	if err := generateTestCert(certPath, keyPath); err != nil {
+		t.Fatalf("generate cert: %v", err)
+	}
+	if err := os.WriteFile(caPath, caPEM, 0o644); err != nil {
+		t.Fatalf("write ca: %v", err)
+	}
+	cr, err := newCertReloader(certPath, keyPath)
+	if err != nil {
+		t.Fatalf("cert reloader: %v", err)
+	}
+	tlsCfg, err := stratumTLSConfig(cr, caPath)
+	if err != nil {
+		t.Fatalf("stratumTLSConfig: %v", err)
+	}
+	serverSide, clientSide := net.Pipe()
+	t.Cleanup(func() {
+		_ = serverSide.Close()
+		_ = clientSide.Close()
+	})
+	serverConn := tls.Server(serverSide, tlsCfg)
+	mc := &MinerConn{
+		ctx:             context.Background(),
+		conn:            serverConn,
+		isTLSConnection: true,
+		cfg:             Config{StratumTLSClientCA: caPath, TLSInitialTimeout: 5 * time.Second},
+	}
+	return mc, serverConn, clientSide
+}
+
+func TestStratumTLSConfigClientAuth(t *testing.T) {
+	cfg, err := stratumTLSConfig(&certReloader{}, "")
+	if err != nil {
+		t.Fatalf("stratumTLSConfig: %v", err)
+	}
+	if cfg.ClientAuth != tls.NoClientCert || cfg.ClientCAs != nil {
+		t.Fatalf("client auth should be off without a CA, got %v", cfg.ClientAuth)
+	}
+
+	badCA := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(badCA, []byte("not a certificate"), 0o644); err != nil {
+		t.Fatalf("write ca: %v", err)
+	}
+	if _, err := stratumTLSConfig(&certReloader{}, badCA); err == nil {
+		t.Fatalf("expected error for CA bundle without certificates")
+	}
+}
+
+func TestCompleteTLSHandshakeAcceptsClientCert(t *testing.T) {
+	clientCert, caPEM := newTestClientCert(t)
+	mc, serverConn, clientSide := newClientCertTestServer(t, caPEM)
+
+	go func() {
+		client := tls.Client(clientSide, &tls.Config{InsecureSkipVerify: true, Certificates: []tls.Certificate{clientCert}})
+		_ = client.Handshake()
+	}()
+	if !mc.completeTLSHandshake() {
+		t.Fatalf("expected handshake with a valid client certificate to complete")
+	}
+	if got := len(serverConn.ConnectionState().PeerCertificates); got != 1 {
+		t.Fatalf("expected 1 peer certificate, got %d", got)
+	}
+}
+
+func TestCompleteTLSHandshakeRejectsMissingClientCert(t *testing.T) {
+	_, caPEM := newTestClientCert(t)
+	mc, serverConn, clientSide := newClientCertTestServer(t, caPEM)
+
+	go func() {
+		client := tls.Client(clientSide, &tls.Config{InsecureSkipVerify: true})
+		_ = client.Handshake()
+		// TLS 1.3 clients learn about the rejection on their first read.
+		_, _ = client.Read(make([]byte, 1))
+	}()
+	if mc.completeTLSHandshake() {
+		t.Fatalf("expected handshake without a client certificate to fail")
+	}
+	err := serverConn.HandshakeContext(context.Background())
+	if !isTLSClientCertError(err) {
+		t.Fatalf("expected client certificate error, got %v", err)
+	}
+}
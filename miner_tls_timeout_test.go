@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTLSTestPipe(t *testing.T) (*tls.Conn, net.Conn) {
+	t.Helper()
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "cert.pem")
+	keyPath := filepath.Join(dir, "key.pem")
+	if err := generateTestCert(certPath, keyPath); err != nil {
+		t.Fatalf("generate cert: %v", err)
+	}
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		t.Fatalf("load cert: %v", err)
+	}
+	serverSide, clientSide := net.Pipe()
+	t.Cleanup(func() {
+		_ = serverSide.Close()
+		_ = clientSide.Close()
+	})
+	return tls.Server(serverSide, &tls.Config{Certificates: []tls.Certificate{cert}}), clientSide
+}
+
+func TestInitialReadTimeoutTLSOverride(t *testing.T) {
+	mc := &MinerConn{cfg: Config{TLSInitialTimeout: 3 * time.Minute}}
+	if got := mc.initialReadTimeout(); got != initialReadTimeout {
+		t.Fatalf("plain conn initial timeout = %s, want %s", got, initialReadTimeout)
+	}
+	mc.isTLSConnection = true
+	if got := mc.initialReadTimeout(); got != 3*time.Minute {
+		t.Fatalf("tls conn initial timeout = %s, want 3m", got)
+	}
+	mc.cfg.TLSInitialTimeout = 0
+	if got := mc.initialReadTimeout(); got != initialReadTimeout {
+		t.Fatalf("tls conn without override = %s, want %s", got, initialReadTimeout)
+	}
+}
+
+func TestCompleteTLSHandshake(t *testing.T) {
+	serverConn, clientSide := newTLSTestPipe(t)
+	mc := &MinerConn{ctx: context.Background(), conn: serverConn, isTLSConnection: true}
+
+	go func() {
+		client := tls.Client(clientSide, &tls.Config{InsecureSkipVerify: true})
+		_ = client.Handshake()
+	}()
+	if !mc.completeTLSHandshake() {
+		t.Fatalf("expected handshake to complete")
+	}
+	if !serverConn.ConnectionState().HandshakeComplete {
+		t.Fatalf("handshake state not complete")
+	}
+}
+
+func TestCompleteTLSHandshakeTimesOut(t *testing.T) {
+	serverConn, _ := newTLSTestPipe(t)
+	mc := &MinerConn{
+		ctx:             context.Background(),
+		conn:            serverConn,
+		isTLSConnection: true,
+		cfg:             Config{TLSInitialTimeout: 50 * time.Millisecond},
+	}
+	start := time.Now()
+	if mc.completeTLSHandshake() {
+		t.Fatalf("expected handshake to fail for a silent client")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("handshake deadline not enforced (took %s)", elapsed)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+// maxTrackedWorkerTransports bounds the per-worker transport memory used by
+// track_transport_changes.
+const maxTrackedWorkerTransports = 65536
+
+const (
+	transportTCP = "tcp"
+	transportTLS = "tls"
+)
+
+func (mc *MinerConn) transportLabel() string {
+	if mc.isTLSConnection {
+		return transportTLS
+	}
+	return transportTCP
+}
+
+// noteTransportChange compares the transport this authorize arrived on with
+// the one the same worker used last time (policy
+// [stratum].track_transport_changes). A move from TLS to plain TCP is a
+// downgrade and is logged as a warning; plain TCP to TLS is an upgrade. Both
+// are counted in pool metrics. The connection is never refused.
+func (mc *MinerConn) noteTransportChange(worker, hash string) {
+	cur := mc.transportLabel()
+	prev := mc.workerRegistry.noteTransport(hash, cur)
+	if prev == "" || prev == cur {
+		return
+	}
+	direction := "upgrade"
+	if prev == transportTLS {
+		direction = "downgrade"
+	}
+	if mc.metrics != nil {
+		mc.metrics.RecordTransportChange(direction)
+	}
+	fields := []any{
+		"component", "miner", "kind", "transport",
+		"miner", mc.minerName(worker),
+		"remote", mc.id,
+		"from", prev,
+		"to", cur,
+	}
+	if direction == "downgrade" {
+		logger.Warn("worker reconnected without TLS", fields...)
+		return
+	}
+	logger.Info("worker reconnected over TLS", fields...)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestNoteTransportChangeCountsUpgradesAndDowngrades(t *testing.T) {
+	reg := newWorkerConnectionRegistry()
+	metrics := NewPoolMetrics()
+	hash := workerNameHash("worker1")
+
+	conn := func(tls bool) *MinerConn {
+		return &MinerConn{workerRegistry: reg, metrics: metrics, isTLSConnection: tls, cfg: Config{TrackTransportChanges: true}}
+	}
+
+	conn(true).noteTransportChange("worker1", hash)  // first sighting
+	conn(true).noteTransportChange("worker1", hash)  // same transport
+	conn(false).noteTransportChange("worker1", hash) // downgrade
+	conn(true).noteTransportChange("worker1", hash)  // upgrade
+	conn(false).noteTransportChange("other", workerNameHash("other"))
+
+	up, down := metrics.SnapshotTransportChanges()
+	if up != 1 || down != 1 {
+		t.Fatalf("upgrades=%d downgrades=%d, want 1/1", up, down)
+	}
+}
+
+func TestNoteTransportBounded(t *testing.T) {
+	reg := newWorkerConnectionRegistry()
+	for i := range maxTrackedWorkerTransports + 10 {
+		reg.noteTransport("w"+strconv.Itoa(i), transportTCP)
+	}
+	if n := len(reg.lastTransport); n > maxTrackedWorkerTransports {
+		t.Fatalf("lastTransport grew to %d entries", n)
+	}
+}
@@ -14,6 +14,9 @@ type StratumRequest struct {
 	ID     any    `json:"id"`
 	Method string `json:"method"`
 	Params []any  `json:"params"`
+	// HMAC is the non-standard submit signature sent by a trusted proxy on
+	// the stratum_proxy_listen listener; ignored everywhere else.
+	HMAC string `json:"hmac,omitempty"`
 }
 
 type StratumResponse struct {
@@ -31,6 +34,8 @@ type StratumMessage struct {
 const (
 	// JSON-RPC standard parse error code.
 	stratumErrCodeParseError = -32700
+	// JSON-RPC standard invalid-request code.
+	stratumErrCodeJSONRPCInvalidRequest = -32600
 	// JSON-RPC standard method-not-found code.
 	stratumErrCodeMethodNotFound = -32601
 
@@ -40,6 +45,7 @@ const (
 	stratumErrCodeDuplicateShare = 22
 	stratumErrCodeLowDiffShare   = 23
 	stratumErrCodeUnauthorized   = 24
+	stratumErrCodeNotSubscribed  = 25
 )
 
 func newStratumError(code int, msg string) []any {
@@ -95,6 +101,9 @@ type workerWalletState struct {
 type notifiedCoinbaseParts struct {
 	coinb1 string
 	coinb2 string
+	// extranonce1 is the prefix the coinbase was built with; it outlives a
+	// set_extranonce rotation so in-flight shares still rebuild correctly.
+	extranonce1 []byte
 }
 
 var defaultVarDiff = VarDiffConfig{
@@ -123,6 +132,10 @@ type MinerConn struct {
 	difficulty           atomic.Uint64 // float64 stored as bits
 	previousDifficulty   atomic.Uint64 // float64 stored as bits
 	hintMinDifficulty    atomic.Uint64 // float64 stored as bits; 0 means unset
+	difficultyOverride   atomic.Uint64 // worker_overrides pin as float64 bits; 0 means vardiff
+	savedStaticDiff      atomic.Uint64 // saved-worker static difficulty as float64 bits; 0 means unset
+	floodMinDifficulty   atomic.Uint64 // float64 stored as bits; 0 means no share-flood floor
+	floodFloorUntil      atomic.Int64  // Unix nanos when the share-flood floor expires
 	shareTarget          atomic.Pointer[big.Int]
 	lastDiffChange       atomic.Int64 // Unix nanos
 	stateMu              sync.Mutex
@@ -206,16 +219,43 @@ type MinerConn struct {
 	minerType            string
 	minerClientName      string
 	minerClientVersion   string
-	extranonceSubscribed bool
+	extranonceSubscribed atomic.Bool
+	// extranonceRotatedAt is when extranonce1 was last rotated; guarded by
+	// jobMu along with extranonce1/extranonce1Hex.
+	extranonceRotatedAt time.Time
 	// connectedAt is the time this miner connection was established,
 	// used as the zero point for per-share timing in detail logs.
 	connectedAt time.Time
 	// lastActivity tracks when we last saw a RPC message from this miner.
 	lastActivity time.Time
+	// lifetimeDeadline is when this connection is asked to reconnect (zero
+	// disables); lifetimeReconnectSentAt records when that request was sent.
+	lifetimeDeadline        time.Time
+	lifetimeReconnectSentAt time.Time
+	// traceSampled marks connections picked at accept time for Stratum JSON
+	// trace logging (see [logging].trace_sample_percent).
+	traceSampled bool
+	// subscribePoWBits/subscribePoWChallenge hold the optional anti-spam
+	// challenge for this listener; subscribe is refused until
+	// subscribePoWSolved is set (see miner_subscribe_pow.go).
+	subscribePoWBits      int
+	subscribePoWChallenge []byte
+	subscribePoWSolved    bool
 	// stratumMsgWindowStart/stratumMsgCount track per-connection Stratum message rate.
 	// stratumMsgCount stores weighted half-message units (2 = full message).
 	stratumMsgWindowStart time.Time
 	stratumMsgCount       int
+	// floodWindowStart/floodWindowCount sample the submit rate for the
+	// share-flood difficulty floor (see miner_flood.go).
+	floodWindowStart time.Time
+	floodWindowCount int
+	// nonceCheck batches submit nonces for the optional degenerate-nonce
+	// heuristic (see miner_nonce_check.go).
+	nonceCheck nonceCheckState
+	// versionMaskResyncAt is when mining.set_version_mask was last re-sent to
+	// re-sync a miner rolling outside its mask (see miner_version_resync.go).
+	versionMaskResyncAt            time.Time
+	versionMaskResyncIgnoredWarned bool
 	// invalidWarnedAt/invalidWarnedCount rate-limit client.show_message warnings
 	// when the miner is approaching an invalid-submission ban threshold.
 	invalidWarnedAt    time.Time
@@ -278,7 +318,30 @@ type MinerConn struct {
 	vardiffWindowDifficulty  float64
 	// isTLSConnection tracks whether this miner connected over the TLS listener.
 	isTLSConnection bool
-	connectionSeq   uint64
+	// listener is the label of the Stratum listener that accepted this
+	// connection: "tcp", "tls", "proxy" or "ws".
+	listener string
+	// submitPanics counts recovered panics while processing this
+	// connection's shares (see submit_panic_disconnect_after).
+	submitPanics atomic.Int32
+	// versionConvention backs policy [version].share_version_convention_lock.
+	versionConvention versionConventionTracker
+	// diffSyncMixedWarned limits the worker_difficulty_sync mixed-hardware
+	// warning to once per connection.
+	diffSyncMixedWarned atomic.Bool
+	// writeStartedAt and writeProgressAt (unix nanos, 0 when no write is in
+	// flight) let the write stall watchdog spot a wedged writer.
+	writeStartedAt  atomic.Int64
+	writeProgressAt atomic.Int64
+	// writeErr (guarded by writeMu) is the first failed write. The stream
+	// may end mid-message after it, so later writes return it unsent.
+	writeErr error
+	// notifyHeartbeat backs tuning [stratum].notify_heartbeat_seconds.
+	notifyHeartbeat notifyHeartbeat
+	// submitHMACKey is set on trusted-proxy listener connections; every
+	// mining.submit must then carry a valid HMAC keyed by it.
+	submitHMACKey []byte
+	connectionSeq uint64
 	// sessionID is an optional client-provided token sometimes sent in
 	// mining.subscribe to allow miners/proxies to resume sessions.
 	sessionID string
@@ -694,3 +694,65 @@ func TestSuggestedVardiff_LowHashrateDownshiftNeedsMinimumAcceptedShares(t *test
 		t.Fatalf("got %.8g want %.8g when low-hashrate downshift sample is too small", got, 0.01)
 	}
 }
+
+func TestSuggestedVardiff_StaleFeedbackBiasesDown(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	mc := &MinerConn{
+		cfg: Config{VarDiffStaleFeedbackPercent: 5},
+		vardiff: VarDiffConfig{
+			MinDiff:            1,
+			MaxDiff:            1024,
+			TargetSharesPerMin: 20,
+			AdjustmentWindow:   10 * time.Second,
+			Step:               2,
+			DampingFactor:      1,
+		},
+	}
+	atomicStoreFloat64(&mc.difficulty, 8)
+
+	// Cadence alone is on target at difficulty 8.
+	snap := minerShareSnapshot{
+		Stats: MinerStats{
+			WindowStart:       now.Add(-time.Minute),
+			WindowAccepted:    40,
+			WindowSubmissions: 40,
+		},
+		RollingHashrate:         8 * hashPerShare * 20 / 60,
+		RecentSubmissionSamples: 64,
+	}
+	if got := mc.suggestedVardiff(now, snap); got != 8 {
+		t.Fatalf("expected no move without stale feedback, got %.8g", got)
+	}
+
+	snap.RecentStaleRate = 0.30
+	if got := mc.suggestedVardiff(now, snap); got >= 8 {
+		t.Fatalf("expected stale feedback to lower difficulty, got %.8g", got)
+	}
+
+	// Too few samples, or feedback disabled, leaves difficulty alone.
+	snap.RecentSubmissionSamples = vardiffStaleFeedbackMinSamples - 1
+	if got := mc.suggestedVardiff(now, snap); got != 8 {
+		t.Fatalf("expected no move with too few samples, got %.8g", got)
+	}
+	snap.RecentSubmissionSamples = 64
+	mc.cfg.VarDiffStaleFeedbackPercent = 0
+	if got := mc.suggestedVardiff(now, snap); got != 8 {
+		t.Fatalf("expected no move with feedback disabled, got %.8g", got)
+	}
+}
+
+func TestStaleFeedbackOnlyCountsTimingRejects(t *testing.T) {
+	mc := &MinerConn{}
+	for i := 0; i < 40; i++ {
+		mc.observeRecentSubmitOutcomeLocked(false, "invalid nonce")
+	}
+	if rate := mc.recentStaleRateLocked(); rate != 0 {
+		t.Fatalf("bad-nonce rejects must not count as stale, rate=%v", rate)
+	}
+	for i := 0; i < 40; i++ {
+		mc.observeRecentSubmitOutcomeLocked(false, "stale job")
+	}
+	if rate := mc.recentStaleRateLocked(); rate != 0.5 {
+		t.Fatalf("expected stale rate 0.5, got %v", rate)
+	}
+}
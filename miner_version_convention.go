@@ -0,0 +1,106 @@
+package main
+
+// versionConventionLockSamples is how many consecutive version-rolled
+// submits in one convention a connection needs before
+// share_version_convention_lock pins it.
+const versionConventionLockSamples = 16
+
+// versionConvention is how a miner encodes the mining.submit version field.
+type versionConvention uint8
+
+const (
+	versionConventionUnknown versionConvention = iota
+	// versionConventionDelta submits rolled_version ^ base_version
+	// (ESP-Miner/AxeOS and most BIP310 firmware).
+	versionConventionDelta
+	// versionConventionFull submits the whole rolled version.
+	versionConventionFull
+)
+
+func (c versionConvention) String() string {
+	switch c {
+	case versionConventionDelta:
+		return "delta"
+	case versionConventionFull:
+		return "full"
+	default:
+		return "unknown"
+	}
+}
+
+// classifySubmittedVersion reports which convention a submitted version
+// unambiguously follows: a value inside the mask is a delta, and a value that
+// differs from the job version only inside the mask is a full version.
+// Anything else (including 0) is unknown.
+func classifySubmittedVersion(baseVersion, submittedVersion, versionMask uint32) versionConvention {
+	switch {
+	case submittedVersion == 0:
+		return versionConventionUnknown
+	case submittedVersion&^versionMask == 0:
+		return versionConventionDelta
+	case (submittedVersion^baseVersion)&^versionMask == 0:
+		return versionConventionFull
+	default:
+		return versionConventionUnknown
+	}
+}
+
+// versionConventionTracker pins a connection to the convention it used for
+// versionConventionLockSamples consecutive submits. It is only touched from
+// the connection's read loop, where submits are parsed.
+type versionConventionTracker struct {
+	candidate versionConvention
+	streak    int
+	locked    versionConvention
+}
+
+// observe feeds one classified submit and reports whether it just locked.
+func (t *versionConventionTracker) observe(c versionConvention) bool {
+	if t.locked != versionConventionUnknown || c == versionConventionUnknown {
+		return false
+	}
+	if c != t.candidate {
+		t.candidate = c
+		t.streak = 0
+	}
+	t.streak++
+	if t.streak < versionConventionLockSamples {
+		return false
+	}
+	t.locked = c
+	return true
+}
+
+// resolveConnSubmittedVersion is resolveSubmittedVersion plus the optional
+// per-connection convention lock. Until the lock engages, the mask heuristic
+// decides every submit exactly as before; afterwards the locked convention
+// wins, so one anomalous value is judged (and most likely rejected) under
+// the convention the miner actually uses.
+func (mc *MinerConn) resolveConnSubmittedVersion(baseVersion, submittedVersion uint32) (useVersion, versionDiff uint32) {
+	if !mc.cfg.ShareVersionConventionLock || submittedVersion == 0 {
+		return resolveSubmittedVersion(baseVersion, submittedVersion, mc.versionMask, mc.cfg.ShareAllowVersionMaskMismatch)
+	}
+	class := classifySubmittedVersion(baseVersion, submittedVersion, mc.versionMask)
+	switch mc.versionConvention.locked {
+	case versionConventionDelta, versionConventionFull:
+		if class != versionConventionUnknown && class != mc.versionConvention.locked {
+			logger.Debug("submit version convention mismatch; keeping locked convention",
+				"remote", mc.id,
+				"locked", mc.versionConvention.locked.String(),
+				"observed", class.String(),
+				"version", uint32ToHex8Lower(submittedVersion))
+		}
+		if mc.versionConvention.locked == versionConventionDelta {
+			return baseVersion ^ submittedVersion, submittedVersion
+		}
+		return submittedVersion, submittedVersion ^ baseVersion
+	}
+	if mc.versionConvention.observe(class) {
+		logger.Info("submit version convention locked",
+			"component", "miner", "kind", "version_rolling",
+			"remote", mc.id,
+			"convention", class.String(),
+			"samples", versionConventionLockSamples)
+	}
+	return resolveSubmittedVersion(baseVersion, submittedVersion, mc.versionMask, mc.cfg.ShareAllowVersionMaskMismatch)
+}
@@ -0,0 +1,63 @@
+package main
+
+import "testing"
+
+func TestClassifySubmittedVersion(t *testing.T) {
+	const base, mask = uint32(0x20000000), uint32(0x1fffe000)
+	cases := []struct {
+		submitted uint32
+		want      versionConvention
+	}{
+		{0, versionConventionUnknown},
+		{0x00002000, versionConventionDelta},
+		{0x20002000, versionConventionFull},
+		{0x20000010, versionConventionUnknown},
+	}
+	for _, c := range cases {
+		if got := classifySubmittedVersion(base, c.submitted, mask); got != c.want {
+			t.Fatalf("classify(%08x) = %v, want %v", c.submitted, got, c.want)
+		}
+	}
+}
+
+func TestResolveConnSubmittedVersionLock(t *testing.T) {
+	const base, mask = uint32(0x20000000), uint32(0x1fffe000)
+	mc := &MinerConn{versionMask: mask, cfg: Config{ShareVersionConventionLock: true, ShareAllowVersionMaskMismatch: true}}
+
+	// A full-version miner: with mask mismatches allowed the heuristic reads
+	// these as deltas until the lock engages.
+	for range versionConventionLockSamples - 1 {
+		if use, _ := mc.resolveConnSubmittedVersion(base, 0x20002000); use != 0x00002000 {
+			t.Fatalf("expected heuristic result before the lock, got %08x", use)
+		}
+	}
+	mc.resolveConnSubmittedVersion(base, 0x20004000)
+	if mc.versionConvention.locked != versionConventionFull {
+		t.Fatalf("expected full convention lock, got %v", mc.versionConvention.locked)
+	}
+	if use, diff := mc.resolveConnSubmittedVersion(base, 0x20002000); use != 0x20002000 || diff != 0x00002000 {
+		t.Fatalf("locked full convention: use=%08x diff=%08x", use, diff)
+	}
+	// One delta-looking submit does not flip the interpretation.
+	if use, _ := mc.resolveConnSubmittedVersion(base, 0x00002000); use != 0x00002000 {
+		t.Fatalf("locked full convention must keep the submitted value, got %08x", use)
+	}
+}
+
+func TestVersionConventionTrackerNeedsConsecutiveSamples(t *testing.T) {
+	var tr versionConventionTracker
+	for range versionConventionLockSamples - 1 {
+		tr.observe(versionConventionDelta)
+	}
+	tr.observe(versionConventionFull)
+	if tr.locked != versionConventionUnknown {
+		t.Fatalf("an interrupted streak must not lock")
+	}
+	tr.observe(versionConventionUnknown)
+	for range versionConventionLockSamples - 1 {
+		tr.observe(versionConventionFull)
+	}
+	if tr.locked != versionConventionFull {
+		t.Fatalf("expected full lock after a consecutive streak, got %v", tr.locked)
+	}
+}
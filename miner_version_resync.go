@@ -0,0 +1,64 @@
+package main
+
+import "time"
+
+// versionMaskResyncGrace is how long after re-pushing mining.set_version_mask
+// out-of-mask submits are still treated as in-flight work from before the
+// push rather than as the miner ignoring it.
+const versionMaskResyncGrace = 10 * time.Second
+
+// handleVersionMaskReject gives a miner that keeps rolling bits outside its
+// negotiated mask a chance to re-sync: at most once per
+// version_mask_resync_cooldown_seconds it re-sends mining.set_version_mask and
+// rejects the offending share without counting it toward an invalid-submit
+// ban. Submits inside the grace window after a push are treated the same way.
+// Once the grace window has passed without the miner adopting the mask, it
+// returns false and the caller falls back to the normal ban-eligible reject.
+func (mc *MinerConn) handleVersionMaskReject(reqID any, workerName string, now time.Time) bool {
+	cooldown := mc.cfg.VersionMaskResyncCooldown
+	if cooldown <= 0 {
+		return false
+	}
+
+	mc.stateMu.Lock()
+	last := mc.versionMaskResyncAt
+	push := last.IsZero() || now.Sub(last) >= cooldown
+	inGrace := !push && now.Sub(last) < versionMaskResyncGrace
+	warnIgnored := !push && !inGrace && !mc.versionMaskResyncIgnoredWarned
+	if push {
+		mc.versionMaskResyncAt = now
+		mc.versionMaskResyncIgnoredWarned = false
+	}
+	if warnIgnored {
+		mc.versionMaskResyncIgnoredWarned = true
+	}
+	mc.stateMu.Unlock()
+
+	if warnIgnored {
+		logger.Warn("miner ignored version mask re-sync; rejecting out-of-mask shares",
+			"component", "miner", "kind", "version_mask",
+			"miner", mc.minerName(workerName),
+			"remote", mc.id,
+			"mask", uint32ToHex8Lower(mc.versionMask),
+		)
+	}
+	if !push && !inGrace {
+		return false
+	}
+	if push {
+		logger.Info("re-sending version mask to miner rolling outside it",
+			"component", "miner", "kind", "version_mask",
+			"miner", mc.minerName(workerName),
+			"remote", mc.id,
+			"mask", uint32ToHex8Lower(mc.versionMask),
+		)
+		mc.sendVersionMask()
+	}
+	mc.recordShare(workerName, false, 0, 0, rejectInvalidVersionMask.String(), "", nil, now)
+	mc.writeResponse(StratumResponse{
+		ID:     reqID,
+		Result: false,
+		Error:  newStratumError(stratumErrCodeInvalidRequest, "invalid version mask"),
+	})
+	return true
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandleVersionMaskRejectResyncsThenFallsBack(t *testing.T) {
+	conn := &writeRecorderConn{}
+	mc := &MinerConn{
+		id:          "version-resync-miner",
+		cfg:         Config{VersionMaskResyncCooldown: time.Minute},
+		conn:        conn,
+		subscribed:  true,
+		versionMask: 0x1fffe000,
+	}
+	pushes := func() int { return strings.Count(conn.buf.String(), "mining.set_version_mask") }
+
+	start := time.Now()
+	if !mc.handleVersionMaskReject(1, "w", start) {
+		t.Fatalf("first out-of-mask submit should trigger a re-sync")
+	}
+	if pushes() != 1 {
+		t.Fatalf("expected one set_version_mask push, got %d", pushes())
+	}
+
+	if !mc.handleVersionMaskReject(2, "w", start.Add(5*time.Second)) {
+		t.Fatalf("in-flight submit inside the grace window should not be ban-counted")
+	}
+	if pushes() != 1 {
+		t.Fatalf("re-sync must not repeat within the cooldown, got %d pushes", pushes())
+	}
+
+	if mc.handleVersionMaskReject(3, "w", start.Add(20*time.Second)) {
+		t.Fatalf("miner ignoring the re-sync should fall back to normal rejects")
+	}
+
+	if !mc.handleVersionMaskReject(4, "w", start.Add(61*time.Second)) {
+		t.Fatalf("expected another re-sync once the cooldown elapsed")
+	}
+	if pushes() != 2 {
+		t.Fatalf("expected a second push after cooldown, got %d", pushes())
+	}
+}
+
+func TestHandleVersionMaskRejectDisabledByDefault(t *testing.T) {
+	mc := &MinerConn{conn: &writeRecorderConn{}, subscribed: true}
+	if mc.handleVersionMaskReject(1, "w", time.Now()) {
+		t.Fatalf("re-sync should be disabled when the cooldown is zero")
+	}
+}
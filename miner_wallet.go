@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"strings"
 	"time"
 )
@@ -95,9 +96,15 @@ func (mc *MinerConn) ensureWorkerWallet(worker string) (string, []byte, bool) {
 	}
 	base := workerBaseAddress(worker)
 	if base == "" {
+		if mc.cfg.InvalidWalletFallbackToPool {
+			return mc.fallbackWorkerWallet(worker, base)
+		}
 		return "", nil, false
 	}
 	script, err := scriptForAddress(base, ChainParams())
+	if err != nil && mc.cfg.InvalidWalletFallbackToPool {
+		return mc.fallbackWorkerWallet(worker, base)
+	}
 	if err != nil {
 		logger.Warn("derive worker payout script failed",
 			"remote", mc.id,
@@ -141,9 +148,30 @@ func (mc *MinerConn) registerWorker(worker string) *MinerConn {
 	mc.registeredWorker = worker
 	mc.registeredWorkerHash = hash
 	mc.syncSavedWorkerState(hash)
+	if mc.cfg.ReplaceStaleWorkerConnections {
+		mc.replaceStaleWorkerConnections(worker, hash)
+	}
+	if mc.cfg.TrackTransportChanges {
+		mc.noteTransportChange(worker, hash)
+	}
 	return prev
 }
 
+// replaceStaleWorkerConnections closes leftovers from a miner that reconnected
+// and resumed its session before the old socket timed out
+// (policy [stratum].replace_stale_worker_connections).
+func (mc *MinerConn) replaceStaleWorkerConnections(worker, hash string) {
+	for _, stale := range mc.workerRegistry.staleDuplicates(hash, mc) {
+		logger.Info("replacing stale worker connection",
+			"component", "miner", "kind", "lifecycle",
+			"worker", worker,
+			"remote", mc.id,
+			"stale_remote", stale.id,
+		)
+		stale.Close("replaced by reconnect")
+	}
+}
+
 func (mc *MinerConn) unregisterRegisteredWorker() {
 	if mc.workerRegistry == nil || mc.registeredWorkerHash == "" {
 		return
@@ -155,6 +183,7 @@ func (mc *MinerConn) unregisterRegisteredWorker() {
 	mc.registeredWorkerHash = ""
 	mc.savedWorkerTracked = false
 	mc.savedWorkerBestDiff = 0
+	atomicStoreFloat64(&mc.savedStaticDiff, 0)
 }
 
 func (mc *MinerConn) syncSavedWorkerState(hash string) {
@@ -177,6 +206,7 @@ func (mc *MinerConn) syncSavedWorkerState(hash string) {
 	}
 	mc.savedWorkerBestDiff = best
 	mc.savedWorkerTracked = ok
+	mc.loadSavedWorkerStaticDifficulty(hash)
 }
 
 func (mc *MinerConn) maybeUpdateSavedWorkerBestDiff(diff float64) {
@@ -214,15 +244,78 @@ func (mc *MinerConn) maybeUpdateSavedWorkerMinuteBestDiff(diff float64, now time
 	mc.savedWorkerStore.UpdateSavedWorkerMinuteBestDifficulty(hash, diff, now)
 }
 
+// coinbasePayoutLayout is the coinbase output layout chosen for a worker.
+type coinbasePayoutLayout int
+
+const (
+	// payoutLayoutSinglePool pays the whole reward to the pool payout script.
+	payoutLayoutSinglePool coinbasePayoutLayout = iota
+	// payoutLayoutSingleWorker pays the whole reward to the worker script
+	// (pool fee is 0).
+	payoutLayoutSingleWorker
+	// payoutLayoutDual splits the reward into pool fee + worker outputs.
+	payoutLayoutDual
+	// payoutLayoutTriple adds an operator donation output to the dual layout.
+	payoutLayoutTriple
+)
+
+func (l coinbasePayoutLayout) String() string {
+	switch l {
+	case payoutLayoutSingleWorker:
+		return "single_worker"
+	case payoutLayoutDual:
+		return "dual"
+	case payoutLayoutTriple:
+		return "triple"
+	default:
+		return "single_pool"
+	}
+}
+
+const (
+	coinbasePayoutModeAuto       = "auto"
+	coinbasePayoutModeSinglePool = "single_pool"
+)
+
+// payoutLayout makes the single/dual/triple coinbase decision for a worker.
+// In "auto" mode:
+//   - pool fee 0: single output to the worker wallet (which may itself be the
+//     pool address);
+//   - worker wallet script equals the pool payout script: single output to
+//     the pool, since splitting would pay the same address twice;
+//   - otherwise dual (pool fee + worker), or triple when an operator donation
+//     is configured.
+//
+// In "single_pool" mode every coinbase pays the pool payout address only.
+func (mc *MinerConn) payoutLayout(job *Job, worker string) coinbasePayoutLayout {
+	if job == nil || len(job.PayoutScript) == 0 {
+		return payoutLayoutSinglePool
+	}
+	if mc.cfg.CoinbasePayoutMode == coinbasePayoutModeSinglePool {
+		return payoutLayoutSinglePool
+	}
+	if mc.cfg.PoolFeePercent <= 0 {
+		return payoutLayoutSingleWorker
+	}
+	_, script, ok := mc.workerWalletDataRef(worker)
+	if !ok || len(script) == 0 || bytes.Equal(script, job.PayoutScript) || job.CoinbaseValue <= 0 {
+		return payoutLayoutSinglePool
+	}
+	if job.OperatorDonationPercent > 0 && len(job.DonationScript) > 0 {
+		return payoutLayoutTriple
+	}
+	return payoutLayoutDual
+}
+
 // singlePayoutScript selects the output script for single-output coinbase
-// paths. When pool_fee_percent is 0 (or negative), the full coinbase must go
-// to the resolved worker wallet script; if no validated script is available,
-// nil is returned so callers can fail fast.
+// paths. When the layout pays the worker (pool_fee_percent is 0) the full
+// coinbase must go to the resolved worker wallet script; if no validated
+// script is available, nil is returned so callers can fail fast.
 func (mc *MinerConn) singlePayoutScript(job *Job, worker string) []byte {
 	if job == nil || len(job.PayoutScript) == 0 {
 		return nil
 	}
-	if mc == nil || mc.cfg.PoolFeePercent > 0 {
+	if mc == nil || mc.payoutLayout(job, worker) != payoutLayoutSingleWorker {
 		return job.PayoutScript
 	}
 	_, script, ok := mc.workerWalletDataRef(worker)
@@ -233,36 +326,49 @@ func (mc *MinerConn) singlePayoutScript(job *Job, worker string) []byte {
 }
 
 // dualPayoutParams returns the pool and worker payout scripts and fee
-// parameters for a job, if all required pieces are available. It does not
-// mutate the Job; callers use the returned values with
-// buildDualPayoutCoinbaseParts when constructing coinbase data in the
-// dual-payout path.
-// Returns false (single-payout) when:
-// - Pool fee is 0% (entire reward goes to worker)
-// - Worker wallet matches pool wallet (same beneficiary)
-// - Worker has no valid payout script
+// parameters for a job when payoutLayout selects a dual or triple coinbase.
+// It does not mutate the Job; callers use the returned values with
+// dualCoinbasePayouts (or the triple variant when a donation is configured)
+// when constructing coinbase data.
 func (mc *MinerConn) dualPayoutParams(job *Job, worker string) (poolScript []byte, workerScript []byte, totalValue int64, feePercent float64, ok bool) {
-	if job == nil || job.CoinbaseValue <= 0 {
+	switch mc.payoutLayout(job, worker) {
+	case payoutLayoutDual, payoutLayoutTriple:
+	default:
 		return nil, nil, 0, 0, false
 	}
-	if len(job.PayoutScript) == 0 {
+	_, script, ok := mc.workerWalletDataRef(worker)
+	if !ok {
 		return nil, nil, 0, 0, false
 	}
-	// If the pool fee is 0%, there's no need for dual-payout since the entire
-	// block reward goes to the worker. Use single-output coinbase.
-	if mc.cfg.PoolFeePercent <= 0 {
-		return nil, nil, 0, 0, false
+	return job.PayoutScript, script, job.CoinbaseValue, mc.cfg.PoolFeePercent, true
+}
+
+// maxTrackedWalletFallbackWarnings bounds the worker names remembered by
+// the invalid_wallet_fallback_to_pool warning.
+const maxTrackedWalletFallbackWarnings = 65536
+
+// fallbackWorkerWallet assigns the pool payout address to a worker whose name
+// does not resolve to a valid wallet (invalid_wallet_fallback_to_pool). The
+// warning is logged once per worker name, not on every reconnect.
+func (mc *MinerConn) fallbackWorkerWallet(worker, base string) (string, []byte, bool) {
+	poolAddr := strings.TrimSpace(mc.cfg.PayoutAddress)
+	if poolAddr == "" {
+		return "", nil, false
 	}
-	addr, script, ok := mc.workerWalletDataRef(worker)
-	if !ok || len(script) == 0 {
-		return nil, nil, 0, 0, false
+	script, err := scriptForAddress(poolAddr, ChainParams())
+	if err != nil {
+		return "", nil, false
 	}
-	// If the worker's wallet address is the same as the pool payout address,
-	// there is no benefit to building a dual-payout coinbase; treat it as a
-	// single-output payout to that address.
-	if addr != "" && strings.EqualFold(addr, mc.cfg.PayoutAddress) {
-		return nil, nil, 0, 0, false
+	if mc.workerRegistry.noteWalletFallback(worker) {
+		logger.Warn("worker wallet invalid; falling back to pool payout address",
+			"component", "miner", "kind", "auth",
+			"remote", mc.id,
+			"worker", worker,
+			"address", base,
+			"payout_address", poolAddr,
+		)
 	}
-
-	return job.PayoutScript, script, job.CoinbaseValue, mc.cfg.PoolFeePercent, true
+	mc.setWorkerWallet(worker, poolAddr, script)
+	mc.sendClientShowMessage("Warning: worker name is not a valid payout address; rewards go to the pool payout address.")
+	return poolAddr, cloneBytes(script), true
 }
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestEnsureWorkerWallet_InvalidNameRejectedByDefault(t *testing.T) {
+	poolAddr, _ := generateTestWallet(t)
+	mc := &MinerConn{cfg: Config{PayoutAddress: poolAddr}, conn: &writeRecorderConn{}}
+	if _, _, ok := mc.ensureWorkerWallet("bareusername.rig1"); ok {
+		t.Fatalf("invalid worker wallet should be rejected without fallback")
+	}
+}
+
+func TestEnsureWorkerWallet_FallbackUsesPoolPayout(t *testing.T) {
+	poolAddr, poolScript := generateTestWallet(t)
+	conn := &writeRecorderConn{}
+	mc := &MinerConn{cfg: Config{PayoutAddress: poolAddr, InvalidWalletFallbackToPool: true}, conn: conn}
+
+	addr, script, ok := mc.ensureWorkerWallet("bareusername.rig1")
+	if !ok {
+		t.Fatalf("expected fallback to pool payout address")
+	}
+	if addr != poolAddr || !bytes.Equal(script, poolScript) {
+		t.Fatalf("fallback wallet = %q, want pool payout %q", addr, poolAddr)
+	}
+	if !strings.Contains(conn.String(), "client.show_message") {
+		t.Fatalf("expected miner to be warned, got %q", conn.String())
+	}
+
+	// Fallback workers pay the pool address, so the coinbase stays single-output.
+	job := &Job{PayoutScript: poolScript, CoinbaseValue: 50_0000_0000}
+	mc.cfg.PoolFeePercent = 2
+	if _, _, _, _, dual := mc.dualPayoutParams(job, "bareusername.rig1"); dual {
+		t.Fatalf("fallback worker should not get a dual-payout coinbase")
+	}
+}
+
+func TestEnsureWorkerWallet_FallbackWarnsOncePerWorker(t *testing.T) {
+	poolAddr, _ := generateTestWallet(t)
+	cfg := Config{PayoutAddress: poolAddr, InvalidWalletFallbackToPool: true}
+	registry := newWorkerConnectionRegistry()
+
+	var buf bytes.Buffer
+	prev := logger
+	logger = newSimpleLogger()
+	logger.setLevel(logLevelWarn)
+	logger.configureWriters(&buf, nil, nil, false)
+	defer func() { logger = prev }()
+
+	// The first connection and two reconnects of the same worker, plus a
+	// different worker.
+	for _, worker := range []string{"bareusername.rig1", "bareusername.rig1", "bareusername.rig1", "bareusername.rig2"} {
+		mc := &MinerConn{cfg: cfg, conn: &writeRecorderConn{}, workerRegistry: registry}
+		if _, _, ok := mc.ensureWorkerWallet(worker); !ok {
+			t.Fatalf("expected fallback for %q", worker)
+		}
+	}
+	logger.Stop()
+
+	out := buf.String()
+	if got := strings.Count(out, "worker wallet invalid; falling back to pool payout address"); got != 2 {
+		t.Fatalf("fallback warnings = %d, want one per worker name:\n%s", got, out)
+	}
+	if strings.Count(out, "bareusername.rig1") != 1 {
+		t.Fatalf("expected a single warning for the reconnecting worker:\n%s", out)
+	}
+}
@@ -43,3 +43,45 @@ func TestSinglePayoutScript_ZeroPoolFeeWithoutWorkerScriptReturnsNil(t *testing.
 		t.Fatalf("singlePayoutScript should return nil when worker wallet is unresolved at 0 fee")
 	}
 }
+
+func TestPayoutLayoutSelection(t *testing.T) {
+	poolAddr, poolScript := generateTestWallet(t)
+	_, donationScript := generateTestWallet(t)
+	workerName, workerAddr, workerScript := generateTestWorker(t)
+	poolWorker := poolAddr + ".rig"
+
+	tests := []struct {
+		name     string
+		cfg      Config
+		donation bool
+		worker   string
+		want     coinbasePayoutLayout
+	}{
+		{"fee zero pays worker", Config{PoolFeePercent: 0}, false, workerName, payoutLayoutSingleWorker},
+		{"fee zero worker is pool", Config{PoolFeePercent: 0}, false, poolWorker, payoutLayoutSingleWorker},
+		{"distinct worker gets dual", Config{PoolFeePercent: 2}, false, workerName, payoutLayoutDual},
+		{"donation gets triple", Config{PoolFeePercent: 2}, true, workerName, payoutLayoutTriple},
+		{"worker is pool address", Config{PoolFeePercent: 2}, false, poolWorker, payoutLayoutSinglePool},
+		{"unresolved worker", Config{PoolFeePercent: 2}, false, "missing.worker", payoutLayoutSinglePool},
+		{"single_pool mode", Config{PoolFeePercent: 2, CoinbasePayoutMode: coinbasePayoutModeSinglePool}, false, workerName, payoutLayoutSinglePool},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			job := &Job{PayoutScript: poolScript, CoinbaseValue: 50_0000_0000}
+			if tc.donation {
+				job.DonationScript = donationScript
+				job.OperatorDonationPercent = 1
+			}
+			mc := &MinerConn{cfg: tc.cfg}
+			mc.setWorkerWallet(workerName, workerAddr, workerScript)
+			mc.setWorkerWallet(poolWorker, poolAddr, poolScript)
+			if got := mc.payoutLayout(job, tc.worker); got != tc.want {
+				t.Fatalf("payoutLayout = %s, want %s", got, tc.want)
+			}
+			_, _, _, _, dual := mc.dualPayoutParams(job, tc.worker)
+			if wantDual := tc.want == payoutLayoutDual || tc.want == payoutLayoutTriple; dual != wantDual {
+				t.Fatalf("dualPayoutParams ok = %v, want %v", dual, wantDual)
+			}
+		})
+	}
+}
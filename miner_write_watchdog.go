@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// minWriteStallTimeout keeps the write stall watchdog from closing
+	// connections that are merely behind a briefly congested link.
+	minWriteStallTimeout = 10 * time.Second
+	// maxWriteStallCheckInterval bounds how long a wedged writer can go
+	// unnoticed past its stall timeout (and how often a disabled watchdog
+	// re-reads the config).
+	maxWriteStallCheckInterval = 5 * time.Second
+)
+
+func (mc *MinerConn) beginWrite(now time.Time) {
+	ns := now.UnixNano()
+	mc.writeProgressAt.Store(ns)
+	mc.writeStartedAt.Store(ns)
+}
+
+func (mc *MinerConn) endWrite() {
+	mc.writeStartedAt.Store(0)
+	mc.writeProgressAt.Store(0)
+}
+
+// writeStall reports how long the in-flight write has gone without moving
+// any bytes and how long it has been outstanding overall. Both are zero when
+// no write is in flight. Progress, not start time, is what matters: a slow
+// link that keeps draining the buffer is not stalled.
+func (mc *MinerConn) writeStall(now time.Time) (sinceProgress, outstanding time.Duration) {
+	started := mc.writeStartedAt.Load()
+	progress := mc.writeProgressAt.Load()
+	if started == 0 || progress == 0 {
+		return 0, 0
+	}
+	nowNS := now.UnixNano()
+	if nowNS <= progress {
+		return 0, time.Duration(max(nowNS-started, 0))
+	}
+	return time.Duration(nowNS - progress), time.Duration(nowNS - started)
+}
+
+func writeStallCheckInterval(timeout time.Duration) time.Duration {
+	interval := timeout / 2
+	if interval <= 0 || interval > maxWriteStallCheckInterval {
+		interval = maxWriteStallCheckInterval
+	}
+	return max(interval, time.Second)
+}
+
+// runWriteStallWatchdog force-closes connections whose writer has been stuck
+// on a single write with no progress for write_stall_timeout_seconds (e.g. a
+// dead peer with a full kernel send buffer). Such a connection never reaches
+// its read timeout because the handler goroutine is parked in Write, so the
+// per-write deadline is the only other thing that would free it; the
+// watchdog covers transports where that deadline is not honored.
+func runWriteStallWatchdog(ctx context.Context, registry *MinerRegistry, cfgFn func() Config) {
+	if registry == nil || cfgFn == nil {
+		return
+	}
+	for {
+		timeout := cfgFn().WriteStallTimeout
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(writeStallCheckInterval(timeout)):
+		}
+		if timeout <= 0 {
+			continue
+		}
+		now := time.Now()
+		for _, mc := range registry.Snapshot() {
+			sinceProgress, outstanding := mc.writeStall(now)
+			if sinceProgress < timeout {
+				continue
+			}
+			logger.Warn("closing miner with stalled write",
+				"component", "miner", "kind", "write_stall",
+				"remote", mc.id,
+				"since_progress", sinceProgress.Round(time.Second),
+				"outstanding", outstanding.Round(time.Second),
+			)
+			mc.Close("write stalled")
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestWriteStallTracksProgressNotStart(t *testing.T) {
+	mc := &MinerConn{}
+	now := time.Now()
+	if since, outstanding := mc.writeStall(now); since != 0 || outstanding != 0 {
+		t.Fatalf("idle writer reported stall %v/%v", since, outstanding)
+	}
+
+	mc.beginWrite(now.Add(-time.Minute))
+	// A slow write that moved bytes a second ago is outstanding for a
+	// minute but not stalled.
+	mc.writeProgressAt.Store(now.Add(-time.Second).UnixNano())
+	since, outstanding := mc.writeStall(now)
+	if since != time.Second || outstanding != time.Minute {
+		t.Fatalf("got since_progress=%v outstanding=%v, want 1s/1m", since, outstanding)
+	}
+
+	mc.endWrite()
+	if since, _ := mc.writeStall(now); since != 0 {
+		t.Fatalf("finished write still reported stalled for %v", since)
+	}
+}
+
+func TestWriteStallWatchdogClosesWedgedWriter(t *testing.T) {
+	serverSide, clientSide := net.Pipe()
+	defer clientSide.Close()
+	mc := &MinerConn{id: "wedged", conn: serverSide}
+	registry := NewMinerRegistry()
+	registry.Add(mc)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go runWriteStallWatchdog(ctx, registry, func() Config {
+		return Config{WriteStallTimeout: 50 * time.Millisecond}
+	})
+
+	// Nothing reads clientSide, so this write blocks until the watchdog
+	// closes the connection.
+	done := make(chan error, 1)
+	go func() { done <- mc.writeBytes([]byte("{\"id\":1}\n")) }()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatalf("expected wedged write to fail once the connection was closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("watchdog did not close the wedged connection")
+	}
+	if since, _ := mc.writeStall(time.Now()); since != 0 {
+		t.Fatalf("write state not cleared after failure")
+	}
+}
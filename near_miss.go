@@ -0,0 +1,61 @@
+package main
+
+import (
+	"math/big"
+	"time"
+)
+
+// NearMissShare is the most recent accepted share that came within
+// near_miss_factor of the network target without solving a block.
+type NearMissShare struct {
+	Worker      string    `json:"worker"`
+	Difficulty  float64   `json:"difficulty"`
+	NetworkDiff float64   `json:"network_difficulty"`
+	Timestamp   time.Time `json:"timestamp"`
+	Hash        string    `json:"hash,omitempty"`
+}
+
+// difficultyFromTarget returns the difficulty (relative to diff 1) of a
+// 256-bit target, e.g. the network difficulty of a job.
+func difficultyFromTarget(target *big.Int) float64 {
+	if target == nil || target.Sign() <= 0 {
+		return 0
+	}
+	d, _ := new(big.Float).Quo(new(big.Float).SetInt(diff1Target), new(big.Float).SetInt(target)).Float64()
+	return d
+}
+
+// nearMissThreshold is the share difficulty at or above which a non-block
+// share counts as a near-miss for job (0 when tracking is disabled).
+func nearMissThreshold(job *Job, factor float64) float64 {
+	if job == nil || factor <= 1 || job.networkDiff <= 0 {
+		return 0
+	}
+	return job.networkDiff / factor
+}
+
+// noteNearMiss classifies an accepted, non-block share. The per-share cost
+// is a single comparison against a per-job threshold.
+func (mc *MinerConn) noteNearMiss(job *Job, workerName, hash string, shareDiff float64, now time.Time) {
+	threshold := nearMissThreshold(job, mc.cfg.NearMissFactor)
+	if threshold <= 0 || shareDiff < threshold {
+		return
+	}
+	if mc.metrics != nil {
+		mc.metrics.RecordNearMiss(NearMissShare{
+			Worker:      mc.minerName(workerName),
+			Difficulty:  shareDiff,
+			NetworkDiff: job.networkDiff,
+			Timestamp:   now,
+			Hash:        hash,
+		})
+	}
+	logger.Info("near-miss share",
+		"component", "miner", "kind", "near_miss",
+		"miner", mc.minerName(workerName),
+		"share_diff", shareDiff,
+		"network_diff", job.networkDiff,
+		"percent_of_network", 100*shareDiff/job.networkDiff,
+		"hash", hash,
+	)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDifficultyFromTarget(t *testing.T) {
+	if got := difficultyFromTarget(diff1Target); got != 1 {
+		t.Fatalf("diff1 target difficulty = %v, want 1", got)
+	}
+	target := targetFromDifficulty(1_000_000)
+	if got := difficultyFromTarget(target); math.Abs(got-1_000_000)/1_000_000 > 1e-9 {
+		t.Fatalf("difficultyFromTarget = %v, want ~1e6", got)
+	}
+	if got := difficultyFromTarget(nil); got != 0 {
+		t.Fatalf("nil target difficulty = %v, want 0", got)
+	}
+}
+
+func TestNoteNearMiss(t *testing.T) {
+	metrics := NewPoolMetrics()
+	job := &Job{networkDiff: 1000}
+	mc := &MinerConn{cfg: Config{NearMissFactor: 10}, metrics: metrics}
+	now := time.Now()
+
+	mc.noteNearMiss(job, "worker", "aa", 99, now)
+	if n, _ := metrics.SnapshotNearMisses(); n != 0 {
+		t.Fatalf("share below 1/10 of network diff counted as near-miss")
+	}
+	mc.noteNearMiss(job, "worker", "bb", 100, now)
+	n, last := metrics.SnapshotNearMisses()
+	if n != 1 {
+		t.Fatalf("near misses = %d, want 1", n)
+	}
+	if last.Difficulty != 100 || last.NetworkDiff != 1000 || last.Hash != "bb" || !last.Timestamp.Equal(now) {
+		t.Fatalf("unexpected last near miss: %+v", last)
+	}
+
+	mc.cfg.NearMissFactor = 0
+	mc.noteNearMiss(job, "worker", "cc", 900, now)
+	if n, _ := metrics.SnapshotNearMisses(); n != 1 {
+		t.Fatalf("near-miss tracking should be off when factor is 0")
+	}
+}
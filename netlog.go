@@ -52,6 +52,10 @@ func logNetMessage(direction string, data []byte) {
 	if !netLogEnabled.Load() || netLogWriter == nil {
 		return
 	}
+	if logJSON.Load() {
+		_, _ = io.WriteString(netLogWriter, formatJSONLogLine(time.Now(), levelNames[logLevelDebug], trimNewline(data), []any{"direction", direction}))
+		return
+	}
 	fmt.Fprintf(netLogWriter, "%s [%s] %s\n", time.Now().UTC().Format(time.RFC3339Nano), direction, trimNewline(data))
 }
 
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+const (
+	maxOperatorFields         = 32
+	maxOperatorFieldKeyLen    = 64
+	maxOperatorFieldStringLen = 256
+)
+
+// validateOperatorFields checks services.toml [status.operator_fields]. The
+// fields are echoed verbatim under "operator" in /api/overview and
+// /api/pool-page, so keys are restricted to identifier-like names and values
+// to JSON scalars (string, integer, float, bool).
+func validateOperatorFields(fields map[string]any) error {
+	if len(fields) > maxOperatorFields {
+		return fmt.Errorf("[status.operator_fields] allows at most %d entries, got %d", maxOperatorFields, len(fields))
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if !validOperatorFieldKey(k) {
+			return fmt.Errorf("[status.operator_fields] key %q must be 1-%d characters of letters, digits, '_' or '-'", k, maxOperatorFieldKeyLen)
+		}
+		switch v := fields[k].(type) {
+		case string:
+			if len(v) > maxOperatorFieldStringLen {
+				return fmt.Errorf("[status.operator_fields] %s is longer than %d bytes", k, maxOperatorFieldStringLen)
+			}
+		case bool, int64:
+		case float64:
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				return fmt.Errorf("[status.operator_fields] %s must be a finite number", k)
+			}
+		default:
+			return fmt.Errorf("[status.operator_fields] %s must be a string, number or boolean, got %T", k, v)
+		}
+	}
+	return nil
+}
+
+func validOperatorFieldKey(k string) bool {
+	if k == "" || len(k) > maxOperatorFieldKeyLen {
+		return false
+	}
+	for i := 0; i < len(k); i++ {
+		b := k[i]
+		switch {
+		case b >= 'a' && b <= 'z', b >= 'A' && b <= 'Z', b >= '0' && b <= '9', b == '_', b == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// operatorFieldsView returns the configured operator fields for a JSON
+// response, or nil when none are set so the "operator" object is omitted.
+func (s *StatusServer) operatorFieldsView() map[string]any {
+	fields := s.Config().OperatorFields
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateOperatorFields(t *testing.T) {
+	ok := map[string]any{"region": "eu-west", "pool_id": int64(7), "fee_pct": 1.5, "beta": true}
+	if err := validateOperatorFields(ok); err != nil {
+		t.Fatalf("valid fields rejected: %v", err)
+	}
+	bad := []map[string]any{
+		{"bad key": "x"},
+		{"": "x"},
+		{"nested": map[string]any{"a": "b"}},
+		{"list": []any{"a"}},
+		{"nan": math.NaN()},
+		{"long": strings.Repeat("x", maxOperatorFieldStringLen+1)},
+	}
+	for _, fields := range bad {
+		if err := validateOperatorFields(fields); err == nil {
+			t.Fatalf("expected %v to be rejected", fields)
+		}
+	}
+}
+
+func TestOperatorFieldsLoadFromServicesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "services.toml")
+	data := "[status.operator_fields]\nregion = \"eu\"\nsupport_url = \"https://example.com/help\"\nshard = 3\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("write services.toml: %v", err)
+	}
+	fc, ok, err := loadServicesFile(path)
+	if err != nil || !ok {
+		t.Fatalf("load services.toml: ok=%v err=%v", ok, err)
+	}
+	var cfg Config
+	applyServicesConfig(&cfg, *fc)
+	if err := validateOperatorFields(cfg.OperatorFields); err != nil {
+		t.Fatalf("loaded fields rejected: %v", err)
+	}
+	if cfg.OperatorFields["region"] != "eu" || cfg.OperatorFields["shard"] != int64(3) {
+		t.Fatalf("unexpected operator fields: %#v", cfg.OperatorFields)
+	}
+}
+
+func TestOperatorFieldsNamespacedInPoolPageJSON(t *testing.T) {
+	s := newStatusServerForJSONTests()
+	s.UpdateConfig(Config{
+		FiatCurrency:   "USD",
+		OperatorFields: map[string]any{"region": "eu", "api_version": "spoofed"},
+	})
+	rr := httptest.NewRecorder()
+	s.handlePoolPageJSON(rr, httptest.NewRequest(http.MethodGet, "/api/pool-page", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status %d", rr.Code)
+	}
+	var resp struct {
+		APIVersion string         `json:"api_version"`
+		Operator   map[string]any `json:"operator"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.APIVersion != apiVersion {
+		t.Fatalf("operator field overrode api_version: %q", resp.APIVersion)
+	}
+	if resp.Operator["region"] != "eu" || resp.Operator["api_version"] != "spoofed" {
+		t.Fatalf("unexpected operator object: %#v", resp.Operator)
+	}
+}
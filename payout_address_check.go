@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// minPayoutAddressCheckInterval keeps the background validateaddress
+	// check from turning into RPC load.
+	minPayoutAddressCheckInterval = time.Minute
+	payoutAddressCheckTimeout     = 10 * time.Second
+)
+
+// sanityCheckPoolAddressRPC performs a one-shot RPC validation of the pool
+// payout address using the node's validateaddress RPC. It returns a non-empty
+// problem only for a definitive answer from the node: the address is
+// reported invalid, or the node's scriptPubKey differs from the locally
+// derived payout script. RPC failures are returned as err so callers can
+// treat them as transient. A short timeout keeps a stuck node from blocking.
+func sanityCheckPoolAddressRPC(ctx context.Context, rpc *RPCClient, addr string, script []byte) (problem string, err error) {
+	if rpc == nil || strings.TrimSpace(addr) == "" {
+		return "", nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, payoutAddressCheckTimeout)
+	defer cancel()
+	res, err := rpc.ValidateAddress(ctx, addr)
+	if err != nil {
+		return "", err
+	}
+	if !res.IsValid {
+		problem = "node reports payout address invalid"
+		if res.Error != "" {
+			problem += ": " + res.Error
+		}
+		return problem, nil
+	}
+	if res.ScriptPubKey != "" && len(script) > 0 && !strings.EqualFold(res.ScriptPubKey, hex.EncodeToString(script)) {
+		return fmt.Sprintf("payout script drift: node scriptPubKey %s, pool uses %s", res.ScriptPubKey, hex.EncodeToString(script)), nil
+	}
+	return "", nil
+}
+
+// payoutAddressCheckLoop periodically re-validates the configured payout
+// address against the node when payout_address_check_interval_seconds is
+// set (admin settings applies take effect on the next tick). It only alerts;
+// the payout script is never changed at runtime, so fixing a reported
+// problem stays an operator action.
+func (jm *JobManager) payoutAddressCheckLoop(ctx context.Context) {
+	var reported string
+	for {
+		jm.applyMu.Lock()
+		interval := jm.cfg.PayoutAddressCheckInterval
+		addr := jm.cfg.PayoutAddress
+		script := append([]byte(nil), jm.payoutScript...)
+		jm.applyMu.Unlock()
+		enabled := interval > 0
+		if !enabled {
+			// Idle until the check is enabled by an admin settings apply.
+			interval = minPayoutAddressCheckInterval
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+		if !enabled {
+			reported = ""
+			continue
+		}
+
+		problem, err := sanityCheckPoolAddressRPC(ctx, jm.rpc, addr, script)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Debug("payout address check skipped; rpc error", "component", "rpc", "kind", "payout_address", "error", err)
+			continue
+		}
+		if problem == "" {
+			if reported != "" {
+				logger.Info("payout address check passing again", "component", "rpc", "kind", "payout_address", "address", addr)
+				reported = ""
+			}
+			continue
+		}
+		if problem == reported {
+			continue
+		}
+		reported = problem
+		logger.Error("payout address check failed; payout script left unchanged",
+			"component", "rpc", "kind", "payout_address",
+			"address", addr,
+			"problem", problem,
+		)
+		if jm.metrics != nil {
+			jm.metrics.RecordErrorEvent("payout_address", problem, time.Now())
+		}
+	}
+}
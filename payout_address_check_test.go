@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newValidateAddressRPC(t *testing.T, handler func() (*ValidateAddressResult, *rpcError)) *RPCClient {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode rpc request: %v", err)
+		}
+		resp := rpcResponse{ID: req.ID}
+		res, rpcErr := handler()
+		if rpcErr != nil {
+			resp.Error = rpcErr
+		} else {
+			data, _ := json.Marshal(res)
+			resp.Result = data
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return &RPCClient{url: srv.URL, client: srv.Client(), lp: srv.Client()}
+}
+
+func TestSanityCheckPoolAddressRPC(t *testing.T) {
+	addr, script := generateTestWallet(t)
+	scriptHex := hex.EncodeToString(script)
+
+	cases := []struct {
+		name        string
+		res         *ValidateAddressResult
+		rpcErr      *rpcError
+		wantProblem string
+		wantErr     bool
+	}{
+		{name: "valid", res: &ValidateAddressResult{IsValid: true, Address: addr, ScriptPubKey: scriptHex}},
+		{name: "invalid", res: &ValidateAddressResult{IsValid: false, Error: "Invalid checksum"}, wantProblem: "invalid: Invalid checksum"},
+		{name: "drift", res: &ValidateAddressResult{IsValid: true, Address: addr, ScriptPubKey: "0014" + strings.Repeat("00", 20)}, wantProblem: "payout script drift"},
+		{name: "rpc error is transient", rpcErr: &rpcError{Code: -1, Message: "internal error"}, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rpc := newValidateAddressRPC(t, func() (*ValidateAddressResult, *rpcError) { return tc.res, tc.rpcErr })
+			problem, err := sanityCheckPoolAddressRPC(context.Background(), rpc, addr, script)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr && problem != "" {
+				t.Fatalf("transient rpc error must not report a problem, got %q", problem)
+			}
+			if tc.wantProblem == "" && problem != "" {
+				t.Fatalf("unexpected problem %q", problem)
+			}
+			if tc.wantProblem != "" && !strings.Contains(problem, tc.wantProblem) {
+				t.Fatalf("problem = %q, want it to contain %q", problem, tc.wantProblem)
+			}
+		})
+	}
+}
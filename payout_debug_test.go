@@ -44,6 +44,7 @@ func buildBlockCoinbase(t *testing.T, mc *MinerConn, job *Job, worker string, en
 			totalValue,
 			feePercent,
 			job.WitnessCommitment,
+			job.extraOpReturnScript,
 			job.Template.CoinbaseAux.Flags,
 			job.CoinbaseMsg,
 			job.ScriptTime,
@@ -61,6 +62,7 @@ func buildBlockCoinbase(t *testing.T, mc *MinerConn, job *Job, worker string, en
 			job.PayoutScript,
 			job.CoinbaseValue,
 			job.WitnessCommitment,
+			job.extraOpReturnScript,
 			job.Template.CoinbaseAux.Flags,
 			job.CoinbaseMsg,
 			job.ScriptTime,
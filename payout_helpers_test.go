@@ -173,6 +173,7 @@ func TestSerializeCoinbaseTxPayoutsPredecoded_PaymentCountsAndOrdering(t *testin
 				tt.payouts,
 				c,
 				nil,
+				nil,
 				"test",
 				0,
 			)
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	hashrateDropSampleInterval  = 15 * time.Second
+	defaultHashrateDropWindow   = 10 * time.Minute
+	defaultHashrateDropDebounce = 2 * time.Minute
+)
+
+// poolDraining is set once a deliberate shutdown starts closing miners, and
+// while an admin drain is active, so the resulting drop is not reported as an
+// outage.
+var poolDraining atomic.Bool
+
+func setPoolDraining(v bool) { poolDraining.Store(v) }
+
+type hashrateDropSample struct {
+	at       time.Time
+	hashrate float64
+	conns    int
+}
+
+// hashrateDropDetector compares the latest pool hashrate and connection
+// count against the peak seen within the window. A drop has to persist for
+// the debounce period before it is reported, and it is reported once until
+// the pool recovers.
+type hashrateDropDetector struct {
+	samples    []hashrateDropSample
+	dropSince  time.Time
+	alerted    bool
+	peakAtDrop hashrateDropSample
+}
+
+type hashrateDropAlert struct {
+	before, after hashrateDropSample
+	window        time.Duration
+}
+
+func (a hashrateDropAlert) message() string {
+	return fmt.Sprintf("Pool hashrate dropped sharply within %s: %s -> %s, connections %d -> %d (%.0f%% / %.0f%% drop).",
+		a.window.Round(time.Second),
+		formatHashrateValue(a.before.hashrate), formatHashrateValue(a.after.hashrate),
+		a.before.conns, a.after.conns,
+		dropPercent(a.before.hashrate, a.after.hashrate), dropPercent(float64(a.before.conns), float64(a.after.conns)))
+}
+
+func dropPercent(before, after float64) float64 {
+	if before <= 0 || after >= before {
+		return 0
+	}
+	return (before - after) / before * 100
+}
+
+// observe records a sample and returns an alert when a debounced drop of at
+// least percent is first confirmed. recovered is true when a previously
+// alerted drop has cleared.
+func (d *hashrateDropDetector) observe(s hashrateDropSample, percent float64, window, debounce time.Duration) (alert *hashrateDropAlert, recovered bool) {
+	cutoff := s.at.Add(-window)
+	keep := d.samples[:0]
+	for _, old := range d.samples {
+		if !old.at.Before(cutoff) {
+			keep = append(keep, old)
+		}
+	}
+	d.samples = append(keep, s)
+
+	var peak hashrateDropSample
+	for _, old := range d.samples {
+		if old.hashrate > peak.hashrate {
+			peak.hashrate = old.hashrate
+			peak.at = old.at
+		}
+		if old.conns > peak.conns {
+			peak.conns = old.conns
+		}
+	}
+	dropped := dropPercent(peak.hashrate, s.hashrate) >= percent ||
+		dropPercent(float64(peak.conns), float64(s.conns)) >= percent
+
+	if !dropped {
+		d.dropSince = time.Time{}
+		if d.alerted {
+			d.alerted = false
+			return nil, true
+		}
+		return nil, false
+	}
+	if d.dropSince.IsZero() {
+		d.dropSince = s.at
+		d.peakAtDrop = peak
+	}
+	if d.alerted || s.at.Sub(d.dropSince) < debounce {
+		return nil, false
+	}
+	d.alerted = true
+	return &hashrateDropAlert{before: d.peakAtDrop, after: s, window: window}, false
+}
+
+// reset forgets history, e.g. after the alert is disabled or a drain ends.
+func (d *hashrateDropDetector) reset() {
+	*d = hashrateDropDetector{}
+}
+
+// runHashrateDropMonitor samples pool hashrate and connection count and
+// alerts (log, error history and the Discord notice channel) on a sharp
+// drop when hashrate_drop_alert_percent is set.
+func runHashrateDropMonitor(ctx context.Context, s *StatusServer, notifier *discordNotifier) {
+	if s == nil {
+		return
+	}
+	var d hashrateDropDetector
+	ticker := time.NewTicker(hashrateDropSampleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		cfg := s.Config()
+		if cfg.HashrateDropAlertPercent <= 0 || poolDraining.Load() {
+			d.reset()
+			continue
+		}
+		conns := 0
+		if s.registry != nil {
+			conns = s.registry.Count()
+		}
+		now := time.Now()
+		alert, recovered := d.observe(hashrateDropSample{at: now, hashrate: s.computePoolHashrate(), conns: conns},
+			cfg.HashrateDropAlertPercent, cfg.HashrateDropAlertWindow, cfg.HashrateDropAlertDebounce)
+		// A shutdown or admin drain that began while we were sampling is not
+		// an outage.
+		if ctx.Err() != nil {
+			return
+		}
+		if poolDraining.Load() {
+			d.reset()
+			continue
+		}
+		switch {
+		case alert != nil:
+			msg := alert.message()
+			logger.Error("pool hashrate drop", "component", "alerts", "kind", "hashrate_drop",
+				"hashrate_before", alert.before.hashrate, "hashrate_after", alert.after.hashrate,
+				"connections_before", alert.before.conns, "connections_after", alert.after.conns,
+				"window", cfg.HashrateDropAlertWindow)
+			if s.metrics != nil {
+				s.metrics.RecordErrorEvent("hashrate_drop", msg, now)
+			}
+			notifier.NotifyPoolAlert(msg)
+		case recovered:
+			logger.Info("pool hashrate recovered", "component", "alerts", "kind", "hashrate_drop", "hashrate", s.computePoolHashrate(), "connections", conns)
+			notifier.NotifyPoolAlert("Pool hashrate has recovered.")
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHashrateDropDetectorDebouncesAndRearms(t *testing.T) {
+	var d hashrateDropDetector
+	start := time.Unix(1_700_000_000, 0)
+	window, debounce := 10*time.Minute, 2*time.Minute
+	at := func(sec int) time.Time { return start.Add(time.Duration(sec) * time.Second) }
+
+	if a, _ := d.observe(hashrateDropSample{at: at(0), hashrate: 1000, conns: 10}, 50, window, debounce); a != nil {
+		t.Fatalf("unexpected alert on first sample")
+	}
+	// A brief dip that recovers inside the debounce never alerts.
+	if a, _ := d.observe(hashrateDropSample{at: at(15), hashrate: 100, conns: 2}, 50, window, debounce); a != nil {
+		t.Fatalf("alert fired before debounce")
+	}
+	if a, rec := d.observe(hashrateDropSample{at: at(30), hashrate: 990, conns: 10}, 50, window, debounce); a != nil || rec {
+		t.Fatalf("unexpected alert/recovery after a brief dip")
+	}
+
+	// A sustained drop alerts once, with the peak as the before numbers.
+	d.observe(hashrateDropSample{at: at(45), hashrate: 200, conns: 9}, 50, window, debounce)
+	a, _ := d.observe(hashrateDropSample{at: at(165), hashrate: 200, conns: 9}, 50, window, debounce)
+	if a == nil {
+		t.Fatalf("expected alert after debounce")
+	}
+	if a.before.hashrate != 1000 || a.before.conns != 10 || a.after.hashrate != 200 || a.after.conns != 9 {
+		t.Fatalf("unexpected before/after: %+v -> %+v", a.before, a.after)
+	}
+	if msg := a.message(); !strings.Contains(msg, "10 -> 9") || !strings.Contains(msg, "80%") {
+		t.Fatalf("alert message missing numbers: %q", msg)
+	}
+	if a, _ := d.observe(hashrateDropSample{at: at(180), hashrate: 200, conns: 9}, 50, window, debounce); a != nil {
+		t.Fatalf("alert repeated while still down")
+	}
+	if _, rec := d.observe(hashrateDropSample{at: at(195), hashrate: 950, conns: 10}, 50, window, debounce); !rec {
+		t.Fatalf("expected recovery")
+	}
+}
+
+func TestHashrateDropDetectorConnectionCount(t *testing.T) {
+	var d hashrateDropDetector
+	start := time.Unix(1_700_000_000, 0)
+	d.observe(hashrateDropSample{at: start, hashrate: 1000, conns: 100}, 40, time.Minute, 0)
+	// Hashrate estimates lag; a mass disconnect shows up in the count first.
+	a, _ := d.observe(hashrateDropSample{at: start.Add(15 * time.Second), hashrate: 950, conns: 30}, 40, time.Minute, 0)
+	if a == nil {
+		t.Fatalf("expected alert on connection count drop")
+	}
+	// Samples older than the window no longer count as the peak.
+	d.reset()
+	d.observe(hashrateDropSample{at: start, hashrate: 1000, conns: 100}, 40, time.Minute, 0)
+	if a, _ := d.observe(hashrateDropSample{at: start.Add(2 * time.Minute), hashrate: 100, conns: 10}, 40, time.Minute, 0); a != nil {
+		t.Fatalf("peak outside the window should not trigger")
+	}
+}
@@ -1,11 +1,13 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bytedance/sonic"
@@ -21,8 +23,13 @@ type PriceService struct {
 	lastFiat  string
 	lastErr   error
 	client    *http.Client
+	offline   atomic.Bool
 }
 
+// errPriceOffline is returned by BTCPrice in offline mode, where no lookup
+// is attempted.
+var errPriceOffline = errors.New("price unavailable (offline mode)")
+
 type PriceServiceSnapshot struct {
 	LastFetch time.Time
 	LastPrice float64
@@ -46,6 +53,9 @@ func (p *PriceService) BTCPrice(fiat string) (float64, error) {
 	if p == nil {
 		return 0, fmt.Errorf("price service not initialized")
 	}
+	if p.offline.Load() {
+		return 0, errPriceOffline
+	}
 	fiat = strings.ToLower(strings.TrimSpace(fiat))
 	if fiat == "" {
 		fiat = "usd"
@@ -120,6 +130,19 @@ func (p *PriceService) BTCPrice(fiat string) (float64, error) {
 	return price, nil
 }
 
+// SetOffline stops (or resumes) price lookups for offline mode.
+func (p *PriceService) SetOffline(offline bool) {
+	if p == nil {
+		return
+	}
+	p.offline.Store(offline)
+}
+
+// Offline reports whether price lookups are disabled by offline mode.
+func (p *PriceService) Offline() bool {
+	return p != nil && p.offline.Load()
+}
+
 // LastUpdate returns the time the price was last fetched from CoinGecko.
 // If no successful fetch has occurred yet, it returns the zero time.
 func (p *PriceService) LastUpdate() time.Time {
@@ -23,6 +23,18 @@ const (
 )
 
 var rpcRetryMaxDelay = 5 * time.Second
+
+// errRPCDecode marks a response body that could not be parsed as a JSON-RPC
+// envelope (typically a truncated reply from a struggling node).
+var errRPCDecode = errors.New("decode rpc response")
+
+// errRPCResultDecode marks a well-formed JSON-RPC reply whose result does not
+// match the expected Go type (a schema mismatch rather than a transport fault).
+var errRPCResultDecode = errors.New("decode rpc result")
+
+// errRPCAuth marks a call the node still refused with 401 after the cookie
+// was re-read (node.rpc_cookie_retry): the credentials are wrong, not stale.
+var errRPCAuth = errors.New("rpc authentication failed")
 var rpcCookieWatchInterval = time.Second
 var rpcRetryJitterFrac = 0.2
 
@@ -75,6 +87,7 @@ type RPCClient struct {
 	disconnects        atomic.Uint64
 	reconnects         atomic.Uint64
 	cookieWatchStarted atomic.Bool
+	cookieRetry        bool
 
 	authMu        sync.RWMutex
 	cookiePath    string
@@ -104,10 +117,17 @@ func NewRPCClient(cfg Config, metrics *PoolMetrics) *RPCClient {
 		ExpectContinueTimeout: 1 * time.Second,
 	}
 
+	user, pass := cfg.RPCUser, cfg.RPCPass
+	if cfg.rpcCookieWatch {
+		// Cookie mode: the client parses node.rpc_cookie_path itself and
+		// keeps it current, so any credentials copied into cfg (possibly from
+		// a cookie bitcoind has since rotated) are ignored.
+		user, pass = "", ""
+	}
 	c := &RPCClient{
 		url:     cfg.RPCURL,
-		user:    cfg.RPCUser,
-		pass:    cfg.RPCPass,
+		user:    user,
+		pass:    pass,
 		metrics: metrics,
 		client: &http.Client{
 			Timeout:   60 * time.Second,
@@ -117,8 +137,9 @@ func NewRPCClient(cfg Config, metrics *PoolMetrics) *RPCClient {
 			Timeout:   0, // longpoll waits for bitcoind to respond on new blocks
 			Transport: transport,
 		},
-		nextID:     1,
-		cookiePath: strings.TrimSpace(cfg.RPCCookiePath),
+		nextID:      1,
+		cookiePath:  strings.TrimSpace(cfg.RPCCookiePath),
+		cookieRetry: cfg.RPCCookieRetry,
 	}
 	c.initCookieStat()
 	return c
@@ -153,6 +174,13 @@ func (c *RPCClient) initCookieStat() {
 }
 
 func (c *RPCClient) reloadCookieIfChanged() {
+	c.reloadCookie(false)
+}
+
+// reloadCookie re-reads the cookie when it changed on disk or, with force,
+// unconditionally; a rotation within the same second and size is invisible
+// to the stat check.
+func (c *RPCClient) reloadCookie(force bool) {
 	if c.cookiePath == "" {
 		return
 	}
@@ -173,7 +201,7 @@ func (c *RPCClient) reloadCookieIfChanged() {
 
 	credsEmpty := strings.TrimSpace(user) == "" && strings.TrimSpace(pass) == ""
 	changed := !info.ModTime().Equal(modTime) || info.Size() != size
-	if !changed && !credsEmpty {
+	if !changed && !credsEmpty && !force {
 		return
 	}
 	newUser, newPass, err := readRPCCookie(c.cookiePath)
@@ -181,13 +209,17 @@ func (c *RPCClient) reloadCookieIfChanged() {
 		logger.Warn("reload rpc cookie", "component", "rpc", "kind", "auth_cookie", "path", c.cookiePath, "error", err)
 		return
 	}
+	newUser, newPass = strings.TrimSpace(newUser), strings.TrimSpace(newPass)
 	c.authMu.Lock()
-	c.user = strings.TrimSpace(newUser)
-	c.pass = strings.TrimSpace(newPass)
+	c.user = newUser
+	c.pass = newPass
 	c.cookieModTime = info.ModTime()
 	c.cookieSize = info.Size()
 	c.authMu.Unlock()
-	if changed {
+	if !changed && !credsEmpty && newUser == user && newPass == pass {
+		return
+	}
+	if changed || !credsEmpty {
 		logger.Info("rpc cookie reloaded", "component", "rpc", "kind", "auth_cookie", "path", c.cookiePath)
 	} else {
 		logger.Info("rpc cookie loaded", "component", "rpc", "kind", "auth_cookie", "path", c.cookiePath)
@@ -246,6 +278,7 @@ func (c *RPCClient) callLongPollCtx(ctx context.Context, method string, params a
 
 func (c *RPCClient) callWithClientCtx(ctx context.Context, client *http.Client, method string, params any, out any) error {
 	retryCount := 0
+	authRetried := false
 	for {
 		if ctx.Err() != nil {
 			c.recordLastError(ctx.Err())
@@ -271,7 +304,8 @@ func (c *RPCClient) callWithClientCtx(ctx context.Context, client *http.Client,
 		if c.metrics != nil {
 			c.metrics.RecordRPCError(err)
 		}
-		if isRPCConnectivityError(err) {
+		callerHandlesDecode := decodeErrorHandledByCaller(method, err)
+		if !callerHandlesDecode && isRPCConnectivityError(err) {
 			if !c.unhealthy.Swap(true) {
 				c.disconnects.Add(1)
 				if c.metrics != nil {
@@ -279,7 +313,20 @@ func (c *RPCClient) callWithClientCtx(ctx context.Context, client *http.Client,
 				}
 			}
 		}
-		if c.shouldRetry(err) {
+		if c.cookieRetry && c.cookiePath != "" && isRPCUnauthorized(err) {
+			if authRetried {
+				logger.Error("rpc still unauthorized after cookie re-read", "component", "rpc", "kind", "auth_cookie", "method", method, "path", c.cookiePath)
+				err = fmt.Errorf("%w: %w", errRPCAuth, err)
+				c.recordLastError(err)
+				return err
+			}
+			// bitcoind restarted and rotated the cookie: pick up the new
+			// one now instead of waiting for the watcher, and retry once.
+			authRetried = true
+			c.reloadCookie(true)
+			continue
+		}
+		if !callerHandlesDecode && c.shouldRetry(err) {
 			retryCount++
 			c.reloadCookieIfChanged()
 			delay := rpcRetryDelayWithBackoff(retryCount)
@@ -337,6 +384,15 @@ func (c *RPCClient) Reconnects() uint64 {
 	return c.reconnects.Load()
 }
 
+// decodeErrorHandledByCaller reports a malformed getblocktemplate reply. The
+// node did answer, so it is neither retried here nor counted as a disconnect:
+// JobManager.fetchTemplateCtx retries it a bounded number of times and keeps
+// the previous job meanwhile. Other methods keep the generic retry, which
+// covers truncated replies while the node restarts.
+func decodeErrorHandledByCaller(method string, err error) bool {
+	return method == "getblocktemplate" && errors.Is(err, errRPCDecode)
+}
+
 func isRPCConnectivityError(err error) bool {
 	if err == nil {
 		return false
@@ -423,7 +479,7 @@ func (c *RPCClient) performCall(ctx context.Context, client *http.Client, method
 
 	var rpcResp rpcResponse
 	if err := fastJSONUnmarshal(data, &rpcResp); err != nil {
-		return fmt.Errorf("decode rpc response: %w", err)
+		return fmt.Errorf("%w: %w", errRPCDecode, err)
 	}
 	if rpcResp.Error != nil {
 		if shouldIgnoreRPCError(method, rpcResp.Error) {
@@ -444,7 +500,10 @@ func (c *RPCClient) performCall(ctx context.Context, client *http.Client, method
 	if out == nil {
 		return nil
 	}
-	return fastJSONUnmarshal(rpcResp.Result, out)
+	if err := fastJSONUnmarshal(rpcResp.Result, out); err != nil {
+		return fmt.Errorf("%w: %w", errRPCResultDecode, err)
+	}
+	return nil
 }
 
 func shouldIgnoreRPCError(method string, err *rpcError) bool {
@@ -463,6 +522,11 @@ func (c *RPCClient) recordRPCCallSuccess() {
 	c.lastErrMu.Unlock()
 }
 
+func isRPCUnauthorized(err error) bool {
+	var statusErr *httpStatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusUnauthorized
+}
+
 func (c *RPCClient) shouldRetry(err error) bool {
 	if err == nil {
 		return false
@@ -583,6 +647,23 @@ func (c *RPCClient) GetBlockHeader(ctx context.Context, hash string) (*BlockHead
 	return &header, nil
 }
 
+// ValidateAddressResult is the subset of validateaddress fields the pool uses.
+type ValidateAddressResult struct {
+	IsValid      bool   `json:"isvalid"`
+	Address      string `json:"address"`
+	ScriptPubKey string `json:"scriptPubKey"`
+	Error        string `json:"error"`
+}
+
+// ValidateAddress asks the node whether addr is valid on its network.
+func (c *RPCClient) ValidateAddress(ctx context.Context, addr string) (*ValidateAddressResult, error) {
+	var res ValidateAddressResult
+	if err := c.callCtx(ctx, "validateaddress", []any{addr}, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
 // Fetch the scriptPubKey for the payout address using local address
 // validation instead of relying on bitcoind wallet RPCs. This avoids extra
 // RPC calls and does not require the node's wallet to know about the
@@ -328,3 +328,139 @@ func TestRPCClientTracksDisconnectsAndReconnects(t *testing.T) {
 		t.Fatalf("expected client to be healthy after retry")
 	}
 }
+
+func TestNewRPCClientPrefersCookieOverCopiedCredentials(t *testing.T) {
+	cookiePath := filepath.Join(t.TempDir(), ".cookie")
+	if err := os.WriteFile(cookiePath, []byte("__cookie__:fresh\n"), 0o600); err != nil {
+		t.Fatalf("write cookie: %v", err)
+	}
+	cfg := Config{
+		RPCURL:         "http://127.0.0.1:8332",
+		RPCUser:        "__cookie__",
+		RPCPass:        "rotated-away",
+		RPCCookiePath:  cookiePath,
+		rpcCookieWatch: true,
+	}
+	client := NewRPCClient(cfg, nil)
+
+	client.authMu.RLock()
+	user, pass := client.user, client.pass
+	client.authMu.RUnlock()
+	if user != "__cookie__" || pass != "fresh" {
+		t.Fatalf("expected cookie credentials, got %q/%q", user, pass)
+	}
+}
+
+func TestValidateConfigAcceptsCookieOnlyRPCAuth(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.PayoutAddress = "1Pool"
+	cfg.RPCUser = ""
+	cfg.RPCPass = ""
+	cfg.RPCCookiePath = "/var/lib/bitcoind/.cookie"
+	if err := validateConfig(cfg); err != nil && strings.Contains(err.Error(), "rpc credentials") {
+		t.Fatalf("cookie-only config rejected: %v", err)
+	}
+	cfg.RPCCookiePath = ""
+	if err := validateConfig(cfg); err == nil || !strings.Contains(err.Error(), "rpc credentials") {
+		t.Fatalf("expected missing credentials error without a cookie, got %v", err)
+	}
+}
+
+func TestRPCClientCookieRetryOn401(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		var req rpcRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if user, pass, ok := r.BasicAuth(); !ok || user != "__cookie__" || pass != "rotated" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(rpcResponse{Result: json.RawMessage("null"), ID: req.ID})
+	}))
+	t.Cleanup(srv.Close)
+
+	cookiePath := filepath.Join(t.TempDir(), ".cookie")
+	if err := os.WriteFile(cookiePath, []byte("__cookie__:stale01"), 0o600); err != nil {
+		t.Fatalf("write cookie: %v", err)
+	}
+	client := &RPCClient{
+		url:         srv.URL,
+		client:      srv.Client(),
+		lp:          srv.Client(),
+		cookiePath:  cookiePath,
+		cookieRetry: true,
+	}
+	client.initCookieStat()
+
+	// Same size and mtime: only a forced re-read notices the rotation.
+	info, _ := os.Stat(cookiePath)
+	if err := os.WriteFile(cookiePath, []byte("__cookie__:rotated"), 0o600); err != nil {
+		t.Fatalf("rotate cookie: %v", err)
+	}
+	if err := os.Chtimes(cookiePath, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	if err := client.call("getblockchaininfo", nil, nil); err != nil {
+		t.Fatalf("expected call to succeed after cookie re-read, got: %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("calls = %d, want 2 (401 then retry)", got)
+	}
+
+	if err := os.WriteFile(cookiePath, []byte("__cookie__:wrong"), 0o600); err != nil {
+		t.Fatalf("write cookie: %v", err)
+	}
+	client.reloadCookieIfChanged()
+	calls.Store(0)
+	err := client.call("getblockchaininfo", nil, nil)
+	if !errors.Is(err, errRPCAuth) {
+		t.Fatalf("expected errRPCAuth for a wrong cookie, got: %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("calls = %d, want exactly one retry for a wrong cookie", got)
+	}
+	if !errors.Is(client.LastError(), errRPCAuth) {
+		t.Fatalf("LastError = %v, want errRPCAuth", client.LastError())
+	}
+}
+
+func TestRPCClientRetriesTruncatedReplyExceptTemplates(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpcRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if calls.Add(1) == 1 {
+			_, _ = w.Write([]byte(`{"result":{"blocks":`))
+			return
+		}
+		resp := rpcResponse{ID: req.ID}
+		resp.Result, _ = json.Marshal(map[string]any{"blocks": 7})
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	client := &RPCClient{url: srv.URL, client: srv.Client(), lp: srv.Client()}
+
+	var info struct {
+		Blocks int64 `json:"blocks"`
+	}
+	if err := client.call("getblockchaininfo", nil, &info); err != nil {
+		t.Fatalf("expected truncated reply to be retried, got %v", err)
+	}
+	if info.Blocks != 7 || calls.Load() != 2 {
+		t.Fatalf("blocks=%d calls=%d, want 7 after 2 calls", info.Blocks, calls.Load())
+	}
+
+	calls.Store(0)
+	var tpl GetBlockTemplateResult
+	err := client.call("getblocktemplate", []any{map[string]any{}}, &tpl)
+	if !errors.Is(err, errRPCDecode) {
+		t.Fatalf("expected getblocktemplate decode error to reach the caller, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("getblocktemplate calls=%d, want 1 (JobManager retries it)", calls.Load())
+	}
+	if client.unhealthy.Load() {
+		t.Fatalf("a malformed template reply should not mark the node disconnected")
+	}
+}
@@ -180,6 +180,8 @@ func applySafeModeProfile(cfg *Config) {
 	// Disable automatic temporary bans in safe mode to avoid false positives while troubleshooting.
 	cfg.BanInvalidSubmissionsAfter = 0
 	cfg.ReconnectBanThreshold = 0
+	cfg.SourcePortChurnPerMin = 0
+	cfg.NonceCheckBanAfter = 0
 
 	cfg.DisableConnectRateLimits = true
 }
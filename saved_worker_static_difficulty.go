@@ -0,0 +1,271 @@
+package main
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bytedance/sonic"
+)
+
+// SetSavedWorkerStaticDifficulty stores userID's pinned difficulty for a
+// saved worker (0 clears it). The value is kept as entered; min/max are
+// applied when a connection picks it up so later limit changes still hold.
+// The first call also gives the entry its owner token; the value is only
+// used once the rig has presented that token (see VerifySavedWorkerOwner).
+func (s *workerListStore) SetSavedWorkerStaticDifficulty(userID, workerHash string, diff float64) (bool, error) {
+	if s == nil || s.db == nil {
+		return false, nil
+	}
+	userID = strings.TrimSpace(userID)
+	workerHash = strings.ToLower(strings.TrimSpace(workerHash))
+	if userID == "" || len(workerHash) != 64 {
+		return false, nil
+	}
+	token, err := generateSavedWorkerOwnerToken()
+	if err != nil {
+		return false, err
+	}
+	res, err := s.db.Exec(
+		"UPDATE saved_workers SET static_difficulty = ?, owner_token = CASE WHEN owner_token = '' THEN ? ELSE owner_token END WHERE user_id = ? AND worker_hash = ?",
+		diff, token, userID, workerHash,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// SavedWorkerOwnership returns userID's owner token for a saved worker and
+// whether the rig has confirmed it.
+func (s *workerListStore) SavedWorkerOwnership(userID, workerHash string) (token string, verified bool, err error) {
+	if s == nil || s.db == nil {
+		return "", false, nil
+	}
+	var verifiedInt int
+	err = s.db.QueryRow(
+		"SELECT owner_token, owner_verified FROM saved_workers WHERE user_id = ? AND worker_hash = ?",
+		strings.TrimSpace(userID), strings.ToLower(strings.TrimSpace(workerHash)),
+	).Scan(&token, &verifiedInt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	return token, verifiedInt != 0, err
+}
+
+// VerifySavedWorkerOwner marks the saved entry whose owner token matches as
+// the worker's owner. Only someone who controls the rig's configuration can
+// present the token, so this ties a user to the worker; any other user's
+// earlier claim on the same worker is dropped.
+func (s *workerListStore) VerifySavedWorkerOwner(workerHash, token string) (bool, error) {
+	if s == nil || s.db == nil {
+		return false, nil
+	}
+	workerHash = strings.ToLower(strings.TrimSpace(workerHash))
+	token = strings.TrimSpace(token)
+	if workerHash == "" || token == "" {
+		return false, nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			logger.Debug("saved worker owner verify rollback failed", "error", err, "hash", workerHash)
+		}
+	}()
+	var userID string
+	err = tx.QueryRow("SELECT user_id FROM saved_workers WHERE worker_hash = ? AND owner_token = ?", workerHash, token).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec(
+		"UPDATE saved_workers SET owner_verified = CASE WHEN user_id = ? THEN 1 ELSE 0 END WHERE worker_hash = ?",
+		userID, workerHash,
+	); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// StaticDifficultyForHash returns the difficulty pinned by the worker's
+// verified owner, or 0. Values saved by users who have not proven ownership
+// are never used, so saving a stranger's worker cannot change its difficulty.
+func (s *workerListStore) StaticDifficultyForHash(hash string) (float64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	hash = strings.ToLower(strings.TrimSpace(hash))
+	if hash == "" {
+		return 0, nil
+	}
+	var diff float64
+	err := s.db.QueryRow("SELECT static_difficulty FROM saved_workers WHERE worker_hash = ? AND owner_verified = 1 AND static_difficulty > 0 LIMIT 1", hash).Scan(&diff)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return diff, err
+}
+
+func generateSavedWorkerOwnerToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// parsePasswordOwnerToken extracts an owner=<token> option from a stratum
+// password.
+func parsePasswordOwnerToken(pass string) string {
+	for _, token := range splitPasswordTokens(pass) {
+		key, val, ok := splitOptionToken(token)
+		if ok && normalizeOptionKey(key) == "owner" {
+			return val
+		}
+	}
+	return ""
+}
+
+// claimSavedWorkerOwner verifies an owner token sent in the authorize
+// password so that user's saved static difficulty applies to this worker.
+func (mc *MinerConn) claimSavedWorkerOwner(worker, pass string) {
+	if !mc.cfg.SavedWorkerStaticDifficulty || mc.savedWorkerStore == nil {
+		return
+	}
+	token := parsePasswordOwnerToken(pass)
+	if token == "" {
+		return
+	}
+	hash := workerNameHash(worker)
+	ok, err := mc.savedWorkerStore.VerifySavedWorkerOwner(hash, token)
+	if err != nil {
+		logger.Warn("saved worker owner verify failed", "error", err, "hash", hash)
+		return
+	}
+	if !ok {
+		logger.Info("saved worker owner token not recognized", "component", "miner", "kind", "auth", "remote", mc.id, "worker", worker)
+		return
+	}
+	mc.loadSavedWorkerStaticDifficulty(hash)
+}
+
+// loadSavedWorkerStaticDifficulty refreshes the connection's saved static
+// difficulty for hash. On a lookup error the previous value is kept, so a
+// connection that authorized before its saved entry could be read starts on
+// the normal default and is adjusted by the next refresh.
+func (mc *MinerConn) loadSavedWorkerStaticDifficulty(hash string) {
+	if !mc.cfg.SavedWorkerStaticDifficulty || mc.savedWorkerStore == nil {
+		atomicStoreFloat64(&mc.savedStaticDiff, 0)
+		return
+	}
+	diff, err := mc.savedWorkerStore.StaticDifficultyForHash(hash)
+	if err != nil {
+		logger.Warn("saved worker static difficulty lookup failed", "error", err, "hash", hash)
+		return
+	}
+	atomicStoreFloat64(&mc.savedStaticDiff, diff)
+}
+
+func (s *StatusServer) handleSavedWorkerDifficulty(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	user := ClerkUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if s.workerLists == nil || !s.Config().SavedWorkerStaticDifficulty {
+		http.Error(w, "saved worker difficulty not enabled", http.StatusBadRequest)
+		return
+	}
+
+	type req struct {
+		Hash       string   `json:"hash"`
+		Difficulty *float64 `json:"difficulty"`
+	}
+	var parsed req
+	if strings.Contains(r.Header.Get("Content-Type"), "application/json") {
+		if err := json.NewDecoder(r.Body).Decode(&parsed); err != nil {
+			logger.Warn("saved worker difficulty decode failed", "error", err, "user_id", user.UserID)
+		}
+	} else {
+		if err := r.ParseForm(); err != nil {
+			logger.Warn("saved worker difficulty parse form failed", "error", err, "user_id", user.UserID)
+		}
+		parsed.Hash = r.FormValue("hash")
+		if v := strings.TrimSpace(r.FormValue("difficulty")); v != "" {
+			if d, err := strconv.ParseFloat(v, 64); err == nil {
+				parsed.Difficulty = &d
+			}
+		}
+	}
+
+	hash := strings.ToLower(strings.TrimSpace(parsed.Hash))
+	if len(hash) != 64 {
+		http.Error(w, "invalid hash", http.StatusBadRequest)
+		return
+	}
+	if parsed.Difficulty == nil || !validSavedWorkerStaticDifficulty(*parsed.Difficulty) {
+		http.Error(w, "invalid difficulty", http.StatusBadRequest)
+		return
+	}
+	found, err := s.workerLists.SetSavedWorkerStaticDifficulty(user.UserID, hash, *parsed.Difficulty)
+	if err != nil {
+		logger.Warn("saved worker difficulty update failed", "error", err, "user_id", user.UserID)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(w, "worker not found", http.StatusNotFound)
+		return
+	}
+	s.refreshLiveSavedWorkerTrackingByHash(hash)
+	token, verified, err := s.workerLists.SavedWorkerOwnership(user.UserID, hash)
+	if err != nil {
+		logger.Warn("saved worker ownership lookup failed", "error", err, "user_id", user.UserID)
+	}
+
+	resp := struct {
+		OK            bool    `json:"ok"`
+		Difficulty    float64 `json:"difficulty"`
+		OwnerToken    string  `json:"owner_token,omitempty"`
+		OwnerVerified bool    `json:"owner_verified"`
+	}{
+		OK:            true,
+		Difficulty:    *parsed.Difficulty,
+		OwnerToken:    token,
+		OwnerVerified: verified,
+	}
+	setShortJSONCacheHeaders(w, true)
+	out, err := sonic.Marshal(resp)
+	if err != nil {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if _, err := w.Write(out); err != nil {
+		logger.Debug("saved worker difficulty json write failed", "error", err, "user_id", user.UserID)
+	}
+}
+
+// validSavedWorkerStaticDifficulty accepts 0 (back to vardiff) or a finite
+// difficulty a share target can represent.
+func validSavedWorkerStaticDifficulty(diff float64) bool {
+	if math.IsNaN(diff) || math.IsInf(diff, 0) || diff < 0 {
+		return false
+	}
+	return diff == 0 || diff >= minShareDifficulty
+}
@@ -0,0 +1,93 @@
+package main
+
+import "testing"
+
+func TestSavedWorkerStaticDifficultyAppliesAndClamps(t *testing.T) {
+	store, err := newWorkerListStore(t.TempDir() + "/saved_workers.sqlite")
+	if err != nil {
+		t.Fatalf("newWorkerListStore: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	const worker = "1BitcoinEaterAddressDontSendf59kuE.rig1"
+	hash := workerNameHash(worker)
+	for _, user := range []string{"user-1", "user-2"} {
+		if err := store.Add(user, worker); err != nil {
+			t.Fatalf("store.Add: %v", err)
+		}
+	}
+	if ok, err := store.SetSavedWorkerStaticDifficulty("user-1", hash, 8192); err != nil || !ok {
+		t.Fatalf("SetSavedWorkerStaticDifficulty: ok=%v err=%v", ok, err)
+	}
+	if ok, _ := store.SetSavedWorkerStaticDifficulty("user-3", hash, 1); ok {
+		t.Fatalf("a user who has not saved the worker must not set its difficulty")
+	}
+
+	cfg := Config{SavedWorkerStaticDifficulty: true, MaxDifficulty: 4096, VarDiffEnabled: true, TargetSharesPerMin: 5, DifficultyStepGranularity: 1}
+	mc := &MinerConn{cfg: cfg, savedWorkerStore: store}
+	atomicStoreFloat64(&mc.difficulty, 1)
+
+	// Connected before anything was saved for it: vardiff as usual.
+	mc.syncSavedWorkerState(workerNameHash("1BitcoinEaterAddressDontSendf59kuE.other"))
+	if mc.applyDifficultyOverride(cfg, worker) {
+		t.Fatalf("unexpected pin without a saved difficulty")
+	}
+
+	// Saved but not yet claimed by the rig: ignored.
+	mc.syncSavedWorkerState(hash)
+	if mc.applyDifficultyOverride(cfg, worker) {
+		t.Fatalf("static difficulty applied before the owner was verified")
+	}
+
+	token1, verified, err := store.SavedWorkerOwnership("user-1", hash)
+	if err != nil || token1 == "" || verified {
+		t.Fatalf("SavedWorkerOwnership = %q, %v, %v; want unverified token", token1, verified, err)
+	}
+	mc.claimSavedWorkerOwner(worker, "x,owner=not-the-token")
+	if mc.applyDifficultyOverride(cfg, worker) {
+		t.Fatalf("a wrong owner token must not verify the worker")
+	}
+	mc.claimSavedWorkerOwner(worker, "x,owner="+token1)
+	if !mc.applyDifficultyOverride(cfg, worker) {
+		t.Fatalf("expected the verified owner's static difficulty to pin the connection")
+	}
+	if got := atomicLoadFloat64(&mc.difficulty); got != 4096 {
+		t.Fatalf("difficulty = %v, want 4096 (clamped to max_difficulty)", got)
+	}
+
+	// Another user saving a lower value changes nothing.
+	if _, err := store.SetSavedWorkerStaticDifficulty("user-2", hash, 1); err != nil {
+		t.Fatalf("SetSavedWorkerStaticDifficulty: %v", err)
+	}
+	mc.syncSavedWorkerState(hash)
+	mc.applyDifficultyOverride(cfg, worker)
+	if got := atomicLoadFloat64(&mc.difficulty); got != 4096 {
+		t.Fatalf("difficulty = %v, want 4096 (unverified user's value ignored)", got)
+	}
+
+	// When the rig presents user-2's token instead, user-2 becomes the owner.
+	token2, _, _ := store.SavedWorkerOwnership("user-2", hash)
+	cfg.MinDifficulty = 1024
+	mc.cfg = cfg
+	mc.claimSavedWorkerOwner(worker, "owner="+token2)
+	mc.applyDifficultyOverride(cfg, worker)
+	if got := atomicLoadFloat64(&mc.difficulty); got != 1024 {
+		t.Fatalf("difficulty = %v, want 1024 (new owner, clamped to min_difficulty)", got)
+	}
+	if _, verified, _ := store.SavedWorkerOwnership("user-1", hash); verified {
+		t.Fatalf("previous owner still verified after another claim")
+	}
+
+	// An admin override takes precedence.
+	cfg.WorkerDifficultyOverrides = map[string]float64{"rig1": 2048}
+	mc.applyDifficultyOverride(cfg, worker)
+	if got := atomicLoadFloat64(&mc.difficulty); got != 2048 {
+		t.Fatalf("difficulty = %v, want admin override 2048", got)
+	}
+
+	// With the feature off the saved value is ignored.
+	cfg = Config{}
+	if mc.applyDifficultyOverride(cfg, worker) {
+		t.Fatalf("saved static difficulty applied while disabled")
+	}
+}
@@ -46,11 +46,15 @@ func (mc *MinerConn) buildCurrentJobCoinbaseDetail(job *Job) *ShareDetail {
 	if !ok && mc.lastJob == job && mc.lastJobID != "" {
 		parts, ok = mc.jobNotifyCoinbase[mc.lastJobID]
 	}
+	en1 := parts.extranonce1
+	if en1 == nil {
+		en1 = mc.extranonce1
+	}
 	mc.jobMu.Unlock()
 	if !ok || parts.coinb1 == "" || parts.coinb2 == "" {
 		return nil
 	}
-	coinbaseHex := parts.coinb1 + hex.EncodeToString(mc.extranonce1) + hex.EncodeToString(en2) + parts.coinb2
+	coinbaseHex := parts.coinb1 + hex.EncodeToString(en1) + hex.EncodeToString(en2) + parts.coinb2
 	detail := &ShareDetail{Coinbase: coinbaseHex}
 	detail.DecodeCoinbaseFields()
 	return detail
@@ -34,6 +34,7 @@ func (mc *MinerConn) buildShareDetail(job *Job, worker string, extranonce2 strin
 				feePercent,
 				job.OperatorDonationPercent,
 				job.WitnessCommitment,
+				job.extraOpReturnScript,
 				job.Template.CoinbaseAux.Flags,
 				job.CoinbaseMsg,
 				job.ScriptTime,
@@ -52,6 +53,7 @@ func (mc *MinerConn) buildShareDetail(job *Job, worker string, extranonce2 strin
 				totalValue,
 				feePercent,
 				job.WitnessCommitment,
+				job.extraOpReturnScript,
 				job.Template.CoinbaseAux.Flags,
 				job.CoinbaseMsg,
 				job.ScriptTime,
@@ -70,6 +72,7 @@ func (mc *MinerConn) buildShareDetail(job *Job, worker string, extranonce2 strin
 			job.PayoutScript,
 			job.CoinbaseValue,
 			job.WitnessCommitment,
+			job.extraOpReturnScript,
 			job.Template.CoinbaseAux.Flags,
 			job.CoinbaseMsg,
 			job.ScriptTime,
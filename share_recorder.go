@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// shareRecorderFlushInterval bounds how long recorded shares may sit in the
+// write buffer.
+const shareRecorderFlushInterval = time.Second
+
+// shareRecorderMaxJobs caps the set of jobs already defined in the file;
+// when it is exceeded the set is cleared and jobs are simply defined again.
+const shareRecorderMaxJobs = 1024
+
+var activeShareRecorder atomic.Pointer[shareRecorder]
+
+func setShareRecorder(r *shareRecorder) {
+	activeShareRecorder.Store(r)
+}
+
+func getShareRecorder() *shareRecorder {
+	return activeShareRecorder.Load()
+}
+
+// shareRecorder appends every hashed share to a -replay-shares file
+// (-record-shares). A job's template and coinbase inputs are written with
+// the first share recorded for it; later shares on the same job carry only
+// the submit and the connection's payout context.
+type shareRecorder struct {
+	path      string
+	mu        sync.Mutex
+	f         *os.File
+	w         *bufio.Writer
+	jobs      map[string]*Job
+	lastFlush time.Time
+}
+
+func newShareRecorder(path string) (*shareRecorder, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &shareRecorder{
+		path: path,
+		f:    f,
+		w:    bufio.NewWriterSize(f, 256<<10),
+		jobs: make(map[string]*Job, 64),
+	}, nil
+}
+
+// record writes one share and the result the pool gave it.
+func (r *shareRecorder) record(mc *MinerConn, task submissionTask, accepted bool) {
+	if r == nil || mc == nil || task.job == nil {
+		return
+	}
+	rec := mc.replayRecordFor(task, accepted)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.w == nil {
+		return
+	}
+	if r.jobs[task.job.JobID] != task.job {
+		if len(r.jobs) >= shareRecorderMaxJobs {
+			clear(r.jobs)
+		}
+		r.jobs[task.job.JobID] = task.job
+		setReplayJobFields(&rec, task.job)
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		logger.Warn("share recorder encode failed", "component", "miner", "kind", "record_shares", "error", err)
+		return
+	}
+	line = append(line, '\n')
+	if _, err := r.w.Write(line); err != nil {
+		logger.Warn("share recorder write failed", "component", "miner", "kind", "record_shares", "path", r.path, "error", err)
+		return
+	}
+	if now := time.Now(); now.Sub(r.lastFlush) >= shareRecorderFlushInterval {
+		r.lastFlush = now
+		if err := r.w.Flush(); err != nil {
+			logger.Warn("share recorder flush failed", "component", "miner", "kind", "record_shares", "path", r.path, "error", err)
+		}
+	}
+}
+
+// Close flushes buffered shares and closes the file.
+func (r *shareRecorder) Close() error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.w == nil {
+		return nil
+	}
+	err := r.w.Flush()
+	if cerr := r.f.Close(); err == nil {
+		err = cerr
+	}
+	r.w = nil
+	return err
+}
+
+// replayRecordFor captures the per-share part of a replay record: the
+// submit params and what the connection used to build this share's
+// coinbase.
+func (mc *MinerConn) replayRecordFor(task submissionTask, accepted bool) replayShareRecord {
+	job := task.job
+	en1 := task.extranonce1
+	if en1 == nil {
+		en1 = mc.extranonce1ForJob(task.jobID)
+	}
+	scriptTime := task.scriptTime
+	if scriptTime == 0 {
+		scriptTime = mc.scriptTimeForJob(task.jobID, job.ScriptTime)
+	}
+	diff := task.assignedDifficulty
+	if diff <= 0 {
+		diff = mc.assignedDifficulty(task.jobID)
+	}
+	params := []string{
+		task.workerName,
+		task.jobID,
+		hex.EncodeToString((&task).extranonce2Decoded()),
+		uint32ToHex8Lower(task.ntimeVal),
+		uint32ToHex8Lower(task.nonceVal),
+	}
+	if task.versionHex != "" {
+		params = append(params, task.versionHex)
+	}
+	mode := mc.cfg.CoinbasePayoutMode
+	if mode == "" {
+		mode = coinbasePayoutModeAuto
+	}
+	rec := replayShareRecord{
+		JobID:              job.JobID,
+		ScriptTime:         scriptTime,
+		Extranonce1:        hex.EncodeToString(en1),
+		Difficulty:         diff,
+		CoinbasePayoutMode: mode,
+		PoolFeePercent:     mc.cfg.PoolFeePercent,
+		Params:             params,
+		At:                 task.receivedAt,
+		Accepted:           accepted,
+	}
+	if mc.versionRoll {
+		rec.VersionMask = uint32ToHex8Lower(mc.versionMask)
+	}
+	if _, script, ok := mc.workerWalletDataRef(task.workerName); ok {
+		rec.WorkerScript = hex.EncodeToString(script)
+	}
+	return rec
+}
+
+// setReplayJobFields adds the job definition to rec. Transactions are left
+// out of the template in favour of the merkle branches, which is all share
+// validation needs and keeps the file small.
+func setReplayJobFields(rec *replayShareRecord, job *Job) {
+	tpl := job.Template
+	tpl.Transactions = nil
+	rec.Template = &tpl
+	rec.MerkleBranches = job.MerkleBranches
+	rec.CoinbaseMsg = job.CoinbaseMsg
+	rec.PayoutScript = hex.EncodeToString(job.PayoutScript)
+	rec.DonationScript = hex.EncodeToString(job.DonationScript)
+	rec.DonationPercent = job.OperatorDonationPercent
+	rec.DustThreshold = job.CoinbaseDustThreshold
+	for _, split := range job.PayoutSplits {
+		rec.PayoutSplits = append(rec.PayoutSplits, replayPayoutSplit{Script: hex.EncodeToString(split.Script), Weight: split.Weight})
+	}
+	rec.ExtraOpReturn = extraOpReturnData(job.extraOpReturnScript)
+	rec.Extranonce2Size = job.Extranonce2Size
+	rec.TemplateExtranonce2Size = job.TemplateExtraNonce2Size
+}
+
+// extraOpReturnData is the inverse of coinbaseExtraOpReturnScript: the data
+// hex without the OP_RETURN and push opcodes.
+func extraOpReturnData(script []byte) string {
+	if len(script) < 2 {
+		return ""
+	}
+	data := script[2:]
+	if script[1] == 0x4c && len(script) >= 3 {
+		data = script[3:]
+	}
+	return hex.EncodeToString(data)
+}
@@ -0,0 +1,450 @@
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// replayMaxShareLine bounds one -replay-shares record; a template with a
+// full block of transactions fits comfortably.
+const replayMaxShareLine = 64 << 20
+
+// replayMaxListedRegressions caps how many regressions are printed one per
+// line; the total is always reported.
+const replayMaxListedRegressions = 20
+
+// replayShareRecord is one line of a -replay-shares file: the job a share
+// was mined on, the submit params as sent and the result the pool gave it.
+// The job must reproduce the exact coinbase the miner hashed, so the record
+// carries the coinbase inputs rather than relying on the current config.
+// -record-shares writes this format.
+type replayShareRecord struct {
+	// Template is the job's getblocktemplate result, with the version bits
+	// the pool applied. A line that carries it defines (or redefines) the
+	// job for JobID together with the job fields below; later lines for the
+	// same job may leave them out. Transactions may be omitted when
+	// MerkleBranches is given.
+	Template                *GetBlockTemplateResult `json:"template,omitempty"`
+	MerkleBranches          []string                `json:"merkle_branches,omitempty"`
+	JobID                   string                  `json:"job_id"`
+	CoinbaseMsg             string                  `json:"coinbase_msg,omitempty"`
+	PayoutScript            string                  `json:"payout_script,omitempty"`
+	DonationScript          string                  `json:"donation_script,omitempty"`
+	DonationPercent         float64                 `json:"donation_percent,omitempty"`
+	PayoutSplits            []replayPayoutSplit     `json:"payout_splits,omitempty"`
+	DustThreshold           int64                   `json:"dust_threshold,omitempty"`
+	ExtraOpReturn           string                  `json:"extra_op_return,omitempty"`
+	Extranonce2Size         int                     `json:"extranonce2_size,omitempty"`
+	TemplateExtranonce2Size int                     `json:"template_extranonce2_size,omitempty"`
+
+	ScriptTime  int64   `json:"script_time"`
+	Extranonce1 string  `json:"extranonce1"`
+	VersionMask string  `json:"version_mask,omitempty"`
+	Difficulty  float64 `json:"difficulty"`
+	// CoinbasePayoutMode, PoolFeePercent and WorkerScript select the payout
+	// layout the connection used: with a worker script and a fee the
+	// coinbase splits between pool, worker and any donation or
+	// payout_splits outputs. An empty mode replays as single_pool.
+	CoinbasePayoutMode string  `json:"coinbase_payout_mode,omitempty"`
+	PoolFeePercent     float64 `json:"pool_fee_percent,omitempty"`
+	WorkerScript       string  `json:"worker_script,omitempty"`
+	// Params are the mining.submit params: worker, job id, extranonce2,
+	// ntime, nonce and optionally version.
+	Params   []string  `json:"params"`
+	At       time.Time `json:"at"`
+	Accepted bool      `json:"accepted"`
+}
+
+// replayPayoutSplit is a resolved payout_splits entry.
+type replayPayoutSplit struct {
+	Script string  `json:"script"`
+	Weight float64 `json:"weight"`
+}
+
+// replayConn stands in for the miner socket; responses are discarded since
+// outcomes are read from the connection's share counters.
+type replayConn struct{}
+
+func (replayConn) Read([]byte) (int, error)         { return 0, io.EOF }
+func (replayConn) Write(b []byte) (int, error)      { return len(b), nil }
+func (replayConn) Close() error                     { return nil }
+func (replayConn) LocalAddr() net.Addr              { return &net.IPAddr{} }
+func (replayConn) RemoteAddr() net.Addr             { return &net.IPAddr{} }
+func (replayConn) SetDeadline(time.Time) error      { return nil }
+func (replayConn) SetReadDeadline(time.Time) error  { return nil }
+func (replayConn) SetWriteDeadline(time.Time) error { return nil }
+
+// replayJob rebuilds a Job from a record the way buildJob does from a
+// template, minus the parts that depend on the running pool.
+func replayJob(rec replayShareRecord, cfg Config) (*Job, error) {
+	tpl := *rec.Template
+	target, err := validateBits(tpl.Bits, tpl.Target)
+	if err != nil {
+		return nil, err
+	}
+	var txids [][]byte
+	merkleBranches := rec.MerkleBranches
+	if len(merkleBranches) == 0 {
+		if txids, err = validateTransactions(tpl.Transactions); err != nil {
+			return nil, err
+		}
+		merkleBranches = buildMerkleBranches(txids)
+	}
+	merkleBranchesBytes, err := decodeMerkleBranchesBytes(merkleBranches)
+	if err != nil {
+		return nil, err
+	}
+	var prevBytes [32]byte
+	if len(tpl.Previous) != 64 {
+		return nil, fmt.Errorf("previousblockhash hex must be 64 chars")
+	}
+	if err := decodeHexToFixedBytes(prevBytes[:], tpl.Previous); err != nil {
+		return nil, fmt.Errorf("decode previousblockhash: %w", err)
+	}
+	var bitsBytes [4]byte
+	if err := decodeHex8To4(&bitsBytes, tpl.Bits); err != nil {
+		return nil, fmt.Errorf("decode bits: %w", err)
+	}
+	var flagsBytes []byte
+	if tpl.CoinbaseAux.Flags != "" {
+		if flagsBytes, err = hex.DecodeString(tpl.CoinbaseAux.Flags); err != nil {
+			return nil, fmt.Errorf("decode coinbase flags: %w", err)
+		}
+	}
+	var commitScript []byte
+	if tpl.DefaultWitnessCommitment != "" {
+		if commitScript, err = hex.DecodeString(tpl.DefaultWitnessCommitment); err != nil {
+			return nil, fmt.Errorf("decode witness commitment: %w", err)
+		}
+	}
+	extraOpReturn, err := coinbaseExtraOpReturnScript(rec.ExtraOpReturn)
+	if err != nil {
+		return nil, err
+	}
+	payoutScript, err := hex.DecodeString(rec.PayoutScript)
+	if err != nil || len(payoutScript) == 0 {
+		return nil, fmt.Errorf("payout_script must be non-empty hex")
+	}
+	if rec.Extranonce2Size <= 0 {
+		return nil, fmt.Errorf("extranonce2_size must be > 0")
+	}
+	donationScript, err := hex.DecodeString(rec.DonationScript)
+	if err != nil {
+		return nil, fmt.Errorf("decode donation_script: %w", err)
+	}
+	var splits []coinbasePayoutSplit
+	for i, split := range rec.PayoutSplits {
+		script, err := hex.DecodeString(split.Script)
+		if err != nil || len(script) == 0 {
+			return nil, fmt.Errorf("payout_splits[%d].script must be non-empty hex", i)
+		}
+		splits = append(splits, coinbasePayoutSplit{Script: script, Weight: split.Weight})
+	}
+
+	return &Job{
+		JobID:                   rec.JobID,
+		Template:                tpl,
+		Target:                  target,
+		targetBE:                uint256BEFromBigInt(target),
+		networkDiff:             difficultyFromTarget(target),
+		CreatedAt:               time.Unix(rec.ScriptTime, 0),
+		ScriptTime:              rec.ScriptTime,
+		Extranonce2Size:         rec.Extranonce2Size,
+		CoinbaseValue:           tpl.CoinbaseValue,
+		WitnessCommitment:       tpl.DefaultWitnessCommitment,
+		CoinbaseMsg:             rec.CoinbaseMsg,
+		MerkleBranches:          merkleBranches,
+		merkleBranchesBytes:     merkleBranchesBytes,
+		Transactions:            tpl.Transactions,
+		TransactionIDs:          txids,
+		PayoutScript:            payoutScript,
+		DonationScript:          donationScript,
+		OperatorDonationPercent: rec.DonationPercent,
+		CoinbaseDustThreshold:   rec.DustThreshold,
+		PayoutSplits:            splits,
+		VersionMask:             computePoolMask(tpl, cfg),
+		PrevHash:                tpl.Previous,
+		prevHashBytes:           prevBytes,
+		bitsBytes:               bitsBytes,
+		coinbaseFlagsBytes:      flagsBytes,
+		witnessCommitScript:     commitScript,
+		extraOpReturnScript:     extraOpReturn,
+		TemplateExtraNonce2Size: rec.TemplateExtranonce2Size,
+	}, nil
+}
+
+// newReplayMinerConn builds an authorized, subscribed connection with the
+// current share policy and no network, RPC or accounting attached. The
+// payout layout is set per record by setReplayPayout.
+func newReplayMinerConn(cfg Config, metrics *PoolMetrics, worker string, en1 []byte) *MinerConn {
+	maxRecentJobs := cfg.MaxRecentJobs
+	if maxRecentJobs <= 0 {
+		maxRecentJobs = defaultRecentJobs
+	}
+	mask, minBits := versionRollingPolicyFromConfig(cfg)
+	mc := &MinerConn{
+		id:                "replay",
+		conn:              replayConn{},
+		cfg:               cfg,
+		vardiff:           buildVarDiffConfig(cfg),
+		metrics:           metrics,
+		extranonce1:       en1,
+		extranonce1Hex:    hex.EncodeToString(en1),
+		lockDifficulty:    true,
+		authorized:        true,
+		subscribed:        true,
+		stats:             MinerStats{Worker: worker, WorkerSHA256: workerNameHash(worker)},
+		activeJobs:        make(map[string]*Job, maxRecentJobs),
+		jobDifficulty:     make(map[string]float64, maxRecentJobs),
+		jobScriptTime:     make(map[string]int64, maxRecentJobs),
+		jobNotifyCoinbase: make(map[string]notifiedCoinbaseParts, maxRecentJobs),
+		maxRecentJobs:     maxRecentJobs,
+		poolMask:          mask,
+		minVerBits:        minBits,
+		workerWallets:     make(map[string]workerWalletState, 1),
+	}
+	if cfg.ShareCheckDuplicate {
+		mc.shareCache = make(map[string]*duplicateShareSet, maxRecentJobs)
+		mc.evictedShareCache = make(map[string]*evictedCacheEntry, maxRecentJobs)
+	}
+	if cfg.ShareCheckNTimeWindow {
+		mc.jobNTimeBounds = make(map[string]jobNTimeBounds, maxRecentJobs)
+	}
+	return mc
+}
+
+// setReplayPayout applies the record's payout context so the coinbase is
+// rebuilt with the same outputs the miner hashed.
+func (mc *MinerConn) setReplayPayout(rec replayShareRecord, worker string) error {
+	mc.cfg.CoinbasePayoutMode = rec.CoinbasePayoutMode
+	if mc.cfg.CoinbasePayoutMode == "" {
+		mc.cfg.CoinbasePayoutMode = coinbasePayoutModeSinglePool
+	}
+	mc.cfg.PoolFeePercent = rec.PoolFeePercent
+	if rec.WorkerScript == "" {
+		mc.walletMu.Lock()
+		delete(mc.workerWallets, worker)
+		mc.walletMu.Unlock()
+		return nil
+	}
+	script, err := hex.DecodeString(rec.WorkerScript)
+	if err != nil {
+		return fmt.Errorf("worker_script: %w", err)
+	}
+	addr := workerBaseAddress(worker)
+	if addr == "" {
+		addr = worker
+	}
+	mc.setWorkerWallet(worker, addr, script)
+	return nil
+}
+
+type replayRegression struct {
+	line   int
+	jobID  string
+	worker string
+	reason string
+}
+
+// replayShareResult tallies one replay run.
+type replayShareResult struct {
+	total          int
+	accepted       int
+	rejected       int
+	blocks         int
+	wasAccepted    int
+	rejectReasons  map[string]int
+	regressions    []replayRegression
+	newlyAccepted  int
+	elapsed        time.Duration
+	regressionsAll int
+}
+
+// replayShares feeds every record in r through the submit path of a
+// per-worker connection, in file order, so duplicate detection, bans and
+// job retention behave as they would have live. Shares that solve a block
+// are counted but not processed further, since that would submit them.
+func replayShares(r io.Reader, cfg Config) (replayShareResult, error) {
+	res := replayShareResult{rejectReasons: make(map[string]int)}
+	metrics := NewPoolMetrics()
+	conns := make(map[string]*MinerConn)
+	jobs := make(map[string]*Job)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), replayMaxShareLine)
+	start := time.Now()
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := strings.TrimSpace(scanner.Text())
+		if raw == "" || strings.HasPrefix(raw, "#") {
+			continue
+		}
+		var rec replayShareRecord
+		if err := json.Unmarshal([]byte(raw), &rec); err != nil {
+			return res, fmt.Errorf("line %d: %w", line, err)
+		}
+		if len(rec.Params) < 2 {
+			return res, fmt.Errorf("line %d: params must hold at least worker and job id", line)
+		}
+		en1, err := hex.DecodeString(rec.Extranonce1)
+		if err != nil {
+			return res, fmt.Errorf("line %d: extranonce1: %w", line, err)
+		}
+		job := jobs[rec.JobID]
+		if rec.Template != nil {
+			if job, err = replayJob(rec, cfg); err != nil {
+				return res, fmt.Errorf("line %d: job %s: %w", line, rec.JobID, err)
+			}
+			jobs[rec.JobID] = job
+		}
+		if job == nil {
+			return res, fmt.Errorf("line %d: job %s has no template on an earlier line", line, rec.JobID)
+		}
+
+		worker := rec.Params[0]
+		connKey := worker + "/" + rec.Extranonce1
+		mc := conns[connKey]
+		if mc == nil {
+			mc = newReplayMinerConn(cfg, metrics, worker, en1)
+			conns[connKey] = mc
+		}
+		if mc.activeJobs[rec.Params[1]] != job {
+			mc.trackJob(job, rec.Params[1], false)
+		}
+		mc.jobScriptTime[rec.Params[1]] = rec.ScriptTime
+		if err := mc.setReplayPayout(rec, worker); err != nil {
+			return res, fmt.Errorf("line %d: %w", line, err)
+		}
+		if rec.Difficulty > 0 {
+			mc.setJobDifficulty(rec.Params[1], rec.Difficulty)
+			atomicStoreFloat64(&mc.difficulty, rec.Difficulty)
+			mc.shareTarget.Store(targetFromDifficulty(rec.Difficulty))
+		}
+		mc.versionRoll, mc.versionMask = false, 0
+		if rec.VersionMask != "" {
+			if m, ok := parseUint32Hexish(rec.VersionMask); ok && m&mc.poolMask != 0 {
+				mc.versionRoll, mc.versionMask = true, m&mc.poolMask
+			}
+		}
+
+		now := rec.At
+		if now.IsZero() {
+			now = time.Unix(rec.ScriptTime, 0)
+		}
+		before := mc.snapshotStats()
+		isBlock := false
+		if params, ok := mc.parseSubmitParamsStrings(line, rec.Params, now); ok {
+			if task, ok := mc.prepareSubmissionTaskFromParsed(line, params, now); ok {
+				task.receivedAt = now
+				if ctx, ok := mc.prepareShareContext(task); ok {
+					if ctx.isBlock {
+						isBlock = true
+					} else {
+						mc.processShare(task, ctx)
+					}
+				}
+			}
+		}
+		after := mc.snapshotStats()
+		accepted := isBlock || after.Accepted > before.Accepted
+
+		res.total++
+		if rec.Accepted {
+			res.wasAccepted++
+		}
+		switch {
+		case isBlock:
+			res.blocks++
+			res.accepted++
+		case accepted:
+			res.accepted++
+			if !rec.Accepted {
+				res.newlyAccepted++
+			}
+		default:
+			res.rejected++
+			reason := "banned"
+			if after.Rejected > before.Rejected {
+				if reason = mc.snapshotShareInfo().LastReject; reason == "" {
+					reason = "unspecified"
+				}
+			}
+			res.rejectReasons[reason]++
+			if rec.Accepted {
+				res.regressionsAll++
+				if len(res.regressions) < replayMaxListedRegressions {
+					res.regressions = append(res.regressions, replayRegression{line: line, jobID: rec.Params[1], worker: worker, reason: reason})
+				}
+			}
+		}
+	}
+	res.elapsed = time.Since(start)
+	if err := scanner.Err(); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+func (res replayShareResult) write(w io.Writer) {
+	perShare := time.Duration(0)
+	if res.total > 0 {
+		perShare = res.elapsed / time.Duration(res.total)
+	}
+	fmt.Fprintf(w, "replayed %d shares in %s (%s/share)\n", res.total, res.elapsed.Round(time.Microsecond), perShare)
+	fmt.Fprintf(w, "accepted %d (historically %d), rejected %d, block candidates %d\n", res.accepted, res.wasAccepted, res.rejected, res.blocks)
+	if len(res.rejectReasons) > 0 {
+		reasons := make([]string, 0, len(res.rejectReasons))
+		for reason := range res.rejectReasons {
+			reasons = append(reasons, reason)
+		}
+		sort.Strings(reasons)
+		for _, reason := range reasons {
+			fmt.Fprintf(w, "  rejected %-24s %d\n", reason, res.rejectReasons[reason])
+		}
+	}
+	if res.newlyAccepted > 0 {
+		fmt.Fprintf(w, "%d historically rejected shares are now accepted\n", res.newlyAccepted)
+	}
+	if res.regressionsAll == 0 {
+		fmt.Fprintln(w, "no regressions")
+		return
+	}
+	fmt.Fprintf(w, "REGRESSIONS: %d historically accepted shares are now rejected\n", res.regressionsAll)
+	for _, r := range res.regressions {
+		fmt.Fprintf(w, "  line %d: job %s worker %s: %s\n", r.line, r.jobID, r.worker, r.reason)
+	}
+	if res.regressionsAll > len(res.regressions) {
+		fmt.Fprintf(w, "  ... %d more\n", res.regressionsAll-len(res.regressions))
+	}
+}
+
+// runShareReplay implements -replay-shares: it replays path against cfg,
+// prints the tallies and returns the process exit code (1 when a share
+// that was accepted is now rejected, 2 when the file cannot be replayed).
+func runShareReplay(path string, cfg Config, w io.Writer) int {
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(w, "replay-shares: %v\n", err)
+		return 2
+	}
+	defer f.Close()
+	res, err := replayShares(f, cfg)
+	if err != nil {
+		fmt.Fprintf(w, "replay-shares: %s: %v\n", path, err)
+		return 2
+	}
+	res.write(w)
+	if res.regressionsAll > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func replayTestRecord(params []string, accepted bool) replayShareRecord {
+	return replayShareRecord{
+		Template: &GetBlockTemplateResult{
+			Height:        101,
+			CurTime:       1700000000,
+			Mintime:       1700000000,
+			Bits:          "1d00ffff",
+			Previous:      strings.Repeat("00", 32),
+			CoinbaseValue: 50 * 1e8,
+			Version:       0x20000000,
+		},
+		JobID:                   "replay-job",
+		CoinbaseMsg:             "goPool-test",
+		ScriptTime:              1700000000,
+		PayoutScript:            "51",
+		Extranonce1:             "01020304",
+		Extranonce2Size:         4,
+		TemplateExtranonce2Size: 8,
+		Difficulty:              1e-9,
+		Params:                  params,
+		Accepted:                accepted,
+	}
+}
+
+func writeReplayFile(t *testing.T, recs ...replayShareRecord) string {
+	t.Helper()
+	var buf bytes.Buffer
+	for _, rec := range recs {
+		line, err := json.Marshal(rec)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	path := filepath.Join(t.TempDir(), "shares.jsonl")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	return path
+}
+
+func TestRunShareReplayTalliesAndRegressions(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.ShareCheckDuplicate = true
+	share := []string{"worker1", "replay-job", "00000000", "6553f100", "00000001"}
+
+	path := writeReplayFile(t,
+		replayTestRecord(share, true),
+		replayTestRecord([]string{"worker1", "missing-job", "00000000", "6553f100", "00000002"}, false),
+	)
+	var out bytes.Buffer
+	if code := runShareReplay(path, cfg, &out); code != 0 {
+		t.Fatalf("exit code = %d, want 0; output:\n%s", code, out.String())
+	}
+	if !strings.Contains(out.String(), "accepted 1 (historically 1), rejected 1") {
+		t.Fatalf("unexpected tallies:\n%s", out.String())
+	}
+
+	// The same share twice: the duplicate was accepted historically but is
+	// rejected now, which is a regression.
+	path = writeReplayFile(t, replayTestRecord(share, true), replayTestRecord(share, true))
+	out.Reset()
+	if code := runShareReplay(path, cfg, &out); code != 1 {
+		t.Fatalf("exit code = %d, want 1; output:\n%s", code, out.String())
+	}
+	if !strings.Contains(out.String(), "REGRESSIONS: 1") {
+		t.Fatalf("expected a regression:\n%s", out.String())
+	}
+}
+
+func TestRunShareReplayBadInput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shares.jsonl")
+	if err := os.WriteFile(path, []byte("{not json\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	var out bytes.Buffer
+	if code := runShareReplay(path, defaultConfig(), &out); code != 2 {
+		t.Fatalf("exit code = %d, want 2; output:\n%s", code, out.String())
+	}
+}
+
+func TestRecordedSharesReplayMultiOutputCoinbase(t *testing.T) {
+	job := benchmarkSubmitJob(t)
+	job.Template.Version = 0x20000000
+	job.DonationScript = []byte{0x52}
+	job.OperatorDonationPercent = 1
+	mc := benchmarkMinerConnForSubmit(NewPoolMetrics())
+	mc.cfg.PoolFeePercent = 2
+	worker := mc.stats.Worker
+	if mc.payoutLayout(job, worker) != payoutLayoutTriple {
+		t.Fatalf("test setup should build a pool/worker/donation coinbase")
+	}
+
+	path := filepath.Join(t.TempDir(), "shares.jsonl")
+	rec, err := newShareRecorder(path)
+	if err != nil {
+		t.Fatalf("newShareRecorder: %v", err)
+	}
+	setShareRecorder(rec)
+	defer setShareRecorder(nil)
+
+	const shares = 16
+	for nonce := uint32(1); nonce <= shares; nonce++ {
+		task := submissionTask{
+			mc:               mc,
+			job:              job,
+			jobID:            job.JobID,
+			workerName:       worker,
+			extranonce2Large: []byte{0, 0, 0, 0},
+			ntimeVal:         uint32(job.Template.CurTime),
+			nonceVal:         nonce,
+			useVersion:       uint32(job.Template.Version),
+			scriptTime:       job.ScriptTime,
+			receivedAt:       time.Unix(job.Template.CurTime, 0),
+			policyReject:     submitPolicyReject{reason: rejectUnknown},
+		}
+		// Demand exactly the share's own difficulty, so the replay only
+		// accepts it if it rebuilds the same three-output coinbase.
+		ctx, ok := mc.prepareShareContext(task)
+		if !ok {
+			t.Fatalf("prepareShareContext failed")
+		}
+		task.assignedDifficulty = ctx.shareDiff
+		mc.processSubmissionTask(task)
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatalf("close recorder: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read recording: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != shares || !strings.Contains(lines[0], `"template"`) || strings.Contains(lines[1], `"template"`) {
+		t.Fatalf("expected %d lines with the job defined once on the first:\n%s", shares, data)
+	}
+
+	var out bytes.Buffer
+	if code := runShareReplay(path, defaultConfig(), &out); code != 0 {
+		t.Fatalf("exit code = %d, want 0; output:\n%s", code, out.String())
+	}
+	if !strings.Contains(out.String(), "accepted 16 (historically 16), rejected 0") {
+		t.Fatalf("unexpected tallies:\n%s", out.String())
+	}
+
+	// Without the worker output the coinbase no longer matches, so about
+	// half the shares fall short of their recorded difficulty.
+	stripped := strings.ReplaceAll(string(data), `"worker_script"`, `"ignored"`)
+	if err := os.WriteFile(path, []byte(stripped), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	out.Reset()
+	if code := runShareReplay(path, defaultConfig(), &out); code != 1 {
+		t.Fatalf("exit code = %d, want 1 without the worker output; output:\n%s", code, out.String())
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// sourcePortWindow is the sampling window for per-IP source-port churn. The
+// threshold is expressed per minute, so NAT gateways that open a handful of
+// new ports per minute stay well below it regardless of how long they run.
+const sourcePortWindow = time.Minute
+
+// sourcePortTracker counts distinct source ports per remote IP and flags
+// addresses that cycle through ports faster than a configured rate (for
+// example a single host spraying thousands of short-lived connections).
+// With no ban duration the churn is still reported, once per window, but the
+// host is not refused.
+type sourcePortTracker struct {
+	mu          sync.Mutex
+	entries     map[string]*sourcePortEntry
+	maxPerMin   int
+	banDuration time.Duration
+	lastSweep   time.Time
+}
+
+type sourcePortEntry struct {
+	windowStart time.Time
+	ports       map[int]struct{}
+	accepts     int
+	minPort     int
+	maxPort     int
+	bannedUntil time.Time
+	reported    bool
+}
+
+// sourcePortAnomaly summarizes the window that tripped the churn threshold.
+type sourcePortAnomaly struct {
+	DistinctPorts int
+	Accepts       int
+	MinPort       int
+	MaxPort       int
+	Window        time.Duration
+}
+
+func newSourcePortTracker(maxPerMin int, banDuration time.Duration) *sourcePortTracker {
+	if maxPerMin <= 0 {
+		return nil
+	}
+	return &sourcePortTracker{
+		entries:     make(map[string]*sourcePortEntry),
+		maxPerMin:   maxPerMin,
+		banDuration: banDuration,
+	}
+}
+
+// observe records an accepted connection from host:port. It returns false
+// while the host is banned. anomaly is non-nil only for the accept that
+// crosses the threshold, so callers log once per incident (once per window
+// when not banning).
+func (st *sourcePortTracker) observe(host string, port int, now time.Time) (allowed bool, anomaly *sourcePortAnomaly) {
+	if st == nil || host == "" {
+		return true, nil
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.sweepLocked(now)
+
+	entry, ok := st.entries[host]
+	if !ok {
+		entry = &sourcePortEntry{windowStart: now}
+		st.entries[host] = entry
+	}
+	if !entry.bannedUntil.IsZero() {
+		if now.Before(entry.bannedUntil) {
+			return false, nil
+		}
+		entry.bannedUntil = time.Time{}
+		entry.resetLocked(now)
+	}
+	if now.Sub(entry.windowStart) >= sourcePortWindow {
+		entry.resetLocked(now)
+	}
+
+	entry.accepts++
+	if entry.ports == nil {
+		entry.ports = make(map[int]struct{}, 8)
+	}
+	if _, seen := entry.ports[port]; !seen {
+		entry.ports[port] = struct{}{}
+		if entry.minPort == 0 || port < entry.minPort {
+			entry.minPort = port
+		}
+		if port > entry.maxPort {
+			entry.maxPort = port
+		}
+	}
+	if len(entry.ports) <= st.maxPerMin || entry.reported {
+		return true, nil
+	}
+
+	anomaly = &sourcePortAnomaly{
+		DistinctPorts: len(entry.ports),
+		Accepts:       entry.accepts,
+		MinPort:       entry.minPort,
+		MaxPort:       entry.maxPort,
+		Window:        now.Sub(entry.windowStart),
+	}
+	if st.banDuration <= 0 {
+		entry.reported = true
+		return true, anomaly
+	}
+	entry.bannedUntil = now.Add(st.banDuration)
+	entry.ports = nil
+	return false, anomaly
+}
+
+func (e *sourcePortEntry) resetLocked(now time.Time) {
+	e.windowStart = now
+	e.ports = nil
+	e.accepts = 0
+	e.minPort = 0
+	e.maxPort = 0
+	e.reported = false
+}
+
+// sweepLocked drops idle, unbanned hosts once per window so the map does not
+// grow with every address that ever connected.
+func (st *sourcePortTracker) sweepLocked(now time.Time) {
+	if now.Sub(st.lastSweep) < sourcePortWindow {
+		return
+	}
+	st.lastSweep = now
+	for host, entry := range st.entries {
+		if now.Before(entry.bannedUntil) {
+			continue
+		}
+		if now.Sub(entry.windowStart) >= sourcePortWindow {
+			delete(st.entries, host)
+		}
+	}
+}
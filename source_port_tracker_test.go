@@ -0,0 +1,79 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSourcePortTrackerBansRapidPortChurn(t *testing.T) {
+	st := newSourcePortTracker(10, time.Hour)
+	now := time.Unix(1700000000, 0)
+
+	for i := range 10 {
+		if ok, anomaly := st.observe("203.0.113.7", 40000+i, now); !ok || anomaly != nil {
+			t.Fatalf("accept %d should be allowed", i)
+		}
+	}
+	// Reusing a port does not count as churn.
+	if ok, _ := st.observe("203.0.113.7", 40000, now); !ok {
+		t.Fatalf("repeated port should be allowed")
+	}
+	ok, anomaly := st.observe("203.0.113.7", 40010, now)
+	if ok || anomaly == nil {
+		t.Fatalf("expected ban on the 11th distinct port")
+	}
+	if anomaly.DistinctPorts != 11 || anomaly.MinPort != 40000 || anomaly.MaxPort != 40010 {
+		t.Fatalf("unexpected anomaly %+v", anomaly)
+	}
+	if ok, anomaly := st.observe("203.0.113.7", 50000, now.Add(time.Minute)); ok || anomaly != nil {
+		t.Fatalf("banned host should be rejected without a new anomaly")
+	}
+	if ok, _ := st.observe("198.51.100.1", 40000, now); !ok {
+		t.Fatalf("other hosts must not be affected")
+	}
+	if ok, _ := st.observe("203.0.113.7", 50001, now.Add(2*time.Hour)); !ok {
+		t.Fatalf("ban should expire")
+	}
+}
+
+func TestSourcePortTrackerIsRateBased(t *testing.T) {
+	st := newSourcePortTracker(10, time.Hour)
+	now := time.Unix(1700000000, 0)
+	// A NAT gateway opening a few new ports per minute never trips the limit,
+	// no matter how many ports it uses in total.
+	for minute := range 60 {
+		for i := range 5 {
+			port := 1024 + minute*5 + i
+			if ok, _ := st.observe("192.0.2.10", port, now.Add(time.Duration(minute)*time.Minute)); !ok {
+				t.Fatalf("NAT churn should be allowed (minute %d)", minute)
+			}
+		}
+	}
+	if newSourcePortTracker(0, time.Hour) != nil {
+		t.Fatalf("zero limit should disable the tracker")
+	}
+}
+
+func TestSourcePortTrackerReportsWithoutBanDuration(t *testing.T) {
+	st := newSourcePortTracker(3, 0)
+	if st == nil {
+		t.Fatalf("a zero ban duration must still track churn")
+	}
+	now := time.Unix(1700000000, 0)
+	for i := range 3 {
+		st.observe("203.0.113.9", 40000+i, now)
+	}
+	ok, anomaly := st.observe("203.0.113.9", 40003, now)
+	if !ok || anomaly == nil {
+		t.Fatalf("expected churn reported without a ban, got ok=%v anomaly=%v", ok, anomaly)
+	}
+	if ok, anomaly := st.observe("203.0.113.9", 40004, now.Add(time.Second)); !ok || anomaly != nil {
+		t.Fatalf("churn should be reported once per window, got ok=%v anomaly=%v", ok, anomaly)
+	}
+	for i := range 3 {
+		st.observe("203.0.113.9", 41000+i, now.Add(time.Minute))
+	}
+	if _, anomaly := st.observe("203.0.113.9", 41003, now.Add(time.Minute)); anomaly == nil {
+		t.Fatalf("expected a new report in the next window")
+	}
+}
@@ -160,6 +160,12 @@ func ensureStateTables(db *sql.DB) error {
 	if err := addSavedWorkersBestDifficultyColumn(db); err != nil {
 		return err
 	}
+	if err := addSavedWorkersStaticDifficultyColumn(db); err != nil {
+		return err
+	}
+	if err := addSavedWorkersOwnerColumns(db); err != nil {
+		return err
+	}
 	if err := addSavedWorkersDisplayColumn(db); err != nil {
 		return err
 	}
@@ -281,6 +287,23 @@ func ensureStateTables(db *sql.DB) error {
 	`); err != nil {
 		return err
 	}
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS admin_session_key (
+			id INTEGER PRIMARY KEY CHECK (id = 1),
+			secret BLOB NOT NULL,
+			created_at_unix INTEGER NOT NULL
+		)
+	`); err != nil {
+		return err
+	}
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS admin_session_revocations (
+			token_sha256 TEXT PRIMARY KEY,
+			expires_at_unix INTEGER NOT NULL
+		)
+	`); err != nil {
+		return err
+	}
 	if err := ensureWorkerDBChangeTracking(db); err != nil {
 		return err
 	}
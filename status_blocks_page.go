@@ -0,0 +1,135 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultBlocksPageLimit = 50
+	maxBlocksPageLimit     = 500
+)
+
+// blocksPage is the /api/blocks response: one newest-first page of found
+// blocks plus the total number of blocks matching the query.
+type blocksPage struct {
+	Blocks []FoundBlockView `json:"blocks"`
+	Total  int              `json:"total"`
+	Limit  int              `json:"limit"`
+	Offset int              `json:"offset"`
+	Since  int64            `json:"since,omitempty"`
+}
+
+// loadFoundBlocksPage reads one page of found_blocks_log, newest first,
+// limited to blocks recorded at or after since (unix seconds, 0 = all).
+// ok is false when the state database is unavailable.
+func loadFoundBlocksPage(since int64, limit, offset int) (blocks []FoundBlockView, total int, ok bool, err error) {
+	db := getSharedStateDB()
+	if db == nil {
+		return nil, 0, false, nil
+	}
+	const filter = " FROM found_blocks_log WHERE created_at_unix >= ?" +
+		" AND json NOT LIKE '%\"hash\":\"dummyhash\"%' AND json NOT LIKE '%\"also_found\":true%'"
+	if err := db.QueryRow("SELECT COUNT(*)"+filter, since).Scan(&total); err != nil {
+		return nil, 0, true, err
+	}
+	if offset >= total {
+		return []FoundBlockView{}, total, true, nil
+	}
+	rows, err := db.Query("SELECT json"+filter+" ORDER BY created_at_unix DESC, id DESC LIMIT ? OFFSET ?", since, limit, offset)
+	if err != nil {
+		return nil, 0, true, err
+	}
+	defer rows.Close()
+	blocks = make([]FoundBlockView, 0, limit)
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			continue
+		}
+		if view, ok := parseFoundBlockLogLine(line); ok {
+			blocks = append(blocks, view)
+		}
+	}
+	return blocks, total, true, rows.Err()
+}
+
+// pageCachedFoundBlocks pages the status snapshot's found blocks when the
+// state database is not available.
+func pageCachedFoundBlocks(cached []FoundBlockView, since int64, limit, offset int) ([]FoundBlockView, int) {
+	matched := cached
+	if since > 0 {
+		matched = make([]FoundBlockView, 0, len(cached))
+		for _, b := range cached {
+			if b.Timestamp.Unix() >= since {
+				matched = append(matched, b)
+			}
+		}
+	}
+	total := len(matched)
+	if offset >= total {
+		return []FoundBlockView{}, total
+	}
+	end := min(offset+limit, total)
+	return append([]FoundBlockView(nil), matched[offset:end]...), total
+}
+
+// annotateFoundBlocks fills confirmations and result for page entries. Blocks
+// already in the status snapshot reuse its node-checked values; older ones
+// are inferred from the current tip height.
+func annotateFoundBlocks(blocks, known []FoundBlockView, nodeBlocks int64) {
+	const winningConfirmations = 6
+	byHash := make(map[string]FoundBlockView, len(known))
+	for _, b := range known {
+		if h := strings.TrimSpace(b.Hash); h != "" {
+			byHash[h] = b
+		}
+	}
+	for i := range blocks {
+		if k, ok := byHash[strings.TrimSpace(blocks[i].Hash)]; ok && k.Result != "" {
+			blocks[i].Height = k.Height
+			blocks[i].Confirmations = k.Confirmations
+			blocks[i].Result = k.Result
+			continue
+		}
+		if blocks[i].Result != "" || nodeBlocks <= 0 || blocks[i].Height <= 0 {
+			continue
+		}
+		confirms := max(nodeBlocks-blocks[i].Height+1, 0)
+		blocks[i].Confirmations = confirms
+		if confirms >= winningConfirmations {
+			blocks[i].Result = "winning"
+		} else {
+			blocks[i].Result = "possible"
+		}
+	}
+}
+
+// parseBlocksPageQuery validates the limit/offset/since query values of
+// /api/blocks; missing values take their defaults.
+func parseBlocksPageQuery(get func(string) string) (limit, offset int, since int64, problem string) {
+	limit = defaultBlocksPageLimit
+	if v := strings.TrimSpace(get("limit")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxBlocksPageLimit {
+			return 0, 0, 0, "invalid limit"
+		}
+		limit = n
+	}
+	if v := strings.TrimSpace(get("offset")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return 0, 0, 0, "invalid offset"
+		}
+		offset = n
+	}
+	if v := strings.TrimSpace(get("since")); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 || n > time.Now().Add(24*time.Hour).Unix() {
+			return 0, 0, 0, "invalid since"
+		}
+		since = n
+	}
+	return limit, offset, since, ""
+}
@@ -51,7 +51,10 @@ func (s *StatusServer) baseTemplateData(start time.Time) StatusData {
 	if !s.Config().DisableConnectRateLimits && s.Config().MaxAcceptsPerSecond == 0 && s.Config().MaxConns == 0 {
 		warnings = append(warnings, "No connection rate limit and no max connection cap are configured. This can make the pool vulnerable to connection floods or accidental overload.")
 	}
-	if s != nil && !s.start.IsZero() && time.Since(s.start) >= stratumStartupGrace {
+	maintenanceBanner := ""
+	if active, _ := s.maintenanceActive(time.Now()); active {
+		maintenanceBanner = statusMaintenanceMessage(s.Config())
+	} else if s != nil && !s.start.IsZero() && time.Since(s.start) >= stratumStartupGrace {
 		if h := stratumHealthStatus(s.jobMgr, time.Now()); !h.Healthy {
 			msg := "Node updates degraded: " + h.Reason
 			if strings.TrimSpace(h.Detail) != "" {
@@ -152,6 +155,7 @@ func (s *StatusServer) baseTemplateData(start time.Time) StatusData {
 		ShareNTimeMaxForwardSeconds:     s.Config().ShareNTimeMaxForwardSeconds,
 		RenderDuration:                  time.Since(start),
 		Warnings:                        warnings,
+		MaintenanceBanner:               maintenanceBanner,
 		NodePeerCleanupEnabled:          s.Config().PeerCleanupEnabled,
 		NodePeerCleanupMaxPingMs:        s.Config().PeerCleanupMaxPingMs,
 		NodePeerCleanupMinPeers:         s.Config().PeerCleanupMinPeers,
@@ -21,6 +21,11 @@ func (s *StatusServer) buildStatusData() StatusData {
 	var rpcSubmitLast, rpcSubmitMax float64
 	var rpcSubmitCount uint64
 	var rpcErrors, shareErrors uint64
+	var templateDecodeErrors uint64
+	var nearMisses uint64
+	var transportUpgrades, transportDowngrades uint64
+	var submitPanics uint64
+	var lastNearMiss *NearMissShare
 	var rpcGBTMin1h, rpcGBTAvg1h, rpcGBTMax1h float64
 	var errorHistory []PoolErrorEvent
 	now := time.Now()
@@ -32,6 +37,15 @@ func (s *StatusServer) buildStatusData() StatusData {
 			rpcSubmitLast, rpcSubmitMax, rpcSubmitCount,
 			rpcErrors, shareErrors = s.metrics.SnapshotDiagnostics()
 		rpcGBTMin1h, rpcGBTAvg1h, rpcGBTMax1h = s.metrics.SnapshotGBTRollingStats(now)
+		templateDecodeErrors = s.metrics.TemplateDecodeErrors()
+		transportUpgrades, transportDowngrades = s.metrics.SnapshotTransportChanges()
+		submitPanics = s.metrics.SubmitPanics()
+		var last NearMissShare
+		nearMisses, last = s.metrics.SnapshotNearMisses()
+		if !last.Timestamp.IsZero() {
+			last.Worker = shortWorkerName(last.Worker, workerNamePrefix, workerNameSuffix)
+			lastNearMiss = &last
+		}
 		rawErrors := s.metrics.SnapshotErrorHistory()
 		if filtered := filterRecentPoolErrorEvents(rawErrors, now, poolErrorHistoryDisplayWindow); len(filtered) > 0 {
 			errorHistory = filtered
@@ -532,6 +546,7 @@ func (s *StatusServer) buildStatusData() StatusData {
 		FiatCurrency:                   s.Config().FiatCurrency,
 		BTCPriceFiat:                   btcPrice,
 		BTCPriceUpdatedAt:              btcPriceUpdated,
+		BTCPriceOffline:                s.priceSvc.Offline(),
 		PoolDonationAddress:            s.Config().PoolDonationAddress,
 		DiscordURL:                     s.Config().DiscordURL,
 		GitHubURL:                      s.Config().GitHubURL,
@@ -606,6 +621,11 @@ func (s *StatusServer) buildStatusData() StatusData {
 		PoolHashrate:                   poolHashrate,
 		BlocksAccepted:                 blocksAccepted,
 		BlocksErrored:                  blocksErrored,
+		NearMisses:                     nearMisses,
+		LastNearMiss:                   lastNearMiss,
+		TransportUpgrades:              transportUpgrades,
+		TransportDowngrades:            transportDowngrades,
+		SubmitPanics:                   submitPanics,
 		RPCGBTLastSec:                  rpcGBTLast,
 		RPCGBTMaxSec:                   rpcGBTMax,
 		RPCGBTCount:                    rpcGBTCount,
@@ -614,6 +634,7 @@ func (s *StatusServer) buildStatusData() StatusData {
 		RPCSubmitCount:                 rpcSubmitCount,
 		RPCErrors:                      rpcErrors,
 		ShareErrors:                    shareErrors,
+		TemplateDecodeErrors:           templateDecodeErrors,
 		RPCGBTMin1hSec:                 rpcGBTMin1h,
 		RPCGBTAvg1hSec:                 rpcGBTAvg1h,
 		RPCGBTMax1hSec:                 rpcGBTMax1h,
@@ -7,6 +7,14 @@ type SavedWorkerEntry struct {
 	Hash           string  `json:"hash"`
 	NotifyEnabled  bool    `json:"notify_enabled,omitempty"`
 	BestDifficulty float64 `json:"best_difficulty,omitempty"`
+	// StaticDifficulty is the user's pinned difficulty (0 = vardiff); see
+	// saved_worker_static_difficulty.go.
+	StaticDifficulty float64 `json:"static_difficulty,omitempty"`
+	// OwnerToken is what the rig puts in its password (owner=<token>) to
+	// prove this user owns it; only a verified owner's static difficulty is
+	// used.
+	OwnerToken    string `json:"owner_token,omitempty"`
+	OwnerVerified bool   `json:"owner_verified,omitempty"`
 }
 
 // SavedWorkerRecord pairs a Clerk user ID with a saved worker entry.
@@ -36,6 +44,7 @@ type StatusData struct {
 	FiatCurrency                    string                `json:"fiat_currency,omitempty"`
 	BTCPriceFiat                    float64               `json:"btc_price_fiat,omitempty"`
 	BTCPriceUpdatedAt               string                `json:"btc_price_updated_at,omitempty"`
+	BTCPriceOffline                 bool                  `json:"btc_price_offline,omitempty"`
 	PoolDonationAddress             string                `json:"pool_donation_address,omitempty"`
 	DiscordURL                      string                `json:"discord_url,omitempty"`
 	DiscordNotificationsEnabled     bool                  `json:"discord_notifications_enabled,omitempty"`
@@ -116,6 +125,11 @@ type StatusData struct {
 	PoolHashrate                    float64               `json:"pool_hashrate,omitempty"`
 	BlocksAccepted                  uint64                `json:"blocks_accepted"`
 	BlocksErrored                   uint64                `json:"blocks_errored"`
+	NearMisses                      uint64                `json:"near_misses"`
+	LastNearMiss                    *NearMissShare        `json:"last_near_miss,omitempty"`
+	TransportUpgrades               uint64                `json:"transport_upgrades"`
+	TransportDowngrades             uint64                `json:"transport_downgrades"`
+	SubmitPanics                    uint64                `json:"submit_panics,omitempty"`
 	RPCGBTLastSec                   float64               `json:"rpc_gbt_last_sec"`
 	RPCGBTMaxSec                    float64               `json:"rpc_gbt_max_sec"`
 	RPCGBTCount                     uint64                `json:"rpc_gbt_count"`
@@ -124,6 +138,7 @@ type StatusData struct {
 	RPCSubmitCount                  uint64                `json:"rpc_submit_count"`
 	RPCErrors                       uint64                `json:"rpc_errors"`
 	ShareErrors                     uint64                `json:"share_errors"`
+	TemplateDecodeErrors            uint64                `json:"template_decode_errors"`
 	RPCGBTMin1hSec                  float64               `json:"rpc_gbt_min_1h_sec"`
 	RPCGBTAvg1hSec                  float64               `json:"rpc_gbt_avg_1h_sec"`
 	RPCGBTMax1hSec                  float64               `json:"rpc_gbt_max_1h_sec"`
@@ -156,6 +171,7 @@ type StatusData struct {
 	HashrateRecentCumulativeEnabled bool     `json:"hashrate_recent_cumulative_enabled"`
 	ShareNTimeMaxForwardSeconds     int      `json:"share_ntime_max_forward_seconds"`
 	Warnings                        []string `json:"warnings,omitempty"`
+	MaintenanceBanner               string   `json:"maintenance_banner,omitempty"`
 }
 
 type ServerPageJobFeed struct {
@@ -184,12 +200,14 @@ type OverviewPageData struct {
 	PoolTag         string           `json:"pool_tag,omitempty"`
 	BTCPriceFiat    float64          `json:"btc_price_fiat,omitempty"`
 	BTCPriceUpdated string           `json:"btc_price_updated_at,omitempty"`
+	BTCPriceOffline bool             `json:"btc_price_offline,omitempty"`
 	FiatCurrency    string           `json:"fiat_currency,omitempty"`
 	RenderDuration  time.Duration    `json:"render_duration"`
 	Workers         []RecentWorkView `json:"workers"`
 	BannedWorkers   []WorkerView     `json:"banned_workers"`
 	BestShares      []BestShare      `json:"best_shares"`
 	MinerTypes      []MinerTypeView  `json:"miner_types,omitempty"`
+	Operator        map[string]any   `json:"operator,omitempty"`
 }
 
 type PoolErrorEvent struct {
@@ -208,8 +226,15 @@ type PoolDisconnectEvent struct {
 // PoolPageData contains data for the pool info page
 type PoolPageData struct {
 	APIVersion                      string                `json:"api_version"`
+	CoinbaseTag                     string                `json:"coinbase_tag,omitempty"`
+	Operator                        map[string]any        `json:"operator,omitempty"`
 	BlocksAccepted                  uint64                `json:"blocks_accepted"`
 	BlocksErrored                   uint64                `json:"blocks_errored"`
+	NearMisses                      uint64                `json:"near_misses"`
+	LastNearMiss                    *NearMissShare        `json:"last_near_miss,omitempty"`
+	TransportUpgrades               uint64                `json:"transport_upgrades"`
+	TransportDowngrades             uint64                `json:"transport_downgrades"`
+	SubmitPanics                    uint64                `json:"submit_panics,omitempty"`
 	RPCGBTLastSec                   float64               `json:"rpc_gbt_last_sec"`
 	RPCGBTMaxSec                    float64               `json:"rpc_gbt_max_sec"`
 	RPCGBTCount                     uint64                `json:"rpc_gbt_count"`
@@ -218,6 +243,7 @@ type PoolPageData struct {
 	RPCSubmitCount                  uint64                `json:"rpc_submit_count"`
 	RPCErrors                       uint64                `json:"rpc_errors"`
 	ShareErrors                     uint64                `json:"share_errors"`
+	TemplateDecodeErrors            uint64                `json:"template_decode_errors"`
 	RPCGBTMin1hSec                  float64               `json:"rpc_gbt_min_1h_sec"`
 	RPCGBTAvg1hSec                  float64               `json:"rpc_gbt_avg_1h_sec"`
 	RPCGBTMax1hSec                  float64               `json:"rpc_gbt_max_1h_sec"`
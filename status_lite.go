@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// statusLiteRefreshSeconds is the meta-refresh interval for /status-lite.
+// It matches the overview refresh so embedded displays never outrun the
+// status cache.
+var statusLiteRefreshSeconds = int(overviewRefreshInterval / time.Second)
+
+type statusLiteLine struct {
+	label string
+	value string
+}
+
+// statusLiteLines returns the small set of facts shown by the lite endpoints:
+// hashrate, connections, last found block and node height.
+func statusLiteLines(view StatusData, now time.Time) []statusLiteLine {
+	lastBlock := "none"
+	if len(view.FoundBlocks) > 0 {
+		fb := view.FoundBlocks[0]
+		lastBlock = fmt.Sprintf("%d", fb.Height)
+		if !fb.Timestamp.IsZero() {
+			lastBlock += " (" + formatLiteAge(now.Sub(fb.Timestamp)) + " ago)"
+		}
+	}
+	nodeHeight := "unknown"
+	if view.NodeBlocks > 0 {
+		nodeHeight = fmt.Sprintf("%d", view.NodeBlocks)
+		if view.NodeInitialBlockDownload {
+			nodeHeight += " (syncing)"
+		}
+	}
+	return []statusLiteLine{
+		{label: "hashrate", value: formatHashrateValue(view.PoolHashrate)},
+		{label: "connections", value: fmt.Sprintf("%d", view.ActiveMiners)},
+		{label: "shares_per_min", value: fmt.Sprintf("%.1f", view.SharesPerMinute)},
+		{label: "blocks_found", value: fmt.Sprintf("%d", view.BlocksAccepted)},
+		{label: "last_block", value: lastBlock},
+		{label: "node_height", value: nodeHeight},
+		{label: "updated", value: now.UTC().Format(time.RFC3339)},
+	}
+}
+
+func formatLiteAge(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	default:
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	}
+}
+
+// handleStatusText serves /status.txt: a plain-text key/value summary with no
+// assets, suitable for tiny LCDs and scripts over slow links.
+func (s *StatusServer) handleStatusText(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	view := s.statusDataView()
+	var b strings.Builder
+	if name := strings.TrimSpace(view.BrandName); name != "" {
+		b.WriteString(name)
+		b.WriteByte('\n')
+	}
+	for _, line := range statusLiteLines(view, time.Now()) {
+		b.WriteString(line.label)
+		b.WriteString(": ")
+		b.WriteString(line.value)
+		b.WriteByte('\n')
+	}
+	setShortTextCacheHeaders(w, false)
+	if r.Method == http.MethodHead {
+		return
+	}
+	if _, err := w.Write([]byte(b.String())); err != nil {
+		logResponseWriteDebug("status text write failed", err, "component", "http", "kind", "write")
+	}
+}
+
+// handleStatusLitePage serves /status-lite: the same facts as /status.txt as a
+// fully server-rendered HTML page with inline styles and no scripts.
+func (s *StatusServer) handleStatusLitePage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	view := s.statusDataView()
+	title := strings.TrimSpace(view.BrandName)
+	if title == "" {
+		title = poolSoftwareName
+	}
+	var b strings.Builder
+	b.WriteString("<!doctype html><html><head><meta charset=\"utf-8\">")
+	b.WriteString("<meta name=\"viewport\" content=\"width=device-width,initial-scale=1\">")
+	fmt.Fprintf(&b, "<meta http-equiv=\"refresh\" content=\"%d\">", statusLiteRefreshSeconds)
+	fmt.Fprintf(&b, "<title>%s</title>", html.EscapeString(title))
+	b.WriteString("<style>body{font:14px monospace;margin:8px;background:#000;color:#ddd}td{padding:1px 8px 1px 0}</style>")
+	fmt.Fprintf(&b, "</head><body><b>%s</b><table>", html.EscapeString(title))
+	for _, line := range statusLiteLines(view, time.Now()) {
+		fmt.Fprintf(&b, "<tr><td>%s</td><td>%s</td></tr>", html.EscapeString(line.label), html.EscapeString(line.value))
+	}
+	b.WriteString("</table></body></html>\n")
+	setShortHTMLCacheHeaders(w, false)
+	if r.Method == http.MethodHead {
+		return
+	}
+	if _, err := w.Write([]byte(b.String())); err != nil {
+		logResponseWriteDebug("status lite write failed", err, "component", "http", "kind", "write")
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newStatusLiteTestServer(now time.Time) *StatusServer {
+	s := &StatusServer{}
+	s.cachedStatus = StatusData{
+		BrandName:    "Test <Pool>",
+		PoolHashrate: 1.5e12,
+		ActiveMiners: 3,
+		NodeBlocks:   850000,
+		FoundBlocks: []FoundBlockView{
+			{Height: 849990, Timestamp: now.Add(-2 * time.Hour)},
+		},
+	}
+	s.lastStatusBuild = now
+	return s
+}
+
+func TestStatusTextServesPlainSummary(t *testing.T) {
+	s := newStatusLiteTestServer(time.Now())
+
+	rr := httptest.NewRecorder()
+	s.handleStatusText(rr, httptest.NewRequest(http.MethodGet, "/status.txt", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status=%d", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Fatalf("content-type=%q", ct)
+	}
+	body := rr.Body.String()
+	for _, want := range []string{"hashrate: 1.500 TH/s", "connections: 3", "last_block: 849990 (2h ago)", "node_height: 850000"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("body missing %q:\n%s", want, body)
+		}
+	}
+	if !isResponseCacheable(rr.Code, rr.Header(), rr.Body.Len()) {
+		t.Fatalf("expected /status.txt to be cacheable by the short response cache")
+	}
+}
+
+func TestStatusLitePageIsScriptFreeAndEscaped(t *testing.T) {
+	s := newStatusLiteTestServer(time.Now())
+
+	rr := httptest.NewRecorder()
+	s.handleStatusLitePage(rr, httptest.NewRequest(http.MethodGet, "/status-lite", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status=%d", rr.Code)
+	}
+	body := rr.Body.String()
+	if strings.Contains(body, "<script") || strings.Contains(body, "src=") {
+		t.Fatalf("lite page must not reference scripts or assets:\n%s", body)
+	}
+	if !strings.Contains(body, "Test &lt;Pool&gt;") {
+		t.Fatalf("brand name not escaped:\n%s", body)
+	}
+	if !strings.Contains(body, "850000") {
+		t.Fatalf("node height missing:\n%s", body)
+	}
+}
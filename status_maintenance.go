@@ -0,0 +1,85 @@
+package main
+
+import (
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	statusMaintenanceOff    = "off"
+	statusMaintenanceBanner = "banner"
+	statusMaintenancePage   = "page"
+
+	defaultStatusMaintenanceMessage = "The pool is temporarily paused while its node connection recovers. Mining and stats will resume automatically."
+	statusMaintenanceRetryAfter     = "60"
+)
+
+// maintenanceActive reports whether the configured maintenance banner/page
+// should be shown right now: a mode is set, the startup grace has passed and
+// Stratum is unhealthy. It is evaluated per request, so maintenance clears on
+// its own as soon as health recovers.
+func (s *StatusServer) maintenanceActive(now time.Time) (bool, stratumHealth) {
+	if s == nil {
+		return false, stratumHealth{Healthy: true}
+	}
+	mode := s.Config().StatusMaintenanceMode
+	if mode != statusMaintenanceBanner && mode != statusMaintenancePage {
+		return false, stratumHealth{Healthy: true}
+	}
+	if !s.start.IsZero() && now.Sub(s.start) < stratumStartupGrace {
+		return false, stratumHealth{Healthy: true}
+	}
+	h := stratumHealthStatus(s.jobMgr, now)
+	return !h.Healthy, h
+}
+
+func statusMaintenanceMessage(cfg Config) string {
+	if msg := strings.TrimSpace(cfg.StatusMaintenanceMessage); msg != "" {
+		return msg
+	}
+	return defaultStatusMaintenanceMessage
+}
+
+// maintenanceExempt lists paths that keep working in "page" mode: admin pages
+// (so operators can diagnose), JSON APIs, login flows, plain-text status,
+// metrics and static assets the maintenance page itself needs.
+func (s *StatusServer) maintenanceExempt(p string) bool {
+	switch {
+	case p == "/admin" || strings.HasPrefix(p, "/admin/"),
+		strings.HasPrefix(p, "/api/"),
+		p == "/login", p == "/logout", p == "/sign-in",
+		p == "/status.txt", p == "/metrics", p == "/favicon.png":
+		return true
+	}
+	if cb := strings.TrimSpace(s.Config().ClerkCallbackPath); cb != "" && p == cb {
+		return true
+	}
+	return path.Ext(p) != ""
+}
+
+// withMaintenancePage serves a 503 maintenance page instead of the public
+// HTML pages while maintenance_mode is "page" and Stratum is unhealthy. It
+// wraps the response cache so cached pages are not served during maintenance.
+func (s *StatusServer) withMaintenancePage(next http.Handler) http.Handler {
+	if s == nil || next == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.Config().StatusMaintenanceMode != statusMaintenancePage || s.maintenanceExempt(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		active, h := s.maintenanceActive(time.Now())
+		if !active {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Retry-After", statusMaintenanceRetryAfter)
+		s.renderErrorPage(w, r, http.StatusServiceUnavailable,
+			"Pool temporarily paused",
+			statusMaintenanceMessage(s.Config()),
+			"Reason: "+h.Reason+". This page clears automatically when the node recovers.")
+	})
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithMaintenancePage(t *testing.T) {
+	s := &StatusServer{}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	h := s.withMaintenancePage(next)
+	serve := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	// Without a job manager Stratum is unhealthy, but mode "off" and
+	// "banner" leave the pages alone.
+	for _, mode := range []string{statusMaintenanceOff, statusMaintenanceBanner} {
+		s.cfg.Store(Config{StatusMaintenanceMode: mode})
+		if rec := serve("/pool"); rec.Code != http.StatusTeapot {
+			t.Fatalf("mode %q: expected page passthrough, got %d", mode, rec.Code)
+		}
+	}
+
+	s.cfg.Store(Config{StatusMaintenanceMode: statusMaintenancePage})
+	rec := serve("/pool")
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected 503 maintenance page with Retry-After, got %d %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	for _, path := range []string{"/admin", "/admin/logs", "/api/overview", "/style.css", "/status.txt"} {
+		if rec := serve(path); rec.Code != http.StatusTeapot {
+			t.Fatalf("%s should stay reachable during maintenance, got %d", path, rec.Code)
+		}
+	}
+
+	// Healthy again (here: still inside the startup grace) clears it.
+	s.start = time.Now()
+	if rec := serve("/pool"); rec.Code != http.StatusTeapot {
+		t.Fatalf("expected maintenance to clear, got %d", rec.Code)
+	}
+}
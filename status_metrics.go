@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const prometheusTextContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// promWriter emits Prometheus text exposition format. Each metric family is
+// written once with its HELP and TYPE lines.
+type promWriter struct {
+	b strings.Builder
+}
+
+func (p *promWriter) family(name, kind, help string) {
+	fmt.Fprintf(&p.b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func (p *promWriter) sample(name string, value float64, labels ...string) {
+	p.b.WriteString(name)
+	if len(labels) > 0 {
+		p.b.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				p.b.WriteByte(',')
+			}
+			p.b.WriteString(labels[i])
+			p.b.WriteString(`="`)
+			p.b.WriteString(promEscapeLabelValue(labels[i+1]))
+			p.b.WriteByte('"')
+		}
+		p.b.WriteByte('}')
+	}
+	p.b.WriteByte(' ')
+	p.b.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	p.b.WriteByte('\n')
+}
+
+func promEscapeLabelValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+// writePoolMetrics renders the PoolMetrics counters plus the connected miner
+// count under the gopool_ prefix.
+func writePoolMetrics(p *promWriter, m *PoolMetrics, connected int) {
+	accepted, rejected, _ := m.Snapshot()
+	_, _, blocksAccepted, blocksErrored, _, _, _, _, _, _, rpcErrors, _ := m.SnapshotDiagnostics()
+
+	p.family("gopool_shares_accepted_total", "counter", "Shares accepted since start.")
+	p.sample("gopool_shares_accepted_total", float64(accepted))
+	p.family("gopool_shares_rejected_total", "counter", "Shares rejected since start.")
+	p.sample("gopool_shares_rejected_total", float64(rejected))
+
+	p.family("gopool_submit_errors_total", "counter", "Rejected or refused submits by reason.")
+	errs := m.SnapshotSubmitErrors()
+	reasons := make([]string, 0, len(errs))
+	for reason := range errs {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	for _, reason := range reasons {
+		p.sample("gopool_submit_errors_total", float64(errs[reason]), "reason", reason)
+	}
+
+	if listeners := m.SnapshotListenerShares(); len(listeners) > 0 {
+		p.family("gopool_listener_shares_total", "counter", "Shares by Stratum listener and result.")
+		names := make([]string, 0, len(listeners))
+		for name := range listeners {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			p.sample("gopool_listener_shares_total", float64(listeners[name].Accepted), "listener", name, "result", "accepted")
+			p.sample("gopool_listener_shares_total", float64(listeners[name].Rejected), "listener", name, "result", "rejected")
+		}
+	}
+
+	p.family("gopool_blocks_submitted_total", "counter", "Block submissions by result.")
+	p.sample("gopool_blocks_submitted_total", float64(blocksAccepted), "result", "accepted")
+	p.sample("gopool_blocks_submitted_total", float64(blocksErrored), "result", "error")
+
+	p.family("gopool_rpc_errors_total", "counter", "Node RPC errors since start.")
+	p.sample("gopool_rpc_errors_total", float64(rpcErrors))
+
+	p.family("gopool_hashrate_hashes_per_second", "gauge", "Estimated pool hashrate.")
+	p.sample("gopool_hashrate_hashes_per_second", m.PoolHashrate())
+
+	p.family("gopool_connected_miners", "gauge", "Currently connected Stratum miners.")
+	p.sample("gopool_connected_miners", float64(connected))
+}
+
+// handleMetrics serves /metrics in Prometheus text format when
+// [status].metrics_enabled is set.
+func (s *StatusServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	connected := 0
+	if s.registry != nil {
+		connected = s.registry.Count()
+	}
+	var p promWriter
+	writePoolMetrics(&p, s.metrics, connected)
+
+	w.Header().Set("Content-Type", prometheusTextContentType)
+	w.Header().Set("Cache-Control", "no-store")
+	if r.Method == http.MethodHead {
+		return
+	}
+	if _, err := w.Write([]byte(p.b.String())); err != nil {
+		logResponseWriteDebug("metrics write failed", err, "component", "http", "kind", "write")
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsBypassShortResponseCache(t *testing.T) {
+	m := NewPoolMetrics()
+	s := &StatusServer{metrics: m}
+	h := s.serveShortResponseCache(http.HandlerFunc(s.handleMetrics))
+
+	scrape := func() string {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status %d", rec.Code)
+		}
+		if !strings.Contains(rec.Header().Get("Cache-Control"), "no-store") {
+			t.Fatalf("expected Cache-Control no-store, got %q", rec.Header().Get("Cache-Control"))
+		}
+		return rec.Body.String()
+	}
+	scrape()
+	m.RecordSubmitError("low difficulty")
+	if body := scrape(); !strings.Contains(body, `gopool_submit_errors_total{reason="low_difficulty"} 1`) {
+		t.Fatalf("second scrape served stale metrics:\n%s", body)
+	}
+	if len(s.responseCache) != 0 {
+		t.Fatalf("expected /metrics to stay out of the response cache, got %d entries", len(s.responseCache))
+	}
+}
+
+func TestHandleMetricsExposition(t *testing.T) {
+	m := NewPoolMetrics()
+	m.RecordSubmitError("low difficulty")
+	m.RecordSubmitError("low difficulty")
+	m.RecordSubmitError(`bad "job"`)
+	s := &StatusServer{metrics: m}
+
+	rec := httptest.NewRecorder()
+	s.handleMetrics(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != prometheusTextContentType {
+		t.Fatalf("content type %q", ct)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE gopool_shares_accepted_total counter\n",
+		"# TYPE gopool_connected_miners gauge\n",
+		`gopool_submit_errors_total{reason="low_difficulty"} 2` + "\n",
+		`gopool_blocks_submitted_total{result="accepted"} 0` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("missing %q in:\n%s", want, body)
+		}
+	}
+	if strings.Count(body, "# HELP gopool_submit_errors_total") != 1 {
+		t.Fatalf("expected a single HELP line per family")
+	}
+
+	rec = httptest.NewRecorder()
+	s.handleMetrics(rec, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("POST status %d", rec.Code)
+	}
+}
+
+func TestSubmitErrorLabelCap(t *testing.T) {
+	m := NewPoolMetrics()
+	for i := 0; i < maxSubmitErrorLabels+10; i++ {
+		m.RecordSubmitError(fmt.Sprintf("reason-%d", i))
+	}
+	errs := m.SnapshotSubmitErrors()
+	if len(errs) > maxSubmitErrorLabels+1 {
+		t.Fatalf("expected at most %d labels, got %d", maxSubmitErrorLabels+1, len(errs))
+	}
+	if errs["other"] != 10 {
+		t.Fatalf("expected overflow folded into other, got %d", errs["other"])
+	}
+}
+
+func TestPromEscapeLabelValue(t *testing.T) {
+	if got := promEscapeLabelValue("a\\b\"c\nd"); got != `a\\b\"c\nd` {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestListenerShareMetricsAndWorkerMerge(t *testing.T) {
+	m := NewPoolMetrics()
+	m.RecordListenerShare("tls", true)
+	m.RecordListenerShare("tls", true)
+	m.RecordListenerShare("tcp", false)
+	m.RecordListenerShare("", true)
+	s := &StatusServer{metrics: m}
+
+	rec := httptest.NewRecorder()
+	s.handleMetrics(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		`gopool_listener_shares_total{listener="tcp",result="rejected"} 1` + "\n",
+		`gopool_listener_shares_total{listener="tls",result="accepted"} 2` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("missing %q in:\n%s", want, body)
+		}
+	}
+	if len(m.SnapshotListenerShares()) != 2 {
+		t.Fatalf("expected unlabeled shares to be ignored: %+v", m.SnapshotListenerShares())
+	}
+
+	views := mergeWorkerViewsByHash([]WorkerView{
+		{WorkerSHA256: "w1", ConnectionID: "1", Accepted: 3, Listeners: []string{"tls"}},
+		{WorkerSHA256: "w1", ConnectionID: "2", Accepted: 4, Listeners: []string{"tcp"}},
+		{WorkerSHA256: "w1", ConnectionID: "3", Accepted: 1, Listeners: []string{"tls"}},
+	})
+	if len(views) != 1 || views[0].Accepted != 8 {
+		t.Fatalf("merged views = %+v", views)
+	}
+	if got := fmt.Sprint(views[0].Listeners); got != "[tcp tls]" {
+		t.Fatalf("merged listeners = %s, want [tcp tls]", got)
+	}
+}
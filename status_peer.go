@@ -46,7 +46,7 @@ type peerLookupEntry struct {
 }
 
 func (s *StatusServer) lookupPeerName(host string) string {
-	if host == "" {
+	if host == "" || s.Config().OfflineMode {
 		return ""
 	}
 	now := time.Now()
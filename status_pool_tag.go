@@ -30,3 +30,29 @@ func displayPoolTagFromCoinbaseMessage(msg string) string {
 	}
 	return "/" + msg + "/"
 }
+
+// maxPoolCoinbaseTagBytes caps the derived coinbase tag so it stays well
+// within standard coinbase scriptSig bounds.
+const maxPoolCoinbaseTagBytes = 40
+
+// poolCoinbaseTag derives the coinbase tag ("/goPool/" or
+// "/<prefix>-goPool/") from pooltag_prefix. Startup and config reloads both
+// use it so a reload never changes the tag format. Only printable ASCII is
+// kept and the result is truncated to maxPoolCoinbaseTagBytes.
+func poolCoinbaseTag(prefix string) string {
+	brand := poolSoftwareName
+	if prefix = strings.TrimSpace(prefix); prefix != "" {
+		brand = prefix + "-" + brand
+	}
+	tag := "/" + brand + "/"
+	buf := make([]byte, 0, len(tag))
+	for i := 0; i < len(tag); i++ {
+		if b := tag[i]; b >= 0x20 && b <= 0x7e {
+			buf = append(buf, b)
+		}
+	}
+	if len(buf) > maxPoolCoinbaseTagBytes {
+		buf = buf[:maxPoolCoinbaseTagBytes]
+	}
+	return string(buf)
+}
@@ -1,6 +1,9 @@
 package main
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestDisplayPoolTagFromCoinbaseMessage(t *testing.T) {
 	tests := []struct {
@@ -23,3 +26,38 @@ func TestDisplayPoolTagFromCoinbaseMessage(t *testing.T) {
 		}
 	}
 }
+
+func TestPoolCoinbaseTag(t *testing.T) {
+	tests := []struct {
+		prefix string
+		want   string
+	}{
+		{"", "/" + poolSoftwareName + "/"},
+		{"  ", "/" + poolSoftwareName + "/"},
+		{"acme", "/acme-" + poolSoftwareName + "/"},
+		{"ac\x01me\xff", "/acme-" + poolSoftwareName + "/"},
+	}
+	for _, tc := range tests {
+		if got := poolCoinbaseTag(tc.prefix); got != tc.want {
+			t.Fatalf("poolCoinbaseTag(%q) = %q, want %q", tc.prefix, got, tc.want)
+		}
+	}
+
+	long := poolCoinbaseTag(strings.Repeat("x", 64))
+	if len(long) != maxPoolCoinbaseTagBytes {
+		t.Fatalf("long tag length = %d, want %d", len(long), maxPoolCoinbaseTagBytes)
+	}
+
+	// The derived tag and the Discord fallback used when CoinbaseMsg is
+	// empty must produce the same notice prefix.
+	prefixFor := func(cfg Config) string {
+		s := &StatusServer{}
+		s.cfg.Store(cfg)
+		return (&discordNotifier{s: s}).noticePrefix()
+	}
+	derived := prefixFor(Config{PoolTagPrefix: "acme", CoinbaseMsg: poolCoinbaseTag("acme")})
+	fallback := prefixFor(Config{PoolTagPrefix: "acme"})
+	if derived != fallback || derived != "[acme-"+poolSoftwareName+"] " {
+		t.Fatalf("notice prefixes disagree: derived %q, fallback %q", derived, fallback)
+	}
+}
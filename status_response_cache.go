@@ -97,6 +97,12 @@ func isResponseCacheable(status int, header http.Header, bodyLen int) bool {
 	if len(header.Values("Set-Cookie")) > 0 {
 		return false
 	}
+	// Handlers that opt out of caching (e.g. /metrics, which Prometheus
+	// expects to be live on every scrape) are never stored.
+	cacheControl := strings.ToLower(strings.Join(header.Values("Cache-Control"), ","))
+	if strings.Contains(cacheControl, "no-store") || strings.Contains(cacheControl, "no-cache") {
+		return false
+	}
 	contentType := strings.ToLower(strings.TrimSpace(header.Get("Content-Type")))
 	if strings.HasPrefix(contentType, "text/html") {
 		return true
@@ -104,6 +110,9 @@ func isResponseCacheable(status int, header http.Header, bodyLen int) bool {
 	if strings.HasPrefix(contentType, "application/json") {
 		return true
 	}
+	if strings.HasPrefix(contentType, "text/plain") {
+		return true
+	}
 	return false
 }
 
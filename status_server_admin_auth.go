@@ -10,27 +10,76 @@ import (
 	"time"
 )
 
+// adminSession is one logged-in admin browser session. With signed sessions
+// (session_secret_rotation_hours > 0) the map is only a cache of verified
+// tokens: keyGeneration names the signing key and revoked keeps a logged-out
+// token from being accepted again by its signature.
+type adminSession struct {
+	expiry        time.Time
+	username      string
+	keyGeneration int64
+	revoked       bool
+}
+
 func (s *StatusServer) isAdminAuthenticated(r *http.Request) bool {
+	_, ok := s.adminSessionUser(r)
+	return ok
+}
+
+// adminSessionUser returns the account behind the request's admin session.
+// Sessions whose account has since been removed from admin.toml are
+// invalidated here.
+func (s *StatusServer) adminSessionUser(r *http.Request) (string, bool) {
 	token, ok := s.adminSessionToken(r)
 	if !ok {
 		s.pruneExpiredAdminSessions()
-		return false
+		return "", false
+	}
+	adminCfg, err := s.adminConfig()
+	if err != nil {
+		return "", false
+	}
+	now := time.Now()
+	var key adminSessionKey
+	if rotation := adminCfg.sessionSecretRotation(); rotation > 0 {
+		if key, err = s.currentAdminSessionKey(now, rotation); err != nil {
+			logger.Warn("admin session key unavailable", "component", "admin", "kind", "auth", "error", err)
+			return "", false
+		}
 	}
 	s.adminSessionsMu.Lock()
-	expiry, exists := s.adminSessions[token]
-	if !exists {
+	sess, exists := s.adminSessions[token]
+	if exists && sess.keyGeneration != key.generation() {
+		// Signed under a rotated key, or issued before signing was switched
+		// on or off: the admin has to log in again.
+		delete(s.adminSessions, token)
+		exists = false
+	}
+	if !exists && len(key.secret) > 0 {
+		if username, expiry, ok := verifyAdminSessionToken(key, token, now); ok && !adminSessionTokenRevoked(token) {
+			sess = adminSession{expiry: expiry, username: username, keyGeneration: key.generation()}
+			s.adminSessions[token] = sess
+			exists = true
+		}
+	}
+	if !exists || sess.revoked {
 		s.adminSessionsMu.Unlock()
 		s.pruneExpiredAdminSessions()
-		return false
+		return "", false
 	}
-	if time.Now().After(expiry) {
+	if now.After(sess.expiry) {
 		delete(s.adminSessions, token)
 		s.adminSessionsMu.Unlock()
 		s.pruneExpiredAdminSessions()
-		return false
+		return "", false
 	}
 	s.adminSessionsMu.Unlock()
-	return true
+	if !adminCfg.hasAccount(sess.username) {
+		s.invalidateAdminUserSessions(sess.username)
+		logger.Info("admin session ended; account removed", "component", "admin", "kind", "auth", "username", sess.username)
+		return "", false
+	}
+	return sess.username, true
 }
 
 func (s *StatusServer) adminSessionToken(r *http.Request) (string, bool) {
@@ -47,17 +96,33 @@ func (s *StatusServer) adminSessionToken(r *http.Request) (string, bool) {
 	return cookie.Value, true
 }
 
-func (s *StatusServer) createAdminSession(duration time.Duration) (string, time.Time, error) {
+// createAdminSession starts a session for username. A positive rotation
+// issues a token signed with the persisted session key so it survives
+// restarts; otherwise the token is random and lives only in memory.
+func (s *StatusServer) createAdminSession(username string, duration, rotation time.Duration) (string, time.Time, error) {
 	if duration <= 0 {
 		duration = time.Duration(defaultAdminSessionExpirationSeconds) * time.Second
 	}
-	token, err := generateAdminToken()
+	now := time.Now()
+	expiry := now.Add(duration)
+	var (
+		token string
+		key   adminSessionKey
+		err   error
+	)
+	if rotation > 0 {
+		if key, err = s.currentAdminSessionKey(now, rotation); err != nil {
+			return "", time.Time{}, err
+		}
+		token, err = signAdminSessionToken(key, username, expiry)
+	} else {
+		token, err = generateAdminToken()
+	}
 	if err != nil {
 		return "", time.Time{}, err
 	}
-	expiry := time.Now().Add(duration)
 	s.adminSessionsMu.Lock()
-	s.adminSessions[token] = expiry
+	s.adminSessions[token] = adminSession{expiry: expiry, username: username, keyGeneration: key.generation()}
 	s.adminSessionsMu.Unlock()
 	return token, expiry, nil
 }
@@ -68,8 +133,8 @@ func (s *StatusServer) pruneExpiredAdminSessions() {
 	}
 	now := time.Now()
 	s.adminSessionsMu.Lock()
-	for token, expiry := range s.adminSessions {
-		if now.After(expiry) {
+	for token, sess := range s.adminSessions {
+		if now.After(sess.expiry) {
 			delete(s.adminSessions, token)
 		}
 	}
@@ -83,7 +148,13 @@ func (s *StatusServer) activeAdminSessionCount() int {
 	s.pruneExpiredAdminSessions()
 	s.adminSessionsMu.Lock()
 	defer s.adminSessionsMu.Unlock()
-	return len(s.adminSessions)
+	count := 0
+	for _, sess := range s.adminSessions {
+		if !sess.revoked {
+			count++
+		}
+	}
+	return count
 }
 
 func generateAdminToken() (string, error) {
@@ -95,18 +166,48 @@ func generateAdminToken() (string, error) {
 }
 
 func (s *StatusServer) adminCredentialsMatch(cfg adminFileConfig, username, password string) bool {
+	username = strings.TrimSpace(username)
+	if _, ok := cfg.user(username); ok {
+		return s.adminUserPasswordMatches(cfg, username, password)
+	}
 	if cfg.Username == "" && cfg.Password == "" {
 		return false
 	}
-	if !compareStringsConstantTime(cfg.Username, strings.TrimSpace(username)) {
+	if !compareStringsConstantTime(cfg.Username, username) {
 		return false
 	}
 	return s.adminPasswordMatches(cfg, password)
 }
 
+// adminUserPasswordMatches checks password for username, which may be the
+// primary account or a [[users]] account.
+func (s *StatusServer) adminUserPasswordMatches(cfg adminFileConfig, username, password string) bool {
+	if u, ok := cfg.user(username); ok {
+		return verifyAdminPasswordKDF(u.PasswordHash, password)
+	}
+	if username != cfg.Username {
+		return false
+	}
+	return s.adminPasswordMatches(cfg, password)
+}
+
+// adminReauthMatches checks a password re-entered to confirm an action
+// against the account that owns the request's session.
+func (s *StatusServer) adminReauthMatches(r *http.Request, cfg adminFileConfig, password string) bool {
+	username, ok := s.adminSessionUser(r)
+	if !ok {
+		return false
+	}
+	return s.adminUserPasswordMatches(cfg, username, password)
+}
+
+// adminPasswordMatches checks password for the primary account: against
+// password_hash when set, else a legacy password_sha256, else the plaintext.
 func (s *StatusServer) adminPasswordMatches(cfg adminFileConfig, password string) bool {
-	hash := strings.TrimSpace(cfg.PasswordSHA256)
-	if hash != "" {
+	if cfg.PasswordHash != "" {
+		return verifyAdminPasswordKDF(cfg.PasswordHash, password)
+	}
+	if hash := strings.TrimSpace(cfg.PasswordSHA256); hash != "" {
 		return compareStringsConstantTime(hash, adminPasswordHash(password))
 	}
 	return compareStringsConstantTime(cfg.Password, password)
@@ -124,20 +225,52 @@ func (s *StatusServer) invalidateAdminSession(token string) {
 		return
 	}
 	s.adminSessionsMu.Lock()
-	delete(s.adminSessions, token)
+	sess, ok := s.adminSessions[token]
+	signed := ok && sess.keyGeneration != 0
+	if signed {
+		// Keep the entry until expiry so the token's valid signature
+		// cannot bring the session back.
+		sess.revoked = true
+		s.adminSessions[token] = sess
+	} else {
+		delete(s.adminSessions, token)
+	}
+	s.adminSessionsMu.Unlock()
+	if signed {
+		revokeAdminSessionToken(token)
+	}
+}
+
+// invalidateAdminUserSessions ends every session belonging to username.
+func (s *StatusServer) invalidateAdminUserSessions(username string) {
+	s.adminSessionsMu.Lock()
+	for token, sess := range s.adminSessions {
+		if sess.username == username {
+			delete(s.adminSessions, token)
+		}
+	}
 	s.adminSessionsMu.Unlock()
 }
 
-func (s *StatusServer) scrubAdminPasswordPlaintext(cfg adminFileConfig) error {
+// scrubAdminPasswordPlaintext runs after a successful primary login with
+// password. It stores a PBKDF2 password_hash in place of any plaintext
+// password or legacy password_sha256 still in admin.toml.
+func (s *StatusServer) scrubAdminPasswordPlaintext(cfg adminFileConfig, password string) error {
 	if s == nil {
 		return fmt.Errorf("status server is nil")
 	}
-	if cfg.Password == "" {
+	if cfg.Password == "" && cfg.PasswordSHA256 == "" && cfg.PasswordHash != "" {
 		return nil
 	}
-	if cfg.PasswordSHA256 == "" {
-		cfg.PasswordSHA256 = adminPasswordHash(cfg.Password)
+	if cfg.PasswordHash == "" {
+		hash, err := adminPasswordKDF(password, adminPasswordKDFIterations)
+		if err != nil {
+			return err
+		}
+		cfg.PasswordHash = hash
 	}
 	cfg.Password = ""
-	return atomicWriteFile(s.adminConfigPath, []byte(renderAdminConfig(cfg)))
+	cfg.PasswordSHA256 = ""
+	defer s.invalidateAdminConfig()
+	return atomicWriteFileMode(s.adminConfigPath, []byte(renderAdminConfig(cfg)), 0o600)
 }
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// adminConfigCache holds the parsed admin.toml so that authenticating each
+// admin request does not re-read and re-parse the file. It is reloaded when
+// the file's modification time or size changes, so hand edits still apply
+// without a restart.
+type adminConfigCache struct {
+	mu      sync.Mutex
+	loaded  bool
+	modTime time.Time
+	size    int64
+	cfg     adminFileConfig
+}
+
+// adminConfig returns the current admin.toml contents, parsing the file
+// only when it changed since the last call.
+func (s *StatusServer) adminConfig() (adminFileConfig, error) {
+	info, err := os.Stat(s.adminConfigPath)
+	if err != nil {
+		s.invalidateAdminConfig()
+		return loadAdminConfigFile(s.adminConfigPath)
+	}
+	c := &s.adminCfgCache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.loaded || c.size != info.Size() || !c.modTime.Equal(info.ModTime()) {
+		cfg, err := loadAdminConfigFile(s.adminConfigPath)
+		if err != nil {
+			c.loaded = false
+			return cfg, err
+		}
+		c.cfg = cfg
+		c.modTime = info.ModTime()
+		c.size = info.Size()
+		c.loaded = true
+	}
+	cfg := c.cfg
+	cfg.Users = slices.Clone(c.cfg.Users)
+	return cfg, nil
+}
+
+// invalidateAdminConfig forces the next adminConfig call to re-read the
+// file. Writers call it so a rewrite landing within the filesystem's mtime
+// granularity is not missed.
+func (s *StatusServer) invalidateAdminConfig() {
+	c := &s.adminCfgCache
+	c.mu.Lock()
+	c.loaded = false
+	c.mu.Unlock()
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAdminConfigReloadsOnlyWhenFileChanges(t *testing.T) {
+	adminPath := filepath.Join(t.TempDir(), "admin.toml")
+	write := func(username string, mtime time.Time) {
+		t.Helper()
+		cfg := adminFileConfig{Enabled: true, Username: username, PasswordSHA256: adminPasswordHash("primary-password-123")}
+		if err := os.WriteFile(adminPath, []byte(renderAdminConfig(cfg)), 0o600); err != nil {
+			t.Fatalf("write admin config: %v", err)
+		}
+		if err := os.Chtimes(adminPath, mtime, mtime); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	write("alice", base)
+
+	s := &StatusServer{adminConfigPath: adminPath}
+	cfg, err := s.adminConfig()
+	if err != nil || cfg.Username != "alice" {
+		t.Fatalf("first load = %q, %v; want alice", cfg.Username, err)
+	}
+
+	// Same size and mtime: the cached parse is served.
+	write("bobby", base)
+	if cfg, _ := s.adminConfig(); cfg.Username != "alice" {
+		t.Fatalf("unchanged stamp reparsed the file: got %q", cfg.Username)
+	}
+
+	write("bobby", base.Add(time.Second))
+	if cfg, _ := s.adminConfig(); cfg.Username != "bobby" {
+		t.Fatalf("mtime change not picked up: got %q", cfg.Username)
+	}
+
+	write("carol", base.Add(time.Second))
+	s.invalidateAdminConfig()
+	if cfg, _ := s.adminConfig(); cfg.Username != "carol" {
+		t.Fatalf("invalidate did not force a reload: got %q", cfg.Username)
+	}
+}
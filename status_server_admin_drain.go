@@ -0,0 +1,101 @@
+package main
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+// handleAdminDrain starts a gradual drain of connected miners for a rolling
+// restart (POST, optional percent= and interval_seconds=) or cancels it
+// (POST ?cancel=1). Listeners stay open throughout; new miners are refused
+// until the drain is canceled or the process exits.
+func (s *StatusServer) handleAdminDrain(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.isAdminAuthenticated(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	adminCfg, err := s.adminConfig()
+	if err != nil {
+		http.Error(w, "admin config unavailable", http.StatusInternalServerError)
+		return
+	}
+	if !adminCfg.Enabled {
+		http.Error(w, "admin disabled", http.StatusForbidden)
+		return
+	}
+	password := r.FormValue("password")
+	if password == "" || !s.adminReauthMatches(r, adminCfg, password) {
+		http.Error(w, "invalid password", http.StatusForbidden)
+		return
+	}
+	if s.registry == nil {
+		http.Error(w, "stratum not running", http.StatusServiceUnavailable)
+		return
+	}
+
+	var changed bool
+	if parseAdminBool(r.FormValue("cancel")) {
+		changed = stratumDrain.Cancel()
+	} else {
+		percent := defaultDrainPercent
+		if raw := strings.TrimSpace(r.FormValue("percent")); raw != "" {
+			v, err := strconv.ParseFloat(raw, 64)
+			if err != nil || math.IsNaN(v) || v <= 0 || v > 100 {
+				http.Error(w, "percent must be greater than 0 and at most 100", http.StatusBadRequest)
+				return
+			}
+			percent = v
+		}
+		interval := defaultDrainInterval
+		if raw := strings.TrimSpace(r.FormValue("interval_seconds")); raw != "" {
+			v, err := strconv.Atoi(raw)
+			if err != nil || v < 1 || time.Duration(v)*time.Second > maxDrainInterval {
+				http.Error(w, "interval_seconds must be between 1 and 300", http.StatusBadRequest)
+				return
+			}
+			interval = time.Duration(v) * time.Second
+		}
+		changed = stratumDrain.Start(s.ctx, s.registry, percent, interval)
+	}
+	logger.Info("admin drain request", "component", "admin", "kind", "drain",
+		"cancel", parseAdminBool(r.FormValue("cancel")), "changed", changed, "active", stratumDrain.Active())
+
+	percent, interval := stratumDrain.Settings()
+	resp := struct {
+		OK              bool    `json:"ok"`
+		Active          bool    `json:"active"`
+		Changed         bool    `json:"changed"`
+		Percent         float64 `json:"percent"`
+		IntervalSeconds int     `json:"interval_seconds"`
+		Miners          int     `json:"miners"`
+	}{
+		OK:              true,
+		Active:          stratumDrain.Active(),
+		Changed:         changed,
+		Percent:         percent,
+		IntervalSeconds: int(interval / time.Second),
+		Miners:          s.registry.Count(),
+	}
+	setShortJSONCacheHeaders(w, true)
+	out, err := sonic.Marshal(resp)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+	if _, err := w.Write(out); err != nil {
+		logger.Debug("admin drain json write failed", "component", "http", "kind", "write", "error", err)
+	}
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"context"
 	"fmt"
+	"maps"
+	"math"
 	"net/http"
 	"path/filepath"
 	"strconv"
@@ -81,6 +83,7 @@ func (s *StatusServer) handleAdminBansPage(w http.ResponseWriter, r *http.Reques
 	allRows, loadErr := s.buildAdminBannedWorkers()
 	data.AdminBansLoadError = loadErr
 	data.AdminBannedWorkers, data.AdminBansPagination = paginateAdminSlice(allRows, page, perPage)
+	data.AdminBanFeed = s.banFeed.status()
 	s.renderAdminPageTemplate(w, r, data, "admin_bans")
 }
 
@@ -142,7 +145,7 @@ func (s *StatusServer) handleAdminLogin(w http.ResponseWriter, r *http.Request)
 		http.Error(w, "Bad request", http.StatusBadRequest)
 		return
 	}
-	adminCfg, err := loadAdminConfigFile(s.adminConfigPath)
+	adminCfg, err := s.adminConfig()
 	data, _, _ := s.buildAdminPageData(r, "")
 	if err != nil {
 		data.AdminApplyError = fmt.Sprintf("Failed to read admin config: %v", err)
@@ -161,10 +164,12 @@ func (s *StatusServer) handleAdminLogin(w http.ResponseWriter, r *http.Request)
 		s.renderAdminPage(w, r, data)
 		return
 	}
-	if err := s.scrubAdminPasswordPlaintext(adminCfg); err != nil {
-		logger.Warn("admin password scrub failed", "error", err, "path", s.adminConfigPath)
+	if username == adminCfg.Username {
+		if err := s.scrubAdminPasswordPlaintext(adminCfg, password); err != nil {
+			logger.Warn("admin password scrub failed", "error", err, "path", s.adminConfigPath)
+		}
 	}
-	token, expiry, err := s.createAdminSession(adminCfg.sessionDuration())
+	token, expiry, err := s.createAdminSession(username, adminCfg.sessionDuration(), adminCfg.sessionSecretRotation())
 	if err != nil {
 		logger.Error("create admin session failed", "error", err)
 		data.AdminLoginError = "Unable to start admin session."
@@ -204,6 +209,9 @@ func (s *StatusServer) handleAdminLogout(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	if token, ok := s.adminSessionToken(r); ok {
+		// Look the session up first so a signed token restored after a
+		// restart is cached and its revocation gets recorded.
+		s.adminSessionUser(r)
 		s.invalidateAdminSession(token)
 	}
 	http.SetCookie(w, &http.Cookie{
@@ -243,7 +251,7 @@ func (s *StatusServer) handleAdminApplySettings(w http.ResponseWriter, r *http.R
 		return
 	}
 	password := r.FormValue("password")
-	if password == "" || !s.adminPasswordMatches(adminCfg, password) {
+	if password == "" || !s.adminReauthMatches(r, adminCfg, password) {
 		data.AdminApplyError = "Password is required to apply live settings."
 		s.renderAdminPage(w, r, data)
 		return
@@ -266,31 +274,30 @@ func (s *StatusServer) handleAdminApplySettings(w http.ResponseWriter, r *http.R
 		s.renderAdminPage(w, r, data)
 		return
 	}
-	s.UpdateConfig(cfg)
-	if s.registry != nil {
-		for _, mc := range s.registry.Snapshot() {
-			mc.ApplyRuntimeConfig(cfg)
-		}
+	// Derive payout/donation scripts before changing anything so a bad
+	// address leaves the running config and scripts untouched.
+	payoutScript, donationScript, err := derivePayoutScripts(cfg)
+	if err != nil {
+		data.AdminApplyError = fmt.Sprintf("Settings not applied (current payout scripts kept): %v", err)
+		data.Settings = buildAdminSettingsData(cfg)
+		s.renderAdminPage(w, r, data)
+		return
 	}
 	if s.jobMgr != nil {
-		payoutScript, err := fetchPayoutScript(nil, cfg.PayoutAddress)
-		if err != nil {
-			data.AdminApplyError = fmt.Sprintf("Payout script error: %v", err)
+		if err := s.jobMgr.ApplyRuntimeConfig(cfg, payoutScript, donationScript); err != nil {
+			data.AdminApplyError = fmt.Sprintf("Settings not applied (current payout scripts kept): %v", err)
 			data.Settings = buildAdminSettingsData(cfg)
 			s.renderAdminPage(w, r, data)
 			return
 		}
-		var donationScript []byte
-		if cfg.OperatorDonationPercent > 0 && strings.TrimSpace(cfg.OperatorDonationAddress) != "" {
-			donationScript, err = fetchPayoutScript(nil, cfg.OperatorDonationAddress)
-			if err != nil {
-				data.AdminApplyError = fmt.Sprintf("Donation script error: %v", err)
-				data.Settings = buildAdminSettingsData(cfg)
-				s.renderAdminPage(w, r, data)
-				return
-			}
+	}
+	s.UpdateConfig(cfg)
+	if s.registry != nil {
+		for _, mc := range s.registry.Snapshot() {
+			mc.ApplyRuntimeConfig(cfg)
 		}
-		s.jobMgr.ApplyRuntimeConfig(cfg, payoutScript, donationScript)
+	}
+	if s.jobMgr != nil {
 		go func() {
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
@@ -306,6 +313,7 @@ func (s *StatusServer) handleAdminApplySettings(w http.ResponseWriter, r *http.R
 	}
 	debugLogging = debugEnabled()
 	verboseRuntimeLogging = verboseRuntimeEnabled()
+	setTraceSamplePercent(cfg.LogTraceSamplePercent)
 	logger.Info("admin applied live settings (in memory)", "component", "admin", "kind", "config_apply", "active_miners", s.registry.Count())
 	http.Redirect(w, r, "/admin?notice=settings_applied", http.StatusSeeOther)
 }
@@ -335,7 +343,7 @@ func (s *StatusServer) handleAdminReloadUI(w http.ResponseWriter, r *http.Reques
 		return
 	}
 	password := r.FormValue("password")
-	if password == "" || !s.adminPasswordMatches(adminCfg, password) {
+	if password == "" || !s.adminReauthMatches(r, adminCfg, password) {
 		data.AdminReloadError = "Password is required to reload UI assets."
 		s.renderAdminPage(w, r, data)
 		return
@@ -382,7 +390,7 @@ func (s *StatusServer) handleAdminPersist(w http.ResponseWriter, r *http.Request
 		http.Redirect(w, r, "/admin", http.StatusSeeOther)
 		return
 	}
-	if !s.adminPasswordMatches(adminCfg, r.FormValue("password")) {
+	if !s.adminReauthMatches(r, adminCfg, r.FormValue("password")) {
 		data.AdminPersistError = "Password is required to save to disk."
 		s.renderAdminPage(w, r, data)
 		return
@@ -447,7 +455,7 @@ func (s *StatusServer) handleAdminReboot(w http.ResponseWriter, r *http.Request)
 		http.Redirect(w, r, "/admin", http.StatusSeeOther)
 		return
 	}
-	if !s.adminPasswordMatches(adminCfg, r.FormValue("password")) {
+	if !s.adminReauthMatches(r, adminCfg, r.FormValue("password")) {
 		data.AdminRebootError = "Password is required to reboot."
 		s.renderAdminPage(w, r, data)
 		return
@@ -488,7 +496,7 @@ func (s *StatusServer) handleAdminMinerDisconnect(w http.ResponseWriter, r *http
 		http.Redirect(w, r, "/admin", http.StatusSeeOther)
 		return
 	}
-	if r.FormValue("password") == "" || !s.adminPasswordMatches(adminCfg, r.FormValue("password")) {
+	if r.FormValue("password") == "" || !s.adminReauthMatches(r, adminCfg, r.FormValue("password")) {
 		data.AdminApplyError = "Password is required to disconnect miners."
 		s.renderAdminPageTemplate(w, r, data, "admin_miners")
 		return
@@ -524,6 +532,79 @@ func (s *StatusServer) handleAdminMinerDisconnect(w http.ResponseWriter, r *http
 	s.renderAdminPageTemplate(w, r, data, "admin_miners")
 }
 
+// handleAdminMinerRotateExtranonce gives the selected connections a new
+// extranonce1 via mining.set_extranonce. Connections whose miner did not
+// subscribe to extranonce updates are skipped.
+func (s *StatusServer) handleAdminMinerRotateExtranonce(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "/admin/miners", http.StatusSeeOther)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		logger.Warn("parse admin miner rotate extranonce form", "error", err)
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+	data, adminCfg, _ := s.buildAdminPageData(r, "")
+	data.AdminSection = "miners"
+	page, perPage := adminPaginationFromRequest(r)
+	allRows := s.buildAdminMinerRows()
+	data.AdminMinerRows, data.AdminMinerPagination = paginateAdminSlice(allRows, page, perPage)
+	if !adminCfg.Enabled {
+		data.AdminApplyError = "Admin control panel is disabled."
+		s.renderAdminPageTemplate(w, r, data, "admin_miners")
+		return
+	}
+	if !s.isAdminAuthenticated(r) {
+		http.Redirect(w, r, "/admin", http.StatusSeeOther)
+		return
+	}
+	if r.FormValue("password") == "" || !s.adminReauthMatches(r, adminCfg, r.FormValue("password")) {
+		data.AdminApplyError = "Password is required to rotate extranonces."
+		s.renderAdminPageTemplate(w, r, data, "admin_miners")
+		return
+	}
+	rawSeqs := r.Form["connection_seq"]
+	if len(rawSeqs) == 0 || s.workerRegistry == nil {
+		data.AdminApplyError = "Connection not found."
+		s.renderAdminPageTemplate(w, r, data, "admin_miners")
+		return
+	}
+
+	seen := make(map[uint64]struct{})
+	rotated, unsupported := 0, 0
+	now := time.Now()
+	for _, raw := range rawSeqs {
+		seq, err := strconv.ParseUint(strings.TrimSpace(raw), 10, 64)
+		if err != nil || seq == 0 {
+			continue
+		}
+		if _, ok := seen[seq]; ok {
+			continue
+		}
+		seen[seq] = struct{}{}
+		mc := s.workerRegistry.connectionBySeq(seq)
+		if mc == nil {
+			continue
+		}
+		if _, err := mc.rotateExtranonce1(now); err != nil {
+			unsupported++
+			continue
+		}
+		rotated++
+	}
+	if rotated > 0 {
+		http.Redirect(w, r, "/admin/miners?notice=miner_extranonce_rotated", http.StatusSeeOther)
+		return
+	}
+	if unsupported > 0 {
+		data.AdminApplyError = "Selected miners did not subscribe to extranonce updates."
+	} else {
+		data.AdminApplyError = "Connection not found."
+	}
+	s.renderAdminPageTemplate(w, r, data, "admin_miners")
+}
+
 func (s *StatusServer) handleAdminMinerBan(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Redirect(w, r, "/admin/miners", http.StatusSeeOther)
@@ -548,7 +629,7 @@ func (s *StatusServer) handleAdminMinerBan(w http.ResponseWriter, r *http.Reques
 		http.Redirect(w, r, "/admin", http.StatusSeeOther)
 		return
 	}
-	if r.FormValue("password") == "" || !s.adminPasswordMatches(adminCfg, r.FormValue("password")) {
+	if r.FormValue("password") == "" || !s.adminReauthMatches(r, adminCfg, r.FormValue("password")) {
 		data.AdminApplyError = "Password is required to ban miners."
 		s.renderAdminPageTemplate(w, r, data, "admin_miners")
 		return
@@ -586,6 +667,113 @@ func (s *StatusServer) handleAdminMinerBan(w http.ResponseWriter, r *http.Reques
 	s.renderAdminPageTemplate(w, r, data, "admin_miners")
 }
 
+// handleAdminMinerSetDiff pins the selected connections' worker names to a
+// difficulty (0 removes the pin) via tuning [difficulty].worker_overrides,
+// applies it to every live connection and saves tuning.toml.
+func (s *StatusServer) handleAdminMinerSetDiff(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "/admin/miners", http.StatusSeeOther)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		logger.Warn("parse admin miner setdiff form", "error", err)
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+	data, adminCfg, _ := s.buildAdminPageData(r, "")
+	data.AdminSection = "miners"
+	page, perPage := adminPaginationFromRequest(r)
+	allRows := s.buildAdminMinerRows()
+	data.AdminMinerRows, data.AdminMinerPagination = paginateAdminSlice(allRows, page, perPage)
+	if !adminCfg.Enabled {
+		data.AdminApplyError = "Admin control panel is disabled."
+		s.renderAdminPageTemplate(w, r, data, "admin_miners")
+		return
+	}
+	if !s.isAdminAuthenticated(r) {
+		http.Redirect(w, r, "/admin", http.StatusSeeOther)
+		return
+	}
+	if r.FormValue("password") == "" || !s.adminReauthMatches(r, adminCfg, r.FormValue("password")) {
+		data.AdminApplyError = "Password is required to set miner difficulty."
+		s.renderAdminPageTemplate(w, r, data, "admin_miners")
+		return
+	}
+	diff, err := strconv.ParseFloat(strings.TrimSpace(r.FormValue("difficulty")), 64)
+	if err != nil || diff < 0 || math.IsNaN(diff) || math.IsInf(diff, 0) {
+		data.AdminApplyError = "Difficulty must be a number (0 removes the override)."
+		s.renderAdminPageTemplate(w, r, data, "admin_miners")
+		return
+	}
+	rawSeqs := r.Form["connection_seq"]
+	if len(rawSeqs) == 0 || s.workerRegistry == nil {
+		data.AdminApplyError = "Connection not found."
+		s.renderAdminPageTemplate(w, r, data, "admin_miners")
+		return
+	}
+
+	cfg := s.Config()
+	overrides := make(map[string]float64, len(cfg.WorkerDifficultyOverrides)+len(rawSeqs))
+	maps.Copy(overrides, cfg.WorkerDifficultyOverrides)
+	matched := 0
+	for _, raw := range rawSeqs {
+		seq, err := strconv.ParseUint(strings.TrimSpace(raw), 10, 64)
+		if err != nil || seq == 0 {
+			continue
+		}
+		mc := s.workerRegistry.connectionBySeq(seq)
+		if mc == nil {
+			continue
+		}
+		worker := strings.ToLower(strings.TrimSpace(mc.currentWorker()))
+		if worker == "" {
+			continue
+		}
+		matched++
+		if diff > 0 {
+			overrides[worker] = diff
+		} else {
+			delete(overrides, worker)
+		}
+	}
+	if matched == 0 {
+		data.AdminApplyError = "Connection not found."
+		s.renderAdminPageTemplate(w, r, data, "admin_miners")
+		return
+	}
+	cfg.WorkerDifficultyOverrides = normalizeWorkerDifficultyOverrides(overrides)
+	if err := validateWorkerDifficultyOverrides(cfg.WorkerDifficultyOverrides); err != nil {
+		data.AdminApplyError = fmt.Sprintf("Difficulty not set: %v", err)
+		s.renderAdminPageTemplate(w, r, data, "admin_miners")
+		return
+	}
+	s.UpdateConfig(cfg)
+	s.applyWorkerDifficultyOverrides(cfg)
+
+	tuningPath := filepath.Join(filepath.Dir(s.configPath), "tuning.toml")
+	if err := rewriteTuningFile(tuningPath, cfg); err != nil {
+		data.AdminApplyError = fmt.Sprintf("Difficulty applied in memory, but saving tuning.toml failed: %v", err)
+		s.renderAdminPageTemplate(w, r, data, "admin_miners")
+		return
+	}
+	logger.Info("admin set worker difficulty override", "component", "admin", "kind", "difficulty", "connections", matched, "difficulty", diff, "tuning_path", tuningPath)
+	http.Redirect(w, r, "/admin/miners?notice=miner_difficulty_set", http.StatusSeeOther)
+}
+
+// applyWorkerDifficultyOverrides hands cfg to every live connection and
+// pins or releases each one per its worker name.
+func (s *StatusServer) applyWorkerDifficultyOverrides(cfg Config) {
+	if s.registry == nil {
+		return
+	}
+	for _, mc := range s.registry.Snapshot() {
+		mc.ApplyRuntimeConfig(cfg)
+		if mc.applyDifficultyOverride(cfg, mc.currentWorker()) {
+			mc.maybeSendCleanJobAfterSuggest()
+		}
+	}
+}
+
 func (s *StatusServer) handleAdminLoginDelete(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Redirect(w, r, "/admin/logins", http.StatusSeeOther)
@@ -611,7 +799,7 @@ func (s *StatusServer) handleAdminLoginDelete(w http.ResponseWriter, r *http.Req
 		http.Redirect(w, r, "/admin", http.StatusSeeOther)
 		return
 	}
-	if r.FormValue("password") == "" || !s.adminPasswordMatches(adminCfg, r.FormValue("password")) {
+	if r.FormValue("password") == "" || !s.adminReauthMatches(r, adminCfg, r.FormValue("password")) {
 		data.AdminApplyError = "Password is required to delete saved workers."
 		s.renderAdminPageTemplate(w, r, data, "admin_logins")
 		return
@@ -664,7 +852,7 @@ func (s *StatusServer) handleAdminLoginBan(w http.ResponseWriter, r *http.Reques
 		http.Redirect(w, r, "/admin", http.StatusSeeOther)
 		return
 	}
-	if r.FormValue("password") == "" || !s.adminPasswordMatches(adminCfg, r.FormValue("password")) {
+	if r.FormValue("password") == "" || !s.adminReauthMatches(r, adminCfg, r.FormValue("password")) {
 		data.AdminApplyError = "Password is required to ban saved workers."
 		s.renderAdminPageTemplate(w, r, data, "admin_logins")
 		return
@@ -733,7 +921,7 @@ func (s *StatusServer) handleAdminBanRemove(w http.ResponseWriter, r *http.Reque
 		http.Redirect(w, r, "/admin", http.StatusSeeOther)
 		return
 	}
-	if r.FormValue("password") == "" || !s.adminPasswordMatches(adminCfg, r.FormValue("password")) {
+	if r.FormValue("password") == "" || !s.adminReauthMatches(r, adminCfg, r.FormValue("password")) {
 		data.AdminApplyError = "Password is required to remove bans."
 		s.renderAdminPageTemplate(w, r, data, "admin_bans")
 		return
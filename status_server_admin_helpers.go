@@ -94,7 +94,7 @@ func (s *StatusServer) buildAdminPageData(r *http.Request, noticeKey string) (Ad
 		AdminPerPageOptions: adminPerPageOptions,
 		AdminLogSources:     adminLogSourceKeys(),
 	}
-	cfg, err := loadAdminConfigFile(s.adminConfigPath)
+	cfg, err := s.adminConfig()
 	if err != nil {
 		logger.Warn("load admin config failed", "error", err, "path", s.adminConfigPath)
 		data.AdminEnabled = false
@@ -115,6 +115,7 @@ func (s *StatusServer) buildAdminPageData(r *http.Request, noticeKey string) (Ad
 	data.AdminDebugEnabled = debugLogging
 	data.AdminNetDebugSupport = netLogRuntimeSupported()
 	data.AdminNetDebugEnabled = netLogRuntimeEnabled()
+	data.AdminTraceSamplePct = traceSamplePercent()
 	return data, cfg, nil
 }
 
@@ -147,6 +148,9 @@ func (s *StatusServer) buildAdminOperatorStats(status StatusData, settings Admin
 		stats.Currency.LastPrice = priceSnap.LastPrice
 		stats.Currency.LastFetchAt = priceSnap.LastFetch
 		stats.Currency.LastError = priceSnap.LastErr
+		if s.priceSvc.Offline() {
+			stats.Currency.LastError = errPriceOffline.Error()
+		}
 		if fiat := strings.ToUpper(strings.TrimSpace(priceSnap.LastFiat)); fiat != "" {
 			stats.Currency.FiatCurrency = fiat
 		}
@@ -231,6 +235,10 @@ func adminNoticeMessage(key string) string {
 		return "Miner connection disconnected."
 	case "miner_banned":
 		return "Miner connection banned and closed."
+	case "miner_extranonce_rotated":
+		return "Extranonce rotated on miners that support set_extranonce."
+	case "miner_difficulty_set":
+		return "Worker difficulty override saved to tuning.toml."
 	case "saved_worker_deleted":
 		return "Saved worker entry deleted."
 	case "saved_worker_banned":
@@ -3,10 +3,12 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"math"
 	"net/http"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -158,7 +160,7 @@ func (s *StatusServer) handleAdminLogsSetFlags(w http.ResponseWriter, r *http.Re
 		http.Error(w, "bad request", http.StatusBadRequest)
 		return
 	}
-	adminCfg, err := loadAdminConfigFile(s.adminConfigPath)
+	adminCfg, err := s.adminConfig()
 	if err != nil {
 		http.Error(w, "admin config unavailable", http.StatusInternalServerError)
 		return
@@ -168,7 +170,7 @@ func (s *StatusServer) handleAdminLogsSetFlags(w http.ResponseWriter, r *http.Re
 		return
 	}
 	password := r.FormValue("password")
-	if password == "" || !s.adminPasswordMatches(adminCfg, password) {
+	if password == "" || !s.adminReauthMatches(r, adminCfg, password) {
 		http.Error(w, "invalid password", http.StatusForbidden)
 		return
 	}
@@ -176,12 +178,26 @@ func (s *StatusServer) handleAdminLogsSetFlags(w http.ResponseWriter, r *http.Re
 	debugEnabledRequested := parseAdminBool(r.FormValue("debug"))
 	netDebugEnabledRequested := parseAdminBool(r.FormValue("net_debug"))
 
+	// trace_sample_percent is optional so older clients that only post the
+	// two checkboxes leave the current sampling rate alone.
+	cfg := s.Config()
+	traceSamplePercentRequested := cfg.LogTraceSamplePercent
+	if raw := strings.TrimSpace(r.FormValue("trace_sample_percent")); raw != "" {
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsNaN(v) || v < 0 || v > 100 {
+			http.Error(w, "trace_sample_percent must be between 0 and 100", http.StatusBadRequest)
+			return
+		}
+		traceSamplePercentRequested = v
+	}
+
 	// Simplified operator model: app logs are always INFO+ in pool.log; debug
 	// toggles additional DEBUG logs (and verbose runtime traces).
-	cfg := s.Config()
 	cfg.LogDebug = debugEnabledRequested
 	cfg.LogNetDebug = netDebugEnabledRequested
+	cfg.LogTraceSamplePercent = traceSamplePercentRequested
 	s.UpdateConfig(cfg)
+	setTraceSamplePercent(traceSamplePercentRequested)
 
 	debugErrMsg := ""
 	if debugEnabledRequested {
@@ -227,19 +243,22 @@ func (s *StatusServer) handleAdminLogsSetFlags(w http.ResponseWriter, r *http.Re
 		"debug", debugEnabledRequested,
 		"net_debug", netDebugEnabledRequested,
 		"net_debug_supported", netDebugSupported,
+		"trace_sample_percent", traceSamplePercentRequested,
 	)
 
 	resp := struct {
-		OK              bool   `json:"ok"`
-		Debug           bool   `json:"debug"`
-		NetDebug        bool   `json:"net_debug"`
-		NetDebugSupport bool   `json:"net_debug_supported"`
-		Error           string `json:"error,omitempty"`
+		OK                 bool    `json:"ok"`
+		Debug              bool    `json:"debug"`
+		NetDebug           bool    `json:"net_debug"`
+		NetDebugSupport    bool    `json:"net_debug_supported"`
+		TraceSamplePercent float64 `json:"trace_sample_percent"`
+		Error              string  `json:"error,omitempty"`
 	}{
-		OK:              debugErrMsg == "" && netDebugErrMsg == "",
-		Debug:           debugLogging,
-		NetDebug:        netLogRuntimeEnabled(),
-		NetDebugSupport: netDebugSupported,
+		OK:                 debugErrMsg == "" && netDebugErrMsg == "",
+		Debug:              debugLogging,
+		NetDebug:           netLogRuntimeEnabled(),
+		NetDebugSupport:    netDebugSupported,
+		TraceSamplePercent: traceSamplePercent(),
 	}
 	_ = netDebugApplied
 	if debugErrMsg != "" && netDebugErrMsg != "" {
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	// Signed admin session tokens are "v1.<payload>.<mac>" (base64url). The
+	// payload is the expiry (unix seconds, big-endian), a random nonce and
+	// the username; the mac is HMAC-SHA256 under the current session key.
+	adminSignedTokenPrefix = "v1."
+	adminSessionNonceLen   = 16
+	adminSessionKeyLen     = 32
+	// adminSessionKeyReload bounds how long a process trusts its cached key
+	// before re-reading the state DB, so a rotation done by another process
+	// sharing the DB is picked up.
+	adminSessionKeyReload = time.Minute
+)
+
+var adminSessionMACContext = []byte("goPool admin session v1\x00")
+
+// adminSessionKey is the HMAC key that signs admin session tokens when
+// admin.toml session_secret_rotation_hours is set. It lives in the state DB
+// (never in a config file) so sessions survive restarts.
+type adminSessionKey struct {
+	secret  []byte
+	created time.Time
+}
+
+func (k adminSessionKey) generation() int64 {
+	return k.created.Unix()
+}
+
+func (k adminSessionKey) expired(now time.Time, rotation time.Duration) bool {
+	return len(k.secret) == 0 || now.Sub(k.created) >= rotation
+}
+
+func newAdminSessionKey(now time.Time) (adminSessionKey, error) {
+	secret := make([]byte, adminSessionKeyLen)
+	if _, err := rand.Read(secret); err != nil {
+		return adminSessionKey{}, err
+	}
+	return adminSessionKey{secret: secret, created: now.Truncate(time.Second)}, nil
+}
+
+// currentAdminSessionKey returns the signing key, creating or rotating it
+// when it is missing or older than rotation. Rotating invalidates every
+// token signed with the old key, which sends those admins back to the login
+// page. Without a state DB the key is kept in memory only.
+func (s *StatusServer) currentAdminSessionKey(now time.Time, rotation time.Duration) (adminSessionKey, error) {
+	s.adminKeyMu.Lock()
+	defer s.adminKeyMu.Unlock()
+	cached := s.adminKey
+	if !cached.expired(now, rotation) && now.Sub(s.adminKeyLoadedAt) < adminSessionKeyReload {
+		return cached, nil
+	}
+
+	db := getSharedStateDB()
+	if db == nil {
+		if !cached.expired(now, rotation) {
+			return cached, nil
+		}
+		key, err := newAdminSessionKey(now)
+		if err != nil {
+			return adminSessionKey{}, err
+		}
+		logger.Warn("state db unavailable; admin session key kept in memory only", "component", "admin", "kind", "auth")
+		s.setAdminSessionKeyLocked(key, now)
+		return key, nil
+	}
+
+	stored, err := loadAdminSessionKey(db)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return adminSessionKey{}, err
+	}
+	if stored.expired(now, rotation) {
+		previous := stored
+		key, err := newAdminSessionKey(now)
+		if err != nil {
+			return adminSessionKey{}, err
+		}
+		// Only replace the row we read, so processes sharing the DB agree on
+		// one new key when several notice the expiry at once.
+		if _, err := db.Exec(`
+			INSERT INTO admin_session_key (id, secret, created_at_unix) VALUES (1, ?, ?)
+			ON CONFLICT(id) DO UPDATE SET secret = excluded.secret, created_at_unix = excluded.created_at_unix
+			WHERE admin_session_key.created_at_unix = ?
+		`, key.secret, key.created.Unix(), previous.created.Unix()); err != nil {
+			return adminSessionKey{}, err
+		}
+		if _, err := db.Exec("DELETE FROM admin_session_revocations WHERE expires_at_unix < ?", now.Unix()); err != nil {
+			logger.Warn("prune admin session revocations failed", "component", "admin", "kind", "auth", "error", err)
+		}
+		if stored, err = loadAdminSessionKey(db); err != nil {
+			return adminSessionKey{}, err
+		}
+		if len(previous.secret) > 0 {
+			logger.Info("admin session key rotated; existing admin sessions must log in again", "component", "admin", "kind", "auth")
+		}
+	}
+	s.setAdminSessionKeyLocked(stored, now)
+	return stored, nil
+}
+
+func (s *StatusServer) setAdminSessionKeyLocked(key adminSessionKey, now time.Time) {
+	if s.adminKey.generation() != key.generation() {
+		// Cached sessions were verified under the old key; drop them so
+		// they are re-checked (and rejected) on their next request.
+		s.adminSessionsMu.Lock()
+		for token, sess := range s.adminSessions {
+			if sess.keyGeneration != 0 {
+				delete(s.adminSessions, token)
+			}
+		}
+		s.adminSessionsMu.Unlock()
+	}
+	s.adminKey = key
+	s.adminKeyLoadedAt = now
+}
+
+func loadAdminSessionKey(db *sql.DB) (adminSessionKey, error) {
+	var secret []byte
+	var createdUnix int64
+	if err := db.QueryRow("SELECT secret, created_at_unix FROM admin_session_key WHERE id = 1").Scan(&secret, &createdUnix); err != nil {
+		return adminSessionKey{}, err
+	}
+	return adminSessionKey{secret: secret, created: time.Unix(createdUnix, 0)}, nil
+}
+
+func signAdminSessionToken(key adminSessionKey, username string, expiry time.Time) (string, error) {
+	payload := make([]byte, 8+adminSessionNonceLen, 8+adminSessionNonceLen+len(username))
+	binary.BigEndian.PutUint64(payload, uint64(expiry.Unix()))
+	if _, err := rand.Read(payload[8:]); err != nil {
+		return "", err
+	}
+	payload = append(payload, username...)
+	return adminSignedTokenPrefix + base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(adminSessionMAC(key, payload)), nil
+}
+
+func adminSessionMAC(key adminSessionKey, payload []byte) []byte {
+	mac := hmac.New(sha256.New, key.secret)
+	mac.Write(adminSessionMACContext)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// parseAdminSessionToken splits a signed token without checking its mac.
+func parseAdminSessionToken(token string) (payload, mac []byte, ok bool) {
+	rest, found := strings.CutPrefix(token, adminSignedTokenPrefix)
+	if !found {
+		return nil, nil, false
+	}
+	p, m, found := strings.Cut(rest, ".")
+	if !found {
+		return nil, nil, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(p)
+	if err != nil || len(payload) <= 8+adminSessionNonceLen {
+		return nil, nil, false
+	}
+	mac, err = base64.RawURLEncoding.DecodeString(m)
+	if err != nil {
+		return nil, nil, false
+	}
+	return payload, mac, true
+}
+
+// verifyAdminSessionToken returns the username and expiry of a token signed
+// with key. Tokens signed with an earlier key simply fail here.
+func verifyAdminSessionToken(key adminSessionKey, token string, now time.Time) (string, time.Time, bool) {
+	payload, mac, ok := parseAdminSessionToken(token)
+	if !ok || len(key.secret) == 0 || !hmac.Equal(mac, adminSessionMAC(key, payload)) {
+		return "", time.Time{}, false
+	}
+	expiry := time.Unix(int64(binary.BigEndian.Uint64(payload)), 0)
+	if now.After(expiry) {
+		return "", time.Time{}, false
+	}
+	return string(payload[8+adminSessionNonceLen:]), expiry, true
+}
+
+func adminSessionTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// revokeAdminSessionToken records a logged-out signed token in the state DB
+// so it stays invalid across restarts until it would have expired anyway.
+func revokeAdminSessionToken(token string) {
+	payload, _, ok := parseAdminSessionToken(token)
+	db := getSharedStateDB()
+	if !ok || db == nil {
+		return
+	}
+	expiry := int64(binary.BigEndian.Uint64(payload))
+	if _, err := db.Exec("INSERT OR REPLACE INTO admin_session_revocations (token_sha256, expires_at_unix) VALUES (?, ?)", adminSessionTokenHash(token), expiry); err != nil {
+		logger.Warn("record admin session revocation failed", "component", "admin", "kind", "auth", "error", err)
+	}
+}
+
+func adminSessionTokenRevoked(token string) bool {
+	db := getSharedStateDB()
+	if db == nil {
+		return false
+	}
+	var one int
+	err := db.QueryRow("SELECT 1 FROM admin_session_revocations WHERE token_sha256 = ?", adminSessionTokenHash(token)).Scan(&one)
+	// Fail closed: a lookup error is treated as revoked.
+	return !errors.Is(err, sql.ErrNoRows)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSignedAdminSessionsSurviveRestartAndRotate(t *testing.T) {
+	dir := t.TempDir()
+	db, err := openStateDB(filepath.Join(dir, "state", "workers.db"))
+	if err != nil {
+		t.Fatalf("openStateDB: %v", err)
+	}
+	defer db.Close()
+	defer setSharedStateDBForTest(db)()
+
+	adminPath := filepath.Join(dir, "admin.toml")
+	cfg := adminFileConfig{
+		Enabled:                    true,
+		Username:                   "admin",
+		PasswordSHA256:             adminPasswordHash("primary-password-123"),
+		SessionExpirationSeconds:   900,
+		SessionSecretRotationHours: 1,
+	}
+	if err := os.WriteFile(adminPath, []byte(renderAdminConfig(cfg)), 0o600); err != nil {
+		t.Fatalf("write admin config: %v", err)
+	}
+	newServer := func() *StatusServer {
+		return &StatusServer{adminConfigPath: adminPath, adminSessions: make(map[string]adminSession)}
+	}
+	request := func(token string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+		req.AddCookie(&http.Cookie{Name: adminSessionCookieName, Value: token})
+		return req
+	}
+
+	token, _, err := newServer().createAdminSession("admin", time.Minute, cfg.sessionSecretRotation())
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	restarted := newServer()
+	if !restarted.isAdminAuthenticated(request(token)) {
+		t.Fatalf("signed session should survive a restart")
+	}
+	if restarted.isAdminAuthenticated(request(token + "x")) {
+		t.Fatalf("tampered token must not verify")
+	}
+
+	restarted.invalidateAdminSession(token)
+	if restarted.isAdminAuthenticated(request(token)) || newServer().isAdminAuthenticated(request(token)) {
+		t.Fatalf("logged-out session must stay invalid, including after a restart")
+	}
+
+	token, _, err = restarted.createAdminSession("admin", time.Minute, cfg.sessionSecretRotation())
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	old, err := loadAdminSessionKey(db)
+	if err != nil {
+		t.Fatalf("load key: %v", err)
+	}
+	if _, err := db.Exec("UPDATE admin_session_key SET created_at_unix = ?", time.Now().Add(-2*time.Hour).Unix()); err != nil {
+		t.Fatalf("age key: %v", err)
+	}
+	if newServer().isAdminAuthenticated(request(token)) {
+		t.Fatalf("session signed with a rotated key must require a new login")
+	}
+	rotated, err := loadAdminSessionKey(db)
+	if err != nil {
+		t.Fatalf("load key: %v", err)
+	}
+	if string(rotated.secret) == string(old.secret) {
+		t.Fatalf("expected the session key to rotate")
+	}
+}
@@ -23,6 +23,7 @@ type AdminPageData struct {
 	AdminMinerRows         []AdminMinerRow
 	AdminSavedWorkerRows   []AdminSavedWorkerRow
 	AdminBannedWorkers     []WorkerView
+	AdminBanFeed           banFeedStatus
 	AdminMinerPagination   AdminPagination
 	AdminLoginPagination   AdminPagination
 	AdminBansPagination    AdminPagination
@@ -34,6 +35,7 @@ type AdminPageData struct {
 	AdminDebugEnabled      bool
 	AdminNetDebugEnabled   bool
 	AdminNetDebugSupport   bool
+	AdminTraceSamplePct    float64
 	OperatorStats          AdminOperatorStatsData
 }
 
@@ -29,6 +29,11 @@ func setShortHTMLCacheHeaders(w http.ResponseWriter, private bool) {
 	w.Header().Set("Cache-Control", cacheControlShortTTL(private))
 }
 
+func setShortTextCacheHeaders(w http.ResponseWriter, private bool) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", cacheControlShortTTL(private))
+}
+
 func logResponseWriteDebug(msg string, err error, attrs ...any) {
 	if err == nil {
 		return
@@ -58,32 +58,90 @@ func (s *StatusServer) handleNodePageJSON(w http.ResponseWriter, r *http.Request
 	})
 }
 
-// handleBlocksListJSON returns found blocks.
+// handleBlocksListJSON returns one newest-first page of found blocks
+// (?limit=1..500, default 50; ?offset=; ?since=unix seconds) with the total
+// number of matching blocks.
 func (s *StatusServer) handleBlocksListJSON(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
-	limit := 10
-	if l := strings.TrimSpace(r.URL.Query().Get("limit")); l != "" {
-		if n, err := strconv.Atoi(l); err == nil && n > 0 && n <= 100 {
-			limit = n
-		}
+	limit, offset, since, problem := parseBlocksPageQuery(r.URL.Query().Get)
+	if problem != "" {
+		http.Error(w, problem, http.StatusBadRequest)
+		return
 	}
 
-	key := fmt.Sprintf("blocks_%d", limit)
-	s.serveCachedJSON(w, key, blocksRefreshInterval, func() ([]byte, error) {
+	build := func() ([]byte, error) {
 		view := s.statusDataView()
-		blocks := view.FoundBlocks
-		if len(blocks) > limit {
-			blocks = blocks[:limit]
+		blocks, total, ok, err := loadFoundBlocksPage(since, limit, offset)
+		if err != nil {
+			return nil, err
 		}
-		out := make([]FoundBlockView, 0, len(blocks))
-		for _, b := range blocks {
-			out = append(out, censorFoundBlock(b))
+		if !ok {
+			blocks, total = pageCachedFoundBlocks(view.FoundBlocks, since, limit, offset)
 		}
-		return sonic.Marshal(out)
-	})
+		annotateFoundBlocks(blocks, view.FoundBlocks, view.NodeBlocks)
+		for i := range blocks {
+			blocks[i] = censorFoundBlock(blocks[i])
+		}
+		return sonic.Marshal(blocksPage{Blocks: blocks, Total: total, Limit: limit, Offset: offset, Since: since})
+	}
+	// Only the first page of each size is cached; deeper pages and since
+	// queries are built per request so the cache stays bounded.
+	if offset != 0 || since != 0 {
+		payload, err := build()
+		if err != nil {
+			logger.Error("load found blocks page", "component", "status", "kind", "blocks", "error", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		setShortJSONCacheHeaders(w, false)
+		if _, err := w.Write(payload); err != nil {
+			logResponseWriteDebug("write blocks page response", err)
+		}
+		return
+	}
+	s.serveCachedJSON(w, fmt.Sprintf("blocks_%d", limit), blocksRefreshInterval, build)
+}
+
+// handleBlockDetailJSON returns the stored found-block record for ?height=,
+// including the recorded reward distribution when available.
+func (s *StatusServer) handleBlockDetailJSON(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	height, err := strconv.ParseInt(strings.TrimSpace(r.URL.Query().Get("height")), 10, 64)
+	if err != nil || height <= 0 {
+		http.Error(w, "invalid height", http.StatusBadRequest)
+		return
+	}
+	detail, err := loadFoundBlockDetail(height, r.URL.Query().Get("hash"))
+	if err != nil {
+		logger.Error("load found block detail", "height", height, "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if detail == nil {
+		http.Error(w, "block not found", http.StatusNotFound)
+		return
+	}
+	if detail.Worker != "" {
+		detail.Worker = shortWorkerName(detail.Worker, workerNamePrefix, workerNameSuffix)
+	}
+	if detail.RewardDistribution != nil && detail.RewardDistribution.Worker != "" {
+		detail.RewardDistribution.Worker = detail.Worker
+	}
+	payload, err := sonic.Marshal(detail)
+	if err != nil {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	setShortJSONCacheHeaders(w, false)
+	if _, err := w.Write(payload); err != nil {
+		logResponseWriteDebug("write block detail response", err, "height", height)
+	}
 }
 
 // handleOverviewPageJSON returns minimal data for the overview page.
@@ -143,12 +201,14 @@ func (s *StatusServer) handleOverviewPageJSON(w http.ResponseWriter, r *http.Req
 			PoolTag:         poolTag,
 			BTCPriceFiat:    btcFiat,
 			BTCPriceUpdated: btcUpdated,
+			BTCPriceOffline: s.priceSvc.Offline(),
 			FiatCurrency:    fiatCurrency,
 			RenderDuration:  time.Since(start),
 			Workers:         recentWork,
 			BannedWorkers:   censoredBanned,
 			BestShares:      bestShares,
 			MinerTypes:      view.MinerTypes,
+			Operator:        s.operatorFieldsView(),
 		}
 		return sonic.Marshal(data)
 	})
@@ -166,8 +226,15 @@ func (s *StatusServer) handlePoolPageJSON(w http.ResponseWriter, r *http.Request
 		safeguardDisconnectCount, safeguardDisconnects := s.stratumSafeguardDisconnectSnapshot()
 		data := PoolPageData{
 			APIVersion:                      apiVersion,
+			CoinbaseTag:                     s.Config().CoinbaseMsg,
+			Operator:                        s.operatorFieldsView(),
 			BlocksAccepted:                  view.BlocksAccepted,
 			BlocksErrored:                   view.BlocksErrored,
+			NearMisses:                      view.NearMisses,
+			LastNearMiss:                    view.LastNearMiss,
+			TransportUpgrades:               view.TransportUpgrades,
+			TransportDowngrades:             view.TransportDowngrades,
+			SubmitPanics:                    view.SubmitPanics,
 			RPCGBTLastSec:                   view.RPCGBTLastSec,
 			RPCGBTMaxSec:                    view.RPCGBTMaxSec,
 			RPCGBTCount:                     view.RPCGBTCount,
@@ -176,6 +243,7 @@ func (s *StatusServer) handlePoolPageJSON(w http.ResponseWriter, r *http.Request
 			RPCSubmitCount:                  view.RPCSubmitCount,
 			RPCErrors:                       view.RPCErrors,
 			ShareErrors:                     view.ShareErrors,
+			TemplateDecodeErrors:            view.TemplateDecodeErrors,
 			RPCGBTMin1hSec:                  view.RPCGBTMin1hSec,
 			RPCGBTAvg1hSec:                  view.RPCGBTAvg1hSec,
 			RPCGBTMax1hSec:                  view.RPCGBTMax1hSec,
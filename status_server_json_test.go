@@ -2,8 +2,10 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -67,6 +69,7 @@ func TestJSONEndpoints_MethodNotAllowed(t *testing.T) {
 
 func TestHandleBlocksListJSON_LimitAndCensoring(t *testing.T) {
 	s := newStatusServerForJSONTests()
+	t.Cleanup(setSharedStateDBForTest(nil))
 
 	t.Run("respects_limit", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/api/blocks?limit=2", nil)
@@ -76,12 +79,13 @@ func TestHandleBlocksListJSON_LimitAndCensoring(t *testing.T) {
 			t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
 		}
 
-		var blocks []FoundBlockView
-		if err := json.Unmarshal(rr.Body.Bytes(), &blocks); err != nil {
+		var page blocksPage
+		if err := json.Unmarshal(rr.Body.Bytes(), &page); err != nil {
 			t.Fatalf("decode blocks response: %v", err)
 		}
-		if len(blocks) != 2 {
-			t.Fatalf("expected 2 blocks, got %d", len(blocks))
+		blocks := page.Blocks
+		if len(blocks) != 2 || page.Total != 3 {
+			t.Fatalf("expected 2 of 3 blocks, got %d of %d", len(blocks), page.Total)
 		}
 		if blocks[0].Hash == s.cachedStatus.FoundBlocks[0].Hash {
 			t.Fatalf("expected censored block hash, got original")
@@ -91,22 +95,80 @@ func TestHandleBlocksListJSON_LimitAndCensoring(t *testing.T) {
 		}
 	})
 
-	t.Run("invalid_limit_falls_back", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodGet, "/api/blocks?limit=999", nil)
+	t.Run("offset_pages", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/blocks?limit=2&offset=2", nil)
 		rr := httptest.NewRecorder()
 		s.handleBlocksListJSON(rr, req)
 		if rr.Code != http.StatusOK {
 			t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
 		}
-
-		var blocks []FoundBlockView
-		if err := json.Unmarshal(rr.Body.Bytes(), &blocks); err != nil {
+		var page blocksPage
+		if err := json.Unmarshal(rr.Body.Bytes(), &page); err != nil {
 			t.Fatalf("decode blocks response: %v", err)
 		}
-		if len(blocks) != 3 {
-			t.Fatalf("expected fallback to all 3 available blocks, got %d", len(blocks))
+		if len(page.Blocks) != 1 || page.Blocks[0].Height != 899999 || page.Total != 3 {
+			t.Fatalf("unexpected page: %+v", page)
 		}
 	})
+
+	for _, q := range []string{"limit=0", "limit=501", "limit=x", "offset=-1", "since=-5"} {
+		t.Run("rejects_"+q, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/blocks?"+q, nil)
+			rr := httptest.NewRecorder()
+			s.handleBlocksListJSON(rr, req)
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+			}
+		})
+	}
+}
+
+func TestHandleBlocksListJSON_StateDBPaging(t *testing.T) {
+	db, err := openStateDB(filepath.Join(t.TempDir(), "state", "workers.db"))
+	if err != nil {
+		t.Fatalf("openStateDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	t.Cleanup(setSharedStateDBForTest(db))
+
+	base := time.Unix(1_700_000_000, 0)
+	for i := 0; i < 5; i++ {
+		rec := fmt.Sprintf(`{"timestamp":%q,"height":%d,"hash":"%064x","worker":"w%d"}`,
+			base.Add(time.Duration(i)*time.Hour).UTC().Format(time.RFC3339), 100+i, i+1, i)
+		if _, err := db.Exec("INSERT INTO found_blocks_log (created_at_unix, json) VALUES (?, ?)", base.Unix()+int64(i)*3600, rec); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	}
+	if _, err := db.Exec("INSERT INTO found_blocks_log (created_at_unix, json) VALUES (?, ?)", base.Unix()+99999, `{"height":1,"hash":"dummyhash"}`); err != nil {
+		t.Fatalf("insert dummy: %v", err)
+	}
+
+	s := newStatusServerForJSONTests()
+	get := func(q string) blocksPage {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		s.handleBlocksListJSON(rr, httptest.NewRequest(http.MethodGet, "/api/blocks?"+q, nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: expected status %d, got %d", q, http.StatusOK, rr.Code)
+		}
+		var page blocksPage
+		if err := json.Unmarshal(rr.Body.Bytes(), &page); err != nil {
+			t.Fatalf("%s: decode: %v", q, err)
+		}
+		return page
+	}
+
+	page := get("limit=2&offset=1")
+	if page.Total != 5 || len(page.Blocks) != 2 || page.Blocks[0].Height != 103 || page.Blocks[1].Height != 102 {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+	page = get(fmt.Sprintf("since=%d", base.Unix()+2*3600))
+	if page.Total != 3 || len(page.Blocks) != 3 || page.Blocks[0].Height != 104 {
+		t.Fatalf("unexpected since page: %+v", page)
+	}
+	if page := get("offset=10"); page.Total != 5 || len(page.Blocks) != 0 {
+		t.Fatalf("expected empty page past the end, got %+v", page)
+	}
 }
 
 func TestHandlePoolHashrateJSON_IncludeHistoryToggle(t *testing.T) {
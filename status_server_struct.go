@@ -65,18 +65,26 @@ type StatusServer struct {
 	stratumSafeguardDisconnectCount uint64
 
 	backupSvc *backblazeBackupService
+	banFeed   *banFeed
 
 	responseCacheMu sync.RWMutex
 	responseCache   map[string]cachedHTTPResponse
 
 	configPath      string
 	adminConfigPath string
-	adminSessions   map[string]time.Time
+	adminCfgCache   adminConfigCache
+	adminSessions   map[string]adminSession
 	adminSessionsMu sync.Mutex
 	adminLoginMu    sync.Mutex
 	adminLoginNext  time.Time
 	requestShutdown func()
 	staticFiles     *fileServerWithFallback
+
+	// Signing key for admin sessions when session_secret_rotation_hours
+	// is set (see status_server_admin_session_key.go).
+	adminKeyMu       sync.Mutex
+	adminKey         adminSessionKey
+	adminKeyLoadedAt time.Time
 }
 
 type cachedJSONResponse struct {
@@ -111,6 +119,7 @@ func (s *StatusServer) Config() Config {
 func (s *StatusServer) UpdateConfig(cfg Config) {
 	s.cfg.Store(cfg)
 	s.storeStatusPublicURL(cfg.StatusPublicURL)
+	s.priceSvc.SetOffline(cfg.OfflineMode)
 	s.clearPageCache()
 }
 
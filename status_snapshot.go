@@ -9,6 +9,7 @@ import (
 	"io"
 	"math"
 	"net"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -432,6 +433,10 @@ func workerViewFromConn(mc *MinerConn, now time.Time) WorkerView {
 		estPingP50 = estimatedRTT
 		estPingP95 = estimatedRTT
 	}
+	var listeners []string
+	if mc.cfg.ShareListenerLabels && mc.listener != "" {
+		listeners = []string{mc.listener}
+	}
 	return WorkerView{
 		Name:                      name,
 		DisplayName:               displayName,
@@ -476,6 +481,7 @@ func workerViewFromConn(mc *MinerConn, now time.Time) WorkerView {
 		ConnectionSeq:             atomic.LoadUint64(&mc.connectionSeq),
 		ConnectedAt:               mc.connectedAt,
 		WalletValidated:           valid,
+		Listeners:                 listeners,
 	}
 }
 
@@ -571,6 +577,7 @@ func mergeWorkerViewsByHash(views []WorkerView) []WorkerView {
 		if w.ConnectionSeq > current.ConnectionSeq {
 			current.ConnectionSeq = w.ConnectionSeq
 		}
+		current.Listeners = mergeListenerLabels(current.Listeners, w.Listeners)
 		merged[key] = current
 	}
 	out := make([]WorkerView, 0, len(order))
@@ -580,6 +587,23 @@ func mergeWorkerViewsByHash(views []WorkerView) []WorkerView {
 	return out
 }
 
+// mergeListenerLabels returns the sorted union of two listener label lists
+// without modifying either.
+func mergeListenerLabels(a, b []string) []string {
+	if len(b) == 0 {
+		return a
+	}
+	out := make([]string, 0, len(a)+len(b))
+	out = append(out, a...)
+	for _, l := range b {
+		if !slices.Contains(out, l) {
+			out = append(out, l)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
 func (s *StatusServer) computePoolHashrate() float64 {
 	if s.metrics != nil {
 		return s.metrics.PoolHashrate()
@@ -776,6 +800,7 @@ func buildTemplateFuncs() template.FuncMap {
 			}
 			return t.UTC().Format("2006-01-02 15:04:05 UTC")
 		},
+		"formatTimeLocal": formatDisplayTime,
 		"addrPort": func(addr string) string {
 			if addr == "" {
 				return "—"
@@ -831,6 +856,10 @@ func loadTemplates() (*template.Template, error) {
 	return loadTemplatesFromAssets(assets)
 }
 
+// requiredStatusTemplates are the page templates handlers execute by name; a
+// reloaded set must define every one before it replaces the live set.
+var requiredStatusTemplates = []string{"layout", "overview", "worker_status", "error"}
+
 func loadTemplatesFromAssets(assets *uiAssetLoader) (*template.Template, error) {
 	funcs := buildTemplateFuncs()
 
@@ -914,7 +943,7 @@ func NewStatusServer(ctx context.Context, jobMgr *JobManager, metrics *PoolMetri
 		savedWorkerPeriods:  make(map[string]*savedWorkerPeriodRing),
 		configPath:          configPath,
 		adminConfigPath:     adminConfigPath,
-		adminSessions:       make(map[string]time.Time),
+		adminSessions:       make(map[string]adminSession),
 		requestShutdown:     shutdown,
 	}
 	server.UpdateConfig(cfg)
@@ -944,13 +973,25 @@ func (s *StatusServer) executeTemplate(w io.Writer, name string, data any) error
 
 // ReloadTemplates reloads the embedded HTML templates and clears cached pages.
 func (s *StatusServer) ReloadTemplates() error {
+	return s.reloadTemplatesWith(loadTemplates)
+}
+
+// reloadTemplatesWith parses a complete new template set and swaps it in only
+// on success; a parse error or a missing page template leaves the previous
+// set serving.
+func (s *StatusServer) reloadTemplatesWith(load func() (*template.Template, error)) error {
 	if s == nil {
 		return fmt.Errorf("status server is nil")
 	}
 
-	tmpl, err := loadTemplates()
+	tmpl, err := load()
 	if err != nil {
-		return err
+		return fmt.Errorf("%w (previous templates kept)", err)
+	}
+	for _, name := range requiredStatusTemplates {
+		if t := tmpl.Lookup(name); t == nil || t.Tree == nil {
+			return fmt.Errorf("template %q missing after reload (previous templates kept)", name)
+		}
 	}
 
 	// Atomically replace the template
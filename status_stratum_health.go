@@ -0,0 +1,94 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+// StratumHealthData is the /api/stratum-health payload: the same health
+// check enforceStratumFreshness uses to gate Stratum, plus the job feed state
+// behind it.
+type StratumHealthData struct {
+	APIVersion string `json:"api_version"`
+	Healthy    bool   `json:"healthy"`
+	Reason     string `json:"reason,omitempty"`
+	Detail     string `json:"detail,omitempty"`
+	// StartupGraceActive is true while the boot grace window suppresses
+	// gating; Healthy still reports the raw check.
+	StartupGraceActive bool `json:"startup_grace_active"`
+	// GraceSeconds is how long the check must stay unhealthy before miners
+	// are disconnected.
+	GraceSeconds                int64   `json:"grace_seconds"`
+	JobCreatedAt                string  `json:"job_created_at,omitempty"`
+	JobAgeSeconds               float64 `json:"job_age_seconds,omitempty"`
+	LastFeedSuccess             string  `json:"last_feed_success,omitempty"`
+	LastFeedError               string  `json:"last_feed_error,omitempty"`
+	LastFeedErrorAt             string  `json:"last_feed_error_at,omitempty"`
+	SafeguardDisconnectEvents   uint64  `json:"safeguard_disconnect_events"`
+	LastSafeguardDisconnectedAt string  `json:"last_safeguard_disconnect_at,omitempty"`
+}
+
+func (s *StatusServer) stratumHealthData(now time.Time) StratumHealthData {
+	h := stratumHealthStatus(s.jobMgr, now)
+	data := StratumHealthData{
+		APIVersion:         apiVersion,
+		Healthy:            h.Healthy,
+		Reason:             h.Reason,
+		Detail:             h.Detail,
+		StartupGraceActive: !s.start.IsZero() && now.Sub(s.start) < stratumStartupGrace,
+		GraceSeconds:       int64(stratumStaleJobGrace / time.Second),
+	}
+	if s.jobMgr != nil {
+		if job := s.jobMgr.CurrentJob(); job != nil && !job.CreatedAt.IsZero() {
+			data.JobCreatedAt = job.CreatedAt.UTC().Format(time.RFC3339)
+			data.JobAgeSeconds = now.Sub(job.CreatedAt).Seconds()
+		}
+		fs := s.jobMgr.FeedStatus()
+		if !fs.LastSuccess.IsZero() {
+			data.LastFeedSuccess = fs.LastSuccess.UTC().Format(time.RFC3339)
+		}
+		if fs.LastError != nil {
+			data.LastFeedError = strings.TrimSpace(fs.LastError.Error())
+		}
+		if !fs.LastErrorAt.IsZero() {
+			data.LastFeedErrorAt = fs.LastErrorAt.UTC().Format(time.RFC3339)
+		}
+	}
+	count, events := s.stratumSafeguardDisconnectSnapshot()
+	data.SafeguardDisconnectEvents = count
+	if len(events) > 0 {
+		data.LastSafeguardDisconnectedAt = events[len(events)-1].At
+	}
+	return data
+}
+
+// handleStratumHealthJSON serves /api/stratum-health for external health
+// checks. It answers 503 when the check fails outside the startup grace
+// window, matching when the pool itself starts gating miners.
+func (s *StatusServer) handleStratumHealthJSON(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	data := s.stratumHealthData(time.Now())
+	out, err := sonic.Marshal(data)
+	if err != nil {
+		logger.Error("stratum health json marshal", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if !data.Healthy && !data.StartupGraceActive {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if r.Method == http.MethodHead {
+		return
+	}
+	if _, err := w.Write(out); err != nil {
+		logger.Debug("stratum health json write", "error", err)
+	}
+}
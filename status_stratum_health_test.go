@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandleStratumHealthJSON(t *testing.T) {
+	now := time.Now()
+	jm := &JobManager{}
+	jm.mu.Lock()
+	jm.curJob = &Job{CreatedAt: now.Add(-(stratumStaleJobGrace + time.Minute))}
+	jm.mu.Unlock()
+	jm.recordJobError(errors.New("gbt timeout"))
+
+	s := &StatusServer{jobMgr: jm, start: now.Add(-time.Hour)}
+	s.recordStratumSafeguardDisconnectEvent(now, 3, "node/job feed error", "gbt timeout")
+
+	rec := httptest.NewRecorder()
+	s.handleStratumHealthJSON(rec, httptest.NewRequest(http.MethodGet, "/api/stratum-health", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status %d, want 503 for an unhealthy feed after startup grace", rec.Code)
+	}
+	var got StratumHealthData
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.Healthy || got.Reason != "node/job feed error" || got.LastFeedError != "gbt timeout" {
+		t.Fatalf("unexpected health payload: %+v", got)
+	}
+	if got.StartupGraceActive || got.SafeguardDisconnectEvents != 1 || got.JobAgeSeconds < stratumStaleJobGrace.Seconds() {
+		t.Fatalf("unexpected gating fields: %+v", got)
+	}
+
+	// Inside the startup grace the pool does not gate, so neither does the
+	// endpoint's status code.
+	s.start = now
+	rec = httptest.NewRecorder()
+	s.handleStratumHealthJSON(rec, httptest.NewRequest(http.MethodGet, "/api/stratum-health", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d during startup grace, want 200", rec.Code)
+	}
+	got = StratumHealthData{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !got.StartupGraceActive || got.Healthy {
+		t.Fatalf("grace payload should flag grace and keep the raw check: %+v", got)
+	}
+}
@@ -247,16 +247,6 @@ func loadFoundBlocks(dataDir string, limit int) []FoundBlockView {
 		return nil
 	}
 
-	type foundRecord struct {
-		Timestamp        time.Time `json:"timestamp"`
-		Height           int64     `json:"height"`
-		Hash             string    `json:"hash"`
-		Worker           string    `json:"worker"`
-		ShareDiff        float64   `json:"share_diff"`
-		PoolFeeSats      int64     `json:"pool_fee_sats"`
-		WorkerPayoutSats int64     `json:"worker_payout_sats"`
-	}
-
 	var recs []FoundBlockView
 	q := "SELECT json FROM found_blocks_log ORDER BY id DESC"
 	args := []any{}
@@ -275,28 +265,9 @@ func loadFoundBlocks(dataDir string, limit int) []FoundBlockView {
 		if err := rows.Scan(&line); err != nil {
 			continue
 		}
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-		var r foundRecord
-		if err := sonic.Unmarshal([]byte(line), &r); err != nil {
-			continue
+		if view, ok := parseFoundBlockLogLine(line); ok {
+			recs = append(recs, view)
 		}
-		if strings.EqualFold(strings.TrimSpace(r.Hash), "dummyhash") {
-			continue
-		}
-		recs = append(recs, FoundBlockView{
-			Height:           r.Height,
-			Hash:             r.Hash,
-			DisplayHash:      shortDisplayID(r.Hash, hashPrefix, hashSuffix),
-			Worker:           r.Worker,
-			DisplayWorker:    shortWorkerName(r.Worker, 12, 6),
-			Timestamp:        r.Timestamp,
-			ShareDiff:        r.ShareDiff,
-			PoolFeeSats:      r.PoolFeeSats,
-			WorkerPayoutSats: r.WorkerPayoutSats,
-		})
 	}
 	if err := rows.Err(); err != nil {
 		return nil
@@ -310,6 +281,44 @@ func loadFoundBlocks(dataDir string, limit int) []FoundBlockView {
 	return recs
 }
 
+// parseFoundBlockLogLine decodes one found_blocks_log JSON record for
+// display. Blank, malformed and placeholder ("dummyhash") records are skipped,
+// as are also-found records for blocks that were never submitted.
+func parseFoundBlockLogLine(line string) (FoundBlockView, bool) {
+	type foundRecord struct {
+		Timestamp        time.Time `json:"timestamp"`
+		Height           int64     `json:"height"`
+		Hash             string    `json:"hash"`
+		Worker           string    `json:"worker"`
+		ShareDiff        float64   `json:"share_diff"`
+		PoolFeeSats      int64     `json:"pool_fee_sats"`
+		WorkerPayoutSats int64     `json:"worker_payout_sats"`
+		AlsoFound        bool      `json:"also_found"`
+	}
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return FoundBlockView{}, false
+	}
+	var r foundRecord
+	if err := sonic.Unmarshal([]byte(line), &r); err != nil {
+		return FoundBlockView{}, false
+	}
+	if r.AlsoFound || strings.EqualFold(strings.TrimSpace(r.Hash), "dummyhash") {
+		return FoundBlockView{}, false
+	}
+	return FoundBlockView{
+		Height:           r.Height,
+		Hash:             r.Hash,
+		DisplayHash:      shortDisplayID(r.Hash, hashPrefix, hashSuffix),
+		Worker:           r.Worker,
+		DisplayWorker:    shortWorkerName(r.Worker, 12, 6),
+		Timestamp:        r.Timestamp,
+		ShareDiff:        r.ShareDiff,
+		PoolFeeSats:      r.PoolFeeSats,
+		WorkerPayoutSats: r.WorkerPayoutSats,
+	}, true
+}
+
 // readProcessRSS returns the current process resident set size (RSS) in bytes.
 // It parses /proc/self/statm, which is Linux-specific; on failure it returns 0.
 func readProcessRSS() uint64 {
@@ -1,6 +1,9 @@
 package main
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestFormatDiff_SmallValues(t *testing.T) {
 	f := buildTemplateFuncs()["formatDiff"].(func(float64) string)
@@ -52,3 +55,29 @@ func TestFormatDiffDetail_TrimsFixedDecimals(t *testing.T) {
 		}
 	}
 }
+
+func TestFormatTimeLocal_UsesDisplayTimezoneAcrossDST(t *testing.T) {
+	defer setDisplayTimezone("")
+	f := buildTemplateFuncs()["formatTimeLocal"].(func(time.Time) string)
+
+	setDisplayTimezone("America/New_York")
+	winter := time.Date(2026, time.January, 15, 17, 0, 0, 0, time.UTC)
+	summer := time.Date(2026, time.July, 15, 17, 0, 0, 0, time.UTC)
+	if got, want := f(winter), "2026-01-15 12:00:00 EST"; got != want {
+		t.Fatalf("winter = %q, want %q", got, want)
+	}
+	if got, want := f(summer), "2026-07-15 13:00:00 EDT"; got != want {
+		t.Fatalf("summer = %q, want %q", got, want)
+	}
+
+	setDisplayTimezone("")
+	if got, want := f(winter), "2026-01-15 17:00:00 UTC"; got != want {
+		t.Fatalf("default = %q, want %q", got, want)
+	}
+	if got := f(time.Time{}); got != "—" {
+		t.Fatalf("zero time = %q", got)
+	}
+	if _, err := loadDisplayTimezone("Not/AZone"); err == nil {
+		t.Fatalf("expected error for unknown timezone")
+	}
+}
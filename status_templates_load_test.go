@@ -1,8 +1,10 @@
 package main
 
 import (
+	"html/template"
 	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 func TestLoadTemplates_Parse(t *testing.T) {
@@ -46,3 +48,23 @@ func TestOverviewLiveStatusScriptHasLocalDifficultyFormatter(t *testing.T) {
 		t.Fatalf("live status formatter is missing large difficulty units")
 	}
 }
+
+func TestReloadTemplatesKeepsPreviousSetOnParseError(t *testing.T) {
+	t.Parallel()
+
+	good, err := loadTemplates()
+	if err != nil {
+		t.Fatalf("loadTemplates error: %v", err)
+	}
+	s := &StatusServer{tmpl: good}
+	broken := &uiAssetLoader{templates: fstest.MapFS{
+		"layout.tmpl": &fstest.MapFile{Data: []byte(`{{define "broken"}}{{if}}`)},
+	}}
+	err = s.reloadTemplatesWith(func() (*template.Template, error) { return loadTemplatesFromAssets(broken) })
+	if err == nil || !strings.Contains(err.Error(), "previous templates kept") {
+		t.Fatalf("expected reload failure keeping previous templates, got %v", err)
+	}
+	if s.tmpl != good {
+		t.Fatalf("live templates replaced by a failed reload")
+	}
+}
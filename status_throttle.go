@@ -0,0 +1,108 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// statusThrottleLogInterval limits the "status requests throttled" warning
+// during a sustained flood.
+const statusThrottleLogInterval = time.Minute
+
+// statusRequestThrottle caps the status HTTP server's request rate and
+// concurrency ([rate_limits] status_requests_per_second and
+// status_max_inflight_requests) so a flood of page/API requests cannot take
+// CPU away from Stratum. It is separate from the Stratum accept limiter.
+type statusRequestThrottle struct {
+	rate        *acceptRateLimiter
+	maxInflight int64
+	inflight    atomic.Int64
+	rejected    atomic.Uint64
+	lastLog     atomic.Int64 // unix nanos of the last throttle warning
+}
+
+// newStatusRequestThrottle returns nil when both limits are disabled. The
+// rate limiter allows a burst of twice the per-second rate so a normal page
+// load (HTML plus its API calls) is never cut short.
+func newStatusRequestThrottle(perSecond, maxInflight int) *statusRequestThrottle {
+	if perSecond <= 0 && maxInflight <= 0 {
+		return nil
+	}
+	t := &statusRequestThrottle{maxInflight: int64(max(maxInflight, 0))}
+	if perSecond > 0 {
+		t.rate = newAcceptRateLimiter(perSecond, 2*perSecond)
+	}
+	return t
+}
+
+// statusThrottleExempt lists paths that bypass the throttle so operators and
+// monitoring keep access during a flood: admin pages, the plain-text health
+// and metrics endpoints and the login flows that lead to admin.
+func statusThrottleExempt(p string) bool {
+	switch {
+	case p == "/admin" || strings.HasPrefix(p, "/admin/"),
+		p == "/status.txt", p == "/metrics",
+		p == "/login", p == "/logout", p == "/sign-in":
+		return true
+	}
+	return false
+}
+
+// acquire reports whether a request may run now; on success the caller must
+// call release when done.
+func (t *statusRequestThrottle) acquire(now time.Time) bool {
+	if t.maxInflight > 0 && t.inflight.Add(1) > t.maxInflight {
+		t.inflight.Add(-1)
+		return false
+	}
+	if !t.rate.allow(now) {
+		if t.maxInflight > 0 {
+			t.inflight.Add(-1)
+		}
+		return false
+	}
+	return true
+}
+
+func (t *statusRequestThrottle) release() {
+	if t.maxInflight > 0 {
+		t.inflight.Add(-1)
+	}
+}
+
+func (t *statusRequestThrottle) noteRejected(now time.Time) {
+	total := t.rejected.Add(1)
+	last := t.lastLog.Load()
+	if now.UnixNano()-last < int64(statusThrottleLogInterval) || !t.lastLog.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+	logger.Warn("status requests throttled", "component", "http", "kind", "throttle",
+		"rejected_total", total,
+		"inflight", t.inflight.Load(),
+	)
+}
+
+// withStatusRequestThrottle answers 429 Too Many Requests (with Retry-After)
+// for non-exempt requests beyond the configured limits.
+func withStatusRequestThrottle(next http.Handler, t *statusRequestThrottle) http.Handler {
+	if t == nil || next == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if statusThrottleExempt(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		now := time.Now()
+		if !t.acquire(now) {
+			t.noteRejected(now)
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "Too many requests; try again shortly.", http.StatusTooManyRequests)
+			return
+		}
+		defer t.release()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStatusRequestThrottleRate(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := withStatusRequestThrottle(ok, newStatusRequestThrottle(1, 0))
+
+	codes := make([]int, 0, 3)
+	for range 3 {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/overview", nil))
+		codes = append(codes, rr.Code)
+	}
+	// Burst is twice the per-second rate.
+	if codes[0] != http.StatusOK || codes[1] != http.StatusOK || codes[2] != http.StatusTooManyRequests {
+		t.Fatalf("unexpected status codes %v", codes)
+	}
+
+	for _, path := range []string{"/admin", "/admin/miners", "/status.txt"} {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s should be exempt from throttling, got %d", path, rr.Code)
+		}
+	}
+}
+
+func TestStatusRequestThrottleInflight(t *testing.T) {
+	var nested *httptest.ResponseRecorder
+	var h http.Handler
+	h = withStatusRequestThrottle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/outer" {
+			return
+		}
+		// A second request while the first is still running exceeds the cap.
+		nested = httptest.NewRecorder()
+		h.ServeHTTP(nested, httptest.NewRequest(http.MethodGet, "/inner", nil))
+	}), newStatusRequestThrottle(0, 1))
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/outer", nil))
+	if rr.Code != http.StatusOK || nested == nil || nested.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected outer 200 and inner 429, got %d and %v", rr.Code, nested)
+	}
+	if nested.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected Retry-After on 429")
+	}
+
+	// The slot is released once the outer request finishes.
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/inner", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected request after release to pass, got %d", rr.Code)
+	}
+	if newStatusRequestThrottle(0, 0) != nil {
+		t.Fatalf("expected nil throttle when both limits are disabled")
+	}
+}
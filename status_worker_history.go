@@ -0,0 +1,181 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+// workerHistoryMaxWindow bounds the window= parameter of /api/worker-history.
+// The minute ring only retains savedWorkerPeriodHistoryWindow, so longer
+// windows simply return what is retained.
+const workerHistoryMaxWindow = 7 * 24 * time.Hour
+
+// workerHistoryPoint is one downsampled bucket. Shares are estimated
+// difficulty-1 share equivalents (hashrate * seconds / hashPerShare); the
+// minute ring stores hashrate only, not share counts.
+type workerHistoryPoint struct {
+	T        int64   `json:"t"`
+	Hashrate float64 `json:"hashrate"`
+	Shares   float64 `json:"shares"`
+}
+
+func parseWorkerHistoryBucket(raw string) (time.Duration, bool) {
+	switch strings.TrimSpace(raw) {
+	case "", "60":
+		return time.Minute, true
+	case "300":
+		return 5 * time.Minute, true
+	case "3600":
+		return time.Hour, true
+	}
+	return 0, false
+}
+
+func parseWorkerHistoryWindow(raw string, bucket time.Duration) (time.Duration, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return savedWorkerPeriodHistoryWindow, true
+	}
+	secs, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || secs <= 0 {
+		return 0, false
+	}
+	window := workerHistoryMaxWindow
+	if secs < int64(workerHistoryMaxWindow/time.Second) {
+		window = time.Duration(secs) * time.Second
+	}
+	if window < bucket {
+		window = bucket
+	}
+	return window, true
+}
+
+// workerHistoryHashes resolves the worker=, hash= or wallet= parameter to the
+// minute-ring keys to sum. A wallet covers every saved worker named after it
+// ("wallet" or "wallet.<rig>").
+func (s *StatusServer) workerHistoryHashes(r *http.Request) ([]string, int, string) {
+	q := r.URL.Query()
+	if name := strings.TrimSpace(q.Get("worker")); name != "" {
+		return []string{workerNameHash(name)}, 0, ""
+	}
+	if rawHash := strings.TrimSpace(q.Get("hash")); rawHash != "" {
+		if strings.EqualFold(rawHash, savedWorkerPeriodPoolKey) {
+			return []string{savedWorkerPeriodPoolKey}, 0, ""
+		}
+		hash, errMsg := parseSHA256HexStrict(rawHash)
+		if errMsg != "" || hash == "" {
+			return nil, http.StatusBadRequest, "invalid hash"
+		}
+		return []string{hash}, 0, ""
+	}
+	wallet := strings.TrimSpace(q.Get("wallet"))
+	if wallet == "" {
+		return nil, http.StatusBadRequest, "worker, hash or wallet is required"
+	}
+	if s.workerLists == nil {
+		return nil, http.StatusBadRequest, "saved workers not enabled"
+	}
+	saved, err := s.workerLists.ListAllSavedWorkers()
+	if err != nil {
+		logger.Warn("worker history list saved workers", "error", err)
+		return nil, http.StatusInternalServerError, "failed to load saved workers"
+	}
+	seen := make(map[string]struct{})
+	var hashes []string
+	for _, rec := range saved {
+		if rec.Name != wallet && !strings.HasPrefix(rec.Name, wallet+".") {
+			continue
+		}
+		if _, ok := seen[rec.Hash]; ok {
+			continue
+		}
+		seen[rec.Hash] = struct{}{}
+		hashes = append(hashes, rec.Hash)
+	}
+	return hashes, 0, ""
+}
+
+// workerHashrateHistory sums the minute samples of hashes over window ending
+// at now and averages them into bucket-aligned points, oldest first. Buckets
+// without any recorded minute are omitted.
+func (s *StatusServer) workerHashrateHistory(hashes []string, now time.Time, bucket, window time.Duration) []workerHistoryPoint {
+	start := now.UTC().Add(-window)
+	perMinute := make(map[int64]float64)
+	for _, hash := range hashes {
+		for _, sample := range s.savedWorkerPeriodHistory(hash, now) {
+			if sample.At.IsZero() || sample.At.Before(start) {
+				continue
+			}
+			perMinute[sample.At.Unix()] += decodeHashrateSI16(sample.HashrateQ)
+		}
+	}
+
+	type acc struct {
+		sum     float64
+		minutes int
+	}
+	buckets := make(map[int64]*acc)
+	for at, hashrate := range perMinute {
+		t := time.Unix(at, 0).UTC().Truncate(bucket).Unix()
+		b := buckets[t]
+		if b == nil {
+			b = &acc{}
+			buckets[t] = b
+		}
+		b.sum += hashrate
+		b.minutes++
+	}
+
+	out := make([]workerHistoryPoint, 0, len(buckets))
+	for t, b := range buckets {
+		out = append(out, workerHistoryPoint{
+			T:        t,
+			Hashrate: b.sum / float64(b.minutes),
+			Shares:   b.sum * savedWorkerPeriodBucket.Seconds() / hashPerShare,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].T < out[j].T })
+	return out
+}
+
+// handleWorkerHashrateHistoryJSON serves /api/worker-history: bucketed
+// hashrate for a worker, worker hash or wallet from the saved-worker minute
+// history, as [{t, hashrate, shares}] for charting.
+func (s *StatusServer) handleWorkerHashrateHistoryJSON(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	bucket, ok := parseWorkerHistoryBucket(r.URL.Query().Get("bucket"))
+	if !ok {
+		http.Error(w, "bucket must be 60, 300 or 3600", http.StatusBadRequest)
+		return
+	}
+	window, ok := parseWorkerHistoryWindow(r.URL.Query().Get("window"), bucket)
+	if !ok {
+		http.Error(w, "invalid window", http.StatusBadRequest)
+		return
+	}
+	hashes, status, errMsg := s.workerHistoryHashes(r)
+	if errMsg != "" {
+		http.Error(w, errMsg, status)
+		return
+	}
+
+	points := s.workerHashrateHistory(hashes, time.Now(), bucket, window)
+	setShortJSONCacheHeaders(w, false)
+	out, err := sonic.Marshal(points)
+	if err != nil {
+		logger.Error("worker history json marshal", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if _, err := w.Write(out); err != nil {
+		logger.Debug("worker history json write", "error", err)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWorkerHashrateHistoryDownsamples(t *testing.T) {
+	now := time.Unix(1700000000, 0).UTC().Truncate(time.Hour).Add(10 * time.Minute)
+	nowMinute := savedWorkerUnixMinute(now)
+	hash := workerNameHash("bc1qexample.rig1")
+	code := encodeHashrateSI16(1e12)
+	want := decodeHashrateSI16(code)
+
+	s := &StatusServer{savedWorkerPeriods: make(map[string]*savedWorkerPeriodRing)}
+	ring := &savedWorkerPeriodRing{}
+	for m := nowMinute - 9; m <= nowMinute; m++ {
+		idx := savedWorkerRingIndex(m)
+		ring.minutes[idx] = m
+		ring.hashrateQ[idx] = code
+		ring.lastMinute = m
+	}
+	s.savedWorkerPeriods[hash] = ring
+
+	points := s.workerHashrateHistory([]string{hash}, now, 5*time.Minute, time.Hour)
+	if len(points) != 3 {
+		t.Fatalf("points = %+v, want 3 five-minute buckets", points)
+	}
+	for i, p := range points {
+		if p.T%300 != 0 || (i > 0 && p.T <= points[i-1].T) {
+			t.Fatalf("bucket times not aligned/ordered: %+v", points)
+		}
+		if math.Abs(p.Hashrate-want) > want*1e-9 {
+			t.Fatalf("bucket %d hashrate = %v, want %v", i, p.Hashrate, want)
+		}
+	}
+	wantShares := 5 * want * 60 / hashPerShare
+	if math.Abs(points[1].Shares-wantShares) > wantShares*1e-9 {
+		t.Fatalf("full bucket shares = %v, want %v", points[1].Shares, wantShares)
+	}
+
+	if got := s.workerHashrateHistory([]string{hash}, now, time.Minute, 3*time.Minute); len(got) != 4 {
+		t.Fatalf("3m window returned %d minute points, want 4", len(got))
+	}
+}
+
+func TestWorkerHistoryParams(t *testing.T) {
+	if _, ok := parseWorkerHistoryBucket("120"); ok {
+		t.Fatalf("expected bucket=120 to be rejected")
+	}
+	if w, ok := parseWorkerHistoryWindow("99999999", time.Hour); !ok || w != workerHistoryMaxWindow {
+		t.Fatalf("window clamp = %v, %v", w, ok)
+	}
+	if w, ok := parseWorkerHistoryWindow("10", time.Hour); !ok || w != time.Hour {
+		t.Fatalf("window below bucket = %v, %v", w, ok)
+	}
+
+	s := &StatusServer{savedWorkerPeriods: make(map[string]*savedWorkerPeriodRing)}
+	rec := httptest.NewRecorder()
+	s.handleWorkerHashrateHistoryJSON(rec, httptest.NewRequest(http.MethodGet, "/api/worker-history?worker=nobody&bucket=300", nil))
+	var points []workerHistoryPoint
+	if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &points) != nil || len(points) != 0 {
+		t.Fatalf("unknown worker = %d %q, want empty array", rec.Code, rec.Body.String())
+	}
+	rec = httptest.NewRecorder()
+	s.handleWorkerHashrateHistoryJSON(rec, httptest.NewRequest(http.MethodGet, "/api/worker-history", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("missing selector status = %d, want 400", rec.Code)
+	}
+}
@@ -18,6 +18,9 @@ type WorkerStatusData struct {
 	CurrentJobHeight   int64
 	CurrentJobPrevHash string
 	CurrentJobCoinbase *ShareDetail
+	// ConnectionHistory is the worker's reconnect summary; nil when
+	// worker_connection_history_seconds is 0 or nothing is recorded.
+	ConnectionHistory *WorkerConnectionHistory
 	// Hex-encoded scriptPubKey for pool payout, donation, and worker wallet so the
 	// UI can label coinbase outputs without re-parsing addresses.
 	PoolScriptHex     string
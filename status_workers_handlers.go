@@ -343,6 +343,7 @@ func (s *StatusServer) handleWorkerStatusBySHA256(w http.ResponseWriter, r *http
 				if wv, ok := s.findWorkerViewByHash(workerHash); ok {
 					setWorkerStatusView(&data, wv)
 					s.setWorkerCurrentJobCoinbase(&data, curJob, wv)
+					s.setWorkerConnectionHistory(&data, workerHash, now)
 					if data.BTCPriceFiat > 0 {
 						cur := strings.ToUpper(strings.TrimSpace(data.FiatCurrency))
 						if cur == "" {
@@ -358,6 +359,7 @@ func (s *StatusServer) handleWorkerStatusBySHA256(w http.ResponseWriter, r *http
 					if wv, ok := s.accounting.WorkerViewBySHA256(workerHash); ok {
 						setWorkerStatusView(&data, wv)
 						s.setWorkerCurrentJobCoinbase(&data, curJob, wv)
+						s.setWorkerConnectionHistory(&data, workerHash, now)
 						if data.BTCPriceFiat > 0 {
 							cur := strings.ToUpper(strings.TrimSpace(data.FiatCurrency))
 							if cur == "" {
@@ -134,6 +134,9 @@ func (s *StatusServer) refreshLiveSavedWorkerTrackingByHash(hash string) {
 			continue
 		}
 		mc.syncSavedWorkerState(hash)
+		if cfg := s.Config(); cfg.SavedWorkerStaticDifficulty && mc.applyDifficultyOverride(cfg, mc.currentWorker()) {
+			mc.maybeSendCleanJobAfterSuggest()
+		}
 	}
 }
 
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -769,7 +770,15 @@ func (s *StatusServer) handleWorkerSave(w http.ResponseWriter, r *http.Request)
 		if err := s.workerLists.Add(user.UserID, worker); err != nil {
 			logger.Warn("save worker name", "error", err, "user_id", user.UserID)
 		} else {
-			s.refreshLiveSavedWorkerTrackingByHash(workerNameHash(worker))
+			hash := workerNameHash(worker)
+			if raw := strings.TrimSpace(r.FormValue("difficulty")); raw != "" && s.Config().SavedWorkerStaticDifficulty {
+				if diff, err := strconv.ParseFloat(raw, 64); err == nil && validSavedWorkerStaticDifficulty(diff) {
+					if _, err := s.workerLists.SetSavedWorkerStaticDifficulty(user.UserID, hash, diff); err != nil {
+						logger.Warn("save worker difficulty", "error", err, "user_id", user.UserID)
+					}
+				}
+			}
+			s.refreshLiveSavedWorkerTrackingByHash(hash)
 		}
 	}
 	http.Redirect(w, r, "/saved-workers", http.StatusSeeOther)
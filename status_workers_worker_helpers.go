@@ -21,6 +21,15 @@ func (s *StatusServer) setWorkerCurrentJobCoinbase(data *WorkerStatusData, job *
 	}
 }
 
+// setWorkerConnectionHistory attaches the worker's reconnect summary from the
+// connection registry when worker_connection_history_seconds is enabled.
+func (s *StatusServer) setWorkerConnectionHistory(data *WorkerStatusData, hash string, now time.Time) {
+	if s == nil || data == nil || s.workerRegistry == nil {
+		return
+	}
+	data.ConnectionHistory = s.workerRegistry.connectionHistory(hash, s.Config().WorkerConnHistoryWindow, now)
+}
+
 func (s *StatusServer) connectionForWorkerView(wv WorkerView) *MinerConn {
 	if s == nil || s.workerRegistry == nil {
 		return nil
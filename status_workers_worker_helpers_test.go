@@ -39,6 +39,7 @@ func TestBuildCurrentJobCoinbaseDetail_UsesExactSinglePayoutPath(t *testing.T) {
 		workerScript,
 		job.CoinbaseValue,
 		job.WitnessCommitment,
+		job.extraOpReturnScript,
 		job.Template.CoinbaseAux.Flags,
 		job.CoinbaseMsg,
 		2000,
@@ -67,6 +68,7 @@ func TestBuildCurrentJobCoinbaseDetail_UsesExactSinglePayoutPath(t *testing.T) {
 		workerScript,
 		job.CoinbaseValue,
 		job.WitnessCommitment,
+		job.extraOpReturnScript,
 		job.Template.CoinbaseAux.Flags,
 		job.CoinbaseMsg,
 		2000,
@@ -60,5 +60,9 @@ func stratumHealthStatus(jobMgr *JobManager, now time.Time) stratumHealth {
 		return stratumHealth{Healthy: false, Reason: "node syncing/indexing", Detail: detail}
 	}
 
+	if inactive, gate, netFetchedAt := jobMgr.nodeNetworkSnapshot(); inactive && gate && stratumNodeSyncSnapshotFresh(now, netFetchedAt) {
+		return stratumHealth{Healthy: false, Reason: "node network inactive", Detail: "getnetworkinfo reports networkactive=false"}
+	}
+
 	return stratumHealth{Healthy: true}
 }
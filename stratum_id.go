@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+)
+
+// Handling for requests whose JSON-RPC id is missing or not a string/number
+// ([stratum].bad_id_policy in policy.toml).
+const (
+	// stratumBadIDPolicyCompat handles the request and replies with id null
+	// (the historical behavior; some old miners omit the id).
+	stratumBadIDPolicyCompat = "compat"
+	// stratumBadIDPolicyIgnore drops the request without handling or replying.
+	stratumBadIDPolicyIgnore = "ignore"
+	// stratumBadIDPolicyReject replies with an invalid-request error (id null)
+	// and does not handle the request.
+	stratumBadIDPolicyReject = "reject"
+)
+
+type stratumIDKind uint8
+
+const (
+	stratumIDValid   stratumIDKind = iota // string or number
+	stratumIDNull                         // explicit null: a notification
+	stratumIDMissing                      // no id member
+	stratumIDInvalid                      // bool, object or array
+)
+
+func (k stratumIDKind) String() string {
+	switch k {
+	case stratumIDValid:
+		return "valid"
+	case stratumIDNull:
+		return "null"
+	case stratumIDMissing:
+		return "missing"
+	default:
+		return "invalid"
+	}
+}
+
+// classifyStratumID reports what kind of id the raw request line carried.
+// id is the decoded value, which cannot tell a missing id from null.
+func classifyStratumID(line []byte, id any) stratumIDKind {
+	switch id.(type) {
+	case string, float64, json.Number, int, int64, uint64:
+		return stratumIDValid
+	case nil:
+		if _, ok := findTopLevelObjectKeyValueStart(line, stratumKeyIDBytes); ok {
+			return stratumIDNull
+		}
+		return stratumIDMissing
+	default:
+		return stratumIDInvalid
+	}
+}
+
+// stratumNotificationID replaces the id of a request sent with id null.
+// Such requests are notifications: the handler still runs, but
+// writeResponse drops any reply carrying this id.
+type stratumNotificationID struct{}
+
+func (stratumNotificationID) MarshalJSON() ([]byte, error) {
+	return []byte("null"), nil
+}
+
+func isStratumNotificationID(id any) bool {
+	_, ok := id.(stratumNotificationID)
+	return ok
+}
+
+// admitStratumRequestID applies the id rules to a decoded request before it
+// is dispatched. It returns false when the request must not be handled.
+func (mc *MinerConn) admitStratumRequestID(line []byte, req *StratumRequest) bool {
+	kind := classifyStratumID(line, req.ID)
+	switch kind {
+	case stratumIDValid:
+		return true
+	case stratumIDNull:
+		req.ID = stratumNotificationID{}
+		return true
+	}
+	if kind == stratumIDInvalid {
+		// Never echo an id the client could not legally have sent.
+		req.ID = nil
+	}
+	switch mc.cfg.StratumBadIDPolicy {
+	case stratumBadIDPolicyIgnore:
+		if debugLogging {
+			logger.Debug("ignoring stratum request with bad id", "component", "miner", "kind", "protocol", "remote", mc.id, "method", req.Method, "id_kind", kind.String())
+		}
+		return false
+	case stratumBadIDPolicyReject:
+		mc.writeResponse(StratumResponse{
+			ID:     nil,
+			Result: nil,
+			Error:  newStratumError(stratumErrCodeJSONRPCInvalidRequest, "invalid request id"),
+		})
+		if debugLogging {
+			logger.Debug("rejected stratum request with bad id", "component", "miner", "kind", "protocol", "remote", mc.id, "method", req.Method, "id_kind", kind.String())
+		}
+		return false
+	default:
+		return true
+	}
+}
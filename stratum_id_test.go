@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestClassifyStratumID(t *testing.T) {
+	cases := []struct {
+		line string
+		want stratumIDKind
+	}{
+		{`{"id":1,"method":"mining.ping","params":[]}`, stratumIDValid},
+		{`{"id":"abc","method":"mining.ping","params":[]}`, stratumIDValid},
+		{`{"id":null,"method":"mining.ping","params":[]}`, stratumIDNull},
+		{`{"method":"mining.ping","params":[]}`, stratumIDMissing},
+		{`{"method":"mining.ping","params":[{"id":null}]}`, stratumIDMissing},
+		{`{"id":true,"method":"mining.ping","params":[]}`, stratumIDInvalid},
+		{`{"id":{"a":1},"method":"mining.ping","params":[]}`, stratumIDInvalid},
+		{`{"id":[1],"method":"mining.ping","params":[]}`, stratumIDInvalid},
+	}
+	for _, tc := range cases {
+		var req StratumRequest
+		if err := fastJSONUnmarshal([]byte(tc.line), &req); err != nil {
+			t.Fatalf("unmarshal %s: %v", tc.line, err)
+		}
+		if got := classifyStratumID([]byte(tc.line), req.ID); got != tc.want {
+			t.Fatalf("%s: got %v, want %v", tc.line, got, tc.want)
+		}
+	}
+}
+
+// runStratumIDConn feeds lines to a miner connection and returns the raw
+// response lines received before the read deadline.
+func runStratumIDConn(t *testing.T, cfg Config, lines ...string) []string {
+	t.Helper()
+	server, client := net.Pipe()
+	defer client.Close()
+	cfg.ConnectionTimeout = time.Hour
+	mc := &MinerConn{
+		id:           "test",
+		ctx:          context.Background(),
+		conn:         server,
+		reader:       bufio.NewReader(server),
+		cfg:          cfg,
+		lastActivity: time.Now(),
+	}
+	done := make(chan struct{})
+	go func() {
+		mc.handle()
+		close(done)
+	}()
+
+	var out []string
+	br := bufio.NewReader(client)
+	for _, line := range lines {
+		if _, err := io.WriteString(client, line+"\n"); err != nil {
+			t.Fatalf("write request: %v", err)
+		}
+		_ = client.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+		resp, err := br.ReadString('\n')
+		if err == nil {
+			out = append(out, resp)
+		}
+	}
+	_ = client.Close()
+	_ = server.Close()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("miner conn did not exit")
+	}
+	return out
+}
+
+func TestStratumIDEchoAndNotifications(t *testing.T) {
+	resps := runStratumIDConn(t, Config{},
+		`{"id":"req-é 7","method":"mining.ping","params":[]}`,
+		`{"id":42,"method":"mining.ping","params":[]}`,
+		`{"id":null,"method":"mining.ping","params":[]}`,
+		`{"id":null,"method":"client.get_version","params":[]}`,
+	)
+	if len(resps) != 2 {
+		t.Fatalf("expected 2 responses (null ids are notifications), got %d: %q", len(resps), resps)
+	}
+	var first, second map[string]any
+	if err := json.Unmarshal([]byte(resps[0]), &first); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if err := json.Unmarshal([]byte(resps[1]), &second); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if first["id"] != "req-é 7" {
+		t.Fatalf("string id not echoed exactly: %#v", first["id"])
+	}
+	if second["id"] != float64(42) {
+		t.Fatalf("numeric id not echoed: %#v", second["id"])
+	}
+}
+
+func TestStratumBadIDPolicies(t *testing.T) {
+	missing := `{"method":"mining.ping","params":[]}`
+	invalid := `{"id":true,"method":"mining.ping","params":[]}`
+
+	resps := runStratumIDConn(t, Config{StratumBadIDPolicy: stratumBadIDPolicyCompat}, missing, invalid)
+	if len(resps) != 2 {
+		t.Fatalf("compat: expected 2 responses, got %q", resps)
+	}
+	for _, r := range resps {
+		var m map[string]any
+		if err := json.Unmarshal([]byte(r), &m); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if m["id"] != nil || m["result"] != "pong" {
+			t.Fatalf("compat: expected pong with id null, got %q", r)
+		}
+	}
+
+	if resps := runStratumIDConn(t, Config{StratumBadIDPolicy: stratumBadIDPolicyIgnore}, missing, invalid); len(resps) != 0 {
+		t.Fatalf("ignore: expected no responses, got %q", resps)
+	}
+
+	resps = runStratumIDConn(t, Config{StratumBadIDPolicy: stratumBadIDPolicyReject}, invalid)
+	if len(resps) != 1 {
+		t.Fatalf("reject: expected 1 response, got %q", resps)
+	}
+	var m map[string]any
+	if err := json.Unmarshal([]byte(resps[0]), &m); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	errVal, ok := m["error"].([]any)
+	if m["id"] != nil || m["result"] != nil || !ok || len(errVal) < 1 || errVal[0] != float64(stratumErrCodeJSONRPCInvalidRequest) {
+		t.Fatalf("reject: expected -32600 error with id null, got %q", resps[0])
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// stratumReadyFileName is written to the data dir once the stratum listeners
+// are accepting, so a restart script can wait for the new process before
+// stopping the old one.
+const stratumReadyFileName = "stratum.ready"
+
+// listenStratum opens a stratum TCP listener, optionally with SO_REUSEPORT
+// (stratum_reuse_port) so a replacement process can bind the same address
+// while this one is still running.
+func listenStratum(ctx context.Context, addr string, reusePort bool) (net.Listener, error) {
+	var lc net.ListenConfig
+	if reusePort {
+		if !reusePortSupported {
+			logger.Warn("stratum_reuse_port is not supported on this platform; listening without it", "component", "stratum", "kind", "listen", "addr", addr)
+		} else {
+			lc.Control = reusePortControl
+		}
+	}
+	return lc.Listen(ctx, "tcp", addr)
+}
+
+func stratumReadyFilePath(dataDir string) string {
+	if dataDir == "" {
+		dataDir = defaultDataDir
+	}
+	return filepath.Join(dataDir, stratumReadyFileName)
+}
+
+// writeStratumReadyFile records this process's PID as the one currently
+// accepting stratum connections.
+func writeStratumReadyFile(dataDir string) error {
+	path := stratumReadyFilePath(dataDir)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.Itoa(os.Getpid())+"\n"), 0o644); err != nil {
+		return fmt.Errorf("write ready file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("rename ready file: %w", err)
+	}
+	return nil
+}
+
+// removeStratumReadyFile deletes the ready file on shutdown, unless a
+// replacement process has already overwritten it with its own PID.
+func removeStratumReadyFile(dataDir string) {
+	path := stratumReadyFilePath(dataDir)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	if strings.TrimSpace(string(data)) != strconv.Itoa(os.Getpid()) {
+		return
+	}
+	_ = os.Remove(path)
+}
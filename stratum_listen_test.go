@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestListenStratumReusePort(t *testing.T) {
+	if !reusePortSupported {
+		t.Skip("SO_REUSEPORT not supported on this platform")
+	}
+	ctx := context.Background()
+	first, err := listenStratum(ctx, "127.0.0.1:0", true)
+	if err != nil {
+		t.Fatalf("first listen: %v", err)
+	}
+	defer first.Close()
+
+	second, err := listenStratum(ctx, first.Addr().String(), true)
+	if err != nil {
+		t.Fatalf("second listen on %s with reuse port: %v", first.Addr(), err)
+	}
+	second.Close()
+
+	if l, err := listenStratum(ctx, first.Addr().String(), false); err == nil {
+		l.Close()
+		t.Fatalf("expected bind without reuse port to fail while the port is taken")
+	}
+}
+
+func TestStratumReadyFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := writeStratumReadyFile(dir); err != nil {
+		t.Fatalf("writeStratumReadyFile: %v", err)
+	}
+	data, err := os.ReadFile(stratumReadyFilePath(dir))
+	if err != nil {
+		t.Fatalf("read ready file: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != strconv.Itoa(os.Getpid()) {
+		t.Fatalf("ready file pid = %q, want %d", got, os.Getpid())
+	}
+
+	// A replacement process owns the file; shutdown must leave it alone.
+	if err := os.WriteFile(stratumReadyFilePath(dir), []byte("999999999\n"), 0o644); err != nil {
+		t.Fatalf("overwrite ready file: %v", err)
+	}
+	removeStratumReadyFile(dir)
+	if _, err := os.Stat(stratumReadyFilePath(dir)); err != nil {
+		t.Fatalf("ready file of another process was removed: %v", err)
+	}
+
+	if err := writeStratumReadyFile(dir); err != nil {
+		t.Fatalf("writeStratumReadyFile: %v", err)
+	}
+	removeStratumReadyFile(dir)
+	if _, err := os.Stat(stratumReadyFilePath(dir)); !os.IsNotExist(err) {
+		t.Fatalf("expected own ready file removed, stat err = %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// minStratumProxyHMACSecretLen keeps the trusted-proxy secret long enough
+// that it cannot be brute forced from observed submits.
+const minStratumProxyHMACSecretLen = 32
+
+// stratumSubmitHMACMessage is the text a trusted proxy signs for one
+// mining.submit: the method name followed by each param, newline separated.
+// Every param must be a JSON string (as standard submits are).
+func stratumSubmitHMACMessage(params []any) ([]byte, bool) {
+	var b strings.Builder
+	b.WriteString("mining.submit")
+	for _, p := range params {
+		s, ok := p.(string)
+		if !ok {
+			return nil, false
+		}
+		b.WriteByte('\n')
+		b.WriteString(s)
+	}
+	return []byte(b.String()), true
+}
+
+// computeStratumSubmitHMAC returns the hex HMAC-SHA256 a proxy must send in
+// the request's non-standard top-level "hmac" field.
+func computeStratumSubmitHMAC(secret []byte, params []any) (string, bool) {
+	msg, ok := stratumSubmitHMACMessage(params)
+	if !ok {
+		return "", false
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(msg)
+	return hex.EncodeToString(mac.Sum(nil)), true
+}
+
+// verifySubmitHMAC enforces the submit HMAC on trusted-proxy connections
+// (a no-op elsewhere). A missing or wrong HMAC rejects the share.
+func (mc *MinerConn) verifySubmitHMAC(req *StratumRequest, now time.Time) bool {
+	if len(mc.submitHMACKey) == 0 {
+		return true
+	}
+	reason := "missing submit hmac"
+	if got := strings.TrimSpace(req.HMAC); got != "" {
+		reason = "invalid submit hmac"
+		want, ok := computeStratumSubmitHMAC(mc.submitHMACKey, req.Params)
+		gotRaw, err := hex.DecodeString(strings.ToLower(got))
+		wantRaw, _ := hex.DecodeString(want)
+		if ok && err == nil && hmac.Equal(gotRaw, wantRaw) {
+			return true
+		}
+	}
+	worker := ""
+	if len(req.Params) > 0 {
+		worker, _ = req.Params[0].(string)
+	}
+	logger.Warn("submit rejected on proxy listener", "component", "miner", "kind", "proxy_hmac", "remote", mc.id, "worker", worker, "reason", reason)
+	mc.recordShare(worker, false, 0, 0, reason, "", nil, now)
+	if mc.metrics != nil {
+		mc.metrics.RecordSubmitError(reason)
+	}
+	mc.writeResponse(StratumResponse{ID: req.ID, Result: false, Error: newStratumError(stratumErrCodeUnauthorized, reason)})
+	return false
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifySubmitHMAC(t *testing.T) {
+	secret := []byte(strings.Repeat("s", minStratumProxyHMACSecretLen))
+	params := []any{"worker.1", "1", "00000000", "65000000", "00000000"}
+	good, ok := computeStratumSubmitHMAC(secret, params)
+	if !ok {
+		t.Fatalf("compute hmac failed")
+	}
+
+	var parsed StratumRequest
+	line := `{"id":1,"method":"mining.submit","params":["worker.1","1","00000000","65000000","00000000"],"hmac":"` + good + `"}`
+	if err := fastJSONUnmarshal([]byte(line), &parsed); err != nil || parsed.HMAC != good {
+		t.Fatalf("hmac field not parsed: %q err=%v", parsed.HMAC, err)
+	}
+
+	conn := &recordConn{}
+	mc := &MinerConn{id: "proxy", conn: conn, submitHMACKey: secret}
+	now := time.Now()
+	if !mc.verifySubmitHMAC(&StratumRequest{ID: 1, Method: "mining.submit", Params: params, HMAC: strings.ToUpper(good)}, now) {
+		t.Fatalf("expected valid hmac to pass")
+	}
+	if conn.String() != "" {
+		t.Fatalf("valid hmac should not write a response, got %q", conn.String())
+	}
+
+	tampered := append([]any(nil), params...)
+	tampered[4] = "00000001"
+	for name, req := range map[string]*StratumRequest{
+		"missing":  {ID: 2, Method: "mining.submit", Params: params},
+		"tampered": {ID: 3, Method: "mining.submit", Params: tampered, HMAC: good},
+		"garbage":  {ID: 4, Method: "mining.submit", Params: params, HMAC: "zz"},
+	} {
+		if mc.verifySubmitHMAC(req, now) {
+			t.Fatalf("%s hmac should be rejected", name)
+		}
+	}
+	if got := strings.Count(conn.String(), `"result":false`); got != 3 {
+		t.Fatalf("expected 3 rejections, got %d: %s", got, conn.String())
+	}
+
+	// Connections outside the proxy listener ignore the field entirely.
+	plain := &MinerConn{id: "plain", conn: &recordConn{}}
+	if !plain.verifySubmitHMAC(&StratumRequest{ID: 5, Method: "mining.submit", Params: params}, now) {
+		t.Fatalf("non-proxy connection should not require an hmac")
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"sync/atomic"
+)
+
+// traceSamplePercentBits holds the live trace sample percent as float64 bits
+// so the accept path can read it without taking the config lock.
+var traceSamplePercentBits atomic.Uint64
+
+// setTraceSamplePercent updates the percent of new connections whose Stratum
+// JSON traffic is traced. Values are clamped to [0,100]. Existing connections
+// keep the decision made when they were accepted.
+func setTraceSamplePercent(p float64) {
+	if math.IsNaN(p) || p < 0 {
+		p = 0
+	} else if p > 100 {
+		p = 100
+	}
+	traceSamplePercentBits.Store(math.Float64bits(p))
+}
+
+func traceSamplePercent() float64 {
+	return math.Float64frombits(traceSamplePercentBits.Load())
+}
+
+// traceSampleConnection decides whether a newly accepted connection should be
+// traced for its whole lifetime.
+func traceSampleConnection() bool {
+	return sampleTraceDecision(traceSamplePercent(), rand.Float64())
+}
+
+func sampleTraceDecision(percent, roll float64) bool {
+	if percent <= 0 {
+		return false
+	}
+	if percent >= 100 {
+		return true
+	}
+	return roll*100 < percent
+}
+
+// traceJSON logs one raw JSON-RPC line for a sampled connection at debug level,
+// tagged with the connection id so request/response pairs can be matched up.
+func (mc *MinerConn) traceJSON(direction string, line []byte) {
+	if !mc.traceSampled || !debugLogging {
+		return
+	}
+	logger.Debug("stratum trace",
+		"component", "stratum", "kind", "trace",
+		"conn", mc.connectionIDString(),
+		"remote", mc.id,
+		"dir", direction,
+		"json", string(redactTraceLine(trimTraceLine(line))),
+	)
+}
+
+// traceRedacted replaces secrets in traced lines.
+const traceRedacted = "<redacted>"
+
+// redactTraceLine hides the password param of mining.authorize (and its
+// mining.auth alias), which carries the shared stratum_password or a
+// per-worker password. Lines that mention either method but cannot be
+// parsed are dropped entirely rather than risk logging the secret.
+func redactTraceLine(line []byte) []byte {
+	// "mining.auth" is also a prefix of "mining.authorize".
+	if !bytes.Contains(line, []byte("mining.auth")) {
+		return line
+	}
+	var req map[string]any
+	if err := fastJSONUnmarshal(line, &req); err != nil {
+		return []byte(traceRedacted)
+	}
+	switch method, _ := req["method"].(string); method {
+	case "mining.authorize", "mining.auth":
+	default:
+		return line
+	}
+	if params, ok := req["params"].([]any); ok {
+		for i := 1; i < len(params); i++ {
+			params[i] = traceRedacted
+		}
+		req["params"] = params
+	} else if req["params"] != nil {
+		req["params"] = traceRedacted
+	}
+	out, err := fastJSONMarshal(req)
+	if err != nil {
+		return []byte(traceRedacted)
+	}
+	return out
+}
+
+func trimTraceLine(line []byte) []byte {
+	for len(line) > 0 && (line[len(line)-1] == '\n' || line[len(line)-1] == '\r') {
+		line = line[:len(line)-1]
+	}
+	return line
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetTraceSamplePercentClamps(t *testing.T) {
+	defer setTraceSamplePercent(0)
+
+	setTraceSamplePercent(-5)
+	if got := traceSamplePercent(); got != 0 {
+		t.Fatalf("negative percent = %v, want 0", got)
+	}
+	setTraceSamplePercent(250)
+	if got := traceSamplePercent(); got != 100 {
+		t.Fatalf("oversized percent = %v, want 100", got)
+	}
+	setTraceSamplePercent(12.5)
+	if got := traceSamplePercent(); got != 12.5 {
+		t.Fatalf("percent = %v, want 12.5", got)
+	}
+}
+
+func TestSampleTraceDecision(t *testing.T) {
+	tests := []struct {
+		percent float64
+		roll    float64
+		want    bool
+	}{
+		{percent: 0, roll: 0, want: false},
+		{percent: 100, roll: 0.9999, want: true},
+		{percent: 10, roll: 0.05, want: true},
+		{percent: 10, roll: 0.10, want: false},
+		{percent: 10, roll: 0.5, want: false},
+	}
+	for _, tt := range tests {
+		if got := sampleTraceDecision(tt.percent, tt.roll); got != tt.want {
+			t.Errorf("sampleTraceDecision(%v, %v) = %v, want %v", tt.percent, tt.roll, got, tt.want)
+		}
+	}
+}
+
+func TestRedactTraceLineHidesAuthorizePassword(t *testing.T) {
+	line := []byte(`{"id":2,"method":"mining.authorize","params":["bc1qworker.rig1","s3cret-pass"]}`)
+	got := string(redactTraceLine(line))
+	if strings.Contains(got, "s3cret-pass") {
+		t.Fatalf("password leaked in trace: %s", got)
+	}
+	if !strings.Contains(got, "bc1qworker.rig1") || !strings.Contains(got, traceRedacted) {
+		t.Fatalf("expected worker name kept and password redacted, got %s", got)
+	}
+
+	// The mining.auth alias reaches handleAuthorize too.
+	alias := string(redactTraceLine([]byte(`{"id":3,"method":"mining.auth","params":["bc1qworker.rig1","s3cret-pass"]}`)))
+	if strings.Contains(alias, "s3cret-pass") || !strings.Contains(alias, traceRedacted) {
+		t.Fatalf("mining.auth password not redacted: %s", alias)
+	}
+
+	// Unparsable authorize lines are dropped entirely.
+	if got := string(redactTraceLine([]byte(`{"method":"mining.authorize","params":["w","s3cret-pass"`))); strings.Contains(got, "s3cret-pass") {
+		t.Fatalf("password leaked from malformed line: %s", got)
+	}
+
+	// Other methods pass through unchanged.
+	submit := []byte(`{"id":4,"method":"mining.submit","params":["w","job","00","6553f100","1"]}`)
+	if got := redactTraceLine(submit); string(got) != string(submit) {
+		t.Fatalf("non-auth line modified: %s", got)
+	}
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// stratumWSBacklog bounds upgraded WebSocket connections waiting for the
+// stratum accept loop; beyond it new upgrades are closed right away.
+const stratumWSBacklog = 64
+
+// wsStratumListener is a net.Listener fed by WebSocket upgrades on the
+// status server. Served by the regular stratum accept loop, WebSocket
+// miners pass the same accept limiter, bans, gating and capacity checks as
+// TCP miners.
+type wsStratumListener struct {
+	path      string
+	addr      net.Addr
+	upgrader  websocket.Upgrader
+	conns     chan net.Conn
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newWSStratumListener(path string) *wsStratumListener {
+	return &wsStratumListener{
+		path: path,
+		addr: wsStratumAddr(path),
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  4096,
+			WriteBufferSize: 4096,
+			// Browser miners are served from arbitrary origins; Stratum has
+			// no cookies or ambient credentials to protect.
+			CheckOrigin: func(*http.Request) bool { return true },
+		},
+		conns: make(chan net.Conn, stratumWSBacklog),
+		done:  make(chan struct{}),
+	}
+}
+
+func (l *wsStratumListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *wsStratumListener) Close() error {
+	l.closeOnce.Do(func() { close(l.done) })
+	return nil
+}
+
+func (l *wsStratumListener) Addr() net.Addr { return l.addr }
+
+// wrap serves WebSocket upgrades on l.path and passes every other request to
+// next. It sits outside the status middleware, which neither supports
+// hijacking nor should throttle long-lived miner connections.
+func (l *wsStratumListener) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != l.path {
+			next.ServeHTTP(w, r)
+			return
+		}
+		l.ServeHTTP(w, r)
+	})
+}
+
+func (l *wsStratumListener) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	select {
+	case <-l.done:
+		http.Error(w, "stratum unavailable", http.StatusServiceUnavailable)
+		return
+	default:
+	}
+	ws, err := l.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade already wrote the error response.
+		logger.Debug("stratum websocket upgrade failed", "component", "stratum", "kind", "websocket", "remote", r.RemoteAddr, "error", err)
+		return
+	}
+	// Drop the status server's request deadlines from the hijacked socket;
+	// MinerConn manages its own.
+	_ = ws.NetConn().SetDeadline(time.Time{})
+	conn := newWSStratumConn(ws)
+	select {
+	case l.conns <- conn:
+	case <-l.done:
+		_ = conn.Close()
+	default:
+		logger.Warn("rejecting websocket miner: accept backlog full", "component", "stratum", "kind", "websocket", "remote", r.RemoteAddr)
+		_ = conn.Close()
+	}
+}
+
+// newStratumWSServer serves l on a dedicated stratum_ws_listen address.
+// Every request is an upgrade attempt; plain HTTP requests get the
+// upgrader's 400. Upgraded sockets are hijacked, so the server timeouts only
+// cover the handshake.
+func newStratumWSServer(l *wsStratumListener) *http.Server {
+	return &http.Server{
+		Handler:           l,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       15 * time.Second,
+		WriteTimeout:      15 * time.Second,
+	}
+}
+
+type wsStratumAddr string
+
+func (a wsStratumAddr) Network() string { return "websocket" }
+func (a wsStratumAddr) String() string  { return string(a) }
+
+// wsStratumConn adapts a WebSocket to the line-delimited net.Conn MinerConn
+// expects: each text (or binary) frame is one JSON-RPC message and reads
+// see it followed by '\n'; each written line is sent as one text frame.
+//
+// Frames are read by a pump goroutine so read deadlines can expire without
+// poisoning the WebSocket (gorilla treats a read timeout as fatal, while
+// MinerConn keeps reading after one). It deliberately has no NetConn method,
+// so findTCPConn returns nil and TCP tuning is skipped.
+type wsStratumConn struct {
+	ws *websocket.Conn
+
+	frames  chan []byte
+	readErr error // set before frames is closed
+	pending []byte
+
+	deadlineMu   sync.Mutex
+	readDeadline time.Time
+
+	writeMu  sync.Mutex
+	partial  []byte
+	done     chan struct{}
+	doneOnce sync.Once
+}
+
+func newWSStratumConn(ws *websocket.Conn) *wsStratumConn {
+	ws.SetReadLimit(maxStratumMessageSize)
+	c := &wsStratumConn{
+		ws:     ws,
+		frames: make(chan []byte, 8),
+		done:   make(chan struct{}),
+	}
+	go c.pump()
+	return c
+}
+
+func (c *wsStratumConn) pump() {
+	defer close(c.frames)
+	for {
+		typ, msg, err := c.ws.ReadMessage()
+		if err != nil {
+			c.readErr = err
+			return
+		}
+		if typ != websocket.TextMessage && typ != websocket.BinaryMessage {
+			continue
+		}
+		if len(msg) == 0 || msg[len(msg)-1] != '\n' {
+			msg = append(msg, '\n')
+		}
+		select {
+		case c.frames <- msg:
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func (c *wsStratumConn) Read(p []byte) (int, error) {
+	if len(c.pending) > 0 {
+		n := copy(p, c.pending)
+		c.pending = c.pending[n:]
+		return n, nil
+	}
+	c.deadlineMu.Lock()
+	deadline := c.readDeadline
+	c.deadlineMu.Unlock()
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		d := time.Until(deadline)
+		if d <= 0 {
+			return 0, os.ErrDeadlineExceeded
+		}
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case msg, ok := <-c.frames:
+		if !ok {
+			return 0, c.closedReadErr()
+		}
+		n := copy(p, msg)
+		c.pending = msg[n:]
+		return n, nil
+	case <-timeout:
+		return 0, os.ErrDeadlineExceeded
+	case <-c.done:
+		return 0, net.ErrClosed
+	}
+}
+
+// closedReadErr maps the pump's terminal error to what a TCP read would
+// return, so a browser closing its tab is logged as a plain disconnect.
+func (c *wsStratumConn) closedReadErr() error {
+	if c.readErr == nil || websocket.IsCloseError(c.readErr,
+		websocket.CloseNormalClosure, websocket.CloseGoingAway,
+		websocket.CloseNoStatusReceived, websocket.CloseAbnormalClosure) {
+		return io.EOF
+	}
+	return c.readErr
+}
+
+func (c *wsStratumConn) Write(p []byte) (int, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	rest := p
+	for len(rest) > 0 {
+		i := bytes.IndexByte(rest, '\n')
+		if i < 0 {
+			c.partial = append(c.partial, rest...)
+			break
+		}
+		line := rest[:i]
+		if len(c.partial) > 0 {
+			line = append(c.partial, line...)
+			c.partial = c.partial[:0]
+		}
+		if len(line) > 0 {
+			if err := c.ws.WriteMessage(websocket.TextMessage, line); err != nil {
+				return len(p) - len(rest), err
+			}
+		}
+		rest = rest[i+1:]
+	}
+	return len(p), nil
+}
+
+func (c *wsStratumConn) Close() error {
+	c.doneOnce.Do(func() { close(c.done) })
+	// WriteControl may run concurrently with a blocked Write.
+	_ = c.ws.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	return c.ws.Close()
+}
+
+func (c *wsStratumConn) LocalAddr() net.Addr  { return c.ws.LocalAddr() }
+func (c *wsStratumConn) RemoteAddr() net.Addr { return c.ws.RemoteAddr() }
+
+func (c *wsStratumConn) SetDeadline(t time.Time) error {
+	if err := c.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return c.SetWriteDeadline(t)
+}
+
+func (c *wsStratumConn) SetReadDeadline(t time.Time) error {
+	c.deadlineMu.Lock()
+	c.readDeadline = t
+	c.deadlineMu.Unlock()
+	return nil
+}
+
+func (c *wsStratumConn) SetWriteDeadline(t time.Time) error {
+	return c.ws.SetWriteDeadline(t)
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func dialWSStratumForTest(t *testing.T) (*websocket.Conn, net.Conn, *httptest.Server) {
+	t.Helper()
+	ln := newWSStratumListener("/stratum")
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "status page", http.StatusTeapot)
+	})
+	srv := httptest.NewServer(ln.wrap(next))
+	t.Cleanup(func() {
+		ln.Close()
+		srv.Close()
+	})
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/stratum", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return client, conn, srv
+}
+
+func TestWSStratumConnBridgesFramesAndLines(t *testing.T) {
+	client, conn, srv := dialWSStratumForTest(t)
+
+	if findTCPConn(conn) != nil {
+		t.Fatalf("expected findTCPConn to return nil for a websocket conn")
+	}
+	resp, err := http.Get(srv.URL + "/other")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTeapot {
+		t.Fatalf("non-websocket path status = %d, want passthrough", resp.StatusCode)
+	}
+
+	// A frame without a trailing newline still reads as one line.
+	if err := client.WriteMessage(websocket.TextMessage, []byte(`{"id":1,"method":"mining.subscribe","params":[]}`)); err != nil {
+		t.Fatalf("client write: %v", err)
+	}
+	reader := bufio.NewReader(conn)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	line, err := reader.ReadString('\n')
+	if err != nil || line != "{\"id\":1,\"method\":\"mining.subscribe\",\"params\":[]}\n" {
+		t.Fatalf("read line = %q, %v", line, err)
+	}
+
+	// Two lines in one write, the second split across writes, become two frames.
+	if _, err := conn.Write([]byte("{\"id\":1}\n{\"id\"")); err != nil {
+		t.Fatalf("server write: %v", err)
+	}
+	if _, err := conn.Write([]byte(":2}\n")); err != nil {
+		t.Fatalf("server write: %v", err)
+	}
+	for _, want := range []string{`{"id":1}`, `{"id":2}`} {
+		_ = client.SetReadDeadline(time.Now().Add(2 * time.Second))
+		typ, msg, err := client.ReadMessage()
+		if err != nil || typ != websocket.TextMessage || string(msg) != want {
+			t.Fatalf("client read = %d %q %v, want text %q", typ, msg, err, want)
+		}
+	}
+}
+
+func TestWSStratumConnReadDeadlineIsNotFatal(t *testing.T) {
+	client, conn, _ := dialWSStratumForTest(t)
+
+	_ = conn.SetReadDeadline(time.Now().Add(20 * time.Millisecond))
+	buf := make([]byte, 64)
+	_, err := conn.Read(buf)
+	var nErr net.Error
+	if !errors.As(err, &nErr) || !nErr.Timeout() {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+
+	if err := client.WriteMessage(websocket.TextMessage, []byte("{}\n")); err != nil {
+		t.Fatalf("client write: %v", err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, err := conn.Read(buf)
+	if err != nil || string(buf[:n]) != "{}\n" {
+		t.Fatalf("read after timeout = %q, %v", buf[:n], err)
+	}
+
+	client.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""))
+	if _, err := conn.Read(buf); !errors.Is(err, io.EOF) {
+		t.Fatalf("expected io.EOF after client close, got %v", err)
+	}
+}
+
+func TestStratumWSServerUpgradesAnyPath(t *testing.T) {
+	ln := newWSStratumListener("")
+	raw, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	srv := newStratumWSServer(ln)
+	go srv.Serve(raw)
+	t.Cleanup(func() {
+		srv.Close()
+		ln.Close()
+	})
+
+	resp, err := http.Get("http://" + raw.Addr().String() + "/")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("plain request status = %d, want 400", resp.StatusCode)
+	}
+
+	for _, path := range []string{"/", "/stratum"} {
+		client, _, err := websocket.DefaultDialer.Dial("ws://"+raw.Addr().String()+path, nil)
+		if err != nil {
+			t.Fatalf("dial %s: %v", path, err)
+		}
+		conn, err := ln.Accept()
+		if err != nil {
+			t.Fatalf("accept %s: %v", path, err)
+		}
+		if err := client.WriteMessage(websocket.TextMessage, []byte(`{"id":1}`)); err != nil {
+			t.Fatalf("client write: %v", err)
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil || line != "{\"id\":1}\n" {
+			t.Fatalf("read line on %s = %q, %v", path, line, err)
+		}
+		conn.Close()
+		client.Close()
+	}
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"fmt"
 	"runtime"
+	"runtime/debug"
 	"sync"
 	"time"
 )
@@ -47,6 +49,7 @@ type submissionTask struct {
 	versionHex         string
 	useVersion         uint32
 	scriptTime         int64
+	extranonce1        []byte
 	assignedDifficulty float64
 	policyReject       submitPolicyReject
 	receivedAt         time.Time
@@ -99,13 +102,52 @@ func (p *submissionWorkerPool) submit(task submissionTask) {
 
 func (p *submissionWorkerPool) worker(id int) {
 	for task := range p.tasks {
-		func(t submissionTask) {
-			defer func() {
-				if r := recover(); r != nil {
-					logger.Error("submission worker panic", "worker", id, "error", r)
-				}
-			}()
-			t.mc.processSubmissionTask(t)
-		}(task)
+		runSubmissionTask(task, id)
+	}
+}
+
+// runSubmissionTask processes one share, recovering from a panic so a bad
+// task never takes a worker (or, for inline processing, the connection's
+// read loop) down. worker is the pool worker index, or -1 when inline.
+func runSubmissionTask(t submissionTask, worker int) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.mc.handleSubmissionPanic(t, worker, r)
+		}
+	}()
+	t.mc.processSubmissionTask(t)
+}
+
+// handleSubmissionPanic logs a recovered share-processing panic with the
+// task details, counts it, answers the submit with an internal-error reject
+// so the miner is not left waiting, and drops the connection once it has
+// caused submit_panic_disconnect_after panics.
+func (mc *MinerConn) handleSubmissionPanic(t submissionTask, worker int, r any) {
+	if mc == nil {
+		logger.Error("submission task panic", "component", "miner", "kind", "submit_panic", "worker", worker, "panic", r, "stack", string(debug.Stack()))
+		return
+	}
+	count := mc.submitPanics.Add(1)
+	logger.Error("submission task panic",
+		"component", "miner", "kind", "submit_panic",
+		"worker", worker,
+		"remote", mc.id,
+		"miner_worker", t.workerName,
+		"job_id", t.jobID,
+		"extranonce2", t.extranonce2,
+		"ntime", t.ntime,
+		"nonce", t.nonce,
+		"version", t.versionHex,
+		"panic", r,
+		"connection_panics", count,
+		"stack", string(debug.Stack()),
+	)
+	if mc.metrics != nil {
+		mc.metrics.RecordSubmitPanic()
+		mc.metrics.RecordErrorEvent("submit_panic", fmt.Sprintf("share processing panic: %v", r), time.Now())
+	}
+	mc.writeResponse(StratumResponse{ID: t.reqID, Result: false, Error: newStratumError(stratumErrCodeInvalidRequest, "internal error")})
+	if limit := mc.cfg.SubmitPanicDisconnectAfter; limit > 0 && int(count) >= limit {
+		mc.Close("repeated submit processing panics")
 	}
 }
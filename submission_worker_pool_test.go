@@ -0,0 +1,36 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+type closeTrackConn struct {
+	recordConn
+	closed bool
+}
+
+func (c *closeTrackConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestHandleSubmissionPanicRejectsAndDisconnects(t *testing.T) {
+	conn := &closeTrackConn{}
+	metrics := NewPoolMetrics()
+	mc := &MinerConn{id: "panicky", conn: conn, metrics: metrics, cfg: Config{SubmitPanicDisconnectAfter: 2}}
+	task := submissionTask{mc: mc, reqID: 7, workerName: "w", jobID: "j"}
+
+	mc.handleSubmissionPanic(task, 0, "boom")
+	if got := conn.String(); !strings.Contains(got, `"id":7`) || !strings.Contains(got, "internal error") {
+		t.Fatalf("expected internal-error reject for the panicking submit, got %q", got)
+	}
+	if metrics.SubmitPanics() != 1 || conn.closed {
+		t.Fatalf("after one panic: panics=%d closed=%v", metrics.SubmitPanics(), conn.closed)
+	}
+
+	mc.handleSubmissionPanic(task, 0, "boom")
+	if metrics.SubmitPanics() != 2 || !conn.closed {
+		t.Fatalf("expected disconnect after repeated panics: panics=%d closed=%v", metrics.SubmitPanics(), conn.closed)
+	}
+}
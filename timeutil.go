@@ -2,9 +2,53 @@ package main
 
 import (
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
+	// Embed the IANA database so display_timezone works in minimal containers.
+	_ "time/tzdata"
 )
 
+// displayLocation is the timezone used when HTML pages render absolute
+// timestamps. JSON/API responses always stay in UTC.
+var displayLocation atomic.Pointer[time.Location]
+
+// loadDisplayTimezone resolves a [branding].display_timezone value; empty
+// means UTC.
+func loadDisplayTimezone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(name)
+}
+
+// setDisplayTimezone updates the HTML display timezone, falling back to UTC
+// for unknown names (config validation rejects those up front).
+func setDisplayTimezone(name string) {
+	loc, err := loadDisplayTimezone(name)
+	if err != nil || loc == nil {
+		loc = time.UTC
+	}
+	displayLocation.Store(loc)
+}
+
+func currentDisplayLocation() *time.Location {
+	if loc := displayLocation.Load(); loc != nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// formatDisplayTime renders t in the configured display timezone. The zone
+// abbreviation comes from the tz database, so DST transitions are handled.
+func formatDisplayTime(t time.Time) string {
+	if t.IsZero() {
+		return "—"
+	}
+	return t.In(currentDisplayLocation()).Format("2006-01-02 15:04:05 MST")
+}
+
 // humanShortDuration produces a short, human-friendly duration string like
 // "just now", "5s", "3m", "2h", "4d".
 func humanShortDuration(d time.Duration) string {
@@ -8,11 +8,13 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"math/big"
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 )
@@ -197,3 +199,51 @@ func (cr *certReloader) checkAndReload() {
 		}
 	}
 }
+
+// loadClientCAPool reads a PEM bundle of CA certificates used to verify
+// miner client certificates on the stratum TLS listener.
+func loadClientCAPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read client ca: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("client ca %s: no PEM certificates found", path)
+	}
+	return pool, nil
+}
+
+// stratumTLSConfig builds the tls.Config for the stratum TLS listener. When
+// clientCAPath is set, miners must present a certificate signed by one of
+// its CAs; the HTTPS status server keeps its own config without client auth.
+func stratumTLSConfig(cr *certReloader, clientCAPath string) (*tls.Config, error) {
+	cfg := &tls.Config{
+		GetCertificate: cr.getCertificate,
+	}
+	if clientCAPath == "" {
+		return cfg, nil
+	}
+	pool, err := loadClientCAPool(clientCAPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ClientCAs = pool
+	cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	return cfg, nil
+}
+
+// isTLSClientCertError reports whether a server-side handshake error was
+// caused by a missing or unverifiable client certificate.
+func isTLSClientCertError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var verifyErr *tls.CertificateVerificationError
+	if errors.As(err, &verifyErr) {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "client didn't provide a certificate") ||
+		strings.Contains(msg, "failed to verify certificate")
+}
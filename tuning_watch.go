@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// tuningWatchInterval is how often watch_tuning_file polls tuning.toml.
+const tuningWatchInterval = 5 * time.Second
+
+func tuningFilePath(cfg Config) string {
+	return filepath.Join(cfg.DataDir, "config", "tuning.toml")
+}
+
+// checkTuningFile parses tuning.toml without applying it. A config reload
+// calls it first because loadConfig treats a malformed overlay as fatal,
+// which is right at startup but must not take a running pool down.
+func checkTuningFile(path string) error {
+	if _, _, err := loadTuningFile(path); err != nil {
+		return fmt.Errorf("tuning config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// reloadTuningFile re-reads tuning.toml and overlays only its settings on
+// the running config, then hands the result to the job manager and every
+// live connection, as an admin settings apply does, so tuning changes such
+// as vardiff bounds reach connected miners without a reconnect. Everything
+// else (payout settings, the version mask picked from the node, admin
+// applies) is kept as it is. A file that fails to parse or validate is
+// refused as a whole and the running config stays in place.
+func (s *StatusServer) reloadTuningFile(path string) (Config, error) {
+	fc, ok, err := loadTuningFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("tuning config file %s: %w", path, err)
+	}
+	cfg := s.Config()
+	if !ok {
+		// A removed tuning.toml has nothing to overlay; its settings keep
+		// their running values until the next restart.
+		return cfg, nil
+	}
+	applyTuningConfig(&cfg, *fc)
+	if err := validateConfig(cfg); err != nil {
+		return Config{}, err
+	}
+	if err := s.jobMgr.ApplyTuningConfig(*fc); err != nil {
+		return Config{}, err
+	}
+	s.UpdateConfig(cfg)
+	setCoinbaseMaxBytes(cfg.CoinbaseMaxBytes)
+	s.applyWorkerDifficultyOverrides(cfg)
+	return cfg, nil
+}
+
+// tuningFileStamp identifies one version of tuning.toml on disk.
+type tuningFileStamp struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+func (s tuningFileStamp) same(o tuningFileStamp) bool {
+	return s.exists == o.exists && s.size == o.size && s.modTime.Equal(o.modTime)
+}
+
+func statTuningFile(path string) (tuningFileStamp, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return tuningFileStamp{}, nil
+	}
+	if err != nil {
+		return tuningFileStamp{}, err
+	}
+	return tuningFileStamp{exists: true, modTime: info.ModTime(), size: info.Size()}, nil
+}
+
+// watchTuningFile polls path and calls reload whenever the file is created,
+// modified or removed. reload does the parsing and validation, so a bad
+// edit is logged there and the next save triggers another attempt.
+func watchTuningFile(ctx context.Context, path string, interval time.Duration, reload func()) {
+	last, err := statTuningFile(path)
+	if err != nil {
+		logger.Warn("tuning file stat failed during watch", "component", "startup", "kind", "tuning_watch", "path", path, "error", err)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		cur, err := statTuningFile(path)
+		if err != nil {
+			logger.Warn("tuning file stat failed during watch", "component", "startup", "kind", "tuning_watch", "path", path, "error", err)
+			continue
+		}
+		if cur.same(last) {
+			continue
+		}
+		last = cur
+		reload()
+	}
+}
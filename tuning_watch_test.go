@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCheckTuningFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tuning.toml")
+	if err := checkTuningFile(path); err != nil {
+		t.Fatalf("missing tuning file: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("[difficulty]\nvardiff_enabled = true\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := checkTuningFile(path); err != nil {
+		t.Fatalf("valid tuning file: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("[difficulty\nvardiff_enabled = \n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := checkTuningFile(path); err == nil {
+		t.Fatalf("expected error for malformed tuning file")
+	}
+}
+
+func TestWatchTuningFileReloadsOnChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tuning.toml")
+	if err := os.WriteFile(path, []byte("[difficulty]\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloads := make(chan struct{}, 4)
+	go watchTuningFile(ctx, path, 10*time.Millisecond, func() { reloads <- struct{}{} })
+
+	select {
+	case <-reloads:
+		t.Fatalf("unexpected reload before the file changed")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := os.WriteFile(path, []byte("[difficulty]\nvardiff_enabled = false\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	select {
+	case <-reloads:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected a reload after tuning.toml changed")
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	select {
+	case <-reloads:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected a reload after tuning.toml was removed")
+	}
+}
+
+func TestReloadTuningFileKeepsNonTuningSettings(t *testing.T) {
+	const addr = "1BitcoinEaterAddressDontSendf59kuE"
+	cfg := defaultConfig()
+	cfg.PayoutAddress = addr
+	cfg.RPCCookiePath = "/var/lib/bitcoind/.cookie"
+	cfg.VersionMask = 0x3fffe000
+	cfg.VersionMaskConfigured = true
+	cfg.MaxDifficulty = 1000
+
+	jm := &JobManager{cfg: cfg, payoutScript: []byte{0x51}, donationScript: []byte{0x52}}
+	s := &StatusServer{jobMgr: jm}
+	s.UpdateConfig(cfg)
+
+	// A payout change on disk must not reach the running pool through a
+	// tuning reload; only tuning.toml is read.
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tuning.toml")
+	if err := os.WriteFile(path, []byte("[difficulty]\nmax_difficulty = 2000.0\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	got, err := s.reloadTuningFile(path)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	for name, c := range map[string]Config{"returned": got, "status": s.Config(), "job manager": jm.cfg} {
+		if c.MaxDifficulty != 2000 {
+			t.Fatalf("%s max_difficulty = %v, want 2000", name, c.MaxDifficulty)
+		}
+		if c.VersionMask != 0x3fffe000 || !c.VersionMaskConfigured {
+			t.Fatalf("%s version mask = %08x configured=%v, want 3fffe000", name, c.VersionMask, c.VersionMaskConfigured)
+		}
+		if c.PayoutAddress != addr {
+			t.Fatalf("%s payout address = %q, want %q", name, c.PayoutAddress, addr)
+		}
+	}
+	if string(jm.payoutScript) != "\x51" || string(jm.donationScript) != "\x52" {
+		t.Fatalf("payout scripts changed: payout=%x donation=%x", jm.payoutScript, jm.donationScript)
+	}
+
+	// An invalid value refuses the whole file, including valid keys in it.
+	if err := os.WriteFile(path, []byte("[difficulty]\nmax_difficulty = 3000.0\nmin_difficulty = 5000.0\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := s.reloadTuningFile(path); err == nil {
+		t.Fatalf("expected invalid tuning file to be refused")
+	}
+	if s.Config().MaxDifficulty != 2000 || jm.cfg.MaxDifficulty != 2000 {
+		t.Fatalf("refused reload changed config: status=%v job manager=%v", s.Config().MaxDifficulty, jm.cfg.MaxDifficulty)
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	// maxWorkerConnHistoryEvents bounds the connect and disconnect records
+	// kept per worker; older records are dropped first.
+	maxWorkerConnHistoryEvents = 32
+	// maxTrackedWorkerConnHistories bounds how many workers have a history.
+	// When full, workers with nothing left in the window are evicted and,
+	// failing that, new workers go untracked until space frees up.
+	maxTrackedWorkerConnHistories = 65536
+	// maxWorkerConnHistorySessions is how many sessions the worker page lists.
+	maxWorkerConnHistorySessions = 10
+)
+
+type workerConnSession struct {
+	start time.Time
+	end   time.Time
+}
+
+type workerConnEvent struct {
+	at time.Time
+	// reconnect is set when the worker had disconnected within the window
+	// before this connect.
+	reconnect bool
+}
+
+// workerConnHistory aggregates every connection registered under one worker
+// hash (policy [stratum].worker_connection_history_seconds). Records older
+// than the window are pruned, so a rig that stays connected for a full
+// window reads as clean again.
+type workerConnHistory struct {
+	open     map[uint64]time.Time // connection seq -> registered at
+	connects []workerConnEvent
+	closed   []workerConnSession
+}
+
+func (h *workerConnHistory) prune(now time.Time, window time.Duration) {
+	cutoff := now.Add(-window)
+	n := 0
+	for _, ev := range h.connects {
+		if !ev.at.Before(cutoff) {
+			h.connects[n] = ev
+			n++
+		}
+	}
+	h.connects = h.connects[:n]
+	n = 0
+	for _, s := range h.closed {
+		if !s.end.Before(cutoff) {
+			h.closed[n] = s
+			n++
+		}
+	}
+	h.closed = h.closed[:n]
+}
+
+func (h *workerConnHistory) empty() bool {
+	return len(h.open) == 0 && len(h.connects) == 0 && len(h.closed) == 0
+}
+
+// WorkerConnectionHistory is the worker page view of a workerConnHistory.
+type WorkerConnectionHistory struct {
+	Window      time.Duration
+	Active      int
+	Connects    int
+	Reconnects  int
+	Disconnects int
+	// Session durations cover connections that ended within the window.
+	ShortestSession time.Duration
+	LongestSession  time.Duration
+	AverageSession  time.Duration
+	LastDisconnect  time.Time
+	// Sessions lists the most recent connections, newest first.
+	Sessions []WorkerConnectionSession
+}
+
+type WorkerConnectionSession struct {
+	Start    time.Time
+	Duration time.Duration
+	Active   bool
+}
+
+func appendBoundedConnEvent(events []workerConnEvent, ev workerConnEvent) []workerConnEvent {
+	if len(events) >= maxWorkerConnHistoryEvents {
+		events = append(events[:0], events[len(events)-maxWorkerConnHistoryEvents+1:]...)
+	}
+	return append(events, ev)
+}
+
+func appendBoundedConnSession(sessions []workerConnSession, s workerConnSession) []workerConnSession {
+	if len(sessions) >= maxWorkerConnHistoryEvents {
+		sessions = append(sessions[:0], sessions[len(sessions)-maxWorkerConnHistoryEvents+1:]...)
+	}
+	return append(sessions, s)
+}
+
+// noteWorkerConnectLocked records connection seq registering under hash.
+// Callers hold r.mu.
+func (r *workerConnectionRegistry) noteWorkerConnectLocked(hash string, seq uint64, window time.Duration, now time.Time) {
+	if window <= 0 {
+		delete(r.connHistory, hash)
+		return
+	}
+	if r.connHistory == nil {
+		r.connHistory = make(map[string]*workerConnHistory)
+	}
+	h := r.connHistory[hash]
+	if h == nil {
+		if len(r.connHistory) >= maxTrackedWorkerConnHistories {
+			r.evictIdleConnHistoriesLocked(now, window)
+			if len(r.connHistory) >= maxTrackedWorkerConnHistories {
+				return
+			}
+		}
+		h = &workerConnHistory{open: make(map[uint64]time.Time)}
+		r.connHistory[hash] = h
+	}
+	h.prune(now, window)
+	h.open[seq] = now
+	h.connects = appendBoundedConnEvent(h.connects, workerConnEvent{at: now, reconnect: len(h.closed) > 0})
+}
+
+// noteWorkerDisconnectLocked closes the session connection seq opened under
+// hash. Callers hold r.mu.
+func (r *workerConnectionRegistry) noteWorkerDisconnectLocked(hash string, seq uint64, window time.Duration, now time.Time) {
+	h := r.connHistory[hash]
+	if h == nil {
+		return
+	}
+	if window <= 0 {
+		delete(r.connHistory, hash)
+		return
+	}
+	start, ok := h.open[seq]
+	if !ok {
+		return
+	}
+	delete(h.open, seq)
+	h.closed = appendBoundedConnSession(h.closed, workerConnSession{start: start, end: now})
+	h.prune(now, window)
+}
+
+func (r *workerConnectionRegistry) evictIdleConnHistoriesLocked(now time.Time, window time.Duration) {
+	for hash, h := range r.connHistory {
+		h.prune(now, window)
+		if h.empty() {
+			delete(r.connHistory, hash)
+		}
+	}
+}
+
+// connectionHistory summarizes the worker's connections over window. It
+// returns nil when the history is disabled or nothing is recorded.
+func (r *workerConnectionRegistry) connectionHistory(hash string, window time.Duration, now time.Time) *WorkerConnectionHistory {
+	if r == nil || hash == "" || window <= 0 {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	h := r.connHistory[hash]
+	if h == nil {
+		return nil
+	}
+	h.prune(now, window)
+	if h.empty() {
+		delete(r.connHistory, hash)
+		return nil
+	}
+
+	out := &WorkerConnectionHistory{
+		Window:      window,
+		Active:      len(h.open),
+		Connects:    len(h.connects),
+		Disconnects: len(h.closed),
+	}
+	for _, ev := range h.connects {
+		if ev.reconnect {
+			out.Reconnects++
+		}
+	}
+	var total time.Duration
+	for _, s := range h.closed {
+		d := s.end.Sub(s.start)
+		total += d
+		if out.ShortestSession == 0 || d < out.ShortestSession {
+			out.ShortestSession = d
+		}
+		if d > out.LongestSession {
+			out.LongestSession = d
+		}
+		if s.end.After(out.LastDisconnect) {
+			out.LastDisconnect = s.end
+		}
+		out.Sessions = append(out.Sessions, WorkerConnectionSession{Start: s.start, Duration: d})
+	}
+	if len(h.closed) > 0 {
+		out.AverageSession = total / time.Duration(len(h.closed))
+	}
+	for _, start := range h.open {
+		out.Sessions = append(out.Sessions, WorkerConnectionSession{Start: start, Duration: now.Sub(start), Active: true})
+	}
+	sort.Slice(out.Sessions, func(i, j int) bool { return out.Sessions[i].Start.After(out.Sessions[j].Start) })
+	if len(out.Sessions) > maxWorkerConnHistorySessions {
+		out.Sessions = out.Sessions[:maxWorkerConnHistorySessions]
+	}
+	return out
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWorkerConnectionHistoryCountsReconnects(t *testing.T) {
+	reg := newWorkerConnectionRegistry()
+	window := time.Hour
+	base := time.Unix(1700000000, 0)
+
+	reg.mu.Lock()
+	reg.noteWorkerConnectLocked("h", 1, window, base)
+	reg.noteWorkerDisconnectLocked("h", 1, window, base.Add(10*time.Second))
+	reg.noteWorkerConnectLocked("h", 2, window, base.Add(15*time.Second))
+	reg.noteWorkerDisconnectLocked("h", 2, window, base.Add(45*time.Second))
+	reg.noteWorkerConnectLocked("h", 3, window, base.Add(50*time.Second))
+	reg.mu.Unlock()
+
+	got := reg.connectionHistory("h", window, base.Add(time.Minute))
+	if got == nil {
+		t.Fatalf("expected a history")
+	}
+	if got.Connects != 3 || got.Reconnects != 2 || got.Disconnects != 2 || got.Active != 1 {
+		t.Fatalf("unexpected counts: %+v", got)
+	}
+	if got.ShortestSession != 10*time.Second || got.LongestSession != 30*time.Second || got.AverageSession != 20*time.Second {
+		t.Fatalf("unexpected session durations: %+v", got)
+	}
+	if len(got.Sessions) != 3 || !got.Sessions[0].Active {
+		t.Fatalf("expected newest (active) session first, got %+v", got.Sessions)
+	}
+
+	// A full clean window clears the flaky record while the rig stays up.
+	got = reg.connectionHistory("h", window, base.Add(50*time.Second+window+time.Second))
+	if got == nil || got.Reconnects != 0 || got.Disconnects != 0 || got.Active != 1 {
+		t.Fatalf("expected a clean history after a stable window, got %+v", got)
+	}
+}
+
+func TestWorkerConnectionHistoryBounded(t *testing.T) {
+	reg := newWorkerConnectionRegistry()
+	window := time.Hour
+	base := time.Unix(1700000000, 0)
+
+	reg.mu.Lock()
+	for i := range maxWorkerConnHistoryEvents * 3 {
+		at := base.Add(time.Duration(i) * time.Second)
+		reg.noteWorkerConnectLocked("h", uint64(i+1), window, at)
+		reg.noteWorkerDisconnectLocked("h", uint64(i+1), window, at.Add(time.Millisecond))
+	}
+	h := reg.connHistory["h"]
+	if len(h.connects) > maxWorkerConnHistoryEvents || len(h.closed) > maxWorkerConnHistoryEvents {
+		t.Fatalf("history not bounded: %d connects, %d closed", len(h.connects), len(h.closed))
+	}
+	reg.noteWorkerConnectLocked("off", 1, 0, base)
+	reg.mu.Unlock()
+
+	if reg.connectionHistory("off", 0, base) != nil {
+		t.Fatalf("expected no history when disabled")
+	}
+}
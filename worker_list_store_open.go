@@ -72,6 +72,34 @@ func addSavedWorkersBestDifficultyColumn(db *sql.DB) error {
 	return nil
 }
 
+func addSavedWorkersStaticDifficultyColumn(db *sql.DB) error {
+	if db == nil {
+		return nil
+	}
+	_, err := db.Exec("ALTER TABLE saved_workers ADD COLUMN static_difficulty REAL NOT NULL DEFAULT 0")
+	if err != nil && !strings.Contains(err.Error(), "duplicate column name") {
+		return err
+	}
+	return nil
+}
+
+// addSavedWorkersOwnerColumns adds the per-entry token a rig presents to
+// prove which user owns it, and the flag recording that proof.
+func addSavedWorkersOwnerColumns(db *sql.DB) error {
+	if db == nil {
+		return nil
+	}
+	for _, stmt := range []string{
+		"ALTER TABLE saved_workers ADD COLUMN owner_token TEXT NOT NULL DEFAULT ''",
+		"ALTER TABLE saved_workers ADD COLUMN owner_verified INTEGER NOT NULL DEFAULT 0",
+	} {
+		if _, err := db.Exec(stmt); err != nil && !strings.Contains(err.Error(), "duplicate column name") {
+			return err
+		}
+	}
+	return nil
+}
+
 func addSavedWorkersDisplayColumn(db *sql.DB) error {
 	if db == nil {
 		return nil
@@ -70,7 +70,7 @@ func (s *workerListStore) List(userID string) ([]SavedWorkerEntry, error) {
 		return nil, nil
 	}
 	rows, err := s.db.Query(`
-		SELECT COALESCE(worker_display, ''), COALESCE(worker_hash, ''), notify_enabled, best_difficulty
+		SELECT COALESCE(worker_display, ''), COALESCE(worker_hash, ''), notify_enabled, best_difficulty, static_difficulty, owner_token, owner_verified
 		FROM saved_workers
 		WHERE user_id = ?
 		ORDER BY worker_display COLLATE NOCASE
@@ -83,11 +83,13 @@ func (s *workerListStore) List(userID string) ([]SavedWorkerEntry, error) {
 	for rows.Next() {
 		var entry SavedWorkerEntry
 		var notifyEnabledInt int
+		var ownerVerifiedInt int
 		var best sql.NullFloat64
-		if err := rows.Scan(&entry.Name, &entry.Hash, &notifyEnabledInt, &best); err != nil {
+		if err := rows.Scan(&entry.Name, &entry.Hash, &notifyEnabledInt, &best, &entry.StaticDifficulty, &entry.OwnerToken, &ownerVerifiedInt); err != nil {
 			return nil, err
 		}
 		entry.NotifyEnabled = notifyEnabledInt != 0
+		entry.OwnerVerified = ownerVerifiedInt != 0
 		entry.BestDifficulty = best.Float64
 		entry.Name = strings.TrimSpace(entry.Name)
 		entry.Hash = strings.ToLower(strings.TrimSpace(entry.Hash))
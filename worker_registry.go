@@ -1,8 +1,10 @@
 package main
 
 import (
+	"net"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 // workerConnectionRegistry tracks current miner connections by connection ID.
@@ -13,6 +15,15 @@ type workerConnectionRegistry struct {
 	conns               map[uint64]*MinerConn // connection seq -> MinerConn
 	nameToConnIDs       map[string][]uint64   // SHA256 -> list of connection IDs
 	walletHashToConnIDs map[string][]uint64   // wallet hash -> list of connection IDs
+	// lastTransport remembers the transport ("tcp"/"tls") each worker hash
+	// last authorized over, across reconnects (see miner_transport.go).
+	lastTransport map[string]string
+	// connHistory holds per-worker-hash connect/disconnect records when
+	// worker_connection_history_seconds is set (see worker_conn_history.go).
+	connHistory map[string]*workerConnHistory
+	// walletFallbackWarned holds worker names already warned about by
+	// invalid_wallet_fallback_to_pool, so reconnects do not repeat it.
+	walletFallbackWarned map[string]struct{}
 }
 
 func newWorkerConnectionRegistry() *workerConnectionRegistry {
@@ -20,6 +31,7 @@ func newWorkerConnectionRegistry() *workerConnectionRegistry {
 		conns:               make(map[uint64]*MinerConn),
 		nameToConnIDs:       make(map[string][]uint64),
 		walletHashToConnIDs: make(map[string][]uint64),
+		lastTransport:       make(map[string]string),
 	}
 }
 
@@ -40,6 +52,7 @@ func (r *workerConnectionRegistry) register(hash, walletHash string, mc *MinerCo
 
 	// Store connection by its sequence number
 	r.conns[connSeq] = mc
+	r.noteWorkerConnectLocked(hash, connSeq, mc.cfg.WorkerConnHistoryWindow, time.Now())
 
 	// Check if there's a previous connection with this worker hash
 	var prev *MinerConn
@@ -91,6 +104,7 @@ func (r *workerConnectionRegistry) unregister(hash string, walletHash string, mc
 	if current, exists := r.conns[connSeq]; exists && current == mc {
 		delete(r.conns, connSeq)
 	}
+	r.noteWorkerDisconnectLocked(hash, connSeq, mc.cfg.WorkerConnHistoryWindow, time.Now())
 
 	// Remove from the name-to-connection-ID map
 	if connIDs, exists := r.nameToConnIDs[hash]; exists {
@@ -184,3 +198,79 @@ func (r *workerConnectionRegistry) connectionBySeq(seq uint64) *MinerConn {
 	defer r.mu.Unlock()
 	return r.conns[seq]
 }
+
+// staleDuplicates returns other connections registered under the same worker
+// hash that share mc's remote host and subscribe session ID. The session ID is
+// only equal across connections when a reconnecting miner resumes the old
+// session, so distinct machines behind one NAT sharing a worker name never
+// match.
+func (r *workerConnectionRegistry) staleDuplicates(hash string, mc *MinerConn) []*MinerConn {
+	if r == nil || mc == nil {
+		return nil
+	}
+	host := minerRemoteHost(mc)
+	session := mc.currentSessionID()
+	if host == "" || session == "" {
+		return nil
+	}
+	var stale []*MinerConn
+	for _, other := range r.getConnectionsByHash(hash) {
+		if other == mc {
+			continue
+		}
+		if minerRemoteHost(other) != host || other.currentSessionID() != session {
+			continue
+		}
+		stale = append(stale, other)
+	}
+	return stale
+}
+
+func minerRemoteHost(mc *MinerConn) string {
+	host, _, err := net.SplitHostPort(mc.id)
+	if err != nil {
+		return mc.id
+	}
+	return host
+}
+
+// noteTransport records transport as the latest one used by the worker hash
+// and returns the one it used before (empty when unknown).
+func (r *workerConnectionRegistry) noteTransport(hash, transport string) string {
+	if r == nil || hash == "" {
+		return ""
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.lastTransport == nil {
+		r.lastTransport = make(map[string]string)
+	}
+	prev, ok := r.lastTransport[hash]
+	if !ok && len(r.lastTransport) >= maxTrackedWorkerTransports {
+		// Bounded memory: forget everything rather than track eviction order.
+		clear(r.lastTransport)
+	}
+	r.lastTransport[hash] = transport
+	return prev
+}
+
+// noteWalletFallback reports whether worker has not yet been warned about
+// falling back to the pool payout address, and remembers it if so.
+func (r *workerConnectionRegistry) noteWalletFallback(worker string) bool {
+	if r == nil {
+		return true
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.walletFallbackWarned[worker]; ok {
+		return false
+	}
+	if r.walletFallbackWarned == nil {
+		r.walletFallbackWarned = make(map[string]struct{})
+	} else if len(r.walletFallbackWarned) >= maxTrackedWalletFallbackWarnings {
+		// Bounded memory: forget everything rather than track eviction order.
+		clear(r.walletFallbackWarned)
+	}
+	r.walletFallbackWarned[worker] = struct{}{}
+	return true
+}
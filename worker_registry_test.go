@@ -70,3 +70,44 @@ func TestWorkerConnectionRegistryConnectionBySeqZero(t *testing.T) {
 		t.Fatalf("expected nil for seq 0, got %v", got)
 	}
 }
+
+func TestReplaceStaleWorkerConnections(t *testing.T) {
+	reg := newWorkerConnectionRegistry()
+	worker, _, _ := generateTestWorker(t)
+	cfg := Config{ReplaceStaleWorkerConnections: true}
+
+	newConn := func(seq uint64, remote, session string) *MinerConn {
+		mc := &MinerConn{id: remote, cfg: cfg, workerRegistry: reg, sessionID: session, conn: &writeRecorderConn{}}
+		atomic.StoreUint64(&mc.connectionSeq, seq)
+		return mc
+	}
+	old := newConn(101, "203.0.113.5:40001", "resume-1")
+	farm := newConn(102, "203.0.113.5:40002", "resume-2")
+	otherIP := newConn(103, "198.51.100.7:40003", "resume-1")
+	for _, mc := range []*MinerConn{old, farm, otherIP} {
+		mc.registerWorker(worker)
+	}
+	hash := old.registeredWorkerHash
+	if got := len(reg.getConnectionsByHash(hash)); got != 3 {
+		t.Fatalf("expected 3 registered connections, got %d", got)
+	}
+
+	reconnect := newConn(104, "203.0.113.5:40004", "resume-1")
+	reconnect.registerWorker(worker)
+
+	conns := reg.getConnectionsByHash(hash)
+	if len(conns) != 3 {
+		t.Fatalf("expected stale connection to be replaced, have %d connections", len(conns))
+	}
+	for _, mc := range conns {
+		if mc == old {
+			t.Fatalf("stale connection still registered")
+		}
+	}
+	if !old.conn.(*writeRecorderConn).closed {
+		t.Fatalf("expected stale connection to be closed")
+	}
+	if farm.registeredWorkerHash == "" || otherIP.registeredWorkerHash == "" {
+		t.Fatalf("unrelated connections must stay registered")
+	}
+}